@@ -0,0 +1,34 @@
+// Helper privilegiado de Luz Nocturna para escribir el brillo.
+//
+// La aplicación lo lanza con pkexec cuando logind no puede cambiar el
+// brillo; polkit/com.luznocturna.backlight.policy autoriza a la sesión
+// activa sin contraseña. Es un binario aparte y mínimo para que lo único
+// que se ejecuta como root sea la escritura en sysfs.
+//
+//	pkexec /usr/local/libexec/luz-nocturna-backlight intel_backlight 4800
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"luznocturna/luz-nocturna/internal/backlight"
+)
+
+func main() {
+	if len(os.Args) != 3 {
+		fmt.Fprintln(os.Stderr, "Uso: luz-nocturna-backlight <dispositivo> <valor>")
+		os.Exit(2)
+	}
+
+	value, err := strconv.Atoi(os.Args[2])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ valor de brillo inválido: %s\n", os.Args[2])
+		os.Exit(1)
+	}
+	if err := backlight.Write(os.Args[1], value); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,53 @@
+// Package backlight lee y escribe la retroiluminación en sysfs.
+//
+// Solo usa la biblioteca estándar: lo importa el helper privilegiado
+// (cmd/luz-nocturna-backlight), que polkit ejecuta como root y no debe
+// arrastrar la interfaz gráfica ni el resto de la aplicación.
+package backlight
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Dir es el directorio de dispositivos de retroiluminación del kernel
+const Dir = "/sys/class/backlight"
+
+// ReadValue lee un valor entero de /sys/class/backlight/<name>/<file>
+func ReadValue(name, file string) (int, error) {
+	data, err := os.ReadFile(filepath.Join(Dir, name, file))
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(string(data)))
+}
+
+/**
+ * Write - Escribe el brillo directamente en sysfs
+ *
+ * Es el trabajo del helper privilegiado: valida el nombre del dispositivo
+ * (sin rutas) y el rango antes de escribir.
+ *
+ * @param {string} name - Nombre del dispositivo en /sys/class/backlight
+ * @param {int} value - Nuevo valor (0..max_brightness)
+ * @returns {error} Error de validación o escritura
+ */
+func Write(name string, value int) error {
+	if name == "" || strings.ContainsAny(name, "/\\") || name == "." || name == ".." {
+		return fmt.Errorf("nombre de dispositivo inválido: %q", name)
+	}
+
+	max, err := ReadValue(name, "max_brightness")
+	if err != nil {
+		return fmt.Errorf("dispositivo de retroiluminación desconocido: %s", name)
+	}
+	if value < 0 || value > max {
+		return fmt.Errorf("brillo fuera de rango para %s: %d (máximo %d)", name, value, max)
+	}
+
+	path := filepath.Join(Dir, name, "brightness")
+	return os.WriteFile(path, []byte(strconv.Itoa(value)), 0644)
+}
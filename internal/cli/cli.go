@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+)
+
+/**
+ * IsSubcommand - Indica si los argumentos invocan un subcomando de CLI
+ *
+ * Los subcomandos no abren la interfaz gráfica ni tocan el display,
+ * por lo que pueden usarse por SSH o en scripts de aprovisionamiento.
+ *
+ * @param {[]string} args - Argumentos sin el nombre del programa
+ * @returns {bool} true si el primer argumento es un subcomando conocido
+ */
+func IsSubcommand(args []string) bool {
+	if len(args) == 0 {
+		return false
+	}
+	_, ok := subcommands[args[0]]
+	return ok
+}
+
+/**
+ * Run - Ejecuta el subcomando indicado en args[0]
+ *
+ * @param {[]string} args - Argumentos sin el nombre del programa
+ * @returns {int} Código de salida del proceso
+ * @example
+ *   os.Exit(cli.Run([]string{"schedule", "show"}))
+ */
+func Run(args []string) int {
+	if !IsSubcommand(args) {
+		printUsage()
+		return 2
+	}
+	return subcommands[args[0]](args[1:])
+}
+
+// subcommands asocia cada subcomando con su manejador
+var subcommands = map[string]func(args []string) int{
+	"schedule":       runSchedule,
+	"config":         runConfig,
+	"apply":          runApply,
+	"toggle":         runToggle,
+	"reset":          runReset,
+	"color-accurate": runColorAccurate,
+	"doctor":         runDoctor,
+	"extension":      runExtension,
+}
+
+// printUsage muestra la ayuda general de los subcomandos
+func printUsage() {
+	fmt.Fprintln(os.Stderr, "Uso: luz-nocturna <subcomando> [opciones]")
+	fmt.Fprintln(os.Stderr, "")
+	fmt.Fprintln(os.Stderr, "Subcomandos:")
+	fmt.Fprintln(os.Stderr, "  schedule show   Mostrar la programación automática")
+	fmt.Fprintln(os.Stderr, "  schedule set    Modificar la programación automática")
+	fmt.Fprintln(os.Stderr, "  schedule templates  Listar las plantillas de programación")
+	fmt.Fprintln(os.Stderr, "  schedule skip   Omitir la noche de hoy (--cancel la reanuda)")
+	fmt.Fprintln(os.Stderr, "  config get      Mostrar un valor de la configuración (p. ej. schedule.night_temp)")
+	fmt.Fprintln(os.Stderr, "  config set      Cambiar un valor con validación (p. ej. schedule.transition_time 45)")
+	fmt.Fprintln(os.Stderr, "  config keys     Listar las claves disponibles")
+	fmt.Fprintln(os.Stderr, "  apply           Aplicar la última temperatura (o --temp K)")
+	fmt.Fprintln(os.Stderr, "  toggle          Activar o desactivar el filtro")
+	fmt.Fprintln(os.Stderr, "  reset           Restaurar la gamma normal")
+	fmt.Fprintln(os.Stderr, "  color-accurate  Gamma normal durante un rato y después reaplicar (otra vez lo termina)")
+	fmt.Fprintln(os.Stderr, "  doctor          Revisar el entorno (--bundle crea el paquete de diagnóstico)")
+	fmt.Fprintln(os.Stderr, "  extension install  Instalar el widget de GNOME Shell o Plasma (gnome|plasma)")
+	fmt.Fprintln(os.Stderr, gammaUsage)
+}
+
+// fail imprime un error en stderr y devuelve el código de salida 1
+func fail(format string, args ...interface{}) int {
+	fmt.Fprintf(os.Stderr, "❌ "+format+"\n", args...)
+	return 1
+}
@@ -0,0 +1,320 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"luznocturna/luz-nocturna/internal/ipc"
+	"luznocturna/luz-nocturna/internal/models"
+)
+
+/**
+ * runConfig - Subcomando "config" (get | set | keys)
+ *
+ * Lee y modifica config.json por clave con puntos (los nombres JSON),
+ * pensado para configuraciones gestionadas con dotfiles.
+ *
+ * @param {[]string} args - Argumentos después de "config"
+ * @returns {int} Código de salida
+ * @example
+ *   luz-nocturna config get schedule.night_temp
+ *   luz-nocturna config set schedule.transition_time 45
+ */
+func runConfig(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "Uso: luz-nocturna config <get|set|keys> [clave] [valor]")
+		return 2
+	}
+
+	switch args[0] {
+	case "get":
+		if len(args) > 2 {
+			fmt.Fprintln(os.Stderr, "Uso: luz-nocturna config get [clave]")
+			return 2
+		}
+		key := ""
+		if len(args) == 2 {
+			key = args[1]
+		}
+		return runConfigGet(key)
+	case "set":
+		if len(args) != 3 {
+			fmt.Fprintln(os.Stderr, "Uso: luz-nocturna config set <clave> <valor>")
+			return 2
+		}
+		return runConfigSet(args[1], args[2])
+	case "keys":
+		return runConfigKeys()
+	default:
+		return fail("acción desconocida para config: %s", args[0])
+	}
+}
+
+/**
+ * loadConfigTree - Carga la configuración como árbol JSON genérico
+ *
+ * Las claves que config.json omite cuando están vacías (omitempty, p. ej.
+ * schedule.night_dim) se añaden con su valor cero a partir de los campos
+ * de models.AppConfig, así que también se pueden leer y cambiar.
+ *
+ * @returns {map[string]interface{}, error} Configuración como JSON genérico
+ * @private
+ */
+func loadConfigTree() (map[string]interface{}, error) {
+	config := models.NewAppConfig()
+	if err := config.Load(); err != nil {
+		return nil, fmt.Errorf("no se pudo leer la configuración: %w", err)
+	}
+
+	data, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	var tree map[string]interface{}
+	if err := json.Unmarshal(data, &tree); err != nil {
+		return nil, err
+	}
+	addMissingKeys(tree, reflect.TypeOf(*config))
+	return tree, nil
+}
+
+// jsonMarshalerType sirve para reconocer los tipos que se serializan como un solo valor (time.Time...)
+var jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+
+/**
+ * addMissingKeys - Completa el árbol con los campos que la serialización omitió
+ *
+ * Sigue las mismas reglas de nombres que encoding/json: la etiqueta json
+ * o, sin ella, el nombre del campo; los campos sin exportar o con "-" no
+ * cuentan y los structs embebidos sin etiqueta aportan sus campos.
+ *
+ * @param {map[string]interface{}} node - Objeto JSON que corresponde a t
+ * @param {reflect.Type} t - Tipo del struct
+ * @private
+ */
+func addMissingKeys(node map[string]interface{}, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if !field.IsExported() || tag == "-" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		isObject := field.Type.Kind() == reflect.Struct &&
+			!field.Type.Implements(jsonMarshalerType) && !reflect.PointerTo(field.Type).Implements(jsonMarshalerType)
+
+		if field.Anonymous && name == "" && isObject {
+			addMissingKeys(node, field.Type)
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		if isObject {
+			child, ok := node[name].(map[string]interface{})
+			if !ok {
+				if _, present := node[name]; present {
+					continue
+				}
+				child = map[string]interface{}{}
+				node[name] = child
+			}
+			addMissingKeys(child, field.Type)
+			continue
+		}
+		if _, present := node[name]; !present {
+			node[name] = zeroJSONValue(field.Type)
+		}
+	}
+}
+
+// zeroJSONValue devuelve el valor cero de un tipo tal como lo dejaría json.Unmarshal en un interface{}
+func zeroJSONValue(t reflect.Type) interface{} {
+	data, err := json.Marshal(reflect.Zero(t).Interface())
+	if err != nil {
+		return nil
+	}
+	var value interface{}
+	json.Unmarshal(data, &value)
+	return value
+}
+
+/**
+ * lookupKey - Busca una clave con puntos ("schedule.night_temp") en el árbol
+ *
+ * @param {map[string]interface{}} tree - Configuración como JSON genérico
+ * @param {string} key - Clave con puntos
+ * @returns {map[string]interface{}, string, error} Objeto que contiene la clave y su último segmento
+ * @private
+ */
+func lookupKey(tree map[string]interface{}, key string) (map[string]interface{}, string, error) {
+	parts := strings.Split(key, ".")
+	node := tree
+	for i, part := range parts[:len(parts)-1] {
+		child, ok := node[part].(map[string]interface{})
+		if !ok {
+			return nil, "", fmt.Errorf("clave desconocida: %s", strings.Join(parts[:i+1], "."))
+		}
+		node = child
+	}
+
+	last := parts[len(parts)-1]
+	if _, ok := node[last]; !ok {
+		return nil, "", fmt.Errorf("clave desconocida: %s (ver \"luz-nocturna config keys\")", key)
+	}
+	return node, last, nil
+}
+
+/**
+ * runConfigGet - Imprime el valor de una clave (o toda la configuración)
+ *
+ * Los textos y números se imprimen tal cual para poder usarlos en
+ * scripts; los objetos y listas, como JSON indentado.
+ *
+ * @param {string} key - Clave con puntos ("" para toda la configuración)
+ * @returns {int} Código de salida
+ */
+func runConfigGet(key string) int {
+	tree, err := loadConfigTree()
+	if err != nil {
+		return fail("%v", err)
+	}
+
+	var value interface{} = tree
+	if key != "" {
+		node, last, err := lookupKey(tree, key)
+		if err != nil {
+			return fail("%v", err)
+		}
+		value = node[last]
+	}
+
+	switch v := value.(type) {
+	case string:
+		fmt.Println(v)
+	case float64:
+		fmt.Println(strconv.FormatFloat(v, 'f', -1, 64))
+	case bool:
+		fmt.Println(strconv.FormatBool(v))
+	default:
+		data, _ := json.MarshalIndent(v, "", "  ")
+		fmt.Println(string(data))
+	}
+	return 0
+}
+
+/**
+ * runConfigSet - Cambia una clave con validación y la guarda
+ *
+ * El valor se interpreta según el tipo actual de la clave (número,
+ * booleano o texto; las listas y objetos se escriben como JSON). Se
+ * valida la configuración completa antes de guardarla con el mismo
+ * guardado atómico que usa la interfaz gráfica. Con la aplicación
+ * abierta no se cambia nada: su próximo guardado sobrescribiría el
+ * valor sin avisar.
+ *
+ * @param {string} key - Clave con puntos
+ * @param {string} raw - Valor tal como se escribió en la línea de comandos
+ * @returns {int} Código de salida
+ */
+func runConfigSet(key, raw string) int {
+	if found, _ := ipc.CallRunningInstance("GetState"); found {
+		return fail("la aplicación está abierta: cámbialo desde ella o ciérrala antes de usar \"config set\"")
+	}
+
+	tree, err := loadConfigTree()
+	if err != nil {
+		return fail("%v", err)
+	}
+	node, last, err := lookupKey(tree, key)
+	if err != nil {
+		return fail("%v", err)
+	}
+
+	value, err := parseConfigValue(node[last], raw)
+	if err != nil {
+		return fail("%s: %v", key, err)
+	}
+	node[last] = value
+
+	data, err := json.Marshal(tree)
+	if err != nil {
+		return fail("%v", err)
+	}
+	updated := models.NewAppConfig()
+	if err := json.Unmarshal(data, updated); err != nil {
+		return fail("%s: tipo de valor incorrecto: %v", key, err)
+	}
+	if err := updated.Validate(); err != nil {
+		return fail("configuración inválida: %v", err)
+	}
+	if err := updated.Save(); err != nil {
+		return fail("no se pudo guardar la configuración: %v", err)
+	}
+
+	fmt.Printf("✅ %s = %s\n", key, raw)
+	return 0
+}
+
+// parseConfigValue convierte el texto de la línea de comandos al tipo del valor actual
+func parseConfigValue(current interface{}, raw string) (interface{}, error) {
+	switch current.(type) {
+	case string:
+		return raw, nil
+	case float64:
+		number, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("se esperaba un número: %q", raw)
+		}
+		return number, nil
+	case bool:
+		flag, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("se esperaba true o false: %q", raw)
+		}
+		return flag, nil
+	default:
+		var value interface{}
+		if err := json.Unmarshal([]byte(raw), &value); err != nil {
+			return nil, fmt.Errorf("se esperaba JSON: %v", err)
+		}
+		return value, nil
+	}
+}
+
+/**
+ * runConfigKeys - Lista las claves escalares disponibles
+ *
+ * @returns {int} Código de salida
+ */
+func runConfigKeys() int {
+	tree, err := loadConfigTree()
+	if err != nil {
+		return fail("%v", err)
+	}
+
+	var keys []string
+	var walk func(prefix string, node map[string]interface{})
+	walk = func(prefix string, node map[string]interface{}) {
+		for name, value := range node {
+			if child, ok := value.(map[string]interface{}); ok {
+				walk(prefix+name+".", child)
+				continue
+			}
+			keys = append(keys, prefix+name)
+		}
+	}
+	walk("", tree)
+
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Println(key)
+	}
+	return 0
+}
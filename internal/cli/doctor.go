@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"luznocturna/luz-nocturna/internal/diagnostics"
+	"luznocturna/luz-nocturna/internal/system"
+)
+
+/**
+ * runDoctor - Subcomando "doctor": revisa el entorno y los backends
+ *
+ * Sin opciones muestra el protocolo, el compositor, las herramientas y
+ * el estado de RandR. Con --bundle crea además el paquete de diagnóstico
+ * (tar.gz) para adjuntarlo a un informe de error. No modifica la gamma.
+ *
+ * @param {[]string} args - Opciones de línea de comandos
+ * @returns {int} Código de salida
+ * @example
+ *   luz-nocturna doctor
+ *   luz-nocturna doctor --bundle --output ~/Descargas
+ */
+func runDoctor(args []string) int {
+	fs := flag.NewFlagSet("doctor", flag.ContinueOnError)
+	bundle := fs.Bool("bundle", false, "Crear el paquete de diagnóstico (tar.gz) para un informe de error")
+	output := fs.String("output", ".", "Archivo o directorio del paquete de diagnóstico")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	report := system.ProbeEnvironment()
+	fmt.Printf("🖥️  Protocolo: %s\n", report.Protocol)
+	fmt.Printf("🧭 Compositor: %s\n", report.Compositor)
+	if len(report.Strategy) > 0 {
+		fmt.Printf("   Métodos: %s\n", strings.Join(report.Strategy, " → "))
+	}
+	if report.Flatpak {
+		fmt.Println("📦 Ejecutando dentro de Flatpak")
+	}
+	fmt.Printf("🔌 RandR: %s\n", report.RandR)
+
+	tools := make([]string, 0, len(report.Tools))
+	for tool := range report.Tools {
+		tools = append(tools, tool)
+	}
+	sort.Strings(tools)
+	fmt.Println("🛠️  Herramientas:")
+	for _, tool := range tools {
+		mark := "❌"
+		if report.Tools[tool] {
+			mark = "✅"
+		}
+		fmt.Printf("   %s %s\n", mark, tool)
+	}
+	if len(report.Plugins) > 0 {
+		fmt.Printf("🧩 Plugins: %s\n", strings.Join(report.Plugins, ", "))
+	}
+
+	if !*bundle {
+		return 0
+	}
+	path, err := diagnostics.ExportBundle(*output, nil)
+	if err != nil {
+		return fail("%v", err)
+	}
+	fmt.Printf("📋 Paquete de diagnóstico creado: %s\n", path)
+	fmt.Fprintln(os.Stderr, "Revisa su contenido antes de adjuntarlo; los registros de la sesión gráfica se exportan desde \"Acerca de\"")
+	return 0
+}
@@ -0,0 +1,45 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"luznocturna/luz-nocturna/internal/companion"
+)
+
+/**
+ * runExtension - Subcomando "extension install [gnome|plasma]"
+ *
+ * Instala la extensión de GNOME Shell o el applet de Plasma en el
+ * directorio del usuario. Sin escritorio indicado se elige el de la
+ * sesión actual.
+ *
+ * @param {[]string} args - Argumentos después de "extension"
+ * @returns {int} Código de salida
+ * @example
+ *   luz-nocturna extension install
+ *   luz-nocturna extension install plasma
+ */
+func runExtension(args []string) int {
+	if len(args) == 0 || args[0] != "install" || len(args) > 2 {
+		fmt.Fprintln(os.Stderr, "Uso: luz-nocturna extension install [gnome|plasma]")
+		return 2
+	}
+
+	widget, found := companion.Detect()
+	if len(args) == 2 {
+		if widget, found = companion.Find(args[1]); !found {
+			return fail("escritorio desconocido: %s (opciones: gnome, plasma)", args[1])
+		}
+	} else if !found {
+		return fail("el escritorio actual no es GNOME ni Plasma; indica cuál: luz-nocturna extension install gnome|plasma")
+	}
+
+	dir, err := widget.Install()
+	if err != nil {
+		return fail("%v", err)
+	}
+	fmt.Printf("🧩 %s → %s\n", widget.Name, dir)
+	fmt.Println(widget.Hint)
+	return 0
+}
@@ -0,0 +1,395 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"luznocturna/luz-nocturna/internal/ipc"
+	"luznocturna/luz-nocturna/internal/logging"
+	"luznocturna/luz-nocturna/internal/models"
+	"luznocturna/luz-nocturna/internal/paths"
+	"luznocturna/luz-nocturna/internal/system"
+)
+
+// Códigos de salida de apply, toggle y reset, pensados para scripts y atajos de teclado
+const (
+	exitApplied   = 0  // Cambio aplicado
+	exitFailed    = 1  // Error genérico
+	exitNoBackend = 2  // Ningún backend de gamma funcionó
+	exitPartial   = 3  // Aplicado solo en algunos displays
+	exitUsage     = 64 // Opciones inválidas (EX_USAGE)
+)
+
+/**
+ * gammaOutput - Salida de los subcomandos de gamma
+ *
+ * @struct {gammaOutput}
+ * @property {bool} quiet - No escribir nada en stdout (los errores siguen en stderr)
+ * @property {bool} plain - Texto sin emojis, apto para notificaciones y logs
+ * @property {bool} verbose - Mostrar los mensajes de diagnóstico del backend
+ * @property {bool} notify - Mostrar además el resultado como notificación de escritorio
+ * @property {string} remote - Destino SSH cuya instancia abierta recibe la orden ("" = esta máquina)
+ */
+type gammaOutput struct {
+	quiet   bool
+	plain   bool
+	verbose bool
+	notify  bool
+	remote  string
+}
+
+// printf escribe un resultado en stdout con el emoji indicado (salvo --no-emoji)
+func (o gammaOutput) printf(emoji, format string, args ...interface{}) {
+	if o.notify {
+		o.sendNotification(fmt.Sprintf(format, args...), ipc.UrgencyLow)
+	}
+	if o.quiet {
+		return
+	}
+	if !o.plain {
+		format = emoji + " " + format
+	}
+	fmt.Printf(format+"\n", args...)
+}
+
+// fail escribe el error en stderr y devuelve el código de salida que le corresponde
+func (o gammaOutput) fail(err error) int {
+	code := exitFailed
+	switch {
+	case errors.Is(err, system.ErrNoBackend):
+		code = exitNoBackend
+	case errors.Is(err, system.ErrPartialApply):
+		code = exitPartial
+	}
+
+	if o.notify {
+		o.sendNotification(err.Error(), ipc.UrgencyCritical)
+	}
+
+	prefix := "❌ "
+	if o.plain {
+		prefix = "error: "
+	}
+	fmt.Fprintln(os.Stderr, prefix+err.Error())
+	return code
+}
+
+// sendNotification muestra un resultado como notificación (con --verbose avisa si no hay servicio)
+func (o gammaOutput) sendNotification(message string, urgency ipc.Urgency) {
+	if err := ipc.Notify(message, "", urgency); err != nil && o.verbose {
+		fmt.Fprintf(os.Stderr, "⚠️  %v\n", err)
+	}
+}
+
+// silenceBackend oculta los mensajes de diagnóstico del backend (salvo con --verbose)
+// y devuelve la función que restaura stdout
+func (o gammaOutput) silenceBackend() func() {
+	if o.verbose {
+		return func() {}
+	}
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		return func() {}
+	}
+	saved := os.Stdout
+	os.Stdout = devNull
+	return func() {
+		os.Stdout = saved
+		devNull.Close()
+	}
+}
+
+// parseGammaFlags registra las opciones comunes y analiza args
+func parseGammaFlags(name string, args []string, extra func(fs *flag.FlagSet)) (gammaOutput, bool) {
+	var out gammaOutput
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.BoolVar(&out.quiet, "quiet", false, "No escribir nada en stdout")
+	fs.BoolVar(&out.plain, "no-emoji", false, "Salida de texto plano sin emojis")
+	fs.BoolVar(&out.verbose, "verbose", false, "Mostrar los mensajes del backend de gamma")
+	fs.BoolVar(&out.notify, "notify", false, "Mostrar el resultado como notificación de escritorio")
+	fs.StringVar(&out.remote, "remote", "", "Actuar sobre Luz Nocturna abierta en otra máquina por SSH (usuario@host)")
+	logFormat := fs.String("log-format", logging.FormatText, "Formato de los mensajes del backend: text o json")
+	if extra != nil {
+		extra(fs)
+	}
+	if fs.Parse(args) != nil {
+		return out, false
+	}
+	if err := logging.SetFormat(*logFormat); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return out, false
+	}
+	return out, true
+}
+
+/**
+ * runApply - Subcomando "apply": aplica la temperatura guardada o --temp
+ *
+ * Si la aplicación está abierta actúa a través de D-Bus; si no, aplica
+ * la gamma directamente.
+ *
+ * @param {[]string} args - Opciones de línea de comandos
+ * @returns {int} 0 aplicado, 2 sin backend, 3 parcial, 1 otro error
+ * @example
+ *   luz-nocturna apply --temp 3400 --quiet
+ */
+func runApply(args []string) int {
+	var temp float64
+	out, ok := parseGammaFlags("apply", args, func(fs *flag.FlagSet) {
+		fs.Float64Var(&temp, "temp", 0, "Temperatura en Kelvin (por defecto la última usada)")
+	})
+	if !ok {
+		return exitUsage
+	}
+
+	if out.remote != "" {
+		return runRemote(out, "🌙", "Filtro aplicado en %s", func(remote *ipc.RemoteInstance) error {
+			if temp != 0 {
+				if err := remote.Call("SetTemperature", temp); err != nil {
+					return err
+				}
+			}
+			return remote.Call("Apply")
+		})
+	}
+
+	if temp != 0 {
+		if found, err := ipc.CallRunningInstance("SetTemperature", temp); found && err != nil {
+			return out.fail(err)
+		}
+	}
+	if found, err := ipc.CallRunningInstance("Apply"); found {
+		if err != nil {
+			return out.fail(err)
+		}
+		out.printf("🌙", "Filtro aplicado")
+		return exitApplied
+	}
+
+	if temp == 0 {
+		temp = lastTemperature()
+	}
+	return applyStandalone(out, temp)
+}
+
+/**
+ * runToggle - Subcomando "toggle": activa o desactiva el filtro
+ *
+ * Pensado para atajos de teclado del gestor de ventanas. Sin instancia
+ * abierta, recuerda si el último "apply" de la línea de comandos sigue
+ * activo mediante un archivo en $XDG_RUNTIME_DIR.
+ *
+ * @param {[]string} args - Opciones de línea de comandos
+ * @returns {int} 0 aplicado, 2 sin backend, 3 parcial, 1 otro error
+ * @example
+ *   bindsym $mod+n exec luz-nocturna toggle --quiet
+ */
+func runToggle(args []string) int {
+	out, ok := parseGammaFlags("toggle", args, nil)
+	if !ok {
+		return exitUsage
+	}
+
+	if out.remote != "" {
+		return runRemote(out, "🔄", "Filtro alternado en %s", func(remote *ipc.RemoteInstance) error {
+			return remote.Call("Toggle")
+		})
+	}
+
+	if found, err := ipc.CallRunningInstance("Toggle"); found {
+		if err != nil {
+			return out.fail(err)
+		}
+		out.printf("🔄", "Filtro alternado")
+		return exitApplied
+	}
+
+	if _, err := os.Stat(cliStatePath()); err == nil {
+		return resetStandalone(out)
+	}
+	return applyStandalone(out, lastTemperature())
+}
+
+/**
+ * runColorAccurate - Subcomando "color-accurate": activa o termina el modo de color fiel
+ *
+ * Retira el filtro durante accurate_duration minutos (30 por defecto) y
+ * después vuelve a aplicar lo que estaba activo. El temporizador vive en
+ * la aplicación abierta, así que sin una instancia en ejecución falla.
+ *
+ * @param {[]string} args - Opciones de línea de comandos
+ * @returns {int} 0 alternado, 1 error
+ * @example
+ *   bindsym $mod+c exec luz-nocturna color-accurate --notify
+ */
+func runColorAccurate(args []string) int {
+	out, ok := parseGammaFlags("color-accurate", args, nil)
+	if !ok {
+		return exitUsage
+	}
+
+	if out.remote != "" {
+		return runRemote(out, "🎨", "Color fiel alternado en %s", func(remote *ipc.RemoteInstance) error {
+			return remote.Call("ToggleColorAccurate")
+		})
+	}
+
+	found, err := ipc.CallRunningInstance("ToggleColorAccurate")
+	if !found {
+		return out.fail(errors.New("Luz Nocturna no está en ejecución: el modo de color fiel necesita la aplicación abierta"))
+	}
+	if err != nil {
+		return out.fail(err)
+	}
+	out.printf("🎨", "Color fiel alternado")
+	return exitApplied
+}
+
+/**
+ * runReset - Subcomando "reset": restaura la gamma normal
+ *
+ * @param {[]string} args - Opciones de línea de comandos
+ * @returns {int} 0 restaurado, 1 error
+ */
+func runReset(args []string) int {
+	out, ok := parseGammaFlags("reset", args, nil)
+	if !ok {
+		return exitUsage
+	}
+
+	if out.remote != "" {
+		return runRemote(out, "☀️", "Gamma restaurada en %s", func(remote *ipc.RemoteInstance) error {
+			return remote.Call("Reset")
+		})
+	}
+
+	if found, err := ipc.CallRunningInstance("Reset"); found {
+		if err != nil {
+			return out.fail(err)
+		}
+		out.printf("☀️", "Gamma restaurada")
+		return exitApplied
+	}
+	return resetStandalone(out)
+}
+
+/**
+ * runRemote - Envía una orden a la instancia abierta en otra máquina
+ *
+ * La conexión viaja por SSH hasta el bus de sesión remoto; la otra
+ * máquina debe tener la aplicación abierta (no hay modo sin instancia).
+ *
+ * @param {gammaOutput} out - Salida, con el destino en out.remote
+ * @param {string} emoji - Emoji del mensaje de éxito
+ * @param {string} message - Mensaje de éxito con %s para el destino
+ * @param {func(*ipc.RemoteInstance) error} calls - Llamadas a realizar
+ * @returns {int} Código de salida, igual que en local
+ * @example
+ *   luz-nocturna apply --temp 3000 --remote ana@htpc
+ */
+func runRemote(out gammaOutput, emoji, message string, calls func(remote *ipc.RemoteInstance) error) int {
+	remote, err := ipc.ConnectRemoteInstance(out.remote)
+	if err != nil {
+		return out.fail(err)
+	}
+	defer remote.Close()
+
+	if err := calls(remote); err != nil {
+		return out.fail(err)
+	}
+	out.printf(emoji, message, out.remote)
+	return exitApplied
+}
+
+// applyStandalone aplica la gamma sin instancia abierta y recuerda que quedó activa
+func applyStandalone(out gammaOutput, temp float64) int {
+	restore := out.silenceBackend()
+	err := newStandaloneBackend().ApplyTemperatureWithBrightness(temp, 1.0)
+	restore()
+
+	if err != nil && !errors.Is(err, system.ErrPartialApply) {
+		return out.fail(err)
+	}
+	saveCLIState(temp)
+	if err != nil {
+		return out.fail(err)
+	}
+
+	out.printf("🌙", "Filtro aplicado: %s", temperatureFormat().Format(temp))
+	return exitApplied
+}
+
+// resetStandalone restaura la gamma sin instancia abierta
+func resetStandalone(out gammaOutput) int {
+	restore := out.silenceBackend()
+	err := newStandaloneBackend().Reset()
+	restore()
+
+	if err != nil {
+		return out.fail(err)
+	}
+	os.Remove(cliStatePath())
+
+	out.printf("☀️", "Gamma restaurada")
+	return exitApplied
+}
+
+// standaloneBackend son las operaciones de gamma que usan apply, toggle y reset sin instancia abierta
+type standaloneBackend interface {
+	ApplyTemperatureWithBrightness(temperature, brightness float64) error
+	Reset() error
+}
+
+// newStandaloneBackend crea el backend de gamma respetando LUZ_NOCTURNA_BACKEND
+func newStandaloneBackend() standaloneBackend {
+	switch backend := models.BackendOverride(); backend {
+	case models.BackendFake:
+		return system.NewFakeBackend()
+	case models.BackendDryRun:
+		return system.NewGammaManagerWithOptions(system.GammaOptions{DryRun: true})
+	case models.BackendX11, models.BackendWayland:
+		return system.NewGammaManagerWithOptions(system.GammaOptions{Protocol: backend})
+	}
+	return system.NewGammaManager()
+}
+
+// lastTemperature devuelve LUZ_NOCTURNA_TEMP o la última temperatura guardada en la configuración
+func lastTemperature() float64 {
+	if temp, ok := models.TemperatureOverride(); ok {
+		return temp
+	}
+	config := models.NewAppConfig()
+	config.Load() // Con error se usa la temperatura por defecto
+	return config.LastTemperature
+}
+
+// temperatureFormat devuelve la unidad de temperatura elegida en la configuración
+func temperatureFormat() models.TemperatureFormat {
+	config := models.NewAppConfig()
+	config.Load() // Con error se muestran Kelvin
+	return models.NewTemperatureFormat(config.GetTemperatureUnit())
+}
+
+// cliStatePath es el archivo que indica que "apply" dejó el filtro activo
+func cliStatePath() string {
+	return filepath.Join(paths.RuntimeDir(), "cli-active")
+}
+
+// saveCLIState recuerda la temperatura aplicada desde la línea de comandos
+func saveCLIState(temp float64) {
+	if err := os.MkdirAll(paths.RuntimeDir(), 0700); err != nil {
+		return
+	}
+	os.WriteFile(cliStatePath(), []byte(strconv.FormatFloat(temp, 'f', 0, 64)+"\n"), 0600)
+}
+
+// gammaUsage describe las opciones comunes de apply, toggle y reset
+const gammaUsage = "" +
+	"  Opciones de apply/toggle/reset/color-accurate: --quiet (sin salida), --no-emoji (texto plano), --verbose (mensajes del backend),\n" +
+	"    --log-format=json (mensajes del backend como JSON), --notify (resultado como notificación de escritorio),\n" +
+	"    --remote usuario@host (actuar sobre la aplicación abierta en otra máquina por SSH)\n" +
+	"  Códigos de salida: 0 aplicado, 1 error, 2 sin backend de gamma, 3 aplicado solo en algunos displays"
@@ -0,0 +1,268 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"luznocturna/luz-nocturna/internal/ipc"
+	"luznocturna/luz-nocturna/internal/models"
+)
+
+/**
+ * runSchedule - Subcomando "schedule" (show | set | templates | skip)
+ *
+ * @param {[]string} args - Argumentos después de "schedule"
+ * @returns {int} Código de salida
+ */
+func runSchedule(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "Uso: luz-nocturna schedule <show|set|templates|skip> [opciones]")
+		return 2
+	}
+
+	switch args[0] {
+	case "show":
+		return runScheduleShow()
+	case "set":
+		return runScheduleSet(args[1:])
+	case "templates":
+		return runScheduleTemplates()
+	case "skip":
+		return runScheduleSkip(args[1:])
+	default:
+		return fail("acción desconocida para schedule: %s", args[0])
+	}
+}
+
+/**
+ * runScheduleSkip - Omite (o reanuda con --cancel) la noche de hoy
+ *
+ * La omisión vive en el programador de la aplicación abierta, así que
+ * sin una instancia en ejecución no hay nada que omitir.
+ *
+ * @param {[]string} args - Opciones después de "schedule skip"
+ * @returns {int} Código de salida
+ * @example
+ *   luz-nocturna schedule skip
+ *   luz-nocturna schedule skip --cancel
+ */
+func runScheduleSkip(args []string) int {
+	fs := flag.NewFlagSet("schedule skip", flag.ContinueOnError)
+	cancel := fs.Bool("cancel", false, "Volver a aplicar la noche de hoy")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	method, message := "SkipTonight", "⏭️ Noche de hoy omitida: la programación se reanuda mañana"
+	if *cancel {
+		method, message = "CancelSkipTonight", "🌙 Noche de hoy reanudada"
+	}
+	found, err := ipc.CallRunningInstance(method)
+	if !found {
+		return fail("Luz Nocturna no está en ejecución: la programación solo funciona con la aplicación abierta")
+	}
+	if err != nil {
+		return fail("%v", err)
+	}
+	fmt.Println(message)
+	return 0
+}
+
+/**
+ * runScheduleShow - Imprime la programación guardada y el próximo cambio
+ *
+ * @returns {int} Código de salida
+ */
+func runScheduleShow() int {
+	config := models.NewAppConfig()
+	if err := config.Load(); err != nil {
+		return fail("no se pudo leer la configuración: %v", err)
+	}
+
+	schedule := config.Schedule
+	clock := models.NewClock(config.GetClockFormat())
+	format := models.NewTemperatureFormat(config.GetTemperatureUnit())
+	enabled := "no"
+	if config.ScheduleEnabled {
+		enabled = "sí"
+	}
+
+	fmt.Printf("🕐 Programación automática habilitada: %s\n", enabled)
+	fmt.Printf("   Inicio:      %s\n", clock.FormatScheduleTime(schedule.StartTime))
+	fmt.Printf("   Fin:         %s\n", clock.FormatScheduleTime(schedule.EndTime))
+	fmt.Printf("   Nocturna:    %s\n", format.Format(schedule.NightTemp))
+	fmt.Printf("   Diurna:      %s\n", format.Format(schedule.DayTemp))
+	fmt.Printf("   Transición:  %d min\n", schedule.TransitionTime)
+	if wakeUp := schedule.WakeUp; wakeUp.Enabled {
+		fmt.Printf("   Despertador: %s (%v, %s)\n", clock.FormatScheduleTime(wakeUp.Time), wakeUp.GetDuration(), wakeUpDays(wakeUp.Weekdays))
+	}
+	if sleep := schedule.SleepMode; sleep.Enabled {
+		fmt.Printf("   Fase sueño:  %s (%.0f %% de color)\n", clock.FormatScheduleTime(sleep.GetTime()), sleep.Saturation*100)
+	}
+	if schedule.Mode == models.ScheduleModeSolar {
+		fmt.Printf("   Crepúsculo:  %s\n", schedule.GetTwilight())
+		fmt.Printf("   Atardecer:   %+d min\n", schedule.SunsetOffset)
+		fmt.Printf("   Amanecer:    %+d min\n", schedule.SunriseOffset)
+	}
+
+	if config.ScheduleEnabled {
+		description, temp, duration := models.NewScheduler(config, nil).GetNextScheduleChange()
+		fmt.Printf("🔔 %s a las %s (en %dh %02dm, %s)\n", description,
+			clock.FormatTime(time.Now().Add(duration)), int(duration.Hours()), int(duration.Minutes())%60, format.Format(temp))
+	}
+	return 0
+}
+
+/**
+ * runScheduleSet - Modifica la programación con validación
+ *
+ * Solo cambia los valores pasados explícitamente; el resto se conserva.
+ * Si el resultado no es válido no se escribe nada.
+ *
+ * @param {[]string} args - Opciones de línea de comandos
+ * @returns {int} Código de salida
+ * @example
+ *   luz-nocturna schedule set --start 21:00 --end 06:30 --night 3200 --day 6500 --transition 45
+ *   luz-nocturna schedule set --template night-owl --transition 60
+ *   luz-nocturna schedule set --twilight nautical --sunset-offset -45
+ *   luz-nocturna schedule set --wake-up 06:30 --wake-duration 40
+ *   luz-nocturna schedule set --night-dim 30
+ *   luz-nocturna schedule set --sleep-mode 23:30 --sleep-saturation 20
+ */
+func runScheduleSet(args []string) int {
+	fs := flag.NewFlagSet("schedule set", flag.ContinueOnError)
+	start := fs.String("start", "", "Hora de inicio del filtro nocturno (HH:MM o \"9:30 PM\")")
+	end := fs.String("end", "", "Hora de fin del filtro nocturno (HH:MM o \"6:30 AM\")")
+	night := fs.Float64("night", 0, "Temperatura nocturna en Kelvin")
+	day := fs.Float64("day", 0, "Temperatura diurna en Kelvin")
+	transition := fs.Int("transition", 0, "Tiempo de transición en minutos")
+	twilight := fs.String("twilight", "", "Límite de la noche en modo solar: civil, nautical o astronomical")
+	sunsetOffset := fs.Int("sunset-offset", 0, "Minutos que se adelanta (negativo) o retrasa el borde de la tarde en modo solar")
+	sunriseOffset := fs.Int("sunrise-offset", 0, "Minutos que se adelanta (negativo) o retrasa el borde de la mañana en modo solar")
+	wakeUp := fs.String("wake-up", "", "Hora de inicio del despertador (HH:MM) u \"off\" para desactivarlo")
+	wakeDuration := fs.Int("wake-duration", 0, "Minutos de la rampa del despertador")
+	nightDim := fs.Float64("night-dim", 0, "Atenuación nocturna por software en % (0 = ninguna)")
+	sleepMode := fs.String("sleep-mode", "", "Hora desde la que la pantalla pasa a escala de grises (HH:MM) u \"off\" para desactivarlo")
+	sleepSaturation := fs.Float64("sleep-saturation", 0, "Color que se conserva en la fase de sueño en % (0 = escala de grises)")
+	template := fs.String("template", "", "Partir de una plantilla (ver \"schedule templates\")")
+	enable := fs.Bool("enable", false, "Habilitar la programación automática")
+	disable := fs.Bool("disable", false, "Deshabilitar la programación automática")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NFlag() == 0 {
+		fs.Usage()
+		return 2
+	}
+	if *enable && *disable {
+		return fail("--enable y --disable son incompatibles")
+	}
+
+	config := models.NewAppConfig()
+	if err := config.Load(); err != nil {
+		return fail("no se pudo leer la configuración: %v", err)
+	}
+
+	// La plantilla se aplica primero; el resto de opciones la ajustan
+	schedule := config.Schedule
+	if *template != "" {
+		preset, ok := models.FindScheduleTemplate(*template)
+		if !ok {
+			return fail("plantilla desconocida: %s (ver \"luz-nocturna schedule templates\")", *template)
+		}
+		var err error
+		if schedule, err = preset.ApplyTo(schedule); err != nil {
+			return fail("%v", err)
+		}
+	}
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "start":
+			schedule.StartTime = *start
+		case "end":
+			schedule.EndTime = *end
+		case "night":
+			schedule.NightTemp = *night
+		case "day":
+			schedule.DayTemp = *day
+		case "transition":
+			schedule.TransitionTime = *transition
+		case "twilight":
+			schedule.Twilight = *twilight
+		case "sunset-offset":
+			schedule.SunsetOffset = *sunsetOffset
+		case "sunrise-offset":
+			schedule.SunriseOffset = *sunriseOffset
+		case "wake-up":
+			schedule.WakeUp.Enabled = *wakeUp != "off"
+			if schedule.WakeUp.Enabled {
+				schedule.WakeUp.Time = *wakeUp
+			}
+		case "wake-duration":
+			schedule.WakeUp.Duration = *wakeDuration
+		case "night-dim":
+			schedule.NightDim = *nightDim / 100
+		case "sleep-mode":
+			schedule.SleepMode.Enabled = *sleepMode != "off"
+			if schedule.SleepMode.Enabled {
+				schedule.SleepMode.Time = *sleepMode
+			}
+		case "sleep-saturation":
+			schedule.SleepMode.Saturation = *sleepSaturation / 100
+		}
+	})
+
+	if err := schedule.Validate(); err != nil {
+		return fail("programación inválida: %v", err)
+	}
+	// Guardar siempre en 24 horas aunque se escribieran en 12
+	schedule.StartTime, _ = models.NormalizeScheduleTime(schedule.StartTime)
+	schedule.EndTime, _ = models.NormalizeScheduleTime(schedule.EndTime)
+	if schedule.WakeUp.Time != "" {
+		schedule.WakeUp.Time, _ = models.NormalizeScheduleTime(schedule.WakeUp.Time)
+	}
+	if schedule.SleepMode.Time != "" {
+		schedule.SleepMode.Time, _ = models.NormalizeScheduleTime(schedule.SleepMode.Time)
+	}
+
+	config.Schedule = schedule
+	if *enable {
+		config.ScheduleEnabled = true
+	}
+	if *disable {
+		config.ScheduleEnabled = false
+	}
+
+	if err := config.Save(); err != nil {
+		return fail("no se pudo guardar la configuración: %v", err)
+	}
+
+	fmt.Println("✅ Programación actualizada")
+	return runScheduleShow()
+}
+
+// wakeUpDays describe los días en que actúa el despertador
+func wakeUpDays(weekdays []int) string {
+	if len(weekdays) == 0 {
+		return "todos los días"
+	}
+	names := make([]string, len(weekdays))
+	for i, weekday := range weekdays {
+		names[i] = weekdayNames[weekday%7]
+	}
+	return strings.Join(names, ", ")
+}
+
+// weekdayNames son las abreviaturas de los días, empezando por el domingo como time.Weekday
+var weekdayNames = [7]string{"dom", "lun", "mar", "mié", "jue", "vie", "sáb"}
+
+// runScheduleTemplates lista las plantillas de programación disponibles
+func runScheduleTemplates() int {
+	for _, template := range models.ScheduleTemplates {
+		fmt.Printf("%-15s %s — %s\n", template.ID, template.Label(), template.Description)
+	}
+	return 0
+}
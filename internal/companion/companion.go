@@ -0,0 +1,134 @@
+package companion
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"luznocturna/luz-nocturna/internal/paths"
+	"luznocturna/luz-nocturna/internal/system"
+)
+
+// files contiene la extensión de GNOME Shell y el applet de Plasma tal como se instalan
+//
+//go:embed all:gnome-shell all:plasma
+var files embed.FS
+
+/**
+ * Companion - Widget del escritorio que controla la aplicación por D-Bus
+ *
+ * Ninguno toca la gamma: son un interruptor y un slider de temperatura
+ * que llaman a com.luznocturna.LuzNocturna, así que necesitan la
+ * aplicación abierta (con ventana o en modo --tray).
+ *
+ * @struct {Companion}
+ * @property {string} ID - Nombre corto para la línea de comandos ("gnome", "plasma")
+ * @property {string} Name - Nombre visible
+ * @property {string} UUID - Identificador de la extensión o del applet
+ * @property {string} source - Directorio dentro de files
+ * @property {string} target - Directorio de instalación relativo a XDG_DATA_HOME
+ * @property {string} Hint - Cómo activarlo tras instalarlo
+ */
+type Companion struct {
+	ID     string
+	Name   string
+	UUID   string
+	source string
+	target string
+	Hint   string
+}
+
+// Widgets disponibles
+var (
+	GNOMEShell = Companion{
+		ID:     "gnome",
+		Name:   "Extensión de GNOME Shell",
+		UUID:   "luz-nocturna@luznocturna.com",
+		source: "gnome-shell/luz-nocturna@luznocturna.com",
+		target: "gnome-shell/extensions/luz-nocturna@luznocturna.com",
+		Hint: "Cierra la sesión y vuelve a entrar (o Alt+F2, r en X11) y actívala con " +
+			"\"gnome-extensions enable luz-nocturna@luznocturna.com\" o desde Extensiones. " +
+			"Aparecerá en los ajustes rápidos del panel.",
+	}
+	Plasma = Companion{
+		ID:     "plasma",
+		Name:   "Applet de Plasma",
+		UUID:   "com.luznocturna.applet",
+		source: "plasma/com.luznocturna.applet",
+		target: "plasma/plasmoids/com.luznocturna.applet",
+		Hint: "Añádelo al panel con \"Añadir widgets...\" y busca \"Luz Nocturna\". " +
+			"El clic central sobre el icono activa o desactiva el filtro.",
+	}
+)
+
+// All son los widgets que se pueden instalar, en orden
+var All = []Companion{GNOMEShell, Plasma}
+
+/**
+ * Detect - Elige el widget que corresponde al escritorio actual
+ *
+ * @returns {Companion, bool} Widget del escritorio; false si no es GNOME ni Plasma
+ */
+func Detect() (Companion, bool) {
+	switch system.CompositorID() {
+	case system.CompositorMutter:
+		return GNOMEShell, true
+	case system.CompositorKWin:
+		return Plasma, true
+	}
+	return Companion{}, false
+}
+
+// Find busca un widget por su nombre corto ("gnome" o "plasma")
+func Find(id string) (Companion, bool) {
+	for _, companion := range All {
+		if strings.EqualFold(companion.ID, id) {
+			return companion, true
+		}
+	}
+	return Companion{}, false
+}
+
+// Dir devuelve el directorio donde se instala el widget
+func (c Companion) Dir() string {
+	return filepath.Join(paths.DataHome(), filepath.FromSlash(c.target))
+}
+
+/**
+ * Install - Copia el widget al directorio del usuario
+ *
+ * Reemplaza una instalación anterior, de modo que sirve también para
+ * actualizarlo tras actualizar la aplicación. No necesita permisos de
+ * administrador.
+ *
+ * @returns {string, error} Directorio instalado o error al escribir
+ */
+func (c Companion) Install() (string, error) {
+	dir := c.Dir()
+	if err := os.RemoveAll(dir); err != nil {
+		return "", fmt.Errorf("no se pudo reemplazar %s: %w", dir, err)
+	}
+
+	err := fs.WalkDir(files, c.source, func(name string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		relative := strings.TrimPrefix(strings.TrimPrefix(name, c.source), "/")
+		destination := filepath.Join(dir, filepath.FromSlash(relative))
+		if entry.IsDir() {
+			return os.MkdirAll(destination, 0o755)
+		}
+		data, err := files.ReadFile(name)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(destination, data, 0o644)
+	})
+	if err != nil {
+		return "", fmt.Errorf("no se pudo instalar %s: %w", c.Name, err)
+	}
+	return dir, nil
+}
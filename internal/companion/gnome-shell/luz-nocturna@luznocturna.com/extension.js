@@ -0,0 +1,172 @@
+// Luz Nocturna - Ajustes rápidos de GNOME Shell
+//
+// Interruptor y slider de temperatura que hablan con la aplicación por
+// D-Bus (com.luznocturna.LuzNocturna). La extensión no toca la gamma: si
+// la aplicación no está abierta, los controles se ocultan.
+
+import Gio from 'gi://Gio';
+import GLib from 'gi://GLib';
+import GObject from 'gi://GObject';
+
+import {Extension} from 'resource:///org/gnome/shell/extensions/extension.js';
+import * as Main from 'resource:///org/gnome/shell/ui/main.js';
+import {QuickSlider, QuickToggle, SystemIndicator} from 'resource:///org/gnome/shell/ui/quickSettings.js';
+
+const BUS_NAME = 'com.luznocturna.LuzNocturna';
+const OBJECT_PATH = '/com/luznocturna/LuzNocturna';
+
+const LuzNocturnaIface = `
+<node>
+    <interface name="com.luznocturna.LuzNocturna">
+        <method name="GetState">
+            <arg direction="out" type="d" name="temperature"/>
+            <arg direction="out" type="b" name="active"/>
+        </method>
+        <method name="SetTemperature">
+            <arg direction="in" type="d" name="temperature"/>
+        </method>
+        <method name="Apply"/>
+        <method name="Toggle"/>
+        <signal name="TemperatureChanged">
+            <arg type="d" name="temperature"/>
+            <arg type="s" name="source"/>
+        </signal>
+        <signal name="Applied">
+            <arg type="d" name="temperature"/>
+            <arg type="s" name="source"/>
+        </signal>
+        <signal name="Reset"/>
+        <signal name="ScheduleTransition">
+            <arg type="d" name="temperature"/>
+        </signal>
+    </interface>
+</node>`;
+const LuzNocturnaProxy = Gio.DBusProxy.makeProxyWrapper(LuzNocturnaIface);
+
+// Mismo rango y paso que el slider de la aplicación
+const MIN_TEMP = 3000;
+const MAX_TEMP = 6500;
+const TEMP_STEP = 100;
+
+// Espera tras soltar el slider antes de aplicar, para no aplicar cada paso del arrastre
+const APPLY_DELAY_MS = 300;
+
+const NightLightToggle = GObject.registerClass(
+class NightLightToggle extends QuickToggle {
+    _init(proxy) {
+        super._init({
+            title: 'Luz Nocturna',
+            iconName: 'night-light-symbolic',
+            toggleMode: false,
+        });
+        this.connect('clicked', () => proxy.ToggleRemote(() => {}));
+    }
+});
+
+const TemperatureSlider = GObject.registerClass(
+class TemperatureSlider extends QuickSlider {
+    _init(proxy) {
+        super._init({iconName: 'night-light-symbolic'});
+        this._proxy = proxy;
+        this._applyId = 0;
+        this._syncing = false;
+
+        this.slider.accessible_name = 'Temperatura de color';
+        this.slider.connect('notify::value', () => this._onSliderChanged());
+    }
+
+    // El extremo derecho es la temperatura más cálida
+    setTemperature(temperature) {
+        this._syncing = true;
+        this.slider.value = (MAX_TEMP - temperature) / (MAX_TEMP - MIN_TEMP);
+        this._syncing = false;
+    }
+
+    _onSliderChanged() {
+        if (this._syncing)
+            return;
+
+        if (this._applyId)
+            GLib.source_remove(this._applyId);
+        this._applyId = GLib.timeout_add(GLib.PRIORITY_DEFAULT, APPLY_DELAY_MS, () => {
+            this._applyId = 0;
+            const kelvin = MAX_TEMP - this.slider.value * (MAX_TEMP - MIN_TEMP);
+            const temperature = Math.round(kelvin / TEMP_STEP) * TEMP_STEP;
+            this._proxy.SetTemperatureRemote(temperature, (_result, error) => {
+                if (!error)
+                    this._proxy.ApplyRemote(() => {});
+            });
+            return GLib.SOURCE_REMOVE;
+        });
+    }
+
+    destroy() {
+        if (this._applyId)
+            GLib.source_remove(this._applyId);
+        this._applyId = 0;
+        super.destroy();
+    }
+});
+
+const Indicator = GObject.registerClass(
+class Indicator extends SystemIndicator {
+    _init() {
+        super._init();
+
+        this._proxy = new LuzNocturnaProxy(Gio.DBus.session, BUS_NAME, OBJECT_PATH, (_proxy, error) => {
+            if (error) {
+                console.warn(`Luz Nocturna: ${error.message}`);
+                return;
+            }
+            this._refresh();
+        }, null, Gio.DBusProxyFlags.DO_NOT_AUTO_START);
+
+        this._toggle = new NightLightToggle(this._proxy);
+        this._slider = new TemperatureSlider(this._proxy);
+        this.quickSettingsItems.push(this._toggle, this._slider);
+
+        this._signalIds = ['TemperatureChanged', 'Applied', 'Reset', 'ScheduleTransition']
+            .map(name => this._proxy.connectSignal(name, () => this._refresh()));
+        this._ownerId = this._proxy.connect('notify::g-name-owner', () => this._refresh());
+        this._setRunning(false);
+    }
+
+    _setRunning(running) {
+        this._toggle.visible = running;
+        this._slider.visible = running;
+    }
+
+    _refresh() {
+        const running = this._proxy.g_name_owner !== null;
+        this._setRunning(running);
+        if (!running)
+            return;
+
+        this._proxy.GetStateRemote((result, error) => {
+            if (error)
+                return;
+            const [temperature, active] = result;
+            this._toggle.checked = active;
+            this._slider.setTemperature(temperature);
+        });
+    }
+
+    destroy() {
+        this._signalIds.forEach(id => this._proxy.disconnectSignal(id));
+        this._proxy.disconnect(this._ownerId);
+        this.quickSettingsItems.forEach(item => item.destroy());
+        super.destroy();
+    }
+});
+
+export default class LuzNocturnaExtension extends Extension {
+    enable() {
+        this._indicator = new Indicator();
+        Main.panel.statusArea.quickSettings.addExternalIndicator(this._indicator, 2);
+    }
+
+    disable() {
+        this._indicator.destroy();
+        this._indicator = null;
+    }
+}
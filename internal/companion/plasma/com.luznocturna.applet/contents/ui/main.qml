@@ -0,0 +1,125 @@
+// Luz Nocturna - Applet de Plasma
+//
+// Interruptor y slider de temperatura que hablan con la aplicación por
+// D-Bus (com.luznocturna.LuzNocturna). El applet no toca la gamma: si la
+// aplicación no está abierta, lo indica y deja los controles desactivados.
+
+import QtQuick
+import QtQuick.Layouts
+import org.kde.kirigami as Kirigami
+import org.kde.plasma.components as PlasmaComponents
+import org.kde.plasma.plasmoid
+import org.kde.plasma.workspace.dbus as DBus
+
+PlasmoidItem {
+    id: root
+
+    // Mismo rango y paso que el slider de la aplicación
+    readonly property int minTemp: 3000
+    readonly property int maxTemp: 6500
+    readonly property int tempStep: 100
+
+    property bool running: false
+    property bool active: false
+    property int temperature: 4500
+
+    Plasmoid.icon: active ? "redshift-status-on" : "redshift-status-off"
+    toolTipMainText: "Luz Nocturna"
+    toolTipSubText: !running ? "La aplicación no está abierta"
+                             : active ? temperature + "K" : "Filtro desactivado"
+
+    function call(member, args, signature, onReply) {
+        const reply = DBus.SessionBus.asyncCall({
+            service: "com.luznocturna.LuzNocturna",
+            path: "/com/luznocturna/LuzNocturna",
+            iface: "com.luznocturna.LuzNocturna",
+            member: member,
+            arguments: args,
+            signature: signature,
+        });
+        reply.finished.connect(() => {
+            if (onReply) {
+                onReply(reply);
+            }
+        });
+    }
+
+    // Sin señales en QML: se consulta el estado periódicamente y tras cada acción
+    function refresh() {
+        call("GetState", [], "", reply => {
+            root.running = !reply.isError;
+            if (reply.isError) {
+                return;
+            }
+            root.temperature = Math.round(reply.values[0]);
+            root.active = reply.values[1];
+        });
+    }
+
+    Timer {
+        interval: root.expanded ? 2000 : 10000
+        running: true
+        repeat: true
+        triggeredOnStart: true
+        onTriggered: root.refresh()
+    }
+
+    Timer {
+        id: applyTimer
+        interval: 300
+        onTriggered: {
+            root.call("SetTemperature", [slider.value], "d", reply => {
+                if (!reply.isError) {
+                    root.call("Apply", [], "", () => root.refresh());
+                }
+            });
+        }
+    }
+
+    compactRepresentation: Kirigami.Icon {
+        source: Plasmoid.icon
+        active: compactMouse.containsMouse
+
+        MouseArea {
+            id: compactMouse
+            anchors.fill: parent
+            hoverEnabled: true
+            acceptedButtons: Qt.LeftButton | Qt.MiddleButton
+            onClicked: mouse => {
+                if (mouse.button === Qt.MiddleButton) {
+                    root.call("Toggle", [], "", () => root.refresh());
+                } else {
+                    root.expanded = !root.expanded;
+                }
+            }
+        }
+    }
+
+    fullRepresentation: ColumnLayout {
+        Layout.preferredWidth: Kirigami.Units.gridUnit * 16
+
+        PlasmaComponents.Switch {
+            text: "Luz Nocturna"
+            enabled: root.running
+            checked: root.active
+            onToggled: root.call("Toggle", [], "", () => root.refresh())
+        }
+
+        PlasmaComponents.Slider {
+            id: slider
+            Layout.fillWidth: true
+            enabled: root.running
+            from: root.maxTemp
+            to: root.minTemp
+            stepSize: root.tempStep
+            value: root.temperature
+            onMoved: applyTimer.restart()
+        }
+
+        PlasmaComponents.Label {
+            Layout.fillWidth: true
+            text: root.running ? Math.round(slider.value) + "K" : "Abre Luz Nocturna (o luz-nocturna --tray) para usar el applet"
+            wrapMode: Text.WordWrap
+        }
+    }
+}
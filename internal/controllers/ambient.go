@@ -0,0 +1,173 @@
+package controllers
+
+import (
+	"context"
+	"luznocturna/luz-nocturna/internal/logging"
+	"luznocturna/luz-nocturna/internal/models"
+	"luznocturna/luz-nocturna/internal/system"
+	"math"
+	"sync"
+	"time"
+)
+
+// Suavizado de la luz estimada: cada muestra pesa la mitad y solo se
+// reaplica la gamma si el nivel cambió lo suficiente para notarse
+const (
+	ambientSmoothing       = 0.5
+	ambientChangeThreshold = 0.05
+)
+
+/**
+ * ambientState - Luz de la habitación estimada con la webcam
+ *
+ * Como el perfil de batería, el ajuste se aplica en runApply sobre la
+ * petición que llega al backend.
+ *
+ * @struct {ambientState}
+ * @property {float64} level - Luz suavizada (0 oscuridad, 1 luz plena)
+ * @property {float64} applied - Nivel con el que se aplicó la gamma por última vez
+ * @property {bool} valid - Ya hay al menos una muestra
+ * @property {context.CancelFunc} cancel - Detiene las muestras (nil si el ajuste está desactivado)
+ */
+type ambientState struct {
+	mu      sync.Mutex
+	level   float64
+	applied float64
+	valid   bool
+	cancel  context.CancelFunc
+}
+
+// current devuelve el nivel con el que debe ajustarse la gamma; false si no hay muestras
+func (s *ambientState) current() (float64, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.applied, s.valid
+}
+
+// record suaviza una muestra nueva e indica si el cambio merece reaplicar la gamma
+func (s *ambientState) record(sample float64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.valid {
+		s.level, s.applied, s.valid = sample, sample, true
+		return true
+	}
+	s.level += (sample - s.level) * ambientSmoothing
+	if math.Abs(s.level-s.applied) < ambientChangeThreshold {
+		return false
+	}
+	s.applied = s.level
+	return true
+}
+
+// reset olvida las muestras e indica si había alguna en uso
+func (s *ambientState) reset() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	wasValid := s.valid
+	s.level, s.applied, s.valid = 0, 0, false
+	return wasValid
+}
+
+// startAmbient empieza a tomar muestras de la webcam si el ajuste está activado
+func (c *NightLightController) startAmbient() {
+	ambient := c.GetAmbient()
+	if !ambient.Enabled {
+		return
+	}
+
+	c.ambient.mu.Lock()
+	defer c.ambient.mu.Unlock()
+	if c.ambient.cancel != nil {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	c.ambient.cancel = cancel
+
+	interval := time.Duration(ambient.GetInterval()) * time.Second
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		logging.Printf("📷 Estimando la luz de la habitación con la webcam cada %v\n", interval)
+		for {
+			c.sampleAmbient(ctx)
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// stopAmbient detiene las muestras y quita el ajuste; indica si estaba en uso
+func (c *NightLightController) stopAmbient() bool {
+	c.ambient.mu.Lock()
+	if c.ambient.cancel != nil {
+		c.ambient.cancel()
+		c.ambient.cancel = nil
+	}
+	c.ambient.mu.Unlock()
+
+	return c.ambient.reset()
+}
+
+/**
+ * sampleAmbient - Toma una muestra de la webcam y reaplica la gamma si cambió
+ *
+ * Solo se enciende la webcam con el filtro activo: con la gamma normal
+ * el ajuste no tendría efecto.
+ *
+ * @param {context.Context} ctx - Contexto de las muestras
+ * @private
+ */
+func (c *NightLightController) sampleAmbient(ctx context.Context) {
+	if !c.lastState().Active {
+		return
+	}
+
+	level, err := system.SampleWebcamBrightness(ctx, c.GetAmbient().Device)
+	if err != nil {
+		if ctx.Err() == nil {
+			logging.Printf("⚠️  No se pudo estimar la luz de la habitación: %v\n", err)
+		}
+		return
+	}
+	if !c.ambient.record(level) {
+		return
+	}
+
+	applied, _ := c.ambient.current()
+	logging.Printf("📷 Luz de la habitación: %.0f%%\n", applied*100)
+	c.reapplyLastState()
+}
+
+// GetAmbient devuelve la configuración del ajuste por luz ambiental
+func (c *NightLightController) GetAmbient() models.AmbientConfig {
+	return c.currentAppConfig().Ambient
+}
+
+// GetAmbientLevel devuelve la luz estimada de la habitación (0-1); false si aún no hay muestras
+func (c *NightLightController) GetAmbientLevel() (float64, bool) {
+	return c.ambient.current()
+}
+
+/**
+ * SetAmbient - Cambia el ajuste por luz ambiental y reinicia las muestras
+ *
+ * @param {models.AmbientConfig} config - Nueva configuración
+ * @returns {error} Error si la configuración no es válida o no se pudo guardar
+ */
+func (c *NightLightController) SetAmbient(config models.AmbientConfig) error {
+	if err := config.Validate(); err != nil {
+		return err
+	}
+
+	err := c.updateAppConfig(func(app *models.AppConfig) { app.Ambient = config })
+	if c.stopAmbient() {
+		c.reapplyLastState()
+	}
+	c.startAmbient()
+	return err
+}
@@ -0,0 +1,209 @@
+package controllers
+
+import (
+	"errors"
+	"luznocturna/luz-nocturna/internal/logging"
+	"luznocturna/luz-nocturna/internal/models"
+	"luznocturna/luz-nocturna/internal/system"
+	"sync"
+)
+
+/**
+ * appRuleState - Regla por aplicación en vigor y seguimiento del foco
+ *
+ * La regla no cambia la temperatura elegida ni el historial: runApply
+ * la sustituye al llegar al backend, de modo que el programador, el
+ * vigilante y la ventana siguen trabajando con el estado del usuario.
+ *
+ * @struct {appRuleState}
+ * @property {*models.AppRule} active - Regla de la ventana enfocada (nil si ninguna coincide)
+ * @property {*system.FocusWatcher} watcher - Seguimiento de la ventana activa (nil si no hay reglas)
+ */
+type appRuleState struct {
+	mu      sync.Mutex
+	active  *models.AppRule
+	watcher *system.FocusWatcher
+}
+
+// current devuelve la regla en vigor
+func (s *appRuleState) current() (models.AppRule, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.active == nil {
+		return models.AppRule{}, false
+	}
+	return *s.active, true
+}
+
+// set cambia la regla en vigor e indica si es distinta de la anterior
+func (s *appRuleState) set(rule *models.AppRule) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if (s.active == nil && rule == nil) || (s.active != nil && rule != nil && *s.active == *rule) {
+		return false
+	}
+	s.active = rule
+	return true
+}
+
+// startAppRules empieza a seguir la ventana activa si hay reglas configuradas
+func (c *NightLightController) startAppRules() {
+	if len(c.GetAppRules()) == 0 {
+		return
+	}
+
+	c.appRules.mu.Lock()
+	defer c.appRules.mu.Unlock()
+	if c.appRules.watcher != nil {
+		return
+	}
+	watcher, err := system.StartFocusWatcher(c.gammaManager.GetProtocol(), c.onFocusChanged)
+	if err != nil {
+		logging.Printf("⚠️  Las reglas por aplicación no funcionarán: %v\n", err)
+		return
+	}
+	c.appRules.watcher = watcher
+}
+
+// stopAppRules detiene el seguimiento y quita la regla en vigor; indica si había una
+func (c *NightLightController) stopAppRules() bool {
+	c.appRules.mu.Lock()
+	if c.appRules.watcher != nil {
+		c.appRules.watcher.Stop()
+		c.appRules.watcher = nil
+	}
+	c.appRules.mu.Unlock()
+
+	return c.appRules.set(nil)
+}
+
+/**
+ * onFocusChanged - Aplica o retira la regla al cambiar la ventana enfocada
+ *
+ * @param {[]string} ids - Identificadores de la ventana (WM_CLASS o app_id)
+ * @callback - Seguimiento de la ventana activa
+ */
+func (c *NightLightController) onFocusChanged(ids []string) {
+	var active *models.AppRule
+	if rule, found := models.FindAppRule(c.GetAppRules(), ids); found {
+		active = &rule
+	}
+	if !c.appRules.set(active) {
+		return
+	}
+
+	switch {
+	case active == nil:
+		logging.Println("🎯 Sin regla para la ventana activa: temperatura del usuario")
+	case active.Disable:
+		logging.Printf("🎯 Regla de %s: filtro desactivado\n", active.Match)
+	default:
+		logging.Printf("🎯 Regla de %s: %.0fK\n", active.Match, active.Temperature)
+	}
+	c.reapplyLastState()
+}
+
+// reapplyLastState vuelve a enviar el último estado aplicado para que runApply use la regla en vigor
+func (c *NightLightController) reapplyLastState() {
+	state := c.lastState()
+	if !state.Active {
+		return
+	}
+	if err := c.applyQueue.Apply(state.Temperature, state.Brightness); err != nil && !errors.Is(err, errApplySuperseded) {
+		logging.Printf("⚠️  No se pudo aplicar la regla en vigor: %v\n", err)
+	}
+}
+
+/**
+ * effectiveRequest - Sustituye una petición por la que debe llegar al backend
+ *
+ * Durante la calibración todo es el blanco de referencia a
+ * models.DaylightTemp, incluso los resets.
+ * Con la sesión bloqueada (y la opción activada), en modo película o de
+ * color fiel, o con gamescope o Steam Big Picture en marcha todo es un reset. Si no, se
+ * aplica la regla en vigor y, encima, el perfil de batería y el ajuste
+ * por luz ambiental. Los resets no cambian: si el filtro está
+ * desactivado nada lo vuelve a activar.
+ *
+ * @param {applyRequest} request - Petición con el estado del usuario
+ * @returns {applyRequest} Petición que llega al backend
+ * @private
+ */
+func (c *NightLightController) effectiveRequest(request applyRequest) applyRequest {
+	if c.calibration.isActive() {
+		return applyRequest{temperature: models.DaylightTemp, brightness: 1.0}
+	}
+	if request.reset {
+		return request
+	}
+	if c.sessionLock.isLocked() || c.movieMode.isActive() || c.colorAccurate.isActive() || c.gaming.current() != "" {
+		return applyRequest{reset: true}
+	}
+
+	request = c.ruleRequest(request)
+	config, minTemp := c.currentAppConfig(), c.GetConfig().MinTemp
+	if !request.reset && c.power.isSaving() {
+		request.temperature, request.brightness = config.Battery.Adjust(
+			request.temperature, request.brightness, minTemp, config.Presets)
+	}
+	if level, found := c.ambient.current(); found && !request.reset {
+		request.temperature, request.brightness = config.Ambient.Adjust(
+			level, request.temperature, request.brightness, minTemp)
+	}
+	return request
+}
+
+// ruleRequest aplica la regla por aplicación o, si no hay ninguna, la del espacio de trabajo (o el paso del fundido)
+func (c *NightLightController) ruleRequest(request applyRequest) applyRequest {
+	if rule, found := c.appRules.current(); found {
+		if rule.Disable {
+			return applyRequest{reset: true}
+		}
+		request.temperature = rule.Temperature
+		return request
+	}
+
+	rule, fading := c.workspaceRules.current()
+	switch {
+	case fading != nil:
+		request.temperature = *fading
+	case rule == nil:
+	case rule.Disable:
+		return applyRequest{reset: true}
+	default:
+		request.temperature = rule.Temperature
+	}
+	return request
+}
+
+// GetActiveAppRule devuelve la regla de la ventana enfocada, si hay alguna en vigor
+func (c *NightLightController) GetActiveAppRule() (models.AppRule, bool) {
+	return c.appRules.current()
+}
+
+// GetAppRules devuelve las reglas por aplicación configuradas
+func (c *NightLightController) GetAppRules() []models.AppRule {
+	return append([]models.AppRule(nil), c.currentAppConfig().AppRules...)
+}
+
+/**
+ * SetAppRules - Reemplaza las reglas por aplicación y reinicia el seguimiento
+ *
+ * @param {[]models.AppRule} rules - Nuevas reglas, en orden de prioridad
+ * @returns {error} Error si alguna regla no es válida o no se pudo guardar
+ */
+func (c *NightLightController) SetAppRules(rules []models.AppRule) error {
+	minTemp, maxTemp := c.GetTemperatureRange()
+	for _, rule := range rules {
+		if err := rule.Validate(minTemp, maxTemp); err != nil {
+			return err
+		}
+	}
+
+	err := c.updateAppConfig(func(config *models.AppConfig) { config.AppRules = rules })
+	if c.stopAppRules() {
+		c.reapplyLastState()
+	}
+	c.startAppRules()
+	return err
+}
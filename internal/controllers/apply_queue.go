@@ -0,0 +1,162 @@
+package controllers
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// applyMinInterval es el tiempo mínimo entre dos llamadas al backend de gamma
+const applyMinInterval = 100 * time.Millisecond
+
+// errApplySuperseded indica que una petición fue reemplazada por otra más
+// reciente antes de ejecutarse y no llegó al backend
+var errApplySuperseded = errors.New("petición de gamma reemplazada por otra más reciente")
+
+// applyRequest es una petición pendiente de aplicar o resetear la gamma
+type applyRequest struct {
+	reset       bool
+	temperature float64
+	brightness  float64
+}
+
+// applyResult es lo que recibe quien espera: la petición que se ejecutó de verdad y su error
+type applyResult struct {
+	request applyRequest
+	err     error
+}
+
+// errFor devuelve el error para quien envió request, o errApplySuperseded si se
+// ejecutó otra petición en su lugar (y no debe registrar su valor como aplicado)
+func (r applyResult) errFor(request applyRequest) error {
+	if r.request != request {
+		return errApplySuperseded
+	}
+	return r.err
+}
+
+/**
+ * ApplyQueue - Cola serializada de aplicaciones de gamma
+ *
+ * Todas las aplicaciones (ventana, presets, programador, bandeja, D-Bus)
+ * pasan por una única goroutine, de modo que nunca se intercalan
+ * comandos del backend. Las peticiones que llegan mientras otra está en
+ * curso se fusionan: solo se aplica la última, quien la envió recibe su
+ * resultado y los demás reciben errApplySuperseded. Entre dos llamadas
+ * al backend pasa como mínimo applyMinInterval.
+ *
+ * @struct {ApplyQueue}
+ * @property {*applyRequest} pending - Última petición aún no aplicada
+ * @property {[]chan applyResult} waiters - Canales de quienes esperan a la petición pendiente
+ * @property {func(applyRequest) error} run - Función que llama al backend
+ * @property {func()} onPanic - Se llama si el worker entra en pánico, antes de relanzarlo
+ */
+type ApplyQueue struct {
+	mu      sync.Mutex
+	pending *applyRequest
+	waiters []chan applyResult
+	wake    chan struct{}
+	run     func(applyRequest) error
+	onPanic func()
+}
+
+/**
+ * newApplyQueue - Crea la cola e inicia su goroutine de trabajo
+ *
+ * @param {func(applyRequest) error} run - Función que ejecuta una petición en el backend
+ * @param {func()} onPanic - Función que restaura la gamma si el worker entra en pánico (puede ser nil)
+ * @returns {*ApplyQueue} Cola lista para usar
+ * @private
+ */
+func newApplyQueue(run func(applyRequest) error, onPanic func()) *ApplyQueue {
+	queue := &ApplyQueue{
+		wake:    make(chan struct{}, 1),
+		run:     run,
+		onPanic: onPanic,
+	}
+	go queue.worker()
+	return queue
+}
+
+// Apply encola una temperatura y espera a que se aplique (o se fusione con otra posterior)
+func (q *ApplyQueue) Apply(temperature, brightness float64) error {
+	request := applyRequest{temperature: temperature, brightness: brightness}
+	return (<-q.submit(request)).errFor(request)
+}
+
+// Reset encola la restauración de la gamma normal y espera el resultado
+func (q *ApplyQueue) Reset() error {
+	request := applyRequest{reset: true}
+	return (<-q.submit(request)).errFor(request)
+}
+
+/**
+ * submit - Reemplaza la petición pendiente y despierta al worker
+ *
+ * @param {applyRequest} request - Nueva petición
+ * @returns {<-chan applyResult} Canal que recibe la petición ejecutada y su resultado
+ * @private
+ */
+func (q *ApplyQueue) submit(request applyRequest) <-chan applyResult {
+	done := make(chan applyResult, 1)
+
+	q.mu.Lock()
+	if q.pending != nil && q.pending.reset != request.reset {
+		// Un reset no puede responder por un apply (ni al revés)
+		for _, waiter := range q.waiters {
+			waiter <- applyResult{request: *q.pending, err: errApplySuperseded}
+		}
+		q.waiters = nil
+	}
+	q.pending = &request
+	q.waiters = append(q.waiters, done)
+	q.mu.Unlock()
+
+	select {
+	case q.wake <- struct{}{}:
+	default: // El worker ya tiene un aviso pendiente
+	}
+	return done
+}
+
+/**
+ * worker - Goroutine que ejecuta las peticiones de una en una
+ *
+ * @private
+ */
+func (q *ApplyQueue) worker() {
+	defer func() {
+		if r := recover(); r != nil {
+			if q.onPanic != nil {
+				q.onPanic()
+			}
+			panic(r)
+		}
+	}()
+
+	var last time.Time
+	for range q.wake {
+		// Limitar la frecuencia: esperar y fusionar lo que llegue mientras tanto
+		if wait := applyMinInterval - time.Since(last); wait > 0 {
+			time.Sleep(wait)
+		}
+
+		q.mu.Lock()
+		request := q.pending
+		waiters := q.waiters
+		q.pending = nil
+		q.waiters = nil
+		q.mu.Unlock()
+
+		if request == nil {
+			continue
+		}
+
+		err := q.run(*request)
+		last = time.Now()
+
+		for _, done := range waiters {
+			done <- applyResult{request: *request, err: err}
+		}
+	}
+}
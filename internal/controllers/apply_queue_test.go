@@ -0,0 +1,117 @@
+package controllers
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// blockingRun devuelve una función run que registra cada petición y se bloquea
+// en la primera hasta que se cierra release
+func blockingRun(started chan<- struct{}, release <-chan struct{}) (func(applyRequest) error, func() []applyRequest) {
+	var mu sync.Mutex
+	var ran []applyRequest
+	run := func(request applyRequest) error {
+		mu.Lock()
+		ran = append(ran, request)
+		first := len(ran) == 1
+		mu.Unlock()
+		if first {
+			close(started)
+			<-release
+		}
+		return nil
+	}
+	return run, func() []applyRequest {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]applyRequest(nil), ran...)
+	}
+}
+
+func receive(t *testing.T, done <-chan applyResult) applyResult {
+	t.Helper()
+	select {
+	case result := <-done:
+		return result
+	case <-time.After(2 * time.Second):
+		t.Fatal("la cola no respondió a tiempo")
+		return applyResult{}
+	}
+}
+
+func TestApplyQueueMergedRequestsAreSuperseded(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	run, ran := blockingRun(started, release)
+	queue := newApplyQueue(run, nil)
+
+	first := applyRequest{temperature: 5000, brightness: 1}
+	firstDone := queue.submit(first)
+	<-started
+
+	// Mientras la primera está en curso llegan dos más: se fusionan y solo se aplica la última
+	middle := applyRequest{temperature: 4000, brightness: 1}
+	last := applyRequest{temperature: 3000, brightness: 0.9}
+	middleDone := queue.submit(middle)
+	lastDone := queue.submit(last)
+	close(release)
+
+	if err := receive(t, firstDone).errFor(first); err != nil {
+		t.Errorf("primera petición: error %v, se esperaba nil", err)
+	}
+	middleResult := receive(t, middleDone)
+	if middleResult.request != last {
+		t.Errorf("la petición fusionada recibió %+v, se esperaba la ejecutada %+v", middleResult.request, last)
+	}
+	if err := middleResult.errFor(middle); !errors.Is(err, errApplySuperseded) {
+		t.Errorf("petición fusionada: error %v, se esperaba errApplySuperseded", err)
+	}
+	if err := receive(t, lastDone).errFor(last); err != nil {
+		t.Errorf("última petición: error %v, se esperaba nil", err)
+	}
+
+	got := ran()
+	if len(got) != 2 || got[0] != first || got[1] != last {
+		t.Errorf("peticiones ejecutadas = %+v, se esperaba [%+v %+v]", got, first, last)
+	}
+}
+
+func TestApplyQueueResetSupersedesPendingApply(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	run, ran := blockingRun(started, release)
+	queue := newApplyQueue(run, nil)
+
+	go queue.Apply(5000, 1)
+	<-started
+
+	applyErr := make(chan error, 1)
+	go func() { applyErr <- queue.Apply(4000, 1) }()
+	// Esperar a que la petición quede pendiente antes de enviar el reset
+	for {
+		queue.mu.Lock()
+		pending := queue.pending != nil
+		queue.mu.Unlock()
+		if pending {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	resetErr := make(chan error, 1)
+	go func() { resetErr <- queue.Reset() }()
+
+	if err := <-applyErr; !errors.Is(err, errApplySuperseded) {
+		t.Errorf("Apply reemplazado por un reset: error %v, se esperaba errApplySuperseded", err)
+	}
+	close(release)
+	if err := <-resetErr; err != nil {
+		t.Errorf("Reset: error %v, se esperaba nil", err)
+	}
+
+	got := ran()
+	if len(got) != 2 || !got[1].reset {
+		t.Errorf("peticiones ejecutadas = %+v, se esperaba que la última fuera un reset", got)
+	}
+}
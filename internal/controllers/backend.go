@@ -0,0 +1,50 @@
+package controllers
+
+import "luznocturna/luz-nocturna/internal/system"
+
+/**
+ * GammaBackend - Operaciones de gamma que necesita el controlador
+ *
+ * *system.GammaManager es la implementación real; las pruebas de
+ * integración usan system.FakeBackend para ejercitar el flujo completo
+ * (aplicar, resetear, programación) sin tocar el display.
+ *
+ * @interface {GammaBackend}
+ */
+type GammaBackend interface {
+	ApplyTemperatureWithBrightness(temperature, brightness float64) error
+	Reset() error
+	SetExcludedDisplays(displays []string)
+	VerifyGamma(temperature, brightness float64) (bool, error)
+	GetDisplays() []string
+	GetHDRDisplays() []string
+	RefreshDisplays() []string
+	RefreshCapabilities()
+	GetBackend() string
+	GetProtocol() string
+	Close()
+}
+
+// displayInfoBackend lo implementan los backends que conocen el modelo y las
+// capacidades de cada display (*system.GammaManager)
+type displayInfoBackend interface {
+	GetDisplayInfo() []system.DisplayInfo
+}
+
+// contrastBackend lo implementan los backends que pueden cambiar la forma de
+// la curva gamma (*system.GammaManager); al resto no se les pide contraste
+type contrastBackend interface {
+	SetContrast(contrast float64)
+}
+
+// desaturationBackend lo implementan los backends que pueden quitar color a la
+// imagen con una matriz de color (*system.GammaManager, en X11 con CTM)
+type desaturationBackend interface {
+	SetDesaturation(desaturation float64)
+}
+
+// channelTrimBackend lo implementan los backends que aplican la gamma por
+// display (*system.GammaManager en X11) y admiten un ajuste fino RGB en cada uno
+type channelTrimBackend interface {
+	SetChannelTrims(trims map[string][3]float64)
+}
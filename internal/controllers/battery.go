@@ -0,0 +1,120 @@
+package controllers
+
+import (
+	"luznocturna/luz-nocturna/internal/logging"
+	"luznocturna/luz-nocturna/internal/models"
+	"luznocturna/luz-nocturna/internal/system"
+	"sync"
+)
+
+/**
+ * powerState - Perfil de batería en vigor y seguimiento de UPower
+ *
+ * Como las reglas, el perfil se aplica en runApply sobre la petición que
+ * llega al backend; el estado del usuario y el historial no cambian.
+ *
+ * @struct {powerState}
+ * @property {bool} saving - El perfil de batería está en vigor
+ * @property {*system.PowerWatcher} watcher - Seguimiento de la alimentación (nil si el perfil está desactivado)
+ */
+type powerState struct {
+	mu      sync.Mutex
+	saving  bool
+	watcher *system.PowerWatcher
+}
+
+// isSaving indica si el perfil de batería está en vigor
+func (s *powerState) isSaving() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.saving
+}
+
+// set cambia el estado del perfil e indica si es distinto del anterior
+func (s *powerState) set(saving bool) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	changed := s.saving != saving
+	s.saving = saving
+	return changed
+}
+
+// startPowerWatcher empieza a seguir la alimentación si el perfil de batería está activado
+func (c *NightLightController) startPowerWatcher() {
+	if !c.GetBattery().Enabled {
+		return
+	}
+
+	c.power.mu.Lock()
+	defer c.power.mu.Unlock()
+	if c.power.watcher != nil {
+		return
+	}
+	watcher, err := system.StartPowerWatcher(c.onPowerChanged)
+	if err != nil {
+		logging.Printf("⚠️  El perfil de batería no funcionará (UPower no disponible): %v\n", err)
+		return
+	}
+	c.power.watcher = watcher
+}
+
+// stopPowerWatcher detiene el seguimiento y quita el perfil; indica si estaba en vigor
+func (c *NightLightController) stopPowerWatcher() bool {
+	c.power.mu.Lock()
+	if c.power.watcher != nil {
+		c.power.watcher.Stop()
+		c.power.watcher = nil
+	}
+	c.power.mu.Unlock()
+
+	return c.power.set(false)
+}
+
+/**
+ * onPowerChanged - Aplica o retira el perfil de batería al cambiar la alimentación
+ *
+ * @param {system.PowerState} state - Alimentación actual según UPower
+ * @callback - Seguimiento de la alimentación
+ */
+func (c *NightLightController) onPowerChanged(state system.PowerState) {
+	saving := c.GetBattery().Applies(state.OnBattery, state.Percentage)
+	if !c.power.set(saving) {
+		return
+	}
+
+	if saving {
+		logging.Printf("🔋 Con batería (%.0f%%): perfil más cálido y tenue\n", state.Percentage)
+	} else {
+		logging.Println("🔌 Perfil de batería retirado")
+	}
+	c.reapplyLastState()
+}
+
+// GetBattery devuelve la configuración del perfil de batería
+func (c *NightLightController) GetBattery() models.BatteryConfig {
+	return c.currentAppConfig().Battery
+}
+
+// IsBatterySaving indica si el perfil de batería está en vigor ahora mismo
+func (c *NightLightController) IsBatterySaving() bool {
+	return c.power.isSaving()
+}
+
+/**
+ * SetBattery - Cambia el perfil de batería y reinicia el seguimiento de UPower
+ *
+ * @param {models.BatteryConfig} battery - Nueva configuración
+ * @returns {error} Error si la configuración no es válida o no se pudo guardar
+ */
+func (c *NightLightController) SetBattery(battery models.BatteryConfig) error {
+	if err := battery.Validate(c.GetPresets()); err != nil {
+		return err
+	}
+
+	err := c.updateAppConfig(func(config *models.AppConfig) { config.Battery = battery })
+	if c.stopPowerWatcher() {
+		c.reapplyLastState()
+	}
+	c.startPowerWatcher()
+	return err
+}
@@ -0,0 +1,167 @@
+package controllers
+
+import (
+	"errors"
+	"sync"
+
+	"luznocturna/luz-nocturna/internal/logging"
+	"luznocturna/luz-nocturna/internal/models"
+)
+
+/**
+ * calibrationState - Ajuste fino que se está probando con el asistente de calibración
+ *
+ * Mientras dura, effectiveRequest convierte cualquier petición (también
+ * las de la programación, las reglas o un reset) en el blanco de
+ * referencia a models.DaylightTemp, y el display que se calibra recibe
+ * el ajuste de prueba en lugar del guardado. Cada apertura del asistente
+ * es una sesión: las vistas previas que llegan tarde, de una sesión ya
+ * terminada, se descartan.
+ *
+ * @struct {calibrationState}
+ * @property {string} display - Display que se calibra ("" si no hay calibración en curso)
+ * @property {models.ChannelTrim} trim - Ajuste que se está probando
+ * @property {uint64} session - Sesión del asistente abierta; cambia al terminar
+ * @property {sync.Mutex} serial - Serializa las vistas previas con FinishCalibration
+ */
+type calibrationState struct {
+	mu      sync.Mutex
+	display string
+	trim    models.ChannelTrim
+	session uint64
+	serial  sync.Mutex
+}
+
+// begin abre una sesión nueva del asistente y devuelve su identificador
+func (s *calibrationState) begin() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.session++
+	return s.session
+}
+
+// current devuelve el display que se calibra y el ajuste de prueba; ok es false si no hay calibración
+func (s *calibrationState) current() (display string, trim models.ChannelTrim, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.display, s.trim, s.display != ""
+}
+
+// isActive indica si hay una calibración en curso
+func (s *calibrationState) isActive() bool {
+	_, _, ok := s.current()
+	return ok
+}
+
+// preview cambia el ajuste de prueba si la sesión sigue abierta; started indica si empieza la calibración
+func (s *calibrationState) preview(session uint64, display string, trim models.ChannelTrim) (started, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if session != s.session {
+		return false, false
+	}
+	started = s.display == ""
+	s.display, s.trim = display, trim
+	return started, true
+}
+
+// end termina la calibración y cierra la sesión; devuelve lo que se estaba probando y si había calibración
+func (s *calibrationState) end() (display string, trim models.ChannelTrim, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	display, trim, ok = s.display, s.trim, s.display != ""
+	s.display, s.trim = "", models.ChannelTrim{}
+	s.session++
+	return display, trim, ok
+}
+
+/**
+ * BeginCalibration - Abre una sesión del asistente de calibración
+ *
+ * No cambia nada en pantalla: la calibración empieza con la primera
+ * PreviewCalibration de la sesión.
+ *
+ * @returns {uint64} Sesión que se pasa a PreviewCalibration
+ */
+func (c *NightLightController) BeginCalibration() uint64 {
+	return c.calibration.begin()
+}
+
+/**
+ * PreviewCalibration - Muestra el blanco de referencia con un ajuste fino de prueba
+ *
+ * La primera llamada empieza la calibración: todos los displays pasan a
+ * models.DaylightTemp, donde el blanco debería verse neutro, y el display
+ * indicado recibe el ajuste de prueba. Las siguientes llamadas solo
+ * cambian el ajuste. Nada se guarda hasta FinishCalibration. Si la
+ * sesión ya terminó (una vista previa que llega tras cerrar el
+ * asistente) no hace nada.
+ *
+ * @param {uint64} session - Sesión devuelta por BeginCalibration
+ * @param {string} display - Nombre de la salida que se calibra (p. ej. "eDP-1")
+ * @param {models.ChannelTrim} trim - Ajuste de prueba
+ * @returns {error} Error si el ajuste no es válido o falla la aplicación
+ */
+func (c *NightLightController) PreviewCalibration(session uint64, display string, trim models.ChannelTrim) error {
+	if err := trim.Validate(); err != nil {
+		return err
+	}
+	c.calibration.serial.Lock()
+	defer c.calibration.serial.Unlock()
+
+	started, ok := c.calibration.preview(session, display, trim)
+	if !ok {
+		return nil
+	}
+	if started {
+		logging.Printf("🎯 Calibrando %s a %.0fK\n", display, float64(models.DaylightTemp))
+	}
+	err := c.applyQueue.Apply(models.DaylightTemp, 1.0)
+	if errors.Is(err, errApplySuperseded) {
+		return nil
+	}
+	return err
+}
+
+/**
+ * FinishCalibration - Termina la calibración y vuelve al estado anterior
+ *
+ * Con save guarda el ajuste de prueba como ajuste fino del display;
+ * sin él lo descarta. En ambos casos la gamma vuelve a la del usuario:
+ * el último estado aplicado o la gamma normal si el filtro estaba
+ * desactivado.
+ *
+ * @param {bool} save - Guardar el ajuste probado
+ * @returns {error} Error si no se pudo guardar la configuración
+ */
+func (c *NightLightController) FinishCalibration(save bool) error {
+	c.calibration.serial.Lock()
+	defer c.calibration.serial.Unlock()
+
+	display, trim, ok := c.calibration.end()
+	if !ok {
+		return nil
+	}
+
+	var err error
+	if save {
+		err = c.storeChannelTrim(display, trim)
+		if err == nil {
+			factors := trim.Factors()
+			logging.Printf("🎯 Calibración de %s guardada (R %.2f, G %.2f, B %.2f)\n", display, factors[0], factors[1], factors[2])
+		}
+	}
+
+	if c.isActive() {
+		c.reapplyLastState()
+	} else if resetErr := c.applyQueue.Reset(); resetErr != nil && !errors.Is(resetErr, errApplySuperseded) {
+		logging.Printf("⚠️  No se pudo restaurar la gamma tras la calibración: %v\n", resetErr)
+	}
+	return err
+}
+
+// stopCalibration descarta la calibración en curso sin reaplicar; indica si había una
+func (c *NightLightController) stopCalibration() bool {
+	_, _, ok := c.calibration.end()
+	return ok
+}
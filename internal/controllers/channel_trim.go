@@ -0,0 +1,65 @@
+package controllers
+
+import "luznocturna/luz-nocturna/internal/models"
+
+// GetChannelTrim devuelve el ajuste fino RGB del display (neutro si no tiene)
+func (c *NightLightController) GetChannelTrim(display string) models.ChannelTrim {
+	return c.currentAppConfig().ChannelTrims[display]
+}
+
+/**
+ * SetChannelTrim - Guarda el ajuste fino RGB de un display
+ *
+ * Los multiplicadores se aplican sobre la conversión de la temperatura
+ * para compensar paneles con un tinte verde o magenta. Un ajuste neutro
+ * borra la entrada del display. Si el filtro está activo se vuelve a
+ * aplicar para que el cambio se vea al momento; solo tiene efecto en X11,
+ * donde la gamma se aplica por display.
+ *
+ * @param {string} display - Nombre de la salida (p. ej. "HDMI-1")
+ * @param {models.ChannelTrim} trim - Multiplicadores de cada canal
+ * @returns {error} Error si el ajuste no es válido o falla la aplicación
+ */
+func (c *NightLightController) SetChannelTrim(display string, trim models.ChannelTrim) error {
+	if err := trim.Validate(); err != nil {
+		return err
+	}
+	if c.GetChannelTrim(display).Factors() == trim.Factors() {
+		return nil
+	}
+	if err := c.storeChannelTrim(display, trim); err != nil {
+		return err
+	}
+
+	if c.isActive() {
+		return c.applyNightLight("displays")
+	}
+	return nil
+}
+
+// storeChannelTrim guarda el ajuste fino del display en la configuración sin aplicarlo
+func (c *NightLightController) storeChannelTrim(display string, trim models.ChannelTrim) error {
+	return c.updateAppConfig(func(config *models.AppConfig) {
+		// Se reemplaza el mapa en lugar de modificarlo: runApply lo lee desde la cola
+		trims := make(models.ChannelTrims, len(config.ChannelTrims)+1)
+		for name, existing := range config.ChannelTrims {
+			trims[name] = existing
+		}
+		if trim.IsNeutral() {
+			delete(trims, display)
+		} else {
+			trims[display] = trim
+		}
+		config.ChannelTrims = trims
+	})
+}
+
+// channelTrimFactors devuelve los multiplicadores RGB por display que recibe el backend,
+// con el ajuste de prueba del asistente de calibración en lugar del guardado
+func (c *NightLightController) channelTrimFactors() map[string][3]float64 {
+	factors := c.currentAppConfig().ChannelTrims.Factors()
+	if display, trim, ok := c.calibration.current(); ok {
+		factors[display] = trim.Factors()
+	}
+	return factors
+}
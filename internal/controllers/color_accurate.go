@@ -0,0 +1,55 @@
+package controllers
+
+import (
+	"time"
+
+	"luznocturna/luz-nocturna/internal/logging"
+)
+
+/**
+ * StartColorAccurate - Retira el filtro para trabajar con colores fieles
+ *
+ * Pensado para retocar una foto o revisar un diseño sin perder la noche:
+ * a diferencia de Reset no olvida lo que estaba activo, y al pasar la
+ * duración configurada (30 minutos por defecto) vuelve a aplicarlo solo.
+ * Si ya estaba activo vuelve a empezar la cuenta.
+ */
+func (c *NightLightController) StartColorAccurate() {
+	config := c.currentAppConfig()
+	duration := config.GetAccurateDuration()
+	c.colorAccurate.begin(duration, c.StopColorAccurate)
+
+	logging.Printf("🎨 Color fiel durante %v: gamma normal\n", duration)
+	c.reapplyLastState()
+	c.publish(EventAccurateChanged, "color-accurate")
+}
+
+// StopColorAccurate termina el modo de color fiel y reaplica el estado anterior
+func (c *NightLightController) StopColorAccurate() {
+	if !c.stopColorAccurate() {
+		return
+	}
+	logging.Println("🎨 Fin del modo de color fiel: reaplicando el filtro")
+	c.reapplyLastState()
+	c.publish(EventAccurateChanged, "color-accurate")
+}
+
+// stopColorAccurate cancela el temporizador; indica si el modo de color fiel estaba activo
+func (c *NightLightController) stopColorAccurate() bool {
+	return c.colorAccurate.cancel()
+}
+
+// ToggleColorAccurate activa el modo de color fiel o lo termina si ya estaba activo
+func (c *NightLightController) ToggleColorAccurate() {
+	if c.colorAccurate.isActive() {
+		c.StopColorAccurate()
+	} else {
+		c.StartColorAccurate()
+	}
+}
+
+// GetColorAccurate indica si el modo de color fiel está activo y hasta qué hora
+func (c *NightLightController) GetColorAccurate() (bool, time.Time) {
+	until := c.colorAccurate.end()
+	return !until.IsZero(), until
+}
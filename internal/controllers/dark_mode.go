@@ -0,0 +1,167 @@
+package controllers
+
+import (
+	"errors"
+	"sync"
+
+	"luznocturna/luz-nocturna/internal/logging"
+	"luznocturna/luz-nocturna/internal/models"
+	"luznocturna/luz-nocturna/internal/system"
+)
+
+/**
+ * darkModeState - Seguimiento del tema claro/oscuro del escritorio
+ *
+ * Es una alternativa a la programación por horas o solar: el tema oscuro
+ * aplica la temperatura nocturna de la programación y el claro la diurna.
+ * Como la programación, cambia el estado del usuario.
+ *
+ * @struct {darkModeState}
+ * @property {system.ColorScheme} scheme - Último tema aplicado (ColorSchemeUnknown antes del primero)
+ * @property {*system.ColorSchemeWatcher} watcher - Seguimiento del portal (nil si la opción está desactivada)
+ * @property {sync.Mutex} changing - Serializa los cambios de tema (señales del portal y la lectura inicial)
+ */
+type darkModeState struct {
+	mu       sync.Mutex
+	scheme   system.ColorScheme
+	watcher  *system.ColorSchemeWatcher
+	changing sync.Mutex
+}
+
+/**
+ * set - Guarda el tema e indica si es distinto del anterior
+ *
+ * GNOME publica 0 ("por defecto", sin preferencia) al volver al tema
+ * claro, así que tras haber visto el oscuro ese valor cuenta como claro.
+ *
+ * @param {system.ColorScheme} scheme - Preferencia publicada por el portal
+ * @returns {system.ColorScheme, bool} Tema resultante y si cambió
+ * @private
+ */
+func (s *darkModeState) set(scheme system.ColorScheme) (system.ColorScheme, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if scheme == system.ColorSchemeUnknown && s.scheme == system.ColorSchemeDark {
+		scheme = system.ColorSchemeLight
+	}
+	changed := s.scheme != scheme
+	s.scheme = scheme
+	return scheme, changed
+}
+
+// clear olvida el último tema visto
+func (s *darkModeState) clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.scheme = system.ColorSchemeUnknown
+}
+
+// startDarkMode empieza a seguir el tema del escritorio si la opción está activada
+func (c *NightLightController) startDarkMode() {
+	if !c.IsFollowDarkMode() {
+		return
+	}
+
+	c.darkMode.mu.Lock()
+	defer c.darkMode.mu.Unlock()
+	if c.darkMode.watcher != nil {
+		return
+	}
+	watcher, err := system.StartColorSchemeWatcher(c.onColorSchemeChanged)
+	if err != nil {
+		logging.Printf("⚠️  No se seguirá el tema oscuro del escritorio: %v\n", err)
+		return
+	}
+	c.darkMode.watcher = watcher
+	logging.Println("🌗 Siguiendo el tema claro/oscuro del escritorio")
+
+	// Aplicar el tema actual sin bloquear el arranque con la consulta a D-Bus
+	go func() { c.onColorSchemeChanged(system.ReadColorScheme()) }()
+}
+
+// stopDarkMode detiene el seguimiento del tema del escritorio
+func (c *NightLightController) stopDarkMode() {
+	c.darkMode.mu.Lock()
+	if c.darkMode.watcher != nil {
+		c.darkMode.watcher.Stop()
+		c.darkMode.watcher = nil
+	}
+	c.darkMode.mu.Unlock()
+
+	c.darkMode.clear()
+}
+
+/**
+ * onColorSchemeChanged - Aplica la temperatura nocturna o diurna según el tema
+ *
+ * Con el tema oscuro aplica la temperatura nocturna (y la atenuación
+ * nocturna); con el claro, la diurna, o restaura la gamma normal si la
+ * diurna es la luz del día. Sin preferencia no cambia nada. Llega desde
+ * la goroutine de D-Bus (o la de la lectura inicial), así que los
+ * cambios se serializan y el estado se actualiza de una vez.
+ *
+ * @param {system.ColorScheme} scheme - Nueva preferencia del escritorio
+ * @callback - Seguimiento del tema del escritorio
+ */
+func (c *NightLightController) onColorSchemeChanged(scheme system.ColorScheme) {
+	c.darkMode.changing.Lock()
+	defer c.darkMode.changing.Unlock()
+
+	scheme, changed := c.darkMode.set(scheme)
+	if scheme == system.ColorSchemeUnknown || !changed {
+		return
+	}
+	if c.IsScheduleEnabled() {
+		logging.Println("🌗 Tema del escritorio cambiado, pero manda la programación automática")
+		return
+	}
+
+	schedule := c.GetScheduleConfig()
+	var err error
+	switch {
+	case scheme == system.ColorSchemeDark:
+		logging.Printf("🌙 Tema oscuro: %.0fK\n", schedule.NightTemp)
+		c.selectState(schedule.NightTemp, 1-schedule.NightDim, "appearance")
+		err = c.applyNightLight("appearance")
+	case schedule.DayTemp >= models.DaylightTemp:
+		logging.Println("☀️  Tema claro: gamma normal")
+		err = c.resetNightLight("appearance")
+	default:
+		logging.Printf("☀️  Tema claro: %.0fK\n", schedule.DayTemp)
+		c.selectState(schedule.DayTemp, 1.0, "appearance")
+		err = c.applyNightLight("appearance")
+	}
+
+	if err != nil && !errors.Is(err, system.ErrPartialApply) {
+		logging.Printf("⚠️  No se pudo seguir el tema del escritorio: %v\n", err)
+		c.events.Publish(Event{Type: EventApplyFailed, Temperature: c.GetConfig().Temperature, Source: "appearance", Err: err})
+	}
+}
+
+// IsFollowDarkMode indica si el tema oscuro del escritorio activa la temperatura nocturna
+func (c *NightLightController) IsFollowDarkMode() bool {
+	return c.currentAppConfig().FollowDarkMode
+}
+
+/**
+ * SetFollowDarkMode - Activa o desactiva el seguimiento del tema del escritorio
+ *
+ * Es una alternativa a la programación automática: al activarlo, la
+ * programación se desactiva.
+ *
+ * @param {bool} enabled - true para seguir el tema oscuro del escritorio
+ * @returns {error} Error si no se pudo guardar la configuración
+ */
+func (c *NightLightController) SetFollowDarkMode(enabled bool) error {
+	if c.IsFollowDarkMode() == enabled {
+		return nil
+	}
+
+	err := c.updateAppConfig(func(config *models.AppConfig) { config.FollowDarkMode = enabled })
+	if enabled && c.IsScheduleEnabled() {
+		c.EnableSchedule(false)
+	}
+	c.stopDarkMode()
+	c.startDarkMode()
+	return err
+}
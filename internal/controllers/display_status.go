@@ -0,0 +1,105 @@
+package controllers
+
+import (
+	"errors"
+	"strings"
+	"sync"
+
+	"luznocturna/luz-nocturna/internal/system"
+)
+
+/**
+ * DisplayStatus - Resultado de la última aplicación en un display
+ *
+ * @struct {DisplayStatus}
+ * @property {string} Display - Nombre del display ("eDP-1", "HDMI-1"...)
+ * @property {bool} Included - false si está excluido o fuera del alcance elegido
+ * @property {bool} Pending - Aún no se ha aplicado nada en esta sesión
+ * @property {bool} Applied - La última aplicación o restauración llegó a este display
+ * @property {string} Backend - Método usado en la última aplicación ("xrandr", "gammastep"...)
+ * @property {string} Error - Motivo del fallo ("" si se aplicó)
+ */
+type DisplayStatus struct {
+	Display  string
+	Included bool
+	Pending  bool
+	Applied  bool
+	Backend  string
+	Error    string
+}
+
+/**
+ * displayStatusState - Resultado de la última petición de la cola de aplicación
+ *
+ * Lo escribe la goroutine de ApplyQueue y lo lee la interfaz.
+ *
+ * @struct {displayStatusState}
+ * @property {bool} done - Ya se ejecutó alguna petición
+ * @property {error} err - Error de la última petición (nil si tuvo éxito)
+ * @property {string} backend - Backend activo tras la última petición
+ */
+type displayStatusState struct {
+	mu      sync.Mutex
+	done    bool
+	err     error
+	backend string
+}
+
+// record guarda el resultado de una petición de la cola
+func (s *displayStatusState) record(err error, backend string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.done = true
+	s.err = err
+	s.backend = backend
+}
+
+/**
+ * GetDisplayStatus - Estado de cada display tras la última aplicación
+ *
+ * Con un fallo parcial se sabe en qué displays se aplicó; con cualquier
+ * otro error (sin backend, compositor que rechaza la gamma...) el fallo
+ * afecta a todos los displays incluidos.
+ *
+ * @returns {[]DisplayStatus} Un elemento por display detectado, en el mismo orden que GetDisplays
+ */
+func (c *NightLightController) GetDisplayStatus() []DisplayStatus {
+	c.displayStatus.mu.Lock()
+	done, err, backend := c.displayStatus.done, c.displayStatus.err, c.displayStatus.backend
+	c.displayStatus.mu.Unlock()
+
+	var partial *system.PartialApplyError
+	errors.As(err, &partial)
+
+	var statuses []DisplayStatus
+	for _, display := range c.GetDisplays() {
+		status := DisplayStatus{Display: display, Included: c.IsDisplayIncluded(display), Backend: backend}
+		switch {
+		case !status.Included:
+		case !done:
+			status.Pending = true
+		case err == nil:
+			status.Applied = true
+		case partial != nil:
+			status.Applied = containsDisplay(partial.Applied, display)
+			if !status.Applied {
+				status.Error = "no aceptó la gamma"
+			}
+		default:
+			// Los errores de Wayland ocupan varias líneas: basta con la primera
+			status.Error, _, _ = strings.Cut(err.Error(), "\n")
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// containsDisplay indica si display está en la lista
+func containsDisplay(displays []string, display string) bool {
+	for _, item := range displays {
+		if item == display {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,98 @@
+package controllers
+
+import (
+	"sync"
+)
+
+// EventType identifica el tipo de cambio de estado publicado por el controlador
+type EventType string
+
+// Tipos de eventos del bus interno
+const (
+	EventTemperatureChanged EventType = "temperature-changed" // Temperatura seleccionada (aún sin aplicar)
+	EventApplied            EventType = "applied"             // Filtro aplicado al display
+	EventReset              EventType = "reset"               // Gamma restaurada a valores normales
+	EventScheduleTransition EventType = "schedule-transition" // El programador aplicó una temperatura
+	EventPresetsChanged     EventType = "presets-changed"     // Lista de presets modificada
+	EventDisplaysChanged    EventType = "displays-changed"    // Displays incluidos en el filtro modificados
+	EventApplyFailed        EventType = "apply-failed"        // Un cambio automático no se pudo aplicar
+	EventMovieModeChanged   EventType = "movie-mode-changed"  // Modo película activado o terminado
+	EventAccurateChanged    EventType = "color-accurate"      // Modo de color fiel activado o terminado
+	EventSkipTonightChanged EventType = "skip-tonight"        // Noche de hoy omitida o reanudada
+	EventVacationChanged    EventType = "vacation"            // Período de vacaciones cambiado
+)
+
+// Event describe un cambio de estado del controlador
+type Event struct {
+	Type        EventType
+	Temperature float64 // Temperatura en Kelvin tras el cambio
+	Active      bool    // Si el filtro queda activo
+	Source      string  // Origen del cambio: "manual", "preset", "scheduler", "dbus"...
+	Err         error   // Motivo del fallo (solo en EventApplyFailed)
+}
+
+/**
+ * EventBus - Bus de eventos interno del controlador
+ *
+ * La vista, la bandeja, las notificaciones y las capas de D-Bus/IPC se
+ * suscriben aquí en lugar de recibir actualizaciones ad hoc, de modo que
+ * todas muestran siempre el mismo estado.
+ *
+ * Los suscriptores se invocan de forma síncrona en la goroutine que publica;
+ * quien toque la UI debe pasar el trabajo al hilo principal de Fyne.
+ */
+type EventBus struct {
+	mu          sync.RWMutex
+	subscribers map[int]func(Event)
+	nextID      int
+}
+
+/**
+ * NewEventBus - Constructor del bus de eventos
+ *
+ * @returns {*EventBus} Bus sin suscriptores
+ */
+func NewEventBus() *EventBus {
+	return &EventBus{subscribers: make(map[int]func(Event))}
+}
+
+/**
+ * Subscribe - Registra un suscriptor para todos los eventos
+ *
+ * @param {func(Event)} handler - Función a invocar con cada evento
+ * @returns {func()} Función que cancela la suscripción
+ * @example
+ *   unsubscribe := bus.Subscribe(func(e Event) { fmt.Println(e.Type) })
+ *   defer unsubscribe()
+ */
+func (b *EventBus) Subscribe(handler func(Event)) func() {
+	b.mu.Lock()
+	id := b.nextID
+	b.nextID++
+	b.subscribers[id] = handler
+	b.mu.Unlock()
+
+	return func() {
+		b.mu.Lock()
+		delete(b.subscribers, id)
+		b.mu.Unlock()
+	}
+}
+
+/**
+ * Publish - Envía un evento a todos los suscriptores
+ *
+ * @param {Event} event - Evento a publicar
+ */
+func (b *EventBus) Publish(event Event) {
+	b.mu.RLock()
+	handlers := make([]func(Event), 0, len(b.subscribers))
+	for _, handler := range b.subscribers {
+		handlers = append(handlers, handler)
+	}
+	b.mu.RUnlock()
+
+	for _, handler := range handlers {
+		handler(event)
+	}
+}
@@ -0,0 +1,26 @@
+package controllers
+
+import "time"
+
+/**
+ * fade - Recorre los pasos intermedios de un fundido
+ *
+ * Llama a step con el progreso de cada paso (1/steps, 2/steps...) sin
+ * llegar al final, que aplica quien llama, y espera delay entre uno y
+ * otro. Se detiene en cuanto step devuelve false.
+ *
+ * @param {int} steps - Número de tramos del fundido
+ * @param {time.Duration} delay - Espera entre pasos (0 si el ritmo lo marca la cola)
+ * @param {func(float64) bool} step - Aplica un paso; false si el fundido quedó obsoleto
+ * @returns {bool} true si se recorrieron todos los pasos
+ * @private
+ */
+func fade(steps int, delay time.Duration, step func(progress float64) bool) bool {
+	for i := 1; i < steps; i++ {
+		if !step(float64(i) / float64(steps)) {
+			return false
+		}
+		time.Sleep(delay)
+	}
+	return true
+}
@@ -0,0 +1,114 @@
+package controllers
+
+import (
+	"sync"
+
+	"luznocturna/luz-nocturna/internal/logging"
+	"luznocturna/luz-nocturna/internal/models"
+	"luznocturna/luz-nocturna/internal/system"
+)
+
+/**
+ * gamingState - Sesión de juego en marcha y su seguimiento
+ *
+ * Cambiar la gamma bajo gamescope o Steam Big Picture produce bandas y
+ * parpadeos en algunos juegos. Como con el bloqueo de la sesión,
+ * runApply convierte las peticiones en un reset mientras dura y al
+ * terminar basta con reaplicar el último estado.
+ *
+ * @struct {gamingState}
+ * @property {string} session - Sesión de juego detectada ("" si ninguna)
+ * @property {*system.GamingWatcher} watcher - Seguimiento de procesos (nil si la opción está desactivada)
+ */
+type gamingState struct {
+	mu      sync.Mutex
+	session string
+	watcher *system.GamingWatcher
+}
+
+// current devuelve la sesión de juego en marcha ("" si ninguna)
+func (s *gamingState) current() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.session
+}
+
+// set cambia la sesión de juego e indica si es distinta de la anterior
+func (s *gamingState) set(session string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	changed := s.session != session
+	s.session = session
+	return changed
+}
+
+// startGaming empieza a buscar sesiones de juego si la opción está activada
+func (c *NightLightController) startGaming() {
+	if !c.IsSuspendForGames() {
+		return
+	}
+
+	c.gaming.mu.Lock()
+	defer c.gaming.mu.Unlock()
+	if c.gaming.watcher != nil {
+		return
+	}
+	watcher, err := system.StartGamingWatcher(c.onGamingChanged)
+	if err != nil {
+		logging.Printf("⚠️  El filtro no se suspenderá durante los juegos: %v\n", err)
+		return
+	}
+	c.gaming.watcher = watcher
+}
+
+// stopGaming detiene el seguimiento; indica si había una sesión de juego en marcha
+func (c *NightLightController) stopGaming() bool {
+	c.gaming.mu.Lock()
+	if c.gaming.watcher != nil {
+		c.gaming.watcher.Stop()
+		c.gaming.watcher = nil
+	}
+	c.gaming.mu.Unlock()
+
+	return c.gaming.set("")
+}
+
+/**
+ * onGamingChanged - Retira el filtro al empezar una sesión de juego y lo reaplica al terminar
+ *
+ * @param {string} session - Sesión en marcha (system.GamingGamescope, system.GamingBigPicture o "")
+ * @callback - Seguimiento de las sesiones de juego
+ */
+func (c *NightLightController) onGamingChanged(session string) {
+	if !c.gaming.set(session) {
+		return
+	}
+
+	if session != "" {
+		logging.Printf("🎮 %s en marcha: filtro suspendido\n", session)
+	} else {
+		logging.Println("🎮 Fin de la sesión de juego: reaplicando el filtro")
+	}
+	c.reapplyLastState()
+}
+
+// GetGamingSession devuelve la sesión de juego que tiene el filtro suspendido ("" si ninguna)
+func (c *NightLightController) GetGamingSession() string {
+	return c.gaming.current()
+}
+
+// IsSuspendForGames indica si el filtro se suspende con gamescope o Steam Big Picture
+func (c *NightLightController) IsSuspendForGames() bool {
+	return c.currentAppConfig().SuspendForGames
+}
+
+// SetSuspendForGames activa o desactiva la suspensión del filtro durante los juegos
+func (c *NightLightController) SetSuspendForGames(enabled bool) error {
+	err := c.updateAppConfig(func(config *models.AppConfig) { config.SuspendForGames = enabled })
+	if enabled {
+		c.startGaming()
+	} else if c.stopGaming() {
+		c.reapplyLastState()
+	}
+	return err
+}
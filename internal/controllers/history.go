@@ -0,0 +1,108 @@
+package controllers
+
+import (
+	"errors"
+	"sync"
+)
+
+// historyLimit es el número máximo de estados que se pueden deshacer
+const historyLimit = 20
+
+// Errores de deshacer/rehacer cuando la pila correspondiente está vacía
+var (
+	errNothingToUndo = errors.New("no hay cambios que deshacer")
+	errNothingToRedo = errors.New("no hay cambios que rehacer")
+)
+
+// appliedState es una instantánea de lo que se aplicó al display
+type appliedState struct {
+	Temperature float64
+	Brightness  float64
+	Contrast    float64
+	Active      bool // false = gamma restaurada a valores normales
+}
+
+/**
+ * stateHistory - Historial de estados aplicados para deshacer y rehacer
+ *
+ * Guarda los estados anteriores a cada aplicación manual (ventana,
+ * presets, bandeja, D-Bus). Los cambios del programador no se registran,
+ * para que deshacer siempre revierta una acción del usuario.
+ *
+ * @struct {stateHistory}
+ * @property {[]appliedState} undo - Estados anteriores, el último es el más reciente
+ * @property {[]appliedState} redo - Estados deshechos que aún se pueden rehacer
+ */
+type stateHistory struct {
+	mu   sync.Mutex
+	undo []appliedState
+	redo []appliedState
+}
+
+// record guarda el estado previo a un cambio nuevo y descarta lo que se podía rehacer
+func (h *stateHistory) record(previous appliedState) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.undo = pushState(h.undo, previous)
+	h.redo = nil
+}
+
+// popUndo saca el estado más reciente que se puede deshacer
+func (h *stateHistory) popUndo() (appliedState, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return popState(&h.undo)
+}
+
+// popRedo saca el último estado deshecho
+func (h *stateHistory) popRedo() (appliedState, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return popState(&h.redo)
+}
+
+// pushUndo y pushRedo devuelven un estado a su pila (tras deshacer/rehacer o si falló)
+func (h *stateHistory) pushUndo(state appliedState) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.undo = pushState(h.undo, state)
+}
+
+func (h *stateHistory) pushRedo(state appliedState) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.redo = pushState(h.redo, state)
+}
+
+// canUndo y canRedo indican si las pilas tienen estados
+func (h *stateHistory) canUndo() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.undo) > 0
+}
+
+func (h *stateHistory) canRedo() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.redo) > 0
+}
+
+// pushState añade un estado descartando el más antiguo si se supera historyLimit
+func pushState(stack []appliedState, state appliedState) []appliedState {
+	stack = append(stack, state)
+	if len(stack) > historyLimit {
+		stack = stack[len(stack)-historyLimit:]
+	}
+	return stack
+}
+
+// popState saca el último estado de la pila
+func popState(stack *[]appliedState) (appliedState, bool) {
+	if len(*stack) == 0 {
+		return appliedState{}, false
+	}
+	state := (*stack)[len(*stack)-1]
+	*stack = (*stack)[:len(*stack)-1]
+	return state, true
+}
@@ -0,0 +1,128 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"luznocturna/luz-nocturna/internal/logging"
+	"luznocturna/luz-nocturna/internal/models"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// hookTimeout es el tiempo máximo que puede durar un hook antes de terminarlo
+const hookTimeout = 30 * time.Second
+
+// Fases de la programación que reciben los hooks en LUZ_NOCTURNA_PHASE
+const (
+	phaseNight = "night"
+	phaseDay   = "day"
+)
+
+/**
+ * hookRunner - Ejecuta los hooks del usuario a partir de los eventos del controlador
+ *
+ * on_apply y on_reset siguen a EventApplied y EventReset. Los hooks de
+ * inicio de la noche y del día se disparan cuando una transición del
+ * programador cruza el punto medio entre la temperatura diurna y la
+ * nocturna, no en cada paso de la transición.
+ *
+ * @struct {hookRunner}
+ * @property {string} phase - Última fase vista ("night", "day" o "" al arrancar)
+ */
+type hookRunner struct {
+	mu    sync.Mutex
+	phase string
+}
+
+// handleHooks es el suscriptor del bus que decide qué hooks ejecutar
+func (c *NightLightController) handleHooks(event Event) {
+	switch event.Type {
+	case EventApplied:
+		c.runHook(models.HookApply, event, "")
+	case EventReset:
+		c.runHook(models.HookReset, event, "")
+	case EventScheduleTransition:
+		phase := schedulePhase(c.GetScheduleConfig(), event.Temperature)
+		if !c.hooks.enter(phase) {
+			return
+		}
+		if phase == phaseNight {
+			c.runHook(models.HookNightStart, event, phase)
+		} else {
+			c.runHook(models.HookDayStart, event, phase)
+		}
+	}
+}
+
+// enter registra la fase actual e indica si cambió desde el último evento
+func (h *hookRunner) enter(phase string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	changed := h.phase != phase
+	h.phase = phase
+	return changed
+}
+
+// schedulePhase indica si una temperatura de la programación ya es nocturna (más cerca de NightTemp que de DayTemp)
+func schedulePhase(schedule models.ScheduleConfig, temperature float64) string {
+	midpoint := (schedule.NightTemp + schedule.DayTemp) / 2
+	if (schedule.NightTemp <= schedule.DayTemp) == (temperature <= midpoint) {
+		return phaseNight
+	}
+	return phaseDay
+}
+
+/**
+ * runHook - Ejecuta el comando de un hook en segundo plano
+ *
+ * El comando recibe el estado en variables de entorno:
+ * LUZ_NOCTURNA_HOOK, LUZ_NOCTURNA_TEMPERATURE, LUZ_NOCTURNA_BRIGHTNESS,
+ * LUZ_NOCTURNA_ACTIVE ("1" o "0"), LUZ_NOCTURNA_SOURCE y, en los hooks de
+ * la programación, LUZ_NOCTURNA_PHASE ("night" o "day").
+ *
+ * @param {string} name - Hook a ejecutar (models.HookApply...)
+ * @param {Event} event - Evento que lo disparó
+ * @param {string} phase - Fase de la programación ("" si no aplica)
+ * @private
+ */
+func (c *NightLightController) runHook(name string, event Event, phase string) {
+	hooks := c.currentAppConfig().Hooks
+	command := strings.TrimSpace(hooks.Command(name))
+	if command == "" {
+		return
+	}
+
+	active := "0"
+	if event.Active {
+		active = "1"
+	}
+	env := append(os.Environ(),
+		"LUZ_NOCTURNA_HOOK="+name,
+		fmt.Sprintf("LUZ_NOCTURNA_TEMPERATURE=%.0f", event.Temperature),
+		fmt.Sprintf("LUZ_NOCTURNA_BRIGHTNESS=%.2f", c.GetConfig().Brightness),
+		"LUZ_NOCTURNA_ACTIVE="+active,
+		"LUZ_NOCTURNA_SOURCE="+event.Source,
+	)
+	if phase != "" {
+		env = append(env, "LUZ_NOCTURNA_PHASE="+phase)
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), hookTimeout)
+		defer cancel()
+
+		cmd := exec.CommandContext(ctx, "sh", "-c", command)
+		cmd.Env = env
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			logging.WithFields(logging.Fields{"hook": name, "error": err}).
+				Printf("⚠️  El hook %s falló: %v %s\n", name, err, strings.TrimSpace(string(output)))
+			return
+		}
+		logging.Printf("🪝 Hook %s ejecutado\n", name)
+	}()
+}
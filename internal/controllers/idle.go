@@ -0,0 +1,115 @@
+package controllers
+
+import (
+	"sync"
+
+	"luznocturna/luz-nocturna/internal/logging"
+	"luznocturna/luz-nocturna/internal/models"
+	"luznocturna/luz-nocturna/internal/system"
+)
+
+/**
+ * idleState - Inactividad de la sesión y su seguimiento
+ *
+ * Mientras nadie usa el equipo el programador y el vigilante de gamma no
+ * aplican nada (y por tanto no hay avisos de la programación). Al volver
+ * se aplica enseguida el estado que corresponde a la hora.
+ *
+ * @struct {idleState}
+ * @property {bool} idle - La sesión está inactiva según logind
+ * @property {*system.IdleWatcher} watcher - Seguimiento de IdleHint (nil si la opción está desactivada)
+ */
+type idleState struct {
+	mu      sync.Mutex
+	idle    bool
+	watcher *system.IdleWatcher
+}
+
+// isIdle indica si la sesión está inactiva
+func (s *idleState) isIdle() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.idle
+}
+
+// set cambia el estado de inactividad e indica si es distinto del anterior
+func (s *idleState) set(idle bool) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	changed := s.idle != idle
+	s.idle = idle
+	return changed
+}
+
+// startIdleWatcher empieza a seguir la inactividad si la opción está activada
+func (c *NightLightController) startIdleWatcher() {
+	if !c.IsPauseWhileIdle() {
+		return
+	}
+
+	c.idle.mu.Lock()
+	defer c.idle.mu.Unlock()
+	if c.idle.watcher != nil {
+		return
+	}
+	watcher, err := system.StartIdleWatcher(c.onIdleChanged)
+	if err != nil {
+		logging.Printf("⚠️  La programación no se pausará con el equipo inactivo: %v\n", err)
+		return
+	}
+	c.idle.watcher = watcher
+}
+
+// stopIdleWatcher detiene el seguimiento y reanuda el programador si estaba en pausa
+func (c *NightLightController) stopIdleWatcher() {
+	c.idle.mu.Lock()
+	if c.idle.watcher != nil {
+		c.idle.watcher.Stop()
+		c.idle.watcher = nil
+	}
+	c.idle.mu.Unlock()
+
+	if c.idle.set(false) {
+		c.scheduler.SetIdle(false)
+	}
+}
+
+/**
+ * onIdleChanged - Pausa o reanuda los cambios automáticos según la inactividad
+ *
+ * @param {bool} idle - true si la sesión acaba de quedar inactiva
+ * @callback - Seguimiento de la inactividad
+ */
+func (c *NightLightController) onIdleChanged(idle bool) {
+	if !c.idle.set(idle) {
+		return
+	}
+
+	if idle {
+		logging.Println("💤 Equipo inactivo: cambios automáticos en pausa")
+		c.scheduler.SetIdle(true)
+		return
+	}
+
+	logging.Println("👋 Actividad de nuevo: aplicando el estado actual")
+	c.scheduler.SetIdle(false)
+	if !c.scheduler.IsRunning() {
+		c.reapplyLastState() // Por si otro programa cambió la gamma mientras tanto
+	}
+}
+
+// IsPauseWhileIdle indica si los cambios automáticos se pausan con el equipo inactivo
+func (c *NightLightController) IsPauseWhileIdle() bool {
+	return c.currentAppConfig().PauseWhileIdle
+}
+
+// SetPauseWhileIdle activa o desactiva la pausa de los cambios automáticos con el equipo inactivo
+func (c *NightLightController) SetPauseWhileIdle(enabled bool) error {
+	err := c.updateAppConfig(func(config *models.AppConfig) { config.PauseWhileIdle = enabled })
+	if enabled {
+		c.startIdleWatcher()
+	} else {
+		c.stopIdleWatcher()
+	}
+	return err
+}
@@ -0,0 +1,154 @@
+package controllers
+
+import (
+	"errors"
+	"strings"
+	"sync"
+
+	"luznocturna/luz-nocturna/internal/logging"
+	"luznocturna/luz-nocturna/internal/models"
+	"luznocturna/luz-nocturna/internal/system"
+)
+
+/**
+ * layoutState - Disposición de monitores actual y su seguimiento
+ *
+ * A diferencia de las reglas, un perfil de disposición cambia el estado
+ * del usuario: selecciona el preset como si se hubiera elegido a mano,
+ * así que se guarda y aparece en el historial.
+ *
+ * @struct {layoutState}
+ * @property {[]string} current - Monitores conectados en la última lectura (nil antes de la primera)
+ * @property {*system.LayoutWatcher} watcher - Seguimiento de los monitores (nil si está detenido)
+ * @property {sync.Mutex} changing - Atiende los cambios de uno en uno (al reiniciar el seguimiento pueden solaparse)
+ */
+type layoutState struct {
+	mu       sync.Mutex
+	current  []string
+	watcher  *system.LayoutWatcher
+	changing sync.Mutex
+}
+
+// setCurrent guarda la disposición leída e indica si había una anterior
+func (s *layoutState) setCurrent(layout []string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	known := s.current != nil
+	s.current = layout
+	return known
+}
+
+// startLayoutProfiles empieza a seguir los monitores conectados; sin perfiles solo reaplica al conectar uno
+func (c *NightLightController) startLayoutProfiles() {
+	c.layout.mu.Lock()
+	defer c.layout.mu.Unlock()
+	if c.layout.watcher != nil {
+		return
+	}
+	watcher, err := system.StartLayoutWatcher(c.onLayoutChanged)
+	if err != nil {
+		logging.Printf("⚠️  Los perfiles por disposición de monitores no funcionarán: %v\n", err)
+		return
+	}
+	c.layout.watcher = watcher
+}
+
+// stopLayoutProfiles detiene el seguimiento de los monitores
+func (c *NightLightController) stopLayoutProfiles() {
+	c.layout.mu.Lock()
+	defer c.layout.mu.Unlock()
+	if c.layout.watcher != nil {
+		c.layout.watcher.Stop()
+		c.layout.watcher = nil
+	}
+	c.layout.current = nil
+}
+
+/**
+ * onLayoutChanged - Cambia al preset de la nueva disposición de monitores
+ *
+ * Al conectar o desconectar un monitor vuelve a detectar los displays y
+ * reaplica el filtro para que llegue también al monitor nuevo. Si la
+ * disposición tiene perfil, selecciona su preset y lo aplica si el
+ * filtro está activo. Las llamadas se atienden de una en una.
+ *
+ * @param {[]string} layout - Monitores conectados
+ * @callback - Seguimiento de la disposición de monitores
+ */
+func (c *NightLightController) onLayoutChanged(layout []string) {
+	c.layout.changing.Lock()
+	defer c.layout.changing.Unlock()
+
+	if c.layout.setCurrent(layout) {
+		logging.Printf("🖥️  Monitores conectados: %s\n", strings.Join(layout, ", "))
+		c.RefreshDisplays()
+		c.publish(EventDisplaysChanged, "layout")
+	}
+
+	profile, found := models.FindLayoutProfile(c.GetLayoutProfiles(), layout)
+	preset, known := c.findPreset(profile.Preset)
+	if !found || !known {
+		c.reapplyLastState()
+		return
+	}
+
+	logging.Printf("🖥️  Disposición \"%s\": preset %s\n", profile.Name, profile.Preset)
+	c.selectPreset(preset, "layout")
+	if !c.isActive() {
+		return
+	}
+	if err := c.applyNightLight("layout"); err != nil && !errors.Is(err, system.ErrPartialApply) {
+		logging.Printf("⚠️  No se pudo aplicar el preset de la disposición: %v\n", err)
+		c.events.Publish(Event{Type: EventApplyFailed, Temperature: preset.Temperature, Source: "layout", Err: err})
+	}
+}
+
+// findPreset busca el preset con ese nombre (sin distinguir mayúsculas)
+func (c *NightLightController) findPreset(name string) (models.Preset, bool) {
+	for _, preset := range c.GetPresets() {
+		if strings.EqualFold(preset.Name, strings.TrimSpace(name)) {
+			return preset, true
+		}
+	}
+	return models.Preset{}, false
+}
+
+// GetMonitorLayout devuelve los monitores conectados según su EDID
+func (c *NightLightController) GetMonitorLayout() []string {
+	c.layout.mu.Lock()
+	current := c.layout.current
+	c.layout.mu.Unlock()
+
+	if current != nil {
+		return append([]string(nil), current...)
+	}
+	return system.ReadMonitorLayout()
+}
+
+// GetLayoutProfiles devuelve los perfiles por disposición de monitores configurados
+func (c *NightLightController) GetLayoutProfiles() []models.LayoutProfile {
+	return append([]models.LayoutProfile(nil), c.currentAppConfig().LayoutProfiles...)
+}
+
+/**
+ * SetLayoutProfiles - Reemplaza los perfiles por disposición y reinicia el seguimiento
+ *
+ * Al reiniciar, la disposición actual se vuelve a evaluar: si tiene un
+ * perfil, su preset se selecciona en el momento.
+ *
+ * @param {[]models.LayoutProfile} profiles - Nuevos perfiles, en orden de prioridad
+ * @returns {error} Error si algún perfil no es válido o no se pudo guardar
+ */
+func (c *NightLightController) SetLayoutProfiles(profiles []models.LayoutProfile) error {
+	presets := c.GetPresets()
+	for _, profile := range profiles {
+		if err := profile.Validate(presets); err != nil {
+			return err
+		}
+	}
+
+	err := c.updateAppConfig(func(config *models.AppConfig) { config.LayoutProfiles = profiles })
+	c.stopLayoutProfiles()
+	c.startLayoutProfiles()
+	return err
+}
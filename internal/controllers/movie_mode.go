@@ -0,0 +1,117 @@
+package controllers
+
+import (
+	"luznocturna/luz-nocturna/internal/logging"
+	"sync"
+	"time"
+)
+
+/**
+ * timedPauseState - Pausa temporal del filtro (modo película, color fiel)
+ *
+ * A diferencia de un reset, la pausa no cambia la temperatura elegida,
+ * el historial ni el estado activo: runApply convierte las peticiones en
+ * un reset mientras dura y, al terminar, basta con reaplicar el último
+ * estado (que el programador pudo actualizar entre medias).
+ *
+ * @struct {timedPauseState}
+ * @property {time.Time} until - Hora a la que termina (cero si no está activa)
+ * @property {*time.Timer} timer - Temporizador que la termina automáticamente
+ */
+type timedPauseState struct {
+	mu    sync.Mutex
+	until time.Time
+	timer *time.Timer
+}
+
+// isActive indica si la pausa está en curso
+func (s *timedPauseState) isActive() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return !s.until.IsZero()
+}
+
+// end devuelve la hora a la que termina la pausa (cero si no está activa)
+func (s *timedPauseState) end() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.until
+}
+
+// begin empieza (o reinicia) la pausa; onEnd se llama al agotarse el tiempo
+func (s *timedPauseState) begin(duration time.Duration, onEnd func()) {
+	until := time.Now().Add(duration)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.timer != nil {
+		s.timer.Stop()
+	}
+	s.until = until
+	s.timer = time.AfterFunc(duration, func() {
+		// Un reinicio posterior deja este temporizador obsoleto
+		if s.end().Equal(until) {
+			onEnd()
+		}
+	})
+}
+
+// cancel detiene el temporizador; indica si la pausa estaba activa
+func (s *timedPauseState) cancel() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+	active := !s.until.IsZero()
+	s.until = time.Time{}
+	return active
+}
+
+/**
+ * StartMovieMode - Retira el filtro durante la duración configurada
+ *
+ * Si ya estaba activo vuelve a empezar la cuenta. Al terminar el tiempo
+ * (o con StopMovieMode) se reaplica lo que estaba activo.
+ */
+func (c *NightLightController) StartMovieMode() {
+	config := c.currentAppConfig()
+	duration := config.GetMovieDuration()
+	c.movieMode.begin(duration, c.StopMovieMode)
+
+	logging.Printf("🎬 Modo película durante %v: gamma normal\n", duration)
+	c.reapplyLastState()
+	c.publish(EventMovieModeChanged, "movie")
+}
+
+// StopMovieMode termina el modo película y reaplica el estado anterior
+func (c *NightLightController) StopMovieMode() {
+	if !c.stopMovieMode() {
+		return
+	}
+	logging.Println("🎬 Fin del modo película: reaplicando el filtro")
+	c.reapplyLastState()
+	c.publish(EventMovieModeChanged, "movie")
+}
+
+// stopMovieMode cancela el temporizador; indica si el modo película estaba activo
+func (c *NightLightController) stopMovieMode() bool {
+	return c.movieMode.cancel()
+}
+
+// ToggleMovieMode activa el modo película o lo termina si ya estaba activo
+func (c *NightLightController) ToggleMovieMode() {
+	if c.movieMode.isActive() {
+		c.StopMovieMode()
+	} else {
+		c.StartMovieMode()
+	}
+}
+
+// GetMovieMode indica si el modo película está activo y hasta qué hora
+func (c *NightLightController) GetMovieMode() (bool, time.Time) {
+	until := c.movieMode.end()
+	return !until.IsZero(), until
+}
@@ -1,12 +1,22 @@
 package controllers
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"luznocturna/luz-nocturna/internal/diagnostics"
+	"luznocturna/luz-nocturna/internal/logging"
 	"luznocturna/luz-nocturna/internal/models"
 	"luznocturna/luz-nocturna/internal/system"
+	"math"
+	"strings"
+	"sync"
 	"time"
 )
 
+// emergencyStopTimeout es lo que EmergencyRestore espera a los observadores antes de dejar salir
+const emergencyStopTimeout = 2 * time.Second
+
 /**
  * NightLightController - Controlador principal de la aplicación
  *
@@ -14,16 +24,73 @@ import (
  * del monitor. Coordina entre los modelos de configuración y el sistema
  * de gestión de gamma del display.
  *
+ * La ventana, la bandeja, D-Bus, el programador y los vigilantes lo usan
+ * desde goroutines distintas: config, appConfig, lastApplied y loadErr
+ * solo se tocan con mu tomado, y nunca se mantiene mientras se espera a
+ * la cola de gamma o se publican eventos.
+ *
  * @struct {NightLightController}
+ * @property {sync.Mutex} mu - Protege config, appConfig, lastApplied y loadErr
  * @property {*models.NightLightConfig} config - Configuración actual de luz nocturna
  * @property {*models.AppConfig} appConfig - Configuración persistente de la aplicación
- * @property {*system.GammaManager} gammaManager - Manejador de gamma del sistema
+ * @property {GammaBackend} gammaManager - Manejador de gamma del sistema (o uno simulado en pruebas)
+ * @property {*EventBus} events - Bus de eventos de cambios de estado
+ * @property {*ApplyQueue} applyQueue - Cola que serializa las llamadas al backend de gamma
+ * @property {hookRunner} hooks - Estado de los hooks del usuario (fase de la programación)
+ * @property {appRuleState} appRules - Regla por aplicación en vigor y seguimiento del foco
+ * @property {workspaceRuleState} workspaceRules - Regla por espacio de trabajo en vigor (Sway/i3)
+ * @property {sessionLockState} sessionLock - Bloqueo de la sesión (filtro retirado mientras dura)
+ * @property {powerState} power - Perfil de batería en vigor (UPower)
+ * @property {weatherState} weather - Nubosidad que ajusta la temperatura diurna programada
+ * @property {ambientState} ambient - Luz de la habitación estimada con la webcam
+ * @property {timedPauseState} movieMode - Pausa temporal del filtro (modo película)
+ * @property {timedPauseState} colorAccurate - Pausa temporal para trabajo con color fiel
+ * @property {scheduleFadeState} scheduleFade - Fundido de los cambios programados que llegan a mitad de período
+ * @property {layoutState} layout - Monitores conectados (perfiles por disposición)
+ * @property {darkModeState} darkMode - Tema claro/oscuro del escritorio como disparador de la noche
+ * @property {idleState} idle - Inactividad de la sesión (cambios automáticos en pausa)
+ * @property {gamingState} gaming - Gamescope o Steam Big Picture en marcha (filtro suspendido)
+ * @property {calibrationState} calibration - Ajuste fino en prueba del asistente de calibración
  */
 type NightLightController struct {
-	config       *models.NightLightConfig
-	appConfig    *models.AppConfig
-	gammaManager *system.GammaManager
-	scheduler    *models.Scheduler
+	mu             sync.Mutex
+	config         *models.NightLightConfig
+	appConfig      *models.AppConfig
+	gammaManager   GammaBackend
+	scheduler      *models.Scheduler
+	events         *EventBus
+	applyQueue     *ApplyQueue
+	resetOnExit    bool
+	shutdownOnce   sync.Once
+	loadErr        error // Error al cargar la configuración (p. ej. JSON dañado)
+	history        stateHistory
+	lastApplied    appliedState // Último estado aplicado al display
+	activity       *models.ActivityLog
+	watchdog       *gammaWatchdog
+	hooks          hookRunner
+	appRules       appRuleState
+	workspaceRules workspaceRuleState
+	sessionLock    sessionLockState
+	power          powerState
+	weather        weatherState
+	ambient        ambientState
+	movieMode      timedPauseState
+	colorAccurate  timedPauseState
+	scheduleFade   scheduleFadeState
+	displayStatus  displayStatusState
+	layout         layoutState
+	darkMode       darkModeState
+	idle           idleState
+	gaming         gamingState
+	calibration    calibrationState
+}
+
+// ControllerOptions agrupa las opciones de arranque del controlador
+type ControllerOptions struct {
+	DryRun      bool         // Registrar los comandos de gamma sin tocar el display
+	ResetOnExit bool         // Restaurar la gamma al salir aunque la configuración no lo pida
+	Backend     GammaBackend // Backend de gamma a usar; nil para detectar el del sistema
+	Protocol    string       // Protocolo forzado ("x11" o "wayland"); "" para detectarlo
 }
 
 /**
@@ -40,88 +107,408 @@ type NightLightController struct {
  *   controller.ApplyNightLight()
  */
 func NewNightLightController() *NightLightController {
+	return NewNightLightControllerWithOptions(ControllerOptions{})
+}
+
+/**
+ * NewNightLightControllerWithOptions - Constructor con opciones de arranque
+ *
+ * @param {ControllerOptions} opts - Opciones de arranque (dry-run, etc.)
+ * @returns {*NightLightController} Nueva instancia del controlador
+ */
+func NewNightLightControllerWithOptions(opts ControllerOptions) *NightLightController {
+	backend := opts.Backend
+	if backend == nil {
+		backend = system.NewGammaManagerWithOptions(system.GammaOptions{DryRun: opts.DryRun, Protocol: opts.Protocol})
+	}
+
 	controller := &NightLightController{
 		config:       models.NewNightLightConfig(),
 		appConfig:    models.NewAppConfig(),
-		gammaManager: system.NewGammaManager(),
+		gammaManager: backend,
+		events:       NewEventBus(),
+		resetOnExit:  opts.ResetOnExit,
+		lastApplied:  appliedState{Temperature: 6500, Brightness: 1.0}, // Gamma normal al iniciar
 	}
+	controller.applyQueue = newApplyQueue(controller.runApply, controller.EmergencyRestore)
+
+	// Registrar cada cambio aplicado en el historial de actividad
+	controller.activity = models.NewActivityLog(models.GetActivityLogPath())
+	controller.events.Subscribe(controller.logActivity)
+
+	// Ejecutar los scripts del usuario (on_apply, on_night_start...)
+	controller.events.Subscribe(controller.handleHooks)
 
 	// Cargar configuración guardada
 	if err := controller.appConfig.Load(); err == nil {
 		controller.config.SetTemperature(controller.appConfig.LastTemperature)
+	} else {
+		logging.Printf("⚠️  No se pudo cargar la configuración: %v\n", err)
+		controller.loadErr = err
+	}
+	// LUZ_NOCTURNA_TEMP cambia la temperatura inicial sin tocar config.json
+	if temp, ok := models.TemperatureOverride(); ok {
+		controller.config.SetTemperature(temp)
 	}
+	controller.syncExcludedDisplays()
 
 	// Inicializar programador con callback para aplicar temperatura
-	controller.scheduler = models.NewScheduler(controller.appConfig, func(temp float64) error {
-		controller.config.SetTemperature(temp)
-		return controller.gammaManager.ApplyTemperature(temp)
+	controller.scheduler = models.NewScheduler(controller.appConfig, func(temp, brightness float64) error {
+		if fade, generation := controller.scheduleFade.take(time.Now()); fade && controller.fadeToSchedule(temp, brightness, generation) {
+			return nil // El fundido aplica el estado programado al terminar
+		}
+		return controller.applySchedule(temp, brightness)
+	})
+	controller.scheduler.SetConfigLock(&controller.mu)
+	controller.scheduler.SetPanicHandler(controller.EmergencyRestore)
+	controller.scheduler.SetTransitionTickCheck(func() bool {
+		return system.SupportsFineSteps(controller.gammaManager.GetBackend())
 	})
 
+	// Ajuste de la temperatura diurna por nubosidad (lo recogen los tics del programador)
+	controller.startWeather()
+
 	// Iniciar programación automática si está habilitada
-	if controller.appConfig.ScheduleEnabled {
+	if controller.IsScheduleEnabled() {
 		controller.scheduler.Start()
 	}
 
+	// Alternativa a la programación: noche con el tema oscuro del escritorio
+	controller.startDarkMode()
+
+	// Vigilante opcional que reaplica la gamma si otro programa la restaura
+	controller.watchdog = newGammaWatchdog(controller.checkGamma)
+	if watchdog := controller.GetWatchdog(); watchdog.Enabled {
+		controller.watchdog.Start(watchdog.GetInterval())
+	}
+
+	// Reglas por aplicación: temperatura especial según la ventana enfocada
+	controller.startAppRules()
+	controller.startWorkspaceRules()
+
+	// Preset según los monitores conectados (en casa, en la oficina...)
+	controller.startLayoutProfiles()
+
+	// Gamma normal en la pantalla de bloqueo si el usuario lo eligió
+	controller.startSessionLock()
+
+	// Sin cambios automáticos mientras nadie usa el equipo
+	controller.startIdleWatcher()
+
+	// Sin filtro mientras gamescope o Steam Big Picture están en marcha
+	controller.startGaming()
+
+	// Perfil más cálido y tenue con batería
+	controller.startPowerWatcher()
+
+	// Ajuste adaptativo según la luz de la habitación (webcam, opcional)
+	controller.startAmbient()
+
 	return controller
 }
 
-// GetConfig devuelve la configuración actual
+// runApply ejecuta una petición de la cola contra el backend de gamma.
+// Solo la llama la goroutine de ApplyQueue, nunca en paralelo.
+func (c *NightLightController) runApply(request applyRequest) error {
+	request = c.effectiveRequest(request)
+	var err error
+	if request.reset {
+		err = c.gammaManager.Reset()
+	} else {
+		if backend, ok := c.gammaManager.(contrastBackend); ok {
+			backend.SetContrast(c.GetConfig().Contrast)
+		}
+		if backend, ok := c.gammaManager.(desaturationBackend); ok {
+			backend.SetDesaturation(c.sleepDesaturation())
+		}
+		if backend, ok := c.gammaManager.(channelTrimBackend); ok {
+			backend.SetChannelTrims(c.channelTrimFactors())
+		}
+		err = c.gammaManager.ApplyTemperatureWithBrightness(request.temperature, request.brightness)
+	}
+	c.displayStatus.record(err, c.gammaManager.GetBackend())
+	return err
+}
+
+// GetConfig devuelve una copia de la configuración actual
 func (c *NightLightController) GetConfig() *models.NightLightConfig {
-	return c.config
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	config := *c.config
+	return &config
 }
 
-// GetAppConfig devuelve la configuración de la aplicación
+// GetAppConfig devuelve la configuración de la aplicación. Modificarla sin
+// pasar por el controlador solo es seguro antes de usarlo (p. ej. en pruebas)
 func (c *NightLightController) GetAppConfig() *models.AppConfig {
 	return c.appConfig
 }
 
+// currentAppConfig devuelve una copia de la configuración de la aplicación para leerla sin cerrojo
+func (c *NightLightController) currentAppConfig() models.AppConfig {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return *c.appConfig
+}
+
+// updateAppConfig modifica la configuración de la aplicación con el cerrojo tomado y la guarda.
+// Las listas y mapas se reemplazan en lugar de modificarse: las copias de currentAppConfig los comparten
+func (c *NightLightController) updateAppConfig(change func(config *models.AppConfig)) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	change(c.appConfig)
+	return c.appConfig.Save()
+}
+
+// lastState devuelve el último estado aplicado al display
+func (c *NightLightController) lastState() appliedState {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lastApplied
+}
+
+// isActive indica si el filtro está activo según el modelo
+func (c *NightLightController) isActive() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.config.IsActive
+}
+
+// selectState cambia de una vez la temperatura y el brillo del modelo sin aplicarlos
+func (c *NightLightController) selectState(temp, brightness float64, source string) {
+	c.mu.Lock()
+	c.config.SetBrightness(brightness)
+	c.config.SetTemperature(temp)
+	c.appConfig.LastTemperature = temp
+	c.appConfig.Save() // Ignorar errores, como en updateTemperature
+	c.mu.Unlock()
+
+	c.publish(EventTemperatureChanged, source)
+}
+
+// Subscribe registra un suscriptor en el bus de eventos del controlador.
+// Devuelve la función para cancelar la suscripción.
+func (c *NightLightController) Subscribe(handler func(Event)) func() {
+	return c.events.Subscribe(handler)
+}
+
+// publish envía un evento con el estado actual del modelo
+func (c *NightLightController) publish(eventType EventType, source string) {
+	config := c.GetConfig()
+	c.events.Publish(Event{
+		Type:        eventType,
+		Temperature: config.Temperature,
+		Active:      config.IsActive,
+		Source:      source,
+	})
+}
+
 // UpdateTemperature actualiza la temperatura
 func (c *NightLightController) UpdateTemperature(temp float64) {
+	c.updateTemperature(temp, "manual")
+}
+
+// updateTemperature actualiza y guarda la temperatura indicando el origen del cambio
+func (c *NightLightController) updateTemperature(temp float64, source string) {
+	c.mu.Lock()
 	c.config.SetTemperature(temp)
 	// Guardar la temperatura como preferencia del usuario
 	c.appConfig.LastTemperature = temp
 	c.appConfig.Save() // Ignorar errores por ahora
+	c.mu.Unlock()
+
+	c.publish(EventTemperatureChanged, source)
 }
 
 // ApplyNightLight aplica la configuración de luz nocturna usando xrandr
 func (c *NightLightController) ApplyNightLight() error {
-	// Aplicar temperatura usando nuestro sistema xrandr
-	if err := c.gammaManager.ApplyTemperature(c.config.Temperature); err != nil {
+	return c.applyNightLight("manual")
+}
+
+// applyNightLight aplica la configuración actual indicando el origen del cambio
+func (c *NightLightController) applyNightLight(source string) error {
+	c.scheduleFade.cancel()
+	// Aplicar temperatura a través de la cola serializada. Si solo falló en
+	// algunos displays el filtro sí está activo: se registra y se informa del error
+	config := c.GetConfig()
+	applyErr := c.applyQueue.Apply(config.Temperature, config.Brightness)
+	if errors.Is(applyErr, errApplySuperseded) {
+		return nil
+	}
+	if applyErr != nil && !errors.Is(applyErr, system.ErrPartialApply) {
+		return applyErr
+	}
+
+	// Marcar como aplicado en el modelo lo que llegó al display, aunque el
+	// slider se haya movido mientras tanto
+	c.mu.Lock()
+	if err := c.config.Apply(); err != nil {
+		c.mu.Unlock()
 		return err
 	}
+	c.recordApplied(appliedState{Temperature: config.Temperature, Brightness: config.Brightness, Contrast: config.Contrast, Active: true}, source)
+	c.mu.Unlock()
 
-	// Marcar como aplicado en el modelo
-	return c.config.Apply()
+	c.publish(EventApplied, source)
+	return applyErr
 }
 
 // ResetNightLight resetea la configuración a valores por defecto
 func (c *NightLightController) ResetNightLight() error {
-	// Resetear gamma del sistema
-	if err := c.gammaManager.Reset(); err != nil {
+	return c.resetNightLight("manual")
+}
+
+// resetNightLight restaura la gamma normal indicando el origen del cambio
+func (c *NightLightController) resetNightLight(source string) error {
+	c.scheduleFade.cancel()
+	// Resetear gamma del sistema a través de la cola serializada
+	err := c.applyQueue.Reset()
+	if errors.Is(err, errApplySuperseded) {
+		return nil
+	}
+	if err != nil {
 		// Si falla, al menos resetear el modelo
+		c.mu.Lock()
 		c.config.Reset()
+		c.mu.Unlock()
+		c.publish(EventReset, source)
 		return err
 	}
 
 	// Resetear configuración
+	c.mu.Lock()
 	c.config.Reset()
 	c.appConfig.LastTemperature = c.config.Temperature
 	c.appConfig.Save() // Ignorar errores
 
+	c.recordApplied(appliedState{Temperature: c.config.Temperature, Brightness: c.config.Brightness}, source)
+	c.mu.Unlock()
+	c.publish(EventReset, source)
+	return nil
+}
+
+// applySchedule aplica un estado del programador y lo registra como el último aplicado
+func (c *NightLightController) applySchedule(temperature, brightness float64) error {
+	if err := c.applyQueue.Apply(temperature, brightness); err != nil {
+		if errors.Is(err, errApplySuperseded) {
+			return nil
+		}
+		c.events.Publish(Event{Type: EventApplyFailed, Temperature: temperature, Source: "scheduler", Err: err})
+		return err
+	}
+	c.mu.Lock()
+	c.config.SetTemperature(temperature)
+	c.lastApplied = appliedState{Temperature: temperature, Brightness: brightness, Contrast: c.config.Contrast, Active: true}
+	c.mu.Unlock()
+	c.publish(EventScheduleTransition, "scheduler")
 	return nil
 }
 
+// recordApplied actualiza el último estado aplicado y guarda el anterior en el
+// historial, salvo para el programador y las propias operaciones de deshacer/rehacer.
+// Se llama con mu tomado
+func (c *NightLightController) recordApplied(state appliedState, source string) {
+	previous := c.lastApplied
+	c.lastApplied = state
+
+	if source == "scheduler" || source == "undo" || source == "redo" || previous == state {
+		return
+	}
+	c.history.record(previous)
+}
+
+/**
+ * Undo - Vuelve exactamente al estado aplicado antes del último cambio manual
+ *
+ * Revierte un clic accidental en un preset o un arrastre del slider,
+ * incluido el brillo y si el filtro estaba activo o no.
+ *
+ * @returns {error} Error si no hay nada que deshacer o si falla la aplicación
+ */
+func (c *NightLightController) Undo() error {
+	target, ok := c.history.popUndo()
+	if !ok {
+		return errNothingToUndo
+	}
+
+	current := c.lastState()
+	if err := c.restoreState(target, "undo"); err != nil {
+		c.history.pushUndo(target)
+		return err
+	}
+	c.history.pushRedo(current)
+	return nil
+}
+
+/**
+ * Redo - Vuelve a aplicar el último estado deshecho
+ *
+ * @returns {error} Error si no hay nada que rehacer o si falla la aplicación
+ */
+func (c *NightLightController) Redo() error {
+	target, ok := c.history.popRedo()
+	if !ok {
+		return errNothingToRedo
+	}
+
+	current := c.lastState()
+	if err := c.restoreState(target, "redo"); err != nil {
+		c.history.pushRedo(target)
+		return err
+	}
+	c.history.pushUndo(current)
+	return nil
+}
+
+// CanUndo indica si hay cambios que deshacer
+func (c *NightLightController) CanUndo() bool {
+	return c.history.canUndo()
+}
+
+// CanRedo indica si hay cambios deshechos que se pueden rehacer
+func (c *NightLightController) CanRedo() bool {
+	return c.history.canRedo()
+}
+
+// restoreState aplica una instantánea del historial sin registrarla de nuevo
+func (c *NightLightController) restoreState(state appliedState, source string) error {
+	if !state.Active {
+		return c.resetNightLight(source)
+	}
+
+	c.mu.Lock()
+	c.config.SetBrightness(state.Brightness)
+	c.config.SetContrast(state.Contrast)
+	c.mu.Unlock()
+	c.updateTemperature(state.Temperature, source)
+	return c.applyNightLight(source)
+}
+
 // ToggleNightLight alterna entre activar y desactivar la luz nocturna
 func (c *NightLightController) ToggleNightLight() error {
-	if c.config.IsActive {
+	if c.isActive() {
 		return c.ResetNightLight()
 	}
 	return c.ApplyNightLight()
 }
 
+// StepTemperature sube o baja la temperatura delta Kelvin sin salir del rango y la aplica
+func (c *NightLightController) StepTemperature(delta float64) error {
+	config := c.GetConfig()
+	temp := math.Max(config.MinTemp, math.Min(config.MaxTemp, config.Temperature+delta))
+	c.updateTemperature(temp, "manual")
+	return c.applyNightLight("manual")
+}
+
 // GetTemperatureRange devuelve el rango de temperatura válido
 func (c *NightLightController) GetTemperatureRange() (min, max float64) {
-	return c.config.MinTemp, c.config.MaxTemp
+	config := c.GetConfig()
+	return config.MinTemp, config.MaxTemp
+}
+
+// GetRGBForTemperature devuelve los factores gamma RGB (0.3-1.0) que se aplicarían para una temperatura
+func (c *NightLightController) GetRGBForTemperature(temp float64) (r, g, b float64) {
+	return system.TemperatureToRGB(temp)
 }
 
 // GetDisplays devuelve la lista de displays detectados
@@ -129,17 +516,733 @@ func (c *NightLightController) GetDisplays() []string {
 	return c.gammaManager.GetDisplays()
 }
 
+// GetDisplayInfo devuelve modelo, resolución y capacidades de cada display.
+// Puede tardar varios segundos (ddcutil): no llamarlo desde el hilo de la interfaz
+func (c *NightLightController) GetDisplayInfo() []system.DisplayInfo {
+	if backend, ok := c.gammaManager.(displayInfoBackend); ok {
+		return backend.GetDisplayInfo()
+	}
+	var infos []system.DisplayInfo
+	for _, display := range c.GetDisplays() {
+		infos = append(infos, system.DisplayInfo{Name: display, Gamma: true})
+	}
+	return infos
+}
+
+// GetHDRDisplays devuelve las salidas con HDR activo (el filtro solo usa la luz nocturna del compositor)
+func (c *NightLightController) GetHDRDisplays() []string {
+	return c.gammaManager.GetHDRDisplays()
+}
+
+// IsDisplayIncluded indica si el filtro se aplica al display indicado
+func (c *NightLightController) IsDisplayIncluded(display string) bool {
+	if !c.IsDisplayInScope(display) {
+		return false
+	}
+	for _, excluded := range c.currentAppConfig().ExcludedDisplays {
+		if excluded == display {
+			return false
+		}
+	}
+	return true
+}
+
+// IsDisplayInScope indica si el tipo de monitor (interno o externo) entra en el ámbito configurado
+func (c *NightLightController) IsDisplayInScope(display string) bool {
+	switch c.GetDisplayScope() {
+	case models.DisplayScopeExternal:
+		return !system.IsInternalDisplay(display)
+	case models.DisplayScopeInternal:
+		return system.IsInternalDisplay(display)
+	}
+	return true
+}
+
+// syncExcludedDisplays pasa al backend los displays excluidos a mano más los que quedan fuera del ámbito
+func (c *NightLightController) syncExcludedDisplays() {
+	excluded := append([]string(nil), c.currentAppConfig().ExcludedDisplays...)
+	for _, display := range c.gammaManager.GetDisplays() {
+		if !c.IsDisplayInScope(display) {
+			excluded = append(excluded, display)
+		}
+	}
+	c.gammaManager.SetExcludedDisplays(excluded)
+}
+
+// GetDisplayScope devuelve qué monitores reciben el filtro ("all", "external" o "internal")
+func (c *NightLightController) GetDisplayScope() string {
+	config := c.currentAppConfig()
+	return config.GetDisplayScope()
+}
+
+/**
+ * SetDisplayScope - Limita el filtro a los monitores externos o al panel integrado
+ *
+ * Útil cuando un monitor o televisor tiene su propio modo cálido y el
+ * doble filtro se ve mal. El tipo se deduce del nombre del conector
+ * (eDP/LVDS/DSI son internos), así que solo tiene efecto en X11, donde la
+ * gamma se aplica por display.
+ *
+ * @param {string} scope - models.DisplayScopeAll, DisplayScopeExternal o DisplayScopeInternal
+ * @returns {error} Error si el ámbito no es válido o falla la aplicación
+ */
+func (c *NightLightController) SetDisplayScope(scope string) error {
+	switch scope {
+	case models.DisplayScopeAll, models.DisplayScopeExternal, models.DisplayScopeInternal:
+	default:
+		return fmt.Errorf("ámbito de monitores no válido: %q", scope)
+	}
+	if c.GetDisplayScope() == scope {
+		return nil
+	}
+
+	if err := c.updateAppConfig(func(config *models.AppConfig) { config.DisplayScope = scope }); err != nil {
+		return err
+	}
+	c.syncExcludedDisplays()
+	c.publish(EventDisplaysChanged, "displays")
+
+	if c.isActive() {
+		return c.applyNightLight("displays")
+	}
+	return nil
+}
+
+/**
+ * SetDisplayIncluded - Incluye o excluye un display del filtro
+ *
+ * Guarda la lista en la configuración y, si el filtro está activo,
+ * lo vuelve a aplicar para que el cambio se vea al momento.
+ *
+ * @param {string} display - Nombre de la salida (p. ej. "HDMI-1")
+ * @param {bool} included - true para aplicarle el filtro
+ * @returns {error} Error si falla la aplicación
+ */
+func (c *NightLightController) SetDisplayIncluded(display string, included bool) error {
+	if c.IsDisplayIncluded(display) == included {
+		return nil
+	}
+
+	c.updateAppConfig(func(config *models.AppConfig) {
+		var excluded []string
+		for _, name := range config.ExcludedDisplays {
+			if name != display {
+				excluded = append(excluded, name)
+			}
+		}
+		if !included {
+			excluded = append(excluded, display)
+		}
+		config.ExcludedDisplays = excluded
+	})
+	c.syncExcludedDisplays()
+	c.publish(EventDisplaysChanged, "displays")
+
+	if c.isActive() {
+		return c.applyNightLight("displays")
+	}
+	return nil
+}
+
+// RefreshDisplays vuelve a detectar los displays conectados y las herramientas instaladas
+func (c *NightLightController) RefreshDisplays() []string {
+	c.gammaManager.RefreshCapabilities()
+	displays := c.gammaManager.RefreshDisplays()
+	c.syncExcludedDisplays()
+	return displays
+}
+
+// GetBackend devuelve el método que aplicó la última temperatura ("" si aún no se aplicó)
+func (c *NightLightController) GetBackend() string {
+	return c.gammaManager.GetBackend()
+}
+
+// GetProtocol devuelve el protocolo de display detectado ("x11" o "wayland")
+func (c *NightLightController) GetProtocol() string {
+	return c.gammaManager.GetProtocol()
+}
+
+// === MÉTODOS DE COMPORTAMIENTO DE LA VENTANA ===
+
+// GetCloseBehavior devuelve qué hacer al cerrar la ventana ("tray", "quit" o "ask")
+func (c *NightLightController) GetCloseBehavior() string {
+	config := c.currentAppConfig()
+	return config.GetCloseBehavior()
+}
+
+// SetCloseBehavior guarda el comportamiento al cerrar la ventana
+func (c *NightLightController) SetCloseBehavior(behavior string) error {
+	switch behavior {
+	case models.CloseBehaviorTray, models.CloseBehaviorQuit, models.CloseBehaviorAsk:
+	default:
+		return fmt.Errorf("comportamiento de cierre desconocido: %s", behavior)
+	}
+
+	return c.updateAppConfig(func(config *models.AppConfig) {
+		config.CloseBehavior = behavior
+		config.MinimizeToTray = behavior == models.CloseBehaviorTray
+	})
+}
+
+// IsResetOnQuit indica si la gamma se restaura al salir
+func (c *NightLightController) IsResetOnQuit() bool {
+	return c.currentAppConfig().ResetOnQuit
+}
+
+// SetResetOnQuit activa o desactiva la restauración de gamma al salir
+func (c *NightLightController) SetResetOnQuit(enabled bool) error {
+	return c.updateAppConfig(func(config *models.AppConfig) { config.ResetOnQuit = enabled })
+}
+
+// GetInterpolation devuelve la unidad de interpolación de las transiciones ("mired" o "kelvin")
+func (c *NightLightController) GetInterpolation() string {
+	return c.currentAppConfig().Schedule.GetInterpolation()
+}
+
+// SetInterpolation cambia la unidad de interpolación de las transiciones programadas
+func (c *NightLightController) SetInterpolation(unit string) error {
+	switch unit {
+	case models.InterpolationMired, models.InterpolationKelvin:
+	default:
+		return fmt.Errorf("unidad de interpolación desconocida: %s", unit)
+	}
+
+	return c.updateAppConfig(func(config *models.AppConfig) { config.Schedule.Interpolation = unit })
+}
+
+// GetLayoutMode devuelve el tamaño de los controles de la ventana ("auto", "normal" o "touch")
+func (c *NightLightController) GetLayoutMode() string {
+	config := c.currentAppConfig()
+	return config.GetLayoutMode()
+}
+
+// SetLayoutMode cambia el tamaño de los controles de la ventana
+func (c *NightLightController) SetLayoutMode(mode string) error {
+	switch mode {
+	case models.LayoutModeAuto, models.LayoutModeNormal, models.LayoutModeTouch:
+	default:
+		return fmt.Errorf("modo de diseño desconocido: %s", mode)
+	}
+
+	return c.updateAppConfig(func(config *models.AppConfig) { config.LayoutMode = mode })
+}
+
+// GetTrayIcon devuelve el icono de la bandeja elegido ("auto", "color", "light", "dark" o una ruta)
+func (c *NightLightController) GetTrayIcon() string {
+	config := c.currentAppConfig()
+	return config.GetTrayIcon()
+}
+
+// IsTrayBadge indica si la temperatura aplicada se dibuja sobre el icono de la bandeja
+func (c *NightLightController) IsTrayBadge() bool {
+	return c.currentAppConfig().TrayBadge
+}
+
+// GetClockFormat devuelve el formato de hora preferido ("auto", "24h" o "12h")
+func (c *NightLightController) GetClockFormat() string {
+	config := c.currentAppConfig()
+	return config.GetClockFormat()
+}
+
+// GetClock devuelve el formato de hora resuelto para mostrar horarios
+func (c *NightLightController) GetClock() models.Clock {
+	return models.NewClock(c.GetClockFormat())
+}
+
+// SetClockFormat cambia el formato de hora de los horarios, el próximo cambio y las notificaciones
+func (c *NightLightController) SetClockFormat(format string) error {
+	switch format {
+	case models.ClockFormatAuto, models.ClockFormat24h, models.ClockFormat12h:
+	default:
+		return fmt.Errorf("formato de hora desconocido: %s", format)
+	}
+
+	return c.updateAppConfig(func(config *models.AppConfig) { config.ClockFormat = format })
+}
+
+// GetTemperatureUnit devuelve cómo se muestra la temperatura ("kelvin" o "warmth")
+func (c *NightLightController) GetTemperatureUnit() string {
+	config := c.currentAppConfig()
+	return config.GetTemperatureUnit()
+}
+
+// GetTemperatureFormat devuelve el formato de temperatura sobre el rango configurado
+func (c *NightLightController) GetTemperatureFormat() models.TemperatureFormat {
+	minTemp, maxTemp := c.GetTemperatureRange()
+	return models.TemperatureFormat{
+		Unit:    c.GetTemperatureUnit(),
+		MinTemp: minTemp,
+		MaxTemp: maxTemp,
+	}
+}
+
+// SetTemperatureUnit cambia la unidad de la temperatura en las etiquetas, la bandeja y la CLI
+func (c *NightLightController) SetTemperatureUnit(unit string) error {
+	switch unit {
+	case models.TemperatureUnitKelvin, models.TemperatureUnitWarmth:
+	default:
+		return fmt.Errorf("unidad de temperatura desconocida: %s", unit)
+	}
+
+	return c.updateAppConfig(func(config *models.AppConfig) { config.TemperatureUnit = unit })
+}
+
+// IsSnapToPresets indica si el slider de temperatura se ajusta a los presets
+func (c *NightLightController) IsSnapToPresets() bool {
+	return c.currentAppConfig().SnapToPresets
+}
+
+// SetSnapToPresets activa o desactiva el ajuste del slider a los presets
+func (c *NightLightController) SetSnapToPresets(enabled bool) error {
+	return c.updateAppConfig(func(config *models.AppConfig) { config.SnapToPresets = enabled })
+}
+
+// IsFineSteps indica si el slider avanza en pasos finos de 10K
+func (c *NightLightController) IsFineSteps() bool {
+	return c.currentAppConfig().FineSteps
+}
+
+// SetFineSteps alterna entre pasos finos (10K) y normales (100K) del slider
+func (c *NightLightController) SetFineSteps(enabled bool) error {
+	return c.updateAppConfig(func(config *models.AppConfig) { config.FineSteps = enabled })
+}
+
+// GetWatchdog devuelve la configuración del vigilante de gamma
+func (c *NightLightController) GetWatchdog() models.WatchdogConfig {
+	return c.currentAppConfig().Watchdog
+}
+
+// GetGRPCConfig devuelve la configuración del servidor gRPC
+func (c *NightLightController) GetGRPCConfig() models.GRPCConfig {
+	return c.currentAppConfig().GRPC
+}
+
+// GetNotifications devuelve qué notificaciones respetan "No molestar"
+func (c *NightLightController) GetNotifications() models.NotificationsConfig {
+	return c.currentAppConfig().Notifications
+}
+
+/**
+ * SetWatchdog - Activa o desactiva el vigilante de gamma y fija su intervalo
+ *
+ * @param {bool} enabled - Si se vigila la gamma aplicada
+ * @param {int} interval - Segundos entre comprobaciones (MinWatchdogInterval-MaxWatchdogInterval)
+ * @returns {error} Error si el intervalo no es válido o no se puede guardar
+ */
+func (c *NightLightController) SetWatchdog(enabled bool, interval int) error {
+	if interval < models.MinWatchdogInterval || interval > models.MaxWatchdogInterval {
+		return fmt.Errorf("intervalo del vigilante fuera de rango: %d s (%d-%d)",
+			interval, models.MinWatchdogInterval, models.MaxWatchdogInterval)
+	}
+
+	watchdog := models.WatchdogConfig{Enabled: enabled, Interval: interval}
+	err := c.updateAppConfig(func(config *models.AppConfig) { config.Watchdog = watchdog })
+	if enabled {
+		c.watchdog.Start(watchdog.GetInterval())
+	} else {
+		c.watchdog.Stop()
+	}
+	return err
+}
+
+/**
+ * checkGamma - Comprobación periódica del vigilante de gamma
+ *
+ * Si el filtro está activo y la gamma del display ya no coincide con la
+ * última aplicada, la vuelve a aplicar a través de la cola. No publica
+ * eventos ni toca el historial: el estado deseado no ha cambiado. En
+ * modo vacaciones no comprueba nada.
+ *
+ * @private
+ */
+func (c *NightLightController) checkGamma() {
+	state := c.lastState()
+	if !state.Active {
+		return
+	}
+	if _, paused := c.IsOnVacation(); paused {
+		return
+	}
+	if c.idle.isIdle() {
+		return // Se reaplica al volver la actividad
+	}
+
+	// Con una regla o un ajuste automático en vigor se comprueba lo que llegó al backend
+	expected := c.effectiveRequest(applyRequest{temperature: state.Temperature, brightness: state.Brightness})
+	if expected.reset {
+		return
+	}
+	ok, err := c.gammaManager.VerifyGamma(expected.temperature, expected.brightness)
+	if err != nil {
+		logging.Printf("⚠️  Vigilante de gamma: %v\n", err)
+		return
+	}
+	if ok {
+		return
+	}
+
+	logging.WithFields(logging.Fields{"backend": c.gammaManager.GetBackend(), "temp": state.Temperature}).
+		Printf("🐕 Vigilante de gamma: reaplicando %.0fK\n", state.Temperature)
+	if err := c.applyQueue.Apply(state.Temperature, state.Brightness); err != nil && !errors.Is(err, errApplySuperseded) {
+		logging.Printf("⚠️  Vigilante de gamma: no se pudo reaplicar: %v\n", err)
+	}
+}
+
+// GetWindowState devuelve la geometría guardada de la ventana principal
+func (c *NightLightController) GetWindowState() models.WindowState {
+	return c.currentAppConfig().Window
+}
+
+// SaveWindowSize guarda el tamaño de la ventana principal si cambió
+func (c *NightLightController) SaveWindowSize(width, height float32) error {
+	if width <= 0 || height <= 0 {
+		return nil
+	}
+	if window := c.GetWindowState(); window.Width == width && window.Height == height {
+		return nil
+	}
+
+	return c.updateAppConfig(func(config *models.AppConfig) {
+		config.Window.Width = width
+		config.Window.Height = height
+	})
+}
+
+// SaveWindowTab guarda la última pestaña seleccionada de la ventana principal
+func (c *NightLightController) SaveWindowTab(tab string) error {
+	if c.GetWindowState().Tab == tab {
+		return nil
+	}
+
+	return c.updateAppConfig(func(config *models.AppConfig) { config.Window.Tab = tab })
+}
+
+/**
+ * Shutdown - Libera recursos al salir de la aplicación
+ *
+ * Detiene el programador y, si el usuario lo eligió (configuración o
+ * --reset-on-exit), restaura la gamma normal antes de que termine el
+ * proceso. Solo actúa la primera vez que se llama.
+ */
+func (c *NightLightController) Shutdown() {
+	c.shutdown(c.IsResetOnQuit() || c.resetOnExit)
+}
+
+/**
+ * EmergencyRestore - Restaura la gamma tras una señal o un pánico
+ *
+ * Siempre vuelve a los colores normales y elimina el archivo de bloqueo,
+ * para no dejar la pantalla naranja sin una forma evidente de deshacerlo.
+ * Llama al backend directamente, sin pasar por la cola, por si la
+ * goroutine de la cola es la que falló. La gamma se restaura antes de
+ * detener nada; los observadores se detienen después en segundo plano,
+ * esperando como mucho emergencyStopTimeout, porque el proceso va a
+ * terminar y ninguno debe poder bloquear la salida.
+ */
+func (c *NightLightController) EmergencyRestore() {
+	c.shutdownOnce.Do(func() {
+		logging.Println("🚨 Salida inesperada: restaurando gamma...")
+		if err := c.gammaManager.Reset(); err != nil {
+			logging.Printf("⚠️  No se pudo restaurar la gamma: %v\n", err)
+		}
+		c.gammaManager.Close()
+
+		stopped := make(chan struct{})
+		go func() {
+			defer close(stopped)
+			c.scheduler.Stop()
+			c.watchdog.Stop()
+			c.stopAppRules()
+			c.stopWorkspaceRules()
+			c.stopLayoutProfiles()
+			c.stopDarkMode()
+			c.stopIdleWatcher()
+			c.stopGaming()
+			c.stopSessionLock()
+			c.stopPowerWatcher()
+			c.stopWeather()
+			c.stopAmbient()
+			c.stopMovieMode()
+			c.stopColorAccurate()
+			c.stopCalibration()
+		}()
+		select {
+		case <-stopped:
+		case <-time.After(emergencyStopTimeout):
+			logging.Println("⚠️  Los observadores no se detuvieron a tiempo; se sale igualmente")
+		}
+	})
+}
+
+// shutdown detiene el programador, restaura la gamma si se pide y libera el backend
+func (c *NightLightController) shutdown(reset bool) {
+	c.shutdownOnce.Do(func() {
+		c.scheduler.Stop()
+		c.watchdog.Stop()
+		c.stopWeather()
+		c.stopLayoutProfiles()
+		c.stopDarkMode()
+		c.stopIdleWatcher()
+
+		// Si una regla, el bloqueo, una pausa temporal, un juego o un ajuste automático cambiaban la gamma, dejar la del usuario
+		appRule, workspaceRule := c.stopAppRules(), c.stopWorkspaceRules()
+		movie, colorAccurate, calibration := c.stopMovieMode(), c.stopColorAccurate(), c.stopCalibration()
+		locked, saving, ambient, gaming := c.stopSessionLock(), c.stopPowerWatcher(), c.stopAmbient(), c.stopGaming()
+		if (appRule || workspaceRule || movie || colorAccurate || calibration || locked || saving || ambient || gaming) && !reset {
+			c.reapplyLastState()
+		}
+
+		if reset {
+			logging.Println("🔄 Restaurando gamma antes de salir...")
+			if err := c.applyQueue.Reset(); err != nil {
+				logging.Printf("⚠️  No se pudo restaurar la gamma: %v\n", err)
+			}
+		}
+
+		c.gammaManager.Close()
+	})
+}
+
+// logActivity añade al historial los eventos que cambian lo que se ve en pantalla
+func (c *NightLightController) logActivity(event Event) {
+	switch event.Type {
+	case EventApplied, EventReset, EventScheduleTransition:
+	default:
+		return
+	}
+
+	// Solo las temperaturas nocturna y diurna terminan una transición
+	schedule := c.GetScheduleConfig()
+	step := event.Type == EventScheduleTransition &&
+		event.Temperature != schedule.NightTemp && event.Temperature != schedule.DayTemp
+
+	err := c.activity.Append(models.ActivityEntry{
+		Time:        time.Now(),
+		Type:        string(event.Type),
+		Source:      event.Source,
+		Temperature: event.Temperature,
+		Active:      event.Active,
+		Step:        step,
+	})
+	if err != nil {
+		logging.Printf("⚠️  No se pudo registrar la actividad: %v\n", err)
+	}
+}
+
+// GetActivity devuelve las últimas entradas del historial, la más reciente primero
+func (c *NightLightController) GetActivity(limit int) ([]models.ActivityEntry, error) {
+	return c.activity.Recent(limit)
+}
+
+// ExportActivityCSV escribe el historial de actividad completo en formato CSV
+func (c *NightLightController) ExportActivityCSV(w io.Writer) error {
+	return c.activity.ExportCSV(w)
+}
+
+/**
+ * ExportDiagnostics - Escribe el paquete de diagnóstico con el estado de esta instancia
+ *
+ * Además de lo que recoge diagnostics.WriteBundle incluye el backend en
+ * uso, el último estado aplicado y los displays, y los registros son los
+ * de la sesión en marcha.
+ *
+ * @param {io.Writer} w - Destino del tar.gz
+ * @returns {error} Error si no se pudo escribir
+ */
+func (c *NightLightController) ExportDiagnostics(w io.Writer) error {
+	state := c.lastState()
+	config := c.currentAppConfig()
+	movie, _ := c.GetMovieMode()
+	accurate, _ := c.GetColorAccurate()
+	_, vacation := c.IsOnVacation()
+	session := map[string]string{
+		"protocol":        c.GetProtocol(),
+		"backend":         c.GetBackend(),
+		"displays":        strings.Join(c.GetDisplays(), ", "),
+		"excluded":        strings.Join(config.ExcludedDisplays, ", "),
+		"hdr":             strings.Join(c.GetHDRDisplays(), ", "),
+		"active":          fmt.Sprintf("%t", state.Active),
+		"temperature":     fmt.Sprintf("%.0fK", state.Temperature),
+		"brightness":      fmt.Sprintf("%.2f", state.Brightness),
+		"contrast":        fmt.Sprintf("%.2f", state.Contrast),
+		"schedule":        fmt.Sprintf("%t", c.IsScheduleRunning()),
+		"watchdog":        fmt.Sprintf("%t", config.Watchdog.Enabled),
+		"movie_mode":      fmt.Sprintf("%t", movie),
+		"color_accurate":  fmt.Sprintf("%t", accurate),
+		"vacation":        fmt.Sprintf("%t", vacation),
+		"battery_profile": fmt.Sprintf("%t", c.IsBatterySaving()),
+	}
+	return diagnostics.WriteBundle(w, session)
+}
+
+// ConfigLoadError devuelve el error de carga de la configuración al iniciar (nil si cargó bien)
+func (c *NightLightController) ConfigLoadError() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.loadErr
+}
+
+/**
+ * RestoreConfigBackup - Sustituye la configuración actual por la copia de seguridad
+ *
+ * Aplica la temperatura guardada en la copia y reinicia el programador
+ * según la configuración restaurada.
+ *
+ * @returns {error} Error si no hay copia válida
+ */
+func (c *NightLightController) RestoreConfigBackup() error {
+	c.mu.Lock()
+	if err := c.appConfig.RestoreBackup(); err != nil {
+		c.mu.Unlock()
+		return err
+	}
+	c.loadErr = nil
+	c.config.SetTemperature(c.appConfig.LastTemperature)
+	c.mu.Unlock()
+
+	c.scheduler.UpdateConfig(c.appConfig)
+	c.publish(EventTemperatureChanged, "config")
+	return nil
+}
+
+// === MÉTODOS DE PRESETS ===
+
+// GetPresets devuelve una copia de los presets definidos por el usuario
+func (c *NightLightController) GetPresets() []models.Preset {
+	return append([]models.Preset(nil), c.currentAppConfig().Presets...)
+}
+
+// AddPreset agrega un preset nuevo al final de la lista
+func (c *NightLightController) AddPreset(preset models.Preset) error {
+	return c.savePresets(func(presets []models.Preset) ([]models.Preset, error) {
+		if err := c.validatePreset(presets, preset, -1); err != nil {
+			return nil, err
+		}
+		return append(presets, preset), nil
+	})
+}
+
+// UpdatePreset reemplaza el preset en la posición indicada (renombrar, cambiar valores)
+func (c *NightLightController) UpdatePreset(index int, preset models.Preset) error {
+	return c.savePresets(func(presets []models.Preset) ([]models.Preset, error) {
+		if index < 0 || index >= len(presets) {
+			return nil, fmt.Errorf("preset inexistente: %d", index)
+		}
+		if err := c.validatePreset(presets, preset, index); err != nil {
+			return nil, err
+		}
+		presets[index] = preset
+		return presets, nil
+	})
+}
+
+// DeletePreset elimina el preset en la posición indicada
+func (c *NightLightController) DeletePreset(index int) error {
+	return c.savePresets(func(presets []models.Preset) ([]models.Preset, error) {
+		if index < 0 || index >= len(presets) {
+			return nil, fmt.Errorf("preset inexistente: %d", index)
+		}
+		return append(presets[:index], presets[index+1:]...), nil
+	})
+}
+
+// SelectPreset actualiza temperatura, brillo y contraste según el preset, sin aplicarlo al display
+func (c *NightLightController) SelectPreset(index int) error {
+	presets := c.GetPresets()
+	if index < 0 || index >= len(presets) {
+		return fmt.Errorf("preset inexistente: %d", index)
+	}
+
+	c.selectPreset(presets[index], "preset")
+	return nil
+}
+
+// selectPreset fija temperatura, brillo y contraste del preset indicando el origen del cambio
+func (c *NightLightController) selectPreset(preset models.Preset, source string) {
+	c.mu.Lock()
+	c.config.SetBrightness(preset.Brightness)
+	c.config.SetContrast(preset.Contrast)
+	c.mu.Unlock()
+	c.updateTemperature(preset.Temperature, source)
+}
+
+// ApplyPreset selecciona el preset y lo aplica inmediatamente
+func (c *NightLightController) ApplyPreset(index int) error {
+	if err := c.SelectPreset(index); err != nil {
+		return err
+	}
+	return c.applyNightLight("preset")
+}
+
+/**
+ * savePresets - Cambia la lista de presets, la guarda y notifica el cambio
+ *
+ * change recibe una copia de la lista con el cerrojo tomado, así que
+ * validar y modificar es una sola operación aunque llegue otro cambio
+ * desde D-Bus a la vez.
+ *
+ * @param {func([]models.Preset) ([]models.Preset, error)} change - Devuelve la lista nueva o un error para no cambiar nada
+ * @returns {error} Error de change o del guardado
+ * @private
+ */
+func (c *NightLightController) savePresets(change func(presets []models.Preset) ([]models.Preset, error)) error {
+	c.mu.Lock()
+	presets, err := change(append([]models.Preset(nil), c.appConfig.Presets...))
+	if err != nil {
+		c.mu.Unlock()
+		return err
+	}
+	c.appConfig.Presets = presets
+	err = c.appConfig.Save()
+	c.mu.Unlock()
+
+	c.publish(EventPresetsChanged, "manual")
+	return err
+}
+
+// validatePreset comprueba rango y nombre único en presets (ignorando la posición skip). Se llama con mu tomado
+func (c *NightLightController) validatePreset(presets []models.Preset, preset models.Preset, skip int) error {
+	if err := preset.Validate(c.config.MinTemp, c.config.MaxTemp); err != nil {
+		return err
+	}
+	for i, existing := range presets {
+		if i != skip && strings.EqualFold(existing.Name, preset.Name) {
+			return fmt.Errorf("ya existe un preset llamado %q", preset.Name)
+		}
+		if i != skip && preset.Key != "" && strings.EqualFold(existing.Key, preset.Key) {
+			return fmt.Errorf("la tecla %s ya selecciona el preset %q", preset.Key, existing.Name)
+		}
+	}
+	return nil
+}
+
 // === MÉTODOS DE PROGRAMACIÓN AUTOMÁTICA ===
 
 // EnableSchedule habilita la programación automática
 func (c *NightLightController) EnableSchedule(enabled bool) {
-	c.appConfig.ScheduleEnabled = enabled
-	c.appConfig.Save()
+	followedDarkMode := false
+	c.updateAppConfig(func(config *models.AppConfig) {
+		config.ScheduleEnabled = enabled
+		if enabled && config.FollowDarkMode {
+			// La programación y el tema del escritorio son disparadores alternativos
+			config.FollowDarkMode = false
+			followedDarkMode = true
+		}
+	})
+	if followedDarkMode {
+		c.stopDarkMode()
+	}
 
 	if enabled {
+		c.scheduleFade.request()
 		c.scheduler.Start()
 	} else {
+		sleeping := c.IsSleepModeActive()
 		c.scheduler.Stop()
+		c.scheduleFade.cancel()
+		if sleeping {
+			c.reapplyLastState() // Sin programación no hay fase de sueño: recuperar el color
+		}
 	}
 
 	c.scheduler.UpdateConfig(c.appConfig)
@@ -147,7 +1250,7 @@ func (c *NightLightController) EnableSchedule(enabled bool) {
 
 // IsScheduleEnabled verifica si la programación está habilitada
 func (c *NightLightController) IsScheduleEnabled() bool {
-	return c.appConfig.ScheduleEnabled
+	return c.currentAppConfig().ScheduleEnabled
 }
 
 // IsScheduleRunning verifica si el programador está ejecutándose
@@ -155,21 +1258,156 @@ func (c *NightLightController) IsScheduleRunning() bool {
 	return c.scheduler.IsRunning()
 }
 
-// UpdateScheduleConfig actualiza la configuración de horarios
-func (c *NightLightController) UpdateScheduleConfig(startTime, endTime string, nightTemp, dayTemp float64, transitionTime int) {
-	c.appConfig.Schedule.StartTime = startTime
-	c.appConfig.Schedule.EndTime = endTime
-	c.appConfig.Schedule.NightTemp = nightTemp
-	c.appConfig.Schedule.DayTemp = dayTemp
-	c.appConfig.Schedule.TransitionTime = transitionTime
+// UpdateScheduleConfig actualiza la configuración de horarios.
+// Si algún valor es inválido no se guarda nada y se devuelve el error.
+func (c *NightLightController) UpdateScheduleConfig(startTime, endTime string, nightTemp, dayTemp float64, transitionTime int) error {
+	// Las horas pueden escribirse en 12 horas; se guardan siempre como "HH:MM"
+	start, err := models.NormalizeScheduleTime(startTime)
+	if err != nil {
+		return fmt.Errorf("inicio: %w", err)
+	}
+	end, err := models.NormalizeScheduleTime(endTime)
+	if err != nil {
+		return fmt.Errorf("fin: %w", err)
+	}
+
+	return c.updateSchedule(func(schedule *models.ScheduleConfig) error {
+		schedule.StartTime = start
+		schedule.EndTime = end
+		schedule.NightTemp = nightTemp
+		schedule.DayTemp = dayTemp
+		schedule.TransitionTime = transitionTime
+		return schedule.Validate()
+	})
+}
+
+/**
+ * updateSchedule - Cambia la programación, la guarda y avisa al programador
+ *
+ * change recibe una copia con el cerrojo tomado; si devuelve un error no
+ * se guarda nada.
+ *
+ * @param {func(*models.ScheduleConfig) error} change - Modifica la copia y la valida
+ * @returns {error} Error de change
+ * @private
+ */
+func (c *NightLightController) updateSchedule(change func(schedule *models.ScheduleConfig) error) error {
+	c.mu.Lock()
+	schedule := c.appConfig.Schedule
+	if err := change(&schedule); err != nil {
+		c.mu.Unlock()
+		return err
+	}
+	c.appConfig.Schedule = schedule
 	c.appConfig.Save()
+	c.mu.Unlock()
+
+	c.scheduler.UpdateConfig(c.appConfig)
+	return nil
+}
+
+// SetScheduleMode cambia el modo de cálculo de la programación ("fixed" o "solar")
+func (c *NightLightController) SetScheduleMode(mode string) error {
+	if mode != models.ScheduleModeFixed && mode != models.ScheduleModeSolar {
+		return fmt.Errorf("modo de programación desconocido: %s", mode)
+	}
+
+	return c.updateSchedule(func(schedule *models.ScheduleConfig) error {
+		schedule.Mode = mode
+		return nil
+	})
+}
+
+// UpdateLocation actualiza la ubicación usada por el modo solar
+func (c *NightLightController) UpdateLocation(latitude, longitude float64) error {
+	location := models.Location{Latitude: latitude, Longitude: longitude}
+	if !location.IsValid() {
+		return fmt.Errorf("coordenadas fuera de rango: %.4f, %.4f", latitude, longitude)
+	}
+
+	return c.updateSchedule(func(schedule *models.ScheduleConfig) error {
+		schedule.Location = location
+		return nil
+	})
+}
+
+// DetectNativeSchedule devuelve el horario de GNOME Night Light o KDE Night Color
+// en la primera ejecución, para ofrecer adoptarlo; nil en las siguientes
+func (c *NightLightController) DetectNativeSchedule() *system.NativeSchedule {
+	if config := c.currentAppConfig(); !config.IsFirstRun() {
+		return nil
+	}
+	return system.DetectNativeSchedule()
+}
+
+/**
+ * AdoptNativeSchedule - Usa el horario nativo del escritorio como programación inicial
+ *
+ * Copia horas, temperaturas y transición; si el escritorio seguía la
+ * puesta de sol y conocía la ubicación se usa el modo solar. Activa la
+ * programación para que la rutina del usuario continúe sin cambios.
+ *
+ * @param {*system.NativeSchedule} native - Horario leído con DetectNativeSchedule
+ * @returns {error} Error si el horario importado no es válido
+ */
+func (c *NightLightController) AdoptNativeSchedule(native *system.NativeSchedule) error {
+	c.mu.Lock()
+	schedule := c.appConfig.Schedule
+	c.mu.Unlock()
+	schedule.StartTime = native.StartTime
+	schedule.EndTime = native.EndTime
+	schedule.NightTemp = native.NightTemp
+	schedule.DayTemp = native.DayTemp
+	if native.TransitionTime > 0 {
+		schedule.TransitionTime = native.TransitionTime
+	}
+
+	schedule.Mode = models.ScheduleModeFixed
+	location := models.Location{Latitude: native.Latitude, Longitude: native.Longitude}
+	if native.Automatic && native.HasLocation && location.IsValid() {
+		schedule.Mode = models.ScheduleModeSolar
+		schedule.Location = location
+	}
+
+	if err := schedule.Validate(); err != nil {
+		return fmt.Errorf("horario de %s no válido: %w", native.Source, err)
+	}
+
+	c.updateAppConfig(func(config *models.AppConfig) {
+		config.Schedule = schedule
+		config.ScheduleEnabled = true
+	})
 
 	c.scheduler.UpdateConfig(c.appConfig)
+	c.scheduler.Start()
+	return nil
+}
+
+/**
+ * ApplyScheduleTemplate - Rellena la programación con una plantilla predefinida
+ *
+ * Es solo un punto de partida: no habilita la programación ni toca la
+ * ubicación, y los valores pueden ajustarse después.
+ *
+ * @param {string} id - Identificador de models.ScheduleTemplates ("night-owl"...)
+ * @returns {error} Error si la plantilla no existe o necesita una ubicación
+ */
+func (c *NightLightController) ApplyScheduleTemplate(id string) error {
+	template, ok := models.FindScheduleTemplate(id)
+	if !ok {
+		return fmt.Errorf("plantilla de programación desconocida: %s", id)
+	}
+
+	return c.updateSchedule(func(schedule *models.ScheduleConfig) error {
+		applied, err := template.ApplyTo(*schedule)
+		*schedule = applied
+		return err
+	})
 }
 
 // GetScheduleConfig obtiene la configuración actual de horarios
 func (c *NightLightController) GetScheduleConfig() models.ScheduleConfig {
-	return c.appConfig.Schedule
+	return c.currentAppConfig().Schedule
 }
 
 // GetNextScheduleChange obtiene información sobre el próximo cambio programado
@@ -179,12 +1417,48 @@ func (c *NightLightController) GetNextScheduleChange() (string, float64, time.Du
 
 // ApplyScheduleNow aplica inmediatamente la temperatura correspondiente al horario actual
 func (c *NightLightController) ApplyScheduleNow() error {
-	if !c.appConfig.ScheduleEnabled {
+	if !c.IsScheduleEnabled() {
 		return fmt.Errorf("la programación automática está deshabilitada")
 	}
 
-	// El scheduler aplicará automáticamente la temperatura correcta
+	// El scheduler aplicará automáticamente la temperatura correcta, con un fundido desde la actual
 	c.scheduler.Stop()
+	c.scheduleFade.request()
 	c.scheduler.Start()
 	return nil
 }
+
+/**
+ * SkipTonight - Omite solo el período nocturno de hoy
+ *
+ * La programación sigue habilitada: el programador aplica la temperatura
+ * diurna hasta que termina esa noche y después continúa como siempre.
+ *
+ * @returns {time.Time, error} Hora a la que se reanuda la programación
+ */
+func (c *NightLightController) SkipTonight() (time.Time, error) {
+	if !c.IsScheduleEnabled() {
+		return time.Time{}, fmt.Errorf("la programación automática está deshabilitada")
+	}
+
+	c.scheduleFade.request()
+	until, err := c.scheduler.SkipTonight()
+	if err != nil {
+		return time.Time{}, err
+	}
+	c.publish(EventSkipTonightChanged, "scheduler")
+	return until, nil
+}
+
+// CancelSkipTonight vuelve a aplicar la noche de hoy si se había omitido
+func (c *NightLightController) CancelSkipTonight() {
+	c.scheduleFade.request()
+	if c.scheduler.CancelSkip() {
+		c.publish(EventSkipTonightChanged, "scheduler")
+	}
+}
+
+// GetSkipTonight indica si la noche de hoy está omitida y a qué hora se reanuda la programación
+func (c *NightLightController) GetSkipTonight() (time.Time, bool) {
+	return c.scheduler.SkippedUntil()
+}
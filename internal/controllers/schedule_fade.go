@@ -0,0 +1,123 @@
+package controllers
+
+import (
+	"luznocturna/luz-nocturna/internal/logging"
+	"luznocturna/luz-nocturna/internal/models"
+	"math"
+	"sync"
+	"time"
+)
+
+// Fundido hacia un cambio programado que llega a mitad de período: unos 2 segundos
+const (
+	scheduleFadeSteps     = 10
+	scheduleFadeStepDelay = 200 * time.Millisecond
+)
+
+// scheduleResumeGap es el hueco entre dos tics del programador a partir del cual se supone una suspensión
+const scheduleResumeGap = 3 * time.Minute
+
+/**
+ * scheduleFadeState - Decide cuándo un cambio del programador llega con fundido
+ *
+ * Los tics normales ya avanzan poco a poco; solo saltan al habilitar la
+ * programación a mitad de período (o al aplicarla a mano) y al volver de
+ * una suspensión, que se detecta por el hueco entre dos tics. El fundido
+ * corre en segundo plano y cualquier tic o cambio manual posterior lo
+ * deja obsoleto.
+ *
+ * @struct {scheduleFadeState}
+ * @property {bool} pending - El próximo tic debe llegar con fundido
+ * @property {time.Time} lastTick - Hora de reloj del último tic (sin lectura monotónica)
+ * @property {uint64} generation - Cambia con cada tic y cada cancelación; el fundido en curso la compara
+ */
+type scheduleFadeState struct {
+	mu         sync.Mutex
+	pending    bool
+	lastTick   time.Time
+	generation uint64
+}
+
+// request pide un fundido para el próximo tic del programador
+func (s *scheduleFadeState) request() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pending = true
+}
+
+// take registra un tic e indica si debe llegar con fundido (pedido o tras una suspensión) y su generación
+func (s *scheduleFadeState) take(now time.Time) (bool, uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.generation++
+
+	// Round(0) quita la lectura monotónica, que en Linux no avanza durante la suspensión
+	now = now.Round(0)
+	resumed := !s.lastTick.IsZero() && now.Sub(s.lastTick) > scheduleResumeGap
+	s.lastTick = now
+
+	fade := s.pending || resumed
+	s.pending = false
+	return fade, s.generation
+}
+
+// cancel deja obsoleto el fundido en curso (un cambio manual manda sobre él)
+func (s *scheduleFadeState) cancel() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.generation++
+}
+
+// isCurrent indica si ningún tic ni cambio posterior dejó obsoleto el fundido de esa generación
+func (s *scheduleFadeState) isCurrent(generation uint64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.generation == generation
+}
+
+/**
+ * fadeToSchedule - Lleva la pantalla al estado programado con un fundido en segundo plano
+ *
+ * Parte de lo que hay en pantalla (la gamma normal si el filtro estaba
+ * desactivado) y, como el fundido de los espacios de trabajo, pasa cada
+ * paso por la cola. Vuelve enseguida: quien llama (el programador, o
+ * SkipTonight desde la interfaz) no espera los 2 segundos del fundido.
+ * Si un tic o un cambio manual lo deja obsoleto se abandona sin aplicar
+ * el estado final.
+ *
+ * @param {float64} temperature - Temperatura programada
+ * @param {float64} brightness - Brillo programado
+ * @param {uint64} generation - Generación del tic que pidió el fundido
+ * @returns {bool} false si la diferencia es imperceptible y quien llama debe aplicar directamente
+ * @private
+ */
+func (c *NightLightController) fadeToSchedule(temperature, brightness float64, generation uint64) bool {
+	from := c.lastState()
+	if !from.Active {
+		from = appliedState{Temperature: models.DaylightTemp, Brightness: 1.0}
+	}
+	if from.Brightness <= 0 {
+		from.Brightness = 1.0
+	}
+	if math.Abs(temperature-from.Temperature) < 50 && math.Abs(brightness-from.Brightness) < 0.02 {
+		return false // Diferencia imperceptible
+	}
+
+	go func() {
+		completed := fade(scheduleFadeSteps, scheduleFadeStepDelay, func(progress float64) bool {
+			if !c.scheduleFade.isCurrent(generation) {
+				return false
+			}
+			c.applyQueue.Apply(from.Temperature+(temperature-from.Temperature)*progress,
+				from.Brightness+(brightness-from.Brightness)*progress)
+			return true
+		})
+		if !completed || !c.scheduleFade.isCurrent(generation) {
+			return
+		}
+		if err := c.applySchedule(temperature, brightness); err != nil {
+			logging.Printf("⚠️  Error aplicando temperatura automática: %v\n", err)
+		}
+	}()
+	return true
+}
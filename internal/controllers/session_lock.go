@@ -0,0 +1,107 @@
+package controllers
+
+import (
+	"luznocturna/luz-nocturna/internal/logging"
+	"luznocturna/luz-nocturna/internal/models"
+	"luznocturna/luz-nocturna/internal/system"
+	"sync"
+)
+
+/**
+ * sessionLockState - Bloqueo de la sesión y su seguimiento
+ *
+ * Mientras la sesión está bloqueada runApply convierte cualquier petición
+ * en un reset, sin tocar el estado del usuario: al desbloquear basta con
+ * reaplicar el último estado.
+ *
+ * @struct {sessionLockState}
+ * @property {bool} locked - La sesión está bloqueada y el filtro retirado
+ * @property {*system.SessionLockWatcher} watcher - Seguimiento del bloqueo (nil si la opción está desactivada)
+ */
+type sessionLockState struct {
+	mu      sync.Mutex
+	locked  bool
+	watcher *system.SessionLockWatcher
+}
+
+// isLocked indica si el filtro está retirado por el bloqueo de la sesión
+func (s *sessionLockState) isLocked() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.locked
+}
+
+// set cambia el estado de bloqueo e indica si es distinto del anterior
+func (s *sessionLockState) set(locked bool) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	changed := s.locked != locked
+	s.locked = locked
+	return changed
+}
+
+// startSessionLock empieza a seguir el bloqueo de la sesión si la opción está activada
+func (c *NightLightController) startSessionLock() {
+	if !c.IsResetWhileLocked() {
+		return
+	}
+
+	c.sessionLock.mu.Lock()
+	defer c.sessionLock.mu.Unlock()
+	if c.sessionLock.watcher != nil {
+		return
+	}
+	watcher, err := system.StartSessionLockWatcher(c.onSessionLockChanged)
+	if err != nil {
+		logging.Printf("⚠️  No se restaurará la gamma al bloquear la sesión: %v\n", err)
+		return
+	}
+	c.sessionLock.watcher = watcher
+}
+
+// stopSessionLock detiene el seguimiento; indica si la sesión constaba como bloqueada
+func (c *NightLightController) stopSessionLock() bool {
+	c.sessionLock.mu.Lock()
+	if c.sessionLock.watcher != nil {
+		c.sessionLock.watcher.Stop()
+		c.sessionLock.watcher = nil
+	}
+	c.sessionLock.mu.Unlock()
+
+	return c.sessionLock.set(false)
+}
+
+/**
+ * onSessionLockChanged - Retira el filtro al bloquear y lo reaplica al desbloquear
+ *
+ * @param {bool} locked - true si la sesión se acaba de bloquear
+ * @callback - Seguimiento del bloqueo de la sesión
+ */
+func (c *NightLightController) onSessionLockChanged(locked bool) {
+	if !c.sessionLock.set(locked) {
+		return
+	}
+
+	if locked {
+		logging.Println("🔒 Sesión bloqueada: gamma normal")
+	} else {
+		logging.Println("🔓 Sesión desbloqueada: reaplicando el filtro")
+	}
+	c.reapplyLastState()
+}
+
+// IsResetWhileLocked indica si el filtro se retira mientras la sesión está bloqueada
+func (c *NightLightController) IsResetWhileLocked() bool {
+	return c.currentAppConfig().ResetWhileLocked
+}
+
+// SetResetWhileLocked activa o desactiva la restauración de la gamma con la sesión bloqueada
+func (c *NightLightController) SetResetWhileLocked(enabled bool) error {
+	err := c.updateAppConfig(func(config *models.AppConfig) { config.ResetWhileLocked = enabled })
+	if enabled {
+		c.startSessionLock()
+	} else if c.stopSessionLock() {
+		c.reapplyLastState()
+	}
+	return err
+}
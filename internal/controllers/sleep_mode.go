@@ -0,0 +1,17 @@
+package controllers
+
+import "time"
+
+// sleepDesaturation devuelve cuánto color retira la fase de sueño ahora mismo
+// (0 fuera de ella y durante la calibración, que necesita ver el color)
+func (c *NightLightController) sleepDesaturation() float64 {
+	if !c.IsSleepModeActive() || c.calibration.isActive() {
+		return 0
+	}
+	return 1 - c.GetScheduleConfig().SleepMode.Saturation
+}
+
+// IsSleepModeActive indica si la programación está en la fase de sueño (escala de grises)
+func (c *NightLightController) IsSleepModeActive() bool {
+	return c.scheduler.IsRunning() && c.scheduler.SleepModeAt(time.Now())
+}
@@ -0,0 +1,49 @@
+package controllers
+
+import (
+	"luznocturna/luz-nocturna/internal/logging"
+	"luznocturna/luz-nocturna/internal/models"
+	"time"
+)
+
+// GetVacation devuelve el período de vacaciones configurado
+func (c *NightLightController) GetVacation() models.VacationConfig {
+	return c.currentAppConfig().Vacation
+}
+
+// IsOnVacation indica si hoy está en el período de vacaciones y cuándo termina
+func (c *NightLightController) IsOnVacation() (time.Time, bool) {
+	return c.GetVacation().Active(time.Now())
+}
+
+/**
+ * SetVacation - Cambia (o quita, con fechas vacías) el período de vacaciones
+ *
+ * Si el período deja de incluir hoy y la programación está habilitada,
+ * se aplica enseguida la temperatura del horario en lugar de esperar al
+ * siguiente tic.
+ *
+ * @param {models.VacationConfig} vacation - Fechas AAAA-MM-DD, ambas incluidas
+ * @returns {error} Error si las fechas no son válidas o no se pudo guardar
+ */
+func (c *NightLightController) SetVacation(vacation models.VacationConfig) error {
+	if err := vacation.Validate(); err != nil {
+		return err
+	}
+
+	_, wasPaused := c.IsOnVacation()
+	err := c.updateAppConfig(func(config *models.AppConfig) { config.Vacation = vacation })
+	end, paused := c.IsOnVacation()
+	switch {
+	case paused:
+		logging.Printf("🏖️  Modo vacaciones hasta el %s: programación y vigilante en pausa\n", end.Format("02/01/2006"))
+	case wasPaused:
+		logging.Println("🏖️  Fin del modo vacaciones: se reanuda la programación")
+		if c.IsScheduleEnabled() {
+			c.ApplyScheduleNow()
+		}
+	}
+
+	c.publish(EventVacationChanged, "manual")
+	return err
+}
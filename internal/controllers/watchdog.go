@@ -0,0 +1,74 @@
+package controllers
+
+import (
+	"sync"
+	"time"
+)
+
+/**
+ * gammaWatchdog - Vigilante periódico de la gamma aplicada
+ *
+ * Cada intervalo llama a check, que comprueba si la gamma que la
+ * aplicación quiere mantener sigue en efecto y la reaplica si no.
+ * A diferencia del control exclusivo no toca a otros programas: solo
+ * restaura nuestro propio estado.
+ *
+ * @struct {gammaWatchdog}
+ * @property {func()} check - Comprobación a ejecutar en cada intervalo
+ * @property {chan struct{}} stop - Canal que detiene la goroutine activa (nil si está parado)
+ */
+type gammaWatchdog struct {
+	mu    sync.Mutex
+	check func()
+	stop  chan struct{}
+}
+
+// newGammaWatchdog crea un vigilante detenido
+func newGammaWatchdog(check func()) *gammaWatchdog {
+	return &gammaWatchdog{check: check}
+}
+
+// Start inicia (o reinicia con otro intervalo) la vigilancia
+func (w *gammaWatchdog) Start(interval time.Duration) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.stopLocked()
+	stop := make(chan struct{})
+	w.stop = stop
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				w.check()
+			}
+		}
+	}()
+}
+
+// Stop detiene la vigilancia; es seguro llamarlo aunque no esté activa
+func (w *gammaWatchdog) Stop() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.stopLocked()
+}
+
+// IsRunning indica si la vigilancia está activa
+func (w *gammaWatchdog) IsRunning() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.stop != nil
+}
+
+// stopLocked cierra el canal de la goroutine activa (requiere w.mu)
+func (w *gammaWatchdog) stopLocked() {
+	if w.stop != nil {
+		close(w.stop)
+		w.stop = nil
+	}
+}
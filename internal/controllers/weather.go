@@ -0,0 +1,126 @@
+package controllers
+
+import (
+	"context"
+	"luznocturna/luz-nocturna/internal/logging"
+	"luznocturna/luz-nocturna/internal/models"
+	"luznocturna/luz-nocturna/internal/weather"
+	"sync"
+	"time"
+)
+
+/**
+ * weatherState - Consulta periódica de la nubosidad para la programación
+ *
+ * El resultado se traduce en un ajuste de la temperatura diurna del
+ * programador (Scheduler.SetDayBias); el siguiente tic del programador
+ * ya lo aplica, así que aquí no se toca la gamma.
+ *
+ * @struct {weatherState}
+ * @property {float64} cloudCover - Última nubosidad obtenida (0-100)
+ * @property {time.Time} updated - Momento de la última consulta correcta (cero si no hubo ninguna)
+ * @property {context.CancelFunc} cancel - Detiene las consultas (nil si el ajuste está desactivado)
+ */
+type weatherState struct {
+	mu         sync.Mutex
+	cloudCover float64
+	updated    time.Time
+	cancel     context.CancelFunc
+}
+
+// startWeather empieza a consultar la nubosidad si el ajuste está activado
+func (c *NightLightController) startWeather() {
+	if !c.GetWeather().Enabled {
+		return
+	}
+
+	c.weather.mu.Lock()
+	defer c.weather.mu.Unlock()
+	if c.weather.cancel != nil {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	c.weather.cancel = cancel
+
+	go func() {
+		ticker := time.NewTicker(models.WeatherRefreshInterval)
+		defer ticker.Stop()
+
+		for {
+			c.refreshWeather(ctx)
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// stopWeather detiene las consultas y quita el ajuste de la temperatura diurna
+func (c *NightLightController) stopWeather() {
+	c.weather.mu.Lock()
+	defer c.weather.mu.Unlock()
+	if c.weather.cancel != nil {
+		c.weather.cancel()
+		c.weather.cancel = nil
+	}
+	c.scheduler.SetDayBias(0)
+}
+
+/**
+ * refreshWeather - Consulta la nubosidad y ajusta la temperatura diurna
+ *
+ * Si la consulta falla se conserva el ajuste anterior: un corte de red
+ * no debe cambiar la temperatura de golpe.
+ *
+ * @param {context.Context} ctx - Contexto de las consultas
+ * @private
+ */
+func (c *NightLightController) refreshWeather(ctx context.Context) {
+	config := c.currentAppConfig()
+	cover, err := weather.CloudCover(ctx, config.Schedule.Location, config.Weather.APIKey)
+	if err != nil {
+		if ctx.Err() == nil {
+			logging.Printf("⚠️  No se pudo consultar la nubosidad: %v\n", err)
+		}
+		return
+	}
+
+	bias := config.Weather.DayBias(cover)
+	c.scheduler.SetDayBias(bias)
+
+	c.weather.mu.Lock()
+	c.weather.cloudCover, c.weather.updated = cover, time.Now()
+	c.weather.mu.Unlock()
+	logging.Printf("🌥️  Nubosidad %.0f%%: temperatura diurna %.0fK más cálida\n", cover, bias)
+}
+
+// GetWeather devuelve la configuración del ajuste por nubosidad
+func (c *NightLightController) GetWeather() models.WeatherConfig {
+	return c.currentAppConfig().Weather
+}
+
+// GetCloudCover devuelve la última nubosidad obtenida y cuándo; false si aún no hay datos
+func (c *NightLightController) GetCloudCover() (float64, time.Time, bool) {
+	c.weather.mu.Lock()
+	defer c.weather.mu.Unlock()
+	return c.weather.cloudCover, c.weather.updated, !c.weather.updated.IsZero()
+}
+
+/**
+ * SetWeather - Cambia el ajuste por nubosidad y reinicia las consultas
+ *
+ * @param {models.WeatherConfig} config - Nueva configuración
+ * @returns {error} Error si la configuración no es válida o no se pudo guardar
+ */
+func (c *NightLightController) SetWeather(config models.WeatherConfig) error {
+	if err := config.Validate(); err != nil {
+		return err
+	}
+
+	err := c.updateAppConfig(func(app *models.AppConfig) { app.Weather = config })
+	c.stopWeather()
+	c.startWeather()
+	return err
+}
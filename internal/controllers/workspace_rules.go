@@ -0,0 +1,186 @@
+package controllers
+
+import (
+	"luznocturna/luz-nocturna/internal/logging"
+	"luznocturna/luz-nocturna/internal/models"
+	"luznocturna/luz-nocturna/internal/system"
+	"sync"
+)
+
+// workspaceFadeSteps es el número de pasos del fundido al cambiar de espacio de trabajo.
+// Con el intervalo mínimo de la cola (100ms) el fundido dura unos 400ms.
+const workspaceFadeSteps = 4
+
+/**
+ * workspaceRuleState - Regla por espacio de trabajo en vigor y seguimiento de Sway/i3
+ *
+ * Igual que las reglas por aplicación, runApply la sustituye al llegar al
+ * backend sin tocar el estado del usuario. Una regla por aplicación en
+ * vigor tiene prioridad sobre la del espacio de trabajo.
+ *
+ * @struct {workspaceRuleState}
+ * @property {*models.WorkspaceRule} active - Regla del espacio de trabajo enfocado (nil si ninguna coincide)
+ * @property {*float64} fading - Temperatura intermedia mientras dura un fundido (nil fuera de él)
+ * @property {*system.WorkspaceWatcher} watcher - Seguimiento del espacio de trabajo (nil si no hay reglas)
+ */
+type workspaceRuleState struct {
+	mu      sync.Mutex
+	active  *models.WorkspaceRule
+	fading  *float64
+	watcher *system.WorkspaceWatcher
+}
+
+// current devuelve la regla en vigor y la temperatura del fundido en curso
+func (s *workspaceRuleState) current() (*models.WorkspaceRule, *float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.active, s.fading
+}
+
+// setFading fija la temperatura intermedia del fundido (nil al terminar)
+func (s *workspaceRuleState) setFading(temperature *float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fading = temperature
+}
+
+// set cambia la regla en vigor, termina el fundido e indica si la regla es distinta de la anterior
+func (s *workspaceRuleState) set(rule *models.WorkspaceRule) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fading = nil
+	if (s.active == nil && rule == nil) || (s.active != nil && rule != nil && *s.active == *rule) {
+		return false
+	}
+	s.active = rule
+	return true
+}
+
+// startWorkspaceRules empieza a seguir el espacio de trabajo si hay reglas configuradas
+func (c *NightLightController) startWorkspaceRules() {
+	if len(c.GetWorkspaceRules()) == 0 {
+		return
+	}
+
+	c.workspaceRules.mu.Lock()
+	defer c.workspaceRules.mu.Unlock()
+	if c.workspaceRules.watcher != nil {
+		return
+	}
+	watcher, err := system.StartWorkspaceWatcher(c.onWorkspaceChanged)
+	if err != nil {
+		logging.Printf("⚠️  Las reglas por espacio de trabajo no funcionarán: %v\n", err)
+		return
+	}
+	c.workspaceRules.watcher = watcher
+}
+
+// stopWorkspaceRules detiene el seguimiento y quita la regla en vigor; indica si había una
+func (c *NightLightController) stopWorkspaceRules() bool {
+	c.workspaceRules.mu.Lock()
+	if c.workspaceRules.watcher != nil {
+		c.workspaceRules.watcher.Stop()
+		c.workspaceRules.watcher = nil
+	}
+	c.workspaceRules.mu.Unlock()
+
+	return c.workspaceRules.set(nil)
+}
+
+/**
+ * onWorkspaceChanged - Aplica o retira la regla al cambiar de espacio de trabajo
+ *
+ * Si el filtro está activo y ninguna regla por aplicación manda, pasa a
+ * la nueva temperatura con un fundido corto en vez de un salto.
+ *
+ * @param {string} name - Nombre del espacio de trabajo enfocado
+ * @callback - Seguimiento del espacio de trabajo
+ */
+func (c *NightLightController) onWorkspaceChanged(name string) {
+	var next *models.WorkspaceRule
+	if rule, found := models.FindWorkspaceRule(c.GetWorkspaceRules(), name); found {
+		next = &rule
+	}
+	previous, _ := c.workspaceRules.current()
+	if (previous == nil && next == nil) || (previous != nil && next != nil && *previous == *next) {
+		return
+	}
+
+	switch {
+	case next == nil:
+		logging.Printf("🗂️  Espacio de trabajo %s: temperatura del usuario\n", name)
+	case next.Disable:
+		logging.Printf("🗂️  Espacio de trabajo %s: filtro desactivado\n", name)
+	default:
+		logging.Printf("🗂️  Espacio de trabajo %s: %.0fK\n", name, next.Temperature)
+	}
+
+	c.fadeWorkspace(c.workspaceTemperature(previous), c.workspaceTemperature(next))
+	c.workspaceRules.set(next)
+	c.reapplyLastState()
+}
+
+/**
+ * fadeWorkspace - Aplica los pasos intermedios entre dos temperaturas
+ *
+ * El último paso lo aplica quien llama al fijar la nueva regla. Cada
+ * paso espera a la cola, así que el ritmo lo marca su intervalo mínimo.
+ *
+ * @param {float64} from - Temperatura visible antes del cambio
+ * @param {float64} to - Temperatura visible después del cambio
+ * @private
+ */
+func (c *NightLightController) fadeWorkspace(from, to float64) {
+	if !c.lastState().Active || from == to {
+		return
+	}
+	if _, found := c.appRules.current(); found {
+		return // La regla por aplicación tapa el cambio
+	}
+
+	fade(workspaceFadeSteps, 0, func(progress float64) bool {
+		temperature := from + (to-from)*progress
+		c.workspaceRules.setFading(&temperature)
+		c.reapplyLastState()
+		return true
+	})
+}
+
+// workspaceTemperature devuelve la temperatura visible con una regla (la neutra si desactiva el filtro)
+func (c *NightLightController) workspaceTemperature(rule *models.WorkspaceRule) float64 {
+	switch {
+	case rule == nil:
+		return c.lastState().Temperature
+	case rule.Disable:
+		return models.DaylightTemp
+	default:
+		return rule.Temperature
+	}
+}
+
+// GetWorkspaceRules devuelve las reglas por espacio de trabajo configuradas
+func (c *NightLightController) GetWorkspaceRules() []models.WorkspaceRule {
+	return append([]models.WorkspaceRule(nil), c.currentAppConfig().WorkspaceRules...)
+}
+
+/**
+ * SetWorkspaceRules - Reemplaza las reglas por espacio de trabajo y reinicia el seguimiento
+ *
+ * @param {[]models.WorkspaceRule} rules - Nuevas reglas, en orden de prioridad
+ * @returns {error} Error si alguna regla no es válida o no se pudo guardar
+ */
+func (c *NightLightController) SetWorkspaceRules(rules []models.WorkspaceRule) error {
+	minTemp, maxTemp := c.GetTemperatureRange()
+	for _, rule := range rules {
+		if err := rule.Validate(minTemp, maxTemp); err != nil {
+			return err
+		}
+	}
+
+	err := c.updateAppConfig(func(config *models.AppConfig) { config.WorkspaceRules = rules })
+	if c.stopWorkspaceRules() {
+		c.reapplyLastState()
+	}
+	c.startWorkspaceRules()
+	return err
+}
@@ -0,0 +1,205 @@
+package diagnostics
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/user"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"luznocturna/luz-nocturna/internal/logging"
+	"luznocturna/luz-nocturna/internal/models"
+	"luznocturna/luz-nocturna/internal/system"
+	"luznocturna/luz-nocturna/internal/version"
+)
+
+// redacted sustituye los valores privados en el paquete de diagnóstico
+const redacted = "<oculto>"
+
+// DefaultFileName devuelve el nombre propuesto para el paquete ("luz-nocturna-diagnostico-AAAAMMDD-HHMMSS.tar.gz")
+func DefaultFileName(now time.Time) string {
+	return "luz-nocturna-diagnostico-" + now.Format("20060102-150405") + ".tar.gz"
+}
+
+/**
+ * WriteBundle - Escribe el paquete de diagnóstico como tar.gz
+ *
+ * Contiene info.txt (versión, sistema y los datos de session),
+ * environment.json (system.ProbeEnvironment), config.json sin datos
+ * privados y logs.txt con los últimos registros. Las rutas del directorio
+ * personal y el nombre de usuario se sustituyen en todos los archivos.
+ *
+ * @param {io.Writer} w - Destino del tar.gz
+ * @param {map[string]string} session - Estado de la instancia en marcha (backend, temperatura...); nil desde la CLI
+ * @returns {error} Error si no se pudo escribir algún archivo
+ * @example
+ *   file, _ := os.Create(diagnostics.DefaultFileName(time.Now()))
+ *   defer file.Close()
+ *   err := diagnostics.WriteBundle(file, map[string]string{"backend": "randr"})
+ */
+func WriteBundle(w io.Writer, session map[string]string) error {
+	now := time.Now()
+	gz := gzip.NewWriter(w)
+	archive := tar.NewWriter(gz)
+
+	environment, err := json.MarshalIndent(system.ProbeEnvironment(), "", "  ")
+	if err != nil {
+		return err
+	}
+	config, err := sanitizedConfig()
+	if err != nil {
+		config = []byte(fmt.Sprintf("no se pudo leer la configuración: %v\n", err))
+	}
+
+	files := []struct {
+		name string
+		data []byte
+	}{
+		{"info.txt", []byte(info(now, session))},
+		{"environment.json", environment},
+		{"config.json", config},
+		{"logs.txt", []byte(strings.Join(logging.Recent(), "\n") + "\n")},
+	}
+	for _, file := range files {
+		data := []byte(sanitize(string(file.data)))
+		header := &tar.Header{Name: file.name, Mode: 0644, Size: int64(len(data)), ModTime: now}
+		if err := archive.WriteHeader(header); err != nil {
+			return err
+		}
+		if _, err := archive.Write(data); err != nil {
+			return err
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+/**
+ * ExportBundle - Crea el paquete de diagnóstico en un archivo
+ *
+ * @param {string} path - Ruta del tar.gz; si es un directorio se usa DefaultFileName dentro de él
+ * @param {map[string]string} session - Estado de la instancia en marcha (nil desde la CLI)
+ * @returns {string, error} Ruta del archivo creado y error
+ */
+func ExportBundle(path string, session map[string]string) (string, error) {
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		path = filepath.Join(path, DefaultFileName(time.Now()))
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return "", fmt.Errorf("no se pudo crear %s: %w", path, err)
+	}
+	if err := WriteBundle(file, session); err != nil {
+		file.Close()
+		os.Remove(path)
+		return "", fmt.Errorf("no se pudo escribir el paquete de diagnóstico: %w", err)
+	}
+	return path, file.Close()
+}
+
+// info describe la versión, el sistema y el estado de la instancia en marcha
+func info(now time.Time, session map[string]string) string {
+	var text strings.Builder
+	fmt.Fprintf(&text, "Luz Nocturna %s\n", version.String())
+	fmt.Fprintf(&text, "Generado: %s\n", now.Format(time.RFC3339))
+	fmt.Fprintf(&text, "Sistema: %s/%s, %s\n", runtime.GOOS, runtime.GOARCH, runtime.Version())
+	if release, err := os.ReadFile("/etc/os-release"); err == nil {
+		for _, line := range strings.Split(string(release), "\n") {
+			if value, found := strings.CutPrefix(line, "PRETTY_NAME="); found {
+				fmt.Fprintf(&text, "Distribución: %s\n", strings.Trim(value, `"`))
+			}
+		}
+	}
+
+	if len(session) > 0 {
+		keys := make([]string, 0, len(session))
+		for key := range session {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		text.WriteString("\nInstancia en marcha:\n")
+		for _, key := range keys {
+			fmt.Fprintf(&text, "  %s: %s\n", key, session[key])
+		}
+	}
+	return text.String()
+}
+
+/**
+ * sanitizedConfig - config.json sin datos privados
+ *
+ * Se ocultan la ubicación, la clave del tiempo y los comandos de los
+ * hooks (pueden llevar rutas o credenciales); del resto solo importa la
+ * forma, que es lo que ayuda a reproducir un fallo.
+ *
+ * @returns {[]byte, error} JSON indentado o error si no se pudo leer
+ * @private
+ */
+func sanitizedConfig() ([]byte, error) {
+	// Se lee el archivo directamente: Load lo crearía o lo apartaría como dañado
+	data, err := os.ReadFile(models.GetConfigPath())
+	if err != nil {
+		return nil, err
+	}
+	config := models.NewAppConfig()
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("config.json dañado: %w", err)
+	}
+
+	if config.Schedule.Location.IsSet() {
+		config.Schedule.Location = models.Location{}
+	}
+	if config.Weather.APIKey != "" {
+		config.Weather.APIKey = redacted
+	}
+	for _, command := range []*string{&config.Hooks.OnApply, &config.Hooks.OnReset, &config.Hooks.OnNightStart, &config.Hooks.OnDayStart} {
+		if *command != "" {
+			*command = redacted
+		}
+	}
+	return json.MarshalIndent(config, "", "  ")
+}
+
+// Datos privados que pueden aparecer en los registros: la consulta de una
+// URL (clave de la API, coordenadas), claves sueltas y coordenadas
+var (
+	urlQueryRegex   = regexp.MustCompile(`(https?://[^\s?"']+)\?[^\s"']*`)
+	apiKeyRegex     = regexp.MustCompile(`(?i)\b(appid|api_?key|key|token)=[^&\s"']+`)
+	coordinateRegex = regexp.MustCompile(`(?i)\b(lat|lon|latitude|longitude)=-?\d+(\.\d+)?`)
+)
+
+/**
+ * sanitize - Quita los datos privados de un texto del paquete
+ *
+ * Oculta el directorio personal, el nombre de usuario, la consulta de
+ * las URL, las claves de API y las coordenadas.
+ *
+ * @param {string} text - Texto original
+ * @returns {string} Texto sin datos privados
+ * @private
+ */
+func sanitize(text string) string {
+	text = urlQueryRegex.ReplaceAllString(text, "$1?"+redacted)
+	text = apiKeyRegex.ReplaceAllString(text, "$1="+redacted)
+	text = coordinateRegex.ReplaceAllString(text, "$1="+redacted)
+
+	if home, err := os.UserHomeDir(); err == nil && home != "" && home != "/" {
+		text = strings.ReplaceAll(text, home, "~")
+	}
+	// Solo palabras completas: el usuario "root" no debe tocar "xsetroot"
+	if current, err := user.Current(); err == nil && len(current.Username) > 2 {
+		text = regexp.MustCompile(`\b`+regexp.QuoteMeta(current.Username)+`\b`).ReplaceAllString(text, "<usuario>")
+	}
+	return text
+}
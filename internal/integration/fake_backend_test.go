@@ -0,0 +1,199 @@
+//go:build integration
+
+package integration
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"luznocturna/luz-nocturna/internal/models"
+	"luznocturna/luz-nocturna/internal/system"
+)
+
+func TestApplyAndReset(t *testing.T) {
+	controller, backend := newFakeController(t)
+
+	controller.UpdateTemperature(3400)
+	if err := controller.ApplyNightLight(); err != nil {
+		t.Fatalf("ApplyNightLight: %v", err)
+	}
+	if temp, _, active := backend.State(); temp != 3400 || !active {
+		t.Fatalf("tras aplicar: %.0fK activo=%v, se esperaba 3400K activo", temp, active)
+	}
+	if !controller.GetConfig().IsActive {
+		t.Error("el modelo no quedó marcado como activo")
+	}
+
+	if err := controller.ResetNightLight(); err != nil {
+		t.Fatalf("ResetNightLight: %v", err)
+	}
+	if temp, _, active := backend.State(); temp != 6500 || active {
+		t.Fatalf("tras resetear: %.0fK activo=%v, se esperaba 6500K inactivo", temp, active)
+	}
+
+	// Deshacer vuelve a aplicar exactamente el estado anterior
+	if err := controller.Undo(); err != nil {
+		t.Fatalf("Undo: %v", err)
+	}
+	if temp, _, active := backend.State(); temp != 3400 || !active {
+		t.Fatalf("tras deshacer: %.0fK activo=%v, se esperaba 3400K activo", temp, active)
+	}
+}
+
+func TestApplyFailures(t *testing.T) {
+	controller, backend := newFakeController(t)
+
+	backend.FailNextApplies(system.ErrNoBackend)
+	if err := controller.ApplyNightLight(); !errors.Is(err, system.ErrNoBackend) {
+		t.Fatalf("se esperaba ErrNoBackend, se obtuvo %v", err)
+	}
+	if controller.GetConfig().IsActive {
+		t.Error("un fallo total no debe marcar el filtro como activo")
+	}
+
+	// Un fallo parcial deja el filtro activo pero informa del error
+	backend.FailNextApplies(&system.PartialApplyError{Applied: []string{"FAKE-1"}, Failed: []string{"FAKE-2"}})
+	if err := controller.ApplyNightLight(); !errors.Is(err, system.ErrPartialApply) {
+		t.Fatalf("se esperaba ErrPartialApply, se obtuvo %v", err)
+	}
+	if !controller.GetConfig().IsActive {
+		t.Error("un fallo parcial debe marcar el filtro como activo")
+	}
+
+	// El estado por display indica dónde se aplicó
+	statuses := controller.GetDisplayStatus()
+	if len(statuses) != 2 || !statuses[0].Applied || statuses[1].Applied || statuses[1].Error == "" {
+		t.Fatalf("estado por display tras el fallo parcial: %+v", statuses)
+	}
+	backend.FailNextApplies(nil)
+	if err := controller.ApplyNightLight(); err != nil {
+		t.Fatalf("ApplyNightLight: %v", err)
+	}
+	for _, status := range controller.GetDisplayStatus() {
+		if !status.Applied || status.Error != "" {
+			t.Errorf("%s tras aplicar: %+v", status.Display, status)
+		}
+	}
+}
+
+func TestScheduleAppliesNightTemperature(t *testing.T) {
+	controller, backend := newFakeController(t)
+
+	// Ventana nocturna alrededor de la hora actual, sin transición
+	now := time.Now()
+	start := now.Add(-time.Hour).Format("15:04")
+	end := now.Add(time.Hour).Format("15:04")
+	if err := controller.UpdateScheduleConfig(start, end, 3000, 6500, 0); err != nil {
+		t.Fatalf("UpdateScheduleConfig: %v", err)
+	}
+	controller.EnableSchedule(true)
+
+	applied := waitFor(t, 3*time.Second, func() bool {
+		temp, _, active := backend.State()
+		return temp == 3000 && active
+	})
+	if !applied {
+		temp, _, _ := backend.State()
+		t.Fatalf("el programador no aplicó la temperatura nocturna: %.0fK (llamadas: %v)", temp, backend.Calls())
+	}
+}
+
+func TestShutdownResetsAndClosesBackend(t *testing.T) {
+	controller, backend := newFakeController(t)
+
+	if err := controller.SetResetOnQuit(true); err != nil {
+		t.Fatalf("SetResetOnQuit: %v", err)
+	}
+	controller.UpdateTemperature(3400)
+	if err := controller.ApplyNightLight(); err != nil {
+		t.Fatalf("ApplyNightLight: %v", err)
+	}
+
+	controller.Shutdown()
+	if _, _, active := backend.State(); active {
+		t.Error("la gamma no se restauró al salir")
+	}
+	if !backend.IsClosed() {
+		t.Error("el backend no se cerró al salir")
+	}
+}
+
+func TestHooksReceiveState(t *testing.T) {
+	controller, _ := newFakeController(t)
+
+	out := filepath.Join(t.TempDir(), "hooks.log")
+	hooks := &controller.GetAppConfig().Hooks
+	hooks.OnApply = `echo "$LUZ_NOCTURNA_HOOK $LUZ_NOCTURNA_TEMPERATURE $LUZ_NOCTURNA_ACTIVE" >> ` + out
+	hooks.OnNightStart = `echo "$LUZ_NOCTURNA_HOOK $LUZ_NOCTURNA_PHASE" >> ` + out
+
+	controller.UpdateTemperature(3400)
+	if err := controller.ApplyNightLight(); err != nil {
+		t.Fatalf("ApplyNightLight: %v", err)
+	}
+
+	// El programador entra en la noche: on_night_start se ejecuta una sola vez
+	now := time.Now()
+	if err := controller.UpdateScheduleConfig(now.Add(-time.Hour).Format("15:04"), now.Add(time.Hour).Format("15:04"), 3000, 6500, 0); err != nil {
+		t.Fatalf("UpdateScheduleConfig: %v", err)
+	}
+	controller.EnableSchedule(true)
+
+	// Cada hook se ejecuta en su propia goroutine: el orden de las líneas puede variar
+	want := []string{"on_apply 3400 1", "on_night_start night"}
+	var got string
+	ran := waitFor(t, 3*time.Second, func() bool {
+		data, _ := os.ReadFile(out)
+		got = string(data)
+		return strings.Contains(got, want[0]+"\n") && strings.Contains(got, want[1]+"\n")
+	})
+	if !ran {
+		t.Fatalf("salida de los hooks:\n%s\nse esperaba: %q", got, want)
+	}
+	if strings.Count(got, "\n") != len(want) {
+		t.Errorf("los hooks se ejecutaron de más:\n%s", got)
+	}
+}
+
+func TestCalibrationRestoresPreviousState(t *testing.T) {
+	controller, backend := newFakeController(t)
+
+	controller.UpdateTemperature(3400)
+	if err := controller.ApplyNightLight(); err != nil {
+		t.Fatalf("ApplyNightLight: %v", err)
+	}
+
+	// Mientras se calibra se ve el blanco de referencia, aunque llegue otra aplicación
+	trim := models.ChannelTrim{Red: 1.0, Green: 0.95, Blue: 0.9}
+	session := controller.BeginCalibration()
+	if err := controller.PreviewCalibration(session, "FAKE-1", trim); err != nil {
+		t.Fatalf("PreviewCalibration: %v", err)
+	}
+	if err := controller.ApplyNightLight(); err != nil {
+		t.Fatalf("ApplyNightLight durante la calibración: %v", err)
+	}
+	if temp, _, active := backend.State(); temp != models.DaylightTemp || !active {
+		t.Fatalf("durante la calibración: %.0fK activo=%v, se esperaba %dK", temp, active, models.DaylightTemp)
+	}
+
+	if err := controller.FinishCalibration(true); err != nil {
+		t.Fatalf("FinishCalibration: %v", err)
+	}
+	if temp, _, _ := backend.State(); temp != 3400 {
+		t.Fatalf("tras calibrar: %.0fK, se esperaba volver a 3400K", temp)
+	}
+	if got := controller.GetChannelTrim("FAKE-1"); got != trim {
+		t.Errorf("ajuste guardado: %+v, se esperaba %+v", got, trim)
+	}
+
+	// Una vista previa atrasada de la sesión terminada no vuelve a empezar la calibración
+	if err := controller.PreviewCalibration(session, "FAKE-1", trim); err != nil {
+		t.Fatalf("PreviewCalibration atrasada: %v", err)
+	}
+	if temp, _, _ := backend.State(); temp != 3400 {
+		t.Errorf("tras una vista previa atrasada: %.0fK, se esperaba seguir en 3400K", temp)
+	}
+}
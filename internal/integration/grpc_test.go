@@ -0,0 +1,84 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+
+	"luznocturna/luz-nocturna/internal/ipc"
+	"luznocturna/luz-nocturna/internal/models"
+	luznocturnav1 "luznocturna/luz-nocturna/pkg/api/luznocturna/v1"
+)
+
+func TestGRPCSetTemperatureAndWatch(t *testing.T) {
+	controller, backend := newFakeController(t)
+
+	server, err := ipc.StartGRPCServer(controller, models.GRPCConfig{Enabled: true})
+	if err != nil {
+		t.Fatalf("StartGRPCServer: %v", err)
+	}
+	t.Cleanup(server.Close)
+
+	_, socket, _ := models.GRPCConfig{}.Endpoint()
+	conn, err := grpc.NewClient("unix://"+socket, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	client := luznocturnav1.NewNightLightServiceClient(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// El primer mensaje de Watch es el estado al suscribirse
+	watch, err := client.Watch(ctx, &luznocturnav1.WatchRequest{})
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+	first, err := watch.Recv()
+	if err != nil || first.Type != luznocturnav1.EventType_EVENT_TYPE_SNAPSHOT {
+		t.Fatalf("primer mensaje de Watch: %v, %v", first, err)
+	}
+
+	response, err := client.SetTemperature(ctx, &luznocturnav1.SetTemperatureRequest{Temperature: 3600, Apply: true})
+	if err != nil {
+		t.Fatalf("SetTemperature: %v", err)
+	}
+	if state := response.State; state.Temperature != 3600 || !state.Active {
+		t.Fatalf("estado devuelto: %.0fK activo=%v, se esperaba 3600K activo", state.Temperature, state.Active)
+	}
+	if temp, _, active := backend.State(); temp != 3600 || !active {
+		t.Fatalf("backend: %.0fK activo=%v, se esperaba 3600K activo", temp, active)
+	}
+
+	// Watch recibe la selección y después la aplicación
+	for _, want := range []luznocturnav1.EventType{
+		luznocturnav1.EventType_EVENT_TYPE_TEMPERATURE_CHANGED,
+		luznocturnav1.EventType_EVENT_TYPE_APPLIED,
+	} {
+		event, err := watch.Recv()
+		if err != nil {
+			t.Fatalf("Watch.Recv: %v", err)
+		}
+		if event.Type != want || event.State.Temperature != 3600 {
+			t.Fatalf("evento %v a %.0fK, se esperaba %v a 3600K", event.Type, event.State.Temperature, want)
+		}
+	}
+
+	_, err = client.SetTemperature(ctx, &luznocturnav1.SetTemperatureRequest{Temperature: 100})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("temperatura fuera de rango: se esperaba INVALID_ARGUMENT, se obtuvo %v", err)
+	}
+
+	state, err := client.GetState(ctx, &luznocturnav1.GetStateRequest{})
+	if err != nil || state.State.Temperature != 3600 {
+		t.Fatalf("GetState: %v, %v", state, err)
+	}
+}
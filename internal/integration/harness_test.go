@@ -0,0 +1,54 @@
+//go:build integration
+
+package integration
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"luznocturna/luz-nocturna/internal/controllers"
+	"luznocturna/luz-nocturna/internal/system"
+)
+
+// isolateXDG redirige configuración, estado y runtime a directorios temporales
+// para no tocar los archivos del usuario que ejecuta las pruebas
+func isolateXDG(t *testing.T) {
+	t.Helper()
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+}
+
+// newFakeController crea un controlador completo sobre un FakeBackend
+func newFakeController(t *testing.T) (*controllers.NightLightController, *system.FakeBackend) {
+	t.Helper()
+	isolateXDG(t)
+
+	backend := system.NewFakeBackend("FAKE-1", "FAKE-2")
+	controller := controllers.NewNightLightControllerWithOptions(controllers.ControllerOptions{Backend: backend})
+	t.Cleanup(controller.Shutdown)
+	return controller, backend
+}
+
+// requireDisplayTests omite la prueba salvo que el harness haya levantado un
+// display virtual: el backend real modifica la gamma del display en uso
+func requireDisplayTests(t *testing.T) {
+	t.Helper()
+	if os.Getenv("LUZ_NOCTURNA_INTEGRATION_DISPLAY") != "1" {
+		t.Skip("requiere el display virtual de scripts/integration-test.sh")
+	}
+}
+
+// waitFor espera hasta timeout a que cond sea cierta
+func waitFor(t *testing.T, timeout time.Duration, cond func() bool) bool {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if cond() {
+			return true
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	return cond()
+}
@@ -0,0 +1,70 @@
+package ipc
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/godbus/dbus/v5"
+
+	"luznocturna/luz-nocturna/internal/system"
+)
+
+/**
+ * CallRunningInstance - Invoca un método en la instancia que ya está en ejecución
+ *
+ * Permite que la línea de comandos actúe a través de la aplicación
+ * abierta, de modo que la ventana y la bandeja reflejen el cambio. Los
+ * errores NoBackend y Partial se traducen a system.ErrNoBackend y
+ * system.ErrPartialApply.
+ *
+ * @param {string} method - Nombre del método (sin interfaz), p. ej. "Toggle"
+ * @param {...interface{}} args - Argumentos del método
+ * @returns {bool, error} Si había una instancia y el error de la llamada
+ * @example
+ *   if found, err := ipc.CallRunningInstance("Toggle"); found {
+ *       return err
+ *   }
+ */
+func CallRunningInstance(method string, args ...interface{}) (bool, error) {
+	conn, err := dbus.ConnectSessionBus()
+	if err != nil {
+		return false, nil
+	}
+	defer conn.Close()
+
+	var hasOwner bool
+	if err := conn.BusObject().Call("org.freedesktop.DBus.NameHasOwner", 0, DBusName).Store(&hasOwner); err != nil || !hasOwner {
+		return false, nil
+	}
+
+	return true, callInstance(conn, method, args...)
+}
+
+// callInstance invoca un método del servicio y traduce sus errores D-Bus
+func callInstance(conn *dbus.Conn, method string, args ...interface{}) error {
+	call := conn.Object(DBusName, DBusPath).Call(DBusInterface+"."+method, 0, args...)
+	if call.Err == nil {
+		return nil
+	}
+
+	var dbusErr dbus.Error
+	if errors.As(call.Err, &dbusErr) {
+		switch dbusErr.Name {
+		case DBusErrorNoBackend:
+			return fmt.Errorf("%w: %s", system.ErrNoBackend, dbusErrorMessage(dbusErr))
+		case DBusErrorPartial:
+			return fmt.Errorf("%w: %s", system.ErrPartialApply, dbusErrorMessage(dbusErr))
+		}
+	}
+	return call.Err
+}
+
+// dbusErrorMessage extrae el mensaje de texto de un error D-Bus
+func dbusErrorMessage(err dbus.Error) string {
+	if len(err.Body) > 0 {
+		if message, ok := err.Body[0].(string); ok {
+			return message
+		}
+	}
+	return err.Name
+}
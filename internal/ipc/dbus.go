@@ -0,0 +1,231 @@
+package ipc
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/godbus/dbus/v5/introspect"
+
+	"luznocturna/luz-nocturna/internal/controllers"
+	"luznocturna/luz-nocturna/internal/logging"
+	"luznocturna/luz-nocturna/internal/system"
+)
+
+// Nombres públicos del servicio D-Bus
+const (
+	DBusName      = "com.luznocturna.LuzNocturna"
+	DBusPath      = dbus.ObjectPath("/com/luznocturna/LuzNocturna")
+	DBusInterface = "com.luznocturna.LuzNocturna"
+)
+
+// Errores D-Bus con significado propio (el resto usa org.freedesktop.DBus.Error.Failed)
+const (
+	DBusErrorNoBackend = DBusInterface + ".Error.NoBackend" // Ningún backend de gamma funcionó
+	DBusErrorPartial   = DBusInterface + ".Error.Partial"   // Gamma aplicada solo en algunos displays
+)
+
+// Descripción de introspección del objeto exportado
+const dbusIntrospection = `
+<node>
+	<interface name="` + DBusInterface + `">
+		<method name="GetState">
+			<arg direction="out" type="d" name="temperature"/>
+			<arg direction="out" type="b" name="active"/>
+		</method>
+		<method name="SetTemperature">
+			<arg direction="in" type="d" name="temperature"/>
+		</method>
+		<method name="Apply"/>
+		<method name="Reset"/>
+		<method name="Toggle"/>
+		<method name="SkipTonight"/>
+		<method name="CancelSkipTonight"/>
+		<method name="ToggleColorAccurate"/>
+		<signal name="TemperatureChanged">
+			<arg type="d" name="temperature"/>
+			<arg type="s" name="source"/>
+		</signal>
+		<signal name="Applied">
+			<arg type="d" name="temperature"/>
+			<arg type="s" name="source"/>
+		</signal>
+		<signal name="Reset"/>
+		<signal name="ScheduleTransition">
+			<arg type="d" name="temperature"/>
+		</signal>
+	</interface>` + introspect.IntrospectDeclarationString + `
+</node>`
+
+/**
+ * DBusService - Servicio D-Bus de sesión para controlar la aplicación
+ *
+ * Expone métodos para consultar y cambiar el estado, y reemite como
+ * señales D-Bus los eventos del bus interno del controlador para que
+ * widgets y scripts externos se mantengan sincronizados.
+ *
+ * @struct {DBusService}
+ * @property {*controllers.NightLightController} controller - Controlador principal
+ * @property {*dbus.Conn} conn - Conexión al bus de sesión
+ * @property {func()} unsubscribe - Cancela la suscripción al bus de eventos
+ * @property {sync.Mutex} mu - Serializa los métodos que cambian el estado
+ */
+type DBusService struct {
+	mu          sync.Mutex // godbus atiende cada llamada en su propia goroutine
+	controller  *controllers.NightLightController
+	conn        *dbus.Conn
+	unsubscribe func()
+}
+
+/**
+ * StartDBusService - Conecta al bus de sesión y publica el servicio
+ *
+ * @param {*controllers.NightLightController} controller - Controlador principal
+ * @returns {*DBusService, error} Servicio activo o error si no hay bus/nombre
+ * @example
+ *   service, err := ipc.StartDBusService(controller)
+ *   if err == nil {
+ *       defer service.Close()
+ *   }
+ */
+func StartDBusService(controller *controllers.NightLightController) (*DBusService, error) {
+	conn, err := dbus.ConnectSessionBus()
+	if err != nil {
+		return nil, fmt.Errorf("no se pudo conectar al bus de sesión: %w", err)
+	}
+
+	service := &DBusService{controller: controller, conn: conn}
+
+	if err := conn.Export(service, DBusPath, DBusInterface); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if err := conn.Export(introspect.Introspectable(dbusIntrospection), DBusPath,
+		"org.freedesktop.DBus.Introspectable"); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	reply, err := conn.RequestName(DBusName, dbus.NameFlagDoNotQueue)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if reply != dbus.RequestNameReplyPrimaryOwner {
+		conn.Close()
+		return nil, fmt.Errorf("el nombre %s ya está en uso (¿otra instancia?)", DBusName)
+	}
+
+	service.unsubscribe = controller.Subscribe(service.emit)
+	logging.Printf("📡 Servicio D-Bus disponible: %s\n", DBusName)
+	return service, nil
+}
+
+/**
+ * Close - Retira el servicio y cierra la conexión
+ */
+func (s *DBusService) Close() {
+	if s.unsubscribe != nil {
+		s.unsubscribe()
+	}
+	s.conn.Close()
+}
+
+/**
+ * emit - Reemite un evento interno como señal D-Bus
+ *
+ * @param {controllers.Event} event - Evento del controlador
+ * @private
+ */
+func (s *DBusService) emit(event controllers.Event) {
+	var err error
+	switch event.Type {
+	case controllers.EventTemperatureChanged:
+		err = s.conn.Emit(DBusPath, DBusInterface+".TemperatureChanged", event.Temperature, event.Source)
+	case controllers.EventApplied:
+		err = s.conn.Emit(DBusPath, DBusInterface+".Applied", event.Temperature, event.Source)
+	case controllers.EventReset:
+		err = s.conn.Emit(DBusPath, DBusInterface+".Reset")
+	case controllers.EventScheduleTransition:
+		err = s.conn.Emit(DBusPath, DBusInterface+".ScheduleTransition", event.Temperature)
+	}
+	if err != nil {
+		logging.Printf("⚠️  No se pudo emitir señal D-Bus %s: %v\n", event.Type, err)
+	}
+}
+
+// === MÉTODOS EXPORTADOS ===
+
+// GetState devuelve la temperatura actual y si el filtro está activo
+func (s *DBusService) GetState() (float64, bool, *dbus.Error) {
+	config := s.controller.GetConfig()
+	return config.Temperature, config.IsActive, nil
+}
+
+// SetTemperature selecciona una temperatura (sin aplicarla)
+func (s *DBusService) SetTemperature(temperature float64) *dbus.Error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.controller.UpdateTemperature(temperature)
+	return nil
+}
+
+// Apply aplica la temperatura seleccionada
+func (s *DBusService) Apply() *dbus.Error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return toDBusError(s.controller.ApplyNightLight())
+}
+
+// Reset restaura la gamma normal
+func (s *DBusService) Reset() *dbus.Error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return toDBusError(s.controller.ResetNightLight())
+}
+
+// Toggle alterna el filtro
+func (s *DBusService) Toggle() *dbus.Error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return toDBusError(s.controller.ToggleNightLight())
+}
+
+// SkipTonight omite el período nocturno de hoy
+func (s *DBusService) SkipTonight() *dbus.Error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err := s.controller.SkipTonight()
+	return toDBusError(err)
+}
+
+// CancelSkipTonight vuelve a aplicar la noche de hoy si se había omitido
+func (s *DBusService) CancelSkipTonight() *dbus.Error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.controller.CancelSkipTonight()
+	return nil
+}
+
+// ToggleColorAccurate activa o termina el modo de color fiel
+func (s *DBusService) ToggleColorAccurate() *dbus.Error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.controller.ToggleColorAccurate()
+	return nil
+}
+
+// toDBusError convierte un error de Go en error D-Bus
+func toDBusError(err error) *dbus.Error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, system.ErrNoBackend):
+		return dbus.NewError(DBusErrorNoBackend, []interface{}{err.Error()})
+	case errors.Is(err, system.ErrPartialApply):
+		return dbus.NewError(DBusErrorPartial, []interface{}{err.Error()})
+	default:
+		return dbus.MakeFailedError(err)
+	}
+}
@@ -0,0 +1,230 @@
+package ipc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"luznocturna/luz-nocturna/internal/controllers"
+	"luznocturna/luz-nocturna/internal/logging"
+	"luznocturna/luz-nocturna/internal/models"
+	"luznocturna/luz-nocturna/internal/system"
+	luznocturnav1 "luznocturna/luz-nocturna/pkg/api/luznocturna/v1"
+)
+
+// watchBuffer es cuántos eventos puede acumular un cliente de Watch lento
+// antes de que se cierre su stream
+const watchBuffer = 32
+
+// Tipos de evento del controlador en la API gRPC
+var grpcEventTypes = map[controllers.EventType]luznocturnav1.EventType{
+	controllers.EventTemperatureChanged: luznocturnav1.EventType_EVENT_TYPE_TEMPERATURE_CHANGED,
+	controllers.EventApplied:            luznocturnav1.EventType_EVENT_TYPE_APPLIED,
+	controllers.EventReset:              luznocturnav1.EventType_EVENT_TYPE_RESET,
+	controllers.EventScheduleTransition: luznocturnav1.EventType_EVENT_TYPE_SCHEDULE_TRANSITION,
+	controllers.EventPresetsChanged:     luznocturnav1.EventType_EVENT_TYPE_PRESETS_CHANGED,
+	controllers.EventDisplaysChanged:    luznocturnav1.EventType_EVENT_TYPE_DISPLAYS_CHANGED,
+	controllers.EventApplyFailed:        luznocturnav1.EventType_EVENT_TYPE_APPLY_FAILED,
+	controllers.EventMovieModeChanged:   luznocturnav1.EventType_EVENT_TYPE_MOVIE_MODE_CHANGED,
+	controllers.EventSkipTonightChanged: luznocturnav1.EventType_EVENT_TYPE_SKIP_TONIGHT_CHANGED,
+	controllers.EventVacationChanged:    luznocturnav1.EventType_EVENT_TYPE_VACATION_CHANGED,
+}
+
+/**
+ * GRPCServer - Servidor gRPC opcional para controlar la aplicación
+ *
+ * Implementa luznocturna.v1.NightLightService sobre el mismo controlador
+ * que el servicio D-Bus: GetState, SetTemperature y un stream Watch con
+ * los eventos del bus interno.
+ *
+ * @struct {GRPCServer}
+ * @property {*controllers.NightLightController} controller - Controlador principal
+ * @property {*grpc.Server} server - Servidor gRPC
+ * @property {string} socket - Socket Unix a borrar al cerrar (vacío en TCP)
+ */
+type GRPCServer struct {
+	luznocturnav1.UnimplementedNightLightServiceServer
+
+	controller *controllers.NightLightController
+	server     *grpc.Server
+	socket     string
+}
+
+/**
+ * StartGRPCServer - Escucha en la dirección configurada y atiende la API
+ *
+ * @param {*controllers.NightLightController} controller - Controlador principal
+ * @param {models.GRPCConfig} config - Dirección del servidor
+ * @returns {*GRPCServer, error} Servidor activo o error si no se pudo escuchar
+ * @example
+ *   if config := controller.GetGRPCConfig(); config.Enabled {
+ *       server, err := ipc.StartGRPCServer(controller, config)
+ *       ...
+ *   }
+ */
+func StartGRPCServer(controller *controllers.NightLightController, config models.GRPCConfig) (*GRPCServer, error) {
+	network, address, err := config.Endpoint()
+	if err != nil {
+		return nil, err
+	}
+
+	s := &GRPCServer{controller: controller}
+	if network == "unix" {
+		if err := os.MkdirAll(filepath.Dir(address), 0700); err != nil {
+			return nil, err
+		}
+		// Socket de una ejecución anterior que terminó sin cerrarlo
+		// (el bloqueo de instancia única garantiza que no está en uso)
+		os.Remove(address)
+		s.socket = address
+	}
+
+	listener, err := net.Listen(network, address)
+	if err != nil {
+		return nil, fmt.Errorf("no se pudo escuchar en %s: %w", address, err)
+	}
+	if s.socket != "" {
+		os.Chmod(s.socket, 0600)
+	}
+
+	s.server = grpc.NewServer()
+	luznocturnav1.RegisterNightLightServiceServer(s.server, s)
+	go func() {
+		if err := s.server.Serve(listener); err != nil {
+			logging.Printf("⚠️  Servidor gRPC detenido: %v\n", err)
+		}
+	}()
+
+	logging.Printf("📡 Servidor gRPC disponible: %s:%s\n", network, address)
+	return s, nil
+}
+
+/**
+ * Close - Detiene el servidor y cierra los streams de Watch abiertos
+ */
+func (s *GRPCServer) Close() {
+	s.server.Stop()
+	if s.socket != "" {
+		os.Remove(s.socket)
+	}
+}
+
+// === MÉTODOS DE LA API ===
+
+// GetState devuelve la temperatura seleccionada y si el filtro está activo
+func (s *GRPCServer) GetState(context.Context, *luznocturnav1.GetStateRequest) (*luznocturnav1.GetStateResponse, error) {
+	return &luznocturnav1.GetStateResponse{State: s.state()}, nil
+}
+
+// SetTemperature selecciona una temperatura y la aplica si se pide
+func (s *GRPCServer) SetTemperature(_ context.Context, req *luznocturnav1.SetTemperatureRequest) (*luznocturnav1.SetTemperatureResponse, error) {
+	min, max := s.controller.GetTemperatureRange()
+	if req.Temperature < min || req.Temperature > max {
+		return nil, status.Errorf(codes.InvalidArgument, "temperatura fuera de rango (%.0fK - %.0fK)", min, max)
+	}
+
+	s.controller.UpdateTemperature(req.Temperature)
+	if req.Apply {
+		if err := s.controller.ApplyNightLight(); err != nil {
+			return nil, toGRPCError(err)
+		}
+	}
+	return &luznocturnav1.SetTemperatureResponse{State: s.state()}, nil
+}
+
+/**
+ * Watch - Envía el estado actual y después cada evento del controlador
+ *
+ * Los suscriptores del bus se llaman en la goroutine que publica: el
+ * mensaje se construye ahí (con el estado que acaba de escribir) y pasa
+ * por un canal con búfer. Si el cliente no los consume a tiempo, el
+ * stream termina con RESOURCE_EXHAUSTED en lugar de frenar al controlador.
+ *
+ * @param {*luznocturnav1.WatchRequest} req - Sin parámetros por ahora
+ * @param {luznocturnav1.NightLightService_WatchServer} stream - Stream del cliente
+ * @returns {error} Motivo del cierre (nil si el cliente canceló)
+ */
+func (s *GRPCServer) Watch(_ *luznocturnav1.WatchRequest, stream luznocturnav1.NightLightService_WatchServer) error {
+	events := make(chan *luznocturnav1.WatchResponse, watchBuffer)
+	overflow := make(chan struct{})
+	var overflowOnce sync.Once
+	unsubscribe := s.controller.Subscribe(func(event controllers.Event) {
+		if _, ok := grpcEventTypes[event.Type]; !ok {
+			return // Sin equivalente en la API (p. ej. el modo de color fiel)
+		}
+		select {
+		case events <- s.watchResponse(event):
+		default:
+			overflowOnce.Do(func() { close(overflow) })
+		}
+	})
+	defer unsubscribe()
+
+	snapshot := &luznocturnav1.WatchResponse{Type: luznocturnav1.EventType_EVENT_TYPE_SNAPSHOT, State: s.state()}
+	if err := stream.Send(snapshot); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-overflow:
+			return status.Error(codes.ResourceExhausted, "el cliente no consumió los eventos a tiempo")
+		case response := <-events:
+			if err := stream.Send(response); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// state devuelve el estado actual del controlador en la forma de la API
+func (s *GRPCServer) state() *luznocturnav1.State {
+	config := s.controller.GetConfig()
+	return &luznocturnav1.State{
+		Temperature:    config.Temperature,
+		Brightness:     config.Brightness,
+		Active:         config.IsActive,
+		MinTemperature: config.MinTemp,
+		MaxTemperature: config.MaxTemp,
+	}
+}
+
+// watchResponse convierte un evento del controlador en un mensaje de Watch
+func (s *GRPCServer) watchResponse(event controllers.Event) *luznocturnav1.WatchResponse {
+	state := s.state()
+	state.Temperature = event.Temperature
+	state.Active = event.Active
+
+	response := &luznocturnav1.WatchResponse{
+		Type:   grpcEventTypes[event.Type],
+		State:  state,
+		Source: event.Source,
+	}
+	if event.Err != nil {
+		response.Error = event.Err.Error()
+	}
+	return response
+}
+
+// toGRPCError convierte un error de aplicación en un estado gRPC
+func toGRPCError(err error) error {
+	switch {
+	case errors.Is(err, system.ErrNoBackend):
+		return status.Error(codes.Unavailable, err.Error())
+	case errors.Is(err, system.ErrPartialApply):
+		// Aplicado solo en algunos displays
+		return status.Error(codes.Aborted, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
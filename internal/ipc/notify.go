@@ -0,0 +1,384 @@
+package ipc
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+
+	"luznocturna/luz-nocturna/internal/controllers"
+	"luznocturna/luz-nocturna/internal/logging"
+	"luznocturna/luz-nocturna/internal/models"
+	"luznocturna/luz-nocturna/internal/system"
+)
+
+// Servicio de notificaciones de escritorio de freedesktop.org
+const (
+	notificationsName   = "org.freedesktop.Notifications"
+	notificationsPath   = dbus.ObjectPath("/org/freedesktop/Notifications")
+	notificationsNotify = notificationsName + ".Notify"
+)
+
+// actionSkipTonight es la acción de la notificación de inicio de la noche que la omite
+const actionSkipTonight = "skip-tonight"
+
+// Urgency es el nivel de urgencia de una notificación (hint "urgency")
+type Urgency byte
+
+// Niveles de urgencia de la especificación
+const (
+	UrgencyLow      Urgency = 0
+	UrgencyNormal   Urgency = 1
+	UrgencyCritical Urgency = 2
+)
+
+// notificationTimeout es el tiempo que se muestra una notificación no crítica
+const notificationTimeout = 5 * time.Second
+
+// dndPollInterval es cada cuánto se comprueba si terminó "No molestar" con una notificación en espera
+const dndPollInterval = 30 * time.Second
+
+// dndProbeTimeout es el tiempo máximo para averiguar si el escritorio está en "No molestar"
+const dndProbeTimeout = 3 * time.Second
+
+/**
+ * Notify - Envía una notificación de escritorio sin depender de Fyne
+ *
+ * Habla directamente con org.freedesktop.Notifications, así que sirve
+ * desde la línea de comandos y el modo bandeja, donde la aplicación de
+ * Fyne no está disponible o no tiene ventana.
+ *
+ * @param {string} summary - Título de la notificación
+ * @param {string} body - Texto de la notificación
+ * @param {Urgency} urgency - Urgencia (las críticas no se ocultan solas)
+ * @returns {error} Error si no hay bus de sesión o servicio de notificaciones
+ * @example
+ *   ipc.Notify("Luz Nocturna", "No se pudo aplicar el filtro", ipc.UrgencyCritical)
+ */
+func Notify(summary, body string, urgency Urgency) error {
+	conn, err := dbus.ConnectSessionBus()
+	if err != nil {
+		return fmt.Errorf("no se pudo conectar al bus de sesión: %w", err)
+	}
+	defer conn.Close()
+
+	_, err = sendNotification(conn, 0, summary, body, urgency)
+	return err
+}
+
+/**
+ * sendNotification - Llama a Notify en el servicio de notificaciones
+ *
+ * @param {*dbus.Conn} conn - Conexión al bus de sesión
+ * @param {uint32} replacesID - Notificación a reemplazar (0 para una nueva)
+ * @param {string} summary - Título de la notificación
+ * @param {string} body - Texto de la notificación
+ * @param {Urgency} urgency - Urgencia
+ * @param {...string} actions - Pares identificador/etiqueta de los botones (opcional)
+ * @returns {uint32, error} ID asignado a la notificación
+ * @private
+ */
+func sendNotification(conn *dbus.Conn, replacesID uint32, summary, body string, urgency Urgency, actions ...string) (uint32, error) {
+	timeout := int32(notificationTimeout.Milliseconds())
+	if urgency == UrgencyCritical {
+		timeout = 0 // Las críticas permanecen hasta que el usuario las cierra
+	}
+	hints := map[string]dbus.Variant{
+		"urgency":       dbus.MakeVariant(byte(urgency)),
+		"desktop-entry": dbus.MakeVariant("com.luznocturna.app"),
+	}
+
+	var id uint32
+	err := conn.Object(notificationsName, notificationsPath).Call(notificationsNotify, 0,
+		"Luz Nocturna", replacesID, "weather-clear-night", summary, body, append([]string{}, actions...), hints, timeout).Store(&id)
+	if err != nil {
+		return 0, fmt.Errorf("servicio de notificaciones no disponible: %w", err)
+	}
+	return id, nil
+}
+
+/**
+ * ScheduleNotifier - Notifica los cambios de la programación automática
+ *
+ * Se suscribe al bus de eventos del controlador y avisa cuando empieza o
+ * termina el período nocturno y cuando un cambio automático falla. Cada
+ * aviso reemplaza al anterior para no acumular notificaciones. El aviso
+ * de inicio de la noche incluye la acción "Omitir esta noche".
+ *
+ * Con el escritorio en "No molestar" cada tipo de aviso sigue su política
+ * (notifications en la configuración): se muestra, se descarta o espera
+ * a que termine; de los que esperan solo se muestra el último.
+ *
+ * @struct {ScheduleNotifier}
+ * @property {*controllers.NightLightController} controller - Controlador principal
+ * @property {*dbus.Conn} conn - Conexión privada al bus de sesión
+ * @property {uint32} lastID - ID de la última notificación enviada
+ * @property {bool} night - Si el último cambio del programador fue al período nocturno
+ * @property {bool} failing - Ya se avisó de un fallo y aún no hubo un cambio aplicado
+ * @property {*pendingNotification} pending - Aviso retenido por "No molestar" (nil si no hay)
+ * @property {uint64} seq - Número del último aviso; uno más reciente reemplaza al que aún se decide
+ */
+type ScheduleNotifier struct {
+	controller  *controllers.NightLightController
+	conn        *dbus.Conn
+	mu          sync.Mutex
+	lastID      uint32
+	night       bool
+	known       bool
+	failing     bool
+	pending     *pendingNotification
+	seq         uint64
+	done        chan struct{}
+	unsubscribe func()
+}
+
+// pendingNotification es un aviso que espera a que termine "No molestar"
+type pendingNotification struct {
+	summary, body string
+	urgency       Urgency
+	actions       []string
+}
+
+/**
+ * StartScheduleNotifications - Empieza a notificar los cambios programados
+ *
+ * @param {*controllers.NightLightController} controller - Controlador principal
+ * @returns {*ScheduleNotifier, error} Notificador activo o error si no hay bus de sesión
+ */
+func StartScheduleNotifications(controller *controllers.NightLightController) (*ScheduleNotifier, error) {
+	conn, err := dbus.ConnectSessionBus()
+	if err != nil {
+		return nil, fmt.Errorf("no se pudo conectar al bus de sesión: %w", err)
+	}
+
+	notifier := &ScheduleNotifier{controller: controller, conn: conn, done: make(chan struct{})}
+	notifier.listenActions()
+	notifier.unsubscribe = controller.Subscribe(notifier.onEvent)
+	return notifier, nil
+}
+
+// listenActions atiende los botones de las notificaciones hasta que se cierra la conexión
+func (n *ScheduleNotifier) listenActions() {
+	if err := n.conn.AddMatchSignal(
+		dbus.WithMatchInterface(notificationsName),
+		dbus.WithMatchMember("ActionInvoked"),
+	); err != nil {
+		logging.Printf("⚠️  Las acciones de las notificaciones no funcionarán: %v\n", err)
+		return
+	}
+
+	signals := make(chan *dbus.Signal, 10)
+	n.conn.Signal(signals)
+	go func() {
+		// Close cierra la conexión y con ella el canal
+		for signal := range signals {
+			if len(signal.Body) < 2 {
+				continue
+			}
+			id, _ := signal.Body[0].(uint32)
+			action, _ := signal.Body[1].(string)
+
+			n.mu.Lock()
+			own := id != 0 && id == n.lastID
+			n.mu.Unlock()
+			if !own || action != actionSkipTonight {
+				continue
+			}
+			if _, err := n.controller.SkipTonight(); err != nil {
+				logging.Printf("⚠️  No se pudo omitir la noche: %v\n", err)
+			}
+		}
+	}()
+}
+
+/**
+ * onEvent - Decide si un evento del controlador merece una notificación
+ *
+ * Durante una transición el programador publica muchas temperaturas
+ * intermedias: solo se avisa al llegar a la temperatura nocturna o a la
+ * diurna, y solo si cambia el período. De una racha de cambios
+ * automáticos fallidos (con las transiciones, uno cada pocos segundos)
+ * solo se avisa del primero; la racha termina con el siguiente cambio
+ * aplicado.
+ *
+ * @param {controllers.Event} event - Evento publicado
+ * @private
+ */
+func (n *ScheduleNotifier) onEvent(event controllers.Event) {
+	switch event.Type {
+	case controllers.EventApplied, controllers.EventReset, controllers.EventScheduleTransition:
+		n.mu.Lock()
+		n.failing = false
+		n.mu.Unlock()
+	}
+
+	policies := n.controller.GetNotifications()
+	switch event.Type {
+	case controllers.EventApplyFailed:
+		n.mu.Lock()
+		repeated := n.failing
+		n.failing = true
+		n.mu.Unlock()
+		if repeated {
+			return // Ya se avisó de esta racha de fallos
+		}
+		n.notify(policies.GetErrors(), "No se pudo aplicar el cambio programado",
+			fmt.Sprintf("%.0fK: %v", event.Temperature, event.Err), UrgencyCritical)
+	case controllers.EventScheduleTransition:
+		schedule := n.controller.GetScheduleConfig()
+		var night bool
+		switch event.Temperature {
+		case schedule.NightTemp:
+			night = true
+		case schedule.DayTemp:
+			night = false
+		default:
+			return // Temperatura intermedia de una transición
+		}
+
+		n.mu.Lock()
+		changed := !n.known || n.night != night
+		n.night, n.known = night, true
+		n.mu.Unlock()
+		if !changed {
+			return
+		}
+
+		// En modo solar no hay horas fijas que mostrar
+		clock := n.controller.GetClock()
+		format := n.controller.GetTemperatureFormat()
+		until := func(timeStr string) string {
+			if schedule.Mode == models.ScheduleModeSolar {
+				return ""
+			}
+			return " hasta las " + clock.FormatScheduleTime(timeStr)
+		}
+
+		if night {
+			n.notify(policies.GetSchedule(), "🌙 Filtro nocturno activado",
+				fmt.Sprintf("%s nocturna: %s%s", format.Name(), format.Format(event.Temperature), until(schedule.EndTime)), UrgencyLow,
+				actionSkipTonight, "Omitir esta noche")
+		} else {
+			n.notify(policies.GetSchedule(), "☀️ Filtro nocturno finalizado",
+				fmt.Sprintf("%s diurna: %s%s", format.Name(), format.Format(event.Temperature), until(schedule.StartTime)), UrgencyLow)
+		}
+	}
+}
+
+/**
+ * notify - Envía un aviso respetando "No molestar" según su política
+ *
+ * EventBus.Publish es síncrono, así que la consulta de "No molestar"
+ * (que puede lanzar herramientas externas) se hace en otra goroutine y
+ * con tiempo máximo, sin frenar al programador. Si mientras tanto llega
+ * otro aviso, este se descarta: el nuevo lo habría reemplazado.
+ *
+ * @param {string} policy - models.NotifyAlways, NotifyQueue o NotifySkip
+ * @param {string} summary - Título de la notificación
+ * @param {string} body - Texto de la notificación
+ * @param {Urgency} urgency - Urgencia
+ * @param {...string} actions - Pares identificador/etiqueta de los botones (opcional)
+ * @private
+ */
+func (n *ScheduleNotifier) notify(policy, summary, body string, urgency Urgency, actions ...string) {
+	n.mu.Lock()
+	n.seq++
+	seq := n.seq
+	n.mu.Unlock()
+
+	if policy == models.NotifyAlways {
+		n.send(summary, body, urgency, actions...)
+		return
+	}
+	go func() {
+		dnd := isDoNotDisturb()
+		n.mu.Lock()
+		stale := seq != n.seq
+		n.mu.Unlock()
+		select {
+		case <-n.done:
+			return
+		default:
+		}
+		if stale {
+			return
+		}
+		if !dnd {
+			n.send(summary, body, urgency, actions...)
+			return
+		}
+		n.hold(policy, summary, body, urgency, actions...)
+	}()
+}
+
+// hold descarta o deja en espera un aviso que llegó durante "No molestar"
+func (n *ScheduleNotifier) hold(policy, summary, body string, urgency Urgency, actions ...string) {
+	if policy == models.NotifySkip {
+		logging.Printf("🔕 Aviso descartado por \"No molestar\": %s\n", summary)
+		return
+	}
+
+	logging.Printf("🔕 Aviso en espera hasta que termine \"No molestar\": %s\n", summary)
+	n.mu.Lock()
+	waiting := n.pending != nil
+	n.pending = &pendingNotification{summary: summary, body: body, urgency: urgency, actions: actions}
+	n.mu.Unlock()
+	if !waiting {
+		go n.waitForDND()
+	}
+}
+
+// waitForDND muestra el aviso en espera cuando termina "No molestar"
+func (n *ScheduleNotifier) waitForDND() {
+	ticker := time.NewTicker(dndPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-n.done:
+			return
+		case <-ticker.C:
+			if isDoNotDisturb() {
+				continue
+			}
+			n.mu.Lock()
+			pending := n.pending
+			n.pending = nil
+			n.mu.Unlock()
+			if pending != nil {
+				n.send(pending.summary, pending.body, pending.urgency, pending.actions...)
+			}
+			return
+		}
+	}
+}
+
+// isDoNotDisturb consulta "No molestar" con un tiempo máximo de dndProbeTimeout
+func isDoNotDisturb() bool {
+	ctx, cancel := context.WithTimeout(context.Background(), dndProbeTimeout)
+	defer cancel()
+	return system.IsDoNotDisturb(ctx)
+}
+
+// send envía la notificación reemplazando la anterior; si falla solo lo registra
+func (n *ScheduleNotifier) send(summary, body string, urgency Urgency, actions ...string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	id, err := sendNotification(n.conn, n.lastID, summary, body, urgency, actions...)
+	if err != nil {
+		logging.Printf("⚠️  %v\n", err)
+		return
+	}
+	n.lastID = id
+}
+
+/**
+ * Close - Deja de notificar y cierra la conexión
+ */
+func (n *ScheduleNotifier) Close() {
+	n.unsubscribe()
+	close(n.done)
+	n.conn.Close()
+}
@@ -0,0 +1,165 @@
+package ipc
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// remoteBridge es el comando que se ejecuta en la otra máquina: conecta
+// stdin/stdout al socket Unix del bus de sesión del usuario remoto
+var remoteBridge = []string{"systemd-stdio-bridge", "--user"}
+
+/**
+ * RemoteInstance - Instancia de Luz Nocturna abierta en otra máquina
+ *
+ * Las llamadas viajan por SSH hasta el bus de sesión remoto
+ * ($XDG_RUNTIME_DIR/bus) mediante systemd-stdio-bridge, igual que
+ * "busctl --host", y usan el mismo servicio D-Bus que la CLI local.
+ *
+ * @struct {RemoteInstance}
+ * @property {string} target - Destino SSH ("usuario@host")
+ * @property {*dbus.Conn} conn - Conexión D-Bus sobre el túnel
+ * @property {*sshPipe} pipe - Proceso ssh que transporta la conexión
+ */
+type RemoteInstance struct {
+	target string
+	conn   *dbus.Conn
+	pipe   *sshPipe
+}
+
+/**
+ * ConnectRemoteInstance - Abre un túnel SSH al bus de sesión de otra máquina
+ *
+ * ssh puede pedir la contraseña o la frase de la clave por la terminal;
+ * para atajos de teclado conviene usar claves con ssh-agent.
+ *
+ * @param {string} target - Destino SSH ("usuario@host" o un alias de ~/.ssh/config)
+ * @returns {*RemoteInstance, error} Instancia remota o error si no hay túnel o aplicación abierta
+ * @example
+ *   remote, err := ipc.ConnectRemoteInstance("ana@htpc")
+ *   if err == nil {
+ *       defer remote.Close()
+ *       remote.Call("Apply")
+ *   }
+ */
+func ConnectRemoteInstance(target string) (*RemoteInstance, error) {
+	if target == "" || strings.HasPrefix(target, "-") {
+		return nil, fmt.Errorf("destino SSH no válido: %q", target)
+	}
+
+	pipe, err := startSSHPipe(target)
+	if err != nil {
+		return nil, err
+	}
+
+	remote := &RemoteInstance{target: target, pipe: pipe}
+	if err := remote.connect(); err != nil {
+		remote.Close() // Espera a ssh para que stderr esté completo
+		if pipe.missingBridge() {
+			return nil, fmt.Errorf("%s no tiene %s (incluido en systemd 243 o posterior)", target, remoteBridge[0])
+		}
+		return nil, fmt.Errorf("no se pudo conectar al bus de sesión de %s: %w", target, err)
+	}
+	return remote, nil
+}
+
+// connect autentica la conexión D-Bus y comprueba que la aplicación esté abierta
+func (r *RemoteInstance) connect() error {
+	conn, err := dbus.NewConn(r.pipe)
+	if err != nil {
+		return err
+	}
+	r.conn = conn
+	if err := conn.Auth(nil); err != nil {
+		return err
+	}
+	if err := conn.Hello(); err != nil {
+		return err
+	}
+
+	var hasOwner bool
+	if err := conn.BusObject().Call("org.freedesktop.DBus.NameHasOwner", 0, DBusName).Store(&hasOwner); err != nil {
+		return err
+	}
+	if !hasOwner {
+		return errors.New("Luz Nocturna no está abierta en esa sesión")
+	}
+	return nil
+}
+
+// Call invoca un método del servicio remoto ("Apply", "Reset", "Toggle"...)
+func (r *RemoteInstance) Call(method string, args ...interface{}) error {
+	return callInstance(r.conn, method, args...)
+}
+
+// Close cierra la conexión y termina ssh
+func (r *RemoteInstance) Close() {
+	if r.conn != nil {
+		r.conn.Close() // Cierra también el transporte (sshPipe)
+		return
+	}
+	r.pipe.Close()
+}
+
+/**
+ * sshPipe - Proceso ssh usado como conexión (stdin/stdout)
+ *
+ * @struct {sshPipe}
+ * @property {*exec.Cmd} cmd - Proceso ssh
+ * @property {io.WriteCloser} stdin - Lo que se escribe llega al bus remoto
+ * @property {io.ReadCloser} stdout - Respuestas del bus remoto
+ * @property {*strings.Builder} stderr - Errores de ssh o del puente, para el mensaje de error
+ */
+type sshPipe struct {
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stdout io.ReadCloser
+	stderr *strings.Builder
+}
+
+// startSSHPipe lanza ssh sin terminal ni reenvío de X11 hacia el puente del bus remoto
+func startSSHPipe(target string) (*sshPipe, error) {
+	if _, err := exec.LookPath("ssh"); err != nil {
+		return nil, errors.New("no está instalado ssh (paquete openssh-client)")
+	}
+
+	args := append([]string{"-xT", "--", target}, remoteBridge...)
+	cmd := exec.Command("ssh", args...)
+	pipe := &sshPipe{cmd: cmd, stderr: &strings.Builder{}}
+	cmd.Stderr = io.MultiWriter(os.Stderr, pipe.stderr) // Avisos de ssh (host desconocido...) visibles
+
+	var err error
+	if pipe.stdin, err = cmd.StdinPipe(); err != nil {
+		return nil, err
+	}
+	if pipe.stdout, err = cmd.StdoutPipe(); err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("no se pudo ejecutar ssh: %w", err)
+	}
+	return pipe, nil
+}
+
+func (p *sshPipe) Read(b []byte) (int, error)  { return p.stdout.Read(b) }
+func (p *sshPipe) Write(b []byte) (int, error) { return p.stdin.Write(b) }
+
+// Close cierra stdin (el puente remoto termina al recibir EOF) y espera a ssh
+func (p *sshPipe) Close() error {
+	p.stdin.Close()
+	return p.cmd.Wait()
+}
+
+// missingBridge indica si el shell remoto no encontró el puente del bus
+// ("bash: systemd-stdio-bridge: command not found")
+func (p *sshPipe) missingBridge() bool {
+	stderr := p.stderr.String()
+	return strings.Contains(stderr, remoteBridge[0]) &&
+		(strings.Contains(stderr, "not found") || strings.Contains(stderr, "No such file"))
+}
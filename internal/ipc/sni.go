@@ -0,0 +1,633 @@
+package ipc
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	_ "image/png"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/godbus/dbus/v5/introspect"
+	"github.com/godbus/dbus/v5/prop"
+
+	"luznocturna/luz-nocturna/internal/logging"
+)
+
+// Rutas e interfaces de la especificación StatusNotifierItem/dbusmenu
+const (
+	sniPath            = dbus.ObjectPath("/StatusNotifierItem")
+	sniMenuPath        = dbus.ObjectPath("/StatusNotifierItem/Menu")
+	sniInterface       = "org.kde.StatusNotifierItem"
+	sniMenuInterface   = "com.canonical.dbusmenu"
+	sniWatcherName     = "org.kde.StatusNotifierWatcher"
+	sniWatcherPath     = dbus.ObjectPath("/StatusNotifierWatcher")
+	sniWatcherRegister = sniWatcherName + ".RegisterStatusNotifierItem"
+)
+
+// sniScrollNotch es el delta de una muesca de la rueda en los paneles de KDE (Qt)
+const sniScrollNotch = 120
+
+const sniIntrospection = `
+<node>
+	<interface name="` + sniInterface + `">
+		<method name="Activate">
+			<arg direction="in" type="i" name="x"/>
+			<arg direction="in" type="i" name="y"/>
+		</method>
+		<method name="SecondaryActivate">
+			<arg direction="in" type="i" name="x"/>
+			<arg direction="in" type="i" name="y"/>
+		</method>
+		<method name="ContextMenu">
+			<arg direction="in" type="i" name="x"/>
+			<arg direction="in" type="i" name="y"/>
+		</method>
+		<method name="Scroll">
+			<arg direction="in" type="i" name="delta"/>
+			<arg direction="in" type="s" name="orientation"/>
+		</method>
+		<signal name="NewIcon"/>
+		<signal name="NewToolTip"/>
+		<property name="Category" type="s" access="read"/>
+		<property name="Id" type="s" access="read"/>
+		<property name="Title" type="s" access="read"/>
+		<property name="Status" type="s" access="read"/>
+		<property name="IconName" type="s" access="read"/>
+		<property name="IconPixmap" type="a(iiay)" access="read"/>
+		<property name="ItemIsMenu" type="b" access="read"/>
+		<property name="Menu" type="o" access="read"/>
+		<property name="ToolTip" type="(sa(iiay)ss)" access="read"/>
+	</interface>` + prop.IntrospectDataString + introspect.IntrospectDeclarationString + `
+</node>`
+
+const sniMenuIntrospection = `
+<node>
+	<interface name="` + sniMenuInterface + `">
+		<method name="GetLayout">
+			<arg direction="in" type="i" name="parentId"/>
+			<arg direction="in" type="i" name="recursionDepth"/>
+			<arg direction="in" type="as" name="propertyNames"/>
+			<arg direction="out" type="u" name="revision"/>
+			<arg direction="out" type="(ia{sv}av)" name="layout"/>
+		</method>
+		<method name="GetGroupProperties">
+			<arg direction="in" type="ai" name="ids"/>
+			<arg direction="in" type="as" name="propertyNames"/>
+			<arg direction="out" type="a(ia{sv})" name="properties"/>
+		</method>
+		<method name="GetProperty">
+			<arg direction="in" type="i" name="id"/>
+			<arg direction="in" type="s" name="name"/>
+			<arg direction="out" type="v" name="value"/>
+		</method>
+		<method name="Event">
+			<arg direction="in" type="i" name="id"/>
+			<arg direction="in" type="s" name="eventId"/>
+			<arg direction="in" type="v" name="data"/>
+			<arg direction="in" type="u" name="timestamp"/>
+		</method>
+		<method name="AboutToShow">
+			<arg direction="in" type="i" name="id"/>
+			<arg direction="out" type="b" name="needUpdate"/>
+		</method>
+		<signal name="LayoutUpdated">
+			<arg type="u" name="revision"/>
+			<arg type="i" name="parent"/>
+		</signal>
+		<property name="Version" type="u" access="read"/>
+		<property name="Status" type="s" access="read"/>
+	</interface>` + prop.IntrospectDataString + introspect.IntrospectDeclarationString + `
+</node>`
+
+// TrayMenuItem describe una entrada del menú de bandeja independiente del toolkit
+type TrayMenuItem struct {
+	Label     string
+	Action    func()
+	Separator bool
+	Checked   bool // Se muestra con marca de verificación
+	Disabled  bool // Solo informativa: no se puede pulsar
+	Children  []TrayMenuItem
+}
+
+// sniPixmap es un icono ARGB32 en el formato (iiay) de la especificación
+type sniPixmap struct {
+	Width  int32
+	Height int32
+	Data   []byte
+}
+
+// sniToolTip es el tooltip en el formato (sa(iiay)ss) de la especificación
+type sniToolTip struct {
+	IconName    string
+	IconPixmap  []sniPixmap
+	Title       string
+	Description string
+}
+
+// sniLayout es un nodo del menú en el formato (ia{sv}av) de dbusmenu
+type sniLayout struct {
+	ID         int32
+	Properties map[string]dbus.Variant
+	Children   []dbus.Variant
+}
+
+/**
+ * StatusNotifierItem - Icono de bandeja propio sobre D-Bus
+ *
+ * Implementación mínima de StatusNotifierItem + dbusmenu usada como
+ * respaldo cuando el driver de Fyne no ofrece bandeja del sistema.
+ * Solo funciona si hay un StatusNotifierWatcher en el bus de sesión
+ * (KDE, XFCE, la extensión AppIndicator de GNOME, waybar...).
+ *
+ * @struct {StatusNotifierItem}
+ * @property {*dbus.Conn} conn - Conexión privada al bus de sesión
+ * @property {*sniLayout} root - Raíz del menú actual
+ * @property {map[int32]func()} actions - Acciones por ID de entrada
+ * @property {uint32} revision - Revisión del layout (se incrementa en cada SetMenu)
+ * @property {func()} onActivate - Acción del clic principal sobre el icono
+ * @property {func()} onMenuOpening - Se ejecuta justo antes de que el panel muestre el menú
+ * @property {func()} onSecondaryActivate - Acción del clic central (nil = igual que el principal)
+ * @property {func(int)} onScroll - Acción de la rueda sobre el icono, en pasos (positivo = hacia arriba)
+ */
+type StatusNotifierItem struct {
+	conn          *dbus.Conn
+	mu            sync.Mutex
+	props         *prop.Properties
+	title         string
+	root          *sniLayout
+	actions       map[int32]func()
+	revision      uint32
+	onActivate    func()
+	onMenuOpening func()
+	done          chan struct{}
+
+	onSecondaryActivate func()
+	onScroll            func(steps int)
+}
+
+// sniMenu expone el menú en su propia ruta con la interfaz dbusmenu
+type sniMenu struct {
+	item *StatusNotifierItem
+}
+
+/**
+ * StatusNotifierWatcherAvailable - Indica si algún panel acepta iconos SNI
+ *
+ * @returns {bool} true si org.kde.StatusNotifierWatcher tiene dueño en el bus de sesión
+ */
+func StatusNotifierWatcherAvailable() bool {
+	conn, err := dbus.ConnectSessionBus()
+	if err != nil {
+		return false
+	}
+	defer conn.Close()
+
+	var hasOwner bool
+	err = conn.BusObject().Call("org.freedesktop.DBus.NameHasOwner", 0, sniWatcherName).Store(&hasOwner)
+	return err == nil && hasOwner
+}
+
+/**
+ * StartStatusNotifierItem - Publica el icono y lo registra en el watcher
+ *
+ * @param {string} title - Título/tooltip del icono
+ * @param {[]byte} icon - Icono en PNG
+ * @param {func()} onActivate - Acción del clic principal (puede ser nil)
+ * @returns {*StatusNotifierItem, error} Icono activo o error si no hay watcher
+ * @example
+ *   item, err := ipc.StartStatusNotifierItem("Luz Nocturna", iconPNG, showWindow)
+ *   if err == nil {
+ *       item.SetMenu(items)
+ *   }
+ */
+func StartStatusNotifierItem(title string, icon []byte, onActivate func()) (*StatusNotifierItem, error) {
+	conn, err := dbus.ConnectSessionBus()
+	if err != nil {
+		return nil, fmt.Errorf("no se pudo conectar al bus de sesión: %w", err)
+	}
+
+	item := &StatusNotifierItem{
+		conn:       conn,
+		title:      title,
+		root:       &sniLayout{ID: 0, Properties: map[string]dbus.Variant{}, Children: []dbus.Variant{}},
+		actions:    make(map[int32]func()),
+		onActivate: onActivate,
+		done:       make(chan struct{}),
+	}
+
+	if err := item.export(title, icon); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	name := fmt.Sprintf("org.kde.StatusNotifierItem-%d-1", os.Getpid())
+	if _, err := conn.RequestName(name, dbus.NameFlagDoNotQueue); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	if err := item.register(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	go item.stayRegistered()
+
+	logging.Println("🔔 Icono de bandeja publicado vía StatusNotifierItem")
+	return item, nil
+}
+
+/**
+ * export - Exporta los objetos del icono y del menú con sus propiedades
+ *
+ * @param {string} title - Título del icono
+ * @param {[]byte} icon - Icono en PNG
+ * @returns {error} Error de exportación si lo hay
+ * @private
+ */
+func (s *StatusNotifierItem) export(title string, icon []byte) error {
+	if err := s.conn.Export(s, sniPath, sniInterface); err != nil {
+		return err
+	}
+	if err := s.conn.Export(&sniMenu{item: s}, sniMenuPath, sniMenuInterface); err != nil {
+		return err
+	}
+
+	readOnly := func(value interface{}) *prop.Prop {
+		return &prop.Prop{Value: value, Emit: prop.EmitTrue}
+	}
+
+	props, err := prop.Export(s.conn, sniPath, prop.Map{
+		sniInterface: {
+			"Category":   readOnly("ApplicationStatus"),
+			"Id":         readOnly("luz-nocturna"),
+			"Title":      readOnly(title),
+			"Status":     readOnly("Active"),
+			"IconName":   readOnly(""),
+			"IconPixmap": readOnly(pixmapFromPNG(icon)),
+			"ItemIsMenu": readOnly(false),
+			"Menu":       readOnly(sniMenuPath),
+			"ToolTip":    readOnly(sniToolTip{Title: title, IconPixmap: []sniPixmap{}}),
+		},
+	})
+	if err != nil {
+		return err
+	}
+	s.props = props
+
+	if _, err := prop.Export(s.conn, sniMenuPath, prop.Map{
+		sniMenuInterface: {
+			"Version": readOnly(uint32(3)),
+			"Status":  readOnly("normal"),
+		},
+	}); err != nil {
+		return err
+	}
+
+	if err := s.conn.Export(introspect.Introspectable(sniIntrospection), sniPath,
+		"org.freedesktop.DBus.Introspectable"); err != nil {
+		return err
+	}
+	return s.conn.Export(introspect.Introspectable(sniMenuIntrospection), sniMenuPath,
+		"org.freedesktop.DBus.Introspectable")
+}
+
+/**
+ * register - Registra el icono en el StatusNotifierWatcher
+ *
+ * @returns {error} Error si no hay watcher o rechaza el registro
+ * @private
+ */
+func (s *StatusNotifierItem) register() error {
+	call := s.conn.Object(sniWatcherName, sniWatcherPath).Call(sniWatcherRegister, 0, string(sniPath))
+	if call.Err != nil {
+		return fmt.Errorf("no hay StatusNotifierWatcher disponible: %w", call.Err)
+	}
+	return nil
+}
+
+/**
+ * stayRegistered - Vuelve a registrar el icono si el panel se reinicia
+ *
+ * @private
+ */
+func (s *StatusNotifierItem) stayRegistered() {
+	if err := s.conn.AddMatchSignal(
+		dbus.WithMatchInterface("org.freedesktop.DBus"),
+		dbus.WithMatchMember("NameOwnerChanged"),
+		dbus.WithMatchArg(0, sniWatcherName),
+	); err != nil {
+		logging.Printf("⚠️  No se puede vigilar el StatusNotifierWatcher: %v\n", err)
+		return
+	}
+
+	signals := make(chan *dbus.Signal, 10)
+	s.conn.Signal(signals)
+
+	for {
+		select {
+		case signal, ok := <-signals:
+			if !ok || signal == nil {
+				return
+			}
+			if len(signal.Body) < 3 {
+				continue
+			}
+			if newOwner, _ := signal.Body[2].(string); newOwner != "" {
+				if err := s.register(); err != nil {
+					logging.Printf("⚠️  %v\n", err)
+				}
+			}
+		case <-s.done:
+			return
+		}
+	}
+}
+
+/**
+ * SetMenu - Reemplaza el menú del icono
+ *
+ * @param {[]TrayMenuItem} items - Entradas de primer nivel
+ */
+func (s *StatusNotifierItem) SetMenu(items []TrayMenuItem) {
+	s.mu.Lock()
+	s.actions = make(map[int32]func())
+	nextID := int32(1)
+	s.root = &sniLayout{
+		ID:         0,
+		Properties: map[string]dbus.Variant{"children-display": dbus.MakeVariant("submenu")},
+		Children:   s.buildLayout(items, &nextID),
+	}
+	s.revision++
+	revision := s.revision
+	s.mu.Unlock()
+
+	if err := s.conn.Emit(sniMenuPath, sniMenuInterface+".LayoutUpdated", revision, int32(0)); err != nil {
+		logging.Printf("⚠️  No se pudo actualizar el menú de bandeja: %v\n", err)
+	}
+}
+
+/**
+ * SetToolTip - Cambia el texto que el panel muestra al pasar el ratón
+ *
+ * @param {string} description - Texto bajo el título del icono ("" para solo el título)
+ */
+func (s *StatusNotifierItem) SetToolTip(description string) {
+	tooltip := sniToolTip{Title: s.title, IconPixmap: []sniPixmap{}, Description: description}
+	s.props.SetMust(sniInterface, "ToolTip", tooltip)
+	if err := s.conn.Emit(sniPath, sniInterface+".NewToolTip"); err != nil {
+		logging.Printf("⚠️  No se pudo actualizar el tooltip de bandeja: %v\n", err)
+	}
+}
+
+// SetIcon cambia el icono (PNG) y avisa al panel
+func (s *StatusNotifierItem) SetIcon(icon []byte) {
+	s.props.SetMust(sniInterface, "IconPixmap", pixmapFromPNG(icon))
+	if err := s.conn.Emit(sniPath, sniInterface+".NewIcon"); err != nil {
+		logging.Printf("⚠️  No se pudo actualizar el icono de bandeja: %v\n", err)
+	}
+}
+
+// SetOnMenuOpening registra una acción que se ejecuta antes de mostrar el menú (p. ej. refrescar textos)
+func (s *StatusNotifierItem) SetOnMenuOpening(fn func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onMenuOpening = fn
+}
+
+// SetOnSecondaryActivate registra la acción del clic central sobre el icono
+func (s *StatusNotifierItem) SetOnSecondaryActivate(fn func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onSecondaryActivate = fn
+}
+
+// SetOnScroll registra la acción de la rueda del ratón sobre el icono
+func (s *StatusNotifierItem) SetOnScroll(fn func(steps int)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onScroll = fn
+}
+
+/**
+ * buildLayout - Convierte entradas de menú a nodos dbusmenu
+ *
+ * @param {[]TrayMenuItem} items - Entradas a convertir
+ * @param {*int32} nextID - Siguiente ID libre (se incrementa)
+ * @returns {[]dbus.Variant} Nodos hijos
+ * @private
+ */
+func (s *StatusNotifierItem) buildLayout(items []TrayMenuItem, nextID *int32) []dbus.Variant {
+	children := make([]dbus.Variant, 0, len(items))
+	for _, item := range items {
+		node := sniLayout{ID: *nextID, Properties: map[string]dbus.Variant{}, Children: []dbus.Variant{}}
+		*nextID++
+
+		if item.Separator {
+			node.Properties["type"] = dbus.MakeVariant("separator")
+		} else {
+			node.Properties["label"] = dbus.MakeVariant(item.Label)
+			if item.Disabled {
+				node.Properties["enabled"] = dbus.MakeVariant(false)
+			}
+			if item.Checked {
+				node.Properties["toggle-type"] = dbus.MakeVariant("checkmark")
+				node.Properties["toggle-state"] = dbus.MakeVariant(int32(1))
+			}
+			if len(item.Children) > 0 {
+				node.Properties["children-display"] = dbus.MakeVariant("submenu")
+				node.Children = s.buildLayout(item.Children, nextID)
+			}
+			if item.Action != nil {
+				s.actions[node.ID] = item.Action
+			}
+		}
+		children = append(children, dbus.MakeVariant(node))
+	}
+	return children
+}
+
+/**
+ * Close - Retira el icono de la bandeja
+ */
+func (s *StatusNotifierItem) Close() {
+	close(s.done)
+	s.conn.Close()
+}
+
+// === MÉTODOS org.kde.StatusNotifierItem ===
+
+// Activate ejecuta la acción del clic principal
+func (s *StatusNotifierItem) Activate(x, y int32) *dbus.Error {
+	if s.onActivate != nil {
+		s.onActivate()
+	}
+	return nil
+}
+
+// SecondaryActivate ejecuta la acción del clic central (o la del principal si no hay)
+func (s *StatusNotifierItem) SecondaryActivate(x, y int32) *dbus.Error {
+	s.mu.Lock()
+	onSecondaryActivate := s.onSecondaryActivate
+	s.mu.Unlock()
+
+	if onSecondaryActivate == nil {
+		return s.Activate(x, y)
+	}
+	onSecondaryActivate()
+	return nil
+}
+
+// ContextMenu no hace nada: el panel muestra el menú exportado
+func (s *StatusNotifierItem) ContextMenu(x, y int32) *dbus.Error {
+	return nil
+}
+
+/**
+ * Scroll - Convierte la rueda sobre el icono en pasos
+ *
+ * Cada panel manda el delta en su escala: KDE usa 120 por muesca (o
+ * fracciones con touchpad) y otros, como waybar, ±1. Se cuentan las
+ * muescas completas y cualquier delta menor vale un paso; la rueda
+ * horizontal se ignora.
+ *
+ * @param {int32} delta - Desplazamiento (positivo = hacia arriba)
+ * @param {string} orientation - "vertical" u "horizontal"
+ * @returns {*dbus.Error} Siempre nil
+ */
+func (s *StatusNotifierItem) Scroll(delta int32, orientation string) *dbus.Error {
+	s.mu.Lock()
+	onScroll := s.onScroll
+	s.mu.Unlock()
+
+	if onScroll == nil || delta == 0 || !strings.EqualFold(orientation, "vertical") {
+		return nil
+	}
+
+	steps := int(delta / sniScrollNotch)
+	if steps == 0 {
+		steps = 1
+		if delta < 0 {
+			steps = -1
+		}
+	}
+	onScroll(steps)
+	return nil
+}
+
+// === MÉTODOS com.canonical.dbusmenu ===
+
+// GetLayout devuelve el menú completo (se ignora la profundidad pedida)
+func (m *sniMenu) GetLayout(parentID, recursionDepth int32, propertyNames []string) (uint32, sniLayout, *dbus.Error) {
+	m.item.mu.Lock()
+	defer m.item.mu.Unlock()
+
+	if parentID == 0 {
+		return m.item.revision, *m.item.root, nil
+	}
+	if node, ok := findLayout(m.item.root, parentID); ok {
+		return m.item.revision, node, nil
+	}
+	return m.item.revision, sniLayout{}, dbus.MakeFailedError(fmt.Errorf("entrada de menú desconocida: %d", parentID))
+}
+
+// GetGroupProperties devuelve las propiedades de las entradas pedidas
+func (m *sniMenu) GetGroupProperties(ids []int32, propertyNames []string) ([]struct {
+	ID         int32
+	Properties map[string]dbus.Variant
+}, *dbus.Error) {
+	m.item.mu.Lock()
+	defer m.item.mu.Unlock()
+
+	var result []struct {
+		ID         int32
+		Properties map[string]dbus.Variant
+	}
+	for _, id := range ids {
+		if node, ok := findLayout(m.item.root, id); ok {
+			result = append(result, struct {
+				ID         int32
+				Properties map[string]dbus.Variant
+			}{node.ID, node.Properties})
+		}
+	}
+	return result, nil
+}
+
+// GetProperty devuelve una propiedad de una entrada
+func (m *sniMenu) GetProperty(id int32, name string) (dbus.Variant, *dbus.Error) {
+	m.item.mu.Lock()
+	defer m.item.mu.Unlock()
+
+	if node, ok := findLayout(m.item.root, id); ok {
+		if value, ok := node.Properties[name]; ok {
+			return value, nil
+		}
+	}
+	return dbus.MakeVariant(""), nil
+}
+
+// Event ejecuta la acción de la entrada pulsada
+func (m *sniMenu) Event(id int32, eventID string, data dbus.Variant, timestamp uint32) *dbus.Error {
+	if eventID != "clicked" {
+		return nil
+	}
+
+	m.item.mu.Lock()
+	action := m.item.actions[id]
+	m.item.mu.Unlock()
+
+	if action != nil {
+		action()
+	}
+	return nil
+}
+
+// AboutToShow refresca el menú raíz antes de mostrarlo si hay una acción registrada
+func (m *sniMenu) AboutToShow(id int32) (bool, *dbus.Error) {
+	m.item.mu.Lock()
+	onMenuOpening := m.item.onMenuOpening
+	m.item.mu.Unlock()
+
+	if id != 0 || onMenuOpening == nil {
+		return false, nil
+	}
+	onMenuOpening()
+	return true, nil
+}
+
+// findLayout busca un nodo del menú por ID
+func findLayout(node *sniLayout, id int32) (sniLayout, bool) {
+	if node.ID == id {
+		return *node, true
+	}
+	for _, child := range node.Children {
+		childNode := child.Value().(sniLayout)
+		if found, ok := findLayout(&childNode, id); ok {
+			return found, true
+		}
+	}
+	return sniLayout{}, false
+}
+
+// pixmapFromPNG convierte un PNG al formato ARGB32 de StatusNotifierItem
+func pixmapFromPNG(data []byte) []sniPixmap {
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		logging.Printf("⚠️  Icono de bandeja no válido: %v\n", err)
+		return []sniPixmap{}
+	}
+
+	bounds := img.Bounds()
+	pixels := make([]byte, 0, bounds.Dx()*bounds.Dy()*4)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			r, g, b, a := img.At(x, y).RGBA()
+			pixels = append(pixels, byte(a>>8), byte(r>>8), byte(g>>8), byte(b>>8))
+		}
+	}
+
+	return []sniPixmap{{Width: int32(bounds.Dx()), Height: int32(bounds.Dy()), Data: pixels}}
+}
@@ -0,0 +1,208 @@
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Formatos de registro admitidos por --log-format
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Niveles de los registros estructurados
+const (
+	LevelDebug = "debug"
+	LevelInfo  = "info"
+	LevelWarn  = "warn"
+	LevelError = "error"
+)
+
+// Fields son los campos adicionales de un registro (backend, display, temp, error...)
+type Fields map[string]interface{}
+
+// recentLimit es el número de registros que se guardan en memoria para el diagnóstico
+const recentLimit = 500
+
+var (
+	mu     sync.Mutex
+	format = FormatText
+	output io.Writer // nil: el os.Stdout del momento
+	recent []string  // Últimos registros en texto, del más antiguo al más reciente
+)
+
+/**
+ * SetFormat - Selecciona el formato de salida de los registros
+ *
+ * @param {string} name - "text" (por defecto, mensajes con emoji) o "json" (un objeto por línea)
+ * @returns {error} Error si el formato no es válido
+ */
+func SetFormat(name string) error {
+	switch name {
+	case FormatText, FormatJSON:
+	default:
+		return fmt.Errorf("formato de registro no válido %q (usa text o json)", name)
+	}
+	mu.Lock()
+	format = name
+	mu.Unlock()
+	return nil
+}
+
+// SetOutput cambia el destino de los registros (nil vuelve a os.Stdout; io.Discard los silencia)
+func SetOutput(w io.Writer) {
+	mu.Lock()
+	output = w
+	mu.Unlock()
+}
+
+// Format devuelve el formato de registro activo
+func Format() string {
+	mu.Lock()
+	defer mu.Unlock()
+	return format
+}
+
+// Recent devuelve una copia de los últimos registros, con su hora, para el paquete de diagnóstico
+func Recent() []string {
+	mu.Lock()
+	defer mu.Unlock()
+	return append([]string(nil), recent...)
+}
+
+// Printf registra un mensaje con el formato de fmt.Printf
+func Printf(msg string, args ...interface{}) {
+	write(nil, fmt.Sprintf(msg, args...))
+}
+
+// Println registra un mensaje con el formato de fmt.Println
+func Println(args ...interface{}) {
+	write(nil, fmt.Sprintln(args...))
+}
+
+// Entry es un registro con campos estructurados pendiente de escribir
+type Entry struct {
+	fields Fields
+}
+
+/**
+ * WithFields - Adjunta campos estructurados al siguiente registro
+ *
+ * En formato texto los campos se omiten (el mensaje ya los describe);
+ * en JSON se añaden como claves del objeto.
+ *
+ * @param {Fields} fields - Campos del registro
+ * @returns {Entry} Registro sobre el que llamar a Printf
+ *
+ * @example
+ *   logging.WithFields(logging.Fields{"backend": "xrandr", "display": "eDP-1", "error": err}).
+ *       Printf("⚠️  No se pudo aplicar gamma a %s: %v\n", "eDP-1", err)
+ */
+func WithFields(fields Fields) Entry {
+	return Entry{fields: fields}
+}
+
+// Printf escribe el registro con el formato de fmt.Printf
+func (e Entry) Printf(msg string, args ...interface{}) {
+	write(e.fields, fmt.Sprintf(msg, args...))
+}
+
+// Println escribe el registro con el formato de fmt.Println
+func (e Entry) Println(args ...interface{}) {
+	write(e.fields, fmt.Sprintln(args...))
+}
+
+/**
+ * write - Emite el mensaje en el formato activo
+ *
+ * Sin SetOutput se escribe siempre en el os.Stdout del momento para
+ * respetar las redirecciones temporales (p. ej. el modo silencioso de la CLI).
+ *
+ * @private
+ */
+func write(fields Fields, text string) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	w := output
+	if w == nil {
+		w = os.Stdout
+	}
+
+	// Se guarda también lo que la CLI silencia: el diagnóstico lo necesita igual
+	if line := strings.TrimRight(text, "\n"); line != "" {
+		if len(recent) == recentLimit {
+			recent = recent[1:]
+		}
+		recent = append(recent, time.Now().Format("15:04:05 ")+line)
+	}
+
+	if format != FormatJSON {
+		fmt.Fprint(w, text)
+		return
+	}
+
+	level, msg := splitLevel(text)
+	record := make(map[string]interface{}, len(fields)+3)
+	for key, value := range fields {
+		if err, ok := value.(error); ok {
+			value = err.Error()
+		}
+		record[key] = value
+	}
+	record["time"] = time.Now().Format(time.RFC3339)
+	record["level"] = level
+	record["msg"] = msg
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		fmt.Fprintf(w, "{\"level\":%q,\"msg\":%q}\n", LevelError, err.Error())
+		return
+	}
+	w.Write(append(data, '\n'))
+}
+
+// levelPrefixes asocia el emoji inicial de los mensajes existentes con su nivel
+var levelPrefixes = []struct {
+	prefix string
+	level  string
+}{
+	{"❌", LevelError},
+	{"🚨", LevelError},
+	{"⚠️", LevelWarn},
+	{"🧪", LevelDebug},
+}
+
+/**
+ * splitLevel - Deduce el nivel del emoji inicial y limpia el mensaje
+ *
+ * @private
+ * @returns {string, string} Nivel y mensaje sin emoji ni saltos de línea
+ */
+func splitLevel(text string) (string, string) {
+	msg := strings.TrimSpace(text)
+	level := LevelInfo
+	for _, p := range levelPrefixes {
+		if strings.HasPrefix(msg, p.prefix) {
+			level = p.level
+			break
+		}
+	}
+
+	// Quitar el emoji (y su selector de variación) que encabeza el texto
+	if i := strings.IndexByte(msg, ' '); i > 0 && !isASCIILetter(msg[0]) {
+		msg = strings.TrimSpace(msg[i:])
+	}
+	return level, msg
+}
+
+// isASCIILetter indica si el byte es una letra ASCII
+func isASCIILetter(b byte) bool {
+	return (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z')
+}
@@ -0,0 +1,225 @@
+package models
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"luznocturna/luz-nocturna/internal/paths"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Límites del historial de actividad: al superar activityTrimAt se conservan los últimos activityKeep
+const (
+	activityKeep   = 2000
+	activityTrimAt = 2500
+)
+
+// ActivityEntry es un cambio aplicado al display
+type ActivityEntry struct {
+	Time        time.Time `json:"time"`
+	Type        string    `json:"type"`        // "applied", "reset", "schedule-transition"
+	Source      string    `json:"source"`      // "manual", "preset", "scheduler", "dbus", "undo"...
+	Temperature float64   `json:"temperature"` // Temperatura en Kelvin tras el cambio
+	Active      bool      `json:"active"`      // Si el filtro quedó activo
+	Step        bool      `json:"-"`           // Paso intermedio de una transición de la programación (no se guarda)
+}
+
+/**
+ * ActivityLog - Historial persistente de los cambios aplicados
+ *
+ * Cada cambio se añade como una línea JSON en
+ * $XDG_STATE_HOME/luz-nocturna/activity.jsonl, para poder responder a
+ * "¿por qué cambió mi pantalla a las 3 de la tarde?". Las aplicaciones
+ * repetidas con el mismo resultado (el programador reaplica cada minuto)
+ * no se registran, y de una transición solo quedan el primer paso y la
+ * temperatura final.
+ *
+ * @struct {ActivityLog}
+ * @property {string} path - Archivo del historial
+ * @property {*ActivityEntry} last - Última entrada registrada (para omitir repeticiones)
+ * @property {bool} inTransition - La última entrada fue un paso intermedio de una transición
+ * @property {int} count - Entradas en el archivo
+ */
+type ActivityLog struct {
+	mu           sync.Mutex
+	path         string
+	last         *ActivityEntry
+	inTransition bool
+	count        int
+}
+
+// GetActivityLogPath devuelve la ruta del historial de actividad
+func GetActivityLogPath() string {
+	return filepath.Join(paths.StateDir(), "activity.jsonl")
+}
+
+/**
+ * NewActivityLog - Abre el historial de actividad
+ *
+ * @param {string} path - Archivo del historial (GetActivityLogPath() normalmente)
+ * @returns {*ActivityLog} Historial listo para añadir entradas
+ */
+func NewActivityLog(path string) *ActivityLog {
+	log := &ActivityLog{path: path}
+	if entries, err := log.readAll(); err == nil {
+		log.count = len(entries)
+		if len(entries) > 0 {
+			log.last = &entries[len(entries)-1]
+		}
+	}
+	return log
+}
+
+/**
+ * Append - Registra un cambio si difiere del anterior
+ *
+ * Los pasos intermedios de una transición solo se registran si son el
+ * primero: durante un fundido llega uno cada pocos segundos.
+ *
+ * @param {ActivityEntry} entry - Cambio aplicado
+ * @returns {error} Error de escritura
+ */
+func (l *ActivityLog) Append(entry ActivityEntry) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.last != nil && l.last.Type == entry.Type && l.last.Source == entry.Source &&
+		l.last.Temperature == entry.Temperature && l.last.Active == entry.Active {
+		return nil
+	}
+	if entry.Step && l.inTransition {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(l.path), 0755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(l.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(append(data, '\n'))
+	file.Close()
+	if err != nil {
+		return err
+	}
+
+	l.last = &entry
+	l.inTransition = entry.Step
+	l.count++
+	if l.count > activityTrimAt {
+		return l.trim()
+	}
+	return nil
+}
+
+/**
+ * Recent - Últimas entradas del historial, la más reciente primero
+ *
+ * @param {int} limit - Máximo de entradas (0 = todas)
+ * @returns {[]ActivityEntry, error} Entradas y error de lectura
+ */
+func (l *ActivityLog) Recent(limit int) ([]ActivityEntry, error) {
+	l.mu.Lock()
+	entries, err := l.readAll()
+	l.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	if limit > 0 && len(entries) > limit {
+		entries = entries[len(entries)-limit:]
+	}
+	for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+		entries[i], entries[j] = entries[j], entries[i]
+	}
+	return entries, nil
+}
+
+/**
+ * ExportCSV - Escribe todo el historial en formato CSV
+ *
+ * @param {io.Writer} w - Destino del CSV
+ * @returns {error} Error de lectura o escritura
+ */
+func (l *ActivityLog) ExportCSV(w io.Writer) error {
+	l.mu.Lock()
+	entries, err := l.readAll()
+	l.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"time", "type", "source", "temperature", "active"})
+	for _, entry := range entries {
+		writer.Write([]string{
+			entry.Time.Format(time.RFC3339),
+			entry.Type,
+			entry.Source,
+			strconv.FormatFloat(entry.Temperature, 'f', 0, 64),
+			strconv.FormatBool(entry.Active),
+		})
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// readAll lee todas las entradas válidas del archivo (sin bloquear; el llamador tiene mu)
+func (l *ActivityLog) readAll() ([]ActivityEntry, error) {
+	file, err := os.Open(l.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var entries []ActivityEntry
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry ActivityEntry
+		if json.Unmarshal(scanner.Bytes(), &entry) == nil {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, scanner.Err()
+}
+
+// trim reescribe el archivo conservando solo las últimas activityKeep entradas
+func (l *ActivityLog) trim() error {
+	entries, err := l.readAll()
+	if err != nil {
+		return err
+	}
+	if len(entries) > activityKeep {
+		entries = entries[len(entries)-activityKeep:]
+	}
+
+	var data []byte
+	for _, entry := range entries {
+		line, err := json.Marshal(entry)
+		if err != nil {
+			return fmt.Errorf("no se pudo serializar el historial: %w", err)
+		}
+		data = append(append(data, line...), '\n')
+	}
+
+	if err := writeFileAtomic(l.path, data, 0644); err != nil {
+		return err
+	}
+	l.count = len(entries)
+	return nil
+}
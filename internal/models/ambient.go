@@ -0,0 +1,88 @@
+package models
+
+import (
+	"fmt"
+	"math"
+)
+
+// Límites de la estimación de luz ambiental
+const (
+	DefaultAmbientInterval = 120   // Segundos entre muestras de la webcam
+	MinAmbientInterval     = 30    // Muestras más frecuentes encenderían la webcam casi sin parar
+	MaxAmbientInterval     = 3600  // Una muestra por hora como mínimo
+	DefaultAmbientWarmer   = 400.0 // Kelvin más cálida a oscuras
+	DefaultAmbientDim      = 0.3   // Fracción del brillo que se reduce a oscuras
+	MaxAmbientDim          = 0.6   // Reducción máxima del brillo
+)
+
+/**
+ * AmbientConfig - Ajuste adaptativo según la luz de la habitación
+ *
+ * Para portátiles sin sensor de luz: con Enabled se toma cada Interval
+ * segundos una muestra breve de la webcam (sin guardar fotogramas) y,
+ * cuanto más oscura está la habitación, más cálida y tenue se vuelve la
+ * pantalla, hasta Warmer Kelvin y Dim de brillo a oscuras.
+ *
+ * @struct {AmbientConfig}
+ * @example
+ *   AmbientConfig{Enabled: true, Interval: 300} // Una muestra cada 5 minutos
+ */
+type AmbientConfig struct {
+	Enabled  bool    `json:"enabled"`
+	Device   string  `json:"device"`   // Dispositivo V4L2 ("" = /dev/video0)
+	Interval int     `json:"interval"` // Segundos entre muestras (0 = DefaultAmbientInterval)
+	Warmer   float64 `json:"warmer"`   // Kelvin más cálida a oscuras (0 = DefaultAmbientWarmer)
+	Dim      float64 `json:"dim"`      // Fracción del brillo que se reduce a oscuras (0 = DefaultAmbientDim)
+}
+
+// GetInterval devuelve los segundos entre muestras; 0 o valores fuera de rango usan DefaultAmbientInterval
+func (ambient AmbientConfig) GetInterval() int {
+	if ambient.Interval < MinAmbientInterval || ambient.Interval > MaxAmbientInterval {
+		return DefaultAmbientInterval
+	}
+	return ambient.Interval
+}
+
+// GetWarmer devuelve los Kelvin que se restan a oscuras; 0 usa DefaultAmbientWarmer
+func (ambient AmbientConfig) GetWarmer() float64 {
+	if ambient.Warmer <= 0 {
+		return DefaultAmbientWarmer
+	}
+	return ambient.Warmer
+}
+
+// GetDim devuelve la fracción del brillo que se reduce a oscuras; 0 o valores fuera de rango usan DefaultAmbientDim
+func (ambient AmbientConfig) GetDim() float64 {
+	if ambient.Dim <= 0 || ambient.Dim > MaxAmbientDim {
+		return DefaultAmbientDim
+	}
+	return ambient.Dim
+}
+
+/**
+ * Adjust - Calcula la temperatura y el brillo según la luz de la habitación
+ *
+ * @param {float64} level - Luz estimada entre 0 (oscuridad) y 1
+ * @param {float64} temperature - Temperatura que se iba a aplicar
+ * @param {float64} brightness - Brillo que se iba a aplicar
+ * @param {float64} minTemp - Temperatura mínima admitida
+ * @returns {float64, float64} Temperatura y brillo ajustados
+ */
+func (ambient AmbientConfig) Adjust(level, temperature, brightness, minTemp float64) (float64, float64) {
+	darkness := 1 - math.Max(0, math.Min(1, level))
+	return math.Max(minTemp, temperature-ambient.GetWarmer()*darkness), brightness * (1 - ambient.GetDim()*darkness)
+}
+
+// Validate verifica los límites del ajuste por luz ambiental
+func (ambient AmbientConfig) Validate() error {
+	if ambient.Interval != 0 && (ambient.Interval < MinAmbientInterval || ambient.Interval > MaxAmbientInterval) {
+		return fmt.Errorf("interval: debe estar entre %d y %d segundos", MinAmbientInterval, MaxAmbientInterval)
+	}
+	if ambient.Warmer < 0 {
+		return fmt.Errorf("warmer: no puede ser negativo")
+	}
+	if ambient.Dim < 0 || ambient.Dim > MaxAmbientDim {
+		return fmt.Errorf("dim: debe estar entre 0 y %.1f", MaxAmbientDim)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+/**
+ * AppRule - Temperatura especial mientras una aplicación tiene el foco
+ *
+ * Mientras la ventana enfocada coincide con Match se aplica Temperature
+ * (o se quita el filtro con Disable) sin cambiar la temperatura elegida
+ * por el usuario; al cambiar de ventana se vuelve a ella. Solo actúa
+ * cuando el filtro está activo.
+ *
+ * @struct {AppRule}
+ * @example
+ *   AppRule{Match: "darktable", Disable: true} // Colores reales al revelar fotos
+ */
+type AppRule struct {
+	Match       string  `json:"match"`       // WM_CLASS (X11) o app_id (Wayland), sin distinguir mayúsculas
+	Temperature float64 `json:"temperature"` // Temperatura en Kelvin mientras tiene el foco
+	Disable     bool    `json:"disable"`     // Quitar el filtro mientras tiene el foco
+}
+
+// Matches indica si alguno de los identificadores de la ventana coincide con la regla
+func (rule AppRule) Matches(ids []string) bool {
+	for _, id := range ids {
+		if strings.EqualFold(strings.TrimSpace(rule.Match), id) {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate verifica que la regla identifique una aplicación y tenga un efecto válido
+func (rule AppRule) Validate(minTemp, maxTemp float64) error {
+	if strings.TrimSpace(rule.Match) == "" {
+		return fmt.Errorf("falta la aplicación (match)")
+	}
+	if !rule.Disable && (rule.Temperature < minTemp || rule.Temperature > maxTemp) {
+		return fmt.Errorf("%s: temperatura fuera de rango (%.0fK - %.0fK)", rule.Match, minTemp, maxTemp)
+	}
+	return nil
+}
+
+// FindAppRule devuelve la primera regla que coincide con la ventana enfocada
+func FindAppRule(rules []AppRule, ids []string) (AppRule, bool) {
+	for _, rule := range rules {
+		if rule.Matches(ids) {
+			return rule, true
+		}
+	}
+	return AppRule{}, false
+}
@@ -0,0 +1,115 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Ajuste por defecto con batería
+const (
+	DefaultBatteryWarmer = 500.0 // Kelvin más cálida
+	DefaultBatteryDim    = 0.15  // Fracción del brillo que se reduce
+	MaxBatteryDim        = 0.5   // Reducción máxima del brillo
+)
+
+/**
+ * BatteryConfig - Perfil de ahorro al funcionar con batería
+ *
+ * Con Enabled, mientras el equipo funciona con batería (o por debajo de
+ * Threshold %) la temperatura aplicada es más cálida y el brillo más
+ * tenue; al conectar el cargador se vuelve al estado del usuario. Si
+ * Preset nombra un preset, se usan su temperatura y su brillo en lugar
+ * del ajuste relativo.
+ *
+ * @struct {BatteryConfig}
+ * @example
+ *   BatteryConfig{Enabled: true, Threshold: 30}      // Por debajo del 30%: 500K más cálida y 15% más tenue
+ *   BatteryConfig{Enabled: true, Preset: "Cálida"}   // Con batería: el preset "Cálida"
+ */
+type BatteryConfig struct {
+	Enabled   bool    `json:"enabled"`
+	Threshold int     `json:"threshold"` // Porcentaje por debajo del cual se aplica (0 = siempre con batería)
+	Warmer    float64 `json:"warmer"`    // Kelvin más cálida (0 = DefaultBatteryWarmer)
+	Dim       float64 `json:"dim"`       // Fracción del brillo que se reduce (0 = DefaultBatteryDim)
+	Preset    string  `json:"preset"`    // Preset a usar con batería en lugar del ajuste relativo
+}
+
+// Applies indica si el perfil debe aplicarse con el estado de energía dado
+func (battery BatteryConfig) Applies(onBattery bool, percentage float64) bool {
+	if !battery.Enabled || !onBattery {
+		return false
+	}
+	return battery.Threshold <= 0 || percentage <= float64(battery.Threshold)
+}
+
+// GetWarmer devuelve los Kelvin que se restan con batería; 0 usa DefaultBatteryWarmer
+func (battery BatteryConfig) GetWarmer() float64 {
+	if battery.Warmer <= 0 {
+		return DefaultBatteryWarmer
+	}
+	return battery.Warmer
+}
+
+// GetDim devuelve la fracción del brillo que se reduce con batería; 0 o valores fuera de rango usan DefaultBatteryDim
+func (battery BatteryConfig) GetDim() float64 {
+	if battery.Dim <= 0 || battery.Dim > MaxBatteryDim {
+		return DefaultBatteryDim
+	}
+	return battery.Dim
+}
+
+/**
+ * Adjust - Calcula la temperatura y el brillo con el perfil de batería
+ *
+ * Nunca enfría: si el preset o el estado del usuario ya son más cálidos,
+ * se queda la temperatura más cálida de las dos.
+ *
+ * @param {float64} temperature - Temperatura que se iba a aplicar
+ * @param {float64} brightness - Brillo que se iba a aplicar
+ * @param {float64} minTemp - Temperatura mínima admitida
+ * @param {[]Preset} presets - Presets del usuario (para Preset)
+ * @returns {float64, float64} Temperatura y brillo ajustados
+ */
+func (battery BatteryConfig) Adjust(temperature, brightness, minTemp float64, presets []Preset) (float64, float64) {
+	if preset, found := findPresetByName(presets, battery.Preset); found {
+		if preset.HasBrightness() {
+			brightness = math.Min(brightness, preset.Brightness)
+		}
+		return math.Min(temperature, preset.Temperature), brightness
+	}
+	return math.Max(minTemp, temperature-battery.GetWarmer()), brightness * (1 - battery.GetDim())
+}
+
+// Validate verifica los límites del perfil de batería
+func (battery BatteryConfig) Validate(presets []Preset) error {
+	if battery.Threshold < 0 || battery.Threshold > 100 {
+		return fmt.Errorf("threshold: debe estar entre 0 y 100")
+	}
+	if battery.Warmer < 0 {
+		return fmt.Errorf("warmer: no puede ser negativo")
+	}
+	if battery.Dim < 0 || battery.Dim > MaxBatteryDim {
+		return fmt.Errorf("dim: debe estar entre 0 y %.1f", MaxBatteryDim)
+	}
+	if battery.Preset != "" {
+		if _, found := findPresetByName(presets, battery.Preset); !found {
+			return fmt.Errorf("preset: no existe el preset %q", battery.Preset)
+		}
+	}
+	return nil
+}
+
+// findPresetByName busca un preset por nombre sin distinguir mayúsculas
+func findPresetByName(presets []Preset, name string) (Preset, bool) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return Preset{}, false
+	}
+	for _, preset := range presets {
+		if strings.EqualFold(preset.Name, name) {
+			return preset, true
+		}
+	}
+	return Preset{}, false
+}
@@ -0,0 +1,81 @@
+package models
+
+import "fmt"
+
+// Límites del ajuste fino de cada canal: lo justo para compensar el tinte
+// verde o magenta de un panel sin llegar a cambiar la temperatura
+const (
+	MinChannelTrim = 0.8
+	MaxChannelTrim = 1.2
+)
+
+/**
+ * ChannelTrim - Ajuste fino de los canales de un display
+ *
+ * Multiplica los factores RGB que salen de la temperatura. Un valor 0
+ * (campo ausente en config.json) equivale a 1.0, sin cambio.
+ *
+ * @struct {ChannelTrim}
+ * @property {float64} Red - Multiplicador del rojo (0.8-1.2)
+ * @property {float64} Green - Multiplicador del verde (0.8-1.2)
+ * @property {float64} Blue - Multiplicador del azul (0.8-1.2)
+ */
+type ChannelTrim struct {
+	Red   float64 `json:"red,omitempty"`
+	Green float64 `json:"green,omitempty"`
+	Blue  float64 `json:"blue,omitempty"`
+}
+
+// ChannelTrims guarda el ajuste fino de cada display por su nombre de conector ("HDMI-1")
+type ChannelTrims map[string]ChannelTrim
+
+// Factors devuelve los multiplicadores RGB, con 1.0 en los canales sin ajuste
+func (trim ChannelTrim) Factors() [3]float64 {
+	factors := [3]float64{trim.Red, trim.Green, trim.Blue}
+	for i, factor := range factors {
+		if factor == 0 {
+			factors[i] = 1.0
+		}
+	}
+	return factors
+}
+
+// IsNeutral indica si el ajuste no cambia ningún canal
+func (trim ChannelTrim) IsNeutral() bool {
+	return trim.Factors() == [3]float64{1.0, 1.0, 1.0}
+}
+
+// Validate comprueba que cada canal esté entre MinChannelTrim y MaxChannelTrim
+func (trim ChannelTrim) Validate() error {
+	names := [3]string{"red", "green", "blue"}
+	for i, factor := range trim.Factors() {
+		if factor < MinChannelTrim || factor > MaxChannelTrim {
+			return fmt.Errorf("%s: debe estar entre %.1f y %.1f", names[i], MinChannelTrim, MaxChannelTrim)
+		}
+	}
+	return nil
+}
+
+// Validate comprueba el ajuste de cada display
+func (trims ChannelTrims) Validate() error {
+	for display, trim := range trims {
+		if display == "" {
+			return fmt.Errorf("el nombre del display no puede estar vacío")
+		}
+		if err := trim.Validate(); err != nil {
+			return fmt.Errorf("%s.%w", display, err)
+		}
+	}
+	return nil
+}
+
+// Factors devuelve los multiplicadores RGB de los displays con algún ajuste
+func (trims ChannelTrims) Factors() map[string][3]float64 {
+	factors := make(map[string][3]float64, len(trims))
+	for display, trim := range trims {
+		if !trim.IsNeutral() {
+			factors[display] = trim.Factors()
+		}
+	}
+	return factors
+}
@@ -0,0 +1,148 @@
+package models
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// Formatos de hora para los horarios, el próximo cambio y las notificaciones
+const (
+	ClockFormatAuto = "auto" // Según el idioma y la región de LC_TIME/LANG
+	ClockFormat24h  = "24h"  // 21:30
+	ClockFormat12h  = "12h"  // 9:30 p. m.
+)
+
+// twelveHourRegions son las regiones cuyo formato de hora habitual es de 12 horas
+var twelveHourRegions = map[string]bool{
+	"US": true, "CA": true, "AU": true, "NZ": true, "PH": true, "IN": true, "PK": true,
+	"EG": true, "SA": true, "CO": true, "MX": true, "VE": true, "DO": true, "PR": true,
+	"SV": true, "HN": true, "NI": true, "GT": true,
+}
+
+/**
+ * Clock - Formato de hora resuelto (12 o 24 horas y sufijos del idioma)
+ *
+ * @struct {Clock}
+ * @property {bool} TwelveHour - Usar el reloj de 12 horas
+ * @property {string} AM - Sufijo de la mañana ("a. m." en español, "AM" en otros idiomas)
+ * @property {string} PM - Sufijo de la tarde
+ */
+type Clock struct {
+	TwelveHour bool
+	AM         string
+	PM         string
+}
+
+/**
+ * NewClock - Resuelve la preferencia de formato de hora
+ *
+ * En modo automático se consulta la configuración regional (LC_ALL,
+ * LC_TIME o LANG, como hace libc): "es_CO.UTF-8" usa 12 horas y
+ * "es_ES.UTF-8" 24 horas.
+ *
+ * @param {string} format - ClockFormatAuto, ClockFormat24h o ClockFormat12h
+ * @returns {Clock} Formato listo para usar
+ * @example
+ *   clock := NewClock(config.GetClockFormat())
+ *   clock.FormatTime(time.Now()) // "9:30 p. m."
+ */
+func NewClock(format string) Clock {
+	language, region := currentLocale()
+
+	clock := Clock{AM: "AM", PM: "PM"}
+	if language == "es" {
+		clock.AM, clock.PM = "a. m.", "p. m."
+	}
+
+	switch format {
+	case ClockFormat12h:
+		clock.TwelveHour = true
+	case ClockFormat24h:
+		clock.TwelveHour = false
+	default:
+		clock.TwelveHour = twelveHourRegions[region]
+	}
+	return clock
+}
+
+// currentLocale devuelve idioma y región de la configuración regional de la hora ("es", "CO")
+func currentLocale() (language, region string) {
+	locale := ""
+	for _, name := range []string{"LC_ALL", "LC_TIME", "LANG"} {
+		if locale = os.Getenv(name); locale != "" {
+			break
+		}
+	}
+
+	// "es_CO.UTF-8@euro" → "es_CO"
+	locale, _, _ = strings.Cut(locale, ".")
+	locale, _, _ = strings.Cut(locale, "@")
+	language, region, _ = strings.Cut(locale, "_")
+	return strings.ToLower(language), strings.ToUpper(region)
+}
+
+/**
+ * Format - Formatea una hora del día
+ *
+ * @param {int} hours - Hora de 0 a 23
+ * @param {int} minutes - Minutos de 0 a 59
+ * @returns {string} "21:30" o "9:30 p. m."
+ */
+func (clock Clock) Format(hours, minutes int) string {
+	if !clock.TwelveHour {
+		return fmt.Sprintf("%02d:%02d", hours, minutes)
+	}
+
+	suffix := clock.AM
+	if hours >= 12 {
+		suffix = clock.PM
+	}
+	hours %= 12
+	if hours == 0 {
+		hours = 12
+	}
+	return fmt.Sprintf("%d:%02d %s", hours, minutes, suffix)
+}
+
+// FormatTime formatea la hora del día de t
+func (clock Clock) FormatTime(t time.Time) string {
+	return clock.Format(t.Hour(), t.Minute())
+}
+
+// FormatScheduleTime muestra una hora "HH:MM" de la configuración en este formato (sin cambios si no es válida)
+func (clock Clock) FormatScheduleTime(timeStr string) string {
+	hours, minutes, err := ParseScheduleTime(timeStr)
+	if err != nil {
+		return timeStr
+	}
+	return clock.Format(hours, minutes)
+}
+
+// Placeholder es el texto de ayuda para las entradas de hora
+func (clock Clock) Placeholder() string {
+	if clock.TwelveHour {
+		return "h:mm " + clock.PM
+	}
+	return "HH:MM"
+}
+
+/**
+ * NormalizeScheduleTime - Convierte una hora escrita en 12 o 24 horas a "HH:MM"
+ *
+ * La configuración siempre guarda las horas en 24 horas, sea cual sea
+ * el formato en que se muestran.
+ *
+ * @param {string} timeStr - Hora como "21:30", "9:30 PM" o "9:30 p. m."
+ * @returns {string, error} Hora "HH:MM" o error si no es válida
+ * @example
+ *   NormalizeScheduleTime("9:30 p. m.") // "21:30", nil
+ */
+func NormalizeScheduleTime(timeStr string) (string, error) {
+	hours, minutes, err := ParseScheduleTime(timeStr)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%02d:%02d", hours, minutes), nil
+}
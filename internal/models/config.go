@@ -2,28 +2,419 @@ package models
 
 import (
 	"encoding/json"
+	"fmt"
+	"luznocturna/luz-nocturna/internal/logging"
+	"luznocturna/luz-nocturna/internal/paths"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 )
 
 // AppConfig representa la configuración persistente de la aplicación
 type AppConfig struct {
-	LastTemperature float64        `json:"last_temperature"`
-	AutoStart       bool           `json:"auto_start"`
-	MinimizeToTray  bool           `json:"minimize_to_tray"`
-	StartMinimized  bool           `json:"start_minimized"`
-	ScheduleEnabled bool           `json:"schedule_enabled"`
-	Schedule        ScheduleConfig `json:"schedule"`
+	LastTemperature  float64             `json:"last_temperature"`
+	AutoStart        bool                `json:"auto_start"`
+	MinimizeToTray   bool                `json:"minimize_to_tray"`
+	CloseBehavior    string              `json:"close_behavior"`     // Qué hacer al cerrar la ventana: "tray", "quit" o "ask"
+	ResetOnQuit      bool                `json:"reset_on_quit"`      // Restaurar la gamma normal al salir de la aplicación
+	ResetWhileLocked bool                `json:"reset_while_locked"` // Quitar el filtro mientras la sesión está bloqueada
+	PauseWhileIdle   bool                `json:"pause_while_idle"`   // Sin cambios automáticos mientras la sesión está inactiva (logind IdleHint)
+	SuspendForGames  bool                `json:"suspend_for_games"`  // Quitar el filtro mientras gamescope o Steam Big Picture están en marcha
+	StartMinimized   bool                `json:"start_minimized"`
+	ScheduleEnabled  bool                `json:"schedule_enabled"`
+	Schedule         ScheduleConfig      `json:"schedule"`
+	Presets          []Preset            `json:"presets"`
+	Window           WindowState         `json:"window"`
+	SnapToPresets    bool                `json:"snap_to_presets"` // El slider se ajusta a los presets cercanos
+	FineSteps        bool                `json:"fine_steps"`      // El slider avanza de 10K en lugar de 100K
+	Watchdog         WatchdogConfig      `json:"watchdog"`
+	ExcludedDisplays []string            `json:"excluded_displays"` // Displays que el filtro no modifica
+	ChannelTrims     ChannelTrims        `json:"channel_trims"`     // Ajuste fino RGB de cada display (solo X11)
+	DisplayScope     string              `json:"display_scope"`     // Monitores que reciben el filtro: "all", "external" o "internal"
+	LayoutMode       string              `json:"layout_mode"`       // Tamaño de los controles: "auto", "normal" o "touch"
+	ClockFormat      string              `json:"clock_format"`      // Formato de hora mostrado: "auto", "24h" o "12h"
+	TemperatureUnit  string              `json:"temperature_unit"`  // Cómo se muestra la temperatura: "kelvin" o "warmth"
+	Hooks            HooksConfig         `json:"hooks"`             // Comandos del usuario al cambiar el estado
+	AppRules         []AppRule           `json:"app_rules"`         // Temperatura por aplicación enfocada
+	WorkspaceRules   []WorkspaceRule     `json:"workspace_rules"`   // Temperatura por espacio de trabajo (Sway/i3)
+	Battery          BatteryConfig       `json:"battery"`           // Perfil más cálido y tenue con batería
+	Weather          WeatherConfig       `json:"weather"`           // Temperatura diurna más cálida en días nublados
+	Ambient          AmbientConfig       `json:"ambient"`           // Ajuste según la luz de la habitación (webcam)
+	MovieDuration    int                 `json:"movie_duration"`    // Minutos que dura el modo película (0 = DefaultMovieDuration)
+	AccurateDuration int                 `json:"accurate_duration"` // Minutos que dura el modo de color fiel (0 = DefaultAccurateDuration)
+	Vacation         VacationConfig      `json:"vacation"`          // Fechas en las que la programación y el vigilante se pausan
+	GRPC             GRPCConfig          `json:"grpc"`              // Servidor gRPC de control remoto (opcional)
+	TrayIcon         string              `json:"tray_icon"`         // "auto", "color", "light", "dark" o la ruta absoluta de un PNG
+	TrayBadge        bool                `json:"tray_badge"`        // Mostrar la temperatura aplicada sobre el icono de la bandeja
+	LayoutProfiles   []LayoutProfile     `json:"layout_profiles"`   // Preset según los monitores conectados
+	FollowDarkMode   bool                `json:"follow_dark_mode"`  // Temperatura nocturna con el tema oscuro del escritorio y diurna con el claro
+	Notifications    NotificationsConfig `json:"notifications"`     // Qué notificaciones respetan "No molestar"
+
+	firstRun bool // No existía config.json al cargar: primera ejecución
+}
+
+// IsFirstRun indica si Load creó la configuración por no existir el archivo
+func (config *AppConfig) IsFirstRun() bool {
+	return config.firstRun
+}
+
+// WatchdogConfig controla la vigilancia periódica de la gamma aplicada.
+// Algunos compositores y aplicaciones (juegos, reproductores) restauran la
+// gamma por su cuenta; el vigilante la comprueba y la vuelve a aplicar.
+type WatchdogConfig struct {
+	Enabled  bool `json:"enabled"`
+	Interval int  `json:"interval"` // Segundos entre comprobaciones
+}
+
+// Límites del intervalo del vigilante de gamma, en segundos
+const (
+	DefaultWatchdogInterval = 30
+	MinWatchdogInterval     = 5
+	MaxWatchdogInterval     = 3600
+)
+
+// GetInterval devuelve el intervalo entre comprobaciones; los valores
+// ausentes o fuera de rango usan DefaultWatchdogInterval
+func (watchdog WatchdogConfig) GetInterval() time.Duration {
+	seconds := watchdog.Interval
+	if seconds < MinWatchdogInterval || seconds > MaxWatchdogInterval {
+		seconds = DefaultWatchdogInterval
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// Duración del modo película, en minutos
+const (
+	DefaultMovieDuration = 120
+	MaxMovieDuration     = 8 * 60
+)
+
+// GetMovieDuration devuelve cuánto dura el modo película; los valores
+// ausentes o fuera de rango usan DefaultMovieDuration
+func (config *AppConfig) GetMovieDuration() time.Duration {
+	minutes := config.MovieDuration
+	if minutes < 1 || minutes > MaxMovieDuration {
+		minutes = DefaultMovieDuration
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// Duración del modo de color fiel, en minutos
+const DefaultAccurateDuration = 30
+
+// GetAccurateDuration devuelve cuánto dura el modo de color fiel; los
+// valores ausentes o fuera de rango usan DefaultAccurateDuration
+func (config *AppConfig) GetAccurateDuration() time.Duration {
+	minutes := config.AccurateDuration
+	if minutes < 1 || minutes > MaxMovieDuration {
+		minutes = DefaultAccurateDuration
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// Monitores que reciben el filtro según el tipo de conector
+const (
+	DisplayScopeAll      = "all"      // Todos los monitores
+	DisplayScopeExternal = "external" // Solo monitores externos (HDMI, DP...)
+	DisplayScopeInternal = "internal" // Solo el panel integrado (eDP, LVDS...)
+)
+
+// GetDisplayScope devuelve qué monitores reciben el filtro; los valores
+// ausentes o desconocidos usan DisplayScopeAll
+func (config *AppConfig) GetDisplayScope() string {
+	switch config.DisplayScope {
+	case DisplayScopeExternal, DisplayScopeInternal:
+		return config.DisplayScope
+	}
+	return DisplayScopeAll
+}
+
+// Tamaños de los controles de la ventana principal
+const (
+	LayoutModeAuto   = "auto"   // Controles grandes solo si Fyne detecta un dispositivo táctil
+	LayoutModeNormal = "normal" // Controles de escritorio
+	LayoutModeTouch  = "touch"  // Controles grandes y más espaciado para pantallas táctiles
+)
+
+// Iconos de la bandeja; cualquier otro valor es la ruta absoluta de un PNG propio
+const (
+	TrayIconAuto  = "auto"  // Simbólico claro u oscuro según el tema del escritorio
+	TrayIconColor = "color" // Icono a color original
+	TrayIconLight = "light" // Simbólico claro, para paneles oscuros
+	TrayIconDark  = "dark"  // Simbólico oscuro, para paneles claros
+)
+
+// GetTrayIcon devuelve el icono de la bandeja o la ruta del PNG propio; el
+// valor ausente usa TrayIconAuto
+func (config *AppConfig) GetTrayIcon() string {
+	if config.TrayIcon == "" {
+		return TrayIconAuto
+	}
+	return config.TrayIcon
+}
+
+// GetLayoutMode devuelve el tamaño de los controles; los valores ausentes
+// o desconocidos usan LayoutModeAuto
+func (config *AppConfig) GetLayoutMode() string {
+	switch config.LayoutMode {
+	case LayoutModeNormal, LayoutModeTouch:
+		return config.LayoutMode
+	}
+	return LayoutModeAuto
+}
+
+// GetClockFormat devuelve el formato de hora preferido; los valores
+// ausentes o desconocidos usan ClockFormatAuto
+func (config *AppConfig) GetClockFormat() string {
+	switch config.ClockFormat {
+	case ClockFormat24h, ClockFormat12h:
+		return config.ClockFormat
+	}
+	return ClockFormatAuto
+}
+
+// GetTemperatureUnit devuelve cómo se muestra la temperatura; los valores
+// ausentes o desconocidos usan TemperatureUnitKelvin
+func (config *AppConfig) GetTemperatureUnit() string {
+	if config.TemperatureUnit == TemperatureUnitWarmth {
+		return TemperatureUnitWarmth
+	}
+	return TemperatureUnitKelvin
+}
+
+// WindowState guarda la geometría de la ventana principal entre sesiones.
+// Fyne no expone la posición de la ventana, así que solo se guarda el tamaño;
+// el gestor de ventanas decide dónde colocarla.
+type WindowState struct {
+	Width  float32 `json:"width"`
+	Height float32 `json:"height"`
+	Tab    string  `json:"tab"` // Última pestaña seleccionada
+}
+
+// HasSize indica si hay un tamaño guardado
+func (state WindowState) HasSize() bool {
+	return state.Width > 0 && state.Height > 0
 }
 
 // ScheduleConfig representa la configuración de horarios automáticos
 type ScheduleConfig struct {
-	StartTime          string  `json:"start_time"`           // Formato "HH:MM" para inicio del filtro nocturno
-	EndTime            string  `json:"end_time"`             // Formato "HH:MM" para fin del filtro nocturno
-	NightTemp          float64 `json:"night_temp"`           // Temperatura nocturna (ej: 3000K)
-	DayTemp            float64 `json:"day_temp"`             // Temperatura diurna (ej: 6500K)
-	TransitionTime     int     `json:"transition_time"`      // Tiempo de transición en minutos
-	AutoDetectLocation bool    `json:"auto_detect_location"` // Detectar ubicación para sunrise/sunset automático
+	StartTime          string       `json:"start_time"`           // Formato "HH:MM" para inicio del filtro nocturno
+	EndTime            string       `json:"end_time"`             // Formato "HH:MM" para fin del filtro nocturno
+	NightTemp          float64      `json:"night_temp"`           // Temperatura nocturna (ej: 3000K)
+	DayTemp            float64      `json:"day_temp"`             // Temperatura diurna (ej: 6500K)
+	TransitionTime     int          `json:"transition_time"`      // Tiempo de transición en minutos
+	AutoDetectLocation bool         `json:"auto_detect_location"` // Detectar ubicación para sunrise/sunset automático
+	Mode               string       `json:"mode"`                 // Modo de cálculo: "fixed" (horas fijas) o "solar"
+	Location           Location     `json:"location"`             // Ubicación usada por el modo solar
+	Interpolation      string       `json:"interpolation"`        // Unidad de las transiciones: "mired" o "kelvin"
+	Twilight           string       `json:"twilight"`             // Límite de la noche en modo solar: "civil", "nautical" o "astronomical"
+	SunsetOffset       int          `json:"sunset_offset"`        // Minutos que se desplaza el borde de la tarde (negativo = antes)
+	SunriseOffset      int          `json:"sunrise_offset"`       // Minutos que se desplaza el borde de la mañana (negativo = antes)
+	WakeUp             WakeUpConfig `json:"wake_up"`              // Simulación de amanecer para despertar
+	SleepMode          SleepConfig  `json:"sleep_mode"`           // Escala de grises a altas horas de la noche
+	NightDim           float64      `json:"night_dim,omitempty"`  // Atenuación nocturna por software (0 = ninguna, 0.3 = 30 % menos brillo)
+}
+
+// MaxNightDim es la atenuación nocturna máxima (deja al menos un 10 % de brillo)
+const MaxNightDim = 0.9
+
+// MaxSolarOffset es el desplazamiento máximo de cada borde solar, en minutos
+const MaxSolarOffset = 180
+
+// GetTwilight devuelve el crepúsculo que limita la noche; los valores ausentes o desconocidos usan el civil
+func (schedule ScheduleConfig) GetTwilight() string {
+	if _, ok := twilightElevations[schedule.Twilight]; ok {
+		return schedule.Twilight
+	}
+	return TwilightCivil
+}
+
+// Modos de cálculo de la programación automática
+const (
+	ScheduleModeFixed = "fixed" // Horas de inicio/fin fijas con transición lineal
+	ScheduleModeSolar = "solar" // Curva continua según la elevación del sol
+)
+
+// Unidades de interpolación de las transiciones
+const (
+	InterpolationMired  = "mired"  // Lineal en mired (1e6/K): el cambio de calidez se percibe uniforme
+	InterpolationKelvin = "kelvin" // Lineal en Kelvin (comportamiento anterior)
+)
+
+// GetInterpolation devuelve la unidad de interpolación; las configuraciones
+// antiguas sin interpolation usan mired
+func (schedule ScheduleConfig) GetInterpolation() string {
+	if schedule.Interpolation == InterpolationKelvin {
+		return InterpolationKelvin
+	}
+	return InterpolationMired
+}
+
+// Comportamientos al cerrar la ventana principal
+const (
+	CloseBehaviorTray = "tray" // Ocultar la ventana y seguir en la bandeja
+	CloseBehaviorQuit = "quit" // Salir de la aplicación
+	CloseBehaviorAsk  = "ask"  // Preguntar cada vez
+)
+
+// GetCloseBehavior devuelve el comportamiento al cerrar; las configuraciones
+// antiguas sin close_behavior se derivan de minimize_to_tray
+func (config *AppConfig) GetCloseBehavior() string {
+	switch config.CloseBehavior {
+	case CloseBehaviorTray, CloseBehaviorQuit, CloseBehaviorAsk:
+		return config.CloseBehavior
+	}
+	if config.MinimizeToTray {
+		return CloseBehaviorTray
+	}
+	return CloseBehaviorQuit
+}
+
+/**
+ * Validate - Verifica que toda la configuración sea utilizable
+ *
+ * La usa "config set" antes de guardar un valor escrito a mano. Los
+ * campos de texto vacíos se aceptan porque sus getters aplican el valor
+ * por defecto.
+ *
+ * @returns {error} Primer problema encontrado, con el nombre de la clave JSON
+ */
+func (config *AppConfig) Validate() error {
+	limits := NewNightLightConfig()
+	if config.LastTemperature < limits.MinTemp || config.LastTemperature > limits.MaxTemp {
+		return fmt.Errorf("last_temperature: fuera de rango (%.0fK - %.0fK)", limits.MinTemp, limits.MaxTemp)
+	}
+	if err := config.Schedule.Validate(); err != nil {
+		return fmt.Errorf("schedule: %w", err)
+	}
+	if config.Schedule.NightTemp < limits.MinTemp || config.Schedule.NightTemp > limits.MaxTemp ||
+		config.Schedule.DayTemp < limits.MinTemp || config.Schedule.DayTemp > limits.MaxTemp {
+		return fmt.Errorf("schedule: temperaturas fuera de rango (%.0fK - %.0fK)", limits.MinTemp, limits.MaxTemp)
+	}
+
+	checks := []struct {
+		key, value string
+		allowed    []string
+	}{
+		{"close_behavior", config.CloseBehavior, []string{CloseBehaviorTray, CloseBehaviorQuit, CloseBehaviorAsk}},
+		{"display_scope", config.DisplayScope, []string{DisplayScopeAll, DisplayScopeExternal, DisplayScopeInternal}},
+		{"layout_mode", config.LayoutMode, []string{LayoutModeAuto, LayoutModeNormal, LayoutModeTouch}},
+		{"clock_format", config.ClockFormat, []string{ClockFormatAuto, ClockFormat24h, ClockFormat12h}},
+		{"temperature_unit", config.TemperatureUnit, []string{TemperatureUnitKelvin, TemperatureUnitWarmth}},
+		{"schedule.mode", config.Schedule.Mode, []string{ScheduleModeFixed, ScheduleModeSolar}},
+		{"schedule.interpolation", config.Schedule.Interpolation, []string{InterpolationMired, InterpolationKelvin}},
+	}
+	for _, check := range checks {
+		if check.value != "" && !containsString(check.allowed, check.value) {
+			return fmt.Errorf("%s: %q no es válido (opciones: %s)", check.key, check.value, strings.Join(check.allowed, ", "))
+		}
+	}
+
+	trayIcons := []string{TrayIconAuto, TrayIconColor, TrayIconLight, TrayIconDark}
+	if icon := config.TrayIcon; icon != "" && !containsString(trayIcons, icon) && !filepath.IsAbs(icon) {
+		return fmt.Errorf("tray_icon: %q no es válido (opciones: %s o la ruta absoluta de un PNG)", icon, strings.Join(trayIcons, ", "))
+	}
+	if _, _, err := config.GRPC.Endpoint(); err != nil {
+		return fmt.Errorf("grpc.address: %w", err)
+	}
+
+	location := config.Schedule.Location
+	if location.Latitude < -90 || location.Latitude > 90 || location.Longitude < -180 || location.Longitude > 180 {
+		return fmt.Errorf("schedule.location: coordenadas fuera de rango")
+	}
+	if interval := config.Watchdog.Interval; interval != 0 && (interval < MinWatchdogInterval || interval > MaxWatchdogInterval) {
+		return fmt.Errorf("watchdog.interval: debe estar entre %d y %d segundos", MinWatchdogInterval, MaxWatchdogInterval)
+	}
+	if minutes := config.MovieDuration; minutes != 0 && (minutes < 1 || minutes > MaxMovieDuration) {
+		return fmt.Errorf("movie_duration: debe estar entre 1 y %d minutos", MaxMovieDuration)
+	}
+	if minutes := config.AccurateDuration; minutes != 0 && (minutes < 1 || minutes > MaxMovieDuration) {
+		return fmt.Errorf("accurate_duration: debe estar entre 1 y %d minutos", MaxMovieDuration)
+	}
+	for i, preset := range config.Presets {
+		if err := preset.Validate(limits.MinTemp, limits.MaxTemp); err != nil {
+			return fmt.Errorf("presets[%d]: %w", i, err)
+		}
+	}
+	for i, rule := range config.AppRules {
+		if err := rule.Validate(limits.MinTemp, limits.MaxTemp); err != nil {
+			return fmt.Errorf("app_rules[%d]: %w", i, err)
+		}
+	}
+	for i, rule := range config.WorkspaceRules {
+		if err := rule.Validate(limits.MinTemp, limits.MaxTemp); err != nil {
+			return fmt.Errorf("workspace_rules[%d]: %w", i, err)
+		}
+	}
+	for i, profile := range config.LayoutProfiles {
+		if err := profile.Validate(config.Presets); err != nil {
+			return fmt.Errorf("layout_profiles[%d]: %w", i, err)
+		}
+	}
+	if err := config.Battery.Validate(config.Presets); err != nil {
+		return fmt.Errorf("battery.%w", err)
+	}
+	if err := config.Weather.Validate(); err != nil {
+		return fmt.Errorf("weather.%w", err)
+	}
+	if err := config.Ambient.Validate(); err != nil {
+		return fmt.Errorf("ambient.%w", err)
+	}
+	if err := config.Vacation.Validate(); err != nil {
+		return fmt.Errorf("vacation.%w", err)
+	}
+	if err := config.Notifications.Validate(); err != nil {
+		return fmt.Errorf("notifications.%w", err)
+	}
+	if err := config.ChannelTrims.Validate(); err != nil {
+		return fmt.Errorf("channel_trims.%w", err)
+	}
+	return nil
+}
+
+// containsString indica si value está en list
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate verifica que la configuración de horarios sea coherente
+func (schedule ScheduleConfig) Validate() error {
+	if _, _, err := ParseScheduleTime(schedule.StartTime); err != nil {
+		return fmt.Errorf("inicio: %w", err)
+	}
+	if _, _, err := ParseScheduleTime(schedule.EndTime); err != nil {
+		return fmt.Errorf("fin: %w", err)
+	}
+	if schedule.NightTemp <= 0 || schedule.DayTemp <= 0 {
+		return fmt.Errorf("las temperaturas deben ser positivas")
+	}
+	if schedule.TransitionTime < 0 || schedule.TransitionTime > 12*60 {
+		return fmt.Errorf("tiempo de transición fuera de rango: %d min", schedule.TransitionTime)
+	}
+	if _, ok := twilightElevations[schedule.Twilight]; schedule.Twilight != "" && !ok {
+		return fmt.Errorf("crepúsculo desconocido: %s (opciones: civil, nautical, astronomical)", schedule.Twilight)
+	}
+	for _, offset := range []int{schedule.SunsetOffset, schedule.SunriseOffset} {
+		if offset < -MaxSolarOffset || offset > MaxSolarOffset {
+			return fmt.Errorf("los desplazamientos solares deben estar entre -%d y %d minutos", MaxSolarOffset, MaxSolarOffset)
+		}
+	}
+	if schedule.NightDim < 0 || schedule.NightDim > MaxNightDim {
+		return fmt.Errorf("la atenuación nocturna debe estar entre 0 y %.0f %%", MaxNightDim*100)
+	}
+	if err := schedule.WakeUp.Validate(); err != nil {
+		return fmt.Errorf("despertador: %w", err)
+	}
+	if err := schedule.SleepMode.Validate(); err != nil {
+		return fmt.Errorf("fase de sueño: %w", err)
+	}
+	return nil
 }
 
 // NewAppConfig crea una nueva configuración con valores por defecto
@@ -32,6 +423,9 @@ func NewAppConfig() *AppConfig {
 		LastTemperature: 4500,
 		AutoStart:       false,
 		MinimizeToTray:  true,
+		CloseBehavior:   CloseBehaviorTray,
+		ResetOnQuit:     false,
+		SuspendForGames: true,
 		StartMinimized:  false,
 		ScheduleEnabled: false,
 		Schedule: ScheduleConfig{
@@ -41,14 +435,86 @@ func NewAppConfig() *AppConfig {
 			DayTemp:            6500,
 			TransitionTime:     30,
 			AutoDetectLocation: false,
+			Mode:               ScheduleModeFixed,
+			Interpolation:      InterpolationMired,
 		},
+		Presets:  DefaultPresets(),
+		Watchdog: WatchdogConfig{Interval: DefaultWatchdogInterval},
 	}
 }
 
-// GetConfigPath devuelve la ruta del archivo de configuración
+// GetConfigPath devuelve la ruta del archivo de configuración (LUZ_NOCTURNA_CONFIG si está
+// definida, salvo en modo demostración, que nunca toca la configuración del usuario)
 func GetConfigPath() string {
-	homeDir, _ := os.UserHomeDir()
-	return filepath.Join(homeDir, ".config", "luz-nocturna", "config.json")
+	if path := configPathOverride(); path != "" && !paths.IsDemo() {
+		return path
+	}
+	return filepath.Join(paths.ConfigDir(), "config.json")
+}
+
+/**
+ * migrateLegacyConfig - Mueve la configuración de ~/.config a $XDG_CONFIG_HOME
+ *
+ * Las versiones anteriores ignoraban XDG_CONFIG_HOME. Si la ruta antigua
+ * tiene configuración y la nueva todavía no, se mueve el archivo.
+ *
+ * @param {string} configPath - Ruta nueva del archivo de configuración
+ * @private
+ */
+func migrateLegacyConfig(configPath string) {
+	legacyPath := filepath.Join(paths.LegacyConfigDir(), "config.json")
+	if legacyPath == configPath || configPathOverride() != "" || paths.IsPortable() || paths.IsDemo() {
+		return
+	}
+	if _, err := os.Stat(configPath); err == nil {
+		return
+	}
+	if _, err := os.Stat(legacyPath); err != nil {
+		return
+	}
+
+	if err := os.Rename(legacyPath, configPath); err != nil {
+		// Puede estar en otro sistema de archivos: copiar en su lugar
+		data, readErr := os.ReadFile(legacyPath)
+		if readErr == nil && os.WriteFile(configPath, data, 0644) == nil {
+			logging.Printf("📦 Configuración copiada de %s a %s\n", legacyPath, configPath)
+			return
+		}
+		logging.Printf("⚠️  No se pudo migrar la configuración de %s: %v\n", legacyPath, err)
+		return
+	}
+	logging.Printf("📦 Configuración migrada de %s a %s\n", legacyPath, configPath)
+}
+
+/**
+ * ConfigCorruptError - El archivo de configuración no es JSON válido
+ *
+ * Load deja la configuración con valores por defecto y devuelve este
+ * error para que la interfaz ofrezca restaurar la copia de seguridad.
+ * El archivo dañado se conserva como config.json.corrupt.
+ *
+ * @struct {ConfigCorruptError}
+ * @property {string} Path - Ruta del archivo dañado
+ * @property {error} Err - Error de deserialización
+ * @property {bool} HasBackup - Si existe una copia config.json.bak válida
+ */
+type ConfigCorruptError struct {
+	Path      string
+	Err       error
+	HasBackup bool
+}
+
+func (e *ConfigCorruptError) Error() string {
+	return fmt.Sprintf("configuración dañada en %s: %v", e.Path, e.Err)
+}
+
+func (e *ConfigCorruptError) Unwrap() error {
+	return e.Err
+}
+
+// GetConfigBackupPath devuelve la ruta de la copia de seguridad de la configuración
+func GetConfigBackupPath() string {
+	return GetConfigPath() + ".bak"
 }
 
 // Load carga la configuración desde el archivo
@@ -60,9 +526,11 @@ func (config *AppConfig) Load() error {
 	if err := os.MkdirAll(configDir, 0755); err != nil {
 		return err
 	}
+	migrateLegacyConfig(configPath)
 
 	// Si el archivo no existe, usar valores por defecto
 	if _, err := os.Stat(configPath); os.IsNotExist(err) {
+		config.firstRun = true
 		return config.Save() // Crear archivo con valores por defecto
 	}
 
@@ -72,11 +540,51 @@ func (config *AppConfig) Load() error {
 		return err
 	}
 
-	// Deserializar JSON
-	return json.Unmarshal(data, config)
+	// Deserializar JSON; si falla, no seguir con una configuración a medias
+	if err := json.Unmarshal(data, config); err != nil {
+		*config = *NewAppConfig()
+		os.WriteFile(configPath+".corrupt", data, 0644)
+
+		backup, backupErr := os.ReadFile(GetConfigBackupPath())
+		return &ConfigCorruptError{
+			Path:      configPath,
+			Err:       err,
+			HasBackup: backupErr == nil && json.Valid(backup),
+		}
+	}
+	return nil
 }
 
-// Save guarda la configuración al archivo
+/**
+ * RestoreBackup - Carga la copia config.json.bak y la guarda como configuración actual
+ *
+ * @returns {error} Error si no hay copia o también está dañada
+ */
+func (config *AppConfig) RestoreBackup() error {
+	data, err := os.ReadFile(GetConfigBackupPath())
+	if err != nil {
+		return fmt.Errorf("no hay copia de seguridad: %w", err)
+	}
+
+	restored := NewAppConfig()
+	if err := json.Unmarshal(data, restored); err != nil {
+		return fmt.Errorf("la copia de seguridad también está dañada: %w", err)
+	}
+
+	*config = *restored
+	return config.Save()
+}
+
+/**
+ * Save - Guarda la configuración de forma atómica
+ *
+ * Escribe en un archivo temporal del mismo directorio y lo renombra, de
+ * modo que un corte a mitad de escritura nunca deja un config.json
+ * truncado. La versión anterior (si era válida) se conserva como
+ * config.json.bak.
+ *
+ * @returns {error} Error de serialización o de escritura
+ */
 func (config *AppConfig) Save() error {
 	configPath := GetConfigPath()
 
@@ -92,6 +600,46 @@ func (config *AppConfig) Save() error {
 		return err
 	}
 
-	// Escribir archivo
-	return os.WriteFile(configPath, data, 0644)
+	// Copia de seguridad de la versión anterior (nunca de un archivo dañado)
+	if previous, err := os.ReadFile(configPath); err == nil && json.Valid(previous) {
+		if err := writeFileAtomic(GetConfigBackupPath(), previous, 0644); err != nil {
+			logging.Printf("⚠️  No se pudo guardar la copia de seguridad: %v\n", err)
+		}
+	}
+
+	return writeFileAtomic(configPath, data, 0644)
+}
+
+/**
+ * writeFileAtomic - Escribe un archivo mediante archivo temporal + rename
+ *
+ * @param {string} path - Ruta destino
+ * @param {[]byte} data - Contenido
+ * @param {os.FileMode} perm - Permisos del archivo final
+ * @returns {error} Error de escritura o de renombrado
+ * @private
+ */
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath) // No-op si el rename tuvo éxito
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmpPath, perm); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
 }
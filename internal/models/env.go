@@ -0,0 +1,84 @@
+package models
+
+import (
+	"luznocturna/luz-nocturna/internal/logging"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Variables de entorno que se aplican sobre config.json al arrancar
+const (
+	EnvConfig      = "LUZ_NOCTURNA_CONFIG"  // Ruta alternativa de config.json
+	EnvTemperature = "LUZ_NOCTURNA_TEMP"    // Temperatura inicial en Kelvin
+	EnvBackend     = "LUZ_NOCTURNA_BACKEND" // Backend de gamma forzado
+)
+
+// Valores admitidos en LUZ_NOCTURNA_BACKEND
+const (
+	BackendAuto    = "auto"    // Detectar el protocolo y el método (por defecto)
+	BackendX11     = "x11"     // Forzar los métodos de X11 (xrandr/RandR)
+	BackendWayland = "wayland" // Forzar los métodos de Wayland
+	BackendDryRun  = "dry-run" // Registrar los comandos sin tocar el display
+	BackendFake    = "fake"    // Backend simulado, para pruebas automatizadas
+)
+
+// configPathOverride devuelve la ruta de LUZ_NOCTURNA_CONFIG como ruta absoluta ("" si no está definida)
+func configPathOverride() string {
+	path := strings.TrimSpace(os.Getenv(EnvConfig))
+	if path == "" {
+		return ""
+	}
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
+
+/**
+ * TemperatureOverride - Temperatura inicial definida en LUZ_NOCTURNA_TEMP
+ *
+ * Reemplaza a last_temperature al arrancar sin modificar config.json.
+ * Un valor no numérico o fuera de rango se ignora con un aviso.
+ *
+ * @returns {float64, bool} Temperatura en Kelvin y si la variable es válida
+ * @example
+ *   LUZ_NOCTURNA_TEMP=3400 luz-nocturna --tray
+ */
+func TemperatureOverride() (float64, bool) {
+	raw := strings.TrimSpace(os.Getenv(EnvTemperature))
+	if raw == "" {
+		return 0, false
+	}
+
+	limits := NewNightLightConfig()
+	temp, err := strconv.ParseFloat(strings.TrimSuffix(strings.ToUpper(raw), "K"), 64)
+	if err != nil || temp < limits.MinTemp || temp > limits.MaxTemp {
+		logging.Printf("⚠️  %s=%q ignorada: debe ser una temperatura entre %.0fK y %.0fK\n",
+			EnvTemperature, raw, limits.MinTemp, limits.MaxTemp)
+		return 0, false
+	}
+	return temp, true
+}
+
+/**
+ * BackendOverride - Backend de gamma definido en LUZ_NOCTURNA_BACKEND
+ *
+ * @returns {string} Uno de BackendAuto, BackendX11, BackendWayland, BackendDryRun o BackendFake
+ * @example
+ *   LUZ_NOCTURNA_BACKEND=fake luz-nocturna --tray
+ */
+func BackendOverride() string {
+	raw := strings.ToLower(strings.TrimSpace(os.Getenv(EnvBackend)))
+	switch raw {
+	case "":
+		return BackendAuto
+	case BackendAuto, BackendX11, BackendWayland, BackendDryRun, BackendFake:
+		return raw
+	}
+
+	logging.Printf("⚠️  %s=%q ignorada (opciones: %s, %s, %s, %s, %s)\n", EnvBackend, raw,
+		BackendAuto, BackendX11, BackendWayland, BackendDryRun, BackendFake)
+	return BackendAuto
+}
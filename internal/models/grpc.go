@@ -0,0 +1,60 @@
+package models
+
+import (
+	"fmt"
+	"net"
+	"path/filepath"
+	"strings"
+
+	"luznocturna/luz-nocturna/internal/paths"
+)
+
+// DefaultGRPCSocket es el socket Unix del servidor gRPC dentro del directorio de ejecución
+const DefaultGRPCSocket = "grpc.sock"
+
+/**
+ * GRPCConfig - Servidor gRPC opcional para automatizaciones
+ *
+ * La API no tiene autenticación: por defecto escucha en un socket Unix
+ * que solo puede abrir el usuario, y las direcciones TCP deben ser de
+ * loopback para no exponer el control de la pantalla a la red.
+ *
+ * @struct {GRPCConfig}
+ * @example
+ *   GRPCConfig{Enabled: true}                             // $XDG_RUNTIME_DIR/luz-nocturna/grpc.sock
+ *   GRPCConfig{Enabled: true, Address: "127.0.0.1:7465"}  // TCP local
+ *   GRPCConfig{Enabled: true, Address: "unix:/tmp/ln.sock"}
+ */
+type GRPCConfig struct {
+	Enabled bool   `json:"enabled"`
+	Address string `json:"address"` // "unix:/ruta" o "host:puerto" de loopback (vacío = socket por defecto)
+}
+
+/**
+ * Endpoint - Resuelve dónde debe escuchar el servidor
+ *
+ * @returns {string, string, error} Red ("unix" o "tcp"), dirección y error si no es válida
+ */
+func (grpc GRPCConfig) Endpoint() (network, address string, err error) {
+	switch {
+	case grpc.Address == "":
+		return "unix", filepath.Join(paths.RuntimeDir(), DefaultGRPCSocket), nil
+	case strings.HasPrefix(grpc.Address, "unix:"):
+		path := strings.TrimPrefix(grpc.Address, "unix:")
+		if !filepath.IsAbs(path) {
+			return "", "", fmt.Errorf("la ruta del socket debe ser absoluta: %s", path)
+		}
+		return "unix", path, nil
+	}
+
+	host, _, err := net.SplitHostPort(grpc.Address)
+	if err != nil {
+		return "", "", fmt.Errorf("dirección no válida %q: %w", grpc.Address, err)
+	}
+	if host != "localhost" {
+		if ip := net.ParseIP(host); ip == nil || !ip.IsLoopback() {
+			return "", "", fmt.Errorf("solo se admiten direcciones de loopback (sin autenticación): %s", host)
+		}
+	}
+	return "tcp", grpc.Address, nil
+}
@@ -0,0 +1,41 @@
+package models
+
+// Nombres de los hooks (claves JSON y valor de LUZ_NOCTURNA_HOOK)
+const (
+	HookApply      = "on_apply"       // El filtro se aplicó (a mano, preset, atajo, D-Bus...)
+	HookReset      = "on_reset"       // Se restauró la gamma normal
+	HookNightStart = "on_night_start" // La programación entró en el período nocturno
+	HookDayStart   = "on_day_start"   // La programación volvió al período diurno
+)
+
+/**
+ * HooksConfig - Comandos del usuario que se ejecutan al cambiar el estado
+ *
+ * Cada comando se ejecuta con "sh -c" y recibe el nuevo estado en
+ * variables de entorno (LUZ_NOCTURNA_TEMPERATURE, LUZ_NOCTURNA_ACTIVE...),
+ * por ejemplo para cambiar el tema del terminal o las luces de la
+ * habitación. Un comando vacío desactiva el hook.
+ *
+ * @struct {HooksConfig}
+ */
+type HooksConfig struct {
+	OnApply      string `json:"on_apply"`
+	OnReset      string `json:"on_reset"`
+	OnNightStart string `json:"on_night_start"`
+	OnDayStart   string `json:"on_day_start"`
+}
+
+// Command devuelve el comando configurado para un hook ("" si no hay ninguno)
+func (hooks HooksConfig) Command(name string) string {
+	switch name {
+	case HookApply:
+		return hooks.OnApply
+	case HookReset:
+		return hooks.OnReset
+	case HookNightStart:
+		return hooks.OnNightStart
+	case HookDayStart:
+		return hooks.OnDayStart
+	}
+	return ""
+}
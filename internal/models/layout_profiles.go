@@ -0,0 +1,73 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+/**
+ * LayoutProfile - Preset asociado a una disposición de monitores
+ *
+ * La disposición es el conjunto de monitores conectados, identificados
+ * por el nombre de su EDID. Al conectar o desconectar un monitor, si la
+ * nueva disposición coincide con Monitors se selecciona Preset (y se
+ * aplica si el filtro está activo).
+ *
+ * @struct {LayoutProfile}
+ * @example
+ *   LayoutProfile{Name: "Oficina", Monitors: []string{"BOE 0A2B", "DELL U2720Q"}, Preset: "Lectura"}
+ */
+type LayoutProfile struct {
+	Name     string   `json:"name"`     // Nombre visible ("Oficina", "Portátil solo")
+	Monitors []string `json:"monitors"` // Monitores de la disposición según su EDID (el orden no importa)
+	Preset   string   `json:"preset"`   // Preset que se selecciona con esta disposición
+}
+
+// Matches indica si el perfil corresponde a la disposición (mismos monitores, sin distinguir mayúsculas)
+func (profile LayoutProfile) Matches(layout []string) bool {
+	return LayoutKey(profile.Monitors) == LayoutKey(layout)
+}
+
+// Validate verifica que el perfil tenga nombre, monitores y un preset existente
+func (profile LayoutProfile) Validate(presets []Preset) error {
+	if strings.TrimSpace(profile.Name) == "" {
+		return fmt.Errorf("falta el nombre (name)")
+	}
+	if len(profile.Monitors) == 0 {
+		return fmt.Errorf("%s: falta la lista de monitores (monitors)", profile.Name)
+	}
+	if _, found := findPresetByName(presets, profile.Preset); !found {
+		return fmt.Errorf("%s: no existe el preset %q", profile.Name, profile.Preset)
+	}
+	return nil
+}
+
+/**
+ * LayoutKey - Forma canónica de una disposición para compararla
+ *
+ * Ordena los monitores y los pasa a minúsculas; dos monitores iguales
+ * cuentan dos veces, así que "portátil + un Dell" y "portátil + dos
+ * Dell" son disposiciones distintas.
+ *
+ * @param {[]string} monitors - Monitores en cualquier orden
+ * @returns {string} Clave de la disposición ("" si no hay monitores)
+ */
+func LayoutKey(monitors []string) string {
+	normalized := make([]string, 0, len(monitors))
+	for _, monitor := range monitors {
+		normalized = append(normalized, strings.ToLower(strings.TrimSpace(monitor)))
+	}
+	sort.Strings(normalized)
+	return strings.Join(normalized, "\n")
+}
+
+// FindLayoutProfile devuelve el primer perfil que coincide con la disposición
+func FindLayoutProfile(profiles []LayoutProfile, layout []string) (LayoutProfile, bool) {
+	for _, profile := range profiles {
+		if profile.Matches(layout) {
+			return profile, true
+		}
+	}
+	return LayoutProfile{}, false
+}
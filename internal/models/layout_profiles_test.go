@@ -0,0 +1,81 @@
+package models
+
+import "testing"
+
+func TestLayoutKey(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []string
+		same bool
+	}{
+		{"el orden no importa", []string{"BOE 0A2B", "DELL U2720Q"}, []string{"DELL U2720Q", "BOE 0A2B"}, true},
+		{"sin distinguir mayúsculas ni espacios", []string{" dell u2720q "}, []string{"DELL U2720Q"}, true},
+		{"un monitor repetido cuenta dos veces", []string{"BOE 0A2B", "DELL U2720Q"}, []string{"BOE 0A2B", "DELL U2720Q", "DELL U2720Q"}, false},
+		{"monitores distintos", []string{"BOE 0A2B"}, []string{"DELL U2720Q"}, false},
+		{"sin monitores", nil, []string{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := LayoutKey(tt.a) == LayoutKey(tt.b); got != tt.same {
+				t.Errorf("LayoutKey(%q) == LayoutKey(%q) = %v, se esperaba %v", tt.a, tt.b, got, tt.same)
+			}
+		})
+	}
+	if key := LayoutKey(nil); key != "" {
+		t.Errorf("LayoutKey(nil) = %q, se esperaba una clave vacía", key)
+	}
+}
+
+func TestFindLayoutProfile(t *testing.T) {
+	profiles := []LayoutProfile{
+		{Name: "Oficina", Monitors: []string{"BOE 0A2B", "DELL U2720Q"}, Preset: "Lectura"},
+		{Name: "Portátil solo", Monitors: []string{"BOE 0A2B"}, Preset: "Noche"},
+		{Name: "Oficina (copia)", Monitors: []string{"dell u2720q", "boe 0a2b"}, Preset: "Cine"},
+	}
+
+	tests := []struct {
+		name   string
+		layout []string
+		want   string // "" si no hay perfil
+	}{
+		{"coincide el primero en orden de prioridad", []string{"DELL U2720Q", "BOE 0A2B"}, "Oficina"},
+		{"solo el panel integrado", []string{"BOE 0A2B"}, "Portátil solo"},
+		{"disposición sin perfil", []string{"BOE 0A2B", "LG 27UL500"}, ""},
+		{"sin monitores", nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profile, found := FindLayoutProfile(profiles, tt.layout)
+			if found != (tt.want != "") || profile.Name != tt.want {
+				t.Errorf("FindLayoutProfile(%q) = %q (encontrado %v), se esperaba %q", tt.layout, profile.Name, found, tt.want)
+			}
+		})
+	}
+}
+
+func TestLayoutProfileValidate(t *testing.T) {
+	presets := []Preset{{Name: "Lectura", Temperature: 4500, Brightness: 1.0}}
+
+	tests := []struct {
+		name    string
+		profile LayoutProfile
+		valid   bool
+	}{
+		{"válido", LayoutProfile{Name: "Oficina", Monitors: []string{"BOE 0A2B"}, Preset: "Lectura"}, true},
+		{"preset sin distinguir mayúsculas", LayoutProfile{Name: "Oficina", Monitors: []string{"BOE 0A2B"}, Preset: " lectura "}, true},
+		{"sin nombre", LayoutProfile{Name: "  ", Monitors: []string{"BOE 0A2B"}, Preset: "Lectura"}, false},
+		{"sin monitores", LayoutProfile{Name: "Oficina", Preset: "Lectura"}, false},
+		{"preset inexistente", LayoutProfile{Name: "Oficina", Monitors: []string{"BOE 0A2B"}, Preset: "Cine"}, false},
+		{"sin preset", LayoutProfile{Name: "Oficina", Monitors: []string{"BOE 0A2B"}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.profile.Validate(presets); (err == nil) != tt.valid {
+				t.Errorf("Validate() = %v, se esperaba válido=%v", err, tt.valid)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package models
 
 import (
 	"fmt"
+	"luznocturna/luz-nocturna/internal/logging"
 )
 
 // NightLightConfig representa la configuración de luz nocturna
@@ -9,6 +10,8 @@ type NightLightConfig struct {
 	Temperature float64 // Temperatura en Kelvin
 	MinTemp     float64 // Temperatura mínima
 	MaxTemp     float64 // Temperatura máxima
+	Brightness  float64 // Brillo relativo (1.0 = sin atenuación)
+	Contrast    float64 // Contraste extra de la curva gamma (0 = curva normal)
 	IsActive    bool    // Si está activa la luz nocturna
 }
 
@@ -18,6 +21,7 @@ func NewNightLightConfig() *NightLightConfig {
 		Temperature: 4500, // Valor por defecto
 		MinTemp:     3000, // Temperatura más cálida
 		MaxTemp:     6500, // Temperatura más fría (luz diurna)
+		Brightness:  1.0,
 		IsActive:    false,
 	}
 }
@@ -33,6 +37,38 @@ func (config *NightLightConfig) SetTemperature(temp float64) {
 	}
 }
 
+// Límites del brillo relativo. Por encima de 1.0 es el modo "boost" para
+// habitaciones muy iluminadas; más allá de MaxBrightness se pierden los blancos
+const (
+	MinBrightness = 0.1
+	MaxBrightness = 1.3
+)
+
+// SetBrightness establece el brillo relativo (0 o valores fuera de rango = 1.0)
+func (config *NightLightConfig) SetBrightness(brightness float64) {
+	if brightness < MinBrightness || brightness > MaxBrightness {
+		brightness = 1.0
+	}
+	config.Brightness = brightness
+}
+
+// MaxContrast es el contraste extra máximo: con más, los tonos medios se separan
+// tanto que las sombras y las luces pierden detalle
+const MaxContrast = 0.5
+
+// SetContrast establece el contraste extra (valores fuera de rango = 0)
+func (config *NightLightConfig) SetContrast(contrast float64) {
+	if contrast < 0 || contrast > MaxContrast {
+		contrast = 0
+	}
+	config.Contrast = contrast
+}
+
+// IsBoosted indica si el brillo supera la gamma normal (modo "boost")
+func (config *NightLightConfig) IsBoosted() bool {
+	return config.Brightness > 1.0
+}
+
 // GetTemperatureString devuelve la temperatura como string con formato
 func (config *NightLightConfig) GetTemperatureString() string {
 	return fmt.Sprintf("%.0fK", config.Temperature)
@@ -41,6 +77,8 @@ func (config *NightLightConfig) GetTemperatureString() string {
 // Reset restablece la configuración a valores por defecto
 func (config *NightLightConfig) Reset() {
 	config.Temperature = 6500 // Luz diurna normal
+	config.Brightness = 1.0
+	config.Contrast = 0
 	config.IsActive = false
 }
 
@@ -49,7 +87,7 @@ func (config *NightLightConfig) Apply() error {
 	config.IsActive = true
 	// Aquí iría la lógica para aplicar realmente el filtro gamma
 	// Por ahora solo marcamos como activa
-	fmt.Printf("Aplicando luz nocturna con temperatura: %s\n", config.GetTemperatureString())
+	logging.Printf("Aplicando luz nocturna con temperatura: %s\n", config.GetTemperatureString())
 	return nil
 }
 
@@ -57,6 +95,6 @@ func (config *NightLightConfig) Apply() error {
 func (config *NightLightConfig) Disable() error {
 	config.IsActive = false
 	// Aquí iría la lógica para desactivar el filtro gamma
-	fmt.Println("Desactivando luz nocturna")
+	logging.Println("Desactivando luz nocturna")
 	return nil
 }
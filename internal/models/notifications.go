@@ -0,0 +1,58 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Qué hacer con una notificación mientras el escritorio está en "No molestar"
+const (
+	NotifyAlways = "always" // Mostrarla igualmente
+	NotifyQueue  = "queue"  // Guardarla y mostrarla al terminar "No molestar"
+	NotifySkip   = "skip"   // Descartarla
+)
+
+// notifyPolicies son los valores admitidos para cada tipo de notificación
+var notifyPolicies = []string{NotifyAlways, NotifyQueue, NotifySkip}
+
+/**
+ * NotificationsConfig - Comportamiento de las notificaciones con "No molestar"
+ *
+ * Cada tipo de notificación tiene su política. Por defecto los errores
+ * se muestran siempre y los avisos de la programación esperan a que
+ * termine "No molestar".
+ *
+ * @struct {NotificationsConfig}
+ * @example
+ *   NotificationsConfig{Schedule: NotifySkip} // Sin avisos de la programación durante "No molestar"
+ */
+type NotificationsConfig struct {
+	Errors   string `json:"errors"`   // Fallos de un cambio automático: "always" (por defecto), "queue" o "skip"
+	Schedule string `json:"schedule"` // Inicio y fin de la noche: "queue" (por defecto), "always" o "skip"
+}
+
+// GetErrors devuelve la política de los errores; vacía o desconocida usa NotifyAlways
+func (notifications NotificationsConfig) GetErrors() string {
+	if containsString(notifyPolicies, notifications.Errors) {
+		return notifications.Errors
+	}
+	return NotifyAlways
+}
+
+// GetSchedule devuelve la política de los avisos de la programación; vacía o desconocida usa NotifyQueue
+func (notifications NotificationsConfig) GetSchedule() string {
+	if containsString(notifyPolicies, notifications.Schedule) {
+		return notifications.Schedule
+	}
+	return NotifyQueue
+}
+
+// Validate verifica que las políticas sean conocidas
+func (notifications NotificationsConfig) Validate() error {
+	for key, value := range map[string]string{"errors": notifications.Errors, "schedule": notifications.Schedule} {
+		if value != "" && !containsString(notifyPolicies, value) {
+			return fmt.Errorf("%s: %q no es válido (opciones: %s)", key, value, strings.Join(notifyPolicies, ", "))
+		}
+	}
+	return nil
+}
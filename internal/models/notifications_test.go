@@ -0,0 +1,40 @@
+package models
+
+import "testing"
+
+func TestNotificationsConfigPolicies(t *testing.T) {
+	tests := []struct {
+		name                  string
+		config                NotificationsConfig
+		wantErrors, wantSched string
+		wantValid             bool
+	}{
+		{"vacía usa los valores por defecto", NotificationsConfig{}, NotifyAlways, NotifyQueue, true},
+		{"políticas explícitas", NotificationsConfig{Errors: NotifyQueue, Schedule: NotifySkip}, NotifyQueue, NotifySkip, true},
+		{"errores siempre, programación siempre", NotificationsConfig{Errors: NotifyAlways, Schedule: NotifyAlways}, NotifyAlways, NotifyAlways, true},
+		{"error desconocido", NotificationsConfig{Errors: "never"}, NotifyAlways, NotifyQueue, false},
+		{"programación desconocida", NotificationsConfig{Schedule: "Queue"}, NotifyAlways, NotifyQueue, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.config.GetErrors(); got != tt.wantErrors {
+				t.Errorf("GetErrors() = %q, se esperaba %q", got, tt.wantErrors)
+			}
+			if got := tt.config.GetSchedule(); got != tt.wantSched {
+				t.Errorf("GetSchedule() = %q, se esperaba %q", got, tt.wantSched)
+			}
+			if err := tt.config.Validate(); (err == nil) != tt.wantValid {
+				t.Errorf("Validate() = %v, se esperaba válida=%v", err, tt.wantValid)
+			}
+		})
+	}
+}
+
+func TestAppConfigValidatesNotifications(t *testing.T) {
+	config := NewAppConfig()
+	config.Notifications.Errors = "sometimes"
+	if err := config.Validate(); err == nil {
+		t.Fatal("Validate() aceptó una política de notificaciones desconocida")
+	}
+}
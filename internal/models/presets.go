@@ -1,5 +1,12 @@
 package models
 
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
 // TemperaturePresets define presets comunes de temperatura
 type TemperaturePresets struct{}
 
@@ -13,8 +20,152 @@ const (
 	NeutralWhiteTemp = 4500 // Blanco neutro
 	CoolWhiteTemp    = 5500 // Blanco frío
 	DaylightTemp     = 6500 // Luz diurna
+	ReadingTemp      = 4000 // Lectura (con un poco más de contraste)
+)
+
+// ReadingContrast es el contraste del preset de lectura: suficiente para
+// marcar el texto sin quemar blancos ni negros
+const ReadingContrast = 0.15
+
+// Preset representa un preset de temperatura definido por el usuario
+type Preset struct {
+	Name        string  `json:"name"`                 // Nombre visible (único)
+	Icon        string  `json:"icon"`                 // Emoji o símbolo corto
+	Temperature float64 `json:"temperature"`          // Temperatura en Kelvin
+	Brightness  float64 `json:"brightness,omitempty"` // Brillo 0.1-1.3 (0 = sin cambio, >1 = boost)
+	Contrast    float64 `json:"contrast,omitempty"`   // Contraste extra 0-0.5 (0 = curva normal)
+	Key         string  `json:"key,omitempty"`        // Tecla que lo selecciona en la ventana ("Q", "7", "F5")
+}
+
+// Pasos del slider de temperatura y distancia a la que se ajusta a un preset
+const (
+	CoarseTemperatureStep = 100.0 // Paso normal del slider
+	FineTemperatureStep   = 10.0  // Paso fino (Shift o ajuste "pasos finos")
+	PresetSnapDistance    = 150.0 // Distancia máxima para ajustarse a un preset
 )
 
+// SnapToPreset devuelve la temperatura del preset más cercano si está a menos
+// de PresetSnapDistance; si no, devuelve temp sin cambios
+func SnapToPreset(temp float64, presets []Preset) float64 {
+	snapped, best := temp, PresetSnapDistance
+	for _, preset := range presets {
+		if distance := math.Abs(preset.Temperature - temp); distance <= best {
+			snapped, best = preset.Temperature, distance
+		}
+	}
+	return snapped
+}
+
+// DefaultPresetKeys es cuántos presets responden a las teclas numéricas por
+// posición (1 = primer preset...) si ningún preset usa esa tecla como propia
+const DefaultPresetKeys = 4
+
+/**
+ * NormalizePresetKey - Valida y normaliza la tecla propia de un preset
+ *
+ * Se admiten letras, dígitos y F2-F12, con el nombre que usa Fyne para
+ * la tecla (fyne.KeyName). F1 y Espacio están reservadas para la ayuda y
+ * para activar o desactivar el filtro.
+ *
+ * @param {string} key - Tecla escrita por el usuario ("q", " f5 ", "")
+ * @returns {string, error} Tecla normalizada ("Q", "F5", "" sin tecla) o error
+ */
+func NormalizePresetKey(key string) (string, error) {
+	key = strings.ToUpper(strings.TrimSpace(key))
+	if key == "" {
+		return "", nil
+	}
+	if len(key) == 1 && (key[0] >= 'A' && key[0] <= 'Z' || key[0] >= '0' && key[0] <= '9') {
+		return key, nil
+	}
+	if n, err := strconv.Atoi(strings.TrimPrefix(key, "F")); err == nil && key[0] == 'F' && n >= 2 && n <= 12 {
+		return key, nil
+	}
+	return "", fmt.Errorf("tecla no válida %q: usa una letra, un dígito o F2-F12", key)
+}
+
+/**
+ * PresetKeyMap - Calcula qué preset selecciona cada tecla
+ *
+ * Las teclas propias de los presets tienen prioridad; las teclas 1 a
+ * DefaultPresetKeys que queden libres seleccionan el preset de esa
+ * posición, igual que los botones de la ventana.
+ *
+ * @param {[]Preset} presets - Presets en el orden de los botones
+ * @returns {map[string]int} Posición del preset por nombre de tecla
+ * @example
+ *   keys := PresetKeyMap(config.Presets) // {"1": 0, "2": 1, "3": 2, "4": 3}
+ */
+func PresetKeyMap(presets []Preset) map[string]int {
+	keys := make(map[string]int)
+	for i, preset := range presets {
+		key, err := NormalizePresetKey(preset.Key)
+		if err != nil || key == "" {
+			continue
+		}
+		if _, taken := keys[key]; !taken {
+			keys[key] = i
+		}
+	}
+	for i := 0; i < len(presets) && i < DefaultPresetKeys; i++ {
+		key := strconv.Itoa(i + 1)
+		if _, taken := keys[key]; !taken {
+			keys[key] = i
+		}
+	}
+	return keys
+}
+
+// DefaultPresets devuelve los presets con los que arranca una configuración nueva
+func DefaultPresets() []Preset {
+	return []Preset{
+		{Name: "Cálida", Icon: "🕯️", Temperature: CandleLightTemp},
+		{Name: "Neutra", Icon: "☀️", Temperature: NeutralWhiteTemp},
+		{Name: "Fría", Icon: "🌤️", Temperature: CoolWhiteTemp},
+		{Name: "Diurna", Icon: "💡", Temperature: DaylightTemp},
+		{Name: "Sol intenso", Icon: "🔆", Temperature: DaylightTemp, Brightness: 1.2},
+		{Name: "Lectura", Icon: "📖", Temperature: ReadingTemp, Contrast: ReadingContrast},
+	}
+}
+
+// Label devuelve el texto del preset para botones y menús
+func (p Preset) Label() string {
+	if p.Icon == "" {
+		return fmt.Sprintf("%s (%.0fK)", p.Name, p.Temperature)
+	}
+	return fmt.Sprintf("%s %s (%.0fK)", p.Icon, p.Name, p.Temperature)
+}
+
+// HasBrightness indica si el preset ajusta también el brillo
+func (p Preset) HasBrightness() bool {
+	return p.Brightness > 0
+}
+
+// HasContrast indica si el preset ajusta también el contraste
+func (p Preset) HasContrast() bool {
+	return p.Contrast > 0
+}
+
+// Validate verifica que el preset tenga valores utilizables
+func (p Preset) Validate(minTemp, maxTemp float64) error {
+	if strings.TrimSpace(p.Name) == "" {
+		return fmt.Errorf("el preset necesita un nombre")
+	}
+	if p.Temperature < minTemp || p.Temperature > maxTemp {
+		return fmt.Errorf("temperatura fuera de rango (%.0fK - %.0fK)", minTemp, maxTemp)
+	}
+	if p.Brightness != 0 && (p.Brightness < MinBrightness || p.Brightness > MaxBrightness) {
+		return fmt.Errorf("el brillo debe estar entre %.1f y %.1f", MinBrightness, MaxBrightness)
+	}
+	if p.Contrast < 0 || p.Contrast > MaxContrast {
+		return fmt.Errorf("el contraste debe estar entre 0 y %.1f", MaxContrast)
+	}
+	if _, err := NormalizePresetKey(p.Key); err != nil {
+		return err
+	}
+	return nil
+}
+
 // GetPresetName devuelve el nombre del preset más cercano a la temperatura dada
 func (p TemperaturePresets) GetPresetName(temp float64) string {
 	switch {
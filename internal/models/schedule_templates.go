@@ -0,0 +1,92 @@
+package models
+
+import "fmt"
+
+/**
+ * ScheduleTemplate - Programación predefinida como punto de partida
+ *
+ * Rellena los horarios, las temperaturas y el modo de ScheduleConfig; la
+ * ubicación y la unidad de interpolación del usuario se conservan.
+ *
+ * @struct {ScheduleTemplate}
+ * @property {string} ID - Identificador estable (CLI y configuración)
+ * @property {string} Name - Nombre visible
+ * @property {string} Icon - Emoji del selector
+ * @property {string} Description - Para quién está pensada
+ */
+type ScheduleTemplate struct {
+	ID             string
+	Name           string
+	Icon           string
+	Description    string
+	StartTime      string
+	EndTime        string
+	NightTemp      float64
+	DayTemp        float64
+	TransitionTime int
+	Mode           string
+}
+
+// ScheduleTemplates son las plantillas de programación disponibles, en el orden del selector
+var ScheduleTemplates = []ScheduleTemplate{
+	{
+		ID: "early-bird", Name: "Madrugador", Icon: "🐦",
+		Description: "Se acuesta y se levanta temprano: filtro de 20:30 a 05:30",
+		StartTime:   "20:30", EndTime: "05:30", NightTemp: 3400, DayTemp: 6500, TransitionTime: 30,
+		Mode: ScheduleModeFixed,
+	},
+	{
+		ID: "night-owl", Name: "Noctámbulo", Icon: "🦉",
+		Description: "Trabaja hasta tarde: filtro de 23:00 a 08:30, más suave y gradual",
+		StartTime:   "23:00", EndTime: "08:30", NightTemp: 3600, DayTemp: 6500, TransitionTime: 45,
+		Mode: ScheduleModeFixed,
+	},
+	{
+		ID: "shift-worker", Name: "Turno de noche", Icon: "🏭",
+		Description: "Duerme de día: luz fría durante el turno y cálida de 06:00 a 14:00",
+		StartTime:   "06:00", EndTime: "14:00", NightTemp: 3000, DayTemp: 6500, TransitionTime: 20,
+		Mode: ScheduleModeFixed,
+	},
+	{
+		ID: "follow-the-sun", Name: "Seguir el sol", Icon: "☀️",
+		Description: "La temperatura sigue la elevación del sol en tu ubicación",
+		StartTime:   "20:00", EndTime: "07:00", NightTemp: 3200, DayTemp: 6500, TransitionTime: 30,
+		Mode: ScheduleModeSolar,
+	},
+}
+
+// FindScheduleTemplate busca una plantilla por su identificador
+func FindScheduleTemplate(id string) (ScheduleTemplate, bool) {
+	for _, template := range ScheduleTemplates {
+		if template.ID == id {
+			return template, true
+		}
+	}
+	return ScheduleTemplate{}, false
+}
+
+// Label devuelve el texto del selector ("🦉 Noctámbulo")
+func (template ScheduleTemplate) Label() string {
+	return template.Icon + " " + template.Name
+}
+
+/**
+ * ApplyTo - Rellena una programación con los valores de la plantilla
+ *
+ * @param {ScheduleConfig} schedule - Programación actual
+ * @returns {ScheduleConfig, error} Programación resultante; error si la plantilla
+ *   sigue el sol y no hay ubicación configurada
+ */
+func (template ScheduleTemplate) ApplyTo(schedule ScheduleConfig) (ScheduleConfig, error) {
+	if template.Mode == ScheduleModeSolar && !schedule.Location.IsSet() {
+		return schedule, fmt.Errorf("la plantilla %q necesita tu ubicación: escribe latitud y longitud", template.Name)
+	}
+
+	schedule.StartTime = template.StartTime
+	schedule.EndTime = template.EndTime
+	schedule.NightTemp = template.NightTemp
+	schedule.DayTemp = template.DayTemp
+	schedule.TransitionTime = template.TransitionTime
+	schedule.Mode = template.Mode
+	return schedule, schedule.Validate()
+}
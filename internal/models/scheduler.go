@@ -2,6 +2,10 @@ package models
 
 import (
 	"fmt"
+	"luznocturna/luz-nocturna/internal/logging"
+	"math"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -14,24 +18,39 @@ import (
  */
 type Scheduler struct {
 	config      *AppConfig
+	configLock  sync.Locker // Cerrojo con el que el dueño de config la modifica (nil si nadie la comparte)
+	runMu       sync.Mutex  // Protege isRunning y stopChannel: Start y Stop llegan desde varias goroutines
 	isRunning   bool
-	stopChannel chan bool
-	onApply     func(float64) error // Callback para aplicar temperatura
+	stopChannel chan struct{}                               // Se cierra en Stop; cada Start crea uno nuevo
+	onApply     func(temperature, brightness float64) error // Callback para aplicar temperatura y brillo
+	onPanic     func()                                      // Se llama si la goroutine del programador entra en pánico (SetPanicHandler)
+	now         func() time.Time                            // Reloj inyectable (time.Now por defecto; las pruebas lo sustituyen)
+	tickCheck   func() bool                                 // Indica si el backend admite pasos de TransitionTick (nil: siempre)
+	biasMu      sync.Mutex
+	dayBias     float64 // Kelvin que se restan a la temperatura diurna (nubosidad)
+	skipMu      sync.Mutex
+	skipUntil   time.Time // Fin de la noche omitida con SkipTonight (cero si no hay ninguna)
+	idleMu      sync.Mutex
+	idle        bool // Nadie usa el equipo: los tics no aplican nada (SetIdle)
+
+	lastMu          sync.Mutex
+	lastTemperature float64 // Último estado enviado a onApply, para omitir los pasos sin cambios
+	lastBrightness  float64
 }
 
 /**
  * NewScheduler - Constructor del programador de horarios
  *
  * @param {*AppConfig} config - Configuración de la aplicación
- * @param {func(float64) error} onApply - Función callback para aplicar temperatura
+ * @param {func(float64, float64) error} onApply - Función callback para aplicar temperatura y brillo
  * @returns {*Scheduler} Nueva instancia del programador
  */
-func NewScheduler(config *AppConfig, onApply func(float64) error) *Scheduler {
+func NewScheduler(config *AppConfig, onApply func(temperature, brightness float64) error) *Scheduler {
 	return &Scheduler{
-		config:      config,
-		isRunning:   false,
-		stopChannel: make(chan bool),
-		onApply:     onApply,
+		config:    config,
+		isRunning: false,
+		onApply:   onApply,
+		now:       time.Now,
 	}
 }
 
@@ -42,27 +61,53 @@ func NewScheduler(config *AppConfig, onApply func(float64) error) *Scheduler {
  * los filtros de temperatura según la configuración.
  */
 func (s *Scheduler) Start() {
-	if s.isRunning || !s.config.ScheduleEnabled {
+	if !s.currentConfig().ScheduleEnabled {
 		return
 	}
 
+	s.runMu.Lock()
+	if s.isRunning {
+		s.runMu.Unlock()
+		return
+	}
 	s.isRunning = true
-	fmt.Println("🕐 Programación automática iniciada")
+	stop := make(chan struct{})
+	s.stopChannel = stop
+	s.runMu.Unlock()
+
+	logging.Println("🕐 Programación automática iniciada")
 
 	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				if s.onPanic != nil {
+					s.onPanic()
+				}
+				panic(r)
+			}
+		}()
+
 		// Aplicar temperatura inicial inmediatamente
 		s.applyCurrentTemperature()
+		lastFull := s.now()
 
-		// Crear ticker para verificar cada minuto
-		ticker := time.NewTicker(1 * time.Minute)
-		defer ticker.Stop()
+		// Un tic por minuto y, durante las transiciones, pasos intermedios cada TransitionTick
+		timer := time.NewTimer(s.nextTick(lastFull))
+		defer timer.Stop()
 
 		for {
 			select {
-			case <-ticker.C:
-				s.applyCurrentTemperature()
-			case <-s.stopChannel:
-				fmt.Println("🕐 Programación automática detenida")
+			case <-timer.C:
+				now := s.now()
+				if now.Sub(lastFull) >= scheduleTick {
+					s.applyCurrentTemperature()
+					lastFull = now
+				} else {
+					s.applyTransitionStep()
+				}
+				timer.Reset(s.nextTick(now))
+			case <-stop:
+				logging.Println("🕐 Programación automática detenida")
 				return
 			}
 		}
@@ -71,14 +116,19 @@ func (s *Scheduler) Start() {
 
 /**
  * Stop - Detiene el programador automático de horarios
+ *
+ * No bloquea: cierra el canal de parada y la goroutine termina en cuanto
+ * acabe lo que esté haciendo, aunque se haya quedado esperando al backend.
  */
 func (s *Scheduler) Stop() {
+	s.runMu.Lock()
+	defer s.runMu.Unlock()
 	if !s.isRunning {
 		return
 	}
 
 	s.isRunning = false
-	s.stopChannel <- true
+	close(s.stopChannel)
 }
 
 /**
@@ -87,6 +137,8 @@ func (s *Scheduler) Stop() {
  * @returns {bool} true si está ejecutándose
  */
 func (s *Scheduler) IsRunning() bool {
+	s.runMu.Lock()
+	defer s.runMu.Unlock()
 	return s.isRunning
 }
 
@@ -94,85 +146,323 @@ func (s *Scheduler) IsRunning() bool {
  * applyCurrentTemperature - Aplica la temperatura correspondiente a la hora actual
  *
  * Calcula la temperatura que debe aplicarse según la hora actual
- * y los horarios configurados, incluyendo transiciones suaves.
+ * y los horarios configurados, incluyendo transiciones suaves. En modo
+ * vacaciones no aplica nada; el siguiente tic tras el período reanuda.
  *
  * @private
  */
 func (s *Scheduler) applyCurrentTemperature() {
-	now := time.Now()
+	s.applyState(false)
+}
+
+// applyTransitionStep aplica un paso intermedio de una transición, solo si el estado cambió desde el último aplicado
+func (s *Scheduler) applyTransitionStep() {
+	s.applyState(true)
+}
+
+/**
+ * applyState - Calcula el estado de la hora actual y lo envía a onApply
+ *
+ * Los pasos intermedios no se registran en el log y se omiten si la
+ * temperatura y el brillo apenas cambiaron, para no repetir el mismo
+ * estado doce veces por minuto.
+ *
+ * @param {bool} step - true en los tics de TransitionTick entre dos tics de minuto
+ * @private
+ */
+func (s *Scheduler) applyState(step bool) {
+	now := s.now()
+	if _, paused := s.currentConfig().Vacation.Active(now); paused {
+		return // Modo vacaciones: no se toca la gamma hasta que termine
+	}
+	if s.isIdle() {
+		return // Nadie mira la pantalla: se aplica al volver (SetIdle)
+	}
 	currentTime := fmt.Sprintf("%02d:%02d", now.Hour(), now.Minute())
 
-	temperature := s.calculateTemperatureForTime(currentTime)
+	temperature, brightness := s.StateAt(now)
+
+	s.lastMu.Lock()
+	unchanged := math.Abs(temperature-s.lastTemperature) < 1 && math.Abs(brightness-s.lastBrightness) < 0.005
+	s.lastMu.Unlock()
+	if step && unchanged {
+		return
+	}
 
 	if s.onApply != nil {
-		if err := s.onApply(temperature); err != nil {
-			fmt.Printf("⚠️  Error aplicando temperatura automática: %v\n", err)
-		} else {
-			fmt.Printf("🕐 Temperatura automática aplicada: %.0fK (%s)\n", temperature, currentTime)
+		if err := s.onApply(temperature, brightness); err != nil {
+			logging.Printf("⚠️  Error aplicando temperatura automática: %v\n", err)
+			return
+		}
+		if !step {
+			logging.Printf("🕐 Temperatura automática aplicada: %.0fK (%s)\n", temperature, currentTime)
 		}
 	}
+
+	s.lastMu.Lock()
+	s.lastTemperature, s.lastBrightness = temperature, brightness
+	s.lastMu.Unlock()
+}
+
+// Resolución del programador: un tic por minuto y uno cada TransitionTick mientras dura una transición
+const (
+	scheduleTick   = time.Minute
+	TransitionTick = 5 * time.Second
+)
+
+/**
+ * nextTick - Tiempo hasta el siguiente tic del programador
+ *
+ * Compara el estado actual con el de dentro de un minuto: si cambia hay
+ * una transición (horaria, solar o del despertador) en curso y se usa
+ * TransitionTick para que el fundido no avance a saltos de un minuto,
+ * salvo que el backend no admita pasos tan seguidos (SetTransitionTickCheck).
+ *
+ * @param {time.Time} now - Instante del tic actual
+ * @returns {time.Duration} TransitionTick durante una transición, un minuto en otro caso
+ * @private
+ */
+func (s *Scheduler) nextTick(now time.Time) time.Duration {
+	temperature, brightness := s.StateAt(now)
+	nextTemperature, nextBrightness := s.StateAt(now.Add(scheduleTick))
+	transition := math.Abs(nextTemperature-temperature) >= 1 || math.Abs(nextBrightness-brightness) >= 0.005
+	if transition && (s.tickCheck == nil || s.tickCheck()) {
+		return TransitionTick
+	}
+	return scheduleTick
+}
+
+/**
+ * SetTransitionTickCheck - Indica cómo saber si el backend admite los pasos de TransitionTick
+ *
+ * Los backends que aplican la gamma en el propio proceso (RandR, el
+ * protocolo wlr-gamma, Mutter o KWin) lo hacen al instante; los que
+ * relanzan una herramienta externa en cada cambio parpadearían cada 5
+ * segundos, así que con ellos las transiciones siguen a pasos de un
+ * minuto. Se llama antes de Start.
+ *
+ * @param {func() bool} check - Devuelve true si el backend actual admite pasos cortos
+ */
+func (s *Scheduler) SetTransitionTickCheck(check func() bool) {
+	s.tickCheck = check
+}
+
+/**
+ * TemperatureAt - Calcula la temperatura para un instante según el modo configurado
+ *
+ * En modo solar usa la elevación del sol; si no hay ubicación configurada
+ * vuelve al cálculo por horas fijas.
+ *
+ * @param {time.Time} now - Instante a evaluar
+ * @returns {float64} Temperatura a aplicar en Kelvin
+ */
+func (s *Scheduler) TemperatureAt(now time.Time) float64 {
+	temperature, _ := s.StateAt(now)
+	return temperature
+}
+
+/**
+ * StateAt - Calcula la temperatura y el brillo para un instante
+ *
+ * Con la noche omitida rige la temperatura diurna hasta que termina.
+ * Durante la rampa del despertador manda ella, y al acabar se mantiene
+ * la luz diurna hasta que el horario la alcanza; el resto del tiempo la
+ * temperatura es la del horario y el brillo baja con NightDim en la
+ * misma proporción en que la temperatura se acerca a la nocturna.
+ *
+ * @param {time.Time} now - Instante a evaluar
+ * @returns {float64, float64} Temperatura en Kelvin y brillo (0-1)
+ */
+func (s *Scheduler) StateAt(now time.Time) (temperature, brightness float64) {
+	if until, ok := s.skippedUntil(now); ok && now.Before(until) {
+		return s.dayTemperature(), 1.0
+	}
+	wakeUp := s.currentConfig().Schedule.WakeUp
+	if progress, ok := wakeUp.Progress(now); ok {
+		startBrightness := wakeUp.GetStartBrightness()
+		return s.interpolateTemperature(wakeUp.GetStartTemp(), s.dayTemperature(), progress),
+			startBrightness + (1-startBrightness)*progress
+	}
+	if s.holdingWakeUp(now) {
+		return s.dayTemperature(), 1.0
+	}
+	temperature = s.scheduleTemperatureAt(now)
+	return temperature, s.nightBrightness(temperature)
+}
+
+// holdingWakeUp indica si la rampa del despertador de hoy ya terminó y el horario aún no llegó a la luz diurna desde entonces
+func (s *Scheduler) holdingWakeUp(now time.Time) bool {
+	end, ok := s.currentConfig().Schedule.WakeUp.EndedBefore(now)
+	if !ok {
+		return false
+	}
+	day := s.dayTemperature()
+	for at := end; ; at = at.Add(sleepCheckStep) {
+		if at.After(now) {
+			at = now
+		}
+		if s.scheduleTemperatureAt(at) >= day-1 {
+			return false // El horario ya es de día: vuelve a mandar
+		}
+		if !at.Before(now) {
+			return true
+		}
+	}
+}
+
+// nightBrightness aplica la atenuación nocturna según lo cerca que esté la temperatura de la nocturna
+func (s *Scheduler) nightBrightness(temperature float64) float64 {
+	config := s.currentConfig()
+	dim := config.Schedule.NightDim
+	night, day := config.Schedule.NightTemp, s.dayTemperature()
+	if dim <= 0 || night == day {
+		return 1.0
+	}
+	nightness := math.Max(0, math.Min((day-temperature)/(day-night), 1))
+	return 1 - dim*nightness
+}
+
+// scheduleTemperatureAt calcula la temperatura del horario (solar o por horas fijas) sin el despertador
+func (s *Scheduler) scheduleTemperatureAt(now time.Time) float64 {
+	if s.isSolarMode() {
+		return s.calculateSolarTemperature(now)
+	}
+	minutes := float64(now.Hour()*60+now.Minute()) + float64(now.Second())/60
+	return s.temperatureAtMinutes(minutes)
+}
+
+/**
+ * isSolarMode - Verifica si debe usarse la curva solar
+ *
+ * @returns {bool} true si el modo es solar y hay ubicación válida
+ * @private
+ */
+func (s *Scheduler) isSolarMode() bool {
+	schedule := s.currentConfig().Schedule
+	return schedule.Mode == ScheduleModeSolar && schedule.Location.IsSet() && schedule.Location.IsValid()
+}
+
+/**
+ * calculateSolarTemperature - Temperatura como función continua de la elevación solar
+ *
+ * Interpola entre la temperatura nocturna y la diurna mientras el sol está
+ * entre el crepúsculo elegido y SolarDayElevation, de modo que el cambio
+ * sigue el amanecer y el atardecer reales a lo largo de las estaciones.
+ *
+ * @param {time.Time} now - Instante a evaluar
+ * @returns {float64} Temperatura a aplicar en Kelvin
+ * @private
+ */
+func (s *Scheduler) calculateSolarTemperature(now time.Time) float64 {
+	return s.interpolateTemperature(s.currentConfig().Schedule.NightTemp, s.dayTemperature(), s.solarProgress(now))
+}
+
+/**
+ * solarProgress - Progreso noche→día con el crepúsculo y los desplazamientos elegidos
+ *
+ * Por la mañana (sol subiendo) se aplica SunriseOffset y por la tarde
+ * SunsetOffset: con SunsetOffset -45 la curva de la tarde se evalúa 45
+ * minutos más tarde, así que la transición empieza 45 minutos antes.
+ *
+ * @param {time.Time} now - Instante a evaluar
+ * @returns {float64} 0.0 de noche, 1.0 de día
+ * @private
+ */
+func (s *Scheduler) solarProgress(now time.Time) float64 {
+	schedule := s.currentConfig().Schedule
+	offset := schedule.SunsetOffset
+	if SolarElevation(now.Add(time.Minute), schedule.Location) > SolarElevation(now, schedule.Location) {
+		offset = schedule.SunriseOffset
+	}
+
+	elevation := SolarElevation(now.Add(-time.Duration(offset)*time.Minute), schedule.Location)
+	return SolarDayProgressTo(elevation, TwilightElevation(schedule.GetTwilight()))
+}
+
+/**
+ * nextSolarChange - Busca el próximo cruce de los umbrales de elevación solar
+ *
+ * @param {time.Time} now - Instante de partida
+ * @returns {string, float64, time.Duration} Descripción, temperatura y tiempo restante
+ * @private
+ */
+func (s *Scheduler) nextSolarChange(now time.Time) (string, float64, time.Duration) {
+	schedule := s.currentConfig().Schedule
+	current := s.solarProgress(now)
+
+	// Avanzar de minuto en minuto hasta que el progreso alcance un extremo distinto
+	for step := time.Minute; step <= 48*time.Hour; step += time.Minute {
+		progress := s.solarProgress(now.Add(step))
+		if current < 1 && progress == 1 {
+			return "Fin filtro nocturno (amanecer)", s.dayTemperature(), step
+		}
+		if current > 0 && progress == 0 {
+			return "Inicio filtro nocturno (atardecer)", schedule.NightTemp, step
+		}
+	}
+
+	// Día o noche polar: no hay cruces en el horizonte de búsqueda
+	return "Sin cambios solares próximos", s.calculateSolarTemperature(now), 0
 }
 
 /**
  * calculateTemperatureForTime - Calcula la temperatura para una hora específica
  *
- * Determina qué temperatura aplicar basándose en los horarios configurados
- * y aplica transiciones suaves durante los períodos de cambio.
+ * El período nocturno va de StartTime (incluido) a EndTime (excluido) y
+ * puede cruzar medianoche. La transición de la tarde ocupa los primeros
+ * TransitionTime minutos del período (día → noche) y la de la mañana los
+ * últimos (noche → día), de modo que a EndTime ya rige la temperatura
+ * diurna. Si el período es más corto que dos transiciones, estas se
+ * acortan a la mitad del período. Con StartTime igual a EndTime no hay
+ * período nocturno.
  *
  * @param {string} currentTime - Hora actual en formato "HH:MM"
  * @returns {float64} Temperatura a aplicar en Kelvin
  * @private
  */
 func (s *Scheduler) calculateTemperatureForTime(currentTime string) float64 {
-	schedule := s.config.Schedule
+	return s.temperatureAtMinutes(float64(s.timeToMinutes(currentTime)))
+}
+
+// temperatureAtMinutes es calculateTemperatureForTime con minutos fraccionarios desde medianoche (resolución de segundos)
+func (s *Scheduler) temperatureAtMinutes(currentMinutes float64) float64 {
+	schedule := s.currentConfig().Schedule
+	const day = 24 * 60
 
 	// Convertir horarios a minutos desde medianoche para facilitar comparaciones
-	currentMinutes := s.timeToMinutes(currentTime)
 	startMinutes := s.timeToMinutes(schedule.StartTime)
 	endMinutes := s.timeToMinutes(schedule.EndTime)
 
-	// Manejar casos donde el período nocturno cruza medianoche (ej: 20:00 - 07:00)
-	var isNightPeriod bool
-	if startMinutes > endMinutes {
-		// El período nocturno cruza medianoche
-		isNightPeriod = currentMinutes >= startMinutes || currentMinutes <= endMinutes
-	} else {
-		// El período nocturno no cruza medianoche
-		isNightPeriod = currentMinutes >= startMinutes && currentMinutes <= endMinutes
+	// Duración del período nocturno y minutos transcurridos desde su inicio,
+	// ambos módulo 24h para manejar períodos que cruzan medianoche (ej: 20:00 - 07:00)
+	nightLength := (endMinutes - startMinutes + day) % day
+	elapsed := math.Mod(currentMinutes-float64(startMinutes)+day, day)
+	dayTemp := s.dayTemperature()
+	if nightLength == 0 || elapsed >= float64(nightLength) {
+		return dayTemp
 	}
 
-	// Calcular si estamos en período de transición
-	transitionMinutes := schedule.TransitionTime
+	transition := schedule.TransitionTime
+	if transition > nightLength/2 {
+		transition = nightLength / 2
+	}
+	if transition <= 0 {
+		return schedule.NightTemp
+	}
 
-	if isNightPeriod {
-		// Estamos en período nocturno
-		if transitionMinutes > 0 {
-			// Verificar si estamos en transición al inicio del período nocturno
-			transitionStart := startMinutes
-			transitionEnd := (startMinutes + transitionMinutes) % (24 * 60)
+	// Transición de la tarde: del día a la noche
+	if elapsed < float64(transition) {
+		progress := elapsed / float64(transition)
+		return s.interpolateTemperature(dayTemp, schedule.NightTemp, progress)
+	}
 
-			if s.isInTransitionPeriod(currentMinutes, transitionStart, transitionEnd, startMinutes > endMinutes) {
-				// Calcular progreso de transición (0.0 = inicio, 1.0 = final)
-				progress := s.calculateTransitionProgress(currentMinutes, transitionStart, transitionEnd, startMinutes > endMinutes)
-				return s.interpolateTemperature(schedule.DayTemp, schedule.NightTemp, progress)
-			}
-		}
-		return schedule.NightTemp
-	} else {
-		// Estamos en período diurno
-		if transitionMinutes > 0 {
-			// Verificar si estamos en transición al final del período nocturno
-			transitionStart := (endMinutes - transitionMinutes + 24*60) % (24 * 60)
-			transitionEnd := endMinutes
-
-			if s.isInTransitionPeriod(currentMinutes, transitionStart, transitionEnd, startMinutes > endMinutes) {
-				// Calcular progreso de transición (0.0 = inicio, 1.0 = final)
-				progress := s.calculateTransitionProgress(currentMinutes, transitionStart, transitionEnd, startMinutes > endMinutes)
-				return s.interpolateTemperature(schedule.NightTemp, schedule.DayTemp, progress)
-			}
-		}
-		return schedule.DayTemp
+	// Transición de la mañana: de la noche al día
+	if remaining := float64(nightLength) - elapsed; remaining <= float64(transition) {
+		progress := (float64(transition) - remaining) / float64(transition)
+		return s.interpolateTemperature(schedule.NightTemp, dayTemp, progress)
 	}
+
+	return schedule.NightTemp
 }
 
 /**
@@ -183,72 +473,83 @@ func (s *Scheduler) calculateTemperatureForTime(currentTime string) float64 {
  * @private
  */
 func (s *Scheduler) timeToMinutes(timeStr string) int {
-	var hours, minutes int
-	fmt.Sscanf(timeStr, "%d:%d", &hours, &minutes)
+	hours, minutes, err := ParseScheduleTime(timeStr)
+	if err != nil {
+		logging.Printf("⚠️  Horario inválido en la configuración: %v\n", err)
+		return 0
+	}
 	return hours*60 + minutes
 }
 
 /**
- * isInTransitionPeriod - Verifica si estamos en un período de transición
+ * ParseScheduleTime - Valida y descompone una hora en formato "HH:MM"
  *
- * @param {int} current - Minutos actuales
- * @param {int} start - Inicio de transición
- * @param {int} end - Final de transición
- * @param {bool} crossesMidnight - Si el período cruza medianoche
- * @returns {bool} true si estamos en transición
- * @private
- */
-func (s *Scheduler) isInTransitionPeriod(current, start, end int, crossesMidnight bool) bool {
-	if crossesMidnight && start > end {
-		return current >= start || current <= end
-	}
-	return current >= start && current <= end
-}
-
-/**
- * calculateTransitionProgress - Calcula el progreso de una transición
+ * Acepta horas de 00:00 a 23:59 (la hora puede tener uno o dos dígitos)
+ * y también el formato de 12 horas con sufijo ("9:30 PM", "9:30 p. m.").
+ * Valores como "25:99" o "8pm" se rechazan en lugar de convertirse en
+ * minutos sin sentido.
  *
- * @param {int} current - Minutos actuales
- * @param {int} start - Inicio de transición
- * @param {int} end - Final de transición
- * @param {bool} crossesMidnight - Si el período cruza medianoche
- * @returns {float64} Progreso de 0.0 a 1.0
- * @private
+ * @param {string} timeStr - Hora en formato "HH:MM" o "h:mm AM/PM"
+ * @returns {int, int, error} Horas, minutos y error si el formato no es válido
+ * @example
+ *   h, m, err := ParseScheduleTime("21:30") // 21, 30, nil
+ *   h, m, err = ParseScheduleTime("9:30 p. m.") // 21, 30, nil
  */
-func (s *Scheduler) calculateTransitionProgress(current, start, end int, crossesMidnight bool) float64 {
-	var duration int
-	var elapsed int
-
-	if crossesMidnight && start > end {
-		duration = (24*60 - start) + end
-		if current >= start {
-			elapsed = current - start
-		} else {
-			elapsed = (24*60 - start) + current
-		}
-	} else {
-		duration = end - start
-		elapsed = current - start
+func ParseScheduleTime(timeStr string) (hours, minutes int, err error) {
+	invalid := fmt.Errorf("hora inválida %q: use el formato HH:MM (00:00 - 23:59) o h:mm a. m./p. m.", timeStr)
+
+	// "9:30 p. m." → "9:30pm"
+	compact := strings.ToLower(strings.NewReplacer(" ", "", ".", "").Replace(timeStr))
+	suffix := ""
+	if strings.HasSuffix(compact, "am") || strings.HasSuffix(compact, "pm") {
+		suffix = compact[len(compact)-2:]
+		compact = compact[:len(compact)-2]
 	}
 
-	if duration <= 0 {
-		return 1.0
+	parsed, err := time.Parse("15:04", compact)
+	if err != nil {
+		return 0, 0, invalid
 	}
+	hours, minutes = parsed.Hour(), parsed.Minute()
 
-	progress := float64(elapsed) / float64(duration)
-	if progress < 0 {
-		progress = 0
-	}
-	if progress > 1 {
-		progress = 1
+	if suffix != "" {
+		if hours < 1 || hours > 12 {
+			return 0, 0, invalid
+		}
+		hours %= 12
+		if suffix == "pm" {
+			hours += 12
+		}
 	}
+	return hours, minutes, nil
+}
 
-	return progress
+// SetDayBias fija los Kelvin que se restan a la temperatura diurna (0 para quitar el ajuste)
+func (s *Scheduler) SetDayBias(kelvin float64) {
+	s.biasMu.Lock()
+	defer s.biasMu.Unlock()
+	s.dayBias = kelvin
+}
+
+// dayTemperature devuelve la temperatura diurna con el ajuste por nubosidad, sin bajar de la nocturna
+func (s *Scheduler) dayTemperature() float64 {
+	s.biasMu.Lock()
+	bias := s.dayBias
+	s.biasMu.Unlock()
+
+	schedule := s.currentConfig().Schedule
+	return math.Max(schedule.DayTemp-bias, math.Min(schedule.NightTemp, schedule.DayTemp))
 }
 
 /**
  * interpolateTemperature - Interpola entre dos temperaturas
  *
+ * Por defecto interpola en mired (1e6/K): la percepción de calidez es
+ * aproximadamente lineal en mired, así que una transición lineal en
+ * Kelvin parece lenta al principio y brusca al final cuando va hacia
+ * temperaturas cálidas. Con Interpolation "kelvin" se usa la
+ * interpolación lineal clásica.
+ *
  * @param {float64} from - Temperatura inicial
  * @param {float64} to - Temperatura final
  * @param {float64} progress - Progreso (0.0 a 1.0)
@@ -256,7 +557,12 @@ func (s *Scheduler) calculateTransitionProgress(current, start, end int, crosses
  * @private
  */
 func (s *Scheduler) interpolateTemperature(from, to, progress float64) float64 {
-	return from + (to-from)*progress
+	if s.currentConfig().Schedule.GetInterpolation() == InterpolationKelvin || from <= 0 || to <= 0 {
+		return from + (to-from)*progress
+	}
+
+	fromMired, toMired := 1e6/from, 1e6/to
+	return 1e6 / (fromMired + (toMired-fromMired)*progress)
 }
 
 /**
@@ -265,60 +571,224 @@ func (s *Scheduler) interpolateTemperature(from, to, progress float64) float64 {
  * @returns {string, float64, time.Duration} Descripción, temperatura y tiempo restante
  */
 func (s *Scheduler) GetNextScheduleChange() (string, float64, time.Duration) {
-	if !s.config.ScheduleEnabled {
-		return "Programación deshabilitada", s.config.LastTemperature, 0
+	config := s.currentConfig()
+	if !config.ScheduleEnabled {
+		return "Programación deshabilitada", config.LastTemperature, 0
 	}
 
-	now := time.Now()
-	schedule := s.config.Schedule
+	now := s.now()
+	if end, paused := config.Vacation.Active(now); paused {
+		return "Modo vacaciones: programación en pausa", config.LastTemperature, end.Sub(now)
+	}
+	if until, ok := s.skippedUntil(now); ok {
+		// El próximo cambio real es el de la noche siguiente
+		description, temperature, remaining := s.nextChangeAt(until)
+		return description + " (noche de hoy omitida)", temperature, until.Sub(now) + remaining
+	}
+	return s.nextChangeAt(now)
+}
 
-	// Obtener horarios de hoy
-	startTime := s.parseTimeToday(schedule.StartTime)
-	endTime := s.parseTimeToday(schedule.EndTime)
+// nextChangeAt describe el próximo cambio del horario a partir de now, sin la noche omitida
+func (s *Scheduler) nextChangeAt(now time.Time) (string, float64, time.Duration) {
+	schedule := s.currentConfig().Schedule
 
-	// Si el horario de fin es antes que el de inicio, significa que cruza medianoche
-	if endTime.Before(startTime) {
-		endTime = endTime.Add(24 * time.Hour)
+	if _, ok := schedule.WakeUp.Progress(now); ok {
+		return "Fin del despertador (luz diurna)", s.dayTemperature(), schedule.WakeUp.End(now).Sub(now)
+	}
+	if s.holdingWakeUp(now) && !s.isSolarMode() {
+		// Ya es de día desde el despertador: lo siguiente es la noche de hoy
+		return "Inicio filtro nocturno", schedule.NightTemp, nextOccurrence(now, schedule.StartTime).Sub(now)
+	}
+	if s.isSolarMode() {
+		return s.nextSolarChange(now)
 	}
 
-	var nextChange time.Time
-	var nextTemp float64
-	var description string
+	if s.timeToMinutes(schedule.StartTime) == s.timeToMinutes(schedule.EndTime) {
+		return "Sin período nocturno", s.dayTemperature(), 0
+	}
 
-	if now.Before(startTime) {
-		// Próximo cambio es el inicio del período nocturno
-		nextChange = startTime
-		nextTemp = schedule.NightTemp
-		description = "Inicio filtro nocturno"
-	} else if now.Before(endTime) {
-		// Estamos en período nocturno, próximo cambio es el fin
-		nextChange = endTime
-		nextTemp = schedule.DayTemp
-		description = "Fin filtro nocturno"
-	} else {
-		// Próximo cambio es el inicio del día siguiente
-		nextChange = startTime.Add(24 * time.Hour)
-		nextTemp = schedule.NightTemp
-		description = "Inicio filtro nocturno"
+	// Según el período actual, el próximo cambio es el fin o el inicio de la noche
+	currentTime := fmt.Sprintf("%02d:%02d", now.Hour(), now.Minute())
+	if s.isNightPeriod(currentTime) {
+		return "Fin filtro nocturno", s.dayTemperature(), nextOccurrence(now, schedule.EndTime).Sub(now)
 	}
+	return "Inicio filtro nocturno", schedule.NightTemp, nextOccurrence(now, schedule.StartTime).Sub(now)
+}
 
-	duration := nextChange.Sub(now)
-	return description, nextTemp, duration
+// isNightPeriod indica si la hora "HH:MM" cae entre StartTime (incluido) y EndTime (excluido)
+func (s *Scheduler) isNightPeriod(currentTime string) bool {
+	config := s.currentConfig()
+	const day = 24 * 60
+	startMinutes := s.timeToMinutes(config.Schedule.StartTime)
+	nightLength := (s.timeToMinutes(config.Schedule.EndTime) - startMinutes + day) % day
+	return (s.timeToMinutes(currentTime)-startMinutes+day)%day < nightLength
 }
 
 /**
- * parseTimeToday - Convierte "HH:MM" a time.Time para hoy
+ * SkipTonight - Omite solo el período nocturno de hoy
  *
- * @param {string} timeStr - Tiempo en formato "HH:MM"
- * @returns {time.Time} Tiempo completo para hoy
+ * Si ya es de noche se omite la noche en curso; si no, la próxima. La
+ * programación sigue habilitada y se reanuda sola cuando esa noche
+ * termina. Si el programador está en marcha aplica enseguida la
+ * temperatura diurna.
+ *
+ * @returns {time.Time, error} Hora a la que se reanuda; error si no hay noche que omitir
+ */
+func (s *Scheduler) SkipTonight() (time.Time, error) {
+	until, ok := s.nightEnd(s.now())
+	if !ok {
+		return time.Time{}, fmt.Errorf("no hay ningún período nocturno próximo que omitir")
+	}
+
+	s.skipMu.Lock()
+	s.skipUntil = until
+	s.skipMu.Unlock()
+
+	logging.Printf("⏭️  Noche de hoy omitida hasta %s\n", until.Format("02/01 15:04"))
+	if s.IsRunning() {
+		s.applyCurrentTemperature()
+	}
+	return until, nil
+}
+
+/**
+ * SetIdle - Pausa los tics mientras nadie usa el equipo
+ *
+ * Durante la inactividad no se aplica nada, ni siquiera los pasos de
+ * una transición. Al volver se aplica enseguida el estado de la hora
+ * actual, sin esperar al siguiente tic.
+ *
+ * @param {bool} idle - true al empezar la inactividad, false al volver
+ */
+func (s *Scheduler) SetIdle(idle bool) {
+	s.idleMu.Lock()
+	changed := s.idle != idle
+	s.idle = idle
+	s.idleMu.Unlock()
+
+	if changed && !idle && s.IsRunning() {
+		s.applyCurrentTemperature()
+	}
+}
+
+// isIdle indica si los tics están en pausa por inactividad
+func (s *Scheduler) isIdle() bool {
+	s.idleMu.Lock()
+	defer s.idleMu.Unlock()
+	return s.idle
+}
+
+// CancelSkip vuelve a aplicar la noche omitida; indica si había una
+func (s *Scheduler) CancelSkip() bool {
+	s.skipMu.Lock()
+	skipped := !s.skipUntil.IsZero() && s.now().Before(s.skipUntil)
+	s.skipUntil = time.Time{}
+	s.skipMu.Unlock()
+
+	if skipped && s.IsRunning() {
+		s.applyCurrentTemperature()
+	}
+	return skipped
+}
+
+// SkippedUntil devuelve hasta cuándo está omitida la noche de hoy, si lo está
+func (s *Scheduler) SkippedUntil() (time.Time, bool) {
+	return s.skippedUntil(s.now())
+}
+
+// skippedUntil devuelve el fin de la noche omitida si aún no ha llegado en now
+func (s *Scheduler) skippedUntil(now time.Time) (time.Time, bool) {
+	s.skipMu.Lock()
+	defer s.skipMu.Unlock()
+	if s.skipUntil.IsZero() || !now.Before(s.skipUntil) {
+		return time.Time{}, false
+	}
+	return s.skipUntil, true
+}
+
+// sleepCheckStep es la resolución con la que se comprueba que la fase de sueño no cruzó el día
+const sleepCheckStep = 10 * time.Minute
+
+/**
+ * SleepModeAt - Indica si en un instante rige la fase de sueño
+ *
+ * La fase empieza a la hora configurada y dura lo que quede de la misma
+ * noche: si entre el inicio y now hubo día (p. ej. la hora cae de día o
+ * antes de la puesta de sol) no se aplica. Con la noche omitida o en
+ * vacaciones tampoco.
+ *
+ * @param {time.Time} now - Instante a evaluar
+ * @returns {bool} true si la pantalla debe pasar a escala de grises
+ */
+func (s *Scheduler) SleepModeAt(now time.Time) bool {
+	config := s.currentConfig()
+	sleep := config.Schedule.SleepMode
+	if !sleep.Enabled || !s.isNightAt(now) {
+		return false
+	}
+	if _, paused := config.Vacation.Active(now); paused {
+		return false
+	}
+	if until, ok := s.skippedUntil(now); ok && now.Before(until) {
+		return false
+	}
+
+	start, ok := sleep.lastStart(now)
+	if !ok {
+		return false
+	}
+	for at := start; at.Before(now); at = at.Add(sleepCheckStep) {
+		if !s.isNightAt(at) {
+			return false
+		}
+	}
+	return true
+}
+
+/**
+ * nightEnd - Busca el final de la noche en curso o, si es de día, de la próxima
+ *
+ * @param {time.Time} now - Instante de partida
+ * @returns {time.Time, bool} Primer instante de día tras la noche; false si no hay noche en 48h
+ * @private
+ */
+func (s *Scheduler) nightEnd(now time.Time) (time.Time, bool) {
+	night := false
+	for step := time.Duration(0); step <= 48*time.Hour; step += time.Minute {
+		at := now.Add(step)
+		if s.isNightAt(at) {
+			night = true
+		} else if night {
+			return at, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// isNightAt indica si en un instante rige el período nocturno (incluidas sus transiciones)
+func (s *Scheduler) isNightAt(now time.Time) bool {
+	if s.isSolarMode() {
+		return s.solarProgress(now) < 1
+	}
+	return s.isNightPeriod(fmt.Sprintf("%02d:%02d", now.Hour(), now.Minute()))
+}
+
+/**
+ * nextOccurrence - Próximo instante posterior a now con la hora "HH:MM"
+ *
+ * @param {time.Time} now - Instante de referencia
+ * @param {string} timeStr - Hora en formato "HH:MM"
+ * @returns {time.Time} Hoy a esa hora si aún no pasó, si no mañana
  * @private
  */
-func (s *Scheduler) parseTimeToday(timeStr string) time.Time {
-	var hours, minutes int
-	fmt.Sscanf(timeStr, "%d:%d", &hours, &minutes)
+func nextOccurrence(now time.Time, timeStr string) time.Time {
+	hours, minutes, _ := ParseScheduleTime(timeStr)
 
-	now := time.Now()
-	return time.Date(now.Year(), now.Month(), now.Day(), hours, minutes, 0, 0, now.Location())
+	next := time.Date(now.Year(), now.Month(), now.Day(), hours, minutes, 0, 0, now.Location())
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
 }
 
 /**
@@ -327,15 +797,63 @@ func (s *Scheduler) parseTimeToday(timeStr string) time.Time {
  * @param {*AppConfig} newConfig - Nueva configuración
  */
 func (s *Scheduler) UpdateConfig(newConfig *AppConfig) {
+	s.lockConfig()
 	s.config = newConfig
+	enabled := newConfig.ScheduleEnabled
+	s.unlockConfig()
 
-	// Si la programación se deshabilitó, detener
-	if !newConfig.ScheduleEnabled && s.isRunning {
+	// Si la programación se deshabilitó, detener; si se habilitó, iniciar
+	// (Start y Stop no hacen nada si ya estaba en ese estado)
+	if enabled {
+		s.Start()
+	} else {
 		s.Stop()
 	}
+}
 
-	// Si se habilitó y no está corriendo, iniciar
-	if newConfig.ScheduleEnabled && !s.isRunning {
-		s.Start()
+/**
+ * SetPanicHandler - Indica qué hacer si la goroutine del programador entra en pánico
+ *
+ * El recover de main solo cubre la goroutine principal; con este
+ * manejador se restaura la gamma antes de que el pánico termine el
+ * proceso. Se llama antes de Start.
+ *
+ * @param {func()} handler - Función que se ejecuta antes de relanzar el pánico
+ */
+func (s *Scheduler) SetPanicHandler(handler func()) {
+	s.onPanic = handler
+}
+
+/**
+ * SetConfigLock - Indica el cerrojo con el que se modifica la configuración compartida
+ *
+ * El programador lee la configuración desde su propia goroutine; con el
+ * cerrojo de quien la modifica, cada lectura es una copia coherente.
+ *
+ * @param {sync.Locker} lock - Cerrojo que protege la configuración pasada a NewScheduler
+ */
+func (s *Scheduler) SetConfigLock(lock sync.Locker) {
+	s.configLock = lock
+}
+
+// currentConfig devuelve una copia de la configuración tomada con el cerrojo de su dueño
+func (s *Scheduler) currentConfig() *AppConfig {
+	s.lockConfig()
+	defer s.unlockConfig()
+	config := *s.config
+	return &config
+}
+
+// lockConfig toma el cerrojo de la configuración, si hay uno
+func (s *Scheduler) lockConfig() {
+	if s.configLock != nil {
+		s.configLock.Lock()
+	}
+}
+
+// unlockConfig suelta el cerrojo tomado con lockConfig
+func (s *Scheduler) unlockConfig() {
+	if s.configLock != nil {
+		s.configLock.Unlock()
 	}
 }
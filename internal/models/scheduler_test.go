@@ -0,0 +1,425 @@
+package models
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// newTestScheduler crea un programador con horario fijo y reloj fijo
+func newTestScheduler(start, end string, transition int, clock time.Time) *Scheduler {
+	config := NewAppConfig()
+	config.ScheduleEnabled = true
+	config.Schedule.StartTime = start
+	config.Schedule.EndTime = end
+	config.Schedule.NightTemp = 3000
+	config.Schedule.DayTemp = 6000
+	config.Schedule.TransitionTime = transition
+	config.Schedule.Interpolation = InterpolationKelvin
+
+	scheduler := NewScheduler(config, nil)
+	scheduler.now = func() time.Time { return clock }
+	return scheduler
+}
+
+func TestCalculateTemperatureForTime(t *testing.T) {
+	tests := []struct {
+		name       string
+		start, end string
+		transition int
+		at         string
+		want       float64
+	}{
+		// Período que cruza medianoche, sin transición
+		{"medianoche: antes del inicio", "20:00", "07:00", 0, "19:59", 6000},
+		{"medianoche: justo al inicio", "20:00", "07:00", 0, "20:00", 3000},
+		{"medianoche: 23:59", "20:00", "07:00", 0, "23:59", 3000},
+		{"medianoche: 00:00", "20:00", "07:00", 0, "00:00", 3000},
+		{"medianoche: último minuto nocturno", "20:00", "07:00", 0, "06:59", 3000},
+		{"medianoche: fin excluido", "20:00", "07:00", 0, "07:00", 6000},
+		{"medianoche: mediodía", "20:00", "07:00", 0, "12:00", 6000},
+
+		// Período que cruza medianoche, con transición de 30 minutos
+		{"transición tarde: inicio", "20:00", "07:00", 30, "20:00", 6000},
+		{"transición tarde: mitad", "20:00", "07:00", 30, "20:15", 4500},
+		{"transición tarde: fin", "20:00", "07:00", 30, "20:30", 3000},
+		{"transición mañana: inicio", "20:00", "07:00", 30, "06:30", 3000},
+		{"transición mañana: mitad", "20:00", "07:00", 30, "06:45", 4500},
+		{"transición mañana: último minuto", "20:00", "07:00", 30, "06:59", 5900},
+		{"transición mañana: fin", "20:00", "07:00", 30, "07:00", 6000},
+
+		// Transiciones que cruzan la medianoche
+		{"transición tarde cruza medianoche", "23:50", "07:00", 20, "00:00", 4500},
+		{"transición mañana cruza medianoche", "22:00", "00:10", 20, "00:00", 4500},
+
+		// Período dentro del mismo día
+		{"mismo día: dentro", "01:00", "05:00", 0, "03:00", 3000},
+		{"mismo día: antes", "01:00", "05:00", 0, "00:59", 6000},
+		{"mismo día: después", "01:00", "05:00", 0, "05:00", 6000},
+		{"mismo día: transición mitad", "01:00", "05:00", 60, "01:30", 4500},
+
+		// Transición más larga que el período: se acorta a la mitad del período
+		{"transición recortada: mitad del período", "20:00", "21:00", 120, "20:30", 3000},
+		{"transición recortada: tarde", "20:00", "21:00", 120, "20:15", 4500},
+		{"transición recortada: mañana", "20:00", "21:00", 120, "20:45", 4500},
+
+		// Inicio igual al fin: no hay período nocturno
+		{"horas iguales: a esa hora", "20:00", "20:00", 0, "20:00", 6000},
+		{"horas iguales: otra hora", "20:00", "20:00", 30, "03:00", 6000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheduler := newTestScheduler(tt.start, tt.end, tt.transition, time.Now())
+			got := scheduler.calculateTemperatureForTime(tt.at)
+			if math.Abs(got-tt.want) > 0.5 {
+				t.Errorf("%s-%s (transición %d) a las %s = %.1fK, se esperaba %.1fK",
+					tt.start, tt.end, tt.transition, tt.at, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalculateTemperatureForTimeIsContinuous(t *testing.T) {
+	// Con transición, ningún minuto debe saltar más que un paso de la transición
+	scheduler := newTestScheduler("21:00", "06:30", 45, time.Now())
+	step := (6000.0 - 3000.0) / 45
+
+	previous := scheduler.calculateTemperatureForTime("00:00")
+	for minute := 1; minute <= 24*60; minute++ {
+		at := minute % (24 * 60)
+		current := scheduler.calculateTemperatureForTime(formatMinutes(at))
+		if math.Abs(current-previous) > step+0.01 {
+			t.Fatalf("salto de %.1fK a %.1fK a las %s", previous, current, formatMinutes(at))
+		}
+		previous = current
+	}
+}
+
+func TestInterpolateTemperatureMired(t *testing.T) {
+	scheduler := newTestScheduler("20:00", "07:00", 30, time.Now())
+	scheduler.config.Schedule.Interpolation = InterpolationMired
+
+	tests := []struct {
+		from, to, progress float64
+		want               float64
+	}{
+		{6000, 3000, 0, 6000},
+		{6000, 3000, 1, 3000},
+		{6000, 3000, 0.5, 4000}, // Punto medio en mired: (166.7 + 333.3) / 2 = 250 → 4000K
+		{3000, 6000, 0.5, 4000},
+		{6500, 6500, 0.5, 6500},
+	}
+
+	for _, tt := range tests {
+		got := scheduler.interpolateTemperature(tt.from, tt.to, tt.progress)
+		if math.Abs(got-tt.want) > 0.5 {
+			t.Errorf("interpolateTemperature(%.0f, %.0f, %.2f) = %.1f, se esperaba %.1f",
+				tt.from, tt.to, tt.progress, got, tt.want)
+		}
+	}
+
+	// Las configuraciones antiguas sin "interpolation" usan mired
+	scheduler.config.Schedule.Interpolation = ""
+	if got := scheduler.calculateTemperatureForTime("20:15"); math.Abs(got-4000) > 0.5 {
+		t.Errorf("transición sin unidad configurada = %.1fK, se esperaba 4000K (mired)", got)
+	}
+}
+
+func TestGetNextScheduleChange(t *testing.T) {
+	day := func(hour, minute int) time.Time {
+		return time.Date(2024, time.March, 10, hour, minute, 0, 0, time.Local)
+	}
+
+	tests := []struct {
+		name         string
+		start, end   string
+		now          time.Time
+		wantDesc     string
+		wantTemp     float64
+		wantDuration time.Duration
+	}{
+		{"de día, inicio hoy", "20:00", "07:00", day(12, 0), "Inicio filtro nocturno", 3000, 8 * time.Hour},
+		{"de noche antes de medianoche", "20:00", "07:00", day(22, 0), "Fin filtro nocturno", 6000, 9 * time.Hour},
+		{"de noche después de medianoche", "20:00", "07:00", day(3, 0), "Fin filtro nocturno", 6000, 4 * time.Hour},
+		{"justo al fin", "20:00", "07:00", day(7, 0), "Inicio filtro nocturno", 3000, 13 * time.Hour},
+		{"justo al inicio", "20:00", "07:00", day(20, 0), "Fin filtro nocturno", 6000, 11 * time.Hour},
+		{"mismo día, después del fin", "01:00", "05:00", day(6, 0), "Inicio filtro nocturno", 3000, 19 * time.Hour},
+		{"horas iguales", "20:00", "20:00", day(12, 0), "Sin período nocturno", 6000, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheduler := newTestScheduler(tt.start, tt.end, 30, tt.now)
+			desc, temp, duration := scheduler.GetNextScheduleChange()
+			if desc != tt.wantDesc || temp != tt.wantTemp || duration != tt.wantDuration {
+				t.Errorf("GetNextScheduleChange() = (%q, %.0f, %v), se esperaba (%q, %.0f, %v)",
+					desc, temp, duration, tt.wantDesc, tt.wantTemp, tt.wantDuration)
+			}
+		})
+	}
+}
+
+func TestParseScheduleTime(t *testing.T) {
+	tests := []struct {
+		input         string
+		hours, minute int
+		wantErr       bool
+	}{
+		{"00:00", 0, 0, false},
+		{"23:59", 23, 59, false},
+		{"7:05", 7, 5, false},
+		{" 21:30 ", 21, 30, false},
+		{"24:00", 0, 0, true},
+		{"25:99", 0, 0, true},
+		{"8pm", 0, 0, true},
+		{"", 0, 0, true},
+	}
+
+	for _, tt := range tests {
+		hours, minutes, err := ParseScheduleTime(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseScheduleTime(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && (hours != tt.hours || minutes != tt.minute) {
+			t.Errorf("ParseScheduleTime(%q) = %d:%d, se esperaba %d:%d", tt.input, hours, minutes, tt.hours, tt.minute)
+		}
+	}
+}
+
+// formatMinutes convierte minutos desde medianoche a "HH:MM"
+func formatMinutes(minutes int) string {
+	return time.Date(0, 1, 1, minutes/60, minutes%60, 0, 0, time.UTC).Format("15:04")
+}
+
+func TestSolarTwilightAndOffsets(t *testing.T) {
+	noon := time.Date(2024, time.March, 20, 12, 0, 0, 0, time.UTC)
+	nightStart := func(twilight string, sunsetOffset int) time.Duration {
+		scheduler := newTestScheduler("20:00", "07:00", 30, noon)
+		scheduler.config.Schedule.Mode = ScheduleModeSolar
+		scheduler.config.Schedule.Location = Location{Latitude: 0.1, Longitude: 0.1}
+		scheduler.config.Schedule.Twilight = twilight
+		scheduler.config.Schedule.SunsetOffset = sunsetOffset
+		desc, _, duration := scheduler.GetNextScheduleChange()
+		if desc != "Inicio filtro nocturno (atardecer)" {
+			t.Fatalf("GetNextScheduleChange() = %q, se esperaba el atardecer", desc)
+		}
+		return duration
+	}
+
+	civil := nightStart(TwilightCivil, 0)
+	if earlier := nightStart(TwilightCivil, -45); civil-earlier != 45*time.Minute {
+		t.Errorf("con sunset_offset -45 la noche empieza %v antes, se esperaban 45m", civil-earlier)
+	}
+	// En el ecuador el sol baja ~1° cada 4 minutos: de -6° a -12° hay unos 24 minutos
+	if nautical := nightStart(TwilightNautical, 0); nautical-civil < 20*time.Minute || nautical-civil > 30*time.Minute {
+		t.Errorf("el crepúsculo náutico termina %v después del civil, se esperaban ~24m", nautical-civil)
+	}
+	if astronomical := nightStart(TwilightAstronomical, 0); astronomical <= nightStart(TwilightNautical, 0) {
+		t.Error("el crepúsculo astronómico debe terminar después del náutico")
+	}
+}
+
+func TestWakeUpRamp(t *testing.T) {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2024, time.March, 11, hour, minute, 0, 0, time.Local) // Lunes
+	}
+	scheduler := newTestScheduler("22:00", "08:00", 30, at(6, 0))
+	scheduler.config.Schedule.WakeUp = WakeUpConfig{
+		Enabled: true, Time: "06:30", Duration: 20, StartTemp: 3000, StartBrightness: 0.5,
+		Easing: EasingLinear, Weekdays: []int{1, 2, 3, 4, 5},
+	}
+
+	tests := []struct {
+		name           string
+		now            time.Time
+		wantTemp       float64
+		wantBrightness float64
+	}{
+		{"antes de la rampa", at(6, 29), 3000, 1.0},
+		{"inicio", at(6, 30), 3000, 0.5},
+		{"mitad", at(6, 40), 4500, 0.75},
+		{"tras la rampa se mantiene el día", at(6, 50), 6000, 1.0},
+		{"hasta el fin de la noche del horario", at(7, 45), 6000, 1.0},
+		{"de día manda el horario", at(12, 0), 6000, 1.0},
+		{"por la noche vuelve el horario", at(23, 0), 3000, 1.0},
+		{"sábado sin despertador", at(6, 40).AddDate(0, 0, 5), 3000, 1.0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			temp, brightness := scheduler.StateAt(tt.now)
+			if math.Abs(temp-tt.wantTemp) > 0.5 || math.Abs(brightness-tt.wantBrightness) > 0.001 {
+				t.Errorf("StateAt() = (%.1fK, %.3f), se esperaba (%.0fK, %.2f)", temp, brightness, tt.wantTemp, tt.wantBrightness)
+			}
+		})
+	}
+
+	scheduler.now = func() time.Time { return at(6, 35) }
+	if desc, temp, duration := scheduler.GetNextScheduleChange(); desc != "Fin del despertador (luz diurna)" || temp != 6000 || duration != 15*time.Minute {
+		t.Errorf("GetNextScheduleChange() = (%q, %.0f, %v) durante la rampa", desc, temp, duration)
+	}
+	scheduler.now = func() time.Time { return at(7, 0) }
+	if desc, _, duration := scheduler.GetNextScheduleChange(); desc != "Inicio filtro nocturno" || duration != 15*time.Hour {
+		t.Errorf("GetNextScheduleChange() = (%q, %v) tras la rampa", desc, duration)
+	}
+}
+
+func TestSkipTonight(t *testing.T) {
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2024, time.March, day, hour, minute, 0, 0, time.Local)
+	}
+
+	tests := []struct {
+		name      string
+		now       time.Time
+		wantUntil time.Time
+	}{
+		{"de día omite la noche siguiente", at(11, 15, 0), at(12, 7, 0)},
+		{"de noche omite la noche en curso", at(12, 2, 0), at(12, 7, 0)},
+		{"durante la transición de la tarde", at(11, 20, 10), at(12, 7, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheduler := newTestScheduler("20:00", "07:00", 30, tt.now)
+			until, err := scheduler.SkipTonight()
+			if err != nil || !until.Equal(tt.wantUntil) {
+				t.Fatalf("SkipTonight() = (%v, %v), se esperaba %v", until, err, tt.wantUntil)
+			}
+			if temp := scheduler.TemperatureAt(at(11, 23, 0)); tt.now.Before(at(11, 23, 0)) && temp != 6000 {
+				t.Errorf("TemperatureAt(23:00) = %.0fK con la noche omitida", temp)
+			}
+			// La noche siguiente vuelve a aplicarse
+			if temp := scheduler.TemperatureAt(at(12, 23, 0)); temp != 3000 {
+				t.Errorf("TemperatureAt(23:00 del día siguiente) = %.0fK, se esperaba 3000K", temp)
+			}
+		})
+	}
+
+	scheduler := newTestScheduler("20:00", "07:00", 30, at(11, 15, 0))
+	scheduler.SkipTonight()
+	if desc, _, duration := scheduler.GetNextScheduleChange(); duration != 29*time.Hour || desc != "Inicio filtro nocturno (noche de hoy omitida)" {
+		t.Errorf("GetNextScheduleChange() = (%q, %v) con la noche omitida", desc, duration)
+	}
+	if !scheduler.CancelSkip() || scheduler.TemperatureAt(at(11, 23, 0)) != 3000 {
+		t.Error("CancelSkip() no devolvió la noche de hoy")
+	}
+
+	// Sin período nocturno no hay nada que omitir
+	if _, err := newTestScheduler("20:00", "20:00", 30, at(11, 15, 0)).SkipTonight(); err == nil {
+		t.Error("SkipTonight() sin período nocturno debería fallar")
+	}
+}
+
+func TestVacationPausesSchedule(t *testing.T) {
+	now := time.Date(2024, time.March, 11, 23, 0, 0, 0, time.Local)
+	scheduler := newTestScheduler("20:00", "07:00", 30, now)
+	applied := 0
+	scheduler.onApply = func(float64, float64) error { applied++; return nil }
+
+	scheduler.config.Vacation = VacationConfig{From: "2024-03-10", To: "2024-03-11"}
+	scheduler.applyCurrentTemperature()
+	if applied != 0 {
+		t.Error("el programador aplicó una temperatura en vacaciones")
+	}
+	if desc, _, duration := scheduler.GetNextScheduleChange(); desc != "Modo vacaciones: programación en pausa" || duration != time.Hour {
+		t.Errorf("GetNextScheduleChange() = (%q, %v) en vacaciones", desc, duration)
+	}
+
+	// El día siguiente al último se reanuda
+	scheduler.now = func() time.Time { return now.Add(2 * time.Hour) }
+	scheduler.applyCurrentTemperature()
+	if applied != 1 {
+		t.Error("el programador no se reanudó al terminar las vacaciones")
+	}
+}
+
+func TestIdlePausesSchedule(t *testing.T) {
+	scheduler := newTestScheduler("20:00", "07:00", 30, time.Date(2024, time.March, 11, 23, 0, 0, 0, time.Local))
+	var applied []float64
+	scheduler.onApply = func(temperature, _ float64) error { applied = append(applied, temperature); return nil }
+	scheduler.isRunning = true // Sin goroutine: los tics se simulan a mano
+
+	scheduler.SetIdle(true)
+	scheduler.applyCurrentTemperature()
+	scheduler.applyTransitionStep()
+	if len(applied) != 0 {
+		t.Fatalf("el programador aplicó %v con el equipo inactivo", applied)
+	}
+
+	// Al volver se aplica enseguida el estado de la hora actual
+	scheduler.SetIdle(false)
+	if len(applied) != 1 || applied[0] != 3000 {
+		t.Fatalf("al volver de la inactividad se aplicó %v, se esperaba [3000]", applied)
+	}
+}
+
+func TestSleepModeAt(t *testing.T) {
+	day := time.Date(2024, time.March, 11, 0, 0, 0, 0, time.Local)
+	scheduler := newTestScheduler("20:00", "07:00", 30, day)
+	scheduler.config.Schedule.SleepMode = SleepConfig{Enabled: true, Time: "23:00"}
+
+	tests := []struct {
+		at   time.Time
+		want bool
+	}{
+		{day.Add(21 * time.Hour), false},               // Noche, pero antes de la fase
+		{day.Add(23*time.Hour + 30*time.Minute), true}, // Dentro de la fase
+		{day.Add(27 * time.Hour), true},                // Madrugada: sigue la misma noche
+		{day.Add(32 * time.Hour), false},               // Día siguiente
+	}
+	for _, tt := range tests {
+		if got := scheduler.SleepModeAt(tt.at); got != tt.want {
+			t.Errorf("SleepModeAt(%s) = %v, se esperaba %v", tt.at.Format("02 15:04"), got, tt.want)
+		}
+	}
+
+	// Una hora de inicio que cae de día no abarca la noche siguiente
+	scheduler.config.Schedule.SleepMode.Time = "18:00"
+	if scheduler.SleepModeAt(day.Add(21 * time.Hour)) {
+		t.Error("la fase empezó de día y no debería aplicarse por la noche")
+	}
+}
+
+func TestNightDimFollowsTransition(t *testing.T) {
+	day := time.Date(2024, time.March, 11, 0, 0, 0, 0, time.Local)
+	scheduler := newTestScheduler("20:00", "07:00", 60, day)
+	scheduler.config.Schedule.NightDim = 0.4
+
+	tests := []struct {
+		at   time.Time
+		want float64
+	}{
+		{day.Add(12 * time.Hour), 1.0},                // Día: brillo completo
+		{day.Add(20*time.Hour + 30*time.Minute), 0.8}, // Mitad de la transición
+		{day.Add(23 * time.Hour), 0.6},                // Noche: atenuación completa
+	}
+	for _, tt := range tests {
+		if _, brightness := scheduler.StateAt(tt.at); math.Abs(brightness-tt.want) > 0.01 {
+			t.Errorf("StateAt(%s) brillo = %.2f, se esperaba %.2f", tt.at.Format("15:04"), brightness, tt.want)
+		}
+	}
+}
+
+func TestTransitionTickResolution(t *testing.T) {
+	day := time.Date(2024, time.March, 11, 0, 0, 0, 0, time.Local)
+	scheduler := newTestScheduler("20:00", "07:00", 30, day)
+
+	if got := scheduler.nextTick(day.Add(12 * time.Hour)); got != time.Minute {
+		t.Errorf("nextTick() de día = %v, se esperaba un minuto", got)
+	}
+	if got := scheduler.nextTick(day.Add(20*time.Hour + 10*time.Minute)); got != TransitionTick {
+		t.Errorf("nextTick() en la transición = %v, se esperaba %v", got, TransitionTick)
+	}
+	scheduler.SetTransitionTickCheck(func() bool { return false })
+	if got := scheduler.nextTick(day.Add(20*time.Hour + 10*time.Minute)); got != time.Minute {
+		t.Errorf("nextTick() en la transición con un backend externo = %v, se esperaba un minuto", got)
+	}
+	scheduler.SetTransitionTickCheck(nil)
+
+	// Dentro de un minuto la temperatura avanza con los segundos
+	at := day.Add(20*time.Hour + 10*time.Minute)
+	if before, after := scheduler.TemperatureAt(at), scheduler.TemperatureAt(at.Add(30*time.Second)); after >= before || math.Abs(before-after-50) > 0.01 {
+		t.Errorf("TemperatureAt() a los 30 s = %.1fK, se esperaba 50K menos que %.1fK", after, before)
+	}
+}
@@ -0,0 +1,65 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// Valores de la fase de sueño
+const (
+	DefaultSleepTime   = "23:00"
+	MaxSleepSaturation = 0.5 // Con más color la fase deja de notarse
+)
+
+/**
+ * SleepConfig - Fase de sueño: pantalla en escala de grises a altas horas
+ *
+ * Desde Time hasta el final de la noche programada la pantalla pierde
+ * el color (o casi todo, con Saturation), una medida más fuerte que la
+ * temperatura contra seguir con el móvil o las redes de madrugada. Es
+ * una capa sobre el programador: solo actúa con la programación en
+ * marcha y en los backends con matriz de color (X11 con CTM).
+ *
+ * @struct {SleepConfig}
+ * @example
+ *   SleepConfig{Enabled: true, Time: "23:30", Saturation: 0.2} // 20 % del color
+ */
+type SleepConfig struct {
+	Enabled    bool    `json:"enabled"`
+	Time       string  `json:"time"`       // Inicio de la fase ("HH:MM", "" = DefaultSleepTime)
+	Saturation float64 `json:"saturation"` // Color que se conserva (0 = escala de grises, hasta MaxSleepSaturation)
+}
+
+// GetTime devuelve la hora de inicio; vacía usa DefaultSleepTime
+func (sleep SleepConfig) GetTime() string {
+	if sleep.Time == "" {
+		return DefaultSleepTime
+	}
+	return sleep.Time
+}
+
+// lastStart devuelve el inicio de la fase más reciente anterior (o igual) a now
+func (sleep SleepConfig) lastStart(now time.Time) (time.Time, bool) {
+	hours, minutes, err := ParseScheduleTime(sleep.GetTime())
+	if err != nil {
+		return time.Time{}, false
+	}
+	start := time.Date(now.Year(), now.Month(), now.Day(), hours, minutes, 0, 0, now.Location())
+	if start.After(now) {
+		start = start.AddDate(0, 0, -1)
+	}
+	return start, true
+}
+
+// Validate verifica la hora y la saturación de la fase de sueño
+func (sleep SleepConfig) Validate() error {
+	if sleep.Time != "" {
+		if _, _, err := ParseScheduleTime(sleep.Time); err != nil {
+			return fmt.Errorf("hora: %w", err)
+		}
+	}
+	if sleep.Saturation < 0 || sleep.Saturation > MaxSleepSaturation {
+		return fmt.Errorf("saturación fuera de rango (0 - %.1f)", MaxSleepSaturation)
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package models
+
+import (
+	"math"
+	"time"
+)
+
+// Umbrales de elevación solar (en grados) usados por la curva continua,
+// los mismos que usa redshift por defecto
+const (
+	SolarDayElevation   = 3.0  // Por encima: temperatura diurna completa
+	SolarNightElevation = -6.0 // Por debajo: temperatura nocturna completa (crepúsculo civil)
+)
+
+// Crepúsculos que pueden marcar el límite de la noche en el modo solar
+const (
+	TwilightCivil        = "civil"        // Sol a 6° bajo el horizonte
+	TwilightNautical     = "nautical"     // Sol a 12° bajo el horizonte
+	TwilightAstronomical = "astronomical" // Sol a 18° bajo el horizonte
+)
+
+// twilightElevations es la elevación solar de cada crepúsculo, en grados
+var twilightElevations = map[string]float64{
+	TwilightCivil:        SolarNightElevation,
+	TwilightNautical:     -12.0,
+	TwilightAstronomical: -18.0,
+}
+
+// TwilightElevation devuelve la elevación solar de un crepúsculo; los valores desconocidos usan el civil
+func TwilightElevation(twilight string) float64 {
+	if elevation, ok := twilightElevations[twilight]; ok {
+		return elevation
+	}
+	return SolarNightElevation
+}
+
+// Location representa una ubicación geográfica en grados decimales
+type Location struct {
+	Latitude  float64 `json:"latitude"`  // Positiva al norte del ecuador
+	Longitude float64 `json:"longitude"` // Positiva al este de Greenwich
+}
+
+// IsSet indica si la ubicación fue configurada (0,0 se considera vacía)
+func (l Location) IsSet() bool {
+	return l.Latitude != 0 || l.Longitude != 0
+}
+
+// IsValid verifica que las coordenadas estén dentro de rango
+func (l Location) IsValid() bool {
+	return l.Latitude >= -90 && l.Latitude <= 90 && l.Longitude >= -180 && l.Longitude <= 180
+}
+
+/**
+ * SolarElevation - Calcula la elevación del sol sobre el horizonte
+ *
+ * Usa la aproximación del Astronomical Almanac (precisión ~0.01°), más que
+ * suficiente para decidir la temperatura de color de la pantalla.
+ *
+ * @param {time.Time} t - Instante a evaluar
+ * @param {Location} loc - Ubicación del observador
+ * @returns {float64} Elevación en grados (negativa bajo el horizonte)
+ * @example
+ *   elev := SolarElevation(time.Now(), Location{Latitude: 4.6, Longitude: -74.1})
+ */
+func SolarElevation(t time.Time, loc Location) float64 {
+	// Días desde J2000.0
+	jd := float64(t.UTC().UnixNano())/float64(24*time.Hour) + 2440587.5
+	n := jd - 2451545.0
+
+	// Longitud media y anomalía media del sol
+	meanLong := normalizeDegrees(280.460 + 0.9856474*n)
+	meanAnomaly := degToRad(normalizeDegrees(357.528 + 0.9856003*n))
+
+	// Longitud eclíptica y oblicuidad de la eclíptica
+	eclipticLong := degToRad(meanLong + 1.915*math.Sin(meanAnomaly) + 0.020*math.Sin(2*meanAnomaly))
+	obliquity := degToRad(23.439 - 0.0000004*n)
+
+	// Coordenadas ecuatoriales
+	rightAscension := math.Atan2(math.Cos(obliquity)*math.Sin(eclipticLong), math.Cos(eclipticLong))
+	declination := math.Asin(math.Sin(obliquity) * math.Sin(eclipticLong))
+
+	// Tiempo sidéreo local y ángulo horario
+	gmst := math.Mod(18.697374558+24.06570982441908*n, 24)
+	localSidereal := degToRad(normalizeDegrees(gmst*15 + loc.Longitude))
+	hourAngle := localSidereal - rightAscension
+
+	lat := degToRad(loc.Latitude)
+	elevation := math.Asin(math.Sin(lat)*math.Sin(declination) +
+		math.Cos(lat)*math.Cos(declination)*math.Cos(hourAngle))
+
+	return radToDeg(elevation)
+}
+
+/**
+ * SolarDayProgress - Convierte la elevación solar en progreso noche→día
+ *
+ * @param {float64} elevation - Elevación solar en grados
+ * @returns {float64} 0.0 de noche, 1.0 de día, interpolado durante el crepúsculo
+ */
+func SolarDayProgress(elevation float64) float64 {
+	return SolarDayProgressTo(elevation, SolarNightElevation)
+}
+
+/**
+ * SolarDayProgressTo - Progreso noche→día con un límite de noche elegido
+ *
+ * @param {float64} elevation - Elevación solar en grados
+ * @param {float64} nightElevation - Elevación por debajo de la cual es de noche (TwilightElevation)
+ * @returns {float64} 0.0 de noche, 1.0 de día, interpolado entre ambos límites
+ */
+func SolarDayProgressTo(elevation, nightElevation float64) float64 {
+	switch {
+	case elevation >= SolarDayElevation:
+		return 1.0
+	case elevation <= nightElevation:
+		return 0.0
+	default:
+		return (elevation - nightElevation) / (SolarDayElevation - nightElevation)
+	}
+}
+
+func degToRad(deg float64) float64 { return deg * math.Pi / 180 }
+
+func radToDeg(rad float64) float64 { return rad * 180 / math.Pi }
+
+func normalizeDegrees(deg float64) float64 {
+	deg = math.Mod(deg, 360)
+	if deg < 0 {
+		deg += 360
+	}
+	return deg
+}
@@ -0,0 +1,71 @@
+package models
+
+import (
+	"fmt"
+	"math"
+)
+
+// Unidades con las que se muestra la temperatura en la interfaz, la bandeja y la CLI
+const (
+	TemperatureUnitKelvin = "kelvin" // 4500K
+	TemperatureUnitWarmth = "warmth" // 57 % de calidez
+)
+
+/**
+ * TemperatureFormat - Forma de mostrar una temperatura al usuario
+ *
+ * La calidez es una escala de 0 a 100 % sobre el rango configurado: 0 %
+ * es la temperatura más fría (MaxTemp, sin filtro) y 100 % la más cálida
+ * (MinTemp). Es más fácil de entender que los Kelvin para quien no sabe
+ * que "menos Kelvin" significa "más naranja".
+ *
+ * @struct {TemperatureFormat}
+ * @property {string} Unit - TemperatureUnitKelvin o TemperatureUnitWarmth
+ * @property {float64} MinTemp - Temperatura que corresponde al 100 %
+ * @property {float64} MaxTemp - Temperatura que corresponde al 0 %
+ */
+type TemperatureFormat struct {
+	Unit    string
+	MinTemp float64
+	MaxTemp float64
+}
+
+/**
+ * NewTemperatureFormat - Crea el formato de temperatura para una preferencia
+ *
+ * @param {string} unit - Valor de AppConfig.GetTemperatureUnit
+ * @returns {TemperatureFormat} Formato sobre el rango por defecto de NightLightConfig
+ * @example
+ *   format := NewTemperatureFormat(config.GetTemperatureUnit())
+ *   format.Format(4500) // "57 %" o "4500K"
+ */
+func NewTemperatureFormat(unit string) TemperatureFormat {
+	limits := NewNightLightConfig()
+	return TemperatureFormat{Unit: unit, MinTemp: limits.MinTemp, MaxTemp: limits.MaxTemp}
+}
+
+// Warmth convierte una temperatura en porcentaje de calidez (0-100)
+func (format TemperatureFormat) Warmth(temp float64) float64 {
+	span := format.MaxTemp - format.MinTemp
+	if span <= 0 {
+		return 0
+	}
+	warmth := (format.MaxTemp - temp) / span * 100
+	return math.Max(0, math.Min(100, warmth))
+}
+
+// Format devuelve la temperatura en la unidad preferida ("4500K" o "57 %")
+func (format TemperatureFormat) Format(temp float64) string {
+	if format.Unit == TemperatureUnitWarmth {
+		return fmt.Sprintf("%.0f %%", format.Warmth(temp))
+	}
+	return fmt.Sprintf("%.0fK", temp)
+}
+
+// Name devuelve cómo se llama la magnitud en la unidad preferida ("Temperatura" o "Calidez")
+func (format TemperatureFormat) Name() string {
+	if format.Unit == TemperatureUnitWarmth {
+		return "Calidez"
+	}
+	return "Temperatura"
+}
@@ -0,0 +1,80 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// VacationDateLayout es el formato de las fechas del modo vacaciones
+const VacationDateLayout = "2006-01-02"
+
+/**
+ * VacationConfig - Pausa de la programación y del vigilante entre dos fechas
+ *
+ * Pensado para viajes (por ejemplo cruzando zonas horarias): entre From y
+ * To, ambos incluidos y en la hora local, el programador no aplica nada y
+ * el vigilante no reaplica la gamma. Al día siguiente de To todo vuelve
+ * a funcionar solo.
+ *
+ * @struct {VacationConfig}
+ * @example
+ *   VacationConfig{From: "2026-12-20", To: "2027-01-06"}
+ */
+type VacationConfig struct {
+	From string `json:"from"` // Primer día en pausa (AAAA-MM-DD)
+	To   string `json:"to"`   // Último día en pausa (AAAA-MM-DD), incluido
+}
+
+// IsSet indica si hay un período de vacaciones configurado
+func (vacation VacationConfig) IsSet() bool {
+	return vacation.From != "" && vacation.To != ""
+}
+
+/**
+ * Range - Devuelve el intervalo de vacaciones en la zona horaria de now
+ *
+ * @param {time.Time} now - Instante de referencia (aporta la zona horaria)
+ * @returns {time.Time, time.Time, bool} Inicio del primer día, inicio del día siguiente al último y si hay período válido
+ */
+func (vacation VacationConfig) Range(now time.Time) (start, end time.Time, ok bool) {
+	if !vacation.IsSet() {
+		return time.Time{}, time.Time{}, false
+	}
+	start, err := time.ParseInLocation(VacationDateLayout, vacation.From, now.Location())
+	if err != nil {
+		return time.Time{}, time.Time{}, false
+	}
+	last, err := time.ParseInLocation(VacationDateLayout, vacation.To, now.Location())
+	if err != nil {
+		return time.Time{}, time.Time{}, false
+	}
+	return start, last.AddDate(0, 0, 1), true
+}
+
+// Active indica si now cae dentro del período de vacaciones; también devuelve cuándo termina
+func (vacation VacationConfig) Active(now time.Time) (time.Time, bool) {
+	start, end, ok := vacation.Range(now)
+	if !ok || now.Before(start) || !now.Before(end) {
+		return time.Time{}, false
+	}
+	return end, true
+}
+
+// Validate verifica que las fechas sean válidas y estén en orden (vacío = sin vacaciones)
+func (vacation VacationConfig) Validate() error {
+	if vacation.From == "" && vacation.To == "" {
+		return nil
+	}
+	from, err := time.Parse(VacationDateLayout, vacation.From)
+	if err != nil {
+		return fmt.Errorf("from: fecha inválida %q (formato AAAA-MM-DD)", vacation.From)
+	}
+	to, err := time.Parse(VacationDateLayout, vacation.To)
+	if err != nil {
+		return fmt.Errorf("to: fecha inválida %q (formato AAAA-MM-DD)", vacation.To)
+	}
+	if to.Before(from) {
+		return fmt.Errorf("to: el último día (%s) es anterior al primero (%s)", vacation.To, vacation.From)
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Curvas de la rampa del despertador
+const (
+	EasingLinear    = "linear"      // Cambio uniforme
+	EasingEaseIn    = "ease-in"     // Empieza muy despacio, como el cielo antes del alba
+	EasingEaseInOut = "ease-in-out" // Suave al principio y al final (curva seno)
+)
+
+// Valores por defecto del despertador
+const (
+	DefaultWakeUpDuration   = 30              // Minutos de rampa
+	MaxWakeUpDuration       = 180             // Rampa máxima en minutos
+	DefaultWakeUpTemp       = CandleLightTemp // Temperatura inicial
+	DefaultWakeUpBrightness = 0.3             // Brillo inicial
+	MinWakeUpBrightness     = 0.1             // Brillo inicial mínimo admitido
+)
+
+/**
+ * WakeUpConfig - Simulación de amanecer para despertar
+ *
+ * Desde Time, durante Duration minutos, la programación pasa de una luz
+ * muy cálida y tenue (StartTemp, StartBrightness) a la temperatura
+ * diurna con brillo completo siguiendo la curva Easing. Es un período
+ * especial del programador: al terminar la rampa se mantiene la luz
+ * diurna hasta que el horario normal llega a ella por sí mismo.
+ *
+ * @struct {WakeUpConfig}
+ * @example
+ *   WakeUpConfig{Enabled: true, Time: "06:30", Duration: 30, Weekdays: []int{1, 2, 3, 4, 5}}
+ */
+type WakeUpConfig struct {
+	Enabled         bool    `json:"enabled"`
+	Time            string  `json:"time"`             // Inicio de la rampa ("HH:MM")
+	Duration        int     `json:"duration"`         // Minutos hasta la luz diurna (0 = DefaultWakeUpDuration)
+	StartTemp       float64 `json:"start_temp"`       // Temperatura inicial (0 = DefaultWakeUpTemp)
+	StartBrightness float64 `json:"start_brightness"` // Brillo inicial (0 = DefaultWakeUpBrightness)
+	Easing          string  `json:"easing"`           // "linear", "ease-in" o "ease-in-out" (por defecto)
+	Weekdays        []int   `json:"weekdays"`         // Días activos, 0 = domingo (vacío = todos)
+}
+
+// GetDuration devuelve la duración de la rampa; 0 o valores fuera de rango usan DefaultWakeUpDuration
+func (wake WakeUpConfig) GetDuration() time.Duration {
+	minutes := wake.Duration
+	if minutes <= 0 || minutes > MaxWakeUpDuration {
+		minutes = DefaultWakeUpDuration
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// GetStartTemp devuelve la temperatura inicial; 0 usa DefaultWakeUpTemp
+func (wake WakeUpConfig) GetStartTemp() float64 {
+	if wake.StartTemp <= 0 {
+		return DefaultWakeUpTemp
+	}
+	return wake.StartTemp
+}
+
+// GetStartBrightness devuelve el brillo inicial; 0 o valores fuera de rango usan DefaultWakeUpBrightness
+func (wake WakeUpConfig) GetStartBrightness() float64 {
+	if wake.StartBrightness < MinWakeUpBrightness || wake.StartBrightness > 1 {
+		return DefaultWakeUpBrightness
+	}
+	return wake.StartBrightness
+}
+
+/**
+ * Progress - Progreso de la rampa en un instante, ya con la curva aplicada
+ *
+ * @param {time.Time} now - Instante a evaluar (hora local)
+ * @returns {float64, bool} Progreso 0-1 y true si now cae dentro de la rampa de hoy
+ */
+func (wake WakeUpConfig) Progress(now time.Time) (float64, bool) {
+	start, ok := wake.startOn(now)
+	if !ok || now.Before(start) {
+		return 0, false
+	}
+	elapsed := now.Sub(start)
+	if elapsed >= wake.GetDuration() {
+		return 0, false
+	}
+	return wake.ease(float64(elapsed) / float64(wake.GetDuration())), true
+}
+
+// EndedBefore devuelve cuándo terminó la rampa de hoy; false si no hay rampa hoy o aún no terminó
+func (wake WakeUpConfig) EndedBefore(now time.Time) (time.Time, bool) {
+	start, ok := wake.startOn(now)
+	if !ok {
+		return time.Time{}, false
+	}
+	end := start.Add(wake.GetDuration())
+	return end, !now.Before(end)
+}
+
+// End devuelve cuándo termina la rampa que contiene now
+func (wake WakeUpConfig) End(now time.Time) time.Time {
+	start, _ := wake.startOn(now)
+	return start.Add(wake.GetDuration())
+}
+
+// startOn devuelve el inicio de la rampa del día de now; false si el despertador no actúa ese día
+func (wake WakeUpConfig) startOn(now time.Time) (time.Time, bool) {
+	if !wake.Enabled || !wake.activeOn(now.Weekday()) {
+		return time.Time{}, false
+	}
+	hours, minutes, err := ParseScheduleTime(wake.Time)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Date(now.Year(), now.Month(), now.Day(), hours, minutes, 0, 0, now.Location()), true
+}
+
+// activeOn indica si el despertador actúa un día de la semana
+func (wake WakeUpConfig) activeOn(day time.Weekday) bool {
+	if len(wake.Weekdays) == 0 {
+		return true
+	}
+	for _, weekday := range wake.Weekdays {
+		if time.Weekday(weekday) == day {
+			return true
+		}
+	}
+	return false
+}
+
+// ease aplica la curva elegida a un progreso lineal 0-1
+func (wake WakeUpConfig) ease(progress float64) float64 {
+	switch wake.Easing {
+	case EasingLinear:
+		return progress
+	case EasingEaseIn:
+		return progress * progress * progress
+	default:
+		return (1 - math.Cos(math.Pi*progress)) / 2
+	}
+}
+
+// Validate verifica la configuración del despertador
+func (wake WakeUpConfig) Validate() error {
+	if wake.Enabled || wake.Time != "" {
+		if _, _, err := ParseScheduleTime(wake.Time); err != nil {
+			return fmt.Errorf("hora: %w", err)
+		}
+	}
+	if wake.Duration < 0 || wake.Duration > MaxWakeUpDuration {
+		return fmt.Errorf("duración fuera de rango: %d min (máximo %d)", wake.Duration, MaxWakeUpDuration)
+	}
+	if wake.StartTemp < 0 {
+		return fmt.Errorf("la temperatura inicial no puede ser negativa")
+	}
+	if wake.StartBrightness != 0 && (wake.StartBrightness < MinWakeUpBrightness || wake.StartBrightness > 1) {
+		return fmt.Errorf("brillo inicial fuera de rango (%.1f - 1)", MinWakeUpBrightness)
+	}
+	switch wake.Easing {
+	case "", EasingLinear, EasingEaseIn, EasingEaseInOut:
+	default:
+		return fmt.Errorf("curva desconocida: %s (opciones: linear, ease-in, ease-in-out)", wake.Easing)
+	}
+	for _, weekday := range wake.Weekdays {
+		if weekday < 0 || weekday > 6 {
+			return fmt.Errorf("día de la semana fuera de rango: %d (0 = domingo ... 6 = sábado)", weekday)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Límites del ajuste por nubosidad
+const (
+	DefaultWeatherStrength = 500.0            // Kelvin que se restan con el cielo totalmente cubierto
+	MaxWeatherStrength     = 2000.0           // Ajuste máximo admitido
+	WeatherRefreshInterval = 30 * time.Minute // Cada cuánto se consulta la nubosidad
+)
+
+/**
+ * WeatherConfig - Ajuste de la temperatura diurna según la nubosidad
+ *
+ * Con Enabled, la temperatura diurna de la programación se vuelve más
+ * cálida en proporción a la nubosidad de la ubicación configurada: con
+ * el cielo cubierto al 100% se restan Strength Kelvin. La consulta usa
+ * Open-Meteo (sin clave) u OpenWeatherMap si se indica APIKey.
+ *
+ * @struct {WeatherConfig}
+ * @example
+ *   WeatherConfig{Enabled: true, Strength: 800} // Cielo cubierto al 50%: 400K más cálida de día
+ */
+type WeatherConfig struct {
+	Enabled  bool    `json:"enabled"`
+	Strength float64 `json:"strength"` // Kelvin que se restan con el cielo totalmente cubierto (0 = DefaultWeatherStrength)
+	APIKey   string  `json:"api_key"`  // Clave de OpenWeatherMap; vacía para usar Open-Meteo
+}
+
+// GetStrength devuelve el ajuste máximo en Kelvin; 0 o valores fuera de rango usan DefaultWeatherStrength
+func (weather WeatherConfig) GetStrength() float64 {
+	if weather.Strength <= 0 || weather.Strength > MaxWeatherStrength {
+		return DefaultWeatherStrength
+	}
+	return weather.Strength
+}
+
+// DayBias devuelve los Kelvin que se restan a la temperatura diurna con una nubosidad (0-100%)
+func (weather WeatherConfig) DayBias(cloudCover float64) float64 {
+	cloudCover = math.Max(0, math.Min(100, cloudCover))
+	return weather.GetStrength() * cloudCover / 100
+}
+
+// Validate verifica los límites del ajuste por nubosidad
+func (weather WeatherConfig) Validate() error {
+	if weather.Strength < 0 || weather.Strength > MaxWeatherStrength {
+		return fmt.Errorf("strength: debe estar entre 0 y %.0fK", MaxWeatherStrength)
+	}
+	return nil
+}
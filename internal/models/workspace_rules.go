@@ -0,0 +1,61 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+/**
+ * WorkspaceRule - Temperatura especial en un espacio de trabajo de Sway o i3
+ *
+ * Al cambiar a un espacio de trabajo que coincide con Workspace se pasa
+ * (con un fundido corto) a Temperature, o se quita el filtro con
+ * Disable; al salir se vuelve a la temperatura elegida. Solo actúa
+ * cuando el filtro está activo.
+ *
+ * @struct {WorkspaceRule}
+ * @example
+ *   WorkspaceRule{Workspace: "media", Disable: true} // El espacio de vídeos siempre neutro
+ */
+type WorkspaceRule struct {
+	Workspace   string  `json:"workspace"`   // Nombre ("media"), número ("5") o nombre completo ("5:media")
+	Temperature float64 `json:"temperature"` // Temperatura en Kelvin en ese espacio de trabajo
+	Disable     bool    `json:"disable"`     // Quitar el filtro en ese espacio de trabajo
+}
+
+/**
+ * Matches - Indica si la regla corresponde a un espacio de trabajo
+ *
+ * Los nombres de i3/Sway con número ("5:media") coinciden con el nombre
+ * completo, con el número y con la parte tras los dos puntos.
+ *
+ * @param {string} name - Nombre del espacio de trabajo enfocado
+ * @returns {bool} true si coincide (sin distinguir mayúsculas)
+ */
+func (rule WorkspaceRule) Matches(name string) bool {
+	want := strings.TrimSpace(rule.Workspace)
+	number, label, numbered := strings.Cut(name, ":")
+	return strings.EqualFold(want, name) ||
+		(numbered && (strings.EqualFold(want, number) || strings.EqualFold(want, label)))
+}
+
+// Validate verifica que la regla identifique un espacio de trabajo y tenga un efecto válido
+func (rule WorkspaceRule) Validate(minTemp, maxTemp float64) error {
+	if strings.TrimSpace(rule.Workspace) == "" {
+		return fmt.Errorf("falta el espacio de trabajo (workspace)")
+	}
+	if !rule.Disable && (rule.Temperature < minTemp || rule.Temperature > maxTemp) {
+		return fmt.Errorf("%s: temperatura fuera de rango (%.0fK - %.0fK)", rule.Workspace, minTemp, maxTemp)
+	}
+	return nil
+}
+
+// FindWorkspaceRule devuelve la primera regla que coincide con el espacio de trabajo
+func FindWorkspaceRule(rules []WorkspaceRule, name string) (WorkspaceRule, bool) {
+	for _, rule := range rules {
+		if rule.Matches(name) {
+			return rule, true
+		}
+	}
+	return WorkspaceRule{}, false
+}
@@ -0,0 +1,184 @@
+package paths
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// appDir es el nombre del subdirectorio de la aplicación en cada ubicación XDG
+const appDir = "luz-nocturna"
+
+var (
+	portableOnce sync.Once
+	portableDir  string // Directorio del ejecutable en modo portátil ("" fuera de él)
+	demoDir      string // Directorio temporal del modo demostración ("" fuera de él)
+)
+
+/**
+ * EnableDemo - Activa el modo demostración (--demo)
+ *
+ * Configuración, estado, bloqueos y sockets pasan a un directorio
+ * temporal nuevo, de modo que la demostración arranca con los valores
+ * por defecto, no modifica los archivos del usuario y puede convivir con
+ * una instancia normal. Debe llamarse antes de leer la configuración.
+ *
+ * @returns {string, error} Directorio temporal (a borrar al salir) o error al crearlo
+ */
+func EnableDemo() (string, error) {
+	dir, err := os.MkdirTemp("", appDir+"-demo-")
+	if err != nil {
+		return "", fmt.Errorf("no se pudo crear el directorio de la demostración: %w", err)
+	}
+	demoDir = dir
+	return dir, nil
+}
+
+// IsDemo indica si la aplicación funciona en modo demostración
+func IsDemo() bool {
+	return demoDir != ""
+}
+
+/**
+ * EnablePortable - Activa el modo portátil (--portable)
+ *
+ * La configuración y el estado pasan a guardarse junto al ejecutable,
+ * para llevar la aplicación en una memoria USB entre equipos. Debe
+ * llamarse antes de leer la configuración.
+ *
+ * @returns {error} Error si no se puede determinar la ruta del ejecutable
+ */
+func EnablePortable() error {
+	dir, err := executableDir()
+	if err != nil {
+		return fmt.Errorf("no se pudo determinar la carpeta del ejecutable: %w", err)
+	}
+	portableOnce.Do(func() {}) // Sin detección posterior: el flag manda
+	portableDir = dir
+	return nil
+}
+
+/**
+ * IsPortable - Indica si la aplicación funciona en modo portátil
+ *
+ * Además de --portable, el modo se activa solo si hay un config.json
+ * junto al ejecutable (creado por un arranque anterior con --portable).
+ *
+ * @returns {bool} true si la configuración vive junto al ejecutable
+ */
+func IsPortable() bool {
+	portableOnce.Do(func() {
+		dir, err := executableDir()
+		if err != nil {
+			return
+		}
+		if _, err := os.Stat(filepath.Join(dir, "config.json")); err == nil {
+			portableDir = dir
+		}
+	})
+	return portableDir != ""
+}
+
+// executableDir devuelve la carpeta real del ejecutable (resolviendo enlaces simbólicos)
+func executableDir() (string, error) {
+	executable, err := os.Executable()
+	if err != nil {
+		return "", err
+	}
+	if resolved, err := filepath.EvalSymlinks(executable); err == nil {
+		executable = resolved
+	}
+	return filepath.Dir(executable), nil
+}
+
+/**
+ * ConfigDir - Directorio de configuración ($XDG_CONFIG_HOME/luz-nocturna)
+ *
+ * En modo portátil es la carpeta del ejecutable y en modo demostración
+ * el directorio temporal.
+ *
+ * @returns {string} Ruta absoluta (no se garantiza que exista)
+ */
+func ConfigDir() string {
+	if IsDemo() {
+		return demoDir
+	}
+	if IsPortable() {
+		return portableDir
+	}
+	if dir, err := os.UserConfigDir(); err == nil {
+		return filepath.Join(dir, appDir)
+	}
+	return filepath.Join(homeDir(), ".config", appDir)
+}
+
+/**
+ * StateDir - Directorio de estado y registros ($XDG_STATE_HOME/luz-nocturna)
+ *
+ * Por defecto ~/.local/state, según la especificación XDG Base Directory.
+ * En modo portátil es la subcarpeta "state" junto al ejecutable.
+ *
+ * @returns {string} Ruta absoluta (no se garantiza que exista)
+ */
+func StateDir() string {
+	if IsDemo() {
+		return filepath.Join(demoDir, "state")
+	}
+	if IsPortable() {
+		return filepath.Join(portableDir, "state")
+	}
+	if dir := os.Getenv("XDG_STATE_HOME"); filepath.IsAbs(dir) {
+		return filepath.Join(dir, appDir)
+	}
+	return filepath.Join(homeDir(), ".local", "state", appDir)
+}
+
+/**
+ * RuntimeDir - Directorio para bloqueos y sockets ($XDG_RUNTIME_DIR/luz-nocturna)
+ *
+ * Sin XDG_RUNTIME_DIR se usa un directorio por usuario dentro de
+ * os.TempDir(), para no compartir archivos entre usuarios. No cambia en
+ * modo portátil: los bloqueos y sockets son de cada equipo y muchas
+ * memorias USB (FAT) no los admiten.
+ *
+ * @returns {string} Ruta absoluta (no se garantiza que exista)
+ */
+func RuntimeDir() string {
+	if IsDemo() {
+		return filepath.Join(demoDir, "run")
+	}
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); filepath.IsAbs(dir) {
+		return filepath.Join(dir, appDir)
+	}
+	return filepath.Join(os.TempDir(), fmt.Sprintf("%s-%d", appDir, os.Getuid()))
+}
+
+/**
+ * DataHome - Directorio base de datos del usuario ($XDG_DATA_HOME, ~/.local/share)
+ *
+ * Es la raíz compartida con otras aplicaciones (extensiones de GNOME
+ * Shell, applets de Plasma...), no un subdirectorio de la aplicación, y
+ * no cambia en modo portátil ni en la demostración.
+ *
+ * @returns {string} Ruta absoluta (no se garantiza que exista)
+ */
+func DataHome() string {
+	if dir := os.Getenv("XDG_DATA_HOME"); filepath.IsAbs(dir) {
+		return dir
+	}
+	return filepath.Join(homeDir(), ".local", "share")
+}
+
+// LegacyConfigDir es la ruta fija ~/.config/luz-nocturna usada por versiones anteriores
+func LegacyConfigDir() string {
+	return filepath.Join(homeDir(), ".config", appDir)
+}
+
+// homeDir devuelve el directorio personal o "." si no se puede determinar
+func homeDir() string {
+	if dir, err := os.UserHomeDir(); err == nil {
+		return dir
+	}
+	return "."
+}
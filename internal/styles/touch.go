@@ -0,0 +1,47 @@
+package styles
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/theme"
+)
+
+// TouchScale es el factor de aumento de los controles en el modo táctil
+const TouchScale = 1.4
+
+/**
+ * touchTheme - Tema con controles grandes para pantallas táctiles
+ *
+ * Envuelve el tema por defecto y aumenta el espaciado, el relleno interno
+ * de los widgets (alto de botones y del tirador de los sliders) y el
+ * texto, para que cada control sea fácil de pulsar con el dedo.
+ *
+ * @struct {touchTheme}
+ * @property {fyne.Theme} Theme - Tema original del que se toman colores, fuentes e iconos
+ */
+type touchTheme struct {
+	fyne.Theme
+}
+
+/**
+ * NewTouchTheme - Crea el tema táctil a partir de un tema base
+ *
+ * @param {fyne.Theme} base - Tema que se amplía (normalmente theme.DefaultTheme())
+ * @returns {fyne.Theme} Tema con los tamaños escalados por TouchScale
+ * @example
+ *   app.Settings().SetTheme(styles.NewTouchTheme(app.Settings().Theme()))
+ */
+func NewTouchTheme(base fyne.Theme) fyne.Theme {
+	return &touchTheme{Theme: base}
+}
+
+// Size escala el espaciado, el relleno y el texto; los bordes y radios se mantienen
+func (t *touchTheme) Size(name fyne.ThemeSizeName) float32 {
+	size := t.Theme.Size(name)
+	switch name {
+	case theme.SizeNamePadding, theme.SizeNameInnerPadding, theme.SizeNameLineSpacing,
+		theme.SizeNameText, theme.SizeNameCaptionText, theme.SizeNameHeadingText,
+		theme.SizeNameSubHeadingText, theme.SizeNameInlineIcon, theme.SizeNameScrollBar:
+		return size * TouchScale
+	}
+	return size
+}
@@ -0,0 +1,60 @@
+package system
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Parámetros de la muestra de la webcam
+const (
+	ambientFrameWidth  = 32              // Ancho al que se reduce cada fotograma
+	ambientFrameHeight = 24              // Alto al que se reduce cada fotograma
+	ambientFrames      = 8               // Fotogramas por muestra: el cambio a exposición fija tarda alguno en notarse
+	ambientTimeout     = 8 * time.Second // Tiempo máximo de una muestra
+)
+
+// DefaultWebcamDevice es la webcam que se usa si no se configura otra
+const DefaultWebcamDevice = "/dev/video0"
+
+/**
+ * SampleWebcamBrightness - Estima la luz de la habitación con la webcam
+ *
+ * Pide permiso al portal de cámara y lee una ráfaga corta de la webcam
+ * con V4L2 desde el propio proceso, sin lanzar herramientas externas: no
+ * se guarda ni se envía ningún fotograma. Durante la muestra la
+ * exposición y la ganancia quedan fijas, para que la exposición
+ * automática no compense la oscuridad, y al terminar se restauran. Se
+ * usa el brillo medio del último fotograma; es una estimación relativa,
+ * no una medida en lux.
+ *
+ * @param {context.Context} ctx - Contexto para cancelar la muestra
+ * @param {string} device - Dispositivo V4L2 ("" para DefaultWebcamDevice)
+ * @returns {float64, error} Luz estimada entre 0 (oscuridad) y 1, o error si no hay webcam o permiso
+ * @example
+ *   level, err := SampleWebcamBrightness(ctx, "/dev/video0")
+ */
+func SampleWebcamBrightness(ctx context.Context, device string) (float64, error) {
+	if device == "" {
+		device = DefaultWebcamDevice
+	}
+	if _, err := os.Stat(device); err != nil {
+		if IsFlatpak() {
+			return 0, fmt.Errorf("no se encontró la webcam %s: dentro de Flatpak hay que darle acceso con \"flatpak override --user --device=all com.luznocturna.app\"", device)
+		}
+		return 0, fmt.Errorf("no se encontró la webcam %s", device)
+	}
+	if err := requestCameraAccess(); err != nil {
+		return 0, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, ambientTimeout)
+	defer cancel()
+
+	level, err := readWebcamLuma(ctx, device, ambientFrames)
+	if err != nil {
+		return 0, fmt.Errorf("no se pudo leer la webcam: %w", err)
+	}
+	return level, nil
+}
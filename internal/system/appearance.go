@@ -0,0 +1,125 @@
+package system
+
+import (
+	"fmt"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// Portal de escritorio donde el entorno publica la preferencia de tema
+const (
+	portalName         = "org.freedesktop.portal.Desktop"
+	portalPath         = dbus.ObjectPath("/org/freedesktop/portal/desktop")
+	portalSettings     = "org.freedesktop.portal.Settings"
+	appearanceNS       = "org.freedesktop.appearance"
+	appearanceSchemeID = "color-scheme"
+)
+
+// ColorScheme es la preferencia de tema claro u oscuro del escritorio
+type ColorScheme int
+
+const (
+	ColorSchemeUnknown ColorScheme = iota // Sin preferencia o sin portal
+	ColorSchemeDark
+	ColorSchemeLight
+)
+
+// colorSchemeFromPortal convierte el valor de color-scheme (0 sin preferencia, 1 oscuro, 2 claro)
+func colorSchemeFromPortal(value dbus.Variant) ColorScheme {
+	// Las versiones antiguas de Read devuelven el valor dentro de otra variante
+	if inner, ok := value.Value().(dbus.Variant); ok {
+		value = inner
+	}
+	switch scheme, _ := value.Value().(uint32); scheme {
+	case 1:
+		return ColorSchemeDark
+	case 2:
+		return ColorSchemeLight
+	}
+	return ColorSchemeUnknown
+}
+
+/**
+ * ReadColorScheme - Consulta la preferencia de tema del escritorio
+ *
+ * Usa org.freedesktop.appearance color-scheme del portal de escritorio
+ * (GNOME 42+, KDE Plasma 5.24+, xdg-desktop-portal-gtk/wlr con ajustes).
+ *
+ * @returns {ColorScheme} Preferencia actual; ColorSchemeUnknown si no hay portal o preferencia
+ */
+func ReadColorScheme() ColorScheme {
+	conn, err := dbus.ConnectSessionBus()
+	if err != nil {
+		return ColorSchemeUnknown
+	}
+	defer conn.Close()
+
+	portal := conn.Object(portalName, portalPath)
+	var value dbus.Variant
+	if err := portal.Call(portalSettings+".ReadOne", 0, appearanceNS, appearanceSchemeID).Store(&value); err != nil {
+		// ReadOne es de la versión 2 de la interfaz
+		if err := portal.Call(portalSettings+".Read", 0, appearanceNS, appearanceSchemeID).Store(&value); err != nil {
+			return ColorSchemeUnknown
+		}
+	}
+	return colorSchemeFromPortal(value)
+}
+
+/**
+ * ColorSchemeWatcher - Sigue los cambios de tema claro/oscuro del escritorio
+ *
+ * @struct {ColorSchemeWatcher}
+ * @property {*dbus.Conn} conn - Conexión privada al bus de sesión
+ */
+type ColorSchemeWatcher struct {
+	conn *dbus.Conn
+}
+
+/**
+ * StartColorSchemeWatcher - Avisa cuando el escritorio cambia de tema
+ *
+ * onChange se llama desde la goroutine de D-Bus con la nueva preferencia.
+ *
+ * @param {func(ColorScheme)} onChange - Callback con la nueva preferencia
+ * @returns {*ColorSchemeWatcher, error} Seguimiento activo o error si no hay bus de sesión
+ * @example
+ *   watcher, err := StartColorSchemeWatcher(func(scheme ColorScheme) { ... })
+ *   defer watcher.Stop()
+ */
+func StartColorSchemeWatcher(onChange func(ColorScheme)) (*ColorSchemeWatcher, error) {
+	conn, err := dbus.ConnectSessionBus()
+	if err != nil {
+		return nil, fmt.Errorf("no se pudo conectar al bus de sesión: %w", err)
+	}
+	if err := conn.AddMatchSignal(
+		dbus.WithMatchObjectPath(portalPath),
+		dbus.WithMatchInterface(portalSettings),
+		dbus.WithMatchMember("SettingChanged"),
+		dbus.WithMatchArg(0, appearanceNS),
+	); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	signals := make(chan *dbus.Signal, 10)
+	conn.Signal(signals)
+	go func() {
+		for signal := range signals {
+			if len(signal.Body) < 3 {
+				continue
+			}
+			if key, _ := signal.Body[1].(string); key != appearanceSchemeID {
+				continue
+			}
+			if value, ok := signal.Body[2].(dbus.Variant); ok {
+				onChange(colorSchemeFromPortal(value))
+			}
+		}
+	}()
+	return &ColorSchemeWatcher{conn: conn}, nil
+}
+
+// Stop detiene el seguimiento
+func (w *ColorSchemeWatcher) Stop() {
+	w.conn.Close() // Cierra también el canal de señales
+}
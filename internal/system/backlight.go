@@ -0,0 +1,75 @@
+package system
+
+import (
+	"fmt"
+	"luznocturna/luz-nocturna/internal/backlight"
+	"luznocturna/luz-nocturna/internal/logging"
+	"os"
+	"strconv"
+)
+
+// backlightHelperPath es el helper privilegiado que autoriza la política de polkit
+const backlightHelperPath = "/usr/local/libexec/luz-nocturna-backlight"
+
+// BacklightDevice describe un dispositivo de /sys/class/backlight
+type BacklightDevice struct {
+	Name string // Nombre del dispositivo (ej: "intel_backlight")
+	Max  int    // Valor máximo de max_brightness
+}
+
+/**
+ * ListBacklights - Enumera los dispositivos de retroiluminación
+ *
+ * @returns {[]BacklightDevice, error} Dispositivos con su brillo máximo
+ */
+func ListBacklights() ([]BacklightDevice, error) {
+	entries, err := os.ReadDir(backlight.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var devices []BacklightDevice
+	for _, entry := range entries {
+		max, err := backlight.ReadValue(entry.Name(), "max_brightness")
+		if err != nil || max <= 0 {
+			continue
+		}
+		devices = append(devices, BacklightDevice{Name: entry.Name(), Max: max})
+	}
+	return devices, nil
+}
+
+/**
+ * setBacklight - Cambia el brillo de un dispositivo sin pedir sudo
+ *
+ * Primero usa logind (org.freedesktop.login1.Session.SetBrightness), que
+ * autoriza a la sesión activa sin contraseña. Si logind no está disponible
+ * recurre al helper autorizado por polkit (backlightHelperPath vía pkexec),
+ * un binario mínimo que solo escribe en sysfs.
+ *
+ * @param {BacklightDevice} device - Dispositivo a modificar
+ * @param {int} value - Nuevo valor (0..Max)
+ * @returns {error} Error si ningún método pudo aplicarlo
+ * @private
+ */
+func (gm *GammaManager) setBacklight(device BacklightDevice, value int) error {
+	if value < 0 || value > device.Max {
+		return fmt.Errorf("brillo fuera de rango para %s: %d (máximo %d)", device.Name, value, device.Max)
+	}
+
+	if gm.dryRun {
+		logging.Printf("🧪 [dry-run] logind SetBrightness backlight %s %d\n", device.Name, value)
+		return nil
+	}
+
+	err := setBrightnessLogind(device.Name, uint32(value))
+	if err == nil {
+		return nil
+	}
+	logging.Printf("⚠️  logind no pudo cambiar el brillo (%v), usando helper de polkit\n", err)
+
+	if _, statErr := os.Stat(backlightHelperPath); statErr != nil {
+		return fmt.Errorf("logind no pudo cambiar el brillo y el helper de polkit no está instalado: %w", err)
+	}
+	return gm.runCommand("pkexec", backlightHelperPath, device.Name, strconv.Itoa(value))
+}
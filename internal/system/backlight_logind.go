@@ -0,0 +1,17 @@
+package system
+
+import (
+	"github.com/godbus/dbus/v5"
+)
+
+// setBrightnessLogind cambia el brillo con org.freedesktop.login1.Session.SetBrightness
+func setBrightnessLogind(name string, value uint32) error {
+	conn, err := dbus.ConnectSystemBus()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	session := conn.Object("org.freedesktop.login1", "/org/freedesktop/login1/session/auto")
+	return session.Call("org.freedesktop.login1.Session.SetBrightness", 0, "backlight", name, value).Err
+}
@@ -0,0 +1,92 @@
+package system
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// Interfaz del portal de cámara (org.freedesktop.portal.Camera)
+const (
+	cameraPortal        = "org.freedesktop.portal.Camera"
+	portalRequest       = "org.freedesktop.portal.Request"
+	cameraAccessTimeout = 2 * time.Minute // El usuario puede tardar en responder al diálogo
+)
+
+// errCameraDenied indica que el usuario o la configuración de privacidad no permiten usar la cámara
+var errCameraDenied = errors.New("el acceso a la cámara está denegado en la configuración de privacidad")
+
+var (
+	cameraAccessMu      sync.Mutex
+	cameraAccessGranted bool
+)
+
+/**
+ * requestCameraAccess - Pide permiso para usar la cámara al portal de escritorio
+ *
+ * Así la webcam respeta el interruptor de privacidad del escritorio
+ * (GNOME, KDE) y su indicador de cámara en uso: la primera vez el portal
+ * pregunta al usuario y después recuerda la respuesta. Sin portal de
+ * cámara (escritorios que no lo implementan) se sigue sin preguntar,
+ * igual que cualquier aplicación con acceso a /dev/video*.
+ *
+ * @returns {error} errCameraDenied si el permiso se denegó
+ * @private
+ */
+func requestCameraAccess() error {
+	cameraAccessMu.Lock()
+	defer cameraAccessMu.Unlock()
+	if cameraAccessGranted {
+		return nil
+	}
+
+	conn, err := dbus.ConnectSessionBus()
+	if err != nil {
+		return nil // Sin bus de sesión no hay portal que consultar
+	}
+	defer conn.Close()
+
+	portal := conn.Object(portalName, portalPath)
+	if present, err := portal.GetProperty(cameraPortal + ".IsCameraPresent"); err != nil {
+		return nil // El portal no implementa la cámara
+	} else if ok, _ := present.Value().(bool); !ok {
+		return fmt.Errorf("el portal de escritorio no encuentra ninguna cámara")
+	}
+
+	// La respuesta llega como señal en el objeto Request, cuya ruta se deduce del token
+	token := fmt.Sprintf("luznocturna%d", time.Now().UnixNano())
+	sender := strings.ReplaceAll(strings.TrimPrefix(conn.Names()[0], ":"), ".", "_")
+	request := dbus.ObjectPath(fmt.Sprintf("/org/freedesktop/portal/desktop/request/%s/%s", sender, token))
+	if err := conn.AddMatchSignal(
+		dbus.WithMatchObjectPath(request),
+		dbus.WithMatchInterface(portalRequest),
+		dbus.WithMatchMember("Response"),
+	); err != nil {
+		return err
+	}
+	signals := make(chan *dbus.Signal, 1)
+	conn.Signal(signals)
+
+	options := map[string]dbus.Variant{"handle_token": dbus.MakeVariant(token)}
+	if call := portal.Call(cameraPortal+".AccessCamera", 0, options); call.Err != nil {
+		return fmt.Errorf("el portal de cámara no respondió: %w", call.Err)
+	}
+
+	select {
+	case signal, ok := <-signals:
+		if !ok || len(signal.Body) == 0 {
+			return fmt.Errorf("se cerró la conexión con el portal de cámara")
+		}
+		if response, _ := signal.Body[0].(uint32); response != 0 {
+			return errCameraDenied
+		}
+	case <-time.After(cameraAccessTimeout):
+		return fmt.Errorf("el portal de cámara no respondió a tiempo")
+	}
+	cameraAccessGranted = true
+	return nil
+}
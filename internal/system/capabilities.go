@@ -0,0 +1,140 @@
+package system
+
+import (
+	"luznocturna/luz-nocturna/internal/logging"
+	"regexp"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// knownTools son las herramientas externas que consulta la cadena de backends
+var knownTools = []string{
+	"xrandr", "gsettings", "gdbus", "dbus-send", "ddcutil",
+	"wlr-gamma-control", "wl-gamma-relay", "swaybg", "xsetroot",
+	"gammastep", "wlsunset", "pkexec", "kscreen-doctor",
+}
+
+// ddcBusRegex extrae el número de bus I2C de la salida de "ddcutil detect --brief"
+var ddcBusRegex = regexp.MustCompile(`I2C bus:\s+/dev/i2c-(\d+)`)
+
+/**
+ * Capabilities - Caché de herramientas y capacidades de los backends
+ *
+ * Evita lanzar LookPath y volver a sondear los buses
+ * DDC/CI en cada aplicación. Se rellena una vez al iniciar y puede
+ * refrescarse cuando el usuario instala herramientas o conecta monitores.
+ *
+ * @struct {Capabilities}
+ * @property {map[string]bool} tools - Disponibilidad de cada herramienta consultada
+ * @property {[]int} ddcBuses - Buses I2C con monitores DDC/CI
+ * @property {bool} ddcProbed - Si ya se ejecutó "ddcutil detect"
+ */
+type Capabilities struct {
+	mu        sync.Mutex
+	tools     map[string]bool
+	ddcBuses  []int
+	ddcProbed bool
+}
+
+// newCapabilities crea la caché y sondea las herramientas conocidas
+func newCapabilities() *Capabilities {
+	caps := &Capabilities{}
+	caps.Refresh()
+	return caps
+}
+
+/**
+ * Refresh - Vuelve a detectar herramientas y monitores DDC/CI
+ *
+ * Los buses DDC se sondean de forma diferida en el primer uso, porque
+ * "ddcutil detect" puede tardar varios segundos.
+ */
+func (c *Capabilities) Refresh() {
+	tools := make(map[string]bool, len(knownTools))
+	for _, tool := range knownTools {
+		tools[tool] = lookupTool(tool) == nil
+	}
+
+	c.mu.Lock()
+	c.tools = tools
+	c.ddcBuses = nil
+	c.ddcProbed = false
+	c.mu.Unlock()
+}
+
+/**
+ * HasTool - Indica si una herramienta está instalada (con caché)
+ *
+ * @param {string} tool - Nombre de la herramienta
+ * @returns {bool} true si está disponible
+ */
+func (c *Capabilities) HasTool(tool string) bool {
+	c.mu.Lock()
+	available, cached := c.tools[tool]
+	c.mu.Unlock()
+	if cached {
+		return available
+	}
+
+	available = lookupTool(tool) == nil
+	c.mu.Lock()
+	c.tools[tool] = available
+	c.mu.Unlock()
+	return available
+}
+
+/**
+ * DDCBuses - Buses I2C con monitores que responden a DDC/CI
+ *
+ * El primer uso ejecuta "ddcutil detect --brief"; después se usa la caché
+ * para llamar a ddcutil con --bus y evitar que vuelva a sondear todo.
+ *
+ * @returns {[]int} Números de bus (vacío si no hay monitores DDC/CI)
+ */
+func (c *Capabilities) DDCBuses() []int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.ddcProbed {
+		return c.ddcBuses
+	}
+	c.ddcProbed = true
+
+	if !c.tools["ddcutil"] {
+		return nil
+	}
+
+	output, err := toolCommand("ddcutil", "detect", "--brief").Output()
+	if err != nil {
+		logging.Printf("⚠️  ddcutil detect falló: %v\n", err)
+		return nil
+	}
+
+	for _, match := range ddcBusRegex.FindAllStringSubmatch(string(output), -1) {
+		if bus, err := strconv.Atoi(match[1]); err == nil {
+			c.ddcBuses = append(c.ddcBuses, bus)
+		}
+	}
+	logging.Printf("🔌 Monitores DDC/CI detectados en buses: %v\n", c.ddcBuses)
+	return c.ddcBuses
+}
+
+/**
+ * Tools - Copia ordenada de las herramientas detectadas
+ *
+ * @returns {[]string, map[string]bool} Nombres ordenados y su disponibilidad
+ */
+func (c *Capabilities) Tools() ([]string, map[string]bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	names := make([]string, 0, len(c.tools))
+	tools := make(map[string]bool, len(c.tools))
+	for name, available := range c.tools {
+		names = append(names, name)
+		tools[name] = available
+	}
+	sort.Strings(names)
+	return names, tools
+}
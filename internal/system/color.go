@@ -0,0 +1,281 @@
+package system
+
+import "math"
+
+// Rango y resolución de la tabla temperatura → RGB
+const (
+	rgbTableMinTemp = 1000.0
+	rgbTableMaxTemp = 40000.0
+	rgbTableStep    = 50.0
+)
+
+// rgbEntry son los factores gamma de una temperatura de la tabla
+type rgbEntry struct {
+	r, g, b float64
+}
+
+// rgbTable contiene computeTemperatureRGB precalculado cada rgbTableStep Kelvin
+var rgbTable = buildRGBTable()
+
+// buildRGBTable evalúa la fórmula una sola vez por paso de la tabla
+func buildRGBTable() []rgbEntry {
+	size := int((rgbTableMaxTemp-rgbTableMinTemp)/rgbTableStep) + 1
+	table := make([]rgbEntry, size)
+	for i := range table {
+		r, g, b := computeTemperatureRGB(rgbTableMinTemp + float64(i)*rgbTableStep)
+		table[i] = rgbEntry{r, g, b}
+	}
+	return table
+}
+
+/**
+ * TemperatureToRGB - Factores gamma RGB para una temperatura de color
+ *
+ * Interpola linealmente en una tabla precalculada (pasos de 50K), de modo
+ * que las animaciones de fundido y las rampas por fotograma no evalúan
+ * logaritmos ni potencias en cada llamada. Las temperaturas fuera de
+ * 1000-40000K se recortan al extremo más cercano.
+ *
+ * @param {float64} temp - Temperatura en Kelvin
+ * @returns {float64, float64, float64} Componentes RGB normalizados (0.3-1.0)
+ * @example
+ *   r, g, b := system.TemperatureToRGB(4000) // Temperatura cálida
+ *   // r ≈ 1.0, g ≈ 0.8, b ≈ 0.6
+ */
+func TemperatureToRGB(temp float64) (r, g, b float64) {
+	if math.IsNaN(temp) {
+		temp = rgbTableMaxTemp
+	}
+	temp = math.Max(rgbTableMinTemp, math.Min(rgbTableMaxTemp, temp))
+
+	position := (temp - rgbTableMinTemp) / rgbTableStep
+	index := int(position)
+	if index >= len(rgbTable)-1 {
+		last := rgbTable[len(rgbTable)-1]
+		return last.r, last.g, last.b
+	}
+
+	fraction := position - float64(index)
+	low, high := rgbTable[index], rgbTable[index+1]
+	return low.r + (high.r-low.r)*fraction,
+		low.g + (high.g-low.g)*fraction,
+		low.b + (high.b-low.b)*fraction
+}
+
+/**
+ * RGBToTemperature - Temperatura de color correlacionada (CCT) de unos factores gamma RGB
+ *
+ * Interpreta los factores como el punto blanco resultante en sRGB:
+ * normaliza el brillo, linealiza con la curva sRGB, convierte a XYZ y a
+ * cromaticidad xy, y aplica la aproximación de McCamy. Así los backends
+ * que solo aceptan Kelvin (GNOME, KDE, gammastep...) reciben un valor
+ * continuo en lugar de uno de unos pocos valores fijos.
+ *
+ * @param {float64} r - Componente rojo (0-1)
+ * @param {float64} g - Componente verde (0-1)
+ * @param {float64} b - Componente azul (0-1)
+ * @returns {float64} Temperatura estimada en Kelvin (1000-25000)
+ * @example
+ *   temp := system.RGBToTemperature(1.0, 0.81, 0.65) // ≈ 4000K
+ */
+func RGBToTemperature(r, g, b float64) float64 {
+	// El brillo escala los tres canales por igual y no cambia el color
+	peak := math.Max(r, math.Max(g, b))
+	if peak <= 0 || math.IsNaN(peak) {
+		return 6500
+	}
+	r, g, b = srgbToLinear(r/peak), srgbToLinear(g/peak), srgbToLinear(b/peak)
+
+	// sRGB lineal (D65) → XYZ
+	x := 0.4124564*r + 0.3575761*g + 0.1804375*b
+	y := 0.2126729*r + 0.7151522*g + 0.0721750*b
+	z := 0.0193339*r + 0.1191920*g + 0.9503041*b
+
+	sum := x + y + z
+	if sum <= 0 {
+		return 6500
+	}
+	cx, cy := x/sum, y/sum
+
+	// Aproximación de McCamy (1992)
+	n := (cx - 0.3320) / (0.1858 - cy)
+	cct := 449*n*n*n + 3525*n*n + 6823.3*n + 5520.33
+
+	return math.Max(1000, math.Min(25000, cct))
+}
+
+/**
+ * TemperatureForRGB - Inversa exacta de TemperatureToRGB
+ *
+ * Busca en rgbTable el tramo más cercano a los factores (normalizados
+ * por el canal mayor, porque el brillo escala los tres por igual) y
+ * proyecta sobre él. A diferencia de RGBToTemperature, que estima la
+ * CCT colorimétrica, devuelve la temperatura de nuestra propia curva:
+ * TemperatureToRGB(TemperatureForRGB(r, g, b)) ≈ (r, g, b).
+ *
+ * @param {float64} r - Componente rojo (0-1)
+ * @param {float64} g - Componente verde (0-1)
+ * @param {float64} b - Componente azul (0-1)
+ * @returns {float64} Temperatura en Kelvin (1000-40000)
+ * @example
+ *   temp := system.TemperatureForRGB(system.TemperatureToRGB(4000)) // 4000
+ */
+func TemperatureForRGB(r, g, b float64) float64 {
+	peak := math.Max(r, math.Max(g, b))
+	if peak <= 0 || math.IsNaN(peak) {
+		return 6500
+	}
+	point := rgbEntry{r / peak, g / peak, b / peak}
+
+	best, bestDistance := 0.0, math.Inf(1)
+	for i := 0; i < len(rgbTable)-1; i++ {
+		fraction, distance := projectOnSegment(point, rgbTable[i], rgbTable[i+1])
+		if distance < bestDistance {
+			best, bestDistance = float64(i)+fraction, distance
+		}
+	}
+	return rgbTableMinTemp + best*rgbTableStep
+}
+
+// projectOnSegment proyecta p sobre el tramo a→b: posición (0-1) y distancia al cuadrado
+func projectOnSegment(p, a, b rgbEntry) (fraction, distance float64) {
+	dr, dg, db := b.r-a.r, b.g-a.g, b.b-a.b
+	if length := dr*dr + dg*dg + db*db; length > 0 {
+		fraction = ((p.r-a.r)*dr + (p.g-a.g)*dg + (p.b-a.b)*db) / length
+		fraction = math.Max(0, math.Min(1, fraction))
+	}
+	er := a.r + dr*fraction - p.r
+	eg := a.g + dg*fraction - p.g
+	eb := a.b + db*fraction - p.b
+	return fraction, er*er + eg*eg + eb*eb
+}
+
+// srgbToLinear aplica la función de transferencia inversa de sRGB a un canal 0-1
+func srgbToLinear(c float64) float64 {
+	if c <= 0.04045 {
+		return c / 12.92
+	}
+	return math.Pow((c+0.055)/1.055, 2.4)
+}
+
+/**
+ * computeTemperatureRGB - Convierte temperatura Kelvin a valores RGB gamma
+ *
+ * Implementa el algoritmo de Tanner Helland para conversión de temperatura
+ * de color a valores RGB, optimizado para control de gamma en pantallas.
+ * Solo se usa para construir rgbTable; en tiempo de ejecución se llama a
+ * TemperatureToRGB.
+ *
+ * @param {float64} temp - Temperatura en Kelvin (1000-40000, típicamente 3000-6500)
+ * @returns {float64, float64, float64} Componentes RGB normalizados (0.3-1.0)
+ * @private
+ */
+func computeTemperatureRGB(temp float64) (r, g, b float64) {
+	// Algoritmo de Tanner Helland optimizado para control de gamma
+	// Basado en datos empíricos de temperatura de color de cuerpo negro
+
+	// Normalizar temperatura (dividir por 100 para cálculos)
+	temp = temp / 100
+
+	// === CALCULAR COMPONENTE ROJO ===
+	if temp <= 66 {
+		// Para temperaturas <= 6600K, el rojo está al máximo
+		r = 1.0
+	} else {
+		// Para temperaturas > 6600K, calcular curva de enfriamiento
+		r = temp - 60
+		r = 329.698727446 * math.Pow(r, -0.1332047592)
+		if r < 0 {
+			r = 0
+		}
+		if r > 255 {
+			r = 255
+		}
+		r = r / 255 // Normalizar a 0-1
+	}
+
+	// === CALCULAR COMPONENTE VERDE ===
+	if temp <= 66 {
+		// Curva de calentamiento para verde
+		g = temp
+		g = 99.4708025861*math.Log(g) - 161.1195681661
+		if g < 0 {
+			g = 0
+		}
+		if g > 255 {
+			g = 255
+		}
+		g = g / 255 // Normalizar a 0-1
+	} else {
+		// Curva de enfriamiento para verde
+		g = temp - 60
+		g = 288.1221695283 * math.Pow(g, -0.0755148492)
+		if g < 0 {
+			g = 0
+		}
+		if g > 255 {
+			g = 255
+		}
+		g = g / 255 // Normalizar a 0-1
+	}
+
+	// === CALCULAR COMPONENTE AZUL ===
+	if temp >= 66 {
+		// Para temperaturas >= 6600K, el azul está al máximo
+		b = 1.0
+	} else if temp <= 19 {
+		// Para temperaturas muy bajas, no hay azul
+		b = 0
+	} else {
+		// Curva de calentamiento para azul
+		b = temp - 10
+		b = 138.5177312231*math.Log(b) - 305.0447927307
+		if b < 0 {
+			b = 0
+		}
+		if b > 255 {
+			b = 255
+		}
+		b = b / 255 // Normalizar a 0-1
+	}
+
+	// === APLICAR LÍMITES MÍNIMOS PARA GAMMA ===
+	// Evitar valores demasiado extremos que puedan dañar la vista
+	// o hacer la pantalla ilegible
+	const minGamma = 0.3
+	if r < minGamma {
+		r = minGamma
+	}
+	if g < minGamma {
+		g = minGamma
+	}
+	if b < minGamma {
+		b = minGamma
+	}
+
+	return r, g, b
+}
+
+/**
+ * BlueLightReduction - Porcentaje aproximado de luz azul retirada
+ *
+ * Sale de la atenuación del canal azul (el factor de TemperatureToRGB,
+ * relativo al de 6500K, la gamma normal) junto con la atenuación del
+ * brillo; el boost por encima de 1.0 no cuenta como más luz azul. Es una cifra orientativa, más fácil de
+ * relacionar que los Kelvin: no mide la emisión real del panel.
+ *
+ * @param {float64} temperature - Temperatura en Kelvin
+ * @param {float64} brightness - Brillo relativo (0 o 1.0 = sin atenuar)
+ * @returns {float64} Porcentaje de 0 (6500K a pleno brillo) a 100
+ * @example
+ *   system.BlueLightReduction(3400, 1.0) // ≈ 46
+ */
+func BlueLightReduction(temperature, brightness float64) float64 {
+	_, _, b := TemperatureToRGB(temperature)
+	_, _, neutral := TemperatureToRGB(6500)
+	b /= neutral
+	if brightness > 0 && brightness < 1.0 {
+		b *= brightness
+	}
+	return math.Max(0, math.Min(1-b, 1)) * 100
+}
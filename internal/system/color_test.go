@@ -0,0 +1,188 @@
+package system
+
+import (
+	"math"
+	"testing"
+)
+
+// Rango de temperaturas que cubren las pruebas de la conversión de color
+const (
+	colorTestMinTemp = 1000.0
+	colorTestMaxTemp = 10000.0
+)
+
+// redBluePivot es donde la fórmula cambia de curva: el rojo empieza a bajar y el azul llega al máximo
+const redBluePivot = 6600.0
+
+// colorTolerance absorbe el error de redondeo de la interpolación en la tabla
+const colorTolerance = 1e-9
+
+// isFlat indica si la curva no cambia alrededor de temp (canales recortados):
+// ahí la temperatura no puede recuperarse a partir del color
+func isFlat(temp float64) bool {
+	r, g, b := TemperatureToRGB(temp)
+	r1, g1, b1 := TemperatureToRGB(temp + 1)
+	return r == r1 && g == g1 && b == b1
+}
+
+// checkMonotonic comprueba la forma de cada canal entre dos temperaturas consecutivas
+func checkMonotonic(t *testing.T, low, high float64) {
+	t.Helper()
+	r0, g0, b0 := TemperatureToRGB(low)
+	r1, g1, b1 := TemperatureToRGB(high)
+
+	// El rojo nunca sube al enfriar
+	if r1 > r0+colorTolerance {
+		t.Errorf("rojo sube de %.0fK (%.6f) a %.0fK (%.6f)", low, r0, high, r1)
+	}
+	// El azul nunca baja al enfriar
+	if b1 < b0-colorTolerance {
+		t.Errorf("azul baja de %.0fK (%.6f) a %.0fK (%.6f)", low, b0, high, b1)
+	}
+	// El verde sube hasta el pivote y baja después
+	if high <= redBluePivot && g1 < g0-colorTolerance {
+		t.Errorf("verde baja de %.0fK (%.6f) a %.0fK (%.6f)", low, g0, high, g1)
+	}
+	if low >= redBluePivot && g1 > g0+colorTolerance {
+		t.Errorf("verde sube de %.0fK (%.6f) a %.0fK (%.6f)", low, g0, high, g1)
+	}
+}
+
+// checkRoundTrip comprueba que TemperatureForRGB recupera la temperatura (y el color)
+func checkRoundTrip(t *testing.T, temp, brightness float64) {
+	t.Helper()
+	r, g, b := TemperatureToRGB(temp)
+	recovered := TemperatureForRGB(r*brightness, g*brightness, b*brightness)
+
+	rr, rg, rb := TemperatureToRGB(recovered)
+	if math.Abs(rr-r) > 1e-6 || math.Abs(rg-g) > 1e-6 || math.Abs(rb-b) > 1e-6 {
+		t.Errorf("%.1fK (brillo %.2f) → %.1fK: color %.6f:%.6f:%.6f, se esperaba %.6f:%.6f:%.6f",
+			temp, brightness, recovered, rr, rg, rb, r, g, b)
+	}
+	if !isFlat(temp) && !isFlat(temp-1) && math.Abs(recovered-temp) > 0.5 {
+		t.Errorf("%.1fK (brillo %.2f) → %.1fK", temp, brightness, recovered)
+	}
+}
+
+func TestTemperatureToRGBChannelsAreMonotonic(t *testing.T) {
+	for temp := colorTestMinTemp; temp < colorTestMaxTemp; temp++ {
+		checkMonotonic(t, temp, temp+1)
+	}
+}
+
+func TestTemperatureToRGBVariesAbovePivot(t *testing.T) {
+	// Por encima de 6600K la luz se vuelve azulada: el rojo y el verde deben bajar
+	r, g, b := TemperatureToRGB(colorTestMaxTemp)
+	if r >= 1 || g >= 1 || b != 1 {
+		t.Errorf("%.0fK = %.3f:%.3f:%.3f, se esperaba rojo y verde por debajo de 1 y azul en 1",
+			colorTestMaxTemp, r, g, b)
+	}
+}
+
+func TestTemperatureForRGBRoundTrip(t *testing.T) {
+	for _, brightness := range []float64{1.0, 0.7, 0.3} {
+		for temp := colorTestMinTemp; temp <= colorTestMaxTemp; temp += 10 {
+			checkRoundTrip(t, temp, brightness)
+		}
+	}
+}
+
+func TestTemperatureForRGBDegenerateInput(t *testing.T) {
+	for _, rgb := range [][3]float64{{0, 0, 0}, {math.NaN(), 0.5, 0.5}} {
+		if temp := TemperatureForRGB(rgb[0], rgb[1], rgb[2]); temp != 6500 {
+			t.Errorf("TemperatureForRGB(%v) = %.0f, se esperaba 6500", rgb, temp)
+		}
+	}
+}
+
+// FuzzTemperatureRoundTrip recorre temperaturas y brillos arbitrarios dentro de 1000-10000K
+func FuzzTemperatureRoundTrip(f *testing.F) {
+	for _, seed := range []struct{ temp, delta, brightness float64 }{
+		{1000, 1, 1}, {3400, 50, 0.8}, {6500, 100, 1}, {6599.5, 1, 0.5}, {9999, 0.5, 0.1},
+	} {
+		f.Add(seed.temp, seed.delta, seed.brightness)
+	}
+
+	f.Fuzz(func(t *testing.T, temp, delta, brightness float64) {
+		if math.IsNaN(temp) || math.IsInf(temp, 0) || math.IsNaN(delta) || math.IsNaN(brightness) {
+			t.Skip()
+		}
+		span := colorTestMaxTemp - colorTestMinTemp
+		temp = colorTestMinTemp + math.Mod(math.Abs(temp), span)
+		delta = math.Mod(math.Abs(delta), 500)
+		brightness = 0.1 + math.Mod(math.Abs(brightness), 1.2)
+
+		high := math.Min(temp+delta, colorTestMaxTemp)
+		if temp < redBluePivot && high > redBluePivot {
+			high = redBluePivot // El verde cambia de sentido en el pivote
+		}
+		checkMonotonic(t, temp, high)
+		checkRoundTrip(t, temp, brightness)
+	})
+}
+
+func TestSaturationMatrix(t *testing.T) {
+	identity := saturationMatrix(1)
+	for i, value := range identity {
+		want := 0.0
+		if i%4 == 0 {
+			want = 1
+		}
+		if math.Abs(value-want) > colorTolerance {
+			t.Fatalf("saturación 1: elemento %d = %.4f, se esperaba la identidad", i, value)
+		}
+	}
+
+	// En escala de grises los tres canales salen iguales y el blanco sigue siendo blanco
+	gray := saturationMatrix(0)
+	for row := 0; row < 3; row++ {
+		sum := 0.0
+		for col := 0; col < 3; col++ {
+			sum += gray[row*3+col]
+			if math.Abs(gray[row*3+col]-gray[col]) > colorTolerance {
+				t.Errorf("escala de grises: la fila %d no coincide con la primera", row)
+			}
+		}
+		if math.Abs(sum-1) > colorTolerance {
+			t.Errorf("escala de grises: la fila %d suma %.4f, se esperaba 1", row, sum)
+		}
+	}
+
+	// S31.32: 1.0 es 1<<32 (mitad baja 0, mitad alta 1)
+	encoded := encodeCTM(identity)
+	if len(encoded) != 18 || encoded[0] != 0 || encoded[1] != 1 || encoded[2] != 0 || encoded[3] != 0 {
+		t.Errorf("encodeCTM(identidad) = %v", encoded[:4])
+	}
+}
+
+func TestTrimGamma(t *testing.T) {
+	gamma := [3]float64{1.0, 0.8, 0.6}
+	if got := trimGamma(gamma, [3]float64{}); got != gamma {
+		t.Errorf("sin ajuste: %v, se esperaba %v", got, gamma)
+	}
+
+	got := trimGamma(gamma, [3]float64{1.0, 0.9, 1.1})
+	want := [3]float64{1.0, 0.72, 0.66}
+	for i := range got {
+		if math.Abs(got[i]-want[i]) > colorTolerance {
+			t.Errorf("canal %d = %.4f, se esperaba %.4f", i, got[i], want[i])
+		}
+	}
+}
+
+func TestBlueLightReduction(t *testing.T) {
+	if got := BlueLightReduction(6500, 1.0); math.Abs(got) > colorTolerance {
+		t.Errorf("6500K: %.2f%%, se esperaba 0", got)
+	}
+	if got := BlueLightReduction(6500, 1.3); math.Abs(got) > colorTolerance {
+		t.Errorf("6500K con boost: %.2f%%, se esperaba 0", got)
+	}
+
+	warm := BlueLightReduction(3400, 1.0)
+	if warm <= BlueLightReduction(4500, 1.0) || warm <= 0 || warm >= 100 {
+		t.Errorf("3400K: %.2f%%, se esperaba más que a 4500K y entre 0 y 100", warm)
+	}
+	if dimmed := BlueLightReduction(3400, 0.5); dimmed <= warm {
+		t.Errorf("3400K al 50%%: %.2f%%, se esperaba más que a pleno brillo (%.2f%%)", dimmed, warm)
+	}
+}
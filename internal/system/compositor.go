@@ -0,0 +1,140 @@
+package system
+
+import (
+	"os"
+	"strings"
+	"sync"
+)
+
+// Compositores Wayland reconocidos (CompositorID)
+const (
+	CompositorMutter   = "mutter"
+	CompositorKWin     = "kwin"
+	CompositorSway     = "sway"
+	CompositorHyprland = "hyprland"
+	CompositorWeston   = "weston"
+	CompositorWayfire  = "wayfire"
+)
+
+// compositorNames son los nombres legibles de cada compositor reconocido
+var compositorNames = map[string]string{
+	CompositorMutter:   "GNOME (Mutter)",
+	CompositorKWin:     "KDE Plasma (KWin)",
+	CompositorSway:     "Sway",
+	CompositorHyprland: "Hyprland",
+	CompositorWeston:   "Weston",
+	CompositorWayfire:  "Wayfire",
+}
+
+// compositorBusNames son los nombres de D-Bus que delatan al compositor cuando el entorno no lo dice
+var compositorBusNames = map[string]string{
+	"org.gnome.Mutter.DisplayConfig": CompositorMutter,
+	"org.gnome.Shell":                CompositorMutter,
+	"org.kde.KWin":                   CompositorKWin,
+}
+
+// Métodos de la cadena de Wayland; también son los nombres que muestra WaylandApplyError
+const (
+	methodCompositor = "compositor override"
+	methodMutter     = "GNOME Mutter"
+	methodKWin       = "KDE KWin"
+	methodManaged    = "gammastep/wlsunset"
+	methodDDC        = "DDC/CI"
+	methodOverlay    = "overlay"
+	methodXWayland   = "XWayland"
+)
+
+/**
+ * compositorStrategies - Métodos que funcionan en cada compositor, en orden
+ *
+ * Mutter y KWin solo aceptan su propia luz nocturna (no implementan
+ * wlr-gamma-control), los compositores wlroots funcionan con gammastep o
+ * wlsunset y Weston no ofrece gamma a los clientes. DDC/CI y XWayland
+ * quedan al final porque no dependen del compositor. Un compositor sin
+ * entrada usa defaultWaylandStrategy.
+ */
+var compositorStrategies = map[string][]string{
+	CompositorMutter:   {methodMutter, methodDDC, methodXWayland},
+	CompositorKWin:     {methodKWin, methodDDC, methodXWayland},
+	CompositorSway:     {methodManaged, methodCompositor, methodDDC, methodXWayland},
+	CompositorHyprland: {methodManaged, methodCompositor, methodDDC, methodXWayland},
+	CompositorWayfire:  {methodManaged, methodCompositor, methodDDC, methodXWayland},
+	CompositorWeston:   {methodDDC, methodOverlay, methodXWayland},
+}
+
+// defaultWaylandStrategy prueba todos los métodos cuando no se reconoce el compositor
+var defaultWaylandStrategy = []string{
+	methodCompositor, methodMutter, methodKWin, methodManaged, methodDDC, methodOverlay, methodXWayland,
+}
+
+// waylandStrategy devuelve los métodos a probar en un compositor (CompositorID)
+func waylandStrategy(compositor string) []string {
+	if strategy, ok := compositorStrategies[compositor]; ok {
+		return strategy
+	}
+	return defaultWaylandStrategy
+}
+
+var (
+	compositorOnce     sync.Once
+	detectedCompositor string
+)
+
+/**
+ * CompositorID - Identifica el compositor Wayland de la sesión
+ *
+ * Primero mira las variables que exporta cada compositor (sockets de IPC
+ * y XDG_CURRENT_DESKTOP); si no bastan, busca en el bus de sesión los
+ * nombres que registran Mutter y KWin. El resultado se calcula una vez
+ * por proceso.
+ *
+ * @returns {string} CompositorMutter, CompositorKWin... o "" si no se reconoce
+ */
+func CompositorID() string {
+	compositorOnce.Do(func() {
+		detectedCompositor = compositorFromEnv()
+		if detectedCompositor == "" {
+			detectedCompositor = compositorFromBus(sessionBusNames())
+		}
+	})
+	return detectedCompositor
+}
+
+// compositorFromEnv reconoce el compositor por sus variables de entorno
+func compositorFromEnv() string {
+	switch {
+	case os.Getenv("HYPRLAND_INSTANCE_SIGNATURE") != "":
+		return CompositorHyprland
+	case os.Getenv("SWAYSOCK") != "":
+		return CompositorSway
+	case os.Getenv("WAYFIRE_SOCKET") != "":
+		return CompositorWayfire
+	}
+
+	desktop := strings.ToLower(os.Getenv("XDG_CURRENT_DESKTOP") + ":" + os.Getenv("XDG_SESSION_DESKTOP"))
+	switch {
+	case strings.Contains(desktop, "gnome"):
+		return CompositorMutter
+	case strings.Contains(desktop, "kde"), strings.Contains(desktop, "plasma"):
+		return CompositorKWin
+	case strings.Contains(desktop, "hyprland"):
+		return CompositorHyprland
+	case strings.Contains(desktop, "sway"):
+		return CompositorSway
+	case strings.Contains(desktop, "wayfire"):
+		return CompositorWayfire
+	case strings.Contains(desktop, "weston"):
+		return CompositorWeston
+	}
+	return ""
+}
+
+// compositorFromBus reconoce el compositor por los nombres registrados en el bus de sesión
+func compositorFromBus(names []string) string {
+	for _, name := range names {
+		if compositor, ok := compositorBusNames[name]; ok {
+			return compositor
+		}
+	}
+	return ""
+}
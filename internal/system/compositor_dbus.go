@@ -0,0 +1,39 @@
+package system
+
+import "github.com/godbus/dbus/v5"
+
+// sessionBusNames devuelve los nombres registrados en el bus de sesión (nil si no hay bus)
+func sessionBusNames() []string {
+	conn, err := dbus.ConnectSessionBus()
+	if err != nil {
+		return nil
+	}
+	defer conn.Close()
+
+	var names []string
+	if err := conn.BusObject().Call("org.freedesktop.DBus.ListNames", 0).Store(&names); err != nil {
+		return nil
+	}
+	return names
+}
+
+/**
+ * kwinColorCorrect - Llama a un método de Night Color de KWin por D-Bus
+ *
+ * Se habla directamente con el bus de sesión en lugar de lanzar qdbus,
+ * así funciona igual dentro de Flatpak (con --talk-name=org.kde.KWin).
+ *
+ * @param {string} method - Método de org.kde.kwin.ColorCorrect (p. ej. "setMode")
+ * @param {...interface{}} args - Argumentos del método
+ * @returns {error} Error si no hay bus o KWin rechaza la llamada
+ * @private
+ */
+func kwinColorCorrect(method string, args ...interface{}) error {
+	conn, err := dbus.ConnectSessionBus()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	return conn.Object("org.kde.KWin", "/ColorCorrect").Call("org.kde.kwin.ColorCorrect."+method, 0, args...).Err
+}
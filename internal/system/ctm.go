@@ -0,0 +1,183 @@
+package system
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+)
+
+// Peticiones X11 y RandR para la matriz de color (CTM) de las salidas
+const (
+	x11InternAtom             = 16
+	x11AtomInteger            = 19
+	randrQueryOutputProperty  = 11
+	randrChangeOutputProperty = 13
+)
+
+// ctmProperty es la propiedad de salida con la que los drivers modesetting y
+// amdgpu exponen la matriz de color del CRTC (drm_color_ctm)
+const ctmProperty = "CTM"
+
+// Luminancia relativa de cada canal (Rec. 709): el gris que ve el ojo con ese color
+var lumaWeights = [3]float64{0.2126, 0.7152, 0.0722}
+
+/**
+ * saturationMatrix - Matriz 3×3 que desatura la imagen
+ *
+ * Mezcla cada canal con la luminancia del píxel: con saturación 1 es la
+ * identidad y con 0 los tres canales valen lo mismo (escala de grises).
+ * Las filas suman 1, así que el blanco y el negro no cambian.
+ *
+ * @param {float64} saturation - Color que se conserva (0-1)
+ * @returns {[9]float64} Matriz por filas (rojo, verde, azul)
+ */
+func saturationMatrix(saturation float64) [9]float64 {
+	saturation = math.Max(0, math.Min(saturation, 1))
+	var matrix [9]float64
+	for row := 0; row < 3; row++ {
+		for col := 0; col < 3; col++ {
+			matrix[row*3+col] = (1 - saturation) * lumaWeights[col]
+			if row == col {
+				matrix[row*3+col] += saturation
+			}
+		}
+	}
+	return matrix
+}
+
+/**
+ * encodeCTM - Convierte la matriz al formato de la propiedad CTM
+ *
+ * El kernel espera nueve valores S31.32 en signo y magnitud; la
+ * propiedad de X11 los lleva como 18 enteros de 32 bits, primero la
+ * mitad baja de cada uno.
+ *
+ * @param {[9]float64} matrix - Matriz por filas
+ * @returns {[]uint32} Los 18 valores de la propiedad
+ * @private
+ */
+func encodeCTM(matrix [9]float64) []uint32 {
+	values := make([]uint32, 0, 18)
+	for _, coefficient := range matrix {
+		fixed := uint64(math.Round(math.Abs(coefficient) * (1 << 32)))
+		if coefficient < 0 {
+			fixed |= 1 << 63
+		}
+		values = append(values, uint32(fixed), uint32(fixed>>32))
+	}
+	return values
+}
+
+// internAtom devuelve el átomo de un nombre sin crearlo (0 si el servidor no lo conoce)
+func (c *randrConn) internAtom(name string) (uint32, error) {
+	body := x11Pad([]byte(name))
+	request := make([]byte, 8+len(body))
+	request[0] = x11InternAtom
+	request[1] = 1 // only-if-exists
+	binary.LittleEndian.PutUint16(request[4:], uint16(len(name)))
+	copy(request[8:], body)
+	reply, err := c.roundTrip(request)
+	if err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint32(reply[8:]), nil
+}
+
+// hasOutputProperty indica si la salida tiene la propiedad (el driver la creó)
+func (c *randrConn) hasOutputProperty(output, property uint32) bool {
+	request := c.randrRequest(randrQueryOutputProperty, 8)
+	binary.LittleEndian.PutUint32(request[4:], output)
+	binary.LittleEndian.PutUint32(request[8:], property)
+	_, err := c.roundTrip(request)
+	return err == nil
+}
+
+/**
+ * setCTM - Cambia la matriz de color de una salida
+ *
+ * No espera respuesta: los errores se comprueban después con sync.
+ *
+ * @param {uint32} output - Salida de destino
+ * @param {uint32} property - Átomo de la propiedad CTM
+ * @param {[9]float64} matrix - Matriz por filas
+ * @returns {uint16, error} Secuencia de la petición
+ * @private
+ */
+func (c *randrConn) setCTM(output, property uint32, matrix [9]float64) (uint16, error) {
+	values := encodeCTM(matrix)
+	request := c.randrRequest(randrChangeOutputProperty, 20+4*len(values))
+	binary.LittleEndian.PutUint32(request[4:], output)
+	binary.LittleEndian.PutUint32(request[8:], property)
+	binary.LittleEndian.PutUint32(request[12:], x11AtomInteger)
+	request[16] = 32 // Formato: enteros de 32 bits
+	request[17] = 0  // Modo: reemplazar
+	binary.LittleEndian.PutUint32(request[20:], uint32(len(values)))
+	for i, value := range values {
+		binary.LittleEndian.PutUint32(request[24+4*i:], value)
+	}
+	return c.send(request)
+}
+
+/**
+ * applyRandRCTM - Desatura los displays con la matriz de color de RandR
+ *
+ * Las rampas gamma son una LUT por canal y no pueden mezclar colores;
+ * la matriz CTM, que el hardware aplica antes de la LUT, sí. Los
+ * displays excluidos vuelven a la identidad.
+ *
+ * @param {[]string} targets - Displays que se desaturan
+ * @param {[]string} excluded - Displays que vuelven al color normal
+ * @param {float64} saturation - Color que se conserva (1 = normal, 0 = escala de grises)
+ * @returns {[]string, error} Displays con la matriz aplicada; error si el driver no expone CTM
+ * @private
+ */
+func applyRandRCTM(targets, excluded []string, saturation float64) ([]string, error) {
+	c, err := openRandR()
+	if err != nil {
+		return nil, err
+	}
+	defer c.close()
+
+	property, err := c.internAtom(ctmProperty)
+	if err != nil {
+		return nil, err
+	}
+	if property == 0 {
+		return nil, errors.New("el driver de vídeo no expone la matriz de color (CTM)")
+	}
+	outputs, err := c.outputs()
+	if err != nil {
+		return nil, err
+	}
+
+	upload := func(displays []string, matrix [9]float64) map[string]uint16 {
+		pending := make(map[string]uint16)
+		for _, display := range displays {
+			output, ok := outputs[display]
+			if !ok || !c.hasOutputProperty(output.id, property) {
+				continue
+			}
+			if seq, err := c.setCTM(output.id, property, matrix); err == nil {
+				pending[display] = seq
+			}
+		}
+		return pending
+	}
+
+	upload(excluded, saturationMatrix(1))
+	pending := upload(targets, saturationMatrix(saturation))
+	if err := c.sync(); err != nil {
+		return nil, err
+	}
+
+	var applied []string
+	for _, display := range targets {
+		if seq, ok := pending[display]; ok && c.failed[seq] == nil {
+			applied = append(applied, display)
+		}
+	}
+	if len(applied) == 0 && len(targets) > 0 {
+		return nil, errors.New("ninguna salida aceptó la matriz de color (CTM)")
+	}
+	return applied, nil
+}
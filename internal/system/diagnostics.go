@@ -0,0 +1,116 @@
+package system
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// diagnosticEnvVars son las variables de la sesión que ayudan a reproducir un fallo
+var diagnosticEnvVars = []string{
+	"XDG_SESSION_TYPE", "XDG_CURRENT_DESKTOP", "XDG_SESSION_DESKTOP", "DESKTOP_SESSION",
+	"WAYLAND_DISPLAY", "DISPLAY", "SWAYSOCK", "HYPRLAND_INSTANCE_SIGNATURE", "WAYFIRE_SOCKET",
+	"FLATPAK_ID", "LUZ_NOCTURNA_BACKEND",
+}
+
+/**
+ * EnvironmentReport - Entorno detectado y resultado de sondear los backends
+ *
+ * Es la parte del paquete de diagnóstico que describe el sistema. Solo
+ * lee: no aplica gamma ni desactiva la luz nocturna del sistema, así que
+ * puede generarse desde la CLI sin tocar el display.
+ *
+ * @struct {EnvironmentReport}
+ * @property {string} Protocol - Protocolo según el entorno ("x11" o "wayland")
+ * @property {string} Compositor - Nombre legible del compositor
+ * @property {[]string} Strategy - Métodos de Wayland que se probarían, en orden
+ * @property {bool} Flatpak - Si se ejecuta dentro de Flatpak
+ * @property {string} PackageManager - Gestor de paquetes de la distribución
+ * @property {map[string]string} Session - Variables de la sesión relevantes
+ * @property {map[string]bool} Tools - Herramientas externas instaladas
+ * @property {[]string} Plugins - Plugins de backend encontrados
+ * @property {string} RandR - Resultado de abrir la extensión RandR
+ * @property {map[string]string} XrandrGamma - Gamma actual de cada display según xrandr --verbose
+ */
+type EnvironmentReport struct {
+	Protocol       string            `json:"protocol"`
+	Compositor     string            `json:"compositor"`
+	Strategy       []string          `json:"strategy,omitempty"`
+	Flatpak        bool              `json:"flatpak"`
+	PackageManager string            `json:"package_manager"`
+	Session        map[string]string `json:"session"`
+	Tools          map[string]bool   `json:"tools"`
+	Plugins        []string          `json:"plugins"`
+	RandR          string            `json:"randr"`
+	XrandrGamma    map[string]string `json:"xrandr_gamma,omitempty"`
+}
+
+/**
+ * ProbeEnvironment - Sondea el entorno y los backends sin modificar nada
+ *
+ * @returns {EnvironmentReport} Entorno detectado y resultado de cada sondeo
+ * @example
+ *   report := system.ProbeEnvironment()
+ *   fmt.Println(report.Protocol, report.Compositor)
+ */
+func ProbeEnvironment() EnvironmentReport {
+	report := EnvironmentReport{
+		Protocol:       displayProtocolFromEnv(),
+		Compositor:     DetectCompositor(),
+		Flatpak:        IsFlatpak(),
+		PackageManager: DetectPackageManager(),
+		Session:        make(map[string]string),
+		Tools:          make(map[string]bool, len(knownTools)),
+	}
+	if report.Protocol == "wayland" {
+		report.Strategy = waylandStrategy(CompositorID())
+	}
+	for _, name := range diagnosticEnvVars {
+		if value, ok := os.LookupEnv(name); ok {
+			report.Session[name] = value
+		}
+	}
+	for _, tool := range knownTools {
+		report.Tools[tool] = lookupTool(tool) == nil
+	}
+	for _, plugin := range DiscoverPlugins() {
+		report.Plugins = append(report.Plugins, plugin.Label())
+	}
+
+	report.RandR = probeRandR()
+	if report.Tools["xrandr"] {
+		if output, err := toolCommand("xrandr", "--verbose").Output(); err == nil {
+			report.XrandrGamma = make(map[string]string)
+			for display, gamma := range parseXrandrGamma(string(output)) {
+				report.XrandrGamma[display] = fmt.Sprintf("%.2f:%.2f:%.2f", gamma[0], gamma[1], gamma[2])
+			}
+		}
+	}
+	return report
+}
+
+// probeRandR describe si se pueden subir rampas RandR y con qué tamaño de LUT
+func probeRandR() string {
+	c, err := openRandR()
+	if err != nil {
+		return "no disponible: " + err.Error()
+	}
+	defer c.close()
+
+	crtcs, err := c.outputCrtcs()
+	if err != nil {
+		return "sin salidas: " + err.Error()
+	}
+	outputs := make([]string, 0, len(crtcs))
+	for display, crtc := range crtcs {
+		size, err := c.gammaSize(crtc)
+		if err != nil {
+			outputs = append(outputs, display+" (sin LUT)")
+			continue
+		}
+		outputs = append(outputs, fmt.Sprintf("%s (LUT %d)", display, size))
+	}
+	sort.Strings(outputs)
+	return "disponible: " + strings.Join(outputs, ", ")
+}
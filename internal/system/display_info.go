@@ -0,0 +1,211 @@
+package system
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// drmSysfsDir es donde el kernel publica los conectores DRM con su EDID y su bus DDC
+var drmSysfsDir = "/sys/class/drm"
+
+// xrandrModeRegex extrae el modo actual de una salida conectada ("HDMI-1 connected primary 2560x1440+0+0")
+var xrandrModeRegex = regexp.MustCompile(`^(\S+)\s+connected\s+(?:primary\s+)?(\d+x\d+)\+`)
+
+// edidHeader es la cabecera fija de un bloque EDID
+var edidHeader = []byte{0x00, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x00}
+
+/**
+ * DisplayInfo - Detalles de un display para el panel de pantallas
+ *
+ * @struct {DisplayInfo}
+ * @property {string} Name - Conector tal como lo usa el backend ("eDP-1", "HDMI-1"...)
+ * @property {string} Model - Nombre del monitor según el EDID ("DELL U2720Q"); "" si no se pudo leer
+ * @property {string} Resolution - Modo actual según xrandr o el nativo del EDID ("2560x1440"); "" si se desconoce
+ * @property {bool} Gamma - El backend puede cambiar su rampa de gamma
+ * @property {bool} DDC - El monitor responde a DDC/CI (ddcutil)
+ */
+type DisplayInfo struct {
+	Name       string
+	Model      string
+	Resolution string
+	Gamma      bool
+	DDC        bool
+}
+
+// drmConnector es un conector conectado según sysfs
+type drmConnector struct {
+	name       string // Sin el prefijo de la tarjeta ("HDMI-A-1")
+	model      string
+	resolution string
+	ddcBus     int // -1 si el driver no expone el bus
+}
+
+/**
+ * GetDisplayInfo - Reúne modelo, resolución y capacidades de cada display
+ *
+ * Lee el EDID y el bus I2C de /sys/class/drm, el modo actual de xrandr
+ * y los buses DDC/CI de ddcutil. Puede tardar varios segundos la primera
+ * vez (ddcutil detect): no debe llamarse desde el hilo de la interfaz.
+ *
+ * @returns {[]DisplayInfo} Un elemento por display de GetDisplays, en el mismo orden
+ */
+func (gm *GammaManager) GetDisplayInfo() []DisplayInfo {
+	connectors := readDRMConnectors()
+	modes := gm.currentModes()
+
+	ddcBuses := make(map[int]bool)
+	for _, bus := range gm.caps.DDCBuses() {
+		ddcBuses[bus] = true
+	}
+
+	var infos []DisplayInfo
+	for _, display := range gm.GetDisplays() {
+		info := DisplayInfo{Name: display, Gamma: gm.canSetGamma(), Resolution: modes[display]}
+		for _, connector := range connectors {
+			if !connectorMatches(connector.name, display) {
+				continue
+			}
+			info.Model = connector.model
+			if info.Resolution == "" {
+				info.Resolution = connector.resolution
+			}
+			info.DDC = ddcBuses[connector.ddcBus]
+		}
+		infos = append(infos, info)
+	}
+	return infos
+}
+
+// canSetGamma indica si hay un método de rampa de gamma: xrandr/RandR en X11;
+// en Wayland, que la última aplicación no necesitara DDC/CI ni la capa superpuesta
+func (gm *GammaManager) canSetGamma() bool {
+	if gm.protocol != "wayland" {
+		return true
+	}
+	backend := gm.GetBackend()
+	return backend != "" && backend != methodDDC && backend != methodOverlay
+}
+
+// currentModes devuelve el modo actual de cada salida según xrandr (vacío si no está)
+func (gm *GammaManager) currentModes() map[string]string {
+	modes := make(map[string]string)
+	if !gm.isToolAvailable("xrandr") {
+		return modes
+	}
+	output, err := toolCommand("xrandr", "--query").Output()
+	if err != nil {
+		return modes
+	}
+	for _, line := range strings.Split(string(output), "\n") {
+		if matches := xrandrModeRegex.FindStringSubmatch(line); matches != nil {
+			modes[matches[1]] = matches[2]
+		}
+	}
+	return modes
+}
+
+// readDRMConnectors lee los conectores con un monitor conectado
+func readDRMConnectors() []drmConnector {
+	paths, _ := filepath.Glob(filepath.Join(drmSysfsDir, "card*-*"))
+
+	var connectors []drmConnector
+	for _, path := range paths {
+		status, err := os.ReadFile(filepath.Join(path, "status"))
+		if err != nil || strings.TrimSpace(string(status)) != "connected" {
+			continue
+		}
+
+		// "card0-HDMI-A-1" -> "HDMI-A-1"
+		_, name, _ := strings.Cut(filepath.Base(path), "-")
+		connector := drmConnector{name: name, ddcBus: ddcBusOf(path)}
+		if edid, err := os.ReadFile(filepath.Join(path, "edid")); err == nil {
+			connector.model, connector.resolution = parseEDID(edid)
+		}
+		connectors = append(connectors, connector)
+	}
+	return connectors
+}
+
+// ddcBusOf devuelve el número del bus I2C del conector (enlace "ddc" o subdirectorio "i2c-N"), o -1
+func ddcBusOf(connectorPath string) int {
+	candidates, _ := filepath.Glob(filepath.Join(connectorPath, "i2c-*"))
+	if target, err := os.Readlink(filepath.Join(connectorPath, "ddc")); err == nil {
+		candidates = append([]string{target}, candidates...)
+	}
+	for _, candidate := range candidates {
+		if bus, err := strconv.Atoi(strings.TrimPrefix(filepath.Base(candidate), "i2c-")); err == nil {
+			return bus
+		}
+	}
+	return -1
+}
+
+/**
+ * parseEDID - Extrae el nombre del monitor y su modo nativo de un bloque EDID
+ *
+ * El nombre sale del descriptor 0xFC; si no lo hay se usa el fabricante
+ * (código PNP de tres letras) y el código de producto. El modo nativo es
+ * el primer descriptor de temporización detallada.
+ *
+ * @param {[]byte} edid - Contenido de /sys/class/drm/<conector>/edid
+ * @returns {string, string} Modelo y resolución ("" si el bloque no es válido)
+ */
+func parseEDID(edid []byte) (model, resolution string) {
+	if len(edid) < 128 || !bytes.Equal(edid[:8], edidHeader) {
+		return "", ""
+	}
+
+	for offset := 54; offset <= 108; offset += 18 {
+		descriptor := edid[offset : offset+18]
+		if descriptor[0] != 0 || descriptor[1] != 0 {
+			// Temporización detallada: la primera es el modo preferido
+			if resolution == "" {
+				width := int(descriptor[2]) | int(descriptor[4]>>4)<<8
+				height := int(descriptor[5]) | int(descriptor[7]>>4)<<8
+				resolution = fmt.Sprintf("%dx%d", width, height)
+			}
+			continue
+		}
+		if descriptor[3] == 0xfc {
+			model = strings.TrimSpace(strings.SplitN(string(descriptor[5:]), "\n", 2)[0])
+		}
+	}
+
+	if model == "" {
+		manufacturer := uint16(edid[8])<<8 | uint16(edid[9])
+		letters := []byte{
+			byte('A' - 1 + (manufacturer>>10)&0x1f),
+			byte('A' - 1 + (manufacturer>>5)&0x1f),
+			byte('A' - 1 + manufacturer&0x1f),
+		}
+		product := uint16(edid[11])<<8 | uint16(edid[10])
+		model = fmt.Sprintf("%s %04X", letters, product)
+	}
+	return model, resolution
+}
+
+// connectorMatches indica si un conector de sysfs ("HDMI-A-1") es el display del
+// backend: xrandr lo llama "HDMI-1" con modesetting y "HDMI1" con el driver intel
+func connectorMatches(connector, display string) bool {
+	normalize := func(name string) string {
+		return strings.ToUpper(strings.ReplaceAll(name, "-", ""))
+	}
+	target := normalize(display)
+	if normalize(connector) == target {
+		return true
+	}
+	for _, kind := range []string{"HDMI-A-", "HDMI-B-", "DVI-D-", "DVI-I-"} {
+		if strings.HasPrefix(connector, kind) {
+			short := kind[:strings.Index(kind, "-")+1] + strings.TrimPrefix(connector, kind)
+			if normalize(short) == target {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,61 @@
+package system
+
+import "testing"
+
+// testEDID construye un bloque EDID mínimo con un modo nativo y, si name no está vacío, su nombre
+func testEDID(name string, width, height int) []byte {
+	edid := make([]byte, 128)
+	copy(edid, edidHeader)
+	edid[8], edid[9] = 0x10, 0xac // "DEL"
+	edid[10], edid[11] = 0x34, 0x12
+
+	timing := edid[54:72]
+	timing[0] = 0x01 // Reloj de píxel distinto de cero: temporización detallada
+	timing[2], timing[4] = byte(width), byte(width>>8)<<4
+	timing[5], timing[7] = byte(height), byte(height>>8)<<4
+
+	if name != "" {
+		descriptor := edid[72:90]
+		descriptor[3] = 0xfc
+		copy(descriptor[5:], name+"\n   ")
+	}
+	return edid
+}
+
+func TestParseEDID(t *testing.T) {
+	model, resolution := parseEDID(testEDID("DELL U2720Q", 3840, 2160))
+	if model != "DELL U2720Q" || resolution != "3840x2160" {
+		t.Errorf("con nombre: %q %q, se esperaba \"DELL U2720Q\" \"3840x2160\"", model, resolution)
+	}
+
+	// Sin descriptor de nombre: fabricante y código de producto
+	model, _ = parseEDID(testEDID("", 1920, 1080))
+	if model != "DEL 1234" {
+		t.Errorf("sin nombre: %q, se esperaba \"DEL 1234\"", model)
+	}
+
+	if model, resolution := parseEDID([]byte{0, 1, 2}); model != "" || resolution != "" {
+		t.Errorf("EDID no válido: %q %q, se esperaba vacío", model, resolution)
+	}
+}
+
+func TestConnectorMatches(t *testing.T) {
+	cases := []struct {
+		connector, display string
+		want               bool
+	}{
+		{"eDP-1", "eDP-1", true},
+		{"eDP-1", "eDP1", true},
+		{"HDMI-A-1", "HDMI-1", true},
+		{"HDMI-A-1", "HDMI1", true},
+		{"HDMI-A-1", "HDMI-A-1", true},
+		{"DVI-D-2", "DVI-2", true},
+		{"DP-1", "DP-2", false},
+		{"HDMI-A-1", "DP-1", false},
+	}
+	for _, c := range cases {
+		if got := connectorMatches(c.connector, c.display); got != c.want {
+			t.Errorf("connectorMatches(%q, %q) = %v, se esperaba %v", c.connector, c.display, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package system
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrNoBackend indica que ningún método de gamma pudo aplicarse
+var ErrNoBackend = errors.New("no hay ningún backend de gamma disponible")
+
+// ErrPartialApply indica que la gamma se aplicó solo en algunos displays
+var ErrPartialApply = errors.New("gamma aplicada solo en parte de los displays")
+
+// PartialApplyError detalla en qué displays se aplicó la gamma y en cuáles falló.
+// errors.Is(err, ErrPartialApply) es true para este error.
+type PartialApplyError struct {
+	Applied  []string
+	Failed   []string
+	Attempts int // Intentos realizados antes de rendirse (0 o 1 = sin reintentos)
+}
+
+func (e *PartialApplyError) Error() string {
+	message := fmt.Sprintf("gamma aplicada en %s; falló en %s",
+		strings.Join(e.Applied, ", "), strings.Join(e.Failed, ", "))
+	if e.Attempts > 1 {
+		message += fmt.Sprintf(" (tras %d intentos)", e.Attempts)
+	}
+	return message
+}
+
+func (e *PartialApplyError) Is(target error) bool {
+	return target == ErrPartialApply
+}
+
+// MethodAttempt es un método de la cadena de Wayland que no funcionó y el motivo
+type MethodAttempt struct {
+	Method string
+	Reason string
+}
+
+// WaylandApplyError detalla por qué falló toda la cadena de métodos de Wayland y
+// qué puede hacer el usuario. errors.Is(err, ErrNoBackend) es true para este error.
+type WaylandApplyError struct {
+	Compositor  string          // Compositor detectado ("GNOME (Mutter)", "Sway"...)
+	Attempts    []MethodAttempt // Métodos intentados, en orden
+	Suggestions []string        // Pasos concretos para habilitar algún método
+	Install     string          // Comando para instalar las herramientas recomendadas que faltan ("" si no hay)
+	Transient   bool            // Algún método disponible falló: puede ser pasajero y merece otro intento
+}
+
+func (e *WaylandApplyError) Error() string {
+	methods := make([]string, len(e.Attempts))
+	for i, attempt := range e.Attempts {
+		methods[i] = attempt.Method
+	}
+	return fmt.Sprintf("no se pudo aplicar gamma en Wayland (%v).\n"+
+		"Métodos intentados: %s\n"+
+		"Tu compositor Wayland (%s) puede no soportar control de gamma",
+		ErrNoBackend, strings.Join(methods, ", "), e.Compositor)
+}
+
+func (e *WaylandApplyError) Unwrap() error {
+	return ErrNoBackend
+}
+
+// MissingToolError indica que el único método posible necesita una herramienta
+// que no está instalada. errors.Is(err, ErrNoBackend) es true para este error.
+type MissingToolError struct {
+	Tools          []string // Herramientas ausentes ("xrandr"...)
+	InstallCommand string   // Comando de instalación para esta distribución ("" si es desconocida)
+}
+
+func (e *MissingToolError) Error() string {
+	message := fmt.Sprintf("%v: no está instalado %s", ErrNoBackend, strings.Join(e.Tools, ", "))
+	if e.InstallCommand != "" {
+		message += " (instálalo con: " + e.InstallCommand + ")"
+	}
+	return message
+}
+
+func (e *MissingToolError) Unwrap() error {
+	return ErrNoBackend
+}
@@ -0,0 +1,174 @@
+package system
+
+import (
+	"bufio"
+	"context"
+	"luznocturna/luz-nocturna/internal/logging"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// competitorProcesses son los procesos que compiten por la gamma mientras tenemos el control
+var competitorProcesses = []string{"redshift", "wlsunset", "gammastep"}
+
+// processCheckInterval es cada cuánto se revisa /proc en busca de competidores
+const processCheckInterval = 10 * time.Second
+
+/**
+ * exclusiveMonitor - Mantiene el control exclusivo de la gamma sin sondeo
+ *
+ * Sustituye al antiguo ticker de 30 segundos que lanzaba gsettings y pgrep:
+ * - GNOME: un único "gsettings monitor" avisa al instante si Night Light se reactiva
+ * - KDE: suscripción a PropertiesChanged de Night Color por D-Bus
+ * - Procesos: lectura de /proc cada 10 s, sin lanzar subprocesos, solo si no
+ *   hay señales de KWin y fuera de Flatpak (que no ve el host)
+ *
+ * Se inicia una sola vez y se detiene con Stop().
+ *
+ * @struct {exclusiveMonitor}
+ * @property {*GammaManager} gm - Manejador que ejecuta las correcciones
+ * @property {context.CancelFunc} cancel - Detiene todas las goroutines (nil si no está activo)
+ */
+type exclusiveMonitor struct {
+	gm     *GammaManager
+	mu     sync.Mutex
+	cancel context.CancelFunc
+}
+
+// newExclusiveMonitor crea el monitor sin iniciarlo
+func newExclusiveMonitor(gm *GammaManager) *exclusiveMonitor {
+	return &exclusiveMonitor{gm: gm}
+}
+
+/**
+ * Start - Inicia la vigilancia si no estaba activa
+ */
+func (m *exclusiveMonitor) Start() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.cancel != nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancel = cancel
+
+	go m.watchGSettings(ctx)
+	// Con las señales de KWin no hace falta revisar /proc: Night Color es quien compite por la gamma.
+	// En el sandbox no se ven ni se pueden terminar los procesos del host.
+	if !m.watchKWin(ctx) && !IsFlatpak() {
+		go m.watchProcesses(ctx)
+	}
+}
+
+/**
+ * Stop - Detiene la vigilancia y sus subprocesos
+ */
+func (m *exclusiveMonitor) Stop() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.cancel != nil {
+		m.cancel()
+		m.cancel = nil
+	}
+}
+
+/**
+ * watchGSettings - Escucha los cambios de Night Light de GNOME
+ *
+ * "gsettings monitor" imprime una línea por cambio; si el proceso muere
+ * se relanza tras una pausa.
+ *
+ * @param {context.Context} ctx - Contexto de cancelación
+ * @private
+ */
+func (m *exclusiveMonitor) watchGSettings(ctx context.Context) {
+	if !m.gm.isToolAvailable("gsettings") {
+		return
+	}
+
+	for {
+		cmd := toolCommandContext(ctx, "gsettings", "monitor", "org.gnome.settings-daemon.plugins.color", "night-light-enabled")
+		stdout, err := cmd.StdoutPipe()
+		if err == nil {
+			err = cmd.Start()
+		}
+		if err == nil {
+			scanner := bufio.NewScanner(stdout)
+			for scanner.Scan() {
+				if strings.HasSuffix(strings.TrimSpace(scanner.Text()), "true") {
+					logging.Println("🔧 Night Light de GNOME se reactivó, deshabilitando")
+					m.gm.runCommand("gsettings", "set", "org.gnome.settings-daemon.plugins.color", "night-light-enabled", "false")
+				}
+			}
+			cmd.Wait()
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(5 * time.Second):
+		}
+	}
+}
+
+/**
+ * watchProcesses - Termina procesos competidores en cuanto aparecen
+ *
+ * @param {context.Context} ctx - Contexto de cancelación
+ * @private
+ */
+func (m *exclusiveMonitor) watchProcesses(ctx context.Context) {
+	ticker := time.NewTicker(processCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		for _, proc := range competitorProcesses {
+			if m.gm.managed.Manages(proc) {
+				continue // Es nuestro proceso hijo supervisado
+			}
+			if isProcessRunning(proc) {
+				m.gm.runCommand("pkill", "-TERM", proc)
+			}
+		}
+	}
+}
+
+/**
+ * isProcessRunning - Busca un proceso por nombre en /proc
+ *
+ * Dentro de Flatpak /proc solo muestra el sandbox, así que nunca se
+ * encuentra un proceso del host.
+ *
+ * @param {string} name - Nombre del proceso (como en /proc/<pid>/comm)
+ * @returns {bool} true si hay algún proceso con ese nombre
+ * @private
+ */
+func isProcessRunning(name string) bool {
+	if IsFlatpak() {
+		return false
+	}
+
+	comms, err := filepath.Glob("/proc/[0-9]*/comm")
+	if err != nil {
+		return false
+	}
+	for _, comm := range comms {
+		data, err := os.ReadFile(comm)
+		if err == nil && strings.TrimSpace(string(data)) == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,74 @@
+package system
+
+import (
+	"context"
+
+	"github.com/godbus/dbus/v5"
+
+	"luznocturna/luz-nocturna/internal/logging"
+)
+
+// kwinNightColorPaths son los objetos de Night Color en Plasma 5 y Plasma 6
+var kwinNightColorPaths = []dbus.ObjectPath{"/ColorCorrect", "/org/kde/KWin/NightLight"}
+
+/**
+ * watchKWin - Escucha los cambios de Night Color de KDE por D-Bus
+ *
+ * La suscripción se hace antes de volver; las señales se atienden en
+ * una goroutine propia hasta que se cancela ctx o se cae la conexión.
+ *
+ * @param {context.Context} ctx - Contexto de cancelación
+ * @returns {bool} true si KWin está en el bus y se reciben sus señales
+ * @private
+ */
+func (m *exclusiveMonitor) watchKWin(ctx context.Context) bool {
+	conn, err := dbus.ConnectSessionBus()
+	if err != nil {
+		return false
+	}
+
+	var hasOwner bool
+	if err := conn.BusObject().Call("org.freedesktop.DBus.NameHasOwner", 0, "org.kde.KWin").Store(&hasOwner); err != nil || !hasOwner {
+		conn.Close()
+		return false
+	}
+	for _, path := range kwinNightColorPaths {
+		if err := conn.AddMatchSignal(
+			dbus.WithMatchObjectPath(path),
+			dbus.WithMatchInterface("org.freedesktop.DBus.Properties"),
+			dbus.WithMatchMember("PropertiesChanged"),
+		); err != nil {
+			conn.Close()
+			return false
+		}
+	}
+
+	signals := make(chan *dbus.Signal, 10)
+	conn.Signal(signals)
+
+	go func() {
+		defer conn.Close()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case signal, ok := <-signals:
+				if !ok {
+					return // Se cerró la conexión con el bus
+				}
+				if signal == nil || len(signal.Body) < 2 {
+					continue
+				}
+				changed, ok := signal.Body[1].(map[string]dbus.Variant)
+				if !ok {
+					continue
+				}
+				if active, ok := changed["active"]; ok && active.Value() == true {
+					logging.Println("🔧 Night Color de KDE se reactivó, deshabilitando")
+					m.gm.callKWin("setMode", int32(0))
+				}
+			}
+		}
+	}()
+	return true
+}
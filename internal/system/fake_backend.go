@@ -0,0 +1,156 @@
+package system
+
+import (
+	"fmt"
+	"luznocturna/luz-nocturna/internal/logging"
+	"sync"
+)
+
+/**
+ * FakeBackend - Backend de gamma simulado para pruebas de integración
+ *
+ * Guarda en memoria la gamma "aplicada" y registra cada llamada, de modo
+ * que las pruebas (y el modo --fake-backend) recorren el controlador, la
+ * cola y el programador completos sin depender de xrandr ni del
+ * compositor.
+ *
+ * @struct {FakeBackend}
+ * @property {[]string} displays - Displays simulados
+ * @property {error} applyErr - Error que devolverá la próxima aplicación (nil para éxito)
+ * @property {[]string} calls - Historial de llamadas ("apply 3400K 1.00", "reset"...)
+ */
+type FakeBackend struct {
+	mu          sync.Mutex
+	displays    []string
+	applyErr    error
+	temperature float64
+	brightness  float64
+	active      bool
+	calls       []string
+	closed      bool
+}
+
+/**
+ * NewFakeBackend - Crea un backend simulado con gamma normal
+ *
+ * @param {...string} displays - Displays simulados (por defecto "FAKE-1")
+ * @returns {*FakeBackend} Backend listo para pasar en ControllerOptions.Backend
+ */
+func NewFakeBackend(displays ...string) *FakeBackend {
+	if len(displays) == 0 {
+		displays = []string{"FAKE-1"}
+	}
+	return &FakeBackend{displays: displays, temperature: 6500, brightness: 1.0}
+}
+
+// ApplyTemperatureWithBrightness simula la aplicación y la registra
+func (f *FakeBackend) ApplyTemperatureWithBrightness(temperature, brightness float64) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls = append(f.calls, fmt.Sprintf("apply %.0fK %.2f", temperature, brightness))
+	if f.applyErr != nil {
+		return f.applyErr
+	}
+	f.temperature, f.brightness, f.active = temperature, brightness, true
+	logging.WithFields(logging.Fields{"backend": "fake", "display": f.displays, "temp": temperature}).
+		Printf("🧪 [fake] Temperatura aplicada: %.0fK\n", temperature)
+	return nil
+}
+
+// Reset simula la restauración de la gamma normal
+func (f *FakeBackend) Reset() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls = append(f.calls, "reset")
+	f.temperature, f.brightness, f.active = 6500, 1.0, false
+	logging.Println("🧪 [fake] Gamma reseteada")
+	return nil
+}
+
+// VerifyGamma compara el estado simulado con el esperado
+func (f *FakeBackend) VerifyGamma(temperature, brightness float64) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.active && f.temperature == temperature && f.brightness == brightness, nil
+}
+
+// GetDisplays devuelve los displays simulados
+func (f *FakeBackend) GetDisplays() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.displays...)
+}
+
+// RefreshDisplays devuelve los displays simulados (no hay nada que detectar)
+func (f *FakeBackend) RefreshDisplays() []string {
+	return f.GetDisplays()
+}
+
+// SetExcludedDisplays registra los displays excluidos del filtro
+func (f *FakeBackend) SetExcludedDisplays(displays []string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, fmt.Sprintf("exclude %v", displays))
+}
+
+// GetHDRDisplays devuelve nil: los displays simulados no tienen HDR
+func (f *FakeBackend) GetHDRDisplays() []string {
+	return nil
+}
+
+// RefreshCapabilities no hace nada: el backend simulado no usa herramientas externas
+func (f *FakeBackend) RefreshCapabilities() {}
+
+// GetBackend devuelve "fake"
+func (f *FakeBackend) GetBackend() string {
+	return "fake"
+}
+
+// GetProtocol devuelve "fake"
+func (f *FakeBackend) GetProtocol() string {
+	return "fake"
+}
+
+// Close marca el backend como cerrado
+func (f *FakeBackend) Close() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.closed = true
+}
+
+// FailNextApplies hace que las siguientes aplicaciones devuelvan err (nil para volver a funcionar)
+func (f *FakeBackend) FailNextApplies(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.applyErr = err
+}
+
+// Tamper simula que otro programa restauró la gamma por su cuenta
+func (f *FakeBackend) Tamper() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.temperature, f.brightness, f.active = 6500, 1.0, false
+}
+
+// State devuelve la gamma simulada actual
+func (f *FakeBackend) State() (temperature, brightness float64, active bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.temperature, f.brightness, f.active
+}
+
+// Calls devuelve una copia del historial de llamadas
+func (f *FakeBackend) Calls() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.calls...)
+}
+
+// IsClosed indica si el controlador liberó el backend
+func (f *FakeBackend) IsClosed() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.closed
+}
@@ -0,0 +1,216 @@
+package system
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"luznocturna/luz-nocturna/internal/logging"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+/**
+ * FocusWatcher - Sigue la ventana enfocada para las reglas por aplicación
+ *
+ * En X11 escucha _NET_ACTIVE_WINDOW (EWMH) con "xprop -spy" y lee el
+ * WM_CLASS de cada ventana activa. Wayland no ofrece a los clientes
+ * normales el protocolo foreign-toplevel con el foco, así que se usa la
+ * IPC del compositor: los eventos de ventana de Sway y el socket de
+ * eventos de Hyprland.
+ *
+ * @struct {FocusWatcher}
+ * @property {string} Method - Método de seguimiento en uso ("EWMH", "Sway", "Hyprland")
+ * @property {context.CancelFunc} cancel - Detiene el seguimiento
+ */
+type FocusWatcher struct {
+	Method string
+	cancel context.CancelFunc
+}
+
+/**
+ * StartFocusWatcher - Empieza a seguir la ventana enfocada
+ *
+ * onChange recibe los identificadores de la aplicación enfocada: instancia
+ * y clase de WM_CLASS en X11, app_id (o la clase en XWayland) en Wayland.
+ * Se llama desde la goroutine del seguimiento.
+ *
+ * @param {string} protocol - Protocolo de display ("x11" o "wayland")
+ * @param {func([]string)} onChange - Callback con los identificadores de la nueva ventana
+ * @returns {*FocusWatcher, error} Seguimiento activo; error si no hay método para esta sesión
+ * @example
+ *   watcher, err := StartFocusWatcher("x11", func(ids []string) { fmt.Println(ids) })
+ *   defer watcher.Stop()
+ */
+func StartFocusWatcher(protocol string, onChange func(ids []string)) (*FocusWatcher, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	watcher := &FocusWatcher{cancel: cancel}
+
+	var watch func(context.Context, func([]string)) error
+	switch {
+	case protocol != "wayland":
+		if err := lookupTool("xprop"); err != nil {
+			cancel()
+			if install := InstallCommand(DetectPackageManager(), "xprop"); install != "" {
+				return nil, fmt.Errorf("el seguimiento de la ventana activa necesita xprop (instálalo con: %s)", install)
+			}
+			return nil, fmt.Errorf("el seguimiento de la ventana activa necesita xprop")
+		}
+		watcher.Method, watch = "EWMH", watchX11Focus
+	case os.Getenv("SWAYSOCK") != "":
+		watcher.Method, watch = "Sway", watchSwayFocus
+	case os.Getenv("HYPRLAND_INSTANCE_SIGNATURE") != "":
+		watcher.Method, watch = "Hyprland", watchHyprlandFocus
+	default:
+		cancel()
+		return nil, fmt.Errorf("el seguimiento de la ventana activa no está disponible en %s", DetectCompositor())
+	}
+
+	go func() {
+		if err := watch(ctx, onChange); err != nil && ctx.Err() == nil {
+			logging.Printf("⚠️  Seguimiento de la ventana activa (%s) detenido: %v\n", watcher.Method, err)
+		}
+	}()
+	logging.Printf("🎯 Siguiendo la ventana activa (%s)\n", watcher.Method)
+	return watcher, nil
+}
+
+// Stop detiene el seguimiento
+func (w *FocusWatcher) Stop() {
+	w.cancel()
+}
+
+// Expresiones de la salida de xprop
+var (
+	xpropWindowRegex = regexp.MustCompile(`window id # (0x[0-9a-fA-F]+)`)
+	xpropStringRegex = regexp.MustCompile(`"([^"]*)"`)
+)
+
+// watchX11Focus sigue _NET_ACTIVE_WINDOW y lee el WM_CLASS de cada ventana activa
+func watchX11Focus(ctx context.Context, onChange func([]string)) error {
+	return followLines(toolCommandContext(ctx, "xprop", "-root", "-spy", "_NET_ACTIVE_WINDOW"), func(line string) {
+		match := xpropWindowRegex.FindStringSubmatch(line)
+		if match == nil || match[1] == "0x0" {
+			onChange(nil) // Ninguna ventana activa (escritorio)
+			return
+		}
+
+		output, err := toolCommandContext(ctx, "xprop", "-id", match[1], "WM_CLASS").Output()
+		if err != nil {
+			return // La ventana se cerró antes de leerla
+		}
+		var ids []string
+		for _, value := range xpropStringRegex.FindAllStringSubmatch(string(output), -1) {
+			ids = append(ids, value[1])
+		}
+		onChange(ids)
+	})
+}
+
+// swayWindowEvent es la parte que interesa de un evento "window" de Sway
+type swayWindowEvent struct {
+	Change    string `json:"change"`
+	Container struct {
+		AppID            string `json:"app_id"`
+		WindowProperties struct {
+			Class    string `json:"class"`
+			Instance string `json:"instance"`
+		} `json:"window_properties"`
+	} `json:"container"`
+}
+
+// watchSwayFocus escucha los eventos de ventana de Sway
+func watchSwayFocus(ctx context.Context, onChange func([]string)) error {
+	cmd := toolCommandContext(ctx, "swaymsg", "-t", "subscribe", "-m", `["window"]`)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	defer cmd.Wait()
+
+	decoder := json.NewDecoder(stdout)
+	for {
+		var event swayWindowEvent
+		if err := decoder.Decode(&event); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		if event.Change != "focus" {
+			continue
+		}
+		container := event.Container
+		onChange(nonEmpty(container.AppID, container.WindowProperties.Instance, container.WindowProperties.Class))
+	}
+}
+
+// hyprlandSocket devuelve la ruta del socket de eventos de Hyprland (la de versiones recientes o la antigua en /tmp)
+func hyprlandSocket() string {
+	signature := os.Getenv("HYPRLAND_INSTANCE_SIGNATURE")
+	path := filepath.Join(os.Getenv("XDG_RUNTIME_DIR"), "hypr", signature, ".socket2.sock")
+	if _, err := os.Stat(path); err == nil {
+		return path
+	}
+	return filepath.Join("/tmp", "hypr", signature, ".socket2.sock")
+}
+
+// watchHyprlandFocus lee los eventos "activewindow>>clase,título" de Hyprland
+func watchHyprlandFocus(ctx context.Context, onChange func([]string)) error {
+	conn, err := (&net.Dialer{}).DialContext(ctx, "unix", hyprlandSocket())
+	if err != nil {
+		return err
+	}
+	go func() {
+		<-ctx.Done()
+		conn.Close()
+	}()
+
+	scanner := bufio.NewScanner(conn)
+	for scanner.Scan() {
+		event, data, _ := strings.Cut(scanner.Text(), ">>")
+		if event != "activewindow" {
+			continue
+		}
+		class, _, _ := strings.Cut(data, ",")
+		onChange(nonEmpty(class))
+	}
+	return scanner.Err()
+}
+
+// followLines ejecuta cmd y llama a handle con cada línea de su salida
+func followLines(cmd *exec.Cmd, handle func(string)) error {
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	defer cmd.Wait()
+
+	scanner := bufio.NewScanner(stdout)
+	for scanner.Scan() {
+		handle(scanner.Text())
+	}
+	return scanner.Err()
+}
+
+// nonEmpty devuelve los valores no vacíos
+func nonEmpty(values ...string) []string {
+	var result []string
+	for _, value := range values {
+		if value != "" {
+			result = append(result, value)
+		}
+	}
+	return result
+}
@@ -0,0 +1,142 @@
+package system
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"luznocturna/luz-nocturna/internal/logging"
+)
+
+// gamingPollInterval es cada cuánto se revisa /proc en busca de gamescope o Steam Big Picture
+const gamingPollInterval = 5 * time.Second
+
+// Sesiones de juego que retiran el filtro
+const (
+	GamingGamescope  = "Gamescope"
+	GamingBigPicture = "Steam Big Picture"
+)
+
+// gamescopeProcesses son los nombres del compositor de Valve en /proc/<pid>/comm
+var gamescopeProcesses = []string{"gamescope", "gamescope-wl"}
+
+// bigPictureFlags son los argumentos con los que Steam arranca en Big Picture (o en modo juego)
+var bigPictureFlags = []string{"-gamepadui", "-bigpicture", "-tenfoot", "steam://open/bigpicture"}
+
+/**
+ * DetectGamingSession - Busca gamescope o Steam Big Picture en ejecución
+ *
+ * Gamescope se reconoce por el nombre del proceso; Big Picture, por los
+ * argumentos del proceso "steam". Dentro de Flatpak /proc solo muestra el
+ * sandbox: StartGamingWatcher no arranca y aquí no se encuentra nada.
+ *
+ * @returns {string} GamingGamescope, GamingBigPicture o "" si no hay ninguna
+ */
+func DetectGamingSession() string {
+	for _, name := range gamescopeProcesses {
+		if isProcessRunning(name) {
+			return GamingGamescope
+		}
+	}
+	if isBigPictureRunning() {
+		return GamingBigPicture
+	}
+	return ""
+}
+
+// isBigPictureRunning indica si algún proceso "steam" tiene un argumento de Big Picture
+func isBigPictureRunning() bool {
+	comms, err := filepath.Glob("/proc/[0-9]*/comm")
+	if err != nil {
+		return false
+	}
+	for _, comm := range comms {
+		data, err := os.ReadFile(comm)
+		if err != nil || strings.TrimSpace(string(data)) != "steam" {
+			continue
+		}
+		cmdline, err := os.ReadFile(filepath.Join(filepath.Dir(comm), "cmdline"))
+		if err != nil {
+			continue
+		}
+		for _, arg := range strings.Split(string(cmdline), "\x00") {
+			if isBigPictureFlag(arg) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isBigPictureFlag indica si un argumento de steam es uno de bigPictureFlags
+func isBigPictureFlag(arg string) bool {
+	for _, flag := range bigPictureFlags {
+		if strings.EqualFold(arg, flag) {
+			return true
+		}
+	}
+	return false
+}
+
+/**
+ * GamingWatcher - Sigue si hay una sesión de juego en marcha
+ *
+ * Revisa /proc cada gamingPollInterval y avisa cuando gamescope o Steam
+ * Big Picture arrancan o terminan.
+ *
+ * @struct {GamingWatcher}
+ * @property {chan struct{}} stop - Detiene la goroutine del seguimiento
+ */
+type GamingWatcher struct {
+	stop chan struct{}
+}
+
+/**
+ * StartGamingWatcher - Empieza a seguir las sesiones de juego
+ *
+ * onChange se llama desde la goroutine del seguimiento, primero con la
+ * sesión actual y después con cada cambio.
+ *
+ * @param {func(string)} onChange - Callback con la sesión en marcha (ver DetectGamingSession)
+ * @returns {*GamingWatcher, error} Seguimiento activo; error si no se pueden listar los procesos
+ * @example
+ *   watcher, err := StartGamingWatcher(func(session string) { fmt.Println(session) })
+ *   defer watcher.Stop()
+ */
+func StartGamingWatcher(onChange func(session string)) (*GamingWatcher, error) {
+	if IsFlatpak() {
+		return nil, fmt.Errorf("dentro de Flatpak no se ven los procesos del host")
+	}
+	if _, err := os.Stat("/proc/self/comm"); err != nil {
+		return nil, fmt.Errorf("no se pueden listar los procesos: %w", err)
+	}
+
+	watcher := &GamingWatcher{stop: make(chan struct{})}
+	go func() {
+		session := DetectGamingSession()
+		onChange(session)
+
+		ticker := time.NewTicker(gamingPollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-watcher.stop:
+				return
+			case <-ticker.C:
+				if current := DetectGamingSession(); current != session {
+					session = current
+					onChange(session)
+				}
+			}
+		}
+	}()
+	logging.Println("🎮 Siguiendo gamescope y Steam Big Picture")
+	return watcher, nil
+}
+
+// Stop detiene el seguimiento
+func (w *GamingWatcher) Stop() {
+	close(w.stop)
+}
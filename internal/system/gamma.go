@@ -1,12 +1,18 @@
 package system
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"luznocturna/luz-nocturna/internal/logging"
+	"luznocturna/luz-nocturna/internal/paths"
 	"math"
 	"os"
-	"os/exec"
+	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -19,10 +25,53 @@ import (
  * @struct {GammaManager}
  * @property {[]string} displays - Lista de displays detectados automáticamente
  * @property {string} protocol - Protocolo de display detectado ("x11" o "wayland")
+ * @property {string} compositor - Compositor Wayland detectado (CompositorID, "" en X11)
+ * @property {bool} dryRun - Si es true, solo registra los comandos sin ejecutarlos
+ * @property {*ManagedBackend} managed - Backend gammastep/wlsunset supervisado
+ * @property {string} backend - Método que aplicó la última temperatura
+ * @property {*Capabilities} caps - Caché de herramientas y monitores DDC/CI
+ * @property {*exclusiveMonitor} exclusive - Vigilancia de sistemas competidores
+ * @property {*PluginBackend} plugin - Backend externo elegido (nil si no hay ninguno)
  */
 type GammaManager struct {
-	displays []string
-	protocol string
+	displaysMu sync.Mutex // Protege displays y hdrDisplays: RefreshDisplays llega desde otras goroutines
+	displays   []string
+	protocol   string
+	compositor string
+	dryRun     bool
+	managed    *ManagedBackend
+	caps       *Capabilities
+	exclusive  *exclusiveMonitor
+
+	lockConflict bool // Ya se avisó de que otra instancia tiene el bloqueo
+
+	backendMu sync.Mutex // Protege backend: el worker de la cola lo escribe y el programador lo lee
+	backend   string
+
+	pluginMu sync.Mutex // Protege plugin: RefreshCapabilities lo reemplaza mientras la cola aplica
+	plugin   *PluginBackend
+
+	excludedMu sync.Mutex
+	excluded   map[string]bool // Displays que el filtro no modifica (solo X11)
+
+	hdrDisplays []string // Salidas con HDR activo (solo Wayland)
+
+	contrastMu sync.Mutex
+	contrast   float64 // Contraste extra de las rampas RandR (0 = curva normal)
+
+	trimsMu sync.Mutex
+	trims   map[string][3]float64 // Multiplicadores RGB por display (solo X11)
+
+	desaturationMu sync.Mutex
+	desaturation   float64 // Color que se retira vía la matriz CTM de RandR (0 = color normal, 1 = escala de grises)
+	ctmApplied     bool    // Algún display tiene una matriz de color distinta de la identidad
+	ctmWarned      bool    // Ya se avisó de que la matriz de color no está disponible
+}
+
+// GammaOptions agrupa las opciones de creación del manejador de gamma
+type GammaOptions struct {
+	DryRun   bool   // Registrar comandos en lugar de modificar el display
+	Protocol string // "x11" o "wayland" para no detectarlo ("" detecta)
 }
 
 /**
@@ -37,9 +86,46 @@ type GammaManager struct {
  *   gm.ApplyTemperature(4000) // Aplica 4000K
  */
 func NewGammaManager() *GammaManager {
-	gm := &GammaManager{}
-	gm.detectDisplayProtocol()
+	return NewGammaManagerWithOptions(GammaOptions{})
+}
+
+/**
+ * NewGammaManagerWithOptions - Constructor del manejador de gamma con opciones
+ *
+ * Igual que NewGammaManager, pero permite activar el modo dry-run antes
+ * de que se ejecute cualquier comando que modifique el sistema.
+ *
+ * @param {GammaOptions} opts - Opciones de creación
+ * @returns {*GammaManager} Nueva instancia del manejador de gamma
+ * @example
+ *   gm := NewGammaManagerWithOptions(GammaOptions{DryRun: true})
+ *   gm.ApplyTemperature(4000) // Solo registra los comandos
+ */
+func NewGammaManagerWithOptions(opts GammaOptions) *GammaManager {
+	if opts.DryRun {
+		logging.Println("🧪 Modo dry-run activo: no se modificará el display")
+	}
+	checkSandboxAccess()
+
+	caps := newCapabilities()
+	gm := &GammaManager{
+		dryRun:  opts.DryRun,
+		managed: NewManagedBackend(opts.DryRun, caps),
+		caps:    caps,
+	}
+	gm.exclusive = newExclusiveMonitor(gm)
+	if opts.Protocol == "x11" || opts.Protocol == "wayland" {
+		gm.protocol = opts.Protocol
+		logging.Printf("🖥️  Protocolo forzado: %s\n", gm.protocol)
+	} else {
+		gm.detectDisplayProtocol()
+	}
+	if gm.protocol == "wayland" {
+		gm.compositor = CompositorID()
+		logging.Printf("🧭 Compositor: %s (métodos: %s)\n", DetectCompositor(), strings.Join(waylandStrategy(gm.compositor), " → "))
+	}
 	gm.detectDisplays()
+	gm.plugin = detectPlugin(opts.DryRun)
 	gm.disableSystemNightLight()
 	return gm
 }
@@ -59,15 +145,262 @@ func NewGammaManager() *GammaManager {
  *   }
  */
 func (gm *GammaManager) ApplyTemperature(temperature float64) error {
-	// Convertir temperatura a valores RGB gamma
-	r, g, b := gm.temperatureToRGB(temperature)
+	return gm.ApplyTemperatureWithBrightness(temperature, 1.0)
+}
+
+/**
+ * ApplyTemperatureWithBrightness - Aplica temperatura y atenuación de brillo
+ *
+ * El brillo escala los tres canales gamma por igual, por lo que funciona
+ * con cualquier backend que reciba valores RGB. Valores mayores que 1.0
+ * (modo "boost") aclaran la imagen en X11; los backends de Wayland que
+ * solo aceptan Kelvin los ignoran. En X11 la atenuación (brillo menor
+ * que 1.0) se aplica aparte, como "xrandr --brightness": escala la
+ * salida de forma lineal y baja también los blancos, lo que sirve de
+ * atenuación por software en equipos sin control de retroiluminación.
+ * Si el backend falla (p. ej. el compositor está ocupado con un cambio
+ * de modo) se reintenta con backoff antes de informar del resultado.
+ *
+ * @param {float64} temperature - Temperatura en Kelvin (3000-6500)
+ * @param {float64} brightness - Brillo relativo (0.1-1.3)
+ * @returns {error} Error si no se puede aplicar la temperatura
+ */
+func (gm *GammaManager) ApplyTemperatureWithBrightness(temperature, brightness float64) error {
+	r, g, b := gammaForState(temperature, brightness)
+
+	if gm.dryRun {
+		logging.Printf("🧪 [dry-run] %.0fK → RGB %.3f:%.3f:%.3f (%s)\n", temperature, r, g, b, gm.protocol)
+	}
+
+	// Un backend externo tiene prioridad; si falla se usan los propios
+	if plugin := gm.currentPlugin(); plugin != nil {
+		pluginErr := plugin.Apply(r, g, b, temperature)
+		if pluginErr == nil {
+			gm.setBackend("plugin " + plugin.Name)
+			return nil
+		}
+		logging.Printf("⚠️  El plugin %s falló: %v\n", plugin.Name, pluginErr)
+	}
+
+	err := gm.applyWithRetry(func() error {
+		if gm.protocol == "wayland" {
+			return gm.applyWaylandGamma(r, g, b)
+		}
+		// Aplicar usando X11/xrandr (comportamiento por defecto)
+		return gm.applyX11Gamma(temperature, brightness)
+	})
+
+	if err != nil {
+		entry := logging.WithFields(logging.Fields{"backend": gm.GetBackend(), "temp": temperature, "error": err})
+		var partial *PartialApplyError
+		if errors.As(err, &partial) {
+			entry.Printf("⚠️  Temperatura %.0fK aplicada solo en parte: %v\n", temperature, partial)
+		} else {
+			entry.Printf("❌ No se pudo aplicar %.0fK: %v\n", temperature, err)
+		}
+	}
+	return err
+}
+
+// Reintentos de una aplicación fallida: el compositor rechaza la gamma
+// durante un instante al cambiar de modo o al conectar un monitor
+const (
+	applyMaxAttempts  = 4
+	applyRetryBackoff = 100 * time.Millisecond
+)
+
+/**
+ * applyWithRetry - Repite una aplicación fallida con backoff exponencial
+ *
+ * Espera 100, 200 y 400 ms entre intentos. No se reintenta si falta una
+ * herramienta o si en Wayland ningún método disponible llegó a fallar
+ * (todos faltaban), porque esperar no lo arregla. Los fallos intermedios
+ * no se registran: quien llama informa una sola vez del resultado final,
+ * que en una aplicación parcial indica cuántos intentos se hicieron.
+ *
+ * @param {func() error} apply - Aplicación en el backend del protocolo
+ * @returns {error} Error del último intento (nil si alguno tuvo éxito)
+ * @private
+ */
+func (gm *GammaManager) applyWithRetry(apply func() error) error {
+	backoff := applyRetryBackoff
+	attempt := 1
+	err := apply()
+	for ; err != nil && attempt < applyMaxAttempts && !gm.dryRun && isRetryableApplyError(err); attempt++ {
+		time.Sleep(backoff)
+		backoff *= 2
+		err = apply()
+	}
+
+	var partial *PartialApplyError
+	if errors.As(err, &partial) {
+		partial.Attempts = attempt
+	}
+	if err == nil && attempt > 1 {
+		logging.WithFields(logging.Fields{"backend": gm.GetBackend(), "attempts": attempt}).
+			Printf("🔁 Gamma aplicada al %dº intento\n", attempt)
+	}
+	return err
+}
+
+// isRetryableApplyError indica si un fallo puede ser pasajero (display ocupado) y merece otro intento
+func isRetryableApplyError(err error) bool {
+	var missing *MissingToolError
+	var wayland *WaylandApplyError
+	if errors.As(err, &wayland) {
+		return wayland.Transient
+	}
+	return !errors.As(err, &missing)
+}
+
+// maxGammaBoost es el tope de seguridad del modo "boost" (igual que models.MaxBrightness)
+const maxGammaBoost = 1.3
 
+// gammaForState convierte temperatura y brillo en los valores gamma RGB del backend
+func gammaForState(temperature, brightness float64) (r, g, b float64) {
+	r, g, b = TemperatureToRGB(temperature)
+	if brightness > 0 && brightness != 1.0 {
+		brightness = math.Max(0.1, math.Min(brightness, maxGammaBoost))
+		r, g, b = r*brightness, g*brightness, b*brightness
+	}
+	return r, g, b
+}
+
+/**
+ * x11GammaForState - Separa la temperatura y la atenuación para X11
+ *
+ * Con brillo menor que 1.0 los factores gamma solo llevan la temperatura
+ * y la atenuación se devuelve aparte para "xrandr --brightness" (o para
+ * escalar las rampas RandR). El modo "boost" sigue en los factores gamma,
+ * que aclaran los tonos medios sin recortar los blancos.
+ *
+ * @param {float64} temperature - Temperatura en Kelvin
+ * @param {float64} brightness - Brillo relativo (0.1-1.3)
+ * @returns {[3]float64, float64} Factores gamma RGB y atenuación lineal (1.0 = sin atenuar)
+ * @private
+ */
+func x11GammaForState(temperature, brightness float64) ([3]float64, float64) {
+	if brightness > 0 && brightness < 1.0 {
+		r, g, b := TemperatureToRGB(temperature)
+		return [3]float64{r, g, b}, math.Max(0.1, brightness)
+	}
+	r, g, b := gammaForState(temperature, brightness)
+	return [3]float64{r, g, b}, 1.0
+}
+
+/**
+ * BackendGamma - Valores que recibe el backend para una temperatura y un brillo
+ *
+ * Es la misma conversión que usa la aplicación del filtro: en X11 la
+ * atenuación va aparte (x11GammaForState) y en Wayland está incluida en
+ * los factores. No incluye el ajuste fino de cada display.
+ *
+ * @param {string} protocol - "x11" o "wayland" (GetProtocol)
+ * @param {float64} temperature - Temperatura en Kelvin
+ * @param {float64} brightness - Brillo relativo (0.1-1.3)
+ * @returns {[3]float64, float64} Factores gamma RGB y atenuación lineal (1.0 = sin atenuar)
+ */
+func BackendGamma(protocol string, temperature, brightness float64) ([3]float64, float64) {
+	if protocol != "wayland" {
+		return x11GammaForState(temperature, brightness)
+	}
+	r, g, b := gammaForState(temperature, brightness)
+	return [3]float64{r, g, b}, 1.0
+}
+
+/**
+ * VerifyGamma - Comprueba si la gamma indicada sigue aplicada en el display
+ *
+ * En X11 lee la gamma actual con "xrandr --verbose" y la compara con la
+ * esperada en cada display. xrandr separa el brillo de la gamma al
+ * leerla, así que se compara solo la parte de x11GammaForState que va en
+ * los factores gamma. Wayland no permite leer la gamma: con el backend
+ * supervisado basta con que el proceso siga vivo, y con el resto (la luz
+ * nocturna del compositor, DDC/CI...) no hay forma de comprobarla y se
+ * da por buena, para no reaplicarla en cada revisión.
+ *
+ * @param {float64} temperature - Temperatura que debería estar aplicada
+ * @param {float64} brightness - Brillo que debería estar aplicado
+ * @returns {bool} true si la gamma coincide con la esperada
+ * @returns {error} Error si no se pudo leer la gamma actual
+ */
+func (gm *GammaManager) VerifyGamma(temperature, brightness float64) (bool, error) {
+	backend := gm.GetBackend()
+	if gm.dryRun || strings.HasPrefix(backend, "plugin ") {
+		return true, nil // Un plugin no permite leer la gamma
+	}
 	if gm.protocol == "wayland" {
-		return gm.applyWaylandGamma(r, g, b)
+		if backend != methodManaged {
+			return true, nil // Sin forma de leerla: nada que reaplicar
+		}
+		return gm.managed.IsRunning(), nil
+	}
+
+	output, err := toolCommand("xrandr", "--verbose").Output()
+	if err != nil {
+		return false, fmt.Errorf("no se pudo leer la gamma con xrandr: %w", err)
+	}
+
+	expected, _ := x11GammaForState(temperature, brightness)
+	current := parseXrandrGamma(string(output))
+	targets, _ := gm.splitExcluded()
+	trims := gm.getChannelTrims()
+	for _, display := range targets {
+		values, ok := current[display]
+		if !ok {
+			continue // Display desconectado desde la última detección
+		}
+		if !gammaMatches(values, trimGamma(expected, trims[display])) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// xrandrGammaRegex captura la línea "Gamma: r:g:b" de xrandr --verbose
+var xrandrGammaRegex = regexp.MustCompile(`^\s+Gamma:\s+([\d.]+):([\d.]+):([\d.]+)`)
+
+// parseXrandrGamma devuelve la gamma de cada display conectado según xrandr --verbose
+func parseXrandrGamma(output string) map[string][3]float64 {
+	connectedRegex := regexp.MustCompile(`^(\S+)\s+connected`)
+	result := make(map[string][3]float64)
+
+	display := ""
+	for _, line := range strings.Split(output, "\n") {
+		if matches := connectedRegex.FindStringSubmatch(line); matches != nil {
+			display = matches[1]
+			continue
+		}
+		if display == "" {
+			continue
+		}
+		if matches := xrandrGammaRegex.FindStringSubmatch(line); matches != nil {
+			var values [3]float64
+			for i := range values {
+				values[i], _ = strconv.ParseFloat(matches[i+1], 64)
+			}
+			result[display] = values
+			display = ""
+		}
+	}
+	return result
+}
+
+// gammaMatches compara la gamma leída con la esperada. Algunas versiones de
+// xrandr muestran el inverso del valor aplicado, así que se aceptan ambos.
+// xrandr redondea a dos cifras significativas, de ahí la tolerancia relativa.
+func gammaMatches(current, expected [3]float64) bool {
+	const tolerance = 0.04
+	near := func(value, want float64) bool {
+		return want > 0 && math.Abs(value-want)/want <= tolerance
 	}
 
-	// Aplicar usando X11/xrandr (comportamiento por defecto)
-	return gm.applyX11Gamma(r, g, b, temperature)
+	direct, inverse := true, true
+	for i := range current {
+		direct = direct && near(current[i], expected[i])
+		inverse = inverse && expected[i] > 0 && near(current[i], 1/expected[i])
+	}
+	return direct || inverse
 }
 
 /**
@@ -84,20 +417,37 @@ func (gm *GammaManager) ApplyTemperature(temperature float64) error {
  *   }
  */
 func (gm *GammaManager) Reset() error {
+	if plugin := gm.currentPlugin(); plugin != nil {
+		err := plugin.Reset()
+		if err == nil {
+			logging.Println("✅ Gamma reseteada a valores normales")
+			return nil
+		}
+		logging.Printf("⚠️  El plugin %s no pudo resetear la gamma: %v\n", plugin.Name, err)
+	}
+
 	if gm.protocol == "wayland" {
 		return gm.resetWaylandGamma()
 	}
 
-	// Reset usando X11/xrandr
-	for _, display := range gm.displays {
-		cmd := exec.Command("xrandr", "--output", display, "--gamma", "1.0:1.0:1.0")
-		if err := cmd.Run(); err != nil {
-			fmt.Printf("⚠️  Advertencia: no se pudo resetear gamma en %s: %v\n", display, err)
-			continue
-		}
+	// Reset con rampas identidad vía RandR; si no, xrandr con todos los displays en una sola llamada
+	var applied []string
+	var err error
+	if !gm.dryRun {
+		applied, _, err = applyRandRRamps(gm.GetDisplays(), nil, [3]float64{1.0, 1.0, 1.0}, nil, 0, 1.0)
+	}
+	if gm.dryRun || err != nil {
+		applied = gm.runXrandrGamma(gm.GetDisplays(), "1.0:1.0:1.0", 1.0)
 	}
+	gm.resetDesaturation()
 
-	fmt.Println("✅ Gamma reseteada a valores normales")
+	if failed := missingDisplays(gm.GetDisplays(), applied); len(failed) > 0 {
+		if len(applied) == 0 {
+			return fmt.Errorf("no se pudo resetear la gamma en %s", strings.Join(failed, ", "))
+		}
+		return &PartialApplyError{Applied: applied, Failed: failed}
+	}
+	logging.Println("✅ Gamma reseteada a valores normales")
 	return nil
 }
 
@@ -110,14 +460,15 @@ func (gm *GammaManager) Reset() error {
  * @private
  */
 func (gm *GammaManager) detectDisplayProtocol() {
-	// Verificar variables de entorno
+	gm.protocol = displayProtocolFromEnv()
+}
+
+// displayProtocolFromEnv devuelve "wayland" si las variables de la sesión lo indican y "x11" en otro caso
+func displayProtocolFromEnv() string {
 	if os.Getenv("WAYLAND_DISPLAY") != "" || os.Getenv("XDG_SESSION_TYPE") == "wayland" {
-		gm.protocol = "wayland"
-		return
+		return "wayland"
 	}
-
-	// Por defecto asumir X11
-	gm.protocol = "x11"
+	return "x11"
 }
 
 /**
@@ -131,16 +482,17 @@ func (gm *GammaManager) detectDisplayProtocol() {
 func (gm *GammaManager) detectDisplays() {
 	if gm.protocol == "wayland" {
 		gm.detectWaylandDisplays()
+		gm.detectHDR()
 		return
 	}
 
 	// Detectar displays X11 usando xrandr
-	cmd := exec.Command("xrandr")
+	cmd := toolCommand("xrandr")
 	output, err := cmd.Output()
 	if err != nil {
 		// Fallback a display común
-		gm.displays = []string{"eDP-1"}
-		fmt.Printf("⚠️  No se pudo ejecutar xrandr, usando display por defecto: eDP-1\n")
+		gm.setDisplays([]string{"eDP-1"})
+		logging.Printf("⚠️  No se pudo ejecutar xrandr, usando display por defecto: eDP-1\n")
 		return
 	}
 
@@ -160,39 +512,318 @@ func (gm *GammaManager) detectDisplays() {
 		displays = []string{"eDP-1"}
 	}
 
-	gm.displays = displays
-	fmt.Printf("🖥️  Displays detectados (%s): %v\n", gm.protocol, displays)
+	gm.setDisplays(displays)
+	logging.Printf("🖥️  Displays detectados (%s): %v\n", gm.protocol, displays)
 }
 
 /**
- * applyX11Gamma - Aplica gamma usando xrandr (X11)
+ * applyX11Gamma - Aplica gamma y atenuación usando xrandr (X11)
  *
- * @param {float64} r - Componente rojo del gamma (0.3-1.0)
- * @param {float64} g - Componente verde del gamma (0.3-1.0)
- * @param {float64} b - Componente azul del gamma (0.3-1.0)
- * @param {float64} temperature - Temperatura original para logging
+ * @param {float64} temperature - Temperatura en Kelvin
+ * @param {float64} brightness - Brillo relativo (0.1-1.3); por debajo de 1.0 va como "--brightness"
  * @returns {error} Error si falla la aplicación
  * @private
  */
-func (gm *GammaManager) applyX11Gamma(r, g, b, temperature float64) error {
-	for _, display := range gm.displays {
-		cmd := exec.Command("xrandr", "--output", display, "--gamma", fmt.Sprintf("%.2f:%.2f:%.2f", r, g, b))
-		if err := cmd.Run(); err != nil {
-			// Si falla un display, continúa con los otros
-			fmt.Printf("⚠️  Advertencia: no se pudo aplicar gamma a %s: %v\n", display, err)
-			continue
+func (gm *GammaManager) applyX11Gamma(temperature, brightness float64) error {
+	targets, excluded := gm.splitExcluded()
+	gamma, dim := x11GammaForState(temperature, brightness)
+	r, g, b := gamma[0], gamma[1], gamma[2]
+
+	// Rampas completas vía RandR: sin redondeo y con toda la LUT del hardware
+	if !gm.dryRun {
+		applied, lutSize, err := applyRandRRamps(targets, excluded, gamma, gm.getChannelTrims(), gm.getContrast(), dim)
+		if err == nil {
+			gm.setBackend("randr")
+			logging.WithFields(logging.Fields{"backend": gm.GetBackend(), "display": applied, "temp": temperature, "lut": lutSize}).
+				Printf("🌡️  Temperatura aplicada: %.0fK (rampas de %d entradas, RGB: %.3f:%.3f:%.3f)\n", temperature, lutSize, r, g, b)
+			gm.applyDesaturation(applied, excluded)
+			if len(applied) < len(targets) {
+				return &PartialApplyError{Applied: applied, Failed: missingDisplays(targets, applied)}
+			}
+			return nil
 		}
+		logging.Printf("🧪 Rampas RandR no disponibles, se usa xrandr --gamma: %v\n", err)
+	}
+
+	if !gm.dryRun && !gm.isToolAvailable("xrandr") {
+		return newMissingToolError("xrandr")
 	}
 
-	fmt.Printf("🌡️  Temperatura aplicada: %.0fK (RGB: %.2f:%.2f:%.2f)\n", temperature, r, g, b)
+	// Los displays excluidos vuelven a la gamma normal por si tenían el filtro
+	if len(excluded) > 0 {
+		gm.runXrandrGamma(excluded, "1.0:1.0:1.0", 1.0)
+	}
+	if len(targets) == 0 {
+		gm.setBackend("xrandr")
+		logging.Println("🖥️  Todos los displays están excluidos del filtro")
+		return nil
+	}
+
+	// Una llamada por cada gamma distinta: los displays con ajuste fino llevan la suya
+	trims := gm.getChannelTrims()
+	groups := make(map[string][]string)
+	var order []string
+	for _, display := range targets {
+		trimmed := trimGamma(gamma, trims[display])
+		value := fmt.Sprintf("%.2f:%.2f:%.2f", trimmed[0], trimmed[1], trimmed[2])
+		if _, ok := groups[value]; !ok {
+			order = append(order, value)
+		}
+		groups[value] = append(groups[value], display)
+	}
+	var applied []string
+	for _, value := range order {
+		applied = append(applied, gm.runXrandrGamma(groups[value], value, dim)...)
+	}
+	if len(applied) == 0 {
+		return fmt.Errorf("%w: xrandr falló en todos los displays", ErrNoBackend)
+	}
+
+	gm.setBackend("xrandr")
+	logging.WithFields(logging.Fields{"backend": gm.GetBackend(), "display": applied, "temp": temperature}).
+		Printf("🌡️  Temperatura aplicada: %.0fK (RGB: %.2f:%.2f:%.2f, brillo %.2f)\n", temperature, r, g, b, dim)
+
+	if len(applied) < len(targets) {
+		return &PartialApplyError{Applied: applied, Failed: missingDisplays(targets, applied)}
+	}
 	return nil
 }
 
 /**
- * applyWaylandGamma - Aplica gamma usando overlays de color efectivos para Wayland
+ * SetExcludedDisplays - Define qué displays no recibe el filtro
+ *
+ * Solo tiene efecto en X11: en Wayland la gamma se controla de forma
+ * global a través del compositor.
+ *
+ * @param {[]string} displays - Nombres de las salidas a excluir
+ */
+func (gm *GammaManager) SetExcludedDisplays(displays []string) {
+	excluded := make(map[string]bool, len(displays))
+	for _, display := range displays {
+		excluded[display] = true
+	}
+
+	gm.excludedMu.Lock()
+	gm.excluded = excluded
+	gm.excludedMu.Unlock()
+}
+
+/**
+ * SetContrast - Define el contraste extra de las próximas aplicaciones
+ *
+ * Solo lo aplican las rampas RandR de X11, que permiten cambiar la forma
+ * de la curva; "xrandr --gamma", Wayland y los plugins solo reciben un
+ * factor por canal y lo ignoran.
+ *
+ * @param {float64} contrast - Contraste extra (0 = curva normal)
+ */
+func (gm *GammaManager) SetContrast(contrast float64) {
+	gm.contrastMu.Lock()
+	gm.contrast = contrast
+	gm.contrastMu.Unlock()
+}
+
+// getContrast devuelve el contraste extra en vigor
+func (gm *GammaManager) getContrast() float64 {
+	gm.contrastMu.Lock()
+	defer gm.contrastMu.Unlock()
+	return gm.contrast
+}
+
+/**
+ * SetChannelTrims - Define el ajuste fino RGB de cada display
+ *
+ * Los multiplicadores se aplican sobre los factores de la temperatura en
+ * las próximas aplicaciones. Solo en X11: en Wayland la gamma es la
+ * misma para todos los displays y el ajuste se ignora.
+ *
+ * @param {map[string][3]float64} trims - Multiplicadores RGB por nombre de display
+ */
+func (gm *GammaManager) SetChannelTrims(trims map[string][3]float64) {
+	copied := make(map[string][3]float64, len(trims))
+	for display, factors := range trims {
+		copied[display] = factors
+	}
+
+	gm.trimsMu.Lock()
+	gm.trims = copied
+	gm.trimsMu.Unlock()
+}
+
+// getChannelTrims devuelve el ajuste fino en vigor (no se modifica: SetChannelTrims lo reemplaza)
+func (gm *GammaManager) getChannelTrims() map[string][3]float64 {
+	gm.trimsMu.Lock()
+	defer gm.trimsMu.Unlock()
+	return gm.trims
+}
+
+// trimGamma multiplica los factores gamma por el ajuste fino de un display (sin ajuste si factors es cero)
+func trimGamma(gamma, factors [3]float64) [3]float64 {
+	if factors == [3]float64{} {
+		return gamma
+	}
+	return [3]float64{gamma[0] * factors[0], gamma[1] * factors[1], gamma[2] * factors[2]}
+}
+
+/**
+ * SetDesaturation - Define cuánto color retiran las próximas aplicaciones
+ *
+ * Solo lo aplican las salidas de X11 cuyo driver expone la matriz de
+ * color (propiedad CTM de RandR): las rampas gamma de cada canal no
+ * pueden mezclar colores, así que en Wayland, con "xrandr --gamma" y en
+ * los plugins se ignora.
+ *
+ * @param {float64} desaturation - 0 = color normal, 1 = escala de grises
+ */
+func (gm *GammaManager) SetDesaturation(desaturation float64) {
+	gm.desaturationMu.Lock()
+	gm.desaturation = math.Max(0, math.Min(desaturation, 1))
+	gm.desaturationMu.Unlock()
+}
+
+/**
+ * applyDesaturation - Sube la matriz de color que corresponde a la desaturación en vigor
+ *
+ * Con color normal solo toca las salidas si antes se desaturaron, para
+ * no reescribir la propiedad CTM en cada aplicación.
+ *
+ * @param {[]string} targets - Displays con el filtro
+ * @param {[]string} excluded - Displays excluidos del filtro
+ * @private
+ */
+func (gm *GammaManager) applyDesaturation(targets, excluded []string) {
+	gm.desaturationMu.Lock()
+	defer gm.desaturationMu.Unlock()
+
+	desaturation := gm.desaturation
+	if desaturation == 0 && !gm.ctmApplied {
+		return
+	}
+
+	applied, err := applyRandRCTM(targets, excluded, 1-desaturation)
+	if err != nil {
+		if desaturation > 0 && !gm.ctmWarned {
+			logging.Printf("⚠️  No se puede desaturar la pantalla: %v\n", err)
+			gm.ctmWarned = true
+		}
+		return
+	}
+	switch {
+	case desaturation > 0 && !gm.ctmApplied:
+		logging.WithFields(logging.Fields{"display": applied}).
+			Printf("🩶 Pantalla desaturada (%.0f%% del color)\n", (1-desaturation)*100)
+	case desaturation == 0:
+		logging.Println("🎨 Color restaurado")
+	}
+	gm.ctmApplied = desaturation > 0 && len(applied) > 0
+}
+
+// resetDesaturation devuelve la matriz identidad a todos los displays si alguno estaba desaturado
+func (gm *GammaManager) resetDesaturation() {
+	gm.desaturationMu.Lock()
+	defer gm.desaturationMu.Unlock()
+	if !gm.ctmApplied {
+		return
+	}
+	if _, err := applyRandRCTM(gm.GetDisplays(), nil, 1); err != nil {
+		logging.Printf("⚠️  No se pudo restaurar el color: %v\n", err)
+		return
+	}
+	gm.ctmApplied = false
+}
+
+// splitExcluded separa los displays detectados en los que reciben el filtro y los excluidos
+func (gm *GammaManager) splitExcluded() (targets, excluded []string) {
+	gm.excludedMu.Lock()
+	defer gm.excludedMu.Unlock()
+
+	for _, display := range gm.GetDisplays() {
+		if gm.excluded[display] {
+			excluded = append(excluded, display)
+		} else {
+			targets = append(targets, display)
+		}
+	}
+	return targets, excluded
+}
+
+// missingDisplays devuelve los displays de all que no están en applied
+func missingDisplays(all, applied []string) []string {
+	var missing []string
+	for _, display := range all {
+		found := false
+		for _, ok := range applied {
+			if ok == display {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, display)
+		}
+	}
+	return missing
+}
+
+/**
+ * runXrandrGamma - Aplica el mismo gamma y brillo a varios displays con una sola llamada a xrandr
+ *
+ * Encadena "--output A --gamma ... --brightness ... --output B ..." para que todos
+ * los monitores cambien a la vez, sin el escalonado visible de una
+ * llamada por display. Si la llamada conjunta falla (p. ej. un display
+ * desconectado) se reintenta display por display para aplicar al menos
+ * los que funcionan, avisando de cada display que falla con lo que
+ * xrandr escribió en stderr.
+ *
+ * @param {[]string} displays - Nombres de las salidas de xrandr
+ * @param {string} gamma - Valor de gamma "r:g:b"
+ * @param {float64} brightness - Atenuación lineal de "--brightness" (1.0 = sin atenuar)
+ * @returns {[]string} Displays en los que se aplicó el gamma
+ * @private
+ */
+func (gm *GammaManager) runXrandrGamma(displays []string, gamma string, brightness float64) []string {
+	if len(displays) == 0 {
+		return nil
+	}
+
+	level := fmt.Sprintf("%.2f", brightness)
+	args := make([]string, 0, len(displays)*6)
+	for _, display := range displays {
+		args = append(args, "--output", display, "--gamma", gamma, "--brightness", level)
+	}
+	err := gm.runCommand("xrandr", args...)
+	if err == nil {
+		return displays
+	}
+	if len(displays) == 1 {
+		logging.WithFields(logging.Fields{"backend": "xrandr", "display": displays[0], "error": err}).
+			Printf("⚠️  Advertencia: no se pudo aplicar gamma a %s: %v\n", displays[0], err)
+		return nil
+	}
+
+	// Si falla un display, continúa con los otros
+	var applied []string
+	for _, display := range displays {
+		if err := gm.runCommand("xrandr", "--output", display, "--gamma", gamma, "--brightness", level); err != nil {
+			logging.WithFields(logging.Fields{"backend": "xrandr", "display": display, "error": err}).
+				Printf("⚠️  Advertencia: no se pudo aplicar gamma a %s: %v\n", display, err)
+			continue
+		}
+		applied = append(applied, display)
+	}
+	return applied
+}
+
+/**
+ * applyWaylandGamma - Aplica gamma con los métodos que admite el compositor
+ *
+ * Recorre la estrategia de compositorStrategies para el compositor
+ * detectado (la luz nocturna de Mutter o KWin, gammastep/wlsunset en
+ * wlroots...); si no se reconoce el compositor se prueban todos.
  *
- * Implementa métodos más agresivos que realmente funcionen en Wayland
- * incluyendo overlays de color y filtros visuales.
+ * En dry-run los comandos no fallan, así que cada método se descarta si
+ * no está disponible (ver dryRunUnavailable) y se registra el orden, los
+ * métodos descartados y el elegido.
  *
  * @param {float64} r - Componente rojo del gamma (0.3-1.0)
  * @param {float64} g - Componente verde del gamma (0.3-1.0)
@@ -200,47 +831,199 @@ func (gm *GammaManager) applyX11Gamma(r, g, b, temperature float64) error {
  * @returns {error} Error si falla la aplicación
  * @private
  */
-func (gm *GammaManager) applyWaylandGamma(r, g, b float64) error {
+func (gm *GammaManager) applyWaylandGamma(r, g, b float64) (err error) {
 	// Deshabilitar sistema nativo antes de aplicar
 	gm.disableSystemNightLight()
 
 	// Calcular temperatura para métodos que la requieren
-	temp := gm.rgbToTemperature(r, g, b)
+	temp := RGBToTemperature(r, g, b)
 
-	// 1. Método más agresivo: Forzar gamma usando compositor
-	if gm.tryCompositorOverride(r, g, b, temp) {
-		return nil
+	// Si gana otro método, el proceso supervisado no debe seguir compitiendo
+	usedManaged := false
+	defer func() {
+		if !usedManaged {
+			gm.managed.Stop()
+		}
+	}()
+
+	// Con HDR activo solo la luz nocturna del compositor respeta los colores
+	if len(gm.GetHDRDisplays()) > 0 {
+		return gm.applyHDRSafeGamma(temp)
 	}
 
-	// 2. Método compositor específico: GNOME Mutter
-	if gm.tryGnomeMutterMethod(temp) {
-		return nil
+	// Motivo de cada método que falla, para el diálogo de ayuda. Si alguno
+	// estaba disponible y aun así falló, el compositor puede estar ocupado
+	var attempts []MethodAttempt
+	transient := false
+	failed := func(method string, tools ...string) {
+		attempts = append(attempts, MethodAttempt{Method: method, Reason: gm.failureReason(tools...)})
+		for _, tool := range tools {
+			transient = transient || gm.isToolAvailable(tool)
+		}
 	}
 
-	// 3. Método compositor específico: KDE KWin
-	if gm.tryKWinMethod(temp) {
-		return nil
+	// Solo los métodos que funcionan en este compositor, en su orden
+	strategy := waylandStrategy(gm.compositor)
+	var busNames []string
+	if gm.dryRun {
+		busNames = sessionBusNames()
+		logging.Printf("🧪 [dry-run] métodos de Wayland en orden: %s\n", strings.Join(strategy, " → "))
+		defer func() {
+			for _, attempt := range attempts {
+				logging.Printf("🧪 [dry-run] descartado %s: %s\n", attempt.Method, attempt.Reason)
+			}
+			if err == nil {
+				logging.Printf("🧪 [dry-run] método elegido: %s\n", gm.GetBackend())
+			}
+		}()
+	}
+	for _, method := range strategy {
+		if gm.dryRun {
+			if reason := dryRunUnavailable(method, busNames, gm.isToolAvailable); reason != "" {
+				attempts = append(attempts, MethodAttempt{Method: method, Reason: reason})
+				continue
+			}
+		}
+		switch method {
+		case methodCompositor:
+			if gm.tryCompositorOverride(r, g, b, temp) {
+				gm.setBackend("compositor")
+				return nil
+			}
+			failed(method, "wlr-gamma-control", "swaybg")
+		case methodMutter:
+			if gm.tryGnomeMutterMethod(temp) {
+				gm.setBackend(method)
+				return nil
+			}
+			failed(method, "gdbus")
+		case methodKWin:
+			kwinErr := gm.tryKWinMethod(temp)
+			if kwinErr == nil {
+				gm.setBackend(method)
+				return nil
+			}
+			attempts = append(attempts, MethodAttempt{Method: method, Reason: kwinErr.Error()})
+		case methodManaged:
+			// Backend supervisado: gammastep/wlsunset como proceso hijo
+			managedErr := gm.tryManagedMethod(temp)
+			if managedErr == nil {
+				usedManaged = true
+				gm.setBackend(method)
+				return nil
+			}
+			attempts = append(attempts, MethodAttempt{Method: method, Reason: managedErr.Error()})
+			transient = transient || gm.isToolAvailable("gammastep") || gm.isToolAvailable("wlsunset")
+		case methodDDC:
+			// Control directo del monitor
+			if gm.tryDDCMethod(r, g, b) {
+				gm.setBackend(method)
+				return nil
+			}
+			if gm.isToolAvailable("ddcutil") && len(gm.caps.DDCBuses()) == 0 {
+				attempts = append(attempts, MethodAttempt{Method: method, Reason: "no se detectaron monitores con DDC/CI"})
+			} else {
+				failed(method, "ddcutil")
+			}
+		case methodOverlay:
+			if gm.tryColorOverlayMethod(r, g, b) {
+				gm.setBackend(method)
+				return nil
+			}
+			failed(method, "xsetroot")
+		case methodXWayland:
+			if gm.tryXWaylandMethod(r, g, b) {
+				logging.Printf("⚠️  Usando XWayland (puede no ser efectivo en Wayland nativo)\n")
+				gm.setBackend(method)
+				return nil
+			}
+			failed(method, "xrandr")
+		}
 	}
 
-	// 4. Método DDC/CI para control directo del monitor
-	if gm.tryDDCMethod(r, g, b) {
-		return nil
+	compositor := DetectCompositor()
+	return &WaylandApplyError{
+		Compositor:  compositor,
+		Attempts:    attempts,
+		Suggestions: gm.waylandSuggestions(compositor),
+		Install:     InstallCommand(DetectPackageManager(), gm.missingWaylandTools(compositor)...),
+		Transient:   transient,
 	}
+}
 
-	// 5. Método overlay de color usando herramientas gráficas
-	if gm.tryColorOverlayMethod(r, g, b) {
-		return nil
+/**
+ * dryRunUnavailable - Motivo por el que un método de Wayland no funcionaría
+ *
+ * Solo cubre los métodos que en dry-run no comprueban nada antes de
+ * "ejecutar": la luz nocturna de Mutter y de KWin necesitan su servicio
+ * en el bus de sesión. El resto ya mira si su herramienta está instalada.
+ *
+ * @param {string} method - Método de la estrategia (methodMutter, methodKWin...)
+ * @param {[]string} busNames - Nombres registrados en el bus de sesión
+ * @param {func(string) bool} hasTool - Indica si una herramienta está disponible
+ * @returns {string} Motivo ("" si el método está disponible)
+ * @private
+ */
+func dryRunUnavailable(method string, busNames []string, hasTool func(string) bool) string {
+	onBus := func(name string) bool {
+		for _, busName := range busNames {
+			if busName == name {
+				return true
+			}
+		}
+		return false
+	}
+
+	switch method {
+	case methodMutter:
+		if !hasTool("gdbus") || !hasTool("gsettings") {
+			return "faltan gdbus o gsettings"
+		}
+		if !onBus("org.gnome.SettingsDaemon.Color") {
+			return "org.gnome.SettingsDaemon.Color no está en el bus de sesión"
+		}
+	case methodKWin:
+		if !onBus("org.kde.KWin") {
+			return "org.kde.KWin no está en el bus de sesión"
+		}
 	}
+	return ""
+}
 
-	// 6. Fallback: XWayland si está disponible
-	if gm.tryXWaylandMethod(r, g, b) {
-		fmt.Printf("⚠️  Usando XWayland (puede no ser efectivo en Wayland nativo)\n")
+/**
+ * applyHDRSafeGamma - Aplica la temperatura solo con la API nativa del compositor
+ *
+ * Las rampas gamma externas (wlr-gamma-control, gammastep, DDC...) se
+ * aplican sobre la señal HDR ya codificada y producen colores rotos; la
+ * luz nocturna de Mutter o KWin se aplica dentro de su gestión de color.
+ *
+ * @param {float64} temp - Temperatura en Kelvin
+ * @returns {error} WaylandApplyError si el compositor no ofrece luz nocturna
+ * @private
+ */
+func (gm *GammaManager) applyHDRSafeGamma(temp float64) error {
+	if gm.tryGnomeMutterMethod(temp) {
+		gm.setBackend(methodMutter)
+		return nil
+	}
+	kwinErr := gm.tryKWinMethod(temp)
+	if kwinErr == nil {
+		gm.setBackend(methodKWin)
 		return nil
 	}
 
-	return fmt.Errorf("no se pudo aplicar gamma en Wayland.\n" +
-		"Métodos intentados: compositor override, GNOME, KDE, DDC/CI, overlay, XWayland\n" +
-		"Tu compositor Wayland puede no soportar control de gamma")
+	return &WaylandApplyError{
+		Compositor: DetectCompositor(),
+		Attempts: []MethodAttempt{
+			{Method: methodMutter, Reason: gm.failureReason("gdbus")},
+			{Method: methodKWin, Reason: kwinErr.Error()},
+			{Method: "gamma externa", Reason: fmt.Sprintf("omitida: HDR activo en %s", strings.Join(gm.GetHDRDisplays(), ", "))},
+		},
+		Suggestions: []string{
+			"Usa GNOME Night Light o KDE Night Color: aplican la temperatura respetando el HDR",
+			"O desactiva HDR en la configuración de pantallas para usar gammastep, wlsunset o DDC/CI",
+		},
+	}
 }
 
 /**
@@ -249,28 +1032,26 @@ func (gm *GammaManager) applyWaylandGamma(r, g, b float64) error {
 func (gm *GammaManager) tryCompositorOverride(r, g, b, temp float64) bool {
 	// 1. Intentar con wlr-gamma-control más agresivo
 	if gm.isToolAvailable("wlr-gamma-control") {
-		cmd := exec.Command("wlr-gamma-control", fmt.Sprintf("%.2f", r), fmt.Sprintf("%.2f", g), fmt.Sprintf("%.2f", b))
-		if err := cmd.Run(); err == nil {
-			fmt.Printf("🌡️  Gamma aplicada en Wayland (wlr-gamma-control): %.2f:%.2f:%.2f\n", r, g, b)
+		if err := gm.runCommand("wlr-gamma-control", fmt.Sprintf("%.2f", r), fmt.Sprintf("%.2f", g), fmt.Sprintf("%.2f", b)); err == nil {
+			logging.Printf("🌡️  Gamma aplicada en Wayland (wlr-gamma-control): %.2f:%.2f:%.2f\n", r, g, b)
 			return true
 		}
 	}
 
 	// 2. Crear archivo temporal de configuración de gamma
-	configPath := "/tmp/luz-nocturna-gamma.conf"
+	configPath := filepath.Join(paths.RuntimeDir(), "gamma.conf")
 	configContent := fmt.Sprintf(`
 [output:*]
 gamma = %.2f:%.2f:%.2f
 temperature = %.0f
 `, r, g, b, temp)
 
-	if err := os.WriteFile(configPath, []byte(configContent), 0644); err == nil {
+	if err := gm.writeFile(configPath, []byte(configContent), 0644); err == nil {
 		// Intentar aplicar con swaybg si está disponible
 		if gm.isToolAvailable("swaybg") {
-			cmd := exec.Command("swaybg", "-c", fmt.Sprintf("#%02x%02x%02x",
-				int(255*r), int(255*g), int(255*b)))
-			if err := cmd.Start(); err == nil {
-				fmt.Printf("🌡️  Overlay de color aplicado en Wayland (swaybg): %.2f:%.2f:%.2f\n", r, g, b)
+			if err := gm.startCommand("swaybg", "-c", fmt.Sprintf("#%02x%02x%02x",
+				int(255*r), int(255*g), int(255*b))); err == nil {
+				logging.Printf("🌡️  Overlay de color aplicado en Wayland (swaybg): %.2f:%.2f:%.2f\n", r, g, b)
 				return true
 			}
 		}
@@ -288,19 +1069,18 @@ func (gm *GammaManager) tryGnomeMutterMethod(temp float64) bool {
 	}
 
 	// Forzar habilitación temporal del Night Light para controlarlo
-	exec.Command("gsettings", "set", "org.gnome.settings-daemon.plugins.color", "night-light-enabled", "true").Run()
+	gm.runCommand("gsettings", "set", "org.gnome.settings-daemon.plugins.color", "night-light-enabled", "true")
 	time.Sleep(100 * time.Millisecond)
 
 	// Configurar temperatura específica
-	cmd := exec.Command("gsettings", "set", "org.gnome.settings-daemon.plugins.color", "night-light-temperature", fmt.Sprintf("uint32:%.0f", temp))
-	if err := cmd.Run(); err == nil {
+	if err := gm.runCommand("gsettings", "set", "org.gnome.settings-daemon.plugins.color", "night-light-temperature", fmt.Sprintf("uint32:%.0f", temp)); err == nil {
 		// Forzar aplicación inmediata via D-Bus
-		exec.Command("gdbus", "call", "--session", "--dest", "org.gnome.SettingsDaemon.Color",
+		gm.runCommand("gdbus", "call", "--session", "--dest", "org.gnome.SettingsDaemon.Color",
 			"--object-path", "/org/gnome/SettingsDaemon/Color",
 			"--method", "org.gnome.SettingsDaemon.Color.NightLightPreview",
-			fmt.Sprintf("uint32:%.0f", temp)).Run()
+			fmt.Sprintf("uint32:%.0f", temp))
 
-		fmt.Printf("🌡️  Temperatura aplicada en Wayland (GNOME Mutter): %.0fK\n", temp)
+		logging.Printf("🌡️  Temperatura aplicada en Wayland (GNOME Mutter): %.0fK\n", temp)
 		return true
 	}
 	return false
@@ -308,23 +1088,33 @@ func (gm *GammaManager) tryGnomeMutterMethod(temp float64) bool {
 
 /**
  * tryKWinMethod - Método específico para KDE KWin
+ *
+ * Activa Night Color y fija la temperatura por D-Bus.
+ *
+ * @param {float64} temp - Temperatura en Kelvin
+ * @returns {error} Motivo por el que KWin no aceptó el cambio
+ * @private
  */
-func (gm *GammaManager) tryKWinMethod(temp float64) bool {
-	if !gm.isToolAvailable("qdbus") {
-		return false
+func (gm *GammaManager) tryKWinMethod(temp float64) error {
+	// Habilitar Night Color en KDE
+	if err := gm.callKWin("setMode", int32(2)); err != nil {
+		return fmt.Errorf("KWin no respondió por D-Bus: %w", err)
+	}
+	// Configurar temperatura
+	if err := gm.callKWin("setTemperature", int32(temp)); err != nil {
+		return fmt.Errorf("KWin rechazó la temperatura: %w", err)
 	}
+	logging.Printf("🌡️  Temperatura aplicada en Wayland (KDE KWin): %.0fK\n", temp)
+	return nil
+}
 
-	// Habilitar Night Color en KDE
-	cmd := exec.Command("qdbus", "org.kde.KWin", "/ColorCorrect", "setMode", "2")
-	if err := cmd.Run(); err == nil {
-		// Configurar temperatura
-		cmd = exec.Command("qdbus", "org.kde.KWin", "/ColorCorrect", "setTemperature", fmt.Sprintf("%.0f", temp))
-		if err := cmd.Run(); err == nil {
-			fmt.Printf("🌡️  Temperatura aplicada en Wayland (KDE KWin): %.0fK\n", temp)
-			return true
-		}
+// callKWin llama a Night Color de KWin, o solo lo registra en dry-run
+func (gm *GammaManager) callKWin(method string, args ...interface{}) error {
+	if gm.dryRun {
+		logging.Printf("🧪 [dry-run] org.kde.KWin /ColorCorrect %s %v\n", method, args)
+		return nil
 	}
-	return false
+	return kwinColorCorrect(method, args...)
 }
 
 /**
@@ -340,23 +1130,30 @@ func (gm *GammaManager) tryDDCMethod(r, g, b float64) bool {
 	greenVal := int(g * 100)
 	blueVal := int(b * 100)
 
+	// Solo monitores detectados: --bus evita que ddcutil sondee todos los buses
+	buses := gm.caps.DDCBuses()
+	if len(buses) == 0 {
+		return false
+	}
+
 	// Aplicar usando ddcutil para control directo del hardware
-	commands := [][]string{
-		{"ddcutil", "setvcp", "16", fmt.Sprintf("%d", redVal)},   // Red gain
-		{"ddcutil", "setvcp", "18", fmt.Sprintf("%d", greenVal)}, // Green gain
-		{"ddcutil", "setvcp", "1A", fmt.Sprintf("%d", blueVal)},  // Blue gain
+	gains := [][]string{
+		{"16", fmt.Sprintf("%d", redVal)},   // Red gain
+		{"18", fmt.Sprintf("%d", greenVal)}, // Green gain
+		{"1A", fmt.Sprintf("%d", blueVal)},  // Blue gain
 	}
 
 	success := false
-	for _, cmdArgs := range commands {
-		cmd := exec.Command(cmdArgs[0], cmdArgs[1:]...)
-		if err := cmd.Run(); err == nil {
-			success = true
+	for _, bus := range buses {
+		for _, gain := range gains {
+			if err := gm.runCommand("ddcutil", "--bus", fmt.Sprintf("%d", bus), "--noverify", "setvcp", gain[0], gain[1]); err == nil {
+				success = true
+			}
 		}
 	}
 
 	if success {
-		fmt.Printf("🌡️  Gamma aplicada en Wayland (DDC/CI hardware): %.2f:%.2f:%.2f\n", r, g, b)
+		logging.Printf("🌡️  Gamma aplicada en Wayland (DDC/CI hardware): %.2f:%.2f:%.2f\n", r, g, b)
 		return true
 	}
 	return false
@@ -381,15 +1178,13 @@ func (gm *GammaManager) tryColorOverlayMethod(r, g, b float64) bool {
 	}
 
 	for _, cmdArgs := range overlayTools {
-		cmd := exec.Command(cmdArgs[0], cmdArgs[1:]...)
-		cmd.Start() // No esperar, es un overlay
+		gm.startCommand(cmdArgs[0], cmdArgs[1:]...) // No esperar, es un overlay
 	}
 
 	// También intentar con xsetroot si funciona en XWayland
 	if gm.isToolAvailable("xsetroot") {
-		cmd := exec.Command("xsetroot", "-solid", colorHex)
-		if err := cmd.Run(); err == nil {
-			fmt.Printf("🌡️  Overlay de color aplicado en Wayland: %s\n", colorHex)
+		if err := gm.runCommand("xsetroot", "-solid", colorHex); err == nil {
+			logging.Printf("🌡️  Overlay de color aplicado en Wayland: %s\n", colorHex)
 			return true
 		}
 	}
@@ -406,7 +1201,7 @@ func (gm *GammaManager) tryXWaylandMethod(r, g, b float64) bool {
 	}
 
 	// Verificar si hay displays detectados
-	cmd := exec.Command("xrandr")
+	cmd := toolCommand("xrandr")
 	output, err := cmd.Output()
 	if err != nil {
 		return false
@@ -416,18 +1211,19 @@ func (gm *GammaManager) tryXWaylandMethod(r, g, b float64) bool {
 	lines := strings.Split(string(output), "\n")
 	connectedRegex := regexp.MustCompile(`^(\S+)\s+connected`)
 
-	applied := false
+	var displays []string
 	for _, line := range lines {
 		if matches := connectedRegex.FindStringSubmatch(line); matches != nil {
-			display := matches[1]
-			cmd := exec.Command("xrandr", "--output", display, "--gamma", fmt.Sprintf("%.2f:%.2f:%.2f", r, g, b))
-			if err := cmd.Run(); err == nil {
-				fmt.Printf("🌡️  Gamma aplicada en Wayland (XWayland/%s): %.2f:%.2f:%.2f\n", display, r, g, b)
-				applied = true
-			}
+			displays = append(displays, matches[1])
 		}
 	}
-	return applied
+
+	applied := gm.runXrandrGamma(displays, fmt.Sprintf("%.2f:%.2f:%.2f", r, g, b), 1.0)
+	if len(applied) == 0 {
+		return false
+	}
+	logging.Printf("🌡️  Gamma aplicada en Wayland (XWayland/%s): %.2f:%.2f:%.2f\n", strings.Join(applied, ", "), r, g, b)
+	return true
 }
 
 /**
@@ -439,33 +1235,27 @@ func (gm *GammaManager) tryDBusMethod(temp float64) bool {
 	}
 
 	// Intentar con GNOME Settings Daemon
-	cmd := exec.Command("dbus-send", "--session", "--type=method_call",
+	if err := gm.runCommand("dbus-send", "--session", "--type=method_call",
 		"--dest=org.gnome.SettingsDaemon.Color",
 		"/org/gnome/SettingsDaemon/Color",
 		"org.gnome.SettingsDaemon.Color.NightLightPreview",
-		fmt.Sprintf("uint32:%.0f", temp))
-
-	if err := cmd.Run(); err == nil {
-		fmt.Printf("🌡️  Temperatura aplicada en Wayland (D-Bus/GNOME): %.0fK\n", temp)
+		fmt.Sprintf("uint32:%.0f", temp)); err == nil {
+		logging.Printf("🌡️  Temperatura aplicada en Wayland (D-Bus/GNOME): %.0fK\n", temp)
 		return true
 	}
 
 	// Intentar con KDE
-	cmd = exec.Command("dbus-send", "--session", "--type=method_call",
+	if err := gm.runCommand("dbus-send", "--session", "--type=method_call",
 		"--dest=org.kde.KWin",
 		"/ColorCorrect",
 		"org.kde.kwin.ColorCorrect.setMode",
-		"string:manual")
-
-	if err := cmd.Run(); err == nil {
-		cmd = exec.Command("dbus-send", "--session", "--type=method_call",
+		"string:manual"); err == nil {
+		if err := gm.runCommand("dbus-send", "--session", "--type=method_call",
 			"--dest=org.kde.KWin",
 			"/ColorCorrect",
 			"org.kde.kwin.ColorCorrect.setTemperature",
-			fmt.Sprintf("int32:%.0f", temp))
-
-		if err := cmd.Run(); err == nil {
-			fmt.Printf("🌡️  Temperatura aplicada en Wayland (D-Bus/KDE): %.0fK\n", temp)
+			fmt.Sprintf("int32:%.0f", temp)); err == nil {
+			logging.Printf("🌡️  Temperatura aplicada en Wayland (D-Bus/KDE): %.0fK\n", temp)
 			return true
 		}
 	}
@@ -481,9 +1271,8 @@ func (gm *GammaManager) tryWlGammaRelay(r, g, b float64) bool {
 		return false
 	}
 
-	cmd := exec.Command("wl-gamma-relay", fmt.Sprintf("%.2f", r), fmt.Sprintf("%.2f", g), fmt.Sprintf("%.2f", b))
-	if err := cmd.Run(); err == nil {
-		fmt.Printf("🌡️  Gamma aplicada en Wayland (wl-gamma-relay): %.2f:%.2f:%.2f\n", r, g, b)
+	if err := gm.runCommand("wl-gamma-relay", fmt.Sprintf("%.2f", r), fmt.Sprintf("%.2f", g), fmt.Sprintf("%.2f", b)); err == nil {
+		logging.Printf("🌡️  Gamma aplicada en Wayland (wl-gamma-relay): %.2f:%.2f:%.2f\n", r, g, b)
 		return true
 	}
 	return false
@@ -496,36 +1285,18 @@ func (gm *GammaManager) tryBrightnessMethod(r, g, b float64) bool {
 	// Calcular brillo basado en valores RGB
 	brightness := (r + g + b) / 3.0
 
-	// Buscar archivos de brillo en /sys/class/backlight/
-	cmd := exec.Command("find", "/sys/class/backlight/", "-name", "brightness", "2>/dev/null")
-	output, err := cmd.Output()
-	if err != nil {
+	devices, err := ListBacklights()
+	if err != nil || len(devices) == 0 {
 		return false
 	}
 
-	brightnessFiles := strings.Split(strings.TrimSpace(string(output)), "\n")
-	for _, file := range brightnessFiles {
-		if file == "" {
-			continue
-		}
-
-		// Leer brillo máximo
-		maxFile := strings.Replace(file, "brightness", "max_brightness", 1)
-		maxOutput, err := exec.Command("cat", maxFile).Output()
-		if err != nil {
-			continue
-		}
-
-		var maxBrightness int
-		fmt.Sscanf(strings.TrimSpace(string(maxOutput)), "%d", &maxBrightness)
-
+	for _, device := range devices {
 		// Calcular nuevo brillo
-		newBrightness := int(float64(maxBrightness) * brightness)
+		newBrightness := int(float64(device.Max) * brightness)
 
-		// Aplicar nuevo brillo
-		cmd := exec.Command("sh", "-c", fmt.Sprintf("echo %d | sudo tee %s", newBrightness, file))
-		if err := cmd.Run(); err == nil {
-			fmt.Printf("🌡️  Brillo ajustado en Wayland: %.0f%% (simulando temperatura)\n", brightness*100)
+		// Aplicar vía logind o helper de polkit (nunca sudo en terminal)
+		if err := gm.setBacklight(device, newBrightness); err == nil {
+			logging.Printf("🌡️  Brillo ajustado en Wayland: %.0f%% (simulando temperatura)\n", brightness*100)
 			return true
 		}
 	}
@@ -533,24 +1304,13 @@ func (gm *GammaManager) tryBrightnessMethod(r, g, b float64) bool {
 }
 
 /**
- * tryRedshiftMethod - Intenta usar redshift temporalmente
+ * tryManagedMethod - Usa gammastep/wlsunset como backend supervisado
+ *
+ * Lanza la herramienta como proceso hijo con la temperatura calculada;
+ * el supervisor la relanza si el compositor la cierra.
  */
-func (gm *GammaManager) tryRedshiftMethod(temp float64) bool {
-	if !gm.isToolAvailable("redshift") {
-		return false
-	}
-
-	// Matar redshift anterior
-	exec.Command("pkill", "redshift").Run()
-	time.Sleep(100 * time.Millisecond)
-
-	// Aplicar temperatura con redshift
-	cmd := exec.Command("redshift", "-P", "-O", fmt.Sprintf("%.0f", temp))
-	if err := cmd.Run(); err == nil {
-		fmt.Printf("🌡️  Temperatura aplicada en Wayland (redshift): %.0fK\n", temp)
-		return true
-	}
-	return false
+func (gm *GammaManager) tryManagedMethod(temp float64) error {
+	return gm.managed.Apply(temp)
 }
 
 /**
@@ -560,31 +1320,33 @@ func (gm *GammaManager) tryRedshiftMethod(temp float64) bool {
  * @private
  */
 func (gm *GammaManager) resetWaylandGamma() error {
+	// Detener primero nuestro propio backend supervisado
+	gm.managed.Stop()
+
 	// Matar todos los procesos de control de gamma
 	processes := []string{"wlsunset", "wl-gamma-relay", "gammastep", "redshift", "f.lux"}
 	for _, proc := range processes {
-		exec.Command("pkill", "-9", proc).Run()
-		exec.Command("killall", "-9", proc).Run()
+		gm.runCommand("pkill", "-9", proc)
+		gm.runCommand("killall", "-9", proc)
 	}
 	time.Sleep(300 * time.Millisecond)
 
 	// 1. Intentar reset con XWayland
 	if gm.tryXWaylandMethod(1.0, 1.0, 1.0) {
-		fmt.Println("✅ Gamma reseteada en Wayland (XWayland)")
+		logging.Println("✅ Gamma reseteada en Wayland (XWayland)")
 		return nil
 	}
 
 	// 2. Intentar reset con D-Bus
 	if gm.tryDBusMethod(6500) {
-		fmt.Println("✅ Gamma reseteada en Wayland (D-Bus)")
+		logging.Println("✅ Gamma reseteada en Wayland (D-Bus)")
 		return nil
 	}
 
 	// 3. Intentar reset con wl-gamma-relay
 	if gm.isToolAvailable("wl-gamma-relay") {
-		cmd := exec.Command("wl-gamma-relay", "1.0", "1.0", "1.0")
-		if err := cmd.Run(); err == nil {
-			fmt.Println("✅ Gamma reseteada en Wayland (wl-gamma-relay)")
+		if err := gm.runCommand("wl-gamma-relay", "1.0", "1.0", "1.0"); err == nil {
+			logging.Println("✅ Gamma reseteada en Wayland (wl-gamma-relay)")
 			return nil
 		}
 	}
@@ -592,11 +1354,11 @@ func (gm *GammaManager) resetWaylandGamma() error {
 	// 4. Resetear configuración del sistema nativo
 	if gm.isToolAvailable("gsettings") {
 		// Habilitar de nuevo el sistema nativo y ponerlo en modo día
-		exec.Command("gsettings", "set", "org.gnome.settings-daemon.plugins.color", "night-light-enabled", "false").Run()
-		exec.Command("gsettings", "set", "org.gnome.settings-daemon.plugins.color", "night-light-temperature", "6500").Run()
+		gm.runCommand("gsettings", "set", "org.gnome.settings-daemon.plugins.color", "night-light-enabled", "false")
+		gm.runCommand("gsettings", "set", "org.gnome.settings-daemon.plugins.color", "night-light-temperature", "6500")
 	}
 
-	fmt.Println("✅ Reset de gamma completado en Wayland")
+	logging.Println("✅ Reset de gamma completado en Wayland")
 	return nil
 }
 
@@ -611,7 +1373,7 @@ func (gm *GammaManager) resetWaylandGamma() error {
 func (gm *GammaManager) detectWaylandDisplays() {
 	// Intentar usar xrandr incluso en Wayland (funciona en XWayland)
 	if gm.isToolAvailable("xrandr") {
-		cmd := exec.Command("xrandr")
+		cmd := toolCommand("xrandr")
 		output, err := cmd.Output()
 		if err == nil {
 			// Parsear output de xrandr para encontrar displays conectados
@@ -626,16 +1388,16 @@ func (gm *GammaManager) detectWaylandDisplays() {
 			}
 
 			if len(displays) > 0 {
-				gm.displays = displays
-				fmt.Printf("🖥️  Displays detectados en Wayland (xrandr): %v\n", displays)
+				gm.setDisplays(displays)
+				logging.Printf("🖥️  Displays detectados en Wayland (xrandr): %v\n", displays)
 				return
 			}
 		}
 	}
 
 	// Fallback a control global de Wayland
-	gm.displays = []string{"wayland-global"}
-	fmt.Printf("🖥️  Protocolo Wayland detectado - control global de gamma\n")
+	gm.setDisplays([]string{"wayland-global"})
+	logging.Printf("🖥️  Protocolo Wayland detectado - control global de gamma\n")
 }
 
 /**
@@ -647,9 +1409,126 @@ func (gm *GammaManager) detectWaylandDisplays() {
  *   fmt.Printf("Displays disponibles: %v", displays)
  */
 func (gm *GammaManager) GetDisplays() []string {
+	gm.displaysMu.Lock()
+	defer gm.displaysMu.Unlock()
 	return gm.displays
 }
 
+// setDisplays reemplaza la lista de displays detectados
+func (gm *GammaManager) setDisplays(displays []string) {
+	gm.displaysMu.Lock()
+	defer gm.displaysMu.Unlock()
+	gm.displays = displays
+}
+
+// internalConnectorPrefixes son los prefijos de conector de los paneles integrados
+var internalConnectorPrefixes = []string{"EDP", "LVDS", "DSI"}
+
+// IsInternalDisplay indica si el conector es el panel integrado (eDP-1, LVDS1, DSI-1...);
+// el resto (HDMI, DP, DVI, VGA...) se consideran monitores externos
+func IsInternalDisplay(name string) bool {
+	upper := strings.ToUpper(name)
+	for _, prefix := range internalConnectorPrefixes {
+		if strings.HasPrefix(upper, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+/**
+ * Close - Detiene la vigilancia de control exclusivo y el backend supervisado
+ *
+ * Debe llamarse al salir para no dejar subprocesos huérfanos ni un
+ * archivo de bloqueo obsoleto.
+ */
+func (gm *GammaManager) Close() {
+	gm.exclusive.Stop()
+	gm.managed.Stop()
+	gm.releaseSystemLock()
+}
+
+/**
+ * RefreshCapabilities - Vuelve a detectar herramientas y monitores DDC/CI
+ *
+ * Útil tras instalar una herramienta o conectar un monitor externo.
+ * También vuelve a buscar backends externos en PluginDir.
+ */
+func (gm *GammaManager) RefreshCapabilities() {
+	gm.caps.Refresh()
+	plugin := detectPlugin(gm.dryRun)
+
+	gm.pluginMu.Lock()
+	defer gm.pluginMu.Unlock()
+	gm.plugin = plugin
+}
+
+// currentPlugin devuelve el backend externo en uso (nil si no hay ninguno)
+func (gm *GammaManager) currentPlugin() *PluginBackend {
+	gm.pluginMu.Lock()
+	defer gm.pluginMu.Unlock()
+	return gm.plugin
+}
+
+/**
+ * GetCapabilities - Obtiene la caché de capacidades de los backends
+ *
+ * @returns {*Capabilities} Caché compartida por todos los backends
+ */
+func (gm *GammaManager) GetCapabilities() *Capabilities {
+	return gm.caps
+}
+
+/**
+ * GetBackend - Obtiene el método que aplicó la última temperatura
+ *
+ * @returns {string} Nombre del backend ("xrandr", "GNOME Mutter"...) o "" si aún no se aplicó nada
+ */
+func (gm *GammaManager) GetBackend() string {
+	gm.backendMu.Lock()
+	defer gm.backendMu.Unlock()
+	return gm.backend
+}
+
+// setBackend registra el método que acaba de aplicar la temperatura
+func (gm *GammaManager) setBackend(backend string) {
+	gm.backendMu.Lock()
+	defer gm.backendMu.Unlock()
+	gm.backend = backend
+}
+
+/**
+ * SupportsFineSteps - Indica si un backend aplica la gamma sin lanzar procesos
+ *
+ * RandR, el protocolo wlr-gamma y la luz nocturna de Mutter o KWin
+ * cambian la gamma al instante y admiten los pasos cortos de las
+ * transiciones. Los demás lanzan un proceso en cada cambio (xrandr,
+ * gammastep/wlsunset, que además se relanza) o son lentos (DDC/CI,
+ * plugins), así que siguen con pasos de un minuto.
+ *
+ * @param {string} backend - Nombre devuelto por GetBackend
+ * @returns {bool} true si el backend admite pasos de pocos segundos
+ */
+func SupportsFineSteps(backend string) bool {
+	switch backend {
+	case "randr", "compositor", methodMutter, methodKWin:
+		return true
+	}
+	return false
+}
+
+/**
+ * RefreshDisplays - Vuelve a detectar los displays conectados
+ *
+ * Útil cuando se conectan o desconectan monitores con la aplicación abierta.
+ *
+ * @returns {[]string} Lista actualizada de displays
+ */
+func (gm *GammaManager) RefreshDisplays() []string {
+	gm.detectDisplays()
+	return gm.GetDisplays()
+}
+
 /**
  * GetProtocol - Obtiene el protocolo de display detectado
  *
@@ -660,147 +1539,90 @@ func (gm *GammaManager) GetProtocol() string {
 }
 
 /**
- * temperatureToRGB - Convierte temperatura Kelvin a valores RGB gamma
+ * runCommand - Ejecuta un comando que modifica el estado del sistema
  *
- * Implementa el algoritmo de Tanner Helland para conversión de temperatura
- * de color a valores RGB, optimizado para control de gamma en pantallas.
+ * En modo dry-run no ejecuta nada: registra el comando exacto que se
+ * habría lanzado y lo considera exitoso. Por eso cada método comprueba
+ * antes si su herramienta está disponible (en Wayland, además,
+ * dryRunUnavailable), para que la cadena de fallbacks elija lo mismo
+ * que en una ejecución real.
  *
- * @param {float64} temp - Temperatura en Kelvin (1000-40000, típicamente 3000-6500)
- * @returns {float64, float64, float64} Componentes RGB normalizados (0.3-1.0)
- * @example
- *   r, g, b := gm.temperatureToRGB(4000) // Temperatura cálida
- *   // r ≈ 1.0, g ≈ 0.8, b ≈ 0.6
+ * @param {string} name - Ejecutable a lanzar
+ * @param {...string} args - Argumentos del comando
+ * @returns {error} Error de ejecución con lo que el comando escribió en stderr (siempre nil en dry-run)
+ * @private
  */
-func (gm *GammaManager) temperatureToRGB(temp float64) (r, g, b float64) {
-	// Algoritmo de Tanner Helland optimizado para control de gamma
-	// Basado en datos empíricos de temperatura de color de cuerpo negro
-
-	// Normalizar temperatura (dividir por 100 para cálculos)
-	temp = temp / 100
-
-	// === CALCULAR COMPONENTE ROJO ===
-	if temp <= 66 {
-		// Para temperaturas <= 6600K, el rojo está al máximo
-		r = 1.0
-	} else {
-		// Para temperaturas > 6600K, calcular curva de enfriamiento
-		r = temp - 60
-		r = 329.698727446 * math.Pow(r, -0.1332047592)
-		if r < 0 {
-			r = 0
-		}
-		if r > 1 {
-			r = 1
-		}
+func (gm *GammaManager) runCommand(name string, args ...string) error {
+	if gm.dryRun {
+		logging.Printf("🧪 [dry-run] %s %s\n", name, strings.Join(args, " "))
+		return nil
 	}
-
-	// === CALCULAR COMPONENTE VERDE ===
-	if temp <= 66 {
-		// Curva de calentamiento para verde
-		g = temp
-		g = 99.4708025861*math.Log(g) - 161.1195681661
-		if g < 0 {
-			g = 0
-		}
-		if g > 255 {
-			g = 255
-		}
-		g = g / 255 // Normalizar a 0-1
-	} else {
-		// Curva de enfriamiento para verde
-		g = temp - 60
-		g = 288.1221695283 * math.Pow(g, -0.0755148492)
-		if g < 0 {
-			g = 0
-		}
-		if g > 1 {
-			g = 1
+	cmd := toolCommand(name, args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return fmt.Errorf("%w: %s", err, message)
 		}
+		return err
 	}
+	return nil
+}
 
-	// === CALCULAR COMPONENTE AZUL ===
-	if temp >= 66 {
-		// Para temperaturas >= 6600K, el azul está al máximo
-		b = 1.0
-	} else if temp <= 19 {
-		// Para temperaturas muy bajas, no hay azul
-		b = 0
-	} else {
-		// Curva de calentamiento para azul
-		b = temp - 10
-		b = 138.5177312231*math.Log(b) - 305.0447927307
-		if b < 0 {
-			b = 0
-		}
-		if b > 255 {
-			b = 255
-		}
-		b = b / 255 // Normalizar a 0-1
+/**
+ * startCommand - Lanza un comando en segundo plano sin esperar su fin
+ *
+ * @param {string} name - Ejecutable a lanzar
+ * @param {...string} args - Argumentos del comando
+ * @returns {error} Error al lanzar el proceso (siempre nil en dry-run)
+ * @private
+ */
+func (gm *GammaManager) startCommand(name string, args ...string) error {
+	if gm.dryRun {
+		logging.Printf("🧪 [dry-run] %s %s &\n", name, strings.Join(args, " "))
+		return nil
 	}
+	return toolCommand(name, args...).Start()
+}
 
-	// === APLICAR LÍMITES MÍNIMOS PARA GAMMA ===
-	// Evitar valores demasiado extremos que puedan dañar la vista
-	// o hacer la pantalla ilegible
-	const minGamma = 0.3
-	if r < minGamma {
-		r = minGamma
-	}
-	if g < minGamma {
-		g = minGamma
+/**
+ * writeFile - Escribe un archivo auxiliar creando su directorio
+ *
+ * @param {string} path - Ruta del archivo
+ * @param {[]byte} data - Contenido a escribir
+ * @param {os.FileMode} perm - Permisos del archivo
+ * @returns {error} Error de escritura (siempre nil en dry-run)
+ * @private
+ */
+func (gm *GammaManager) writeFile(path string, data []byte, perm os.FileMode) error {
+	if gm.dryRun {
+		logging.Printf("🧪 [dry-run] escribir %s (%d bytes)\n", path, len(data))
+		return nil
 	}
-	if b < minGamma {
-		b = minGamma
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
 	}
-
-	return r, g, b
+	return os.WriteFile(path, data, perm)
 }
 
 /**
- * isToolAvailable - Verifica si una herramienta está disponible en el sistema
+ * IsDryRun - Indica si el manejador está en modo dry-run
  *
- * @param {string} tool - Nombre de la herramienta a verificar
- * @returns {bool} true si la herramienta está disponible
- * @private
+ * @returns {bool} true si los comandos solo se registran
  */
-func (gm *GammaManager) isToolAvailable(tool string) bool {
-	_, err := exec.LookPath(tool)
-	return err == nil
+func (gm *GammaManager) IsDryRun() bool {
+	return gm.dryRun
 }
 
 /**
- * rgbToTemperature - Convierte valores RGB aproximadamente a temperatura Kelvin
- *
- * Función inversa aproximada para estimar temperatura desde valores RGB.
- * Útil para retrocompatibilidad con herramientas que requieren temperatura.
+ * isToolAvailable - Verifica si una herramienta está disponible en el sistema
  *
- * @param {float64} r - Componente rojo (0-1)
- * @param {float64} g - Componente verde (0-1)
- * @param {float64} b - Componente azul (0-1)
- * @returns {float64} Temperatura estimada en Kelvin
+ * @param {string} tool - Nombre de la herramienta a verificar
+ * @returns {bool} true si la herramienta está disponible
  * @private
  */
-func (gm *GammaManager) rgbToTemperature(r, g, b float64) float64 {
-	// Estimación mejorada basada en valores RGB gamma
-
-	// Si todos los valores están cerca de 1.0, es temperatura diurna
-	if r >= 0.95 && g >= 0.95 && b >= 0.95 {
-		return 6500
-	}
-
-	// Usar el valor azul como indicador principal
-	if b >= 0.9 {
-		return 6500 // Muy frío/diurno
-	} else if b >= 0.8 {
-		return 5500 // Frío
-	} else if b >= 0.7 {
-		return 4500 // Neutro-frío
-	} else if b >= 0.6 {
-		return 4000 // Neutro-cálido
-	} else if b >= 0.5 {
-		return 3500 // Cálido
-	} else {
-		return 3000 // Muy cálido
-	}
+func (gm *GammaManager) isToolAvailable(tool string) bool {
+	return gm.caps.HasTool(tool)
 }
 
 /**
@@ -812,38 +1634,48 @@ func (gm *GammaManager) rgbToTemperature(r, g, b float64) float64 {
  * @private
  */
 func (gm *GammaManager) disableSystemNightLight() {
+	// Otra instancia viva ya tiene el control: no pelear con ella
+	if err := gm.acquireSystemLock(); err != nil {
+		if !gm.lockConflict {
+			logging.Printf("⚠️  %v; no se tomará el control exclusivo\n", err)
+			gm.lockConflict = true
+		}
+		return
+	}
+	gm.lockConflict = false
+
 	// Deshabilitar sistemas nativos silenciosamente
 
 	// 1. GNOME/ZorinOS Night Light - Deshabilitación forzada
 	if gm.isToolAvailable("gsettings") {
 		// Verificar si está activo
-		cmd := exec.Command("gsettings", "get", "org.gnome.settings-daemon.plugins.color", "night-light-enabled")
+		cmd := toolCommand("gsettings", "get", "org.gnome.settings-daemon.plugins.color", "night-light-enabled")
 		output, err := cmd.Output()
 		if err == nil {
 			isEnabled := strings.TrimSpace(string(output)) == "true"
 
 			// Deshabilitar completamente
-			exec.Command("gsettings", "set", "org.gnome.settings-daemon.plugins.color", "night-light-enabled", "false").Run()
-			exec.Command("gsettings", "set", "org.gnome.settings-daemon.plugins.color", "night-light-temperature", "uint32:6500").Run()
-			exec.Command("gsettings", "set", "org.gnome.settings-daemon.plugins.color", "night-light-schedule-automatic", "false").Run()
+			gm.runCommand("gsettings", "set", "org.gnome.settings-daemon.plugins.color", "night-light-enabled", "false")
+			gm.runCommand("gsettings", "set", "org.gnome.settings-daemon.plugins.color", "night-light-temperature", "uint32:6500")
+			gm.runCommand("gsettings", "set", "org.gnome.settings-daemon.plugins.color", "night-light-schedule-automatic", "false")
 
 			// Forzar aplicación inmediata via D-Bus
 			if gm.isToolAvailable("gdbus") {
-				exec.Command("gdbus", "call", "--session", "--dest", "org.gnome.SettingsDaemon.Color",
+				gm.runCommand("gdbus", "call", "--session", "--dest", "org.gnome.SettingsDaemon.Color",
 					"--object-path", "/org/gnome/SettingsDaemon/Color",
 					"--method", "org.gnome.SettingsDaemon.Color.NightLightPreview",
-					"uint32:6500").Run()
+					"uint32:6500")
 			}
 
 			if isEnabled {
-				fmt.Println("🔧 Sistema nativo deshabilitado")
+				logging.Println("🔧 Sistema nativo deshabilitado")
 			}
 		}
 	}
 
 	// 2. KDE Night Color - Deshabilitación completa
-	if gm.isToolAvailable("qdbus") {
-		exec.Command("qdbus", "org.kde.KWin", "/ColorCorrect", "setMode", "0").Run()
+	if strings.Contains(DetectCompositor(), "KWin") {
+		gm.callKWin("setMode", int32(0))
 	}
 
 	// 3. Terminar todos los procesos competidores agresivamente
@@ -858,13 +1690,16 @@ func (gm *GammaManager) disableSystemNightLight() {
 
 	killed := []string{}
 	for _, proc := range processes {
-		cmd := exec.Command("pgrep", proc)
+		if gm.managed.Manages(proc) {
+			continue // Es nuestro proceso hijo supervisado
+		}
+		cmd := toolCommand("pgrep", proc)
 		if err := cmd.Run(); err == nil {
 			// Terminar proceso gracefully primero
-			exec.Command("pkill", "-TERM", proc).Run()
+			gm.runCommand("pkill", "-TERM", proc)
 			time.Sleep(100 * time.Millisecond)
 			// Si sigue corriendo, forzar terminación
-			exec.Command("pkill", "-KILL", proc).Run()
+			gm.runCommand("pkill", "-KILL", proc)
 			killed = append(killed, proc)
 		}
 	}
@@ -873,55 +1708,6 @@ func (gm *GammaManager) disableSystemNightLight() {
 		time.Sleep(300 * time.Millisecond)
 	}
 
-	// 4. Crear archivo de bloqueo para evitar reactivación automática
-	gm.createSystemLockFile()
-
-	// 5. Monitorear y mantener control exclusivo
-	go gm.maintainExclusiveControl()
-}
-
-/**
- * createSystemLockFile - Crea archivo para indicar que tenemos control exclusivo
- */
-func (gm *GammaManager) createSystemLockFile() {
-	lockDir := "/tmp/luz-nocturna"
-	lockFile := lockDir + "/exclusive-control.lock"
-
-	// Crear directorio si no existe
-	os.MkdirAll(lockDir, 0755)
-
-	// Crear archivo de bloqueo con información
-	lockContent := fmt.Sprintf("luz-nocturna active\npid: %d\ntime: %s\n",
-		os.Getpid(), time.Now().Format(time.RFC3339))
-
-	os.WriteFile(lockFile, []byte(lockContent), 0644)
-}
-
-/**
- * maintainExclusiveControl - Mantiene control exclusivo del gamma
- */
-func (gm *GammaManager) maintainExclusiveControl() {
-	ticker := time.NewTicker(30 * time.Second)
-	defer ticker.Stop()
-
-	for range ticker.C {
-		// Verificar si el sistema nativo se reactivó
-		if gm.isToolAvailable("gsettings") {
-			cmd := exec.Command("gsettings", "get", "org.gnome.settings-daemon.plugins.color", "night-light-enabled")
-			output, err := cmd.Output()
-			if err == nil && strings.TrimSpace(string(output)) == "true" {
-				// El sistema nativo se reactivó, deshabilitarlo de nuevo
-				exec.Command("gsettings", "set", "org.gnome.settings-daemon.plugins.color", "night-light-enabled", "false").Run()
-			}
-		}
-
-		// Verificar procesos competidores
-		competitorProcesses := []string{"redshift", "wlsunset", "gammastep"}
-		for _, proc := range competitorProcesses {
-			cmd := exec.Command("pgrep", proc)
-			if err := cmd.Run(); err == nil {
-				exec.Command("pkill", "-TERM", proc).Run()
-			}
-		}
-	}
+	// 4. Vigilar reactivaciones (una sola vez; sin sondeo periódico)
+	gm.exclusive.Start()
 }
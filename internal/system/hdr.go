@@ -0,0 +1,99 @@
+package system
+
+import (
+	"luznocturna/luz-nocturna/internal/logging"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ansiEscapeRegex elimina los colores ANSI de la salida de kscreen-doctor
+var ansiEscapeRegex = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+// mutterMonitorRegex localiza cada monitor (conector, fabricante, producto, serie) en GetCurrentState
+var mutterMonitorRegex = regexp.MustCompile(`\(\('([^']+)', '[^']*', '[^']*', '[^']*'\)`)
+
+/**
+ * detectHDR - Detecta las salidas con HDR activo
+ *
+ * Solo tiene sentido en Wayland: X11 no admite HDR. Se consulta al
+ * compositor (KWin con kscreen-doctor, Mutter con DisplayConfig), que
+ * usa los mismos nombres de conector que el kernel (DP-1, HDMI-A-1...).
+ *
+ * @private
+ */
+func (gm *GammaManager) detectHDR() {
+	var hdr []string
+	if gm.protocol == "wayland" {
+		if gm.isToolAvailable("kscreen-doctor") {
+			if output, err := toolCommand("kscreen-doctor", "-o").Output(); err == nil {
+				hdr = parseKScreenHDR(string(output))
+			}
+		}
+		if len(hdr) == 0 && gm.isToolAvailable("gdbus") {
+			output, err := toolCommand("gdbus", "call", "--session",
+				"--dest", "org.gnome.Mutter.DisplayConfig",
+				"--object-path", "/org/gnome/Mutter/DisplayConfig",
+				"--method", "org.gnome.Mutter.DisplayConfig.GetCurrentState").Output()
+			if err == nil {
+				hdr = parseMutterHDR(string(output))
+			}
+		}
+	}
+
+	gm.displaysMu.Lock()
+	gm.hdrDisplays = hdr
+	gm.displaysMu.Unlock()
+	if len(hdr) > 0 {
+		logging.Printf("🌈 HDR activo en %v: solo se usará la luz nocturna del compositor\n", hdr)
+	}
+}
+
+// parseKScreenHDR extrae de "kscreen-doctor -o" las salidas con "HDR: enabled"
+func parseKScreenHDR(output string) []string {
+	var hdr []string
+	current := ""
+	for _, line := range strings.Split(ansiEscapeRegex.ReplaceAllString(output, ""), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) >= 3 && fields[0] == "Output:" {
+			current = fields[2]
+			continue
+		}
+		if current != "" && len(fields) >= 2 && fields[0] == "HDR:" && fields[1] == "enabled" {
+			hdr = append(hdr, current)
+		}
+	}
+	return hdr
+}
+
+// parseMutterHDR extrae de GetCurrentState los monitores con color-mode BT.2100 (HDR)
+func parseMutterHDR(output string) []string {
+	var hdr []string
+	matches := mutterMonitorRegex.FindAllStringSubmatchIndex(output, -1)
+	for i, match := range matches {
+		end := len(output)
+		if i+1 < len(matches) {
+			end = matches[i+1][0]
+		}
+		if strings.Contains(output[match[1]:end], "'color-mode': <uint32 1>") {
+			hdr = append(hdr, output[match[2]:match[3]])
+		}
+	}
+	sort.Strings(hdr)
+	return hdr
+}
+
+/**
+ * GetHDRDisplays - Salidas con HDR activo detectadas en el compositor
+ *
+ * Con HDR activo una rampa gamma aplicada por fuera del compositor
+ * (gammastep, wlr-gamma-control, DDC...) deforma los colores, así que
+ * en ese caso solo se usa la luz nocturna nativa de GNOME o KDE.
+ *
+ * @returns {[]string} Conectores con HDR (vacío en X11 o sin HDR)
+ */
+func (gm *GammaManager) GetHDRDisplays() []string {
+	gm.displaysMu.Lock()
+	defer gm.displaysMu.Unlock()
+	return gm.hdrDisplays
+}
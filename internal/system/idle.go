@@ -0,0 +1,89 @@
+package system
+
+import (
+	"fmt"
+
+	"github.com/godbus/dbus/v5"
+
+	"luznocturna/luz-nocturna/internal/logging"
+)
+
+/**
+ * IdleWatcher - Sigue la inactividad de la sesión en logind
+ *
+ * Escucha la propiedad IdleHint de la sesión actual, que el escritorio
+ * (GNOME, KDE, swayidle...) activa cuando vence su propio tiempo de
+ * inactividad y desactiva en cuanto hay actividad.
+ *
+ * @struct {IdleWatcher}
+ * @property {*dbus.Conn} conn - Conexión privada al bus del sistema
+ */
+type IdleWatcher struct {
+	conn *dbus.Conn
+}
+
+/**
+ * StartIdleWatcher - Empieza a seguir la inactividad de la sesión
+ *
+ * onChange se llama desde la goroutine de D-Bus, primero con el estado
+ * actual y después con cada cambio de IdleHint.
+ *
+ * @param {func(bool)} onChange - Callback con true al quedar inactiva y false al volver
+ * @returns {*IdleWatcher, error} Seguimiento activo; error si logind no está disponible
+ * @example
+ *   watcher, err := StartIdleWatcher(func(idle bool) { fmt.Println(idle) })
+ *   defer watcher.Stop()
+ */
+func StartIdleWatcher(onChange func(idle bool)) (*IdleWatcher, error) {
+	conn, err := dbus.ConnectSystemBus()
+	if err != nil {
+		return nil, fmt.Errorf("no se pudo conectar al bus del sistema: %w", err)
+	}
+	path, err := logindSessionPath(conn)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("logind no está disponible: %w", err)
+	}
+
+	var idle bool
+	if err := conn.Object("org.freedesktop.login1", path).StoreProperty("org.freedesktop.login1.Session.IdleHint", &idle); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if err := conn.AddMatchSignal(
+		dbus.WithMatchObjectPath(path),
+		dbus.WithMatchInterface("org.freedesktop.DBus.Properties"),
+		dbus.WithMatchMember("PropertiesChanged"),
+	); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	signals := make(chan *dbus.Signal, 10)
+	conn.Signal(signals)
+	go func() {
+		onChange(idle)
+		// Stop cierra la conexión y con ella el canal
+		for signal := range signals {
+			if len(signal.Body) < 2 {
+				continue
+			}
+			changed, ok := signal.Body[1].(map[string]dbus.Variant)
+			if !ok {
+				continue
+			}
+			if hint, ok := changed["IdleHint"].Value().(bool); ok && hint != idle {
+				idle = hint
+				onChange(idle)
+			}
+		}
+	}()
+
+	logging.Println("💤 Siguiendo la inactividad de la sesión")
+	return &IdleWatcher{conn: conn}, nil
+}
+
+// Stop detiene el seguimiento
+func (w *IdleWatcher) Stop() {
+	w.conn.Close()
+}
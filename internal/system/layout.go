@@ -0,0 +1,95 @@
+package system
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"luznocturna/luz-nocturna/internal/logging"
+)
+
+// layoutPollInterval es cada cuánto se comprueba si cambió la disposición de monitores.
+// Leer sysfs es barato y funciona igual en X11 y en Wayland, sin depender del compositor.
+const layoutPollInterval = 3 * time.Second
+
+/**
+ * ReadMonitorLayout - Lee los monitores conectados ahora mismo
+ *
+ * Cada monitor se identifica por el nombre de su EDID ("DELL U2720Q") o,
+ * si no se pudo leer, por el conector ("HDMI-A-1").
+ *
+ * @returns {[]string} Monitores conectados, ordenados
+ */
+func ReadMonitorLayout() []string {
+	var layout []string
+	for _, connector := range readDRMConnectors() {
+		if connector.model != "" {
+			layout = append(layout, connector.model)
+		} else {
+			layout = append(layout, connector.name)
+		}
+	}
+	sort.Strings(layout)
+	return layout
+}
+
+/**
+ * LayoutWatcher - Sigue los monitores conectados
+ *
+ * Lee /sys/class/drm cada layoutPollInterval y avisa cuando cambia el
+ * conjunto de monitores (conectar, desconectar o cambiar uno por otro).
+ *
+ * @struct {LayoutWatcher}
+ * @property {chan struct{}} stop - Detiene la goroutine del seguimiento
+ */
+type LayoutWatcher struct {
+	stop chan struct{}
+}
+
+/**
+ * StartLayoutWatcher - Empieza a seguir la disposición de monitores
+ *
+ * onChange se llama desde la goroutine del seguimiento, primero con la
+ * disposición actual y después con cada cambio.
+ *
+ * @param {func([]string)} onChange - Callback con los monitores conectados (ver ReadMonitorLayout)
+ * @returns {*LayoutWatcher, error} Seguimiento activo; error si el kernel no expone los conectores DRM
+ * @example
+ *   watcher, err := StartLayoutWatcher(func(layout []string) { fmt.Println(layout) })
+ *   defer watcher.Stop()
+ */
+func StartLayoutWatcher(onChange func(layout []string)) (*LayoutWatcher, error) {
+	if _, err := os.Stat(drmSysfsDir); err != nil {
+		return nil, fmt.Errorf("no se pueden leer los monitores conectados: %w", err)
+	}
+
+	watcher := &LayoutWatcher{stop: make(chan struct{})}
+	go func() {
+		layout := ReadMonitorLayout()
+		onChange(layout)
+
+		ticker := time.NewTicker(layoutPollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-watcher.stop:
+				return
+			case <-ticker.C:
+				current := ReadMonitorLayout()
+				if strings.Join(current, "\n") != strings.Join(layout, "\n") {
+					layout = current
+					onChange(layout)
+				}
+			}
+		}
+	}()
+	logging.Println("🖥️  Siguiendo la disposición de monitores")
+	return watcher, nil
+}
+
+// Stop detiene el seguimiento
+func (w *LayoutWatcher) Stop() {
+	close(w.stop)
+}
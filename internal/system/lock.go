@@ -0,0 +1,98 @@
+package system
+
+import (
+	"errors"
+	"fmt"
+	"luznocturna/luz-nocturna/internal/logging"
+	"luznocturna/luz-nocturna/internal/paths"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// systemLockFile indica qué proceso de luz-nocturna tiene el control exclusivo de la gamma
+var systemLockFile = filepath.Join(paths.RuntimeDir(), "exclusive-control.lock")
+
+/**
+ * acquireSystemLock - Toma el bloqueo de control exclusivo
+ *
+ * El archivo se crea de forma atómica (O_EXCL). Si ya existe se lee el
+ * PID registrado: si es el nuestro no hay nada que hacer, si el proceso
+ * ya no existe (o el archivo está corrupto) el bloqueo se considera
+ * obsoleto y se reemplaza.
+ *
+ * @returns {error} Error si otra instancia viva tiene el bloqueo
+ * @private
+ */
+func (gm *GammaManager) acquireSystemLock() error {
+	if gm.dryRun {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(systemLockFile), 0700); err != nil {
+		return fmt.Errorf("no se pudo crear el directorio de bloqueo: %w", err)
+	}
+
+	for attempt := 0; attempt < 2; attempt++ {
+		file, err := os.OpenFile(systemLockFile, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if err == nil {
+			_, err = fmt.Fprintf(file, "luz-nocturna active\npid: %d\ntime: %s\n",
+				os.Getpid(), time.Now().Format(time.RFC3339))
+			file.Close()
+			return err
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return fmt.Errorf("no se pudo crear el bloqueo: %w", err)
+		}
+
+		pid, ok := readLockPID(systemLockFile)
+		if ok && pid == os.Getpid() {
+			return nil
+		}
+		if ok && isProcessAlive(pid) {
+			return fmt.Errorf("otra instancia (pid %d) tiene el control exclusivo", pid)
+		}
+
+		logging.Printf("🧹 Bloqueo obsoleto encontrado (pid %d), reemplazándolo\n", pid)
+		if err := os.Remove(systemLockFile); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("no se pudo eliminar el bloqueo obsoleto: %w", err)
+		}
+	}
+
+	return fmt.Errorf("no se pudo tomar el bloqueo %s", systemLockFile)
+}
+
+// releaseSystemLock elimina el archivo de bloqueo si pertenece a este proceso
+func (gm *GammaManager) releaseSystemLock() {
+	if gm.dryRun {
+		return
+	}
+
+	if pid, ok := readLockPID(systemLockFile); ok && pid == os.Getpid() {
+		os.Remove(systemLockFile)
+	}
+}
+
+// readLockPID extrae la línea "pid: N" del archivo de bloqueo
+func readLockPID(path string) (int, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, false
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		if value, found := strings.CutPrefix(line, "pid:"); found {
+			pid, err := strconv.Atoi(strings.TrimSpace(value))
+			return pid, err == nil && pid > 0
+		}
+	}
+	return 0, false
+}
+
+// isProcessAlive indica si existe un proceso con ese PID (según /proc)
+func isProcessAlive(pid int) bool {
+	_, err := os.Stat(fmt.Sprintf("/proc/%d", pid))
+	return err == nil
+}
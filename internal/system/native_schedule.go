@@ -0,0 +1,209 @@
+package system
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+/**
+ * NativeSchedule - Horario del modo nocturno nativo del escritorio
+ *
+ * Se lee de GNOME Night Light o KDE Night Color para que el usuario
+ * pueda conservar su rutina al cambiar a esta aplicación.
+ *
+ * @struct {NativeSchedule}
+ * @property {string} Source - Escritorio de origen ("GNOME Night Light" o "KDE Night Color")
+ * @property {bool} Automatic - Horario según la puesta y salida del sol
+ * @property {string} StartTime - Inicio de la noche "HH:MM" (horario fijo)
+ * @property {string} EndTime - Fin de la noche "HH:MM" (horario fijo)
+ * @property {float64} NightTemp - Temperatura nocturna en Kelvin
+ * @property {float64} DayTemp - Temperatura diurna en Kelvin
+ * @property {int} TransitionTime - Minutos de transición (0 si el escritorio no lo define)
+ * @property {bool} HasLocation - Si Latitude/Longitude son válidas
+ */
+type NativeSchedule struct {
+	Source         string
+	Automatic      bool
+	StartTime      string
+	EndTime        string
+	NightTemp      float64
+	DayTemp        float64
+	TransitionTime int
+	Latitude       float64
+	Longitude      float64
+	HasLocation    bool
+}
+
+// Describe resume el horario para mostrarlo al usuario
+func (ns *NativeSchedule) Describe() string {
+	when := fmt.Sprintf("de %s a %s", ns.StartTime, ns.EndTime)
+	if ns.Automatic {
+		when = "del atardecer al amanecer"
+	}
+	return fmt.Sprintf("%s: %s, %.0fK", ns.Source, when, ns.NightTemp)
+}
+
+/**
+ * DetectNativeSchedule - Busca un horario nocturno nativo activo
+ *
+ * Consulta primero el escritorio actual (XDG_CURRENT_DESKTOP) y después
+ * el otro. Solo devuelve horarios activos: si el usuario no usaba el
+ * modo nocturno del sistema no hay rutina que conservar.
+ *
+ * @returns {*NativeSchedule} Horario encontrado o nil si no hay ninguno activo
+ */
+func DetectNativeSchedule() *NativeSchedule {
+	detectors := []func() *NativeSchedule{readGnomeSchedule, readKDESchedule}
+	if strings.Contains(strings.ToUpper(os.Getenv("XDG_CURRENT_DESKTOP")), "KDE") {
+		detectors[0], detectors[1] = detectors[1], detectors[0]
+	}
+
+	for _, detect := range detectors {
+		if schedule := detect(); schedule != nil {
+			return schedule
+		}
+	}
+	return nil
+}
+
+// gnomeColorSchema es el esquema de gsettings de Night Light
+const gnomeColorSchema = "org.gnome.settings-daemon.plugins.color"
+
+/**
+ * readGnomeSchedule - Lee la configuración de GNOME Night Light con gsettings
+ *
+ * @private
+ * @returns {*NativeSchedule} Horario o nil si Night Light no está activo
+ */
+func readGnomeSchedule() *NativeSchedule {
+	get := func(key string) string {
+		output, err := toolCommand("gsettings", "get", gnomeColorSchema, key).Output()
+		if err != nil {
+			return ""
+		}
+		return strings.TrimSpace(string(output))
+	}
+
+	if get("night-light-enabled") != "true" {
+		return nil
+	}
+
+	schedule := &NativeSchedule{
+		Source:    "GNOME Night Light",
+		Automatic: get("night-light-schedule-automatic") == "true",
+		StartTime: gnomeHoursToTime(get("night-light-schedule-from"), "20:00"),
+		EndTime:   gnomeHoursToTime(get("night-light-schedule-to"), "06:00"),
+		NightTemp: 2700,
+		DayTemp:   6500, // GNOME no modifica la temperatura de día
+	}
+
+	// "uint32 2700"
+	if value, err := strconv.ParseFloat(strings.TrimPrefix(get("night-light-temperature"), "uint32 "), 64); err == nil {
+		schedule.NightTemp = value
+	}
+
+	// "(40.4, -3.7)"; GNOME usa (91, 181) cuando no conoce la ubicación
+	coords := strings.Trim(get("night-light-last-coordinates"), "()")
+	if parts := strings.Split(coords, ","); len(parts) == 2 {
+		lat, latErr := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+		lon, lonErr := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+		if latErr == nil && lonErr == nil && math.Abs(lat) <= 90 && math.Abs(lon) <= 180 {
+			schedule.Latitude, schedule.Longitude, schedule.HasLocation = lat, lon, true
+		}
+	}
+	return schedule
+}
+
+// gnomeHoursToTime convierte horas decimales de GNOME ("20.5") en "HH:MM"
+func gnomeHoursToTime(value, fallback string) string {
+	hours, err := strconv.ParseFloat(value, 64)
+	if err != nil || hours < 0 || hours >= 24 {
+		return fallback
+	}
+	minutes := int(math.Round(hours*60)) % (24 * 60)
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
+
+/**
+ * readKDESchedule - Lee la sección [NightColor] de kwinrc con kreadconfig
+ *
+ * Las claves ausentes toman los valores por defecto de KWin.
+ *
+ * @private
+ * @returns {*NativeSchedule} Horario o nil si Night Color no está activo
+ */
+func readKDESchedule() *NativeSchedule {
+	tool := ""
+	for _, candidate := range []string{"kreadconfig6", "kreadconfig5"} {
+		if lookupTool(candidate) == nil {
+			tool = candidate
+			break
+		}
+	}
+	if tool == "" {
+		return nil
+	}
+
+	get := func(key, fallback string) string {
+		output, err := toolCommand(tool, "--file", "kwinrc", "--group", "NightColor", "--key", key).Output()
+		if value := strings.TrimSpace(string(output)); err == nil && value != "" {
+			return value
+		}
+		return fallback
+	}
+
+	if get("Active", "false") != "true" {
+		return nil
+	}
+
+	mode := get("Mode", "Automatic")
+	if mode == "Constant" {
+		return nil // Temperatura fija todo el día: no hay horario que importar
+	}
+
+	schedule := &NativeSchedule{
+		Source:    "KDE Night Color",
+		Automatic: mode == "Automatic" || mode == "Location",
+		StartTime: kdeTimeToTime(get("EveningBeginFixed", "1800"), "18:00"),
+		EndTime:   kdeTimeToTime(get("MorningBeginFixed", "0600"), "06:00"),
+		NightTemp: 4500,
+		DayTemp:   6500,
+	}
+	if value, err := strconv.ParseFloat(get("NightTemperature", "4500"), 64); err == nil {
+		schedule.NightTemp = value
+	}
+	if value, err := strconv.ParseFloat(get("DayTemperature", "6500"), 64); err == nil {
+		schedule.DayTemp = value
+	}
+	if value, err := strconv.Atoi(get("TransitionTime", "30")); err == nil {
+		schedule.TransitionTime = value
+	}
+
+	// Ubicación manual (modo Location) o la última detectada automáticamente
+	latKey, lonKey := "LatitudeAuto", "LongitudeAuto"
+	if mode == "Location" {
+		latKey, lonKey = "LatitudeFixed", "LongitudeFixed"
+	}
+	lat, latErr := strconv.ParseFloat(get(latKey, ""), 64)
+	lon, lonErr := strconv.ParseFloat(get(lonKey, ""), 64)
+	if latErr == nil && lonErr == nil && math.Abs(lat) <= 90 && math.Abs(lon) <= 180 {
+		schedule.Latitude, schedule.Longitude, schedule.HasLocation = lat, lon, true
+	}
+	return schedule
+}
+
+// kdeTimeToTime convierte la hora "HHMM" de kwinrc en "HH:MM"
+func kdeTimeToTime(value, fallback string) string {
+	if len(value) != 4 {
+		return fallback
+	}
+	hours, hErr := strconv.Atoi(value[:2])
+	minutes, mErr := strconv.Atoi(value[2:])
+	if hErr != nil || mErr != nil || hours > 23 || minutes > 59 {
+		return fallback
+	}
+	return fmt.Sprintf("%02d:%02d", hours, minutes)
+}
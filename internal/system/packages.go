@@ -0,0 +1,154 @@
+package system
+
+import (
+	"bufio"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Gestores de paquetes para los que se conocen los nombres de las herramientas
+const (
+	PackageManagerApt    = "apt"
+	PackageManagerDnf    = "dnf"
+	PackageManagerPacman = "pacman"
+)
+
+// toolPackages es el paquete que instala cada herramienta externa en cada distribución
+var toolPackages = map[string]map[string]string{
+	"xrandr": {
+		PackageManagerApt:    "x11-xserver-utils",
+		PackageManagerDnf:    "xrandr",
+		PackageManagerPacman: "xorg-xrandr",
+	},
+	"xprop": {
+		PackageManagerApt:    "x11-utils",
+		PackageManagerDnf:    "xprop",
+		PackageManagerPacman: "xorg-xprop",
+	},
+	"gdbus": {
+		PackageManagerApt:    "libglib2.0-bin",
+		PackageManagerDnf:    "glib2",
+		PackageManagerPacman: "glib2",
+	},
+	"ddcutil": {
+		PackageManagerApt:    "ddcutil",
+		PackageManagerDnf:    "ddcutil",
+		PackageManagerPacman: "ddcutil",
+	},
+	"gammastep": {
+		PackageManagerApt:    "gammastep",
+		PackageManagerDnf:    "gammastep",
+		PackageManagerPacman: "gammastep",
+	},
+	"wlsunset": {
+		PackageManagerApt:    "wlsunset",
+		PackageManagerDnf:    "wlsunset",
+		PackageManagerPacman: "wlsunset",
+	},
+	"kscreen-doctor": {
+		PackageManagerApt:    "libkf6screen-bin",
+		PackageManagerDnf:    "kscreen",
+		PackageManagerPacman: "libkscreen",
+	},
+}
+
+// installVerbs es el comando de instalación de cada gestor de paquetes
+var installVerbs = map[string]string{
+	PackageManagerApt:    "sudo apt install",
+	PackageManagerDnf:    "sudo dnf install",
+	PackageManagerPacman: "sudo pacman -S",
+}
+
+/**
+ * DetectPackageManager - Identifica el gestor de paquetes de la distribución
+ *
+ * Lee ID e ID_LIKE de /etc/os-release (en Flatpak, el del host en
+ * /run/host/os-release) y, si no lo reconoce, busca apt, dnf o pacman
+ * en el PATH.
+ *
+ * @returns {string} PackageManagerApt, PackageManagerDnf, PackageManagerPacman o "" si es desconocido
+ */
+func DetectPackageManager() string {
+	for _, path := range []string{"/run/host/os-release", "/etc/os-release"} {
+		if manager := packageManagerFromOSRelease(path); manager != "" {
+			return manager
+		}
+	}
+
+	for _, manager := range []string{PackageManagerApt, PackageManagerDnf, PackageManagerPacman} {
+		if _, err := exec.LookPath(manager); err == nil {
+			return manager
+		}
+	}
+	return ""
+}
+
+// packageManagerFromOSRelease deduce el gestor de paquetes a partir de ID e ID_LIKE
+func packageManagerFromOSRelease(path string) string {
+	file, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	var ids []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		key, value, found := strings.Cut(scanner.Text(), "=")
+		if found && (key == "ID" || key == "ID_LIKE") {
+			ids = append(ids, strings.Fields(strings.Trim(value, `"'`))...)
+		}
+	}
+
+	for _, id := range ids {
+		switch id {
+		case "debian", "ubuntu", "linuxmint", "pop":
+			return PackageManagerApt
+		case "fedora", "rhel", "centos":
+			return PackageManagerDnf
+		case "arch", "manjaro", "endeavouros":
+			return PackageManagerPacman
+		}
+	}
+	return ""
+}
+
+/**
+ * InstallCommand - Comando para instalar las herramientas indicadas
+ *
+ * @param {string} manager - Gestor de paquetes (DetectPackageManager)
+ * @param {...string} tools - Herramientas que faltan
+ * @returns {string} Comando listo para copiar, o "" si no se conoce el gestor o los paquetes
+ * @example
+ *   InstallCommand("apt", "xrandr") // "sudo apt install x11-xserver-utils"
+ */
+func InstallCommand(manager string, tools ...string) string {
+	verb, ok := installVerbs[manager]
+	if !ok {
+		return ""
+	}
+
+	var packages []string
+	seen := make(map[string]bool)
+	for _, tool := range tools {
+		name := toolPackages[tool][manager]
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		packages = append(packages, name)
+	}
+	if len(packages) == 0 {
+		return ""
+	}
+	return verb + " " + strings.Join(packages, " ")
+}
+
+// newMissingToolError crea el error de herramientas ausentes con el comando de instalación de esta distribución
+func newMissingToolError(tools ...string) *MissingToolError {
+	return &MissingToolError{
+		Tools:          tools,
+		InstallCommand: InstallCommand(DetectPackageManager(), tools...),
+	}
+}
@@ -0,0 +1,167 @@
+package system
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"luznocturna/luz-nocturna/internal/logging"
+	"luznocturna/luz-nocturna/internal/paths"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// pluginTimeout es el tiempo máximo que puede tardar un plugin en responder
+const pluginTimeout = 5 * time.Second
+
+// PluginDir devuelve el directorio donde se buscan los backends externos (~/.config/luz-nocturna/backends)
+func PluginDir() string {
+	return filepath.Join(paths.ConfigDir(), "backends")
+}
+
+/**
+ * PluginBackend - Backend de gamma externo basado en un ejecutable
+ *
+ * Cualquier ejecutable del directorio PluginDir puede añadir soporte para
+ * un compositor sin modificar la aplicación. Cada operación lanza el
+ * ejecutable, le escribe una orden en una línea por stdin y lee la
+ * respuesta de la primera línea de stdout:
+ *
+ *   detect                        → "ok [descripción]" si puede controlar esta sesión
+ *   apply <r> <g> <b> <kelvin>    → "ok" (r, g y b entre 0 y 1, con el brillo ya aplicado)
+ *   reset                         → "ok"
+ *
+ * Cualquier otra respuesta, "error <mensaje>" o un código de salida
+ * distinto de cero se consideran un fallo.
+ *
+ * @struct {PluginBackend}
+ * @property {string} Name - Nombre del ejecutable
+ * @property {string} Path - Ruta completa del ejecutable
+ * @property {string} Description - Texto devuelto por detect (opcional)
+ */
+type PluginBackend struct {
+	Name        string
+	Path        string
+	Description string
+	dryRun      bool
+}
+
+/**
+ * DiscoverPlugins - Lista los ejecutables del directorio de plugins
+ *
+ * Se devuelven en orden alfabético: un prefijo numérico ("10-hyprland")
+ * permite elegir la prioridad.
+ *
+ * @returns {[]*PluginBackend} Plugins encontrados (vacío si el directorio no existe)
+ */
+func DiscoverPlugins() []*PluginBackend {
+	entries, err := os.ReadDir(PluginDir())
+	if err != nil {
+		return nil
+	}
+
+	var plugins []*PluginBackend
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || !info.Mode().IsRegular() || info.Mode().Perm()&0111 == 0 {
+			continue
+		}
+		plugins = append(plugins, &PluginBackend{Name: entry.Name(), Path: filepath.Join(PluginDir(), entry.Name())})
+	}
+	sort.Slice(plugins, func(i, j int) bool { return plugins[i].Name < plugins[j].Name })
+	return plugins
+}
+
+/**
+ * detectPlugin - Busca el primer plugin que puede controlar esta sesión
+ *
+ * detect no modifica nada, así que también se consulta en dry-run.
+ *
+ * @param {bool} dryRun - Si es true, apply y reset solo se registran
+ * @returns {*PluginBackend} Plugin elegido o nil si ninguno responde "ok"
+ * @private
+ */
+func detectPlugin(dryRun bool) *PluginBackend {
+	for _, plugin := range DiscoverPlugins() {
+		plugin.dryRun = dryRun
+		description, err := plugin.call("detect")
+		if err != nil {
+			logging.Printf("🔌 Plugin %s no disponible: %v\n", plugin.Name, err)
+			continue
+		}
+		plugin.Description = description
+		logging.Printf("🔌 Usando el backend externo %s\n", plugin.Label())
+		return plugin
+	}
+	return nil
+}
+
+// Label devuelve el nombre del plugin con su descripción si la tiene
+func (p *PluginBackend) Label() string {
+	if p.Description == "" {
+		return p.Name
+	}
+	return fmt.Sprintf("%s (%s)", p.Name, p.Description)
+}
+
+// Apply envía los valores gamma y la temperatura al plugin
+func (p *PluginBackend) Apply(r, g, b, temperature float64) error {
+	_, err := p.run(fmt.Sprintf("apply %.4f %.4f %.4f %.0f", r, g, b, temperature))
+	return err
+}
+
+// Reset pide al plugin que restaure la gamma normal
+func (p *PluginBackend) Reset() error {
+	_, err := p.run("reset")
+	return err
+}
+
+// run ejecuta una orden que modifica la gamma (solo se registra en dry-run)
+func (p *PluginBackend) run(request string) (string, error) {
+	if p.dryRun {
+		logging.Printf("🧪 [dry-run] %s <<< %s\n", p.Path, request)
+		return "", nil
+	}
+	return p.call(request)
+}
+
+/**
+ * call - Lanza el plugin con una orden y devuelve su respuesta
+ *
+ * @param {string} request - Orden completa ("apply 1.0000 0.8000 0.6000 4000")
+ * @returns {string, error} Texto tras "ok" o error si el plugin falló
+ * @private
+ */
+func (p *PluginBackend) call(request string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), pluginTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, p.Path)
+	cmd.Stdin = strings.NewReader(request + "\n")
+	output, err := cmd.Output()
+	if ctx.Err() != nil {
+		return "", fmt.Errorf("sin respuesta en %v", pluginTimeout)
+	}
+
+	line, _, _ := strings.Cut(string(output), "\n")
+	line = strings.TrimSpace(line)
+	if err != nil {
+		if line != "" {
+			return "", fmt.Errorf("%s: %w", line, err)
+		}
+		return "", err
+	}
+
+	status, detail, _ := strings.Cut(line, " ")
+	switch status {
+	case "ok":
+		return strings.TrimSpace(detail), nil
+	case "error":
+		return "", errors.New(strings.TrimSpace(detail))
+	default:
+		return "", fmt.Errorf("respuesta no válida %q", line)
+	}
+}
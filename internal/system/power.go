@@ -0,0 +1,121 @@
+package system
+
+import (
+	"context"
+
+	"github.com/godbus/dbus/v5"
+
+	"luznocturna/luz-nocturna/internal/logging"
+)
+
+// Objetos de UPower que describen la alimentación del equipo
+const (
+	upowerService       = "org.freedesktop.UPower"
+	upowerPath          = dbus.ObjectPath("/org/freedesktop/UPower")
+	upowerDisplayDevice = dbus.ObjectPath("/org/freedesktop/UPower/devices/DisplayDevice")
+)
+
+// PowerState describe la alimentación del equipo
+type PowerState struct {
+	OnBattery  bool    // Funcionando con batería
+	Percentage float64 // Carga de la batería combinada (0-100; 0 si no hay batería)
+}
+
+/**
+ * PowerWatcher - Sigue la alimentación del equipo con UPower
+ *
+ * Escucha PropertiesChanged de UPower (OnBattery) y de su dispositivo
+ * combinado DisplayDevice (Percentage) en el bus del sistema.
+ *
+ * @struct {PowerWatcher}
+ * @property {context.CancelFunc} cancel - Detiene el seguimiento
+ */
+type PowerWatcher struct {
+	cancel context.CancelFunc
+}
+
+/**
+ * StartPowerWatcher - Empieza a seguir la alimentación del equipo
+ *
+ * onChange se llama desde la goroutine de D-Bus, primero con el estado
+ * actual y después con cada cambio de OnBattery o de Percentage.
+ *
+ * @param {func(PowerState)} onChange - Callback con el nuevo estado de alimentación
+ * @returns {*PowerWatcher, error} Seguimiento activo; error si UPower no está disponible
+ * @example
+ *   watcher, err := StartPowerWatcher(func(state PowerState) { fmt.Println(state.OnBattery) })
+ *   defer watcher.Stop()
+ */
+func StartPowerWatcher(onChange func(PowerState)) (*PowerWatcher, error) {
+	conn, err := dbus.ConnectSystemBus()
+	if err != nil {
+		return nil, err
+	}
+
+	var state PowerState
+	if err := conn.Object(upowerService, upowerPath).StoreProperty(upowerService+".OnBattery", &state.OnBattery); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if err := conn.Object(upowerService, upowerDisplayDevice).StoreProperty(upowerService+".Device.Percentage", &state.Percentage); err != nil {
+		// Sin carga conocida se supone llena, para no activar por error un umbral de batería baja
+		logging.Printf("⚠️  UPower no informa de la carga de la batería: %v\n", err)
+		state.Percentage = 100
+	}
+
+	for _, path := range []dbus.ObjectPath{upowerPath, upowerDisplayDevice} {
+		if err := conn.AddMatchSignal(
+			dbus.WithMatchObjectPath(path),
+			dbus.WithMatchInterface("org.freedesktop.DBus.Properties"),
+			dbus.WithMatchMember("PropertiesChanged"),
+		); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+
+	signals := make(chan *dbus.Signal, 10)
+	conn.Signal(signals)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	go func() {
+		defer conn.Close()
+		onChange(state)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case signal, ok := <-signals:
+				if !ok {
+					return // Se cerró la conexión con el bus
+				}
+				if signal == nil || len(signal.Body) < 2 {
+					continue
+				}
+				changed, ok := signal.Body[1].(map[string]dbus.Variant)
+				if !ok {
+					continue
+				}
+				onBattery, batteryChanged := changed["OnBattery"].Value().(bool)
+				percentage, percentageChanged := changed["Percentage"].Value().(float64)
+				if batteryChanged {
+					state.OnBattery = onBattery
+				}
+				if percentageChanged {
+					state.Percentage = percentage
+				}
+				if batteryChanged || percentageChanged {
+					onChange(state)
+				}
+			}
+		}
+	}()
+
+	logging.Println("🔋 Siguiendo la alimentación (UPower)")
+	return &PowerWatcher{cancel: cancel}, nil
+}
+
+// Stop detiene el seguimiento
+func (w *PowerWatcher) Stop() {
+	w.cancel()
+}
@@ -0,0 +1,592 @@
+package system
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Opcodes del protocolo X11 y de la extensión RandR usados por el cliente mínimo
+const (
+	x11QueryExtension = 98
+	x11GetInputFocus  = 43
+
+	randrQueryVersion              = 0
+	randrGetScreenResources        = 8
+	randrGetOutputInfo             = 9
+	randrGetCrtcGammaSize          = 22
+	randrSetCrtcGamma              = 24
+	randrGetScreenResourcesCurrent = 25
+)
+
+// randrTimeout limita el tiempo total de una conexión con el servidor X
+const randrTimeout = 5 * time.Second
+
+/**
+ * randrConn - Cliente mínimo del protocolo X11 para la extensión RandR
+ *
+ * Solo implementa lo necesario para subir rampas gamma completas por
+ * CRTC (RRSetCrtcGamma), sin depender de Xlib ni de cgo. xrandr --gamma
+ * redondea cada canal a dos decimales; con las rampas se aprovechan
+ * todas las entradas de la LUT del hardware (1024 o 4096 en paneles de
+ * 10 bits) y desaparecen los escalones en degradados y transiciones.
+ *
+ * @struct {randrConn}
+ * @property {net.Conn} conn - Socket con el servidor X
+ * @property {uint32} root - Ventana raíz de la primera pantalla
+ * @property {byte} opcode - Opcode mayor asignado a RandR
+ * @property {int} maxRequest - Tamaño máximo de petición en unidades de 4 bytes
+ * @property {map[uint16]error} failed - Errores recibidos para peticiones sin respuesta
+ * @private
+ */
+type randrConn struct {
+	conn       net.Conn
+	root       uint32
+	opcode     byte
+	minor      uint32
+	maxRequest int
+	seq        uint16
+	failed     map[uint16]error
+}
+
+/**
+ * openRandR - Conecta con el servidor X de $DISPLAY y prepara RandR
+ *
+ * @returns {*randrConn, error} Conexión lista o error si no hay servidor o RandR < 1.2
+ * @private
+ */
+func openRandR() (*randrConn, error) {
+	network, address, number, err := parseX11Display(os.Getenv("DISPLAY"))
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := net.DialTimeout(network, address, randrTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("no se pudo conectar al servidor X: %w", err)
+	}
+	conn.SetDeadline(time.Now().Add(randrTimeout))
+
+	c := &randrConn{conn: conn, failed: make(map[uint16]error)}
+	if err := c.setup(number); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if err := c.initExtension(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// close cierra la conexión con el servidor X
+func (c *randrConn) close() {
+	c.conn.Close()
+}
+
+/**
+ * parseX11Display - Interpreta $DISPLAY (":0", ":1.0", "unix:0", "host:0")
+ *
+ * @param {string} display - Valor de $DISPLAY
+ * @returns {string, string, string, error} Red, dirección y número de display
+ * @private
+ */
+func parseX11Display(display string) (network, address, number string, err error) {
+	colon := strings.LastIndex(display, ":")
+	if display == "" || colon < 0 {
+		return "", "", "", errors.New("DISPLAY no está definido")
+	}
+
+	host := display[:colon]
+	number = display[colon+1:]
+	if dot := strings.Index(number, "."); dot >= 0 {
+		number = number[:dot]
+	}
+	port, convErr := strconv.Atoi(number)
+	if convErr != nil {
+		return "", "", "", fmt.Errorf("DISPLAY no válido: %q", display)
+	}
+
+	if host == "" || host == "unix" {
+		return "unix", "/tmp/.X11-unix/X" + number, number, nil
+	}
+	return "tcp", net.JoinHostPort(host, strconv.Itoa(6000+port)), number, nil
+}
+
+/**
+ * xauthCookie - Busca la cookie MIT-MAGIC-COOKIE-1 del display en $XAUTHORITY
+ *
+ * @param {string} number - Número de display
+ * @returns {[]byte, []byte} Nombre y datos de la autorización (nil si no hay)
+ * @private
+ */
+func xauthCookie(number string) (name, data []byte) {
+	path := os.Getenv("XAUTHORITY")
+	if path == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, nil
+		}
+		path = filepath.Join(home, ".Xauthority")
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil
+	}
+
+	const (
+		familyLocal = 256
+		familyWild  = 65535
+	)
+	hostname, _ := os.Hostname()
+	reader := bytes.NewReader(raw)
+	readField := func() ([]byte, bool) {
+		var length uint16
+		if binary.Read(reader, binary.BigEndian, &length) != nil {
+			return nil, false
+		}
+		field := make([]byte, length)
+		_, err := io.ReadFull(reader, field)
+		return field, err == nil
+	}
+
+	var fallbackName, fallbackData []byte
+	for {
+		var family uint16
+		if binary.Read(reader, binary.BigEndian, &family) != nil {
+			return fallbackName, fallbackData
+		}
+		address, ok1 := readField()
+		display, ok2 := readField()
+		authName, ok3 := readField()
+		authData, ok4 := readField()
+		if !ok1 || !ok2 || !ok3 || !ok4 {
+			return fallbackName, fallbackData
+		}
+		if string(authName) != "MIT-MAGIC-COOKIE-1" || (len(display) > 0 && string(display) != number) {
+			continue
+		}
+		if family == familyWild || (family == familyLocal && string(address) == hostname) {
+			return authName, authData
+		}
+		if fallbackName == nil {
+			fallbackName, fallbackData = authName, authData
+		}
+	}
+}
+
+/**
+ * setup - Envía el saludo inicial del protocolo y lee la ventana raíz
+ *
+ * @param {string} number - Número de display (para elegir la cookie)
+ * @returns {error} Error si el servidor rechaza la conexión
+ * @private
+ */
+func (c *randrConn) setup(number string) error {
+	authName, authData := xauthCookie(number)
+
+	request := make([]byte, 12)
+	request[0] = 'l' // Little endian
+	binary.LittleEndian.PutUint16(request[2:], 11)
+	binary.LittleEndian.PutUint16(request[6:], uint16(len(authName)))
+	binary.LittleEndian.PutUint16(request[8:], uint16(len(authData)))
+	request = append(request, x11Pad(authName)...)
+	request = append(request, x11Pad(authData)...)
+	if _, err := c.conn.Write(request); err != nil {
+		return fmt.Errorf("no se pudo iniciar el protocolo X11: %w", err)
+	}
+
+	header := make([]byte, 8)
+	if _, err := io.ReadFull(c.conn, header); err != nil {
+		return fmt.Errorf("no se pudo iniciar el protocolo X11: %w", err)
+	}
+	extra := make([]byte, int(binary.LittleEndian.Uint16(header[6:]))*4)
+	if _, err := io.ReadFull(c.conn, extra); err != nil {
+		return fmt.Errorf("no se pudo iniciar el protocolo X11: %w", err)
+	}
+
+	switch header[0] {
+	case 1:
+	case 0:
+		reason := extra[:min(int(header[1]), len(extra))]
+		return fmt.Errorf("el servidor X rechazó la conexión: %s", strings.TrimSpace(string(reason)))
+	default:
+		return errors.New("el servidor X pide una autenticación no soportada")
+	}
+
+	// Bloque fijo de 32 bytes, nombre del fabricante y formatos; después la primera pantalla
+	if len(extra) < 32 {
+		return errors.New("respuesta de conexión X11 incompleta")
+	}
+	vendorLength := int(binary.LittleEndian.Uint16(extra[16:]))
+	formats := int(extra[21])
+	offset := 32 + vendorLength + x11PadLength(vendorLength) + 8*formats
+	if len(extra) < offset+4 {
+		return errors.New("respuesta de conexión X11 incompleta")
+	}
+	c.maxRequest = int(binary.LittleEndian.Uint16(extra[18:]))
+	c.root = binary.LittleEndian.Uint32(extra[offset:])
+	return nil
+}
+
+// initExtension localiza RandR y negocia la versión 1.3 (gamma por CRTC desde la 1.2)
+func (c *randrConn) initExtension() error {
+	name := []byte("RANDR")
+	request := make([]byte, 8, 8+len(name)+x11PadLength(len(name)))
+	request[0] = x11QueryExtension
+	binary.LittleEndian.PutUint16(request[4:], uint16(len(name)))
+	request = append(request, x11Pad(name)...)
+
+	reply, err := c.roundTrip(request)
+	if err != nil {
+		return err
+	}
+	if reply[8] == 0 {
+		return errors.New("el servidor X no tiene la extensión RandR")
+	}
+	c.opcode = reply[9]
+
+	version := c.randrRequest(randrQueryVersion, 8)
+	binary.LittleEndian.PutUint32(version[4:], 1)
+	binary.LittleEndian.PutUint32(version[8:], 3)
+	reply, err = c.roundTrip(version)
+	if err != nil {
+		return err
+	}
+	major, minor := binary.LittleEndian.Uint32(reply[8:]), binary.LittleEndian.Uint32(reply[12:])
+	if major < 1 || (major == 1 && minor < 2) {
+		return fmt.Errorf("RandR %d.%d no permite rampas gamma por CRTC", major, minor)
+	}
+	c.minor = minor
+	return nil
+}
+
+// randrRequest crea una petición RandR con el cuerpo indicado (sin la cabecera de 4 bytes)
+func (c *randrConn) randrRequest(minor byte, bodyLength int) []byte {
+	request := make([]byte, 4+bodyLength)
+	request[0] = c.opcode
+	request[1] = minor
+	return request
+}
+
+/**
+ * send - Escribe una petición y devuelve su número de secuencia
+ *
+ * @param {[]byte} request - Petición con longitud múltiplo de 4
+ * @returns {uint16, error} Secuencia asignada
+ * @private
+ */
+func (c *randrConn) send(request []byte) (uint16, error) {
+	binary.LittleEndian.PutUint16(request[2:], uint16(len(request)/4))
+	if _, err := c.conn.Write(request); err != nil {
+		return 0, fmt.Errorf("error escribiendo al servidor X: %w", err)
+	}
+	c.seq++
+	return c.seq, nil
+}
+
+/**
+ * roundTrip - Envía una petición y espera su respuesta
+ *
+ * Los errores de peticiones anteriores sin respuesta (como
+ * RRSetCrtcGamma) se guardan en c.failed; los eventos se descartan.
+ *
+ * @param {[]byte} request - Petición con respuesta
+ * @returns {[]byte, error} Respuesta completa o el error X11 de la petición
+ * @private
+ */
+func (c *randrConn) roundTrip(request []byte) ([]byte, error) {
+	seq, err := c.send(request)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		packet := make([]byte, 32)
+		if _, err := io.ReadFull(c.conn, packet); err != nil {
+			return nil, fmt.Errorf("error leyendo del servidor X: %w", err)
+		}
+		packetSeq := binary.LittleEndian.Uint16(packet[2:])
+
+		switch packet[0] {
+		case 0: // Error
+			x11Err := fmt.Errorf("error X11 %d en la petición %d.%d", packet[1], packet[10], binary.LittleEndian.Uint16(packet[8:]))
+			if packetSeq == seq {
+				return nil, x11Err
+			}
+			c.failed[packetSeq] = x11Err
+		case 1: // Respuesta
+			extra := make([]byte, int(binary.LittleEndian.Uint32(packet[4:]))*4)
+			if _, err := io.ReadFull(c.conn, extra); err != nil {
+				return nil, fmt.Errorf("error leyendo del servidor X: %w", err)
+			}
+			if packetSeq == seq {
+				return append(packet, extra...), nil
+			}
+		case 35: // GenericEvent: también lleva datos adicionales
+			extra := make([]byte, int(binary.LittleEndian.Uint32(packet[4:]))*4)
+			if _, err := io.ReadFull(c.conn, extra); err != nil {
+				return nil, fmt.Errorf("error leyendo del servidor X: %w", err)
+			}
+		}
+	}
+}
+
+// sync espera a que el servidor procese todas las peticiones enviadas
+func (c *randrConn) sync() error {
+	request := make([]byte, 4)
+	request[0] = x11GetInputFocus
+	_, err := c.roundTrip(request)
+	return err
+}
+
+// randrOutput es una salida conectada y encendida
+type randrOutput struct {
+	id   uint32
+	crtc uint32
+}
+
+/**
+ * outputCrtcs - Asocia cada salida conectada y encendida con su CRTC
+ *
+ * @returns {map[string]uint32, error} Nombre de salida (p. ej. "HDMI-1") → CRTC
+ * @private
+ */
+func (c *randrConn) outputCrtcs() (map[string]uint32, error) {
+	outputs, err := c.outputs()
+	if err != nil {
+		return nil, err
+	}
+	crtcs := make(map[string]uint32, len(outputs))
+	for name, output := range outputs {
+		crtcs[name] = output.crtc
+	}
+	return crtcs, nil
+}
+
+/**
+ * outputs - Lista las salidas conectadas y encendidas
+ *
+ * @returns {map[string]randrOutput, error} Nombre de salida (p. ej. "HDMI-1") → salida y CRTC
+ * @private
+ */
+func (c *randrConn) outputs() (map[string]randrOutput, error) {
+	minor := byte(randrGetScreenResourcesCurrent)
+	if c.minor < 3 {
+		minor = randrGetScreenResources
+	}
+	request := c.randrRequest(minor, 4)
+	binary.LittleEndian.PutUint32(request[4:], c.root)
+	resources, err := c.roundTrip(request)
+	if err != nil {
+		return nil, err
+	}
+
+	configTimestamp := binary.LittleEndian.Uint32(resources[12:])
+	crtcCount := int(binary.LittleEndian.Uint16(resources[16:]))
+	outputCount := int(binary.LittleEndian.Uint16(resources[18:]))
+	offset := 32 + 4*crtcCount
+	if len(resources) < offset+4*outputCount {
+		return nil, errors.New("respuesta de RandR incompleta")
+	}
+
+	result := make(map[string]randrOutput)
+	for i := 0; i < outputCount; i++ {
+		output := binary.LittleEndian.Uint32(resources[offset+4*i:])
+
+		info := c.randrRequest(randrGetOutputInfo, 8)
+		binary.LittleEndian.PutUint32(info[4:], output)
+		binary.LittleEndian.PutUint32(info[8:], configTimestamp)
+		reply, err := c.roundTrip(info)
+		if err != nil || len(reply) < 36 {
+			continue
+		}
+
+		crtc := binary.LittleEndian.Uint32(reply[12:])
+		connected := reply[24] == 0
+		if crtc == 0 || !connected {
+			continue
+		}
+		nameOffset := 36 + 4*(int(binary.LittleEndian.Uint16(reply[26:]))+
+			int(binary.LittleEndian.Uint16(reply[28:]))+
+			int(binary.LittleEndian.Uint16(reply[32:])))
+		nameLength := int(binary.LittleEndian.Uint16(reply[34:]))
+		if len(reply) < nameOffset+nameLength {
+			continue
+		}
+		result[string(reply[nameOffset:nameOffset+nameLength])] = randrOutput{id: output, crtc: crtc}
+	}
+	return result, nil
+}
+
+// gammaSize devuelve el número de entradas de la LUT gamma del CRTC
+func (c *randrConn) gammaSize(crtc uint32) (int, error) {
+	request := c.randrRequest(randrGetCrtcGammaSize, 4)
+	binary.LittleEndian.PutUint32(request[4:], crtc)
+	reply, err := c.roundTrip(request)
+	if err != nil {
+		return 0, err
+	}
+	return int(binary.LittleEndian.Uint16(reply[8:])), nil
+}
+
+/**
+ * setGamma - Sube las rampas de los tres canales a un CRTC
+ *
+ * No espera respuesta: los errores se comprueban después con sync.
+ *
+ * @param {uint32} crtc - CRTC de destino
+ * @param {[3][]uint16} ramps - Rampas rojo, verde y azul del tamaño de la LUT
+ * @returns {uint16, error} Secuencia de la petición
+ * @private
+ */
+func (c *randrConn) setGamma(crtc uint32, ramps [3][]uint16) (uint16, error) {
+	size := len(ramps[0])
+	body := 8 + 6*size
+	body += x11PadLength(body)
+	if c.maxRequest > 0 && (4+body)/4 > c.maxRequest {
+		return 0, fmt.Errorf("la LUT de %d entradas excede el tamaño máximo de petición", size)
+	}
+
+	request := c.randrRequest(randrSetCrtcGamma, body)
+	binary.LittleEndian.PutUint32(request[4:], crtc)
+	binary.LittleEndian.PutUint16(request[8:], uint16(size))
+	offset := 12
+	for _, ramp := range ramps {
+		for _, value := range ramp {
+			binary.LittleEndian.PutUint16(request[offset:], value)
+			offset += 2
+		}
+	}
+	return c.send(request)
+}
+
+/**
+ * gammaRamp - Calcula una rampa gamma de alta resolución para un canal
+ *
+ * Usa la misma curva que "xrandr --gamma" (entrada^(1/gamma), limitada
+ * a 1.0) para que el resultado coincida con el método anterior, pero
+ * sin redondear el factor y con tantas entradas como tenga la LUT.
+ *
+ * El contraste mezcla la entrada con una curva en S (smoothstep) que
+ * conserva el negro, el blanco y el gris medio: el punto que usa
+ * "xrandr --verbose" para estimar la gamma no cambia, así que el
+ * vigilante sigue reconociendo la gamma aplicada.
+ *
+ * @param {int} size - Entradas de la LUT (256, 1024, 4096...)
+ * @param {float64} gamma - Factor del canal (1.0 = sin cambio)
+ * @param {float64} contrast - Contraste extra (0 = curva normal)
+ * @param {float64} dim - Escala lineal de la salida, como "xrandr --brightness" (1.0 = sin atenuar)
+ * @returns {[]uint16} Rampa de 16 bits
+ * @private
+ */
+func gammaRamp(size int, gamma, contrast, dim float64) []uint16 {
+	ramp := make([]uint16, size)
+	if gamma <= 0 {
+		gamma = 1.0
+	}
+	if dim <= 0 || dim > 1.0 {
+		dim = 1.0
+	}
+	for i := range ramp {
+		position := 0.0
+		if size > 1 {
+			position = float64(i) / float64(size-1)
+		}
+		if contrast > 0 {
+			curve := position * position * (3 - 2*position)
+			position += contrast * (curve - position)
+		}
+		value := math.Min(math.Pow(position, 1/gamma), 1.0) * dim
+		ramp[i] = uint16(value*65535 + 0.5)
+	}
+	return ramp
+}
+
+/**
+ * applyRandRRamps - Aplica la gamma con rampas completas vía RandR
+ *
+ * Los displays excluidos reciben la rampa identidad. Si no se puede
+ * hablar con el servidor X o ningún display acepta la rampa se devuelve
+ * error para que quien llama use "xrandr --gamma".
+ *
+ * @param {[]string} targets - Displays que reciben el filtro
+ * @param {[]string} excluded - Displays que vuelven a la gamma normal
+ * @param {[3]float64} gamma - Factores rojo, verde y azul
+ * @param {map[string][3]float64} trims - Ajuste fino RGB por display (nil = ninguno)
+ * @param {float64} contrast - Contraste extra de los displays con filtro (0 = curva normal)
+ * @param {float64} dim - Atenuación lineal de los displays con filtro (1.0 = sin atenuar)
+ * @returns {[]string, int, error} Displays aplicados, tamaño de LUT mayor y error
+ * @private
+ */
+func applyRandRRamps(targets, excluded []string, gamma [3]float64, trims map[string][3]float64, contrast, dim float64) ([]string, int, error) {
+	c, err := openRandR()
+	if err != nil {
+		return nil, 0, err
+	}
+	defer c.close()
+
+	crtcs, err := c.outputCrtcs()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	lutSize := 0
+	upload := func(displays []string, gamma [3]float64, trims map[string][3]float64, contrast, dim float64) map[string]uint16 {
+		pending := make(map[string]uint16)
+		for _, display := range displays {
+			crtc, ok := crtcs[display]
+			if !ok {
+				continue
+			}
+			size, err := c.gammaSize(crtc)
+			if err != nil || size < 2 {
+				continue
+			}
+			trimmed := trimGamma(gamma, trims[display])
+			seq, err := c.setGamma(crtc, [3][]uint16{gammaRamp(size, trimmed[0], contrast, dim), gammaRamp(size, trimmed[1], contrast, dim), gammaRamp(size, trimmed[2], contrast, dim)})
+			if err != nil {
+				continue
+			}
+			pending[display] = seq
+			lutSize = max(lutSize, size)
+		}
+		return pending
+	}
+
+	upload(excluded, [3]float64{1.0, 1.0, 1.0}, nil, 0, 1.0)
+	pending := upload(targets, gamma, trims, contrast, dim)
+	if err := c.sync(); err != nil {
+		return nil, 0, err
+	}
+
+	var applied []string
+	for _, display := range targets {
+		if seq, ok := pending[display]; ok && c.failed[seq] == nil {
+			applied = append(applied, display)
+		}
+	}
+	if len(applied) == 0 && len(targets) > 0 {
+		return nil, 0, errors.New("ningún CRTC aceptó la rampa gamma")
+	}
+	return applied, lutSize, nil
+}
+
+// x11PadLength devuelve los bytes de relleno hasta el siguiente múltiplo de 4
+func x11PadLength(length int) int {
+	return (4 - length%4) % 4
+}
+
+// x11Pad devuelve data con relleno hasta un múltiplo de 4 bytes
+func x11Pad(data []byte) []byte {
+	return append(data, make([]byte, x11PadLength(len(data)))...)
+}
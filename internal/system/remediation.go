@@ -0,0 +1,130 @@
+package system
+
+import (
+	"os"
+	"strings"
+)
+
+/**
+ * DetectCompositor - Nombre legible del compositor Wayland de la sesión
+ *
+ * Usa CompositorID; si el compositor no está entre los reconocidos
+ * devuelve el escritorio de XDG_CURRENT_DESKTOP.
+ *
+ * @returns {string} Nombre legible del compositor o del escritorio
+ */
+func DetectCompositor() string {
+	if name, ok := compositorNames[CompositorID()]; ok {
+		return name
+	}
+	if desktop := os.Getenv("XDG_CURRENT_DESKTOP"); desktop != "" {
+		return desktop
+	}
+	return "desconocido"
+}
+
+// isWlroots indica si el compositor implementa wlr-gamma-control (Sway, Hyprland, river...)
+func isWlroots(compositor string) bool {
+	switch compositor {
+	case "Hyprland", "Sway", "Wayfire":
+		return true
+	}
+	desktop := strings.ToLower(compositor)
+	return strings.Contains(desktop, "river") || strings.Contains(desktop, "wayfire") || strings.Contains(desktop, "labwc")
+}
+
+/**
+ * failureReason - Explica por qué un método de la cadena no funcionó
+ *
+ * @param {...string} tools - Herramientas que usa el método
+ * @returns {string} "no instalado: ..." si falta alguna, o un fallo genérico
+ * @private
+ */
+func (gm *GammaManager) failureReason(tools ...string) string {
+	var missing []string
+	for _, tool := range tools {
+		if !gm.isToolAvailable(tool) {
+			missing = append(missing, tool)
+		}
+	}
+	if len(missing) == len(tools) {
+		return "no instalado: " + strings.Join(missing, ", ")
+	}
+	return "el compositor rechazó el cambio"
+}
+
+/**
+ * waylandSuggestions - Pasos concretos para habilitar algún método en este compositor
+ *
+ * @param {string} compositor - Resultado de DetectCompositor
+ * @returns {[]string} Sugerencias ordenadas de la más a la menos recomendable
+ * @private
+ */
+func (gm *GammaManager) waylandSuggestions(compositor string) []string {
+	var suggestions []string
+	if IsFlatpak() {
+		suggestions = append(suggestions,
+			"Dentro de Flatpak solo funcionan KDE Night Color (por D-Bus) y RandR en X11: para gammastep, wlsunset, ddcutil o GNOME Night Light instala el paquete .deb o .rpm")
+	}
+
+	switch {
+	case compositor == "GNOME (Mutter)":
+		suggestions = append(suggestions, "Activa Night Light en Configuración → Pantallas: Mutter solo acepta temperaturas con Night Light habilitado")
+		if !gm.isToolAvailable("gdbus") {
+			suggestions = append(suggestions, "Instala gdbus"+packageHint("gdbus"))
+		}
+	case compositor == "KDE Plasma (KWin)":
+		suggestions = append(suggestions, "Activa Night Color en Preferencias del sistema → Pantalla y monitor")
+	case isWlroots(compositor):
+		suggestions = append(suggestions, "Instala gammastep o wlsunset: usan wlr-gamma-control, que "+compositor+" soporta")
+	default:
+		suggestions = append(suggestions, "Instala gammastep o wlsunset si tu compositor soporta wlr-gamma-control")
+	}
+
+	if !gm.isToolAvailable("ddcutil") {
+		suggestions = append(suggestions, "Para monitores externos instala ddcutil y carga el módulo i2c-dev (sudo modprobe i2c-dev)")
+	}
+	suggestions = append(suggestions, "Como último recurso inicia una sesión X11 desde la pantalla de inicio de sesión")
+	return suggestions
+}
+
+/**
+ * missingWaylandTools - Herramientas recomendadas para este compositor que no están instaladas
+ *
+ * Son las mismas que proponen las sugerencias, para ofrecer un único
+ * comando de instalación que copiar.
+ *
+ * @param {string} compositor - Resultado de DetectCompositor
+ * @returns {[]string} Herramientas ausentes, de la más a la menos recomendable
+ * @private
+ */
+func (gm *GammaManager) missingWaylandTools(compositor string) []string {
+	var recommended []string
+	switch {
+	case compositor == "GNOME (Mutter)":
+		recommended = []string{"gdbus"}
+	case compositor == "KDE Plasma (KWin)":
+		// KWin se controla por D-Bus, sin herramientas externas
+	case gm.isToolAvailable("wlsunset"):
+		// wlsunset ya cubre wlr-gamma-control
+	default:
+		recommended = []string{"gammastep"}
+	}
+	recommended = append(recommended, "ddcutil")
+
+	var missing []string
+	for _, tool := range recommended {
+		if !gm.isToolAvailable(tool) {
+			missing = append(missing, tool)
+		}
+	}
+	return missing
+}
+
+// packageHint devuelve " (paquete X)" para la distribución detectada, o "" si no se conoce
+func packageHint(tool string) string {
+	if name := toolPackages[tool][DetectPackageManager()]; name != "" {
+		return " (paquete " + name + ")"
+	}
+	return ""
+}
@@ -0,0 +1,100 @@
+package system
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"luznocturna/luz-nocturna/internal/logging"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+var (
+	flatpakOnce sync.Once
+	inFlatpak   bool
+)
+
+// flatpakAppDir es donde Flatpak monta los archivos del propio paquete
+const flatpakAppDir = "/app/"
+
+// errSandboxedTool indica que la herramienta solo actuaría sobre el sandbox, no sobre la sesión
+var errSandboxedTool = errors.New("no disponible dentro de Flatpak")
+
+/**
+ * IsFlatpak - Indica si la aplicación se ejecuta dentro de un sandbox Flatpak
+ *
+ * Dentro del sandbox no existen xrandr, gsettings ni las herramientas
+ * Wayland del host, y /proc solo muestra los procesos del propio sandbox.
+ *
+ * @returns {bool} true si existe /.flatpak-info o FLATPAK_ID está definido
+ */
+func IsFlatpak() bool {
+	flatpakOnce.Do(func() {
+		_, err := os.Stat("/.flatpak-info")
+		inFlatpak = err == nil || os.Getenv("FLATPAK_ID") != ""
+	})
+	return inFlatpak
+}
+
+/**
+ * toolCommand - Crea el comando de una herramienta externa
+ *
+ * Fuera de Flatpak equivale a exec.Command. Dentro del sandbox nunca se
+ * sale al host: solo se ejecutan las herramientas incluidas en el propio
+ * paquete (/app). Las del runtime (gsettings, pkill...) actuarían sobre
+ * el sandbox y no sobre la sesión, así que el comando falla al lanzarse
+ * con errSandboxedTool y quien llama lo informa como no disponible.
+ *
+ * @param {string} name - Ejecutable a lanzar
+ * @param {...string} args - Argumentos del comando
+ * @returns {*exec.Cmd} Comando listo para ejecutar
+ * @private
+ */
+func toolCommand(name string, args ...string) *exec.Cmd {
+	return toolCommandContext(context.Background(), name, args...)
+}
+
+// toolCommandContext es como toolCommand pero termina el proceso al cancelar ctx
+func toolCommandContext(ctx context.Context, name string, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, name, args...)
+	if IsFlatpak() {
+		if err := lookupTool(name); err != nil {
+			cmd.Err = err // Start/Run/Output devuelven este error sin lanzar nada
+		}
+	}
+	return cmd
+}
+
+/**
+ * lookupTool - Verifica si una herramienta puede usarse
+ *
+ * Dentro de Flatpak solo cuentan las incluidas en el paquete (/app).
+ *
+ * @param {string} tool - Nombre de la herramienta
+ * @returns {error} Error si no está disponible
+ * @private
+ */
+func lookupTool(tool string) error {
+	path, err := exec.LookPath(tool)
+	if err != nil {
+		return err
+	}
+	if IsFlatpak() && !strings.HasPrefix(path, flatpakAppDir) {
+		return fmt.Errorf("%s: %w", tool, errSandboxedTool)
+	}
+	return nil
+}
+
+/**
+ * checkSandboxAccess - Informa de las limitaciones del sandbox al iniciar
+ *
+ * @private
+ */
+func checkSandboxAccess() {
+	if !IsFlatpak() {
+		return
+	}
+	logging.Println("📦 Ejecutando dentro de Flatpak: sin herramientas del host; se usan RandR (X11), D-Bus y los portales")
+}
@@ -0,0 +1,196 @@
+package system
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/godbus/dbus/v5"
+
+	"luznocturna/luz-nocturna/internal/logging"
+)
+
+// Interfaces de salvapantallas que publican ActiveChanged al bloquear (GNOME y el estándar freedesktop de KDE, Xfce...)
+var screenSaverInterfaces = []string{"org.gnome.ScreenSaver", "org.freedesktop.ScreenSaver"}
+
+/**
+ * SessionLockWatcher - Sigue el bloqueo de la sesión por D-Bus
+ *
+ * Escucha dos fuentes y avisa solo cuando el estado cambia:
+ * - logind: la propiedad LockedHint de la sesión actual (bus del sistema)
+ * - Salvapantallas: la señal ActiveChanged de org.gnome.ScreenSaver y
+ *   org.freedesktop.ScreenSaver (bus de sesión)
+ *
+ * Así funciona tanto con los bloqueadores que informan a logind
+ * (swaylock, GNOME, KDE) como con los que solo emiten ActiveChanged.
+ *
+ * @struct {SessionLockWatcher}
+ * @property {bool} locked - Último estado notificado
+ * @property {context.CancelFunc} cancel - Detiene el seguimiento
+ */
+type SessionLockWatcher struct {
+	mu       sync.Mutex
+	locked   bool
+	onChange func(locked bool)
+	cancel   context.CancelFunc
+}
+
+/**
+ * StartSessionLockWatcher - Empieza a seguir el bloqueo de la sesión
+ *
+ * onChange se llama desde la goroutine de D-Bus con true al bloquear y
+ * false al desbloquear.
+ *
+ * @param {func(bool)} onChange - Callback con el nuevo estado de bloqueo
+ * @returns {*SessionLockWatcher, error} Seguimiento activo; error si no hay ningún bus disponible
+ * @example
+ *   watcher, err := StartSessionLockWatcher(func(locked bool) { fmt.Println(locked) })
+ *   defer watcher.Stop()
+ */
+func StartSessionLockWatcher(onChange func(locked bool)) (*SessionLockWatcher, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	watcher := &SessionLockWatcher{onChange: onChange, cancel: cancel}
+
+	logindErr := watcher.watchLogind(ctx)
+	screenSaverErr := watcher.watchScreenSaver(ctx)
+	if logindErr != nil && screenSaverErr != nil {
+		cancel()
+		return nil, fmt.Errorf("no se puede seguir el bloqueo de la sesión: %v; %v", logindErr, screenSaverErr)
+	}
+
+	logging.Println("🔒 Siguiendo el bloqueo de la sesión")
+	return watcher, nil
+}
+
+// Stop detiene el seguimiento
+func (w *SessionLockWatcher) Stop() {
+	w.cancel()
+}
+
+// notify avisa del nuevo estado si es distinto del último
+func (w *SessionLockWatcher) notify(locked bool) {
+	w.mu.Lock()
+	changed := w.locked != locked
+	w.locked = locked
+	w.mu.Unlock()
+
+	if changed {
+		w.onChange(locked)
+	}
+}
+
+/**
+ * watchLogind - Escucha LockedHint de la sesión actual en logind
+ *
+ * @param {context.Context} ctx - Contexto de cancelación
+ * @returns {error} Error si logind no está disponible
+ * @private
+ */
+func (w *SessionLockWatcher) watchLogind(ctx context.Context) error {
+	conn, err := dbus.ConnectSystemBus()
+	if err != nil {
+		return err
+	}
+
+	path, err := logindSessionPath(conn)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+
+	if err := conn.AddMatchSignal(
+		dbus.WithMatchObjectPath(path),
+		dbus.WithMatchInterface("org.freedesktop.DBus.Properties"),
+		dbus.WithMatchMember("PropertiesChanged"),
+	); err != nil {
+		conn.Close()
+		return err
+	}
+
+	w.follow(ctx, conn, func(signal *dbus.Signal) {
+		if len(signal.Body) < 2 {
+			return
+		}
+		changed, ok := signal.Body[1].(map[string]dbus.Variant)
+		if !ok {
+			return
+		}
+		if hint, ok := changed["LockedHint"].Value().(bool); ok {
+			w.notify(hint)
+		}
+	})
+	return nil
+}
+
+/**
+ * watchScreenSaver - Escucha ActiveChanged de los salvapantallas del escritorio
+ *
+ * @param {context.Context} ctx - Contexto de cancelación
+ * @returns {error} Error si el bus de sesión no está disponible
+ * @private
+ */
+func (w *SessionLockWatcher) watchScreenSaver(ctx context.Context) error {
+	conn, err := dbus.ConnectSessionBus()
+	if err != nil {
+		return err
+	}
+
+	for _, iface := range screenSaverInterfaces {
+		if err := conn.AddMatchSignal(
+			dbus.WithMatchInterface(iface),
+			dbus.WithMatchMember("ActiveChanged"),
+		); err != nil {
+			conn.Close()
+			return err
+		}
+	}
+
+	w.follow(ctx, conn, func(signal *dbus.Signal) {
+		if len(signal.Body) < 1 {
+			return
+		}
+		if active, ok := signal.Body[0].(bool); ok {
+			w.notify(active)
+		}
+	})
+	return nil
+}
+
+// follow entrega las señales de conn a handle en una goroutine hasta que se cancela ctx o se cae la conexión
+func (w *SessionLockWatcher) follow(ctx context.Context, conn *dbus.Conn, handle func(*dbus.Signal)) {
+	signals := make(chan *dbus.Signal, 10)
+	conn.Signal(signals)
+
+	go func() {
+		defer conn.Close()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case signal, ok := <-signals:
+				if !ok {
+					return // Se cerró la conexión con el bus
+				}
+				if signal != nil {
+					handle(signal)
+				}
+			}
+		}
+	}()
+}
+
+// logindSessionPath devuelve la ruta real de la sesión actual en logind: las
+// señales llegan con ella, no con ".../session/auto"
+func logindSessionPath(conn *dbus.Conn) (dbus.ObjectPath, error) {
+	var id string
+	auto := conn.Object("org.freedesktop.login1", "/org/freedesktop/login1/session/auto")
+	if err := auto.StoreProperty("org.freedesktop.login1.Session.Id", &id); err != nil {
+		return "", err
+	}
+	var path dbus.ObjectPath
+	manager := conn.Object("org.freedesktop.login1", "/org/freedesktop/login1")
+	if err := manager.Call("org.freedesktop.login1.Manager.GetSession", 0, id).Store(&path); err != nil {
+		return "", err
+	}
+	return path, nil
+}
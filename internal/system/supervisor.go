@@ -0,0 +1,284 @@
+package system
+
+import (
+	"fmt"
+	"luznocturna/luz-nocturna/internal/logging"
+	"os/exec"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Herramientas que pueden ejecutarse como backend supervisado, en orden de preferencia
+var managedTools = []string{"gammastep", "wlsunset"}
+
+const (
+	managedStartGrace  = 500 * time.Millisecond // Tiempo mínimo vivo para considerar el arranque exitoso
+	managedStopTimeout = 2 * time.Second        // Espera antes de forzar SIGKILL
+	managedMaxRestarts = 5                      // Reinicios seguidos antes de rendirse
+)
+
+/**
+ * ManagedBackend - Backend que ejecuta wlsunset/gammastep como proceso hijo
+ *
+ * En compositores donde solo estas herramientas pueden controlar el gamma,
+ * en lugar de matarlas las lanzamos nosotros con la temperatura calculada.
+ * Cada cambio de temperatura reinicia el proceso, y si el proceso muere
+ * inesperadamente se relanza con backoff exponencial.
+ *
+ * @struct {ManagedBackend}
+ * @property {string} tool - Herramienta supervisada ("gammastep" o "wlsunset")
+ * @property {*exec.Cmd} cmd - Proceso hijo actual (nil si no hay ninguno)
+ * @property {float64} temperature - Temperatura con la que se lanzó el proceso
+ * @property {uint64} generation - Proceso supervisado vigente; cambia al arrancar uno nuevo o al detenerlo
+ * @property {*Capabilities} caps - Caché de herramientas instaladas
+ */
+type ManagedBackend struct {
+	mu          sync.Mutex
+	tool        string
+	cmd         *exec.Cmd
+	temperature float64
+	generation  uint64
+	dryRun      bool
+	caps        *Capabilities
+}
+
+/**
+ * NewManagedBackend - Constructor del backend supervisado
+ *
+ * @param {bool} dryRun - Si es true, solo registra los comandos
+ * @param {*Capabilities} caps - Caché de herramientas compartida con el GammaManager
+ * @returns {*ManagedBackend} Nueva instancia sin proceso activo
+ */
+func NewManagedBackend(dryRun bool, caps *Capabilities) *ManagedBackend {
+	return &ManagedBackend{dryRun: dryRun, caps: caps}
+}
+
+/**
+ * Apply - Lanza (o relanza) la herramienta con la temperatura indicada
+ *
+ * Si ya hay un proceso con la misma temperatura no hace nada. En otro
+ * caso detiene el proceso anterior y arranca uno nuevo, comprobando que
+ * sobreviva el periodo de gracia inicial.
+ *
+ * @param {float64} temperature - Temperatura en Kelvin
+ * @returns {error} Error si ninguna herramienta pudo arrancar
+ */
+func (m *ManagedBackend) Apply(temperature float64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// En dry-run no hay proceso real: basta con que se "lanzara" con esa temperatura
+	if (m.cmd != nil || m.dryRun && m.tool != "") && m.temperature == temperature {
+		return nil
+	}
+
+	m.stopLocked()
+
+	var lastErr error
+	for _, tool := range managedTools {
+		if !m.caps.HasTool(tool) {
+			continue
+		}
+		if err := m.startLocked(tool, temperature); err != nil {
+			lastErr = err
+			continue
+		}
+		return nil
+	}
+
+	if lastErr == nil {
+		lastErr = fmt.Errorf("ni gammastep ni wlsunset están instalados")
+	}
+	return lastErr
+}
+
+/**
+ * Stop - Detiene el proceso supervisado si existe
+ */
+func (m *ManagedBackend) Stop() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.stopLocked()
+}
+
+/**
+ * Manages - Indica si el proceso con ese nombre es nuestro hijo supervisado
+ *
+ * Se usa para que la lógica de control exclusivo no mate nuestro propio backend.
+ *
+ * @param {string} proc - Nombre del proceso
+ * @returns {bool} true si el backend supervisado está usando esa herramienta
+ */
+func (m *ManagedBackend) Manages(proc string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.tool != "" && strings.HasPrefix(proc, m.tool)
+}
+
+/**
+ * IsRunning - Indica si hay un proceso supervisado activo
+ *
+ * @returns {bool} true si el backend está activo
+ */
+func (m *ManagedBackend) IsRunning() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.tool != ""
+}
+
+/**
+ * managedArgs - Construye los argumentos para fijar una temperatura constante
+ *
+ * @param {string} tool - Herramienta a lanzar
+ * @param {float64} temperature - Temperatura en Kelvin
+ * @returns {[]string} Argumentos de línea de comandos
+ * @private
+ */
+func managedArgs(tool string, temperature float64) []string {
+	if tool == "wlsunset" {
+		// wlsunset exige temperatura alta > baja; 1K de diferencia es imperceptible
+		return []string{
+			"-t", fmt.Sprintf("%.0f", temperature),
+			"-T", fmt.Sprintf("%.0f", temperature+1),
+			"-S", "06:00", "-s", "18:00",
+		}
+	}
+	// gammastep mantiene el proceso vivo en Wayland mientras sostiene el gamma
+	return []string{"-m", "wayland", "-P", "-O", fmt.Sprintf("%.0f", temperature)}
+}
+
+/**
+ * startLocked - Arranca la herramienta y lanza su supervisor
+ *
+ * @param {string} tool - Herramienta a lanzar
+ * @param {float64} temperature - Temperatura en Kelvin
+ * @returns {error} Error si el proceso no arranca o muere durante la gracia
+ * @private
+ */
+func (m *ManagedBackend) startLocked(tool string, temperature float64) error {
+	args := managedArgs(tool, temperature)
+
+	if m.dryRun {
+		logging.Printf("🧪 [dry-run] %s %s & (supervisado)\n", tool, strings.Join(args, " "))
+		m.generation++
+		m.tool = tool
+		m.temperature = temperature
+		return nil
+	}
+
+	cmd := toolCommand(tool, args...)
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	exited := make(chan error, 1)
+	go func() { exited <- cmd.Wait() }()
+
+	select {
+	case err := <-exited:
+		return fmt.Errorf("%s terminó al arrancar: %v", tool, err)
+	case <-time.After(managedStartGrace):
+	}
+
+	// Solo un arranque exitoso cambia de generación: un reintento fallido
+	// no debe hacer creer al supervisor que lo han reemplazado
+	m.generation++
+	m.tool = tool
+	m.cmd = cmd
+	m.temperature = temperature
+	logging.WithFields(logging.Fields{"backend": tool, "temp": temperature}).
+		Printf("🌡️  Temperatura aplicada en Wayland (%s supervisado, pid %d): %.0fK\n", tool, cmd.Process.Pid, temperature)
+
+	go m.supervise(m.generation, exited)
+	return nil
+}
+
+/**
+ * supervise - Relanza el proceso si termina sin que lo hayamos pedido
+ *
+ * @param {uint64} generation - Generación del proceso observado
+ * @param {chan error} exited - Canal que recibe el fin del proceso
+ * @private
+ */
+func (m *ManagedBackend) supervise(generation uint64, exited chan error) {
+	err := <-exited
+
+	backoff := time.Second
+	for attempt := 1; attempt <= managedMaxRestarts; attempt++ {
+		m.mu.Lock()
+		if m.generation != generation {
+			// Detenido o reemplazado intencionadamente
+			m.mu.Unlock()
+			return
+		}
+		tool, temperature := m.tool, m.temperature
+		m.cmd = nil
+		m.mu.Unlock()
+
+		logging.WithFields(logging.Fields{"backend": tool, "temp": temperature, "error": err}).
+			Printf("⚠️  %s terminó inesperadamente (%v), reintentando en %v (%d/%d)\n",
+				tool, err, backoff, attempt, managedMaxRestarts)
+		time.Sleep(backoff)
+		backoff *= 2
+
+		m.mu.Lock()
+		if m.generation != generation {
+			m.mu.Unlock()
+			return
+		}
+		startErr := m.startLocked(tool, temperature)
+		m.mu.Unlock()
+		if startErr == nil {
+			// El nuevo proceso tiene su propio supervisor
+			return
+		}
+		err = startErr
+	}
+
+	m.mu.Lock()
+	if m.generation == generation {
+		m.tool = ""
+		m.cmd = nil
+	}
+	m.mu.Unlock()
+	logging.WithFields(logging.Fields{"error": err}).
+		Printf("❌ Backend supervisado abandonado tras %d reintentos\n", managedMaxRestarts)
+}
+
+/**
+ * stopLocked - Termina el proceso actual (SIGTERM y luego SIGKILL)
+ *
+ * @private
+ */
+func (m *ManagedBackend) stopLocked() {
+	m.generation++
+	tool, cmd := m.tool, m.cmd
+	m.tool = ""
+	m.cmd = nil
+
+	if m.dryRun && tool != "" {
+		logging.Printf("🧪 [dry-run] detener %s supervisado\n", tool)
+		return
+	}
+	if cmd == nil || cmd.Process == nil {
+		return
+	}
+
+	cmd.Process.Signal(syscall.SIGTERM)
+	done := make(chan struct{})
+	go func() {
+		// Wait ya lo llama el supervisor; sondeamos si el proceso sigue vivo
+		for cmd.Process.Signal(syscall.Signal(0)) == nil {
+			time.Sleep(50 * time.Millisecond)
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(managedStopTimeout):
+		cmd.Process.Kill()
+	}
+}
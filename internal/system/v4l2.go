@@ -0,0 +1,305 @@
+package system
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+// Constantes de videodev2.h que usa la muestra de la webcam
+const (
+	v4l2BufTypeVideoCapture = 1
+	v4l2MemoryMMap          = 1
+	v4l2CIDAutogain         = 0x00980912 // V4L2_CID_AUTOGAIN
+	v4l2CIDGain             = 0x00980913 // V4L2_CID_GAIN
+	v4l2CIDExposureAuto     = 0x009a0901 // V4L2_CID_EXPOSURE_AUTO
+	v4l2CIDExposureAbsolute = 0x009a0902 // V4L2_CID_EXPOSURE_ABSOLUTE (exposure_time_absolute)
+	v4l2ExposureManual      = 1
+	v4l2CtrlFlagDisabled    = 0x1
+	v4l2Buffers             = 2
+)
+
+// Formatos de píxel con la luminancia legible sin decodificar
+var (
+	v4l2PixFmtYUYV = fourcc('Y', 'U', 'Y', 'V')
+	v4l2PixFmtGrey = fourcc('G', 'R', 'E', 'Y')
+)
+
+// fourcc compone el código de cuatro letras de un formato de píxel
+func fourcc(a, b, c, d byte) uint32 {
+	return uint32(a) | uint32(b)<<8 | uint32(c)<<16 | uint32(d)<<24
+}
+
+// Estructuras de videodev2.h; el tamaño de cada una forma parte del número de ioctl
+
+type v4l2Control struct {
+	id    uint32
+	value int32
+}
+
+type v4l2QueryCtrl struct {
+	id           uint32
+	typ          uint32
+	name         [32]byte
+	minimum      int32
+	maximum      int32
+	step         int32
+	defaultValue int32
+	flags        uint32
+	reserved     [2]uint32
+}
+
+type v4l2PixFormat struct {
+	width        uint32
+	height       uint32
+	pixelformat  uint32
+	field        uint32
+	bytesperline uint32
+	sizeimage    uint32
+	colorspace   uint32
+	priv         uint32
+	flags        uint32
+	ycbcrEnc     uint32
+	quantization uint32
+	xferFunc     uint32
+}
+
+type v4l2Format struct {
+	typ uint32
+	fmt [25]uint64 // Unión de 200 bytes alineada como punteros; v4l2_pix_format va al principio
+}
+
+type v4l2RequestBuffers struct {
+	count        uint32
+	typ          uint32
+	memory       uint32
+	capabilities uint32
+	flags        uint8
+	reserved     [3]uint8
+}
+
+type v4l2Buffer struct {
+	index     uint32
+	typ       uint32
+	bytesused uint32
+	flags     uint32
+	field     uint32
+	timestamp syscall.Timeval
+	timecode  [16]byte
+	sequence  uint32
+	memory    uint32
+	m         uintptr // Unión: offset del búfer para V4L2_MEMORY_MMAP
+	length    uint32
+	reserved2 uint32
+	requestFD int32
+}
+
+// v4l2IOWR calcula el número de un ioctl _IOWR('V', nr, size)
+func v4l2IOWR(nr, size uintptr) uintptr {
+	return 3<<30 | size<<16 | 'V'<<8 | nr
+}
+
+var (
+	vidiocSFmt      = v4l2IOWR(5, unsafe.Sizeof(v4l2Format{}))
+	vidiocReqBufs   = v4l2IOWR(8, unsafe.Sizeof(v4l2RequestBuffers{}))
+	vidiocQueryBuf  = v4l2IOWR(9, unsafe.Sizeof(v4l2Buffer{}))
+	vidiocQBuf      = v4l2IOWR(15, unsafe.Sizeof(v4l2Buffer{}))
+	vidiocDQBuf     = v4l2IOWR(17, unsafe.Sizeof(v4l2Buffer{}))
+	vidiocStreamOn  = 1<<30 | unsafe.Sizeof(int32(0))<<16 | 'V'<<8 | 18 // _IOW
+	vidiocStreamOff = 1<<30 | unsafe.Sizeof(int32(0))<<16 | 'V'<<8 | 19 // _IOW
+	vidiocGCtrl     = v4l2IOWR(27, unsafe.Sizeof(v4l2Control{}))
+	vidiocSCtrl     = v4l2IOWR(28, unsafe.Sizeof(v4l2Control{}))
+	vidiocQueryCtrl = v4l2IOWR(36, unsafe.Sizeof(v4l2QueryCtrl{}))
+)
+
+// v4l2Ioctl lanza un ioctl sobre el dispositivo, repitiendo si lo interrumpe una señal
+func v4l2Ioctl(fd int, request uintptr, arg unsafe.Pointer) error {
+	for {
+		_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(fd), request, uintptr(arg))
+		if errno == syscall.EINTR {
+			continue
+		}
+		if errno != 0 {
+			return errno
+		}
+		return nil
+	}
+}
+
+/**
+ * readWebcamLuma - Abre la webcam, fija la exposición y lee la luminancia
+ *
+ * @param {context.Context} ctx - Contexto con el tiempo máximo de la muestra
+ * @param {string} device - Dispositivo V4L2
+ * @param {int} frames - Fotogramas a leer; se usa el último
+ * @returns {float64, error} Luminancia media entre 0 y 1
+ * @private
+ */
+func readWebcamLuma(ctx context.Context, device string, frames int) (float64, error) {
+	fd, err := syscall.Open(device, syscall.O_RDWR|syscall.O_NONBLOCK|syscall.O_CLOEXEC, 0)
+	if err != nil {
+		return 0, fmt.Errorf("no se pudo abrir %s: %w", device, err)
+	}
+	defer syscall.Close(fd)
+
+	restore := lockWebcamExposure(fd)
+	defer restore()
+	return captureWebcamLuma(ctx, fd, frames)
+}
+
+/**
+ * lockWebcamExposure - Fija la exposición y la ganancia de la webcam
+ *
+ * Con la exposición automática la webcam compensa la oscuridad y todas
+ * las habitaciones acaban pareciendo igual de iluminadas. Se pasa a
+ * exposición manual con el valor por defecto del controlador
+ * (exposure_time_absolute) y la ganancia por defecto, de modo que el
+ * brillo de los fotogramas sigue a la luz de la habitación. Los
+ * controles que la webcam no tiene se dejan como están.
+ *
+ * @param {int} fd - Descriptor del dispositivo V4L2
+ * @returns {func()} Restaura los valores anteriores
+ * @private
+ */
+func lockWebcamExposure(fd int) func() {
+	var restore []v4l2Control
+	set := func(id uint32, value int32) {
+		previous := v4l2Control{id: id}
+		if v4l2Ioctl(fd, vidiocGCtrl, unsafe.Pointer(&previous)) != nil {
+			return
+		}
+		control := v4l2Control{id: id, value: value}
+		if v4l2Ioctl(fd, vidiocSCtrl, unsafe.Pointer(&control)) == nil {
+			restore = append(restore, previous)
+		}
+	}
+	defaultValue := func(id uint32) (int32, bool) {
+		query := v4l2QueryCtrl{id: id}
+		if v4l2Ioctl(fd, vidiocQueryCtrl, unsafe.Pointer(&query)) != nil || query.flags&v4l2CtrlFlagDisabled != 0 {
+			return 0, false
+		}
+		return query.defaultValue, true
+	}
+
+	// El orden importa: el valor manual solo se acepta con la exposición automática apagada
+	set(v4l2CIDExposureAuto, v4l2ExposureManual)
+	if exposure, ok := defaultValue(v4l2CIDExposureAbsolute); ok {
+		set(v4l2CIDExposureAbsolute, exposure)
+	}
+	set(v4l2CIDAutogain, 0)
+	if gain, ok := defaultValue(v4l2CIDGain); ok {
+		set(v4l2CIDGain, gain)
+	}
+
+	return func() {
+		// Al revés, para volver a la exposición automática en último lugar
+		for i := len(restore) - 1; i >= 0; i-- {
+			v4l2Ioctl(fd, vidiocSCtrl, unsafe.Pointer(&restore[i]))
+		}
+	}
+}
+
+/**
+ * captureWebcamLuma - Lee fotogramas de la webcam y devuelve la luminancia media del último
+ *
+ * Usa la captura por búferes mapeados de V4L2 en el propio proceso, en
+ * YUYV o escala de grises (la luminancia se lee sin decodificar). Los
+ * fotogramas no salen de la memoria del proceso.
+ *
+ * @param {context.Context} ctx - Contexto con el tiempo máximo de la muestra
+ * @param {int} fd - Descriptor del dispositivo V4L2, abierto sin bloqueo
+ * @param {int} frames - Fotogramas a leer; se usa el último
+ * @returns {float64, error} Luminancia media entre 0 y 1
+ * @private
+ */
+func captureWebcamLuma(ctx context.Context, fd int, frames int) (float64, error) {
+	format := v4l2Format{typ: v4l2BufTypeVideoCapture}
+	pix := (*v4l2PixFormat)(unsafe.Pointer(&format.fmt[0]))
+	pix.width, pix.height, pix.pixelformat = ambientFrameWidth, ambientFrameHeight, v4l2PixFmtYUYV
+	if err := v4l2Ioctl(fd, vidiocSFmt, unsafe.Pointer(&format)); err != nil {
+		return 0, fmt.Errorf("no se pudo elegir el formato de captura: %w", err)
+	}
+	// El controlador ajusta el tamaño y puede imponer otro formato
+	step := 1
+	switch pix.pixelformat {
+	case v4l2PixFmtYUYV:
+		step = 2 // Y0 U Y1 V: la luminancia va en los bytes pares
+	case v4l2PixFmtGrey:
+	default:
+		return 0, fmt.Errorf("la webcam no ofrece YUYV ni escala de grises")
+	}
+
+	request := v4l2RequestBuffers{count: v4l2Buffers, typ: v4l2BufTypeVideoCapture, memory: v4l2MemoryMMap}
+	if err := v4l2Ioctl(fd, vidiocReqBufs, unsafe.Pointer(&request)); err != nil || request.count == 0 {
+		return 0, fmt.Errorf("la webcam no admite captura por búferes: %v", err)
+	}
+	buffers := make([][]byte, 0, request.count)
+	defer func() {
+		for _, buffer := range buffers {
+			syscall.Munmap(buffer)
+		}
+		// Liberar los búferes del controlador
+		release := v4l2RequestBuffers{typ: v4l2BufTypeVideoCapture, memory: v4l2MemoryMMap}
+		v4l2Ioctl(fd, vidiocReqBufs, unsafe.Pointer(&release))
+	}()
+	for i := uint32(0); i < request.count; i++ {
+		buffer := v4l2Buffer{index: i, typ: v4l2BufTypeVideoCapture, memory: v4l2MemoryMMap}
+		if err := v4l2Ioctl(fd, vidiocQueryBuf, unsafe.Pointer(&buffer)); err != nil {
+			return 0, err
+		}
+		offset := *(*uint32)(unsafe.Pointer(&buffer.m))
+		data, err := syscall.Mmap(fd, int64(offset), int(buffer.length), syscall.PROT_READ, syscall.MAP_SHARED)
+		if err != nil {
+			return 0, err
+		}
+		buffers = append(buffers, data)
+		if err := v4l2Ioctl(fd, vidiocQBuf, unsafe.Pointer(&buffer)); err != nil {
+			return 0, err
+		}
+	}
+
+	streamType := int32(v4l2BufTypeVideoCapture)
+	if err := v4l2Ioctl(fd, vidiocStreamOn, unsafe.Pointer(&streamType)); err != nil {
+		return 0, fmt.Errorf("no se pudo iniciar la captura: %w", err)
+	}
+	defer v4l2Ioctl(fd, vidiocStreamOff, unsafe.Pointer(&streamType))
+
+	var level float64
+	for captured := 0; captured < frames; {
+		buffer := v4l2Buffer{typ: v4l2BufTypeVideoCapture, memory: v4l2MemoryMMap}
+		err := v4l2Ioctl(fd, vidiocDQBuf, unsafe.Pointer(&buffer))
+		if errors.Is(err, syscall.EAGAIN) {
+			select {
+			case <-ctx.Done():
+				return 0, fmt.Errorf("la webcam no devolvió ningún fotograma: %w", ctx.Err())
+			case <-time.After(20 * time.Millisecond):
+			}
+			continue
+		}
+		if err != nil {
+			return 0, err
+		}
+
+		data := buffers[buffer.index]
+		if used := int(buffer.bytesused); used > 0 && used < len(data) {
+			data = data[:used]
+		}
+		total, count := 0, 0
+		for i := 0; i < len(data); i += step {
+			total += int(data[i])
+			count++
+		}
+		if count > 0 {
+			level = float64(total) / float64(count*255)
+		}
+		captured++
+
+		if err := v4l2Ioctl(fd, vidiocQBuf, unsafe.Pointer(&buffer)); err != nil {
+			return 0, err
+		}
+	}
+	return level, nil
+}
@@ -0,0 +1,129 @@
+package system
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"luznocturna/luz-nocturna/internal/logging"
+	"os"
+)
+
+/**
+ * WorkspaceWatcher - Sigue el espacio de trabajo enfocado en Sway o i3
+ *
+ * Usa la IPC común de ambos ("swaymsg" o "i3-msg"): consulta el espacio
+ * de trabajo actual al empezar y después escucha los eventos
+ * "workspace" con change "focus".
+ *
+ * @struct {WorkspaceWatcher}
+ * @property {string} Method - Compositor o gestor de ventanas en uso ("Sway", "i3")
+ * @property {context.CancelFunc} cancel - Detiene el seguimiento
+ */
+type WorkspaceWatcher struct {
+	Method string
+	cancel context.CancelFunc
+}
+
+/**
+ * StartWorkspaceWatcher - Empieza a seguir el espacio de trabajo enfocado
+ *
+ * onChange recibe el nombre completo del espacio de trabajo ("5:media")
+ * y se llama desde la goroutine del seguimiento, primero con el actual.
+ *
+ * @param {func(string)} onChange - Callback con el nombre del nuevo espacio de trabajo
+ * @returns {*WorkspaceWatcher, error} Seguimiento activo; error si la sesión no es Sway ni i3
+ * @example
+ *   watcher, err := StartWorkspaceWatcher(func(name string) { fmt.Println(name) })
+ *   defer watcher.Stop()
+ */
+func StartWorkspaceWatcher(onChange func(name string)) (*WorkspaceWatcher, error) {
+	watcher := &WorkspaceWatcher{}
+	var client string
+	switch {
+	case os.Getenv("SWAYSOCK") != "":
+		watcher.Method, client = "Sway", "swaymsg"
+	case os.Getenv("I3SOCK") != "":
+		watcher.Method, client = "i3", "i3-msg"
+	default:
+		return nil, fmt.Errorf("las reglas por espacio de trabajo solo funcionan en Sway o i3 (sesión actual: %s)", DetectCompositor())
+	}
+	if err := lookupTool(client); err != nil {
+		return nil, fmt.Errorf("no se encontró %s: %w", client, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	watcher.cancel = cancel
+	go func() {
+		if name, err := focusedWorkspace(ctx, client); err == nil {
+			onChange(name)
+		}
+		if err := watchWorkspaces(ctx, client, onChange); err != nil && ctx.Err() == nil {
+			logging.Printf("⚠️  Seguimiento del espacio de trabajo (%s) detenido: %v\n", watcher.Method, err)
+		}
+	}()
+	logging.Printf("🗂️  Siguiendo el espacio de trabajo (%s)\n", watcher.Method)
+	return watcher, nil
+}
+
+// Stop detiene el seguimiento
+func (w *WorkspaceWatcher) Stop() {
+	w.cancel()
+}
+
+// i3Workspace es la parte que interesa de un espacio de trabajo de Sway/i3
+type i3Workspace struct {
+	Name    string `json:"name"`
+	Focused bool   `json:"focused"`
+}
+
+// i3WorkspaceEvent es la parte que interesa de un evento "workspace" de Sway/i3
+type i3WorkspaceEvent struct {
+	Change  string      `json:"change"`
+	Current i3Workspace `json:"current"`
+}
+
+// focusedWorkspace devuelve el nombre del espacio de trabajo enfocado
+func focusedWorkspace(ctx context.Context, client string) (string, error) {
+	output, err := toolCommandContext(ctx, client, "-t", "get_workspaces").Output()
+	if err != nil {
+		return "", err
+	}
+	var workspaces []i3Workspace
+	if err := json.Unmarshal(output, &workspaces); err != nil {
+		return "", err
+	}
+	for _, workspace := range workspaces {
+		if workspace.Focused {
+			return workspace.Name, nil
+		}
+	}
+	return "", fmt.Errorf("ningún espacio de trabajo enfocado")
+}
+
+// watchWorkspaces escucha los eventos de espacio de trabajo de Sway/i3
+func watchWorkspaces(ctx context.Context, client string, onChange func(string)) error {
+	cmd := toolCommandContext(ctx, client, "-t", "subscribe", "-m", `["workspace"]`)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	defer cmd.Wait()
+
+	decoder := json.NewDecoder(stdout)
+	for {
+		var event i3WorkspaceEvent
+		if err := decoder.Decode(&event); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		if event.Change == "focus" && event.Current.Name != "" {
+			onChange(event.Current.Name)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package version
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Información de compilación, inyectada con -ldflags:
+//
+//	go build -ldflags "-X luznocturna/luz-nocturna/internal/version.Version=1.0.1 \
+//	  -X luznocturna/luz-nocturna/internal/version.Commit=$(git rev-parse --short HEAD)"
+var (
+	Version   = "dev"
+	Commit    = "desconocido"
+	BuildDate = "desconocida"
+)
+
+// ReleasesURL es la API de GitHub con la última versión publicada
+const ReleasesURL = "https://api.github.com/repos/Escobarq/luz-nocturna/releases/latest"
+
+// Release describe la última versión publicada en GitHub
+type Release struct {
+	Tag string `json:"tag_name"`
+	URL string `json:"html_url"`
+}
+
+// String devuelve la versión completa para mostrar al usuario
+func String() string {
+	return fmt.Sprintf("%s (%s, %s)", Version, Commit, BuildDate)
+}
+
+/**
+ * CheckForUpdate - Consulta la última versión publicada en GitHub
+ *
+ * Solo se ejecuta cuando el usuario lo pide; la aplicación nunca
+ * contacta a la red por su cuenta.
+ *
+ * @param {context.Context} ctx - Contexto para cancelar la petición
+ * @returns {Release, bool, error} Última versión, si es más nueva que la actual, error de red
+ * @example
+ *   release, newer, err := version.CheckForUpdate(ctx)
+ *   if err == nil && newer {
+ *       fmt.Println("Nueva versión:", release.Tag)
+ *   }
+ */
+func CheckForUpdate(ctx context.Context) (Release, bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, ReleasesURL, nil)
+	if err != nil {
+		return Release{}, false, err
+	}
+	request.Header.Set("Accept", "application/vnd.github+json")
+
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return Release{}, false, fmt.Errorf("no se pudo consultar GitHub: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return Release{}, false, fmt.Errorf("respuesta inesperada de GitHub: %s", response.Status)
+	}
+
+	var release Release
+	if err := json.NewDecoder(response.Body).Decode(&release); err != nil {
+		return Release{}, false, fmt.Errorf("respuesta de GitHub inválida: %w", err)
+	}
+
+	return release, IsNewer(release.Tag, Version), nil
+}
+
+// IsNewer indica si la versión candidate es mayor que current ("v1.2.3" o "1.2.3").
+// Las compilaciones "dev" nunca se consideran desactualizadas.
+func IsNewer(candidate, current string) bool {
+	candidateParts, ok := parseVersion(candidate)
+	if !ok {
+		return false
+	}
+	currentParts, ok := parseVersion(current)
+	if !ok {
+		return false
+	}
+
+	for i := range candidateParts {
+		if candidateParts[i] != currentParts[i] {
+			return candidateParts[i] > currentParts[i]
+		}
+	}
+	return false
+}
+
+// parseVersion convierte "v1.2.3" en [1 2 3]; ignora sufijos como "-rc1"
+func parseVersion(text string) ([3]int, bool) {
+	var parts [3]int
+	text = strings.TrimPrefix(strings.TrimSpace(text), "v")
+	if i := strings.IndexAny(text, "-+"); i >= 0 {
+		text = text[:i]
+	}
+
+	fields := strings.Split(text, ".")
+	if len(fields) == 0 || len(fields) > 3 {
+		return parts, false
+	}
+	for i, field := range fields {
+		number, err := strconv.Atoi(field)
+		if err != nil {
+			return parts, false
+		}
+		parts[i] = number
+	}
+	return parts, true
+}
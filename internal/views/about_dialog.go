@@ -0,0 +1,112 @@
+package views
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"luznocturna/luz-nocturna/internal/diagnostics"
+	"luznocturna/luz-nocturna/internal/version"
+)
+
+/**
+ * showAboutDialog - Muestra la información de versión y del sistema
+ *
+ * Incluye versión, commit, protocolo y backend de gamma detectados, un
+ * botón opcional para buscar actualizaciones en GitHub y otro para
+ * exportar el paquete de diagnóstico de los informes de error.
+ *
+ * @private
+ */
+func (v *NightLightView) showAboutDialog() {
+	backend := v.controller.GetBackend()
+	if backend == "" {
+		backend = "aún no se aplicó ninguna temperatura"
+	}
+
+	info := widget.NewForm(
+		widget.NewFormItem("Versión", widget.NewLabel(version.Version)),
+		widget.NewFormItem("Commit", widget.NewLabel(version.Commit)),
+		widget.NewFormItem("Compilado", widget.NewLabel(version.BuildDate)),
+		widget.NewFormItem("Protocolo", widget.NewLabel(v.controller.GetProtocol())),
+		widget.NewFormItem("Backend", widget.NewLabel(backend)),
+	)
+
+	updateStatus := widget.NewLabel("")
+	updateStatus.Wrapping = fyne.TextWrapWord
+	updateLink := widget.NewHyperlink("", nil)
+	updateLink.Hide()
+
+	var checkButton *widget.Button
+	checkButton = widget.NewButton("🔍 Buscar actualizaciones", func() {
+		checkButton.Disable()
+		updateStatus.SetText("Consultando GitHub...")
+
+		go func() {
+			release, newer, err := version.CheckForUpdate(context.Background())
+			runOnUI(func() {
+				checkButton.Enable()
+				switch {
+				case err != nil:
+					updateStatus.SetText("⚠️ " + err.Error())
+				case newer:
+					updateStatus.SetText(fmt.Sprintf("🎉 Nueva versión disponible: %s", release.Tag))
+					if link, err := url.Parse(release.URL); err == nil {
+						updateLink.SetText("Ver versión en GitHub")
+						updateLink.SetURL(link)
+						updateLink.Show()
+					}
+				default:
+					updateStatus.SetText("✅ Tienes la última versión")
+				}
+			})
+		}()
+	})
+
+	content := container.NewVBox(
+		widget.NewLabelWithStyle("🌙 Luz Nocturna", fyne.TextAlignCenter, fyne.TextStyle{Bold: true}),
+		widget.NewLabelWithStyle("Control de temperatura de color para monitores Linux",
+			fyne.TextAlignCenter, fyne.TextStyle{Italic: true}),
+		info,
+		checkButton,
+		updateStatus,
+		updateLink,
+		widget.NewButton("📋 Exportar diagnóstico", v.exportDiagnostics),
+	)
+
+	dialog.ShowCustom("ℹ️ Acerca de", "Cerrar", content, v.window)
+}
+
+/**
+ * exportDiagnostics - Guarda el paquete de diagnóstico en un archivo elegido por el usuario
+ *
+ * @private
+ */
+func (v *NightLightView) exportDiagnostics() {
+	save := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil {
+			v.showErrorDialog("❌ Error al exportar", err.Error())
+			return
+		}
+		if writer == nil {
+			return // Cancelado
+		}
+		defer writer.Close()
+
+		if err := v.controller.ExportDiagnostics(writer); err != nil {
+			v.showErrorDialog("❌ Error al exportar", err.Error())
+			return
+		}
+		// Diálogo en lugar de aviso breve: el recordatorio hay que leerlo
+		dialog.ShowInformation("📋 Diagnóstico exportado", writer.URI().Path()+
+			"\n\nRevisa su contenido antes de adjuntarlo a un informe de error.", v.window)
+	}, v.window)
+	save.SetFileName(diagnostics.DefaultFileName(time.Now()))
+	save.Show()
+}
@@ -0,0 +1,142 @@
+package views
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"luznocturna/luz-nocturna/internal/models"
+)
+
+// calibrationStep es lo que mueve cada pulsación de "−" o "+" en el asistente de calibración
+const calibrationStep = 0.01
+
+// calibrationPatches son los parches de referencia: blanco y grises neutros
+// (mismo valor en los tres canales), donde cualquier tinte salta a la vista
+var calibrationPatches = []color.Color{
+	color.NRGBA{0xff, 0xff, 0xff, 0xff},
+	color.NRGBA{0xd0, 0xd0, 0xd0, 0xff},
+	color.NRGBA{0x98, 0x98, 0x98, 0xff},
+	color.NRGBA{0x60, 0x60, 0x60, 0xff},
+}
+
+/**
+ * showCalibrationDialog - Asistente para que el blanco se vea neutro a 6500K
+ *
+ * Aplica la luz diurna (models.DaylightTemp) a todas las pantallas y
+ * muestra parches blancos y grises de referencia. El usuario empuja cada
+ * canal con "−" y "+" hasta que el blanco del display elegido deja de
+ * verse azulado, verdoso o magenta (una hoja de papel a la luz del día es
+ * una buena comparación). "Guardar" lo deja como ajuste fino del display;
+ * "Cancelar" lo descarta. Cambiar de display descarta lo que no se
+ * guardó del anterior.
+ *
+ * @private
+ */
+func (v *NightLightView) showCalibrationDialog() {
+	displays := v.controller.GetDisplays()
+	if v.controller.GetProtocol() == "wayland" || len(displays) == 0 {
+		v.showErrorDialog("🎯 Calibración no disponible",
+			"El ajuste fino de color necesita X11 con un display detectado: en Wayland la gamma es la misma para todas las pantallas")
+		return
+	}
+
+	session := v.controller.BeginCalibration()
+	var trim [3]float64
+	valueLabels := [3]*widget.Label{}
+	status := widget.NewLabel("")
+	status.Wrapping = fyne.TextWrapWord
+	displaySel := widget.NewSelect(displays, nil)
+
+	// preview aplica el ajuste de prueba fuera del hilo de la interfaz
+	preview := func() {
+		for i, label := range valueLabels {
+			label.SetText(fmt.Sprintf("%3.0f%%", trim[i]*100))
+		}
+		display := displaySel.Selected
+		candidate := models.ChannelTrim{Red: trim[0], Green: trim[1], Blue: trim[2]}
+		go func() {
+			err := v.controller.PreviewCalibration(session, display, candidate)
+			runOnUI(func() {
+				if err != nil {
+					status.SetText("⚠️ " + err.Error())
+				} else {
+					status.SetText("")
+				}
+			})
+		}()
+	}
+	displaySel.OnChanged = func(display string) {
+		trim = v.controller.GetChannelTrim(display).Factors()
+		preview()
+	}
+
+	channels := container.NewVBox()
+	for i := range trim {
+		channel := i
+		valueLabels[channel] = widget.NewLabel("")
+		nudge := func(delta float64) func() {
+			return func() {
+				value := math.Round((trim[channel]+delta)*100) / 100
+				trim[channel] = math.Max(models.MinChannelTrim, math.Min(value, models.MaxChannelTrim))
+				preview()
+			}
+		}
+		buttons := container.NewHBox(widget.NewButton("−", nudge(-calibrationStep)), valueLabels[channel], widget.NewButton("+", nudge(calibrationStep)))
+		channels.Add(container.NewBorder(nil, nil, widget.NewLabel(channelNames[channel]), buttons))
+	}
+	reset := widget.NewButton("↩️ Sin ajuste", func() {
+		trim = [3]float64{1.0, 1.0, 1.0}
+		preview()
+	})
+
+	patches := container.NewGridWithColumns(len(calibrationPatches))
+	for _, fill := range calibrationPatches {
+		patch := canvas.NewRectangle(fill)
+		patch.SetMinSize(fyne.NewSize(80, 80))
+		patches.Add(patch)
+	}
+
+	hint := widget.NewLabel(fmt.Sprintf("A %.0fK los parches deberían verse blancos y grises neutros. "+
+		"Si tiran a azul, verde o magenta, baja ese canal (o sube los otros) hasta que dejen de hacerlo.", float64(models.DaylightTemp)))
+	hint.Wrapping = fyne.TextWrapWord
+
+	content := container.NewVBox(
+		hint,
+		patches,
+		container.NewBorder(nil, nil, widget.NewLabel("Pantalla:"), reset, displaySel),
+		channels,
+		status,
+	)
+
+	calibration := dialog.NewCustomConfirm("🎯 Calibrar el blanco", "💾 Guardar", "Cancelar", content, func(save bool) {
+		display := displaySel.Selected
+		go func() {
+			err := v.controller.FinishCalibration(save)
+			runOnUI(func() {
+				if err != nil {
+					v.showErrorDialog("❌ Error de calibración", err.Error())
+					return
+				}
+				v.loadChannelTrim()
+				if save {
+					v.showToast("🎯 Calibración guardada: " + display)
+				}
+			})
+		}()
+	}, v.window)
+	calibration.Resize(fyne.NewSize(480, 0))
+	calibration.Show()
+
+	if selected := v.trimDisplaySel.Selected; selected != "" {
+		displaySel.SetSelected(selected)
+	} else {
+		displaySel.SetSelectedIndex(0)
+	}
+}
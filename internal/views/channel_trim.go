@@ -0,0 +1,115 @@
+package views
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"luznocturna/luz-nocturna/internal/models"
+)
+
+// channelNames son las etiquetas de los sliders de ajuste fino, en orden R, G, B
+var channelNames = [3]string{"🔴 Rojo", "🟢 Verde", "🔵 Azul"}
+
+/**
+ * createChannelTrimSection - Crea los sliders de ajuste fino RGB por display
+ *
+ * Multiplicadores pequeños (80%-120%) sobre la conversión de la
+ * temperatura para compensar paneles con un tinte verde o magenta. Cada
+ * display guarda el suyo; el cambio se guarda y se aplica al soltar el
+ * slider. Solo tiene efecto en X11, donde la gamma se aplica por display.
+ * "Calibrar..." abre el asistente que lo ajusta comparando con blancos
+ * de referencia.
+ *
+ * @returns {fyne.CanvasObject} Selector de display, sliders y botones de restablecer y calibrar
+ * @private
+ */
+func (v *NightLightView) createChannelTrimSection() fyne.CanvasObject {
+	displays := v.controller.GetDisplays()
+	v.trimDisplaySel = widget.NewSelect(displays, func(string) { v.loadChannelTrim() })
+
+	rows := container.NewVBox()
+	for i := range v.trimSliders {
+		slider := widget.NewSlider(models.MinChannelTrim, models.MaxChannelTrim)
+		slider.Step = 0.01
+		slider.OnChanged = func(float64) { v.updateChannelTrimLabels() }
+		slider.OnChangeEnded = func(float64) { v.saveChannelTrim() }
+		v.trimSliders[i] = slider
+		v.trimLabels[i] = widget.NewLabel("")
+		rows.Add(container.NewBorder(nil, nil, widget.NewLabel(channelNames[i]), v.trimLabels[i], slider))
+	}
+	reset := widget.NewButton("↩️ Restablecer", func() {
+		for _, slider := range v.trimSliders {
+			slider.SetValue(1.0)
+		}
+		v.saveChannelTrim()
+	})
+
+	if len(displays) > 0 {
+		v.trimDisplaySel.SetSelectedIndex(0)
+	} else {
+		v.loadChannelTrim()
+	}
+	calibrate := widget.NewButton("🎯 Calibrar...", v.showCalibrationDialog)
+	return container.NewVBox(
+		container.NewBorder(nil, nil, widget.NewLabel("🎚️ Ajuste fino de color:"), container.NewHBox(reset, calibrate), v.trimDisplaySel),
+		rows,
+	)
+}
+
+// loadChannelTrim vuelca en los sliders el ajuste fino del display elegido
+func (v *NightLightView) loadChannelTrim() {
+	factors := v.controller.GetChannelTrim(v.trimDisplaySel.Selected).Factors()
+	for i, slider := range v.trimSliders {
+		slider.Value = factors[i]
+		slider.Refresh()
+		if v.trimDisplaySel.Selected == "" {
+			slider.Disable()
+		} else {
+			slider.Enable()
+		}
+	}
+	v.updateChannelTrimLabels()
+}
+
+// updateChannelTrimLabels muestra el valor de cada slider de ajuste fino en porcentaje
+func (v *NightLightView) updateChannelTrimLabels() {
+	for i, slider := range v.trimSliders {
+		v.trimLabels[i].SetText(fmt.Sprintf("%3.0f%%", slider.Value*100))
+	}
+}
+
+/**
+ * saveChannelTrim - Guarda y aplica el ajuste fino de los sliders
+ *
+ * Si ya hay una aplicación en curso se descarta el cambio y los sliders
+ * vuelven al valor guardado.
+ *
+ * @private
+ */
+func (v *NightLightView) saveChannelTrim() {
+	display := v.trimDisplaySel.Selected
+	if display == "" {
+		return
+	}
+	if v.busy {
+		v.loadChannelTrim()
+		return
+	}
+
+	trim := models.ChannelTrim{
+		Red:   v.trimSliders[0].Value,
+		Green: v.trimSliders[1].Value,
+		Blue:  v.trimSliders[2].Value,
+	}
+	v.runBusy(func() error {
+		return v.controller.SetChannelTrim(display, trim)
+	}, func(err error) {
+		if err != nil {
+			v.showApplyError("❌ Error de ajuste fino", err, nil)
+			v.loadChannelTrim()
+		}
+	})
+}
@@ -0,0 +1,256 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"luznocturna/luz-nocturna/internal/models"
+	"luznocturna/luz-nocturna/internal/system"
+)
+
+/**
+ * createDisplaysTab - Crea la pestaña de pantallas
+ *
+ * Lista cada display con su modelo (EDID), resolución y lo que el backend
+ * puede hacer con él (rampa de gamma, DDC/CI o nada), con una casilla para
+ * incluirlo o excluirlo del filtro. "Detectar de nuevo" vuelve a buscar
+ * displays y capacidades sin reiniciar la aplicación. Debajo, la
+ * disposición de monitores actual y el preset que se elige con ella.
+ *
+ * @returns {fyne.CanvasObject} Contenido de la pestaña
+ * @private
+ */
+func (v *NightLightView) createDisplaysTab() fyne.CanvasObject {
+	protocol := widget.NewLabel("🖥️ Protocolo: " + v.controller.GetProtocol())
+	protocol.TextStyle = fyne.TextStyle{Monospace: true}
+
+	v.displayList = container.NewVBox()
+	v.displayRefresh = widget.NewButton("🔍 Detectar de nuevo", func() {
+		v.refreshDisplayInfo(true)
+	})
+	v.layoutInfo = widget.NewLabel("")
+	v.layoutInfo.Wrapping = fyne.TextWrapWord
+	v.layoutRemove = widget.NewButton("🗑️ Quitar perfil", v.removeLayoutProfile)
+	saveLayout := widget.NewButton("💾 Guardar perfil para esta disposición", v.showLayoutProfileDialog)
+	v.refreshDisplayInfo(false)
+
+	return container.NewVBox(
+		protocol,
+		container.NewBorder(nil, nil, widget.NewLabel("Aplicar el filtro a:"), nil, v.displayScopeSel),
+		widget.NewSeparator(),
+		v.displayList,
+		widget.NewSeparator(),
+		v.hdrInfo,
+		v.displayRefresh,
+		widget.NewSeparator(),
+		v.layoutInfo,
+		container.NewGridWithColumns(2, saveLayout, v.layoutRemove),
+	)
+}
+
+/**
+ * refreshDisplayInfo - Vuelve a leer los detalles de los displays fuera del hilo de la interfaz
+ *
+ * La detección de DDC/CI puede tardar varios segundos, así que el botón
+ * queda desactivado mientras tanto.
+ *
+ * @param {bool} redetect - Volver a detectar los displays antes de leer sus detalles
+ * @private
+ */
+func (v *NightLightView) refreshDisplayInfo(redetect bool) {
+	if v.displayRefresh == nil {
+		return
+	}
+	v.displayRefresh.Disable()
+	v.displayRefresh.SetText("🔍 Detectando...")
+
+	go func() {
+		if redetect {
+			v.controller.RefreshDisplays()
+		}
+		infos := v.controller.GetDisplayInfo()
+		layout := v.controller.GetMonitorLayout()
+		runOnUI(func() {
+			v.displayInfos = infos
+			v.monitorLayout = layout
+			v.updateLayoutInfo()
+			v.displayRefresh.SetText("🔍 Detectar de nuevo")
+			v.displayRefresh.Enable()
+			v.renderDisplayList()
+			v.updateDisplayStatus()
+		})
+	}()
+}
+
+// renderDisplayList reconstruye las filas de la pestaña de pantallas con los últimos detalles
+func (v *NightLightView) renderDisplayList() {
+	if v.displayList == nil {
+		return
+	}
+
+	v.displayList.RemoveAll()
+	if len(v.displayInfos) == 0 {
+		v.displayList.Add(widget.NewLabel("No se detectó ningún display"))
+	}
+	for _, info := range v.displayInfos {
+		v.displayList.Add(v.createDisplayRow(info))
+	}
+	v.displayList.Refresh()
+}
+
+// createDisplayRow crea la casilla de un display con su línea de detalles
+func (v *NightLightView) createDisplayRow(info system.DisplayInfo) fyne.CanvasObject {
+	title := info.Name
+	if info.Model != "" {
+		title += " — " + info.Model
+	}
+
+	display := info.Name
+	check := widget.NewCheck(title, nil)
+	check.SetChecked(v.controller.IsDisplayIncluded(display))
+	check.OnChanged = func(included bool) {
+		if v.busy {
+			v.renderDisplayList() // Deshace el clic: ya hay una aplicación en curso
+			return
+		}
+		v.runBusy(func() error {
+			return v.controller.SetDisplayIncluded(display, included)
+		}, func(err error) {
+			if err != nil {
+				v.showApplyError("❌ Error al cambiar el display", err, nil)
+			}
+			v.renderDisplayList()
+		})
+	}
+
+	details := formatDisplayDetails(info)
+	if !v.controller.IsDisplayInScope(display) {
+		check.Disable()
+		details += " (fuera del alcance)"
+	}
+
+	detailLabel := widget.NewLabel(details)
+	detailLabel.TextStyle = fyne.TextStyle{Italic: true}
+	return container.NewVBox(check, detailLabel)
+}
+
+// formatDisplayDetails describe la resolución y las capacidades de un display
+// ("2560x1440 · Rampa de gamma, DDC/CI")
+func formatDisplayDetails(info system.DisplayInfo) string {
+	var capabilities []string
+	if info.Gamma {
+		capabilities = append(capabilities, "Rampa de gamma")
+	}
+	if info.DDC {
+		capabilities = append(capabilities, "DDC/CI")
+	}
+	if len(capabilities) == 0 {
+		capabilities = append(capabilities, "Ninguna")
+	}
+
+	resolution := info.Resolution
+	if resolution == "" {
+		resolution = "Resolución desconocida"
+	}
+	return fmt.Sprintf("%s · %s", resolution, strings.Join(capabilities, ", "))
+}
+
+// updateLayoutInfo muestra la disposición de monitores actual y su perfil
+func (v *NightLightView) updateLayoutInfo() {
+	if v.layoutInfo == nil {
+		return
+	}
+
+	text := "🧩 Disposición: " + strings.Join(v.monitorLayout, " + ")
+	if len(v.monitorLayout) == 0 {
+		text = "🧩 Disposición: no se pudieron leer los monitores"
+	}
+	if profile, found := models.FindLayoutProfile(v.controller.GetLayoutProfiles(), v.monitorLayout); found {
+		text += fmt.Sprintf("\nPerfil \"%s\": preset %s", profile.Name, profile.Preset)
+		v.layoutRemove.Enable()
+	} else {
+		text += "\nSin perfil: se mantiene la temperatura elegida"
+		v.layoutRemove.Disable()
+	}
+	v.layoutInfo.SetText(text)
+}
+
+/**
+ * showLayoutProfileDialog - Pide nombre y preset para la disposición actual
+ *
+ * Si la disposición ya tenía un perfil, se reemplaza.
+ *
+ * @private
+ */
+func (v *NightLightView) showLayoutProfileDialog() {
+	presets := v.controller.GetPresets()
+	if len(presets) == 0 {
+		v.showErrorDialog("❌ Sin presets", "Crea primero un preset para asociarlo a esta disposición")
+		return
+	}
+	if len(v.monitorLayout) == 0 {
+		v.showErrorDialog("❌ Sin monitores", "No se pudieron leer los monitores conectados")
+		return
+	}
+
+	var names []string
+	for _, preset := range presets {
+		names = append(names, preset.Name)
+	}
+	nameEntry := widget.NewEntry()
+	nameEntry.SetPlaceHolder("Oficina, Portátil solo...")
+	presetSelect := widget.NewSelect(names, nil)
+	presetSelect.SetSelectedIndex(0)
+	if profile, found := models.FindLayoutProfile(v.controller.GetLayoutProfiles(), v.monitorLayout); found {
+		nameEntry.SetText(profile.Name)
+		presetSelect.SetSelected(profile.Preset)
+	}
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Nombre", nameEntry),
+		widget.NewFormItem("Preset", presetSelect),
+	}
+	dialog.ShowForm("🧩 Perfil de disposición", "Guardar", "Cancelar", items, func(ok bool) {
+		if !ok {
+			return
+		}
+
+		profile := models.LayoutProfile{
+			Name:     strings.TrimSpace(nameEntry.Text),
+			Monitors: v.monitorLayout,
+			Preset:   presetSelect.Selected,
+		}
+		profiles := append(v.otherLayoutProfiles(), profile)
+		if err := v.controller.SetLayoutProfiles(profiles); err != nil {
+			v.showErrorDialog("❌ Error de perfil", err.Error())
+			return
+		}
+		v.updateLayoutInfo()
+		v.showToast("🧩 Perfil guardado: " + profile.Name)
+	}, v.window)
+}
+
+// removeLayoutProfile quita el perfil de la disposición actual
+func (v *NightLightView) removeLayoutProfile() {
+	if err := v.controller.SetLayoutProfiles(v.otherLayoutProfiles()); err != nil {
+		v.showErrorDialog("❌ Error de perfil", err.Error())
+		return
+	}
+	v.updateLayoutInfo()
+}
+
+// otherLayoutProfiles devuelve los perfiles que no corresponden a la disposición actual
+func (v *NightLightView) otherLayoutProfiles() []models.LayoutProfile {
+	var others []models.LayoutProfile
+	for _, profile := range v.controller.GetLayoutProfiles() {
+		if !profile.Matches(v.monitorLayout) {
+			others = append(others, profile)
+		}
+	}
+	return others
+}
@@ -0,0 +1,151 @@
+package views
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// helpTopic identifica un texto de ayuda contextual
+type helpTopic string
+
+// Temas de ayuda contextual
+const (
+	helpTemperature helpTopic = "temperature"
+	helpSchedule    helpTopic = "schedule"
+	helpTransition  helpTopic = "transition"
+	helpSolar       helpTopic = "solar"
+	helpWeather     helpTopic = "weather"
+	helpVacation    helpTopic = "vacation"
+	helpExclusive   helpTopic = "exclusive"
+	helpKeyboard    helpTopic = "keyboard"
+)
+
+// helpEntry es el título y la explicación de un tema de ayuda
+type helpEntry struct {
+	Title string
+	Text  string
+}
+
+// helpTexts explica cada ajuste en lenguaje sencillo
+var helpTexts = map[helpTopic]helpEntry{
+	helpTemperature: {
+		Title: "🌡️ Temperatura de color",
+		Text: "Cuanto más baja, más cálida y anaranjada se ve la pantalla. " +
+			"6500K es el color normal; 4500K es un tono suave para la tarde y " +
+			"3000K o menos reduce mucho la luz azul antes de dormir.",
+	},
+	helpSchedule: {
+		Title: "🕐 Programación automática",
+		Text: "Entre la hora de inicio y la de fin se usa la temperatura nocturna; " +
+			"el resto del día, la diurna. Si la hora de fin es menor que la de inicio " +
+			"(por ejemplo 20:00 a 07:00) la noche cruza la medianoche.",
+	},
+	helpTransition: {
+		Title: "⏱️ Tiempo de transición",
+		Text: "Minutos que tarda el cambio entre la temperatura de día y la de noche, " +
+			"para que no sea brusco. La pantalla empieza a calentarse a la hora de inicio y " +
+			"vuelve al color de día justo a la hora de fin. " +
+			"0 cambia de golpe.",
+	},
+	helpSolar: {
+		Title: "☀️ Modo solar",
+		Text: "En lugar de horas fijas, sigue la puesta y la salida del sol de tu ubicación, " +
+			"así el horario se adapta solo a cada estación. Solo necesita la latitud y la longitud " +
+			"aproximadas de tu ciudad.",
+	},
+	helpWeather: {
+		Title: "🌥️ Días nublados",
+		Text: "Con el cielo cubierto la luz exterior es más cálida y la pantalla parece demasiado azul. " +
+			"Esta opción consulta la nubosidad de tu ubicación cada media hora (Open-Meteo, sin cuenta) " +
+			"y vuelve más cálida la temperatura diurna en proporción. Es la única opción que usa la red " +
+			"de forma periódica.",
+	},
+	helpVacation: {
+		Title: "🏖️ Vacaciones",
+		Text: "Entre las dos fechas (ambas incluidas) la programación no cambia la pantalla y el vigilante " +
+			"no reaplica la gamma: útil de viaje, cuando la hora del equipo no coincide con la tuya. " +
+			"Al día siguiente de la última fecha todo se reanuda solo.",
+	},
+	helpExclusive: {
+		Title: "🔒 Control exclusivo",
+		Text: "Mientras Luz Nocturna aplica un filtro desactiva el modo nocturno del escritorio " +
+			"(GNOME Night Light, KDE Night Color) y detiene redshift o gammastep, porque dos programas " +
+			"cambiando la gamma a la vez producen parpadeos. Se restablece todo al restaurar la gamma.",
+	},
+	helpKeyboard: {
+		Title: "⌨️ Atajos de teclado",
+		Text: "1 a 4 eligen los cuatro primeros presets y cada preset puede tener su propia tecla " +
+			"(en 🎨 Presets → Editar). Espacio activa o desactiva el filtro, Ctrl+Z deshace, " +
+			"Ctrl+Shift+Z rehace, Ctrl+Shift+C alterna el modo de color fiel y F1 abre esta ayuda. " +
+			"Las teclas sin Ctrl no actúan mientras se escribe en un campo de texto. Para un atajo " +
+			"global, asigna \"luz-nocturna color-accurate\" (o toggle) en tu escritorio.",
+	},
+}
+
+// helpOrder es el orden de los temas en la ayuda general
+var helpOrder = []helpTopic{helpTemperature, helpSchedule, helpTransition, helpSolar, helpWeather, helpVacation, helpExclusive, helpKeyboard}
+
+/**
+ * newHelpButton - Crea un botón "?" que explica un ajuste
+ *
+ * Fyne no tiene tooltips, así que al pulsarlo se muestra un popover
+ * junto al botón; se cierra al pulsar fuera.
+ *
+ * @param {helpTopic} topic - Tema a explicar
+ * @returns {*widget.Button} Botón pequeño con el icono de ayuda
+ * @private
+ */
+func (v *NightLightView) newHelpButton(topic helpTopic) *widget.Button {
+	var button *widget.Button
+	button = widget.NewButtonWithIcon("", theme.QuestionIcon(), func() {
+		entry := helpTexts[topic]
+		text := widget.NewLabel(entry.Text)
+		text.Wrapping = fyne.TextWrapWord
+
+		content := container.NewVBox(
+			widget.NewLabelWithStyle(entry.Title, fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+			text,
+		)
+		popover := widget.NewPopUp(container.NewVScroll(content), v.window.Canvas())
+		popover.Resize(fyne.NewSize(320, 170))
+
+		driver := fyne.CurrentApp().Driver()
+		position := driver.AbsolutePositionForObject(button).Add(fyne.NewPos(0, button.Size().Height))
+		popover.ShowAtPosition(position)
+	})
+	button.Importance = widget.LowImportance
+	return button
+}
+
+// withHelp coloca un botón de ayuda a la derecha de un control
+func (v *NightLightView) withHelp(object fyne.CanvasObject, topic helpTopic) fyne.CanvasObject {
+	return container.NewBorder(nil, nil, nil, v.newHelpButton(topic), object)
+}
+
+/**
+ * showHelpOverlay - Muestra la ayuda general con todos los temas
+ *
+ * Se abre con el botón "?" de la cabecera o con F1.
+ *
+ * @private
+ */
+func (v *NightLightView) showHelpOverlay() {
+	topics := container.NewVBox()
+	for i, topic := range helpOrder {
+		if i > 0 {
+			topics.Add(widget.NewSeparator())
+		}
+		entry := helpTexts[topic]
+		text := widget.NewLabel(entry.Text)
+		text.Wrapping = fyne.TextWrapWord
+		topics.Add(widget.NewLabelWithStyle(entry.Title, fyne.TextAlignLeading, fyne.TextStyle{Bold: true}))
+		topics.Add(text)
+	}
+
+	overlay := dialog.NewCustom("❓ Ayuda", "Cerrar", container.NewVScroll(topics), v.window)
+	overlay.Resize(fyne.NewSize(460, 480))
+	overlay.Show()
+}
@@ -0,0 +1,105 @@
+package views
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"luznocturna/luz-nocturna/internal/controllers"
+	"luznocturna/luz-nocturna/internal/logging"
+	"luznocturna/luz-nocturna/internal/models"
+)
+
+// historyLimit es el número de entradas que muestra el panel de historial
+const historyLimit = 200
+
+// Descripción de cada tipo de evento en el historial
+var activityTypeLabels = map[string]string{
+	string(controllers.EventApplied):            "Aplicado",
+	string(controllers.EventReset):              "Restaurado",
+	string(controllers.EventScheduleTransition): "Programación",
+}
+
+/**
+ * createHistoryTab - Crea el panel con el historial de cambios aplicados
+ *
+ * Muestra fecha, origen y temperatura de cada cambio (el más reciente
+ * arriba) y permite exportarlo a CSV.
+ *
+ * @returns {fyne.CanvasObject} Contenido de la pestaña
+ * @private
+ */
+func (v *NightLightView) createHistoryTab() fyne.CanvasObject {
+	v.historyList = widget.NewList(
+		func() int { return len(v.historyEntries) },
+		func() fyne.CanvasObject {
+			label := widget.NewLabel("")
+			label.TextStyle = fyne.TextStyle{Monospace: true}
+			return label
+		},
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			item.(*widget.Label).SetText(formatActivityEntry(v.historyEntries[id]))
+		},
+	)
+
+	refreshButton := widget.NewButton("🔄 Actualizar", v.refreshHistory)
+	exportButton := widget.NewButton("📤 Exportar CSV", v.exportHistoryCSV)
+
+	v.refreshHistory()
+	return container.NewBorder(nil, container.NewGridWithColumns(2, refreshButton, exportButton), nil, nil, v.historyList)
+}
+
+// refreshHistory vuelve a leer el historial de actividad
+func (v *NightLightView) refreshHistory() {
+	entries, err := v.controller.GetActivity(historyLimit)
+	if err != nil {
+		logging.Printf("⚠️  No se pudo leer el historial: %v\n", err)
+		return
+	}
+
+	v.historyEntries = entries
+	v.historyList.Refresh()
+}
+
+/**
+ * exportHistoryCSV - Guarda el historial completo en un archivo CSV elegido por el usuario
+ *
+ * @private
+ */
+func (v *NightLightView) exportHistoryCSV() {
+	save := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil {
+			v.showErrorDialog("❌ Error al exportar", err.Error())
+			return
+		}
+		if writer == nil {
+			return // Cancelado
+		}
+		defer writer.Close()
+
+		if err := v.controller.ExportActivityCSV(writer); err != nil {
+			v.showErrorDialog("❌ Error al exportar", err.Error())
+			return
+		}
+		v.showToast("Historial exportado a " + writer.URI().Path())
+	}, v.window)
+	save.SetFileName("luz-nocturna-historial.csv")
+	save.Show()
+}
+
+// formatActivityEntry da formato a una línea del historial
+func formatActivityEntry(entry models.ActivityEntry) string {
+	kind := activityTypeLabels[entry.Type]
+	if kind == "" {
+		kind = entry.Type
+	}
+
+	state := fmt.Sprintf("%.0fK", entry.Temperature)
+	if !entry.Active {
+		state = "gamma normal"
+	}
+	return fmt.Sprintf("%s  %-12s %-10s %s", entry.Time.Format("2006-01-02 15:04"), kind, entry.Source, state)
+}
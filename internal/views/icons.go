@@ -1,7 +1,14 @@
 package views
 
 import (
+	"bytes"
 	_ "embed"
+	"image/png"
+	"os"
+
+	"luznocturna/luz-nocturna/internal/logging"
+	"luznocturna/luz-nocturna/internal/models"
+	"luznocturna/luz-nocturna/internal/system"
 )
 
 //go:embed icons/nightlight_icon.svg
@@ -13,6 +20,14 @@ var nightlightIcon16 []byte
 //go:embed icons/nightlight_icon_24.png
 var nightlightIcon24 []byte
 
+// Variantes simbólicas (monocromas) de icons/nightlight_symbolic.svg
+//
+//go:embed icons/nightlight_symbolic_light_24.png
+var nightlightSymbolicLight []byte
+
+//go:embed icons/nightlight_symbolic_dark_24.png
+var nightlightSymbolicDark []byte
+
 /**
  * GetOptimalIcon - Selecciona el icono más apropiado según el sistema
  *
@@ -34,3 +49,53 @@ func GetOptimalIcon() []byte {
 	// Último recurso: SVG
 	return nightlightIconSVG
 }
+
+/**
+ * TrayIcon - Elige el icono de la bandeja según la preferencia y el tema
+ *
+ * En automático un tema oscuro usa el icono simbólico claro y uno claro
+ * el oscuro; sin preferencia conocida se mantiene el icono a color, que
+ * tiene fondo propio. Un PNG propio que no se puede leer vuelve a
+ * automático.
+ *
+ * @param {string} setting - Valor de AppConfig.GetTrayIcon
+ * @param {system.ColorScheme} scheme - Tema actual del escritorio
+ * @returns {[]byte} Icono en PNG
+ */
+func TrayIcon(setting string, scheme system.ColorScheme) []byte {
+	switch setting {
+	case models.TrayIconColor:
+		return GetOptimalIcon()
+	case models.TrayIconLight:
+		return nightlightSymbolicLight
+	case models.TrayIconDark:
+		return nightlightSymbolicDark
+	case models.TrayIconAuto:
+	default:
+		icon, err := loadCustomIcon(setting)
+		if err == nil {
+			return icon
+		}
+		logging.Printf("⚠️  No se puede usar el icono de bandeja %s: %v\n", setting, err)
+	}
+
+	switch scheme {
+	case system.ColorSchemeDark:
+		return nightlightSymbolicLight
+	case system.ColorSchemeLight:
+		return nightlightSymbolicDark
+	}
+	return GetOptimalIcon()
+}
+
+// loadCustomIcon lee un icono propio y comprueba que sea un PNG válido
+func loadCustomIcon(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := png.DecodeConfig(bytes.NewReader(data)); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
@@ -1,17 +1,26 @@
 package views
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
 	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/data/binding"
 	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
 
+	"luznocturna/luz-nocturna/internal/companion"
 	"luznocturna/luz-nocturna/internal/controllers"
+	"luznocturna/luz-nocturna/internal/logging"
 	"luznocturna/luz-nocturna/internal/models"
 	"luznocturna/luz-nocturna/internal/styles"
+	"luznocturna/luz-nocturna/internal/system"
 )
 
 /**
@@ -24,33 +33,164 @@ import (
  * @struct {NightLightView}
  * @property {*controllers.NightLightController} controller - Controlador principal
  * @property {fyne.Window} window - Ventana principal de la aplicación
+ * @property {binding.Float} temperature - Temperatura mostrada (enlazada al slider y al label)
+ * @property {binding.Float} brightness - Brillo mostrado (para el label del preset o del boost)
+ * @property {binding.String} presetText - Texto del label del preset
+ * @property {binding.String} temperatureText - Texto del label de temperatura (en Kelvin o en calidez)
  * @property {*widget.Label} temperatureLabel - Etiqueta que muestra temperatura actual
  * @property {*widget.Slider} temperatureSlider - Control deslizante de temperatura
+ * @property {*canvas.Rectangle} tempSwatch - Muestra aproximada del blanco a la temperatura elegida
  * @property {*widget.Label} presetLabel - Etiqueta que muestra el preset actual
  * @property {*widget.Button} applyButton - Botón para aplicar configuración
  * @property {*widget.Button} resetButton - Botón para resetear a valores normales
  * @property {*widget.Button} toggleButton - Botón para alternar on/off
- * @property {*widget.Label} displayInfo - Información de displays detectados
+ * @property {*widget.Button} movieButton - Botón del modo película
+ * @property {*widget.ProgressBarInfinite} busyBar - Indicador visible mientras se aplica
+ * @property {*widget.Label} statusBar - Barra de estado con los mensajes de confirmación
+ * @property {*widget.Label} displayStatus - Franja inferior con el resultado de la última aplicación por display
+ * @property {*widget.Label} hdrInfo - Aviso de los displays con HDR (oculto si no hay)
+ * @property {*fyne.Container} displayList - Filas de la pestaña de pantallas (una por display)
  * @property {*fyne.Container} presetButtons - Contenedor de botones de presets
+ * @property {func()} unsubscribe - Cancela la suscripción a los eventos del controlador
  */
 type NightLightView struct {
 	controller        *controllers.NightLightController
 	window            fyne.Window
+	unsubscribe       func()
+	temperature       binding.Float
+	brightness        binding.Float
+	presetText        binding.String
+	temperatureText   binding.String
 	temperatureLabel  *widget.Label
 	temperatureSlider *widget.Slider
+	tempSwatch        *canvas.Rectangle // Muestra del blanco a la temperatura del slider
 	presetLabel       *widget.Label
 	applyButton       *widget.Button
 	resetButton       *widget.Button
 	toggleButton      *widget.Button
-	displayInfo       *widget.Label
+	movieButton       *widget.Button
+	busyBar           *widget.ProgressBarInfinite
+	busy              bool // Hay una aplicación en curso lanzada desde la ventana
+	statusBar         *widget.Label
+	toastSeq          int // Último mensaje de la barra de estado (los anteriores no la borran)
+	displayStatus     *widget.Label
+	hdrInfo           *widget.Label
+	displayList       *fyne.Container
+	displayRefresh    *widget.Button
+	displayInfos      []system.DisplayInfo // Últimos detalles detectados (modelo, resolución, capacidades)
+	monitorLayout     []string             // Monitores conectados según su EDID (perfiles por disposición)
+	layoutInfo        *widget.Label
+	layoutRemove      *widget.Button
 	presetButtons     *fyne.Container
 	scheduleCheck     *widget.Check
+	darkModeCheck     *widget.Check
+	templateSel       *widget.Select
 	startTimeEntry    *widget.Entry
 	endTimeEntry      *widget.Entry
 	nightTempSlider   *widget.Slider
 	dayTempSlider     *widget.Slider
 	transitionSlider  *widget.Slider
 	scheduleInfo      *widget.Label
+	scheduleError     *widget.Label
+	solarCheck        *widget.Check
+	latitudeEntry     *widget.Entry
+	longitudeEntry    *widget.Entry
+	weatherCheck      *widget.Check
+	vacationFrom      *widget.Entry
+	vacationTo        *widget.Entry
+	closeSelect       *widget.Select
+	resetOnQuitCheck  *widget.Check
+	lockResetCheck    *widget.Check
+	idlePauseCheck    *widget.Check
+	gamingCheck       *widget.Check
+	batteryCheck      *widget.Check
+	ambientCheck      *widget.Check
+	interpolationSel  *widget.Select
+	watchdogCheck     *widget.Check
+	watchdogSel       *widget.Select
+	displayScopeSel   *widget.Select
+	trimDisplaySel    *widget.Select
+	trimSliders       [3]*widget.Slider // Ajuste fino rojo, verde y azul del display elegido
+	trimLabels        [3]*widget.Label
+	layoutSel         *widget.Select
+	clockSel          *widget.Select
+	unitSel           *widget.Select
+	gammaInfo         *widget.Label
+	desktopTheme      fyne.Theme // Tema anterior al táctil (nil si no está activo)
+	snapCheck         *widget.Check
+	fineStepsCheck    *widget.Check
+	shiftHeld         bool // Shift pulsado: pasos finos temporales
+	historyList       *widget.List
+	historyEntries    []models.ActivityEntry
+	tabs              *container.AppTabs
+	scheduleConfig    *fyne.Container
+	syncingSchedule   bool // Volcando la configuración en los controles: no guardar de vuelta
+	nightTempLabel    *widget.Label
+	dayTempLabel      *widget.Label
+	transitionLabel   *widget.Label
+}
+
+// toastDuration es lo que permanece un mensaje en la barra de estado
+const toastDuration = 4 * time.Second
+
+// Títulos de las pestañas de la ventana principal (también se guardan en la configuración)
+const (
+	tabManual   = "🌡️ Manual"
+	tabSchedule = "🕐 Programación"
+	tabDisplays = "📺 Pantallas"
+	tabHistory  = "📜 Historial"
+	tabAdvanced = "⚙️ Avanzado"
+)
+
+// Opciones del selector de comportamiento al cerrar la ventana
+var closeBehaviorLabels = map[string]string{
+	models.CloseBehaviorTray: "Minimizar a la bandeja",
+	models.CloseBehaviorQuit: "Salir de la aplicación",
+	models.CloseBehaviorAsk:  "Preguntar",
+}
+
+// Opciones del selector de unidad de interpolación de las transiciones
+var interpolationLabels = map[string]string{
+	models.InterpolationMired:  "Mired (cambio percibido uniforme)",
+	models.InterpolationKelvin: "Kelvin (lineal)",
+}
+
+// Opciones del selector de monitores que reciben el filtro
+var displayScopeLabels = map[string]string{
+	models.DisplayScopeAll:      "Todas las pantallas",
+	models.DisplayScopeExternal: "Solo monitores externos",
+	models.DisplayScopeInternal: "Solo la pantalla integrada",
+}
+
+// Opciones del selector de tamaño de los controles
+var layoutModeLabels = map[string]string{
+	models.LayoutModeAuto:   "Automático",
+	models.LayoutModeNormal: "Escritorio",
+	models.LayoutModeTouch:  "Táctil (controles grandes)",
+}
+
+// Opciones del selector de formato de hora
+var clockFormatLabels = map[string]string{
+	models.ClockFormatAuto: "Según el idioma del sistema",
+	models.ClockFormat24h:  "24 horas (21:30)",
+	models.ClockFormat12h:  "12 horas (9:30 p. m.)",
+}
+
+// Opciones del selector de unidad de temperatura
+var temperatureUnitLabels = map[string]string{
+	models.TemperatureUnitKelvin: "Kelvin (4500K)",
+	models.TemperatureUnitWarmth: "Calidez (0-100 %)",
+}
+
+// Intervalos ofrecidos para el vigilante de gamma, en segundos
+var watchdogIntervals = []int{10, 30, 60, 300}
+
+// watchdogIntervalLabel formatea un intervalo del vigilante para el selector
+func watchdogIntervalLabel(seconds int) string {
+	if seconds >= 60 && seconds%60 == 0 {
+		return fmt.Sprintf("Cada %d min", seconds/60)
+	}
+	return fmt.Sprintf("Cada %d s", seconds)
 }
 
 /**
@@ -89,23 +229,112 @@ func NewNightLightView(window fyne.Window, controller *controllers.NightLightCon
  * @private
  */
 func (v *NightLightView) setupUI() {
-	// Configurar ventana principal
-	v.window.Resize(fyne.NewSize(styles.WindowWidth, styles.WindowHeight+200))
+	// Configurar ventana principal con el último tamaño usado
+	if state := v.controller.GetWindowState(); state.HasSize() {
+		v.window.Resize(fyne.NewSize(state.Width, state.Height))
+	} else {
+		v.window.Resize(fyne.NewSize(styles.WindowWidth, styles.WindowHeight+200))
+	}
 	v.window.SetFixedSize(false)
 
-	// Crear todos los widgets de la interfaz
-	v.createWidgets()
+	// Controles grandes en pantallas táctiles (o si así se configuró)
+	v.applyLayoutMode()
+
+	// Crear widgets y layout a partir de la configuración actual
+	v.buildContent()
+
+	// Mantener Shift pulsado activa los pasos finos del slider
+	v.setupStepModifier()
 
-	// Crear y establecer el layout principal
-	content := v.createMainLayout()
-	v.window.SetContent(content)
+	// Ctrl+Z / Ctrl+Shift+Z (o Ctrl+Y) para deshacer y rehacer
+	v.setupUndoShortcuts()
 
-	// Sincronizar estado inicial con el modelo
-	v.updateTemperatureDisplay()
-	v.updateDisplayInfo()
+	// F1 abre la ayuda, Espacio alterna el filtro y 1-4 (o las teclas propias) eligen presets
+	v.setupKeyShortcuts()
+
+	// Mantener la UI sincronizada con los cambios de estado del controlador
+	v.unsubscribe = v.controller.Subscribe(onUIEvent(v.onControllerEvent))
+	v.window.SetOnClosed(v.Close)
 
 	// Iniciar actualizador de información de programación
 	v.startScheduleInfoUpdater()
+
+	// Avisar si la configuración estaba dañada
+	v.checkConfigLoad()
+
+	// En la primera ejecución, ofrecer el horario nocturno del escritorio
+	go v.offerNativeSchedule()
+}
+
+// buildContent crea los widgets y el layout, y los sincroniza con el modelo
+func (v *NightLightView) buildContent() {
+	v.createWidgets()
+	v.window.SetContent(v.createMainLayout())
+
+	v.updateDisplayStatus()
+}
+
+/**
+ * checkConfigLoad - Ofrece restaurar la copia de seguridad si la configuración estaba dañada
+ *
+ * @private
+ */
+func (v *NightLightView) checkConfigLoad() {
+	var corrupt *models.ConfigCorruptError
+	if !errors.As(v.controller.ConfigLoadError(), &corrupt) {
+		return
+	}
+
+	if !corrupt.HasBackup {
+		dialog.ShowInformation("Configuración dañada",
+			"No se pudo leer la configuración y no hay copia de seguridad.\n"+
+				"Se usarán los valores por defecto; el archivo dañado se guardó como config.json.corrupt.",
+			v.window)
+		return
+	}
+
+	dialog.ShowConfirm("Configuración dañada",
+		"No se pudo leer la configuración guardada.\n¿Restaurar la última copia de seguridad?",
+		func(restore bool) {
+			if !restore {
+				return // Seguir con valores por defecto
+			}
+			if err := v.controller.RestoreConfigBackup(); err != nil {
+				v.showErrorDialog("Error al restaurar", err.Error())
+				return
+			}
+			v.buildContent()
+		}, v.window)
+}
+
+/**
+ * offerNativeSchedule - Ofrece adoptar el horario de GNOME Night Light o KDE Night Color
+ *
+ * Solo en la primera ejecución. La detección lanza gsettings/kreadconfig,
+ * así que se ejecuta fuera del hilo de la interfaz.
+ *
+ * @private
+ */
+func (v *NightLightView) offerNativeSchedule() {
+	native := v.controller.DetectNativeSchedule()
+	if native == nil {
+		return
+	}
+
+	runOnUI(func() {
+		dialog.ShowConfirm("Horario nocturno existente",
+			fmt.Sprintf("Se encontró un horario configurado en %s.\n¿Usarlo como programación automática?", native.Describe()),
+			func(adopt bool) {
+				if !adopt {
+					return
+				}
+				if err := v.controller.AdoptNativeSchedule(native); err != nil {
+					v.showErrorDialog("Error al importar el horario", err.Error())
+					return
+				}
+				v.syncScheduleControls()
+			}, v.window)
+	})
 }
 
 /**
@@ -120,19 +349,36 @@ func (v *NightLightView) createWidgets() {
 	config := v.controller.GetConfig()
 	minTemp, maxTemp := v.controller.GetTemperatureRange()
 
+	// === ESTADO ENLAZADO ===
+	// El slider y los labels se sincronizan solos al cambiar estos valores
+	v.temperature = binding.NewFloat()
+	v.temperature.Set(config.Temperature)
+	v.brightness = binding.NewFloat()
+	v.brightness.Set(config.Brightness)
+	v.presetText = binding.NewString()
+	v.temperatureText = binding.NewString()
+
 	// === LABELS DE INFORMACIÓN ===
-	v.temperatureLabel = widget.NewLabel("Temperatura de color: " + config.GetTemperatureString())
+	v.temperatureLabel = widget.NewLabelWithData(v.temperatureText)
 	v.temperatureLabel.Alignment = fyne.TextAlignCenter
 
-	v.presetLabel = widget.NewLabel(models.Presets.GetPresetName(config.Temperature))
+	v.presetLabel = widget.NewLabelWithData(v.presetText)
 	v.presetLabel.Alignment = fyne.TextAlignCenter
 	v.presetLabel.TextStyle = fyne.TextStyle{Italic: true}
 
 	// === CONTROL DESLIZANTE ===
-	v.temperatureSlider = widget.NewSlider(minTemp, maxTemp)
-	v.temperatureSlider.Value = config.Temperature
-	v.temperatureSlider.Step = 100
-	v.temperatureSlider.OnChanged = v.onTemperatureChanged
+	v.temperatureSlider = widget.NewSliderWithData(minTemp, maxTemp, v.temperature)
+	v.temperature.AddListener(binding.NewDataListener(v.onTemperatureChanged))
+	v.brightness.AddListener(binding.NewDataListener(v.updatePresetText))
+	v.brightness.AddListener(binding.NewDataListener(v.updateTemperatureText))
+	v.brightness.AddListener(binding.NewDataListener(v.updateTemperatureSwatch))
+	v.brightness.AddListener(binding.NewDataListener(v.updateGammaInfo))
+
+	v.snapCheck = widget.NewCheck("🧲 Ajustar a presets", v.onSnapToggled)
+	v.snapCheck.SetChecked(v.controller.IsSnapToPresets())
+	v.fineStepsCheck = widget.NewCheck("🎯 Pasos finos (10K)", v.onFineStepsToggled)
+	v.fineStepsCheck.SetChecked(v.controller.IsFineSteps())
+	v.updateSliderStep()
 
 	// === BOTONES DE PRESETS ===
 	v.createPresetButtons()
@@ -147,13 +393,121 @@ func (v *NightLightView) createWidgets() {
 	v.toggleButton = widget.NewButton("🔄 Toggle", v.onToggleClicked)
 	styles.StyleButton(v.toggleButton, false)
 
+	v.movieButton = widget.NewButton("", v.onMovieClicked)
+	styles.StyleButton(v.movieButton, false)
+	v.updateMovieButton()
+
+	// Solo se ve mientras se aplica (por DDC puede tardar varios segundos)
+	v.busyBar = widget.NewProgressBarInfinite()
+	v.busyBar.Stop()
+	v.busyBar.Hide()
+
 	// === INFORMACIÓN DEL SISTEMA ===
-	displays := v.controller.GetDisplays()
-	v.displayInfo = widget.NewLabel(fmt.Sprintf("📺 Displays: %v", displays))
-	v.displayInfo.TextStyle = fyne.TextStyle{Monospace: true}
+	v.displayStatus = widget.NewLabel("")
+	v.displayStatus.Wrapping = fyne.TextWrapWord
+	v.hdrInfo = widget.NewLabel("")
+	v.hdrInfo.Wrapping = fyne.TextWrapWord
 
 	// === CONTROLES DE PROGRAMACIÓN AUTOMÁTICA ===
 	v.createScheduleWidgets()
+
+	// === AJUSTES DE LA APLICACIÓN ===
+	v.createSettingsWidgets()
+}
+
+/**
+ * createSettingsWidgets - Crea los controles de comportamiento de la ventana
+ *
+ * @private
+ */
+func (v *NightLightView) createSettingsWidgets() {
+	options := []string{
+		closeBehaviorLabels[models.CloseBehaviorTray],
+		closeBehaviorLabels[models.CloseBehaviorQuit],
+		closeBehaviorLabels[models.CloseBehaviorAsk],
+	}
+	v.closeSelect = widget.NewSelect(options, nil)
+	v.closeSelect.SetSelected(closeBehaviorLabels[v.controller.GetCloseBehavior()])
+	v.closeSelect.OnChanged = v.onCloseBehaviorChanged
+
+	v.resetOnQuitCheck = widget.NewCheck("🔄 Restaurar gamma al salir", v.onResetOnQuitToggled)
+	v.resetOnQuitCheck.SetChecked(v.controller.IsResetOnQuit())
+
+	v.lockResetCheck = widget.NewCheck("🔐 Gamma normal con la sesión bloqueada", nil)
+	v.lockResetCheck.SetChecked(v.controller.IsResetWhileLocked())
+	v.lockResetCheck.OnChanged = v.onLockResetToggled
+
+	v.idlePauseCheck = widget.NewCheck("💤 Pausar los cambios automáticos con el equipo inactivo", nil)
+	v.idlePauseCheck.SetChecked(v.controller.IsPauseWhileIdle())
+	v.idlePauseCheck.OnChanged = v.onIdlePauseToggled
+
+	v.gamingCheck = widget.NewCheck("🎮 Suspender con gamescope o Steam Big Picture", nil)
+	v.gamingCheck.SetChecked(v.controller.IsSuspendForGames())
+	v.gamingCheck.OnChanged = v.onGamingToggled
+
+	v.batteryCheck = widget.NewCheck("🔋 Más cálida y tenue con batería", nil)
+	v.batteryCheck.SetChecked(v.controller.GetBattery().Enabled)
+	v.batteryCheck.OnChanged = v.onBatteryToggled
+
+	v.ambientCheck = widget.NewCheck("📷 Adaptar a la luz de la habitación (webcam)", nil)
+	v.ambientCheck.SetChecked(v.controller.GetAmbient().Enabled)
+	v.ambientCheck.OnChanged = v.onAmbientToggled
+
+	v.interpolationSel = widget.NewSelect([]string{
+		interpolationLabels[models.InterpolationMired],
+		interpolationLabels[models.InterpolationKelvin],
+	}, nil)
+	v.interpolationSel.SetSelected(interpolationLabels[v.controller.GetInterpolation()])
+	v.interpolationSel.OnChanged = v.onInterpolationChanged
+
+	watchdog := v.controller.GetWatchdog()
+	v.watchdogCheck = widget.NewCheck("🐕 Vigilar la gamma y reaplicarla si cambia", nil)
+	v.watchdogCheck.SetChecked(watchdog.Enabled)
+	v.watchdogCheck.OnChanged = func(bool) { v.onWatchdogChanged() }
+
+	intervals := make([]string, len(watchdogIntervals))
+	for i, seconds := range watchdogIntervals {
+		intervals[i] = watchdogIntervalLabel(seconds)
+	}
+	v.watchdogSel = widget.NewSelect(intervals, nil)
+	v.watchdogSel.SetSelected(watchdogIntervalLabel(int(watchdog.GetInterval().Seconds())))
+	v.watchdogSel.OnChanged = func(string) { v.onWatchdogChanged() }
+
+	v.displayScopeSel = widget.NewSelect([]string{
+		displayScopeLabels[models.DisplayScopeAll],
+		displayScopeLabels[models.DisplayScopeExternal],
+		displayScopeLabels[models.DisplayScopeInternal],
+	}, nil)
+	v.displayScopeSel.SetSelected(displayScopeLabels[v.controller.GetDisplayScope()])
+	v.displayScopeSel.OnChanged = v.onDisplayScopeChanged
+
+	v.layoutSel = widget.NewSelect([]string{
+		layoutModeLabels[models.LayoutModeAuto],
+		layoutModeLabels[models.LayoutModeNormal],
+		layoutModeLabels[models.LayoutModeTouch],
+	}, nil)
+	v.layoutSel.SetSelected(layoutModeLabels[v.controller.GetLayoutMode()])
+	v.layoutSel.OnChanged = v.onLayoutModeChanged
+
+	v.clockSel = widget.NewSelect([]string{
+		clockFormatLabels[models.ClockFormatAuto],
+		clockFormatLabels[models.ClockFormat24h],
+		clockFormatLabels[models.ClockFormat12h],
+	}, nil)
+	v.clockSel.SetSelected(clockFormatLabels[v.controller.GetClockFormat()])
+	v.clockSel.OnChanged = v.onClockFormatChanged
+
+	v.unitSel = widget.NewSelect([]string{
+		temperatureUnitLabels[models.TemperatureUnitKelvin],
+		temperatureUnitLabels[models.TemperatureUnitWarmth],
+	}, nil)
+	v.unitSel.SetSelected(temperatureUnitLabels[v.controller.GetTemperatureUnit()])
+	v.unitSel.OnChanged = v.onTemperatureUnitChanged
+
+	v.gammaInfo = widget.NewLabel("")
+	v.gammaInfo.TextStyle = fyne.TextStyle{Monospace: true}
+	v.gammaInfo.Wrapping = fyne.TextWrapWord
+	v.updateGammaInfo()
 }
 
 /**
@@ -168,14 +522,34 @@ func (v *NightLightView) createScheduleWidgets() {
 	v.scheduleCheck = widget.NewCheck("🕐 Programación automática", v.onScheduleToggled)
 	v.scheduleCheck.SetChecked(v.controller.IsScheduleEnabled())
 
-	// Entradas de tiempo
+	// Alternativa a la programación: seguir el tema oscuro del escritorio
+	v.darkModeCheck = widget.NewCheck("🌗 Noche con el tema oscuro del escritorio", nil)
+	v.darkModeCheck.SetChecked(v.controller.IsFollowDarkMode())
+	v.darkModeCheck.OnChanged = v.onDarkModeToggled
+
+	// Plantillas como punto de partida (madrugador, noctámbulo...)
+	templates := make([]string, len(models.ScheduleTemplates))
+	for i, template := range models.ScheduleTemplates {
+		templates[i] = template.Label()
+	}
+	v.templateSel = widget.NewSelect(templates, v.onScheduleTemplateSelected)
+	v.templateSel.PlaceHolder = "Elegir una plantilla..."
+
+	// Entradas de tiempo validadas (HH:MM o 12 horas); muestran el error junto al campo
 	v.startTimeEntry = widget.NewEntry()
-	v.startTimeEntry.SetText(schedule.StartTime)
+	v.startTimeEntry.Validator = validateScheduleTime
 	v.startTimeEntry.OnChanged = v.onScheduleTimeChanged
 
 	v.endTimeEntry = widget.NewEntry()
-	v.endTimeEntry.SetText(schedule.EndTime)
+	v.endTimeEntry.Validator = validateScheduleTime
 	v.endTimeEntry.OnChanged = v.onScheduleTimeChanged
+	v.updateScheduleTimeEntries()
+
+	// Mensaje de error en línea para horarios inválidos
+	v.scheduleError = widget.NewLabel("")
+	v.scheduleError.Importance = widget.DangerImportance
+	v.scheduleError.Wrapping = fyne.TextWrapWord
+	v.scheduleError.Hide()
 
 	// Sliders de temperatura
 	v.nightTempSlider = widget.NewSlider(3000, 6500)
@@ -194,6 +568,41 @@ func (v *NightLightView) createScheduleWidgets() {
 	v.transitionSlider.Step = 5
 	v.transitionSlider.OnChanged = v.onScheduleTempChanged
 
+	// Labels con el valor actual de cada slider
+	v.nightTempLabel = widget.NewLabel("")
+	v.dayTempLabel = widget.NewLabel("")
+	v.transitionLabel = widget.NewLabel("")
+	v.updateScheduleLabels()
+
+	// Modo solar: temperatura continua según la elevación del sol
+	v.solarCheck = widget.NewCheck("☀️ Seguir el sol (elevación solar)", v.onSolarModeToggled)
+	v.solarCheck.SetChecked(schedule.Mode == models.ScheduleModeSolar)
+
+	v.latitudeEntry = widget.NewEntry()
+	v.latitudeEntry.SetPlaceHolder("Latitud (ej: 4.61)")
+	v.longitudeEntry = widget.NewEntry()
+	v.longitudeEntry.SetPlaceHolder("Longitud (ej: -74.08)")
+	if schedule.Location.IsSet() {
+		v.latitudeEntry.SetText(fmt.Sprintf("%.4f", schedule.Location.Latitude))
+		v.longitudeEntry.SetText(fmt.Sprintf("%.4f", schedule.Location.Longitude))
+	}
+	v.latitudeEntry.OnChanged = v.onLocationChanged
+	v.longitudeEntry.OnChanged = v.onLocationChanged
+
+	// Ajuste por nubosidad: usa la misma ubicación
+	v.weatherCheck = widget.NewCheck("🌥️ Más cálida en días nublados", nil)
+	v.weatherCheck.SetChecked(v.controller.GetWeather().Enabled)
+	v.weatherCheck.OnChanged = v.onWeatherToggled
+
+	// Modo vacaciones: pausa la programación y el vigilante entre dos fechas
+	vacation := v.controller.GetVacation()
+	v.vacationFrom = widget.NewEntry()
+	v.vacationFrom.SetPlaceHolder("Desde (AAAA-MM-DD)")
+	v.vacationFrom.SetText(vacation.From)
+	v.vacationTo = widget.NewEntry()
+	v.vacationTo.SetPlaceHolder("Hasta (AAAA-MM-DD)")
+	v.vacationTo.SetText(vacation.To)
+
 	// Información de próximo cambio
 	v.scheduleInfo = widget.NewLabel("Programación deshabilitada")
 	v.scheduleInfo.TextStyle = fyne.TextStyle{Italic: true}
@@ -204,42 +613,48 @@ func (v *NightLightView) createScheduleWidgets() {
 /**
  * createPresetButtons - Crea los botones de presets de temperatura
  *
- * Genera botones rápidos para temperaturas predefinidas comunes:
- * Cálida (3000K), Neutra (4500K), Fría (5500K), Diurna (6500K)
+ * Genera un botón rápido por cada preset definido por el usuario
+ * (guardados en la configuración) y un botón para gestionarlos.
  *
  * @private
  */
 func (v *NightLightView) createPresetButtons() {
-	presets := []struct {
-		name string
-		temp float64
-		icon string
-	}{
-		{"Cálida", models.CandleLightTemp, "🕯️"},
-		{"Neutra", models.NeutralWhiteTemp, "☀️"},
-		{"Fría", models.CoolWhiteTemp, "🌤️"},
-		{"Diurna", models.DaylightTemp, "☀️"},
-	}
+	v.presetButtons = container.NewGridWithColumns(2)
+	v.refreshPresetButtons()
+}
 
+/**
+ * refreshPresetButtons - Regenera los botones a partir de la lista de presets
+ *
+ * @private
+ */
+func (v *NightLightView) refreshPresetButtons() {
 	var buttons []fyne.CanvasObject
-	for _, preset := range presets {
-		temp := preset.temp // Capturar valor para closure
-		btn := widget.NewButton(preset.icon+" "+preset.name, func() {
-			v.controller.UpdateTemperature(temp)
-			v.temperatureSlider.Value = temp
-			v.updateTemperatureDisplay()
-		})
+	for i, preset := range v.controller.GetPresets() {
+		index := i // Capturar valor para closure
+		btn := widget.NewButton(preset.Icon+" "+preset.Name, func() { v.selectPreset(index) })
 		buttons = append(buttons, btn)
 	}
 
-	v.presetButtons = container.NewGridWithColumns(2, buttons...)
+	buttons = append(buttons, widget.NewButton("⚙️ Gestionar", v.showPresetManager))
+
+	v.presetButtons.Objects = buttons
+	v.presetButtons.Refresh()
+}
+
+// selectPreset selecciona el preset de la posición indicada (botones y atajos de teclado)
+func (v *NightLightView) selectPreset(index int) {
+	if err := v.controller.SelectPreset(index); err != nil {
+		v.showApplyError("❌ Error de preset", err, func() { v.selectPreset(index) })
+	}
 }
 
 /**
  * createMainLayout - Crea el layout principal de la aplicación
  *
- * Organiza todos los widgets en un layout vertical con separadores,
- * creando una interfaz limpia y bien organizada.
+ * Organiza los controles en pestañas (Manual, Programación, Pantallas y
+ * Avanzado) para que mostrar u ocultar opciones no obligue a redimensionar
+ * la ventana ni a recrear todo el contenido.
  *
  * @returns {fyne.CanvasObject} Contenedor principal listo para mostrar
  * @private
@@ -250,11 +665,52 @@ func (v *NightLightView) createMainLayout() fyne.CanvasObject {
 	title.Alignment = fyne.TextAlignCenter
 	title.TextStyle = fyne.TextStyle{Bold: true}
 
+	v.tabs = container.NewAppTabs(
+		container.NewTabItem(tabManual, v.createManualTab()),
+		container.NewTabItem(tabSchedule, container.NewVScroll(v.createScheduleSection())),
+		container.NewTabItem(tabDisplays, v.createDisplaysTab()),
+		container.NewTabItem(tabHistory, v.createHistoryTab()),
+		container.NewTabItem(tabAdvanced, v.createSettingsSection()),
+	)
+
+	// Restaurar la última pestaña usada antes de escuchar cambios
+	lastTab := v.controller.GetWindowState().Tab
+	for _, item := range v.tabs.Items {
+		if item.Text == lastTab {
+			v.tabs.Select(item)
+		}
+	}
+	v.tabs.OnSelected = v.onTabSelected
+
+	// Cabecera con el botón de ayuda general (también con F1)
+	helpButton := widget.NewButtonWithIcon("", theme.QuestionIcon(), v.showHelpOverlay)
+	helpButton.Importance = widget.LowImportance
+	header := container.NewBorder(nil, nil, nil, helpButton, title)
+
+	// Barra de estado para las confirmaciones (sustituye a los diálogos de éxito)
+	v.statusBar = widget.NewLabel("")
+	v.statusBar.Truncation = fyne.TextTruncateEllipsis
+
+	// Pie: resultado por display y confirmaciones
+	footer := container.NewVBox(widget.NewSeparator(), v.displayStatus, v.statusBar)
+
+	// Contenedor con padding para mejor apariencia
+	return container.NewPadded(container.NewBorder(header, footer, nil, nil, v.tabs))
+}
+
+/**
+ * createManualTab - Crea la pestaña de control manual
+ *
+ * @returns {fyne.CanvasObject} Temperatura, presets y botones de acción
+ * @private
+ */
+func (v *NightLightView) createManualTab() fyne.CanvasObject {
 	// Sección de control de temperatura
 	tempContainer := container.NewVBox(
-		v.temperatureLabel,
+		v.withHelp(v.temperatureLabel, helpTemperature),
 		v.presetLabel,
-		v.temperatureSlider,
+		container.NewBorder(nil, nil, nil, v.createTemperatureSwatch(), v.temperatureSlider),
+		container.NewHBox(v.snapCheck, v.fineStepsCheck),
 	)
 
 	// Sección de presets rápidos
@@ -264,47 +720,33 @@ func (v *NightLightView) createMainLayout() fyne.CanvasObject {
 	)
 
 	// Botones principales de acción
-	buttonContainer := container.NewGridWithColumns(3,
+	buttonContainer := container.NewGridWithColumns(2,
 		v.applyButton,
 		v.resetButton,
 		v.toggleButton,
+		v.movieButton,
 	)
 
-	// Sección de programación automática
-	scheduleSection := v.createScheduleSection()
-
-	// Layout principal con separadores para claridad visual
-	mainContainer := container.NewVBox(
-		title,
-		widget.NewSeparator(),
+	return container.NewVBox(
 		tempContainer,
 		widget.NewSeparator(),
 		presetSection,
 		widget.NewSeparator(),
 		buttonContainer,
-		widget.NewSeparator(),
-		scheduleSection,
-		widget.NewSeparator(),
-		v.displayInfo,
+		v.busyBar,
 	)
-
-	// Contenedor con padding para mejor apariencia
-	return container.NewPadded(mainContainer)
 }
 
 /**
  * createScheduleSection - Crea la sección de programación automática
  *
+ * Los controles se muestran u ocultan con el checkbox sin recrear el layout.
+ *
  * @returns {fyne.CanvasObject} Contenedor de la sección de programación
  * @private
  */
 func (v *NightLightView) createScheduleSection() fyne.CanvasObject {
-	// Contenedor principal de programación
-	scheduleContainer := container.NewVBox(
-		v.scheduleCheck,
-	)
-
-	// Controles de horarios (solo se muestran si está habilitado)
+	// Controles de horarios
 	timeContainer := container.NewGridWithColumns(4,
 		widget.NewLabel("Inicio:"),
 		v.startTimeEntry,
@@ -314,318 +756,1223 @@ func (v *NightLightView) createScheduleSection() fyne.CanvasObject {
 
 	// Controles de temperatura
 	tempContainer := container.NewVBox(
-		widget.NewLabel(fmt.Sprintf("🌙 Temperatura nocturna: %.0fK", v.nightTempSlider.Value)),
+		v.scheduleError,
+		v.nightTempLabel,
 		v.nightTempSlider,
-		widget.NewLabel(fmt.Sprintf("☀️ Temperatura diurna: %.0fK", v.dayTempSlider.Value)),
+		v.dayTempLabel,
 		v.dayTempSlider,
 	)
 
 	// Control de transición
 	transitionContainer := container.NewVBox(
-		widget.NewLabel(fmt.Sprintf("⏱️ Transición: %.0f min", v.transitionSlider.Value)),
+		v.withHelp(v.transitionLabel, helpTransition),
 		v.transitionSlider,
 	)
 
-	// Información de estado
-	infoContainer := container.NewVBox(
-		v.scheduleInfo,
+	// Controles del modo solar
+	solarContainer := container.NewVBox(
+		v.withHelp(v.solarCheck, helpSolar),
+		container.NewGridWithColumns(2, v.latitudeEntry, v.longitudeEntry),
+		v.withHelp(v.weatherCheck, helpWeather),
 	)
 
-	// Crear contenedor colapsable para controles de programación
-	configContainer := container.NewVBox()
+	// Controles del modo vacaciones
+	vacationContainer := container.NewVBox(
+		v.withHelp(widget.NewLabel("🏖️ Vacaciones (programación y vigilante en pausa):"), helpVacation),
+		container.NewGridWithColumns(2, v.vacationFrom, v.vacationTo),
+		container.NewGridWithColumns(2,
+			widget.NewButton("💾 Guardar fechas", v.onVacationSaved),
+			widget.NewButton("✖️ Quitar", v.onVacationCleared),
+		),
+	)
 
-	// Agregar controles condicionalmente
-	if v.controller.IsScheduleEnabled() {
-		configContainer.Add(timeContainer)
-		configContainer.Add(tempContainer)
-		configContainer.Add(transitionContainer)
+	// Contenedor colapsable para controles de programación
+	v.scheduleConfig = container.NewVBox(
+		container.NewBorder(nil, nil, widget.NewLabel("📋 Plantilla:"), nil, v.templateSel),
+		timeContainer,
+		tempContainer,
+		transitionContainer,
+		solarContainer,
+		vacationContainer,
+	)
+	if !v.controller.IsScheduleEnabled() {
+		v.scheduleConfig.Hide()
 	}
 
-	scheduleContainer.Add(configContainer)
-	scheduleContainer.Add(infoContainer)
-
 	return container.NewVBox(
-		widget.NewLabel("🕐 Programación Automática:"),
-		scheduleContainer,
+		v.withHelp(v.scheduleCheck, helpSchedule),
+		v.darkModeCheck,
+		v.scheduleConfig,
+		v.scheduleInfo,
 	)
 }
 
-// =====================================================
-// MANEJADORES DE EVENTOS (Event Handlers)
-// =====================================================
-
 /**
- * onTemperatureChanged - Manejador de cambio en el slider de temperatura
+ * createSettingsSection - Crea la sección de ajustes de la aplicación
  *
- * Se ejecuta cuando el usuario mueve el slider. Actualiza el modelo
- * y la interfaz en tiempo real para mostrar el cambio.
- *
- * @param {float64} value - Nueva temperatura seleccionada en Kelvin
- * @callback - Evento del slider
+ * @returns {fyne.CanvasObject} Contenedor de la sección de ajustes
+ * @private
  */
-func (v *NightLightView) onTemperatureChanged(value float64) {
-	v.controller.UpdateTemperature(value)
-	v.updateTemperatureDisplay()
+func (v *NightLightView) createSettingsSection() fyne.CanvasObject {
+	return container.NewVBox(
+		container.NewBorder(nil, nil, widget.NewLabel("Al cerrar la ventana:"), nil, v.closeSelect),
+		v.resetOnQuitCheck,
+		v.lockResetCheck,
+		v.idlePauseCheck,
+		v.gamingCheck,
+		v.batteryCheck,
+		v.ambientCheck,
+		container.NewBorder(nil, nil, widget.NewLabel("Transiciones:"), nil, v.interpolationSel),
+		container.NewBorder(nil, nil, v.watchdogCheck, nil, v.watchdogSel),
+		container.NewBorder(nil, nil, widget.NewLabel("Controles:"), nil, v.layoutSel),
+		container.NewBorder(nil, nil, widget.NewLabel("Formato de hora:"), nil, v.clockSel),
+		container.NewBorder(nil, nil, widget.NewLabel("Mostrar temperatura en:"), nil, v.unitSel),
+		v.createChannelTrimSection(),
+		v.gammaInfo,
+		v.withHelp(widget.NewLabel("🔒 Control exclusivo de la gamma"), helpExclusive),
+		v.createCompanionButton(),
+		widget.NewSeparator(),
+		widget.NewButton("ℹ️ Acerca de", v.showAboutDialog),
+	)
 }
 
 /**
- * onApplyClicked - Manejador del botón Aplicar
+ * createCompanionButton - Botón que instala el widget del panel de GNOME o Plasma
  *
- * Aplica la temperatura actual al sistema usando el controlador.
- * Muestra feedback visual del resultado (éxito o error).
+ * Solo se muestra en esos escritorios; en el resto queda oculto.
  *
- * @callback - Evento del botón Aplicar
+ * @returns {fyne.CanvasObject} Botón de instalación
+ * @private
  */
-func (v *NightLightView) onApplyClicked() {
-	err := v.controller.ApplyNightLight()
-	if err != nil {
-		v.showErrorDialog("❌ Error al aplicar", err.Error())
-		return
+func (v *NightLightView) createCompanionButton() fyne.CanvasObject {
+	desktopWidget, found := companion.Detect()
+	button := widget.NewButton("🧩 Instalar widget del panel ("+desktopWidget.Name+")", func() {
+		dir, err := desktopWidget.Install()
+		if err != nil {
+			v.showErrorDialog("❌ Error al instalar", err.Error())
+			return
+		}
+		logging.Printf("🧩 %s → %s\n", desktopWidget.Name, dir)
+		dialog.ShowInformation("🧩 "+desktopWidget.Name, desktopWidget.Hint, v.window)
+	})
+	if !found {
+		button.Hide()
 	}
-
-	config := v.controller.GetConfig()
-	message := fmt.Sprintf("🌡️ Aplicada: %s", config.GetTemperatureString())
-	v.showSuccessDialog(message)
+	return button
 }
 
+// =====================================================
+// MANEJADORES DE EVENTOS (Event Handlers)
+// =====================================================
+
 /**
- * onResetClicked - Manejador del botón Reset
+ * OnCloseRequested - Manejador del cierre de la ventana principal
  *
- * Resetea la configuración a valores normales (6500K) y actualiza
- * tanto el sistema como la interfaz.
+ * Según la configuración oculta la ventana en la bandeja, sale de la
+ * aplicación o pregunta al usuario. Si no hay bandeja disponible
+ * siempre sale, para no dejar la aplicación invisible.
  *
- * @callback - Evento del botón Reset
+ * @callback - Interceptor de cierre de la ventana
+ * @example
+ *   window.SetCloseIntercept(mainView.OnCloseRequested)
  */
-func (v *NightLightView) onResetClicked() {
-	err := v.controller.ResetNightLight()
-	if err != nil {
-		v.showErrorDialog("❌ Error al resetear", err.Error())
-		return
+func (v *NightLightView) OnCloseRequested() {
+	behavior := v.controller.GetCloseBehavior()
+	if behavior != models.CloseBehaviorQuit && !IsTrayAvailable() {
+		behavior = models.CloseBehaviorQuit
 	}
 
-	// Actualizar UI después del reset
-	config := v.controller.GetConfig()
-	v.temperatureSlider.Value = config.Temperature
-	v.updateTemperatureDisplay()
-
-	v.showSuccessDialog("✅ Gamma reseteada a valores normales")
-}
+	v.SaveWindowState()
 
-/**
- * onScheduleToggled - Manejador del checkbox de programación automática
- *
- * @param {bool} enabled - Estado del checkbox
- * @callback - Evento del checkbox
- */
-func (v *NightLightView) onScheduleToggled(enabled bool) {
-	v.controller.EnableSchedule(enabled)
-	v.refreshScheduleSection()
-	v.updateScheduleInfo()
+	switch behavior {
+	case models.CloseBehaviorTray:
+		v.window.Hide()
+	case models.CloseBehaviorQuit:
+		fyne.CurrentApp().Quit()
+	default:
+		v.askCloseBehavior()
+	}
 }
 
 /**
- * onScheduleTimeChanged - Manejador de cambios en entradas de tiempo
+ * Close - Deja de escuchar los eventos del controlador
  *
- * @param {string} text - Nuevo texto en la entrada
- * @callback - Evento de cambio en entradas de tiempo
+ * Se llama al cerrarse la ventana: los eventos que lleguen después (de
+ * D-Bus, la programación...) ya no tocan widgets destruidos.
  */
-func (v *NightLightView) onScheduleTimeChanged(text string) {
-	if !v.controller.IsScheduleEnabled() {
-		return
+func (v *NightLightView) Close() {
+	if v.unsubscribe != nil {
+		v.unsubscribe()
+		v.unsubscribe = nil
 	}
-
-	v.updateScheduleConfiguration()
 }
 
 /**
- * onScheduleTempChanged - Manejador de cambios en sliders de temperatura
+ * SaveWindowState - Guarda el tamaño actual de la ventana en la configuración
  *
- * @param {float64} value - Nuevo valor del slider
- * @callback - Evento de cambio en sliders
+ * Se llama antes de ocultar la ventana o salir de la aplicación.
  */
-func (v *NightLightView) onScheduleTempChanged(value float64) {
-	if !v.controller.IsScheduleEnabled() {
-		return
+func (v *NightLightView) SaveWindowState() {
+	size := v.window.Canvas().Size()
+	if err := v.controller.SaveWindowSize(size.Width, size.Height); err != nil {
+		logging.Printf("⚠️  No se pudo guardar el tamaño de la ventana: %v\n", err)
 	}
-
-	v.updateScheduleConfiguration()
-	v.refreshScheduleSection() // Actualizar labels de temperatura
 }
 
 /**
- * updateScheduleConfiguration - Actualiza la configuración de horarios
+ * askCloseBehavior - Pregunta si minimizar a la bandeja o salir
+ *
+ * Permite recordar la elección para no volver a preguntar.
  *
  * @private
  */
-func (v *NightLightView) updateScheduleConfiguration() {
-	// Obtener valores actuales de la UI
-	startTime := v.startTimeEntry.Text
-	endTime := v.endTimeEntry.Text
-	nightTemp := v.nightTempSlider.Value
-	dayTemp := v.dayTempSlider.Value
-	transitionTime := int(v.transitionSlider.Value)
+func (v *NightLightView) askCloseBehavior() {
+	remember := widget.NewCheck("Recordar mi elección", nil)
+	content := container.NewVBox(
+		widget.NewLabel("¿Quieres salir o seguir en la bandeja del sistema?"),
+		remember,
+	)
 
-	// Actualizar configuración
-	v.controller.UpdateScheduleConfig(startTime, endTime, nightTemp, dayTemp, transitionTime)
+	dialog.ShowCustomConfirm("Cerrar Luz Nocturna", "Salir", "Minimizar a la bandeja", content,
+		func(quit bool) {
+			behavior := models.CloseBehaviorTray
+			if quit {
+				behavior = models.CloseBehaviorQuit
+			}
 
-	// Actualizar información
-	v.updateScheduleInfo()
+			if remember.Checked {
+				if err := v.controller.SetCloseBehavior(behavior); err == nil {
+					v.closeSelect.SetSelected(closeBehaviorLabels[behavior])
+				}
+			}
+
+			if quit {
+				fyne.CurrentApp().Quit()
+			} else {
+				v.window.Hide()
+			}
+		}, v.window)
 }
 
 /**
- * onToggleClicked - Manejador del botón Toggle
- *
- * Alterna entre activar y desactivar la luz nocturna.
- * Si está activa la desactiva, si está inactiva la activa.
+ * onCloseBehaviorChanged - Manejador del selector de comportamiento al cerrar
  *
- * @callback - Evento del botón Toggle
+ * @param {string} label - Opción seleccionada
+ * @callback - Evento del selector
  */
-func (v *NightLightView) onToggleClicked() {
-	err := v.controller.ToggleNightLight()
-	if err != nil {
-		v.showErrorDialog("❌ Error al cambiar estado", err.Error())
-		return
-	}
-
-	config := v.controller.GetConfig()
-	var message string
-	if config.IsActive {
-		message = "🔥 Luz nocturna activada"
-	} else {
-		message = "❄️ Luz nocturna desactivada"
+func (v *NightLightView) onCloseBehaviorChanged(label string) {
+	for behavior, text := range closeBehaviorLabels {
+		if text == label {
+			if err := v.controller.SetCloseBehavior(behavior); err != nil {
+				v.showErrorDialog("❌ Error de ajustes", err.Error())
+			}
+			return
+		}
 	}
-
-	// Actualizar UI
-	v.temperatureSlider.Value = config.Temperature
-	v.updateTemperatureDisplay()
-	v.showSuccessDialog(message)
 }
 
-// =====================================================
-// MÉTODOS DE ACTUALIZACIÓN DE UI
-// =====================================================
-
 /**
- * updateTemperatureDisplay - Actualiza la visualización de temperatura
+ * onInterpolationChanged - Manejador del selector de unidad de interpolación
  *
- * Sincroniza los labels de temperatura y preset con el estado actual
- * del modelo. Se llama cada vez que cambia la temperatura.
- *
- * @private
+ * @param {string} label - Opción seleccionada
+ * @callback - Evento del selector
  */
-func (v *NightLightView) updateTemperatureDisplay() {
-	config := v.controller.GetConfig()
-	v.temperatureLabel.SetText("🌡️ Temperatura: " + config.GetTemperatureString())
-	v.presetLabel.SetText("✨ " + models.Presets.GetPresetName(config.Temperature))
+func (v *NightLightView) onInterpolationChanged(label string) {
+	for unit, text := range interpolationLabels {
+		if text == label {
+			if err := v.controller.SetInterpolation(unit); err != nil {
+				v.showErrorDialog("❌ Error de ajustes", err.Error())
+			}
+			return
+		}
+	}
 }
 
 /**
- * updateDisplayInfo - Actualiza la información de displays
+ * onWatchdogChanged - Manejador del checkbox y el selector del vigilante de gamma
  *
- * Refresca la información de displays detectados por el sistema.
- * Útil cuando se conectan/desconectan monitores.
- *
- * @private
+ * @callback - Evento del checkbox o del selector
  */
-func (v *NightLightView) updateDisplayInfo() {
-	displays := v.controller.GetDisplays()
-	v.displayInfo.SetText(fmt.Sprintf("📺 Displays: %v", displays))
+func (v *NightLightView) onWatchdogChanged() {
+	interval := models.DefaultWatchdogInterval
+	for _, seconds := range watchdogIntervals {
+		if watchdogIntervalLabel(seconds) == v.watchdogSel.Selected {
+			interval = seconds
+		}
+	}
+	if err := v.controller.SetWatchdog(v.watchdogCheck.Checked, interval); err != nil {
+		v.showErrorDialog("❌ Error de ajustes", err.Error())
+	}
 }
 
 /**
- * updateScheduleInfo - Actualiza la información de programación automática
+ * onDisplayScopeChanged - Manejador del selector de monitores que reciben el filtro
  *
- * @private
+ * @param {string} label - Opción seleccionada
+ * @callback - Evento del selector
  */
-func (v *NightLightView) updateScheduleInfo() {
-	if !v.controller.IsScheduleEnabled() {
-		v.scheduleInfo.SetText("Programación deshabilitada")
-		return
+func (v *NightLightView) onDisplayScopeChanged(label string) {
+	for scope, text := range displayScopeLabels {
+		if text == label {
+			if err := v.controller.SetDisplayScope(scope); err != nil {
+				v.showApplyError("❌ Error al aplicar", err, func() { v.onDisplayScopeChanged(label) })
+			}
+			return
+		}
 	}
+}
 
-	description, temp, duration := v.controller.GetNextScheduleChange()
-
-	if duration > 0 {
-		hours := int(duration.Hours())
-		minutes := int(duration.Minutes()) % 60
-		v.scheduleInfo.SetText(fmt.Sprintf("🔔 %s en %02d:%02d (%.0fK)",
-			description, hours, minutes, temp))
-	} else {
-		v.scheduleInfo.SetText("🔔 " + description)
+/**
+ * onClockFormatChanged - Manejador del selector de formato de hora
+ *
+ * @param {string} label - Opción seleccionada
+ * @callback - Evento del selector
+ */
+func (v *NightLightView) onClockFormatChanged(label string) {
+	for format, text := range clockFormatLabels {
+		if text == label {
+			if err := v.controller.SetClockFormat(format); err != nil {
+				v.showErrorDialog("❌ Error de ajustes", err.Error())
+				return
+			}
+			v.updateScheduleTimeEntries()
+			v.updateScheduleInfo()
+			return
+		}
 	}
 }
 
 /**
- * updateScheduleLabels - Actualiza los labels de los sliders de programación
+ * onTemperatureUnitChanged - Manejador del selector de unidad de temperatura
  *
- * @private
+ * @param {string} label - Opción seleccionada
+ * @callback - Evento del selector
  */
-func (v *NightLightView) updateScheduleLabels() {
-	// Esta función se llamará desde createScheduleSection cuando se recree el layout
-	// Los labels se actualizan automáticamente en createScheduleSection
+func (v *NightLightView) onTemperatureUnitChanged(label string) {
+	for unit, text := range temperatureUnitLabels {
+		if text == label {
+			if err := v.controller.SetTemperatureUnit(unit); err != nil {
+				v.showErrorDialog("❌ Error de ajustes", err.Error())
+				return
+			}
+			v.updateTemperatureText()
+			v.updateScheduleLabels()
+			v.updateScheduleInfo()
+			return
+		}
+	}
 }
 
 /**
- * refreshScheduleSection - Refresca la sección de programación automática
+ * updateScheduleTimeEntries - Muestra las horas de la programación en el formato elegido
  *
  * @private
  */
-func (v *NightLightView) refreshScheduleSection() {
-	// Ajustar tamaño de ventana según estado de programación
-	if v.controller.IsScheduleEnabled() {
-		v.window.Resize(fyne.NewSize(styles.WindowWidth, styles.WindowHeight+300))
-	} else {
-		v.window.Resize(fyne.NewSize(styles.WindowWidth, styles.WindowHeight+150))
-	}
+func (v *NightLightView) updateScheduleTimeEntries() {
+	clock := v.controller.GetClock()
+	schedule := v.controller.GetScheduleConfig()
 
-	// Recrear el contenido de la ventana para mostrar/ocultar controles de programación
-	content := v.createMainLayout()
-	v.window.SetContent(content)
+	v.startTimeEntry.SetPlaceHolder(clock.Placeholder())
+	v.endTimeEntry.SetPlaceHolder(clock.Placeholder())
+	v.startTimeEntry.SetText(clock.FormatScheduleTime(schedule.StartTime))
+	v.endTimeEntry.SetText(clock.FormatScheduleTime(schedule.EndTime))
 }
 
 /**
- * startScheduleInfoUpdater - Inicia el actualizador automático de información de programación
+ * onLayoutModeChanged - Manejador del selector de tamaño de los controles
  *
- * @private
+ * @param {string} label - Opción seleccionada
+ * @callback - Evento del selector
  */
-func (v *NightLightView) startScheduleInfoUpdater() {
-	go func() {
-		ticker := time.NewTicker(30 * time.Second)
-		defer ticker.Stop()
-
-		for range ticker.C {
-			if v.controller.IsScheduleEnabled() {
-				v.updateScheduleInfo()
+func (v *NightLightView) onLayoutModeChanged(label string) {
+	for mode, text := range layoutModeLabels {
+		if text == label {
+			if err := v.controller.SetLayoutMode(mode); err != nil {
+				v.showErrorDialog("❌ Error de ajustes", err.Error())
+				return
 			}
+			v.applyLayoutMode()
+			return
 		}
-	}()
+	}
 }
 
-// =====================================================
-// SISTEMA DE DIÁLOGOS
-// =====================================================
+/**
+ * applyLayoutMode - Aplica el tema táctil o el de escritorio según la configuración
+ *
+ * En modo automático se usan controles grandes si Fyne indica un
+ * dispositivo móvil o sin teclado (convertibles en modo tableta).
+ * Cambiar el tema redibuja todos los widgets sin recrear la ventana.
+ *
+ * @private
+ */
+func (v *NightLightView) applyLayoutMode() {
+	touch := false
+	switch v.controller.GetLayoutMode() {
+	case models.LayoutModeTouch:
+		touch = true
+	case models.LayoutModeAuto:
+		device := fyne.CurrentDevice()
+		touch = device.IsMobile() || !device.HasKeyboard()
+	}
+
+	settings := fyne.CurrentApp().Settings()
+	if touch && v.desktopTheme == nil {
+		v.desktopTheme = settings.Theme()
+		settings.SetTheme(styles.NewTouchTheme(v.desktopTheme))
+	} else if !touch && v.desktopTheme != nil {
+		settings.SetTheme(v.desktopTheme)
+		v.desktopTheme = nil
+	}
+}
 
 /**
- * showSuccessDialog - Muestra un diálogo de éxito auto-cerrable
+ * onResetOnQuitToggled - Manejador del checkbox "Restaurar gamma al salir"
  *
- * Presenta un mensaje de confirmación que se cierra automáticamente
- * después de 2 segundos para no interrumpir el flujo de trabajo.
+ * @param {bool} enabled - Estado del checkbox
+ * @callback - Evento del checkbox
+ */
+func (v *NightLightView) onResetOnQuitToggled(enabled bool) {
+	if err := v.controller.SetResetOnQuit(enabled); err != nil {
+		v.showErrorDialog("❌ Error de ajustes", err.Error())
+	}
+}
+
+/**
+ * onLockResetToggled - Manejador del checkbox "Gamma normal con la sesión bloqueada"
  *
- * @param {string} message - Mensaje a mostrar al usuario
- * @example
- *   v.showSuccessDialog("✅ Configuración aplicada")
+ * @param {bool} enabled - Estado del checkbox
+ * @callback - Evento del checkbox
+ */
+func (v *NightLightView) onLockResetToggled(enabled bool) {
+	if err := v.controller.SetResetWhileLocked(enabled); err != nil {
+		v.showErrorDialog("❌ Error de ajustes", err.Error())
+	}
+}
+
+/**
+ * onIdlePauseToggled - Manejador del checkbox "Pausar los cambios automáticos con el equipo inactivo"
+ *
+ * @param {bool} enabled - Estado del checkbox
+ * @callback - Evento del checkbox
+ */
+func (v *NightLightView) onIdlePauseToggled(enabled bool) {
+	if err := v.controller.SetPauseWhileIdle(enabled); err != nil {
+		v.showErrorDialog("❌ Error de ajustes", err.Error())
+	}
+}
+
+/**
+ * onGamingToggled - Manejador del checkbox "Suspender con gamescope o Steam Big Picture"
+ *
+ * @param {bool} enabled - Estado del checkbox
+ * @callback - Evento del checkbox
+ */
+func (v *NightLightView) onGamingToggled(enabled bool) {
+	if err := v.controller.SetSuspendForGames(enabled); err != nil {
+		v.showErrorDialog("❌ Error de ajustes", err.Error())
+	}
+}
+
+/**
+ * onBatteryToggled - Manejador del checkbox "Más cálida y tenue con batería"
+ *
+ * @param {bool} enabled - Estado del checkbox
+ * @callback - Evento del checkbox
+ */
+func (v *NightLightView) onBatteryToggled(enabled bool) {
+	battery := v.controller.GetBattery()
+	battery.Enabled = enabled
+	if err := v.controller.SetBattery(battery); err != nil {
+		v.showErrorDialog("❌ Error de ajustes", err.Error())
+	}
+}
+
+/**
+ * onAmbientToggled - Manejador del checkbox "Adaptar a la luz de la habitación"
+ *
+ * @param {bool} enabled - Estado del checkbox
+ * @callback - Evento del checkbox
+ */
+func (v *NightLightView) onAmbientToggled(enabled bool) {
+	ambient := v.controller.GetAmbient()
+	ambient.Enabled = enabled
+	if err := v.controller.SetAmbient(ambient); err != nil {
+		v.showErrorDialog("❌ Error de ajustes", err.Error())
+	}
+}
+
+/**
+ * onTemperatureChanged - Manejador de cambios en la temperatura enlazada
+ *
+ * Se ejecuta cuando el usuario mueve el slider y cuando el controlador
+ * publica una temperatura nueva. Solo los cambios que aún no conoce el
+ * controlador (los del usuario) se ajustan a los presets y se guardan.
+ *
+ * @callback - Listener de v.temperature
+ */
+func (v *NightLightView) onTemperatureChanged() {
+	value, _ := v.temperature.Get()
+	if value != v.controller.GetConfig().Temperature {
+		// En pasos gruesos el slider se ajusta a los presets cercanos
+		if v.snapCheck.Checked && !v.isFineStepping() {
+			if snapped := models.SnapToPreset(value, v.controller.GetPresets()); snapped != value {
+				v.temperature.Set(snapped) // Vuelve a llamar a este manejador
+				return
+			}
+		}
+		v.controller.UpdateTemperature(value)
+	}
+
+	v.updateTemperatureText()
+	v.updatePresetText()
+	v.updateTemperatureSwatch()
+	v.updateGammaInfo()
+}
+
+// isFineStepping indica si el slider debe usar pasos finos (ajuste activado o Shift pulsado)
+func (v *NightLightView) isFineStepping() bool {
+	return v.fineStepsCheck.Checked || v.shiftHeld
+}
+
+// updateSliderStep aplica el paso fino o grueso al slider de temperatura
+func (v *NightLightView) updateSliderStep() {
+	if v.isFineStepping() {
+		v.temperatureSlider.Step = models.FineTemperatureStep
+	} else {
+		v.temperatureSlider.Step = models.CoarseTemperatureStep
+	}
+}
+
+/**
+ * setupStepModifier - Activa pasos finos mientras se mantiene pulsado Shift
+ *
+ * @private
+ */
+func (v *NightLightView) setupStepModifier() {
+	canvas, ok := v.window.Canvas().(desktop.Canvas)
+	if !ok {
+		return
+	}
+
+	isShift := func(key fyne.KeyName) bool {
+		return key == desktop.KeyShiftLeft || key == desktop.KeyShiftRight
+	}
+	canvas.SetOnKeyDown(func(event *fyne.KeyEvent) {
+		if isShift(event.Name) && !v.shiftHeld {
+			v.shiftHeld = true
+			v.updateSliderStep()
+		}
+	})
+	canvas.SetOnKeyUp(func(event *fyne.KeyEvent) {
+		if isShift(event.Name) && v.shiftHeld {
+			v.shiftHeld = false
+			v.updateSliderStep()
+		}
+	})
+}
+
+/**
+ * setupUndoShortcuts - Registra los atajos de deshacer y rehacer
+ *
+ * @private
+ */
+func (v *NightLightView) setupUndoShortcuts() {
+	canvas := v.window.Canvas()
+	canvas.AddShortcut(&desktop.CustomShortcut{KeyName: fyne.KeyZ, Modifier: fyne.KeyModifierShortcutDefault},
+		func(fyne.Shortcut) { v.onUndo() })
+	canvas.AddShortcut(&desktop.CustomShortcut{KeyName: fyne.KeyZ, Modifier: fyne.KeyModifierShortcutDefault | fyne.KeyModifierShift},
+		func(fyne.Shortcut) { v.onRedo() })
+	canvas.AddShortcut(&desktop.CustomShortcut{KeyName: fyne.KeyY, Modifier: fyne.KeyModifierShortcutDefault},
+		func(fyne.Shortcut) { v.onRedo() })
+}
+
+// onUndo deshace el último cambio aplicado (sin aviso si no hay nada que deshacer)
+func (v *NightLightView) onUndo() {
+	if !v.controller.CanUndo() {
+		return
+	}
+	if err := v.controller.Undo(); err != nil {
+		v.showErrorDialog("❌ Error al deshacer", err.Error())
+	}
+}
+
+// onRedo rehace el último cambio deshecho
+func (v *NightLightView) onRedo() {
+	if !v.controller.CanRedo() {
+		return
+	}
+	if err := v.controller.Redo(); err != nil {
+		v.showErrorDialog("❌ Error al rehacer", err.Error())
+	}
+}
+
+/**
+ * onSnapToggled - Manejador del checkbox "Ajustar a presets"
+ *
+ * @param {bool} enabled - Estado del checkbox
+ * @callback - Evento del checkbox
+ */
+func (v *NightLightView) onSnapToggled(enabled bool) {
+	if err := v.controller.SetSnapToPresets(enabled); err != nil {
+		v.showErrorDialog("❌ Error de ajustes", err.Error())
+	}
+}
+
+/**
+ * onFineStepsToggled - Manejador del checkbox "Pasos finos"
+ *
+ * @param {bool} enabled - Estado del checkbox
+ * @callback - Evento del checkbox
+ */
+func (v *NightLightView) onFineStepsToggled(enabled bool) {
+	v.updateSliderStep()
+	if err := v.controller.SetFineSteps(enabled); err != nil {
+		v.showErrorDialog("❌ Error de ajustes", err.Error())
+	}
+}
+
+/**
+ * onControllerEvent - Manejador de eventos del bus del controlador
+ *
+ * Sincroniza slider, labels y presets con cualquier cambio de estado,
+ * venga de la ventana, la bandeja, el programador o D-Bus. Se suscribe
+ * con onUIEvent, así que siempre se ejecuta en el hilo de la interfaz.
+ *
+ * @param {controllers.Event} event - Evento publicado por el controlador
+ * @callback - Suscripción al bus de eventos
  */
-func (v *NightLightView) showSuccessDialog(message string) {
-	info := dialog.NewInformation("✅ Éxito", message, v.window)
-	info.Show()
+func (v *NightLightView) onControllerEvent(event controllers.Event) {
+	switch event.Type {
+	case controllers.EventApplied, controllers.EventReset, controllers.EventApplyFailed, controllers.EventScheduleTransition:
+		v.updateDisplayStatus()
+	}
+	if event.Type == controllers.EventPresetsChanged {
+		v.refreshPresetButtons()
+		return
+	}
+	if event.Type == controllers.EventDisplaysChanged {
+		v.updateDisplayStatus()
+		if event.Source == "layout" {
+			v.refreshDisplayInfo(false) // Se conectó o desconectó un monitor
+		} else {
+			v.renderDisplayList()
+		}
+		return
+	}
+	if event.Type == controllers.EventApplyFailed {
+		return // La notificación de escritorio informa del fallo
+	}
+	if event.Type == controllers.EventMovieModeChanged {
+		v.updateMovieButton()
+		return
+	}
+	if event.Type == controllers.EventAccurateChanged {
+		if active, until := v.controller.GetColorAccurate(); active {
+			v.showToast("🎨 Color fiel hasta las " + v.controller.GetClock().FormatTime(until))
+		} else {
+			v.showToast("🎨 Fin del color fiel: filtro reaplicado")
+		}
+		return
+	}
+	if event.Type == controllers.EventVacationChanged || event.Type == controllers.EventSkipTonightChanged {
+		v.updateScheduleInfo()
+		return
+	}
+
+	// Mantener al día el historial si está a la vista
+	if v.tabs.Selected() != nil && v.tabs.Selected().Text == tabHistory {
+		v.refreshHistory()
+	}
+
+	// El slider y los labels siguen a los valores enlazados
+	v.temperature.Set(event.Temperature)
+	v.brightness.Set(v.controller.GetConfig().Brightness)
+}
+
+/**
+ * onApplyClicked - Manejador del botón Aplicar
+ *
+ * Aplica la temperatura actual al sistema usando el controlador.
+ * Muestra feedback visual del resultado (éxito o error).
+ *
+ * @callback - Evento del botón Aplicar
+ */
+func (v *NightLightView) onApplyClicked() {
+	v.runBusy(v.controller.ApplyNightLight, func(err error) {
+		if err != nil {
+			v.showApplyError("❌ Error al aplicar", err, v.onApplyClicked)
+			return
+		}
+
+		config := v.controller.GetConfig()
+		message := fmt.Sprintf("🌡️ Aplicada: %s", config.GetTemperatureString())
+		v.showToast(message)
+	})
+}
+
+/**
+ * runBusy - Ejecuta una aplicación fuera del hilo de la interfaz
+ *
+ * Por DDC/CI una aplicación puede tardar segundos: mientras dura se
+ * muestra el indicador de trabajo y se desactivan Aplicar, Reset y
+ * Toggle (un segundo clic no encola otra aplicación). done recibe el
+ * resultado en el hilo de la interfaz.
+ *
+ * @param {func() error} action - Llamada al controlador que aplica la gamma
+ * @param {func(error)} done - Muestra el resultado
+ * @private
+ */
+func (v *NightLightView) runBusy(action func() error, done func(error)) {
+	if v.busy {
+		return
+	}
+	v.setBusy(true)
 
-	// Auto-cerrar después de 2 segundos
 	go func() {
-		time.Sleep(2 * time.Second)
-		info.Hide()
+		err := action()
+		runOnUI(func() {
+			v.setBusy(false)
+			done(err)
+		})
 	}()
 }
 
+// setBusy muestra u oculta el indicador de trabajo y bloquea los botones de aplicación
+func (v *NightLightView) setBusy(busy bool) {
+	v.busy = busy
+	if !busy {
+		v.updateDisplayStatus() // Los fallos manuales no publican ningún evento
+	}
+	for _, button := range []*widget.Button{v.applyButton, v.resetButton, v.toggleButton} {
+		if busy {
+			button.Disable()
+		} else {
+			button.Enable()
+		}
+	}
+	if busy {
+		v.busyBar.Show()
+		v.busyBar.Start()
+	} else {
+		v.busyBar.Stop()
+		v.busyBar.Hide()
+	}
+}
+
+/**
+ * onResetClicked - Manejador del botón Reset
+ *
+ * Resetea la configuración a valores normales (6500K) y actualiza
+ * tanto el sistema como la interfaz.
+ *
+ * @callback - Evento del botón Reset
+ */
+func (v *NightLightView) onResetClicked() {
+	v.runBusy(v.controller.ResetNightLight, func(err error) {
+		if err != nil {
+			v.showErrorDialog("❌ Error al resetear", err.Error())
+			return
+		}
+
+		v.showToast("✅ Gamma reseteada a valores normales")
+	})
+}
+
+/**
+ * onScheduleToggled - Manejador del checkbox de programación automática
+ *
+ * @param {bool} enabled - Estado del checkbox
+ * @callback - Evento del checkbox
+ */
+func (v *NightLightView) onScheduleToggled(enabled bool) {
+	if !v.syncingSchedule {
+		v.controller.EnableSchedule(enabled)
+		v.darkModeCheck.SetChecked(v.controller.IsFollowDarkMode())
+	}
+	if v.scheduleConfig != nil {
+		if enabled {
+			v.scheduleConfig.Show()
+		} else {
+			v.scheduleConfig.Hide()
+		}
+	}
+	v.updateScheduleInfo()
+}
+
+/**
+ * onDarkModeToggled - Manejador del checkbox "Noche con el tema oscuro del escritorio"
+ *
+ * Activarlo desactiva la programación automática, así que se sincronizan
+ * sus controles.
+ *
+ * @param {bool} enabled - Estado del checkbox
+ * @callback - Evento del checkbox
+ */
+func (v *NightLightView) onDarkModeToggled(enabled bool) {
+	if err := v.controller.SetFollowDarkMode(enabled); err != nil {
+		v.showErrorDialog("❌ Error de ajustes", err.Error())
+	}
+	v.syncScheduleControls()
+}
+
+/**
+ * onTabSelected - Manejador del cambio de pestaña
+ *
+ * @param {*container.TabItem} tab - Pestaña seleccionada
+ * @callback - Evento de las pestañas
+ */
+func (v *NightLightView) onTabSelected(tab *container.TabItem) {
+	if tab.Text == tabHistory {
+		v.refreshHistory()
+	}
+
+	if err := v.controller.SaveWindowTab(tab.Text); err != nil {
+		logging.Printf("⚠️  No se pudo guardar la pestaña seleccionada: %v\n", err)
+	}
+}
+
+/**
+ * onScheduleTimeChanged - Manejador de cambios en entradas de tiempo
+ *
+ * @param {string} text - Nuevo texto en la entrada
+ * @callback - Evento de cambio en entradas de tiempo
+ */
+func (v *NightLightView) onScheduleTimeChanged(text string) {
+	if v.syncingSchedule || !v.controller.IsScheduleEnabled() {
+		return
+	}
+
+	v.updateScheduleConfiguration()
+}
+
+/**
+ * onScheduleTempChanged - Manejador de cambios en sliders de temperatura
+ *
+ * @param {float64} value - Nuevo valor del slider
+ * @callback - Evento de cambio en sliders
+ */
+func (v *NightLightView) onScheduleTempChanged(value float64) {
+	if v.syncingSchedule || !v.controller.IsScheduleEnabled() {
+		return
+	}
+
+	v.updateScheduleConfiguration()
+	v.updateScheduleLabels()
+}
+
+/**
+ * onScheduleTemplateSelected - Manejador del selector de plantillas de programación
+ *
+ * Rellena horarios, temperaturas y modo con la plantilla y la vuelca
+ * en los controles existentes, sin recrear la ventana.
+ *
+ * @param {string} label - Plantilla seleccionada
+ * @callback - Evento del selector
+ */
+func (v *NightLightView) onScheduleTemplateSelected(label string) {
+	for _, template := range models.ScheduleTemplates {
+		if template.Label() != label {
+			continue
+		}
+		if err := v.controller.ApplyScheduleTemplate(template.ID); err != nil {
+			v.templateSel.ClearSelected()
+			v.showErrorDialog("❌ Error de programación", err.Error())
+			return
+		}
+		logging.Printf("📋 Plantilla de programación aplicada: %s\n", template.Name)
+		v.syncScheduleControls()
+		return
+	}
+}
+
+/**
+ * onSolarModeToggled - Manejador del checkbox de modo solar
+ *
+ * @param {bool} enabled - true para seguir la elevación del sol
+ * @callback - Evento del checkbox
+ */
+func (v *NightLightView) onSolarModeToggled(enabled bool) {
+	if v.syncingSchedule {
+		return
+	}
+	mode := models.ScheduleModeFixed
+	if enabled {
+		mode = models.ScheduleModeSolar
+	}
+
+	if err := v.controller.SetScheduleMode(mode); err != nil {
+		v.showErrorDialog("❌ Error de programación", err.Error())
+		return
+	}
+	v.updateScheduleInfo()
+}
+
+/**
+ * onLocationChanged - Manejador de cambios en latitud/longitud
+ *
+ * Solo guarda la ubicación cuando ambos campos contienen números válidos.
+ *
+ * @param {string} text - Nuevo texto en la entrada
+ * @callback - Evento de cambio en entradas de ubicación
+ */
+func (v *NightLightView) onLocationChanged(text string) {
+	if v.syncingSchedule {
+		return
+	}
+	var latitude, longitude float64
+	if _, err := fmt.Sscanf(v.latitudeEntry.Text, "%g", &latitude); err != nil {
+		return
+	}
+	if _, err := fmt.Sscanf(v.longitudeEntry.Text, "%g", &longitude); err != nil {
+		return
+	}
+
+	if err := v.controller.UpdateLocation(latitude, longitude); err != nil {
+		return
+	}
+	v.updateScheduleInfo()
+}
+
+/**
+ * onWeatherToggled - Manejador del checkbox "Más cálida en días nublados"
+ *
+ * @param {bool} enabled - Estado del checkbox
+ * @callback - Evento del checkbox
+ */
+func (v *NightLightView) onWeatherToggled(enabled bool) {
+	config := v.controller.GetWeather()
+	config.Enabled = enabled
+	if err := v.controller.SetWeather(config); err != nil {
+		v.showErrorDialog("❌ Error de ajustes", err.Error())
+	}
+}
+
+/**
+ * syncScheduleControls - Vuelca la programación guardada en los controles existentes
+ *
+ * Se usa cuando la programación cambia fuera de los controles (plantillas,
+ * horario importado del escritorio). Los widgets se actualizan en su
+ * sitio, así que se conservan el foco, la pestaña y el desplazamiento;
+ * mientras tanto los manejadores no vuelven a guardar los valores.
+ *
+ * @private
+ */
+func (v *NightLightView) syncScheduleControls() {
+	schedule := v.controller.GetScheduleConfig()
+
+	v.syncingSchedule = true
+	defer func() { v.syncingSchedule = false }()
+
+	v.scheduleCheck.SetChecked(v.controller.IsScheduleEnabled())
+	v.darkModeCheck.SetChecked(v.controller.IsFollowDarkMode())
+	v.updateScheduleTimeEntries()
+	v.nightTempSlider.SetValue(schedule.NightTemp)
+	v.dayTempSlider.SetValue(schedule.DayTemp)
+	v.transitionSlider.SetValue(float64(schedule.TransitionTime))
+	v.updateScheduleLabels()
+	v.solarCheck.SetChecked(schedule.Mode == models.ScheduleModeSolar)
+	if schedule.Location.IsSet() {
+		v.latitudeEntry.SetText(fmt.Sprintf("%.4f", schedule.Location.Latitude))
+		v.longitudeEntry.SetText(fmt.Sprintf("%.4f", schedule.Location.Longitude))
+	}
+	v.scheduleError.Hide()
+	v.updateScheduleInfo()
+}
+
+/**
+ * updateScheduleConfiguration - Actualiza la configuración de horarios
+ *
+ * @private
+ */
+func (v *NightLightView) updateScheduleConfiguration() {
+	// Obtener valores actuales de la UI
+	startTime := v.startTimeEntry.Text
+	endTime := v.endTimeEntry.Text
+	nightTemp := v.nightTempSlider.Value
+	dayTemp := v.dayTempSlider.Value
+	transitionTime := int(v.transitionSlider.Value)
+
+	// Actualizar configuración; si hay valores inválidos no se guarda nada
+	if err := v.controller.UpdateScheduleConfig(startTime, endTime, nightTemp, dayTemp, transitionTime); err != nil {
+		v.scheduleError.SetText("⚠️ " + err.Error() + " — cambios no guardados")
+		v.scheduleError.Show()
+		return
+	}
+	v.scheduleError.Hide()
+
+	// Actualizar información
+	v.updateScheduleInfo()
+}
+
+/**
+ * validateScheduleTime - Validador de las entradas de hora ("HH:MM" o "h:mm p. m.")
+ *
+ * @param {string} text - Texto de la entrada
+ * @returns {error} Error si la hora no es válida
+ * @private
+ */
+func validateScheduleTime(text string) error {
+	_, _, err := models.ParseScheduleTime(text)
+	return err
+}
+
+/**
+ * onToggleClicked - Manejador del botón Toggle
+ *
+ * Alterna entre activar y desactivar la luz nocturna.
+ * Si está activa la desactiva, si está inactiva la activa.
+ *
+ * @callback - Evento del botón Toggle
+ */
+func (v *NightLightView) onToggleClicked() {
+	v.runBusy(v.controller.ToggleNightLight, func(err error) {
+		if err != nil {
+			v.showApplyError("❌ Error al cambiar estado", err, v.onToggleClicked)
+			return
+		}
+
+		config := v.controller.GetConfig()
+		var message string
+		if config.IsActive {
+			message = "🔥 Luz nocturna activada"
+		} else {
+			message = "❄️ Luz nocturna desactivada"
+		}
+
+		v.showToast(message)
+	})
+}
+
+/**
+ * onVacationSaved - Guarda las fechas del modo vacaciones
+ *
+ * @callback - Evento del botón "Guardar fechas"
+ */
+func (v *NightLightView) onVacationSaved() {
+	vacation := models.VacationConfig{
+		From: strings.TrimSpace(v.vacationFrom.Text),
+		To:   strings.TrimSpace(v.vacationTo.Text),
+	}
+	if err := v.controller.SetVacation(vacation); err != nil {
+		v.showErrorDialog("❌ Error de vacaciones", err.Error())
+	}
+}
+
+/**
+ * onVacationCleared - Quita el período de vacaciones y reanuda la programación
+ *
+ * @callback - Evento del botón "Quitar"
+ */
+func (v *NightLightView) onVacationCleared() {
+	v.vacationFrom.SetText("")
+	v.vacationTo.SetText("")
+	if err := v.controller.SetVacation(models.VacationConfig{}); err != nil {
+		v.showErrorDialog("❌ Error de vacaciones", err.Error())
+	}
+}
+
+/**
+ * onMovieClicked - Manejador del botón del modo película
+ *
+ * Retira el filtro sin olvidar lo que estaba activo; un segundo clic (o
+ * el final de la duración configurada) lo vuelve a aplicar.
+ *
+ * @callback - Evento del botón de modo película
+ */
+func (v *NightLightView) onMovieClicked() {
+	v.controller.ToggleMovieMode()
+}
+
+// updateMovieButton muestra en el botón si el modo película está activo y hasta cuándo
+func (v *NightLightView) updateMovieButton() {
+	if active, until := v.controller.GetMovieMode(); active {
+		v.movieButton.SetText("🎬 Fin película (" + v.controller.GetClock().FormatTime(until) + ")")
+		return
+	}
+	v.movieButton.SetText("🎬 Modo película")
+}
+
+// =====================================================
+// MÉTODOS DE ACTUALIZACIÓN DE UI
+// =====================================================
+
+// updateTemperatureText muestra la temperatura enlazada en la unidad preferida
+func (v *NightLightView) updateTemperatureText() {
+	temp, _ := v.temperature.Get()
+	brightness, _ := v.brightness.Get()
+	format := v.controller.GetTemperatureFormat()
+	v.temperatureText.Set(fmt.Sprintf("🌡️ %s: %s · %s", format.Name(), format.Format(temp), blueLightText(temp, brightness)))
+}
+
+// blueLightText describe cuánta luz azul retira un estado ("🔵 46% menos luz azul")
+func blueLightText(temperature, brightness float64) string {
+	return fmt.Sprintf("🔵 %.0f%% menos luz azul", system.BlueLightReduction(temperature, brightness))
+}
+
+/**
+ * updateGammaInfo - Muestra los valores que recibiría el backend con el slider actual
+ *
+ * Los factores r:g:b (como en "xrandr --gamma"), la atenuación que va
+ * aparte en X11 y la temperatura en mired, para quien quiera comprobar
+ * qué se aplica o adjuntarlo a un informe de error.
+ *
+ * @private
+ */
+func (v *NightLightView) updateGammaInfo() {
+	if v.gammaInfo == nil {
+		return
+	}
+	temp, _ := v.temperature.Get()
+	brightness, _ := v.brightness.Get()
+	gamma, dim := system.BackendGamma(v.controller.GetProtocol(), temp, brightness)
+
+	text := fmt.Sprintf("🧮 Gamma r:g:b %.3f:%.3f:%.3f · %.0f mired", gamma[0], gamma[1], gamma[2], 1e6/temp)
+	if dim < 1.0 {
+		text += fmt.Sprintf(" · brillo %.2f", dim)
+	}
+	v.gammaInfo.SetText(text)
+}
+
+/**
+ * updatePresetText - Actualiza el texto del preset a partir de los valores enlazados
+ *
+ * El label de temperatura lo actualiza updateTemperatureText; el del
+ * preset depende de la temperatura y del brillo, así que lo recalcula
+ * este listener.
+ *
+ * @private
+ */
+func (v *NightLightView) updatePresetText() {
+	temp, _ := v.temperature.Get()
+	brightness, _ := v.brightness.Get()
+	state := models.NightLightConfig{Temperature: temp, Brightness: brightness}
+
+	if state.IsBoosted() {
+		v.presetText.Set(fmt.Sprintf("🔆 Boost de brillo: %.0f%%", brightness*100))
+		return
+	}
+	v.presetText.Set("✨ " + models.Presets.GetPresetName(temp))
+}
+
+/**
+ * updateDisplayStatus - Actualiza la franja de estado de los displays
+ *
+ * Muestra cada display con el resultado de la última aplicación: ✅ con
+ * el backend usado, ❌ con el motivo, ⏳ si aún no se aplicó nada y ⏸️
+ * si está excluido. Se refresca tras cada aplicación y al detectar
+ * displays de nuevo.
+ *
+ * @private
+ */
+func (v *NightLightView) updateDisplayStatus() {
+	var parts []string
+	for _, status := range v.controller.GetDisplayStatus() {
+		switch {
+		case !status.Included:
+			parts = append(parts, "⏸️ "+status.Display+" (excluido)")
+		case status.Pending:
+			parts = append(parts, "⏳ "+status.Display)
+		case status.Applied:
+			parts = append(parts, fmt.Sprintf("✅ %s · %s", status.Display, status.Backend))
+		default:
+			parts = append(parts, fmt.Sprintf("❌ %s: %s", status.Display, status.Error))
+		}
+	}
+	if len(parts) == 0 {
+		parts = append(parts, "📺 No se detectó ningún display")
+	}
+	v.displayStatus.SetText(strings.Join(parts, "    "))
+
+	if hdr := v.controller.GetHDRDisplays(); len(hdr) > 0 {
+		v.hdrInfo.SetText(fmt.Sprintf("🌈 HDR en %v: solo luz nocturna del compositor", hdr))
+		v.hdrInfo.Show()
+	} else {
+		v.hdrInfo.Hide()
+	}
+}
+
+/**
+ * updateScheduleInfo - Actualiza la información de programación automática
+ *
+ * @private
+ */
+func (v *NightLightView) updateScheduleInfo() {
+	if !v.controller.IsScheduleEnabled() {
+		v.scheduleInfo.SetText("Programación deshabilitada")
+		return
+	}
+
+	if end, paused := v.controller.IsOnVacation(); paused {
+		v.scheduleInfo.Importance = widget.WarningImportance
+		v.scheduleInfo.SetText(vacationText(end))
+		return
+	}
+	v.scheduleInfo.Importance = widget.MediumImportance
+
+	description, temp, duration := v.controller.GetNextScheduleChange()
+
+	if duration > 0 {
+		at := v.controller.GetClock().FormatTime(time.Now().Add(duration))
+		v.scheduleInfo.SetText(fmt.Sprintf("🔔 %s a las %s (en %s, %s)",
+			description, at, formatCountdown(duration), v.controller.GetTemperatureFormat().Format(temp)))
+	} else {
+		v.scheduleInfo.SetText("🔔 " + description)
+	}
+}
+
+/**
+ * updateScheduleLabels - Actualiza los labels de los sliders de programación
+ *
+ * @private
+ */
+func (v *NightLightView) updateScheduleLabels() {
+	format := v.controller.GetTemperatureFormat()
+	v.nightTempLabel.SetText(fmt.Sprintf("🌙 %s nocturna: %s", format.Name(), format.Format(v.nightTempSlider.Value)))
+	v.dayTempLabel.SetText(fmt.Sprintf("☀️ %s diurna: %s", format.Name(), format.Format(v.dayTempSlider.Value)))
+	v.transitionLabel.SetText(fmt.Sprintf("⏱️ Transición: %.0f min", v.transitionSlider.Value))
+}
+
+/**
+ * startScheduleInfoUpdater - Inicia el actualizador automático de información de programación
+ *
+ * @private
+ */
+func (v *NightLightView) startScheduleInfoUpdater() {
+	everyOnUI(30*time.Second, func() {
+		if v.controller.IsScheduleEnabled() {
+			v.updateScheduleInfo()
+		}
+	})
+}
+
+// =====================================================
+// SISTEMA DE DIÁLOGOS
+// =====================================================
+
+/**
+ * showToast - Muestra un mensaje breve en la barra de estado
+ *
+ * Confirma acciones sin abrir un diálogo: no es modal, no roba el foco
+ * y se borra solo tras toastDuration. Un mensaje nuevo sustituye al
+ * anterior y reinicia la cuenta.
+ *
+ * @param {string} message - Mensaje a mostrar al usuario
+ * @example
+ *   v.showToast("✅ Configuración aplicada")
+ */
+func (v *NightLightView) showToast(message string) {
+	v.toastSeq++
+	seq := v.toastSeq
+	v.statusBar.SetText(message)
+
+	afterOnUI(toastDuration, func() {
+		if v.toastSeq == seq {
+			v.statusBar.SetText("")
+		}
+	})
+}
+
 /**
  * showErrorDialog - Muestra un diálogo de error
  *
@@ -0,0 +1,206 @@
+package views
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"luznocturna/luz-nocturna/internal/models"
+)
+
+/**
+ * showPresetManager - Muestra el diálogo de gestión de presets
+ *
+ * Lista los presets definidos por el usuario y permite agregarlos,
+ * editarlos (renombrar, cambiar temperatura/brillo/contraste) y eliminarlos.
+ * Los cambios se guardan en la configuración inmediatamente.
+ *
+ * @private
+ */
+func (v *NightLightView) showPresetManager() {
+	presets := v.controller.GetPresets()
+	selected := -1
+
+	list := widget.NewList(
+		func() int { return len(presets) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			label := presets[id].Label()
+			if presets[id].HasBrightness() {
+				label += fmt.Sprintf(" · %.0f%%", presets[id].Brightness*100)
+			}
+			if presets[id].HasContrast() {
+				label += fmt.Sprintf(" · contraste +%.0f%%", presets[id].Contrast*100)
+			}
+			item.(*widget.Label).SetText(label)
+		},
+	)
+	list.OnSelected = func(id widget.ListItemID) { selected = id }
+	list.OnUnselected = func(widget.ListItemID) { selected = -1 }
+
+	reload := func() {
+		presets = v.controller.GetPresets()
+		selected = -1
+		list.UnselectAll()
+		list.Refresh()
+	}
+
+	addButton := widget.NewButton("➕ Agregar", func() {
+		v.showPresetForm("Nuevo preset", models.Preset{Icon: "🌙", Temperature: v.controller.GetConfig().Temperature},
+			func(preset models.Preset) error {
+				if err := v.controller.AddPreset(preset); err != nil {
+					return err
+				}
+				reload()
+				return nil
+			})
+	})
+
+	editButton := widget.NewButton("✏️ Editar", func() {
+		if selected < 0 {
+			return
+		}
+		index := selected
+		v.showPresetForm("Editar preset", presets[index], func(preset models.Preset) error {
+			if err := v.controller.UpdatePreset(index, preset); err != nil {
+				return err
+			}
+			reload()
+			return nil
+		})
+	})
+
+	deleteButton := widget.NewButton("🗑️ Eliminar", func() {
+		if selected < 0 {
+			return
+		}
+		index := selected
+		dialog.ShowConfirm("Eliminar preset",
+			fmt.Sprintf("¿Eliminar el preset %q?", presets[index].Name),
+			func(ok bool) {
+				if !ok {
+					return
+				}
+				if err := v.controller.DeletePreset(index); err != nil {
+					v.showErrorDialog("❌ Error de preset", err.Error())
+					return
+				}
+				reload()
+			}, v.window)
+	})
+
+	content := container.NewBorder(nil,
+		container.NewGridWithColumns(3, addButton, editButton, deleteButton),
+		nil, nil, list)
+
+	manager := dialog.NewCustom("🎨 Presets", "Cerrar", content, v.window)
+	manager.Resize(fyne.NewSize(360, 360))
+	manager.Show()
+}
+
+/**
+ * showPresetForm - Formulario para crear o editar un preset
+ *
+ * @param {string} title - Título del formulario
+ * @param {models.Preset} initial - Valores iniciales
+ * @param {func(models.Preset) error} onSave - Guarda el preset; si devuelve error se muestra
+ * @private
+ */
+func (v *NightLightView) showPresetForm(title string, initial models.Preset, onSave func(models.Preset) error) {
+	minTemp, maxTemp := v.controller.GetTemperatureRange()
+
+	nameEntry := widget.NewEntry()
+	nameEntry.SetText(initial.Name)
+	nameEntry.Validator = func(text string) error {
+		if strings.TrimSpace(text) == "" {
+			return fmt.Errorf("nombre obligatorio")
+		}
+		return nil
+	}
+
+	iconEntry := widget.NewEntry()
+	iconEntry.SetText(initial.Icon)
+
+	tempEntry := widget.NewEntry()
+	tempEntry.SetText(fmt.Sprintf("%.0f", initial.Temperature))
+	tempEntry.Validator = func(text string) error {
+		temp, err := strconv.ParseFloat(strings.TrimSpace(text), 64)
+		if err != nil || temp < minTemp || temp > maxTemp {
+			return fmt.Errorf("entre %.0f y %.0f", minTemp, maxTemp)
+		}
+		return nil
+	}
+
+	brightnessEntry := widget.NewEntry()
+	brightnessEntry.SetPlaceHolder(fmt.Sprintf("opcional, %.0f-%.0f (más de 100 = boost)", models.MinBrightness*100, models.MaxBrightness*100))
+	if initial.HasBrightness() {
+		brightnessEntry.SetText(fmt.Sprintf("%.0f", initial.Brightness*100))
+	}
+
+	contrastEntry := widget.NewEntry()
+	contrastEntry.SetPlaceHolder(fmt.Sprintf("opcional, 0-%.0f (solo X11)", models.MaxContrast*100))
+	if initial.HasContrast() {
+		contrastEntry.SetText(fmt.Sprintf("%.0f", initial.Contrast*100))
+	}
+
+	keyEntry := widget.NewEntry()
+	keyEntry.SetPlaceHolder("opcional: letra, dígito o F2-F12")
+	keyEntry.SetText(initial.Key)
+	keyEntry.Validator = func(text string) error {
+		_, err := models.NormalizePresetKey(text)
+		return err
+	}
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Nombre", nameEntry),
+		widget.NewFormItem("Icono", iconEntry),
+		widget.NewFormItem("Temperatura (K)", tempEntry),
+		widget.NewFormItem("Brillo (%)", brightnessEntry),
+		widget.NewFormItem("Contraste (%)", contrastEntry),
+		widget.NewFormItem("Tecla", keyEntry),
+	}
+
+	dialog.ShowForm(title, "Guardar", "Cancelar", items, func(ok bool) {
+		if !ok {
+			return
+		}
+
+		temp, _ := strconv.ParseFloat(strings.TrimSpace(tempEntry.Text), 64)
+		preset := models.Preset{
+			Name:        strings.TrimSpace(nameEntry.Text),
+			Icon:        strings.TrimSpace(iconEntry.Text),
+			Temperature: temp,
+		}
+		if text := strings.TrimSpace(brightnessEntry.Text); text != "" {
+			percent, err := strconv.ParseFloat(text, 64)
+			if err != nil {
+				v.showErrorDialog("❌ Error de preset", "brillo inválido: "+text)
+				return
+			}
+			preset.Brightness = percent / 100
+		}
+		if text := strings.TrimSpace(contrastEntry.Text); text != "" {
+			percent, err := strconv.ParseFloat(text, 64)
+			if err != nil {
+				v.showErrorDialog("❌ Error de preset", "contraste inválido: "+text)
+				return
+			}
+			preset.Contrast = percent / 100
+		}
+		key, err := models.NormalizePresetKey(keyEntry.Text)
+		if err != nil {
+			v.showErrorDialog("❌ Error de preset", err.Error())
+			return
+		}
+		preset.Key = key
+
+		if err := onSave(preset); err != nil {
+			v.showErrorDialog("❌ Error de preset", err.Error())
+		}
+	}, v.window)
+}
@@ -0,0 +1,117 @@
+package views
+
+import (
+	"errors"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"luznocturna/luz-nocturna/internal/system"
+)
+
+/**
+ * showApplyError - Muestra el error de una aplicación de gamma
+ *
+ * Si fallaron todos los métodos de Wayland se muestra el diálogo de
+ * ayuda con el compositor, el motivo de cada método y qué instalar o
+ * activar. Si falta una herramienta se indica el paquete de esta
+ * distribución. El resto de errores usan el diálogo de error normal.
+ *
+ * @param {string} title - Título del diálogo
+ * @param {error} err - Error devuelto por el controlador
+ * @param {func()} retry - Acción del botón Reintentar (repite la operación)
+ * @private
+ */
+func (v *NightLightView) showApplyError(title string, err error, retry func()) {
+	var missingErr *system.MissingToolError
+	if errors.As(err, &missingErr) {
+		v.showMissingToolDialog(title, missingErr, retry)
+		return
+	}
+
+	var waylandErr *system.WaylandApplyError
+	if !errors.As(err, &waylandErr) {
+		v.showErrorDialog(title, err.Error())
+		return
+	}
+
+	attempts := container.NewVBox()
+	for _, attempt := range waylandErr.Attempts {
+		attempts.Add(widget.NewLabel("• " + attempt.Method + ": " + attempt.Reason))
+	}
+
+	suggestions := container.NewVBox()
+	for _, suggestion := range waylandErr.Suggestions {
+		label := widget.NewLabel("→ " + suggestion)
+		label.Wrapping = fyne.TextWrapWord
+		suggestions.Add(label)
+	}
+
+	details := container.NewVBox(
+		widget.NewLabelWithStyle("Compositor detectado: "+waylandErr.Compositor, fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		widget.NewSeparator(),
+		widget.NewLabel("Métodos intentados:"),
+		attempts,
+		widget.NewSeparator(),
+		widget.NewLabel("Cómo solucionarlo:"),
+		suggestions,
+	)
+	if waylandErr.Install != "" {
+		details.Add(v.installCommandRow(waylandErr.Install))
+	}
+	content := container.NewVScroll(details)
+
+	remediation := dialog.NewCustomConfirm(title, "🔄 Reintentar", "Cerrar", content, func(again bool) {
+		if again {
+			retry()
+		}
+	}, v.window)
+	remediation.Resize(fyne.NewSize(480, 420))
+	remediation.Show()
+}
+
+/**
+ * showMissingToolDialog - Explica qué herramienta falta y cómo instalarla
+ *
+ * @param {string} title - Título del diálogo
+ * @param {*system.MissingToolError} missingErr - Herramientas ausentes y comando de instalación
+ * @param {func()} retry - Acción del botón Reintentar (después de instalar)
+ * @private
+ */
+func (v *NightLightView) showMissingToolDialog(title string, missingErr *system.MissingToolError, retry func()) {
+	message := widget.NewLabel("Para aplicar el filtro falta: " + strings.Join(missingErr.Tools, ", "))
+	message.Wrapping = fyne.TextWrapWord
+
+	content := container.NewVBox(message)
+	if missingErr.InstallCommand != "" {
+		content.Add(widget.NewLabel("Instálalo con:"))
+		content.Add(v.installCommandRow(missingErr.InstallCommand))
+	} else {
+		content.Add(widget.NewLabel("Instálalo con el gestor de paquetes de tu distribución."))
+	}
+
+	missing := dialog.NewCustomConfirm(title, "🔄 Reintentar", "Cerrar", content, func(again bool) {
+		if again {
+			v.controller.RefreshDisplays() // Detectar la herramienta recién instalada
+			retry()
+		}
+	}, v.window)
+	missing.Resize(fyne.NewSize(480, 0))
+	missing.Show()
+}
+
+// installCommandRow muestra un comando de instalación con un botón para copiarlo
+func (v *NightLightView) installCommandRow(command string) fyne.CanvasObject {
+	label := widget.NewLabelWithStyle(command, fyne.TextAlignLeading, fyne.TextStyle{Monospace: true})
+	label.Wrapping = fyne.TextWrapBreak
+
+	copyButton := widget.NewButton("📋 Copiar", nil)
+	copyButton.OnTapped = func() {
+		v.window.Clipboard().SetContent(command)
+		copyButton.SetText("✅ Copiado")
+	}
+	return container.NewBorder(nil, nil, nil, copyButton, label)
+}
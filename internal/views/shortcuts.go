@@ -0,0 +1,39 @@
+package views
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver/desktop"
+
+	"luznocturna/luz-nocturna/internal/models"
+)
+
+/**
+ * setupKeyShortcuts - Registra las teclas sin modificador de la ventana
+ *
+ * F1 abre la ayuda, Espacio activa o desactiva el filtro y las teclas de
+ * los presets (1-4 o las asignadas en el gestor) los seleccionan igual que
+ * sus botones. Fyne solo entrega estas teclas al canvas cuando ningún
+ * widget tiene el foco, así que no interfieren al escribir en un campo.
+ * Ctrl+Shift+C alterna el modo de color fiel desde cualquier sitio.
+ *
+ * @private
+ */
+func (v *NightLightView) setupKeyShortcuts() {
+	v.window.Canvas().SetOnTypedKey(v.onTypedKey)
+	v.window.Canvas().AddShortcut(&desktop.CustomShortcut{KeyName: fyne.KeyC, Modifier: fyne.KeyModifierShortcutDefault | fyne.KeyModifierShift},
+		func(fyne.Shortcut) { v.controller.ToggleColorAccurate() })
+}
+
+// onTypedKey atiende una tecla pulsada sin ningún widget enfocado
+func (v *NightLightView) onTypedKey(event *fyne.KeyEvent) {
+	switch event.Name {
+	case fyne.KeyF1:
+		v.showHelpOverlay()
+	case fyne.KeySpace:
+		v.onToggleClicked()
+	default:
+		if index, ok := models.PresetKeyMap(v.controller.GetPresets())[string(event.Name)]; ok {
+			v.selectPreset(index)
+		}
+	}
+}
@@ -2,79 +2,405 @@ package views
 
 import (
 	"fmt"
+	"time"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/driver/desktop"
+
 	"luznocturna/luz-nocturna/internal/controllers"
-	"luznocturna/luz-nocturna/internal/models"
+	"luznocturna/luz-nocturna/internal/ipc"
+	"luznocturna/luz-nocturna/internal/logging"
+	"luznocturna/luz-nocturna/internal/system"
 )
 
+// trayScrollStep son los Kelvin que cambia cada muesca de la rueda sobre el icono
+const trayScrollStep = 100.0
+
 // SystrayManager - Manejador del icono de bandeja del sistema
 type SystrayManager struct {
 	controller *controllers.NightLightController
 	mainView   *NightLightView
 	app        fyne.App
+	sni        *ipc.StatusNotifierItem // Bandeja propia (atiende la rueda y el clic central)
+	available  bool
+
+	colorScheme  system.ColorScheme         // Tema del escritorio para el icono automático
+	themeWatcher *system.ColorSchemeWatcher // Sigue los cambios de tema claro/oscuro
+	badge        string                     // Temperatura dibujada en el icono ("" = sin insignia)
+
+	menu           *fyne.Menu
+	nextChangeItem *fyne.MenuItem // Entrada informativa con el próximo cambio programado
+	skipItem       *fyne.MenuItem // Omitir o reanudar la noche de hoy
+	refreshing     bool
+}
+
+// IsTrayAvailable indica si algún panel del escritorio puede mostrar el icono
+func IsTrayAvailable() bool {
+	return ipc.StatusNotifierWatcherAvailable()
 }
 
 // NewSystrayManager - Constructor del manejador de bandeja
 func NewSystrayManager(app fyne.App, controller *controllers.NightLightController, mainView *NightLightView) *SystrayManager {
-	return &SystrayManager{
+	manager := &SystrayManager{
 		app:        app,
 		controller: controller,
 		mainView:   mainView,
 	}
+
+	// Regenerar el menú cuando cambian los presets, los displays incluidos o una pausa temporal
+	controller.Subscribe(onUIEvent(func(event controllers.Event) {
+		switch event.Type {
+		case controllers.EventPresetsChanged, controllers.EventDisplaysChanged, controllers.EventMovieModeChanged,
+			controllers.EventAccurateChanged, controllers.EventSkipTonightChanged, controllers.EventVacationChanged:
+			manager.CreateMenu()
+		case controllers.EventApplied, controllers.EventReset, controllers.EventScheduleTransition:
+			manager.refreshBadge()
+			manager.refreshToolTip()
+		}
+	}))
+
+	return manager
 }
 
 // CreateMenu - Crea y configura el menú de la bandeja del sistema
+//
+// Publica un StatusNotifierItem propio, que además del clic atiende la
+// rueda (±100K) y el clic central (alternar) como un applet de volumen;
+// la bandeja de Fyne no entrega esos eventos y queda como respaldo. Si
+// ningún panel acepta iconos lo informa en lugar de no hacer nada.
 func (s *SystrayManager) CreateMenu() {
-	if desk, ok := s.app.(desktop.App); ok {
-		// 1. Crear el submenú de presets
-		presetsSubMenu := fyne.NewMenu("Presets", // El título aquí es para la estructura interna
-			fyne.NewMenuItem(fmt.Sprintf("🔥 Cálido (%.0fK)", models.CandleLightTemp), func() {
-				s.applyTemperaturePreset(int(models.CandleLightTemp), "Cálido")
-			}),
-			fyne.NewMenuItem(fmt.Sprintf("🌅 Medio (%.0fK)", models.NeutralWhiteTemp), func() {
-				s.applyTemperaturePreset(int(models.NeutralWhiteTemp), "Medio")
-			}),
-			fyne.NewMenuItem(fmt.Sprintf("☀️ Frío (%.0fK)", models.CoolWhiteTemp), func() {
-				s.applyTemperaturePreset(int(models.CoolWhiteTemp), "Neutral")
-			}),
-			fyne.NewMenuItem(fmt.Sprintf("💡 Día (%.0fK)", models.DaylightTemp), func() {
-				s.applyTemperaturePreset(int(models.DaylightTemp), "Día")
-			}),
-		)
-
-		// 2. Crear el ítem de menú que contendrá el submenú
-		presetsMenuItem := fyne.NewMenuItem("🌡️ Presets", nil)
-		presetsMenuItem.ChildMenu = presetsSubMenu
-
-		// 3. Crear el menú principal y añadir el ítem con el submenú
-		menuItems := []*fyne.MenuItem{
-			fyne.NewMenuItem("🌙 Aplicar", s.applyCurrentSettings),
-			fyne.NewMenuItem("🔄 Resetear", s.resetToNormal),
-			fyne.NewMenuItemSeparator(),
-			presetsMenuItem, // Añadir el ítem que despliega el submenú
-			fyne.NewMenuItemSeparator(),
-		}
+	if !IsTrayAvailable() {
+		logging.Println("⚠️  No hay bandeja del sistema disponible (falta un StatusNotifierWatcher en el escritorio)")
+		s.available = false
+		return
+	}
+
+	mainMenu := s.buildMenu()
+	s.menu = mainMenu
+	s.startNextChangeRefresher()
+	s.followColorScheme()
+
+	err := s.startSNI()
+	if err == nil {
+		s.sni.SetMenu(toTrayItems(mainMenu.Items))
+		s.sni.SetToolTip(s.nextChangeItem.Label)
+		s.available = true
+		return
+	}
 
+	desk, ok := s.app.(desktop.App)
+	if !ok {
+		logging.Printf("⚠️  No se pudo crear el icono de bandeja: %v\n", err)
+		s.available = false
+		return
+	}
+
+	// Respaldo: bandeja de Fyne (sin rueda ni clic central)
+	logging.Printf("⚠️  Icono propio no disponible, se usa la bandeja de Fyne: %v\n", err)
+	desk.SetSystemTrayMenu(mainMenu)
+
+	// Configurar icono
+	iconData := s.trayIcon()
+	if len(iconData) > 0 {
+		desk.SetSystemTrayIcon(fyne.NewStaticResource("trayIcon", iconData))
+	}
+	s.available = true
+}
+
+// trayIcon devuelve el icono configurado para el tema actual del escritorio,
+// con la temperatura aplicada encima si la insignia está activada
+func (s *SystrayManager) trayIcon() []byte {
+	icon := TrayIcon(s.controller.GetTrayIcon(), s.colorScheme)
+	s.badge = s.badgeText()
+	if s.badge != "" {
+		icon = WithTemperatureBadge(icon, s.badge)
+	}
+	return icon
+}
+
+// badgeText devuelve la insignia que corresponde al estado actual ("" sin filtro o desactivada)
+func (s *SystrayManager) badgeText() string {
+	config := s.controller.GetConfig()
+	if !s.controller.IsTrayBadge() || !config.IsActive {
+		return ""
+	}
+	return BadgeText(config.Temperature)
+}
+
+// refreshBadge redibuja el icono solo si cambió la temperatura mostrada
+// (las transiciones aplican muchas veces la misma centena)
+func (s *SystrayManager) refreshBadge() {
+	if s.badgeText() != s.badge {
+		s.updateIcon()
+	}
+}
+
+// followColorScheme lee el tema del escritorio y cambia el icono cuando cambia
+func (s *SystrayManager) followColorScheme() {
+	if s.themeWatcher != nil {
+		return
+	}
+	s.colorScheme = system.ReadColorScheme()
+
+	watcher, err := system.StartColorSchemeWatcher(func(scheme system.ColorScheme) {
+		runOnUI(func() {
+			s.colorScheme = scheme
+			s.updateIcon()
+		})
+	})
+	if err != nil {
+		logging.Printf("⚠️  El icono de bandeja no seguirá el tema del escritorio: %v\n", err)
+		return
+	}
+	s.themeWatcher = watcher
+}
+
+// updateIcon vuelve a elegir el icono de la bandeja en uso
+func (s *SystrayManager) updateIcon() {
+	icon := s.trayIcon()
+	if s.sni != nil {
+		s.sni.SetIcon(icon)
+		return
+	}
+	if desk, ok := s.app.(desktop.App); ok && s.available {
+		desk.SetSystemTrayIcon(fyne.NewStaticResource("trayIcon", icon))
+	}
+}
+
+// startSNI publica el StatusNotifierItem propio la primera vez
+func (s *SystrayManager) startSNI() error {
+	if s.sni != nil {
+		return nil
+	}
+	item, err := ipc.StartStatusNotifierItem("Luz Nocturna", s.trayIcon(), onUI(s.showMainWindow))
+	if err != nil {
+		return err
+	}
+	s.sni = item
+	// El panel avisa antes de abrir el menú: así la cuenta atrás está al día
+	s.sni.SetOnMenuOpening(func() { runOnUIAndWait(s.refreshNextChange) })
+	// Como en los applets de volumen: la rueda ajusta y el clic central alterna
+	s.sni.SetOnScroll(func(steps int) { runOnUI(func() { s.stepTemperature(steps) }) })
+	s.sni.SetOnSecondaryActivate(onUI(s.toggleNightLight))
+	return nil
+}
+
+// IsAvailable indica si el último CreateMenu logró mostrar el icono
+func (s *SystrayManager) IsAvailable() bool {
+	return s.available
+}
+
+// buildMenu - Construye el menú de la bandeja a partir de la configuración
+func (s *SystrayManager) buildMenu() *fyne.Menu {
+	// 1. Crear el submenú de presets a partir de la configuración
+	var presetItems []*fyne.MenuItem
+	for i, preset := range s.controller.GetPresets() {
+		index := i // Capturar valor para closure
+		presetItems = append(presetItems, fyne.NewMenuItem(preset.Label(), func() {
+			s.applyTemperaturePreset(index)
+		}))
+	}
+	presetsSubMenu := fyne.NewMenu("Presets", presetItems...) // El título aquí es para la estructura interna
+
+	// 2. Crear el ítem de menú que contendrá el submenú
+	presetsMenuItem := fyne.NewMenuItem("🌡️ Presets", nil)
+	presetsMenuItem.ChildMenu = presetsSubMenu
+
+	// 3. Entrada informativa con el próximo cambio (no se puede pulsar)
+	s.nextChangeItem = fyne.NewMenuItem(s.nextChangeText(), nil)
+	s.nextChangeItem.Disabled = true
+	s.skipItem = fyne.NewMenuItem("", s.toggleSkipTonight)
+	s.updateSkipItem()
+
+	// 4. Crear el menú principal y añadir el ítem con el submenú
+	menuItems := []*fyne.MenuItem{
+		s.nextChangeItem,
+		s.skipItem,
+		fyne.NewMenuItemSeparator(),
+		fyne.NewMenuItem("🌙 Aplicar", s.applyCurrentSettings),
+		fyne.NewMenuItem("🔄 Resetear", s.resetToNormal),
+		fyne.NewMenuItem("↶ Deshacer", s.undoLastChange),
+		s.buildMovieMenuItem(),
+		s.buildAccurateMenuItem(),
+		fyne.NewMenuItemSeparator(),
+		presetsMenuItem, // Añadir el ítem que despliega el submenú
+	}
+
+	// 5. Submenú de displays: solo útil con más de un monitor y en X11 (en Wayland la gamma es global)
+	if displays := s.controller.GetDisplays(); len(displays) > 1 && s.controller.GetProtocol() == "x11" {
+		menuItems = append(menuItems, s.buildDisplaysMenuItem(displays))
+	}
+	menuItems = append(menuItems, fyne.NewMenuItemSeparator())
+
+	if s.mainView != nil {
+		menuItems = append(menuItems, fyne.NewMenuItem("📱 Mostrar", s.showMainWindow))
+	}
+
+	menuItems = append(menuItems, fyne.NewMenuItem("❌ Salir", func() {
 		if s.mainView != nil {
-			menuItems = append(menuItems, fyne.NewMenuItem("📱 Mostrar", s.showMainWindow))
+			s.mainView.SaveWindowState()
 		}
+		s.app.Quit()
+	}))
 
-		menuItems = append(menuItems, fyne.NewMenuItem("❌ Salir", func() {
-			s.app.Quit()
-		}))
+	return fyne.NewMenu("Luz Nocturna", menuItems...)
+}
+
+// buildDisplaysMenuItem crea el submenú con un elemento marcable por display
+func (s *SystrayManager) buildDisplaysMenuItem(displays []string) *fyne.MenuItem {
+	var items []*fyne.MenuItem
+	for _, display := range displays {
+		name := display // Capturar valor para closure
+		item := fyne.NewMenuItem(name, func() {
+			s.toggleDisplay(name)
+		})
+		item.Checked = s.controller.IsDisplayIncluded(name)
+		item.Disabled = !s.controller.IsDisplayInScope(name) // Fuera de "solo externos/interno"
+		items = append(items, item)
+	}
+
+	displaysMenuItem := fyne.NewMenuItem("🖥️ Pantallas", nil)
+	displaysMenuItem.ChildMenu = fyne.NewMenu("Pantallas", items...)
+	return displaysMenuItem
+}
+
+// buildMovieMenuItem crea la entrada del modo película, marcada mientras está activo
+func (s *SystrayManager) buildMovieMenuItem() *fyne.MenuItem {
+	item := fyne.NewMenuItem("🎬 Modo película", s.controller.ToggleMovieMode)
+	if active, until := s.controller.GetMovieMode(); active {
+		item.Label = "🎬 Modo película (hasta las " + s.controller.GetClock().FormatTime(until) + ")"
+		item.Checked = true
+	}
+	return item
+}
+
+// buildAccurateMenuItem crea la entrada del modo de color fiel, marcada mientras está activo
+func (s *SystrayManager) buildAccurateMenuItem() *fyne.MenuItem {
+	item := fyne.NewMenuItem("🎨 Color fiel", s.controller.ToggleColorAccurate)
+	if active, until := s.controller.GetColorAccurate(); active {
+		item.Label = "🎨 Color fiel (hasta las " + s.controller.GetClock().FormatTime(until) + ")"
+		item.Checked = true
+	}
+	return item
+}
+
+/**
+ * nextChangeText - Describe el próximo cambio de la programación automática
+ *
+ * @returns {string} Texto como "🔔 Inicio filtro nocturno a las 9:00 p. m. (en 2h 13m, 3200K)"
+ * @private
+ */
+func (s *SystrayManager) nextChangeText() string {
+	if !s.controller.IsScheduleEnabled() {
+		return "⏸️ Programación deshabilitada"
+	}
+	if end, paused := s.controller.IsOnVacation(); paused {
+		return vacationText(end)
+	}
+
+	description, temp, duration := s.controller.GetNextScheduleChange()
+	if duration <= 0 {
+		return "🔔 " + description
+	}
+	at := s.controller.GetClock().FormatTime(time.Now().Add(duration))
+	return fmt.Sprintf("🔔 %s a las %s (en %s, %s)", description, at, formatCountdown(duration),
+		s.controller.GetTemperatureFormat().Format(temp))
+}
+
+// updateSkipItem muestra si la noche de hoy está omitida; sin programación no se puede pulsar
+func (s *SystrayManager) updateSkipItem() {
+	until, skipped := s.controller.GetSkipTonight()
+	s.skipItem.Checked = skipped
+	_, paused := s.controller.IsOnVacation()
+	s.skipItem.Disabled = !s.controller.IsScheduleEnabled() || paused
+	if skipped {
+		s.skipItem.Label = "⏭️ Noche omitida (hasta las " + s.controller.GetClock().FormatTime(until) + ")"
+		return
+	}
+	s.skipItem.Label = "⏭️ Omitir esta noche"
+}
+
+// vacationText describe el modo vacaciones en curso (para la bandeja y la ventana)
+func vacationText(end time.Time) string {
+	return fmt.Sprintf("🏖️ Vacaciones hasta el %s: programación y vigilante en pausa", end.AddDate(0, 0, -1).Format("02/01"))
+}
+
+// formatCountdown formatea un tiempo restante como "2h 13m" o "13m"
+func formatCountdown(duration time.Duration) string {
+	hours := int(duration.Hours())
+	minutes := int(duration.Minutes()) % 60
+	if hours == 0 {
+		return fmt.Sprintf("%dm", minutes)
+	}
+	return fmt.Sprintf("%dh %02dm", hours, minutes)
+}
+
+/**
+ * refreshNextChange - Actualiza la entrada del próximo cambio y el tooltip
+ *
+ * Con la bandeja propia se llama cuando el panel va a abrir el menú; la
+ * bandeja de Fyne no avisa, así que además se refresca cada minuto.
+ * Debe ejecutarse en el hilo principal de Fyne.
+ *
+ * @private
+ */
+func (s *SystrayManager) refreshNextChange() {
+	if s.menu == nil || s.nextChangeItem == nil {
+		return
+	}
+
+	s.nextChangeItem.Label = s.nextChangeText()
+	s.updateSkipItem()
+	if s.sni != nil {
+		s.sni.SetMenu(toTrayItems(s.menu.Items))
+		s.sni.SetToolTip(s.toolTipText())
+		return
+	}
+	s.menu.Refresh()
+}
+
+// refreshToolTip actualiza el tooltip tras aplicar o restaurar (solo la bandeja propia tiene tooltip)
+func (s *SystrayManager) refreshToolTip() {
+	if s.sni == nil || s.nextChangeItem == nil {
+		return
+	}
+	s.sni.SetToolTip(s.toolTipText())
+}
 
-		mainMenu := fyne.NewMenu("Luz Nocturna", menuItems...)
+// toolTipText describe el próximo cambio y, con el filtro activo, cuánta luz azul retira
+func (s *SystrayManager) toolTipText() string {
+	config := s.controller.GetConfig()
+	if !config.IsActive {
+		return s.nextChangeItem.Label
+	}
+	return s.nextChangeItem.Label + "\n" + blueLightText(config.Temperature, config.Brightness)
+}
 
-		desk.SetSystemTrayMenu(mainMenu)
+// startNextChangeRefresher refresca la cuenta atrás cada minuto (solo se inicia una vez)
+func (s *SystrayManager) startNextChangeRefresher() {
+	if s.refreshing {
+		return
+	}
+	s.refreshing = true
 
-		// Configurar icono
-		iconData := GetOptimalIcon()
-		if len(iconData) > 0 {
-			desk.SetSystemTrayIcon(fyne.NewStaticResource("trayIcon", iconData))
+	everyOnUI(time.Minute, s.refreshNextChange)
+}
+
+// toTrayItems convierte un menú de Fyne al formato del StatusNotifierItem propio
+func toTrayItems(items []*fyne.MenuItem) []ipc.TrayMenuItem {
+	result := make([]ipc.TrayMenuItem, 0, len(items))
+	for _, item := range items {
+		trayItem := ipc.TrayMenuItem{Label: item.Label, Separator: item.IsSeparator, Checked: item.Checked, Disabled: item.Disabled}
+		if action := item.Action; action != nil {
+			// Las llamadas D-Bus llegan fuera del hilo principal de Fyne
+			trayItem.Action = onUI(action)
+		}
+		if item.ChildMenu != nil {
+			trayItem.Children = toTrayItems(item.ChildMenu.Items)
 		}
+		result = append(result, trayItem)
 	}
+	return result
 }
 
 func (s *SystrayManager) applyCurrentSettings() {
@@ -85,15 +411,33 @@ func (s *SystrayManager) resetToNormal() {
 	_ = s.controller.ResetNightLight()
 }
 
-func (s *SystrayManager) applyTemperaturePreset(temperature int, presetName string) {
-	config := s.controller.GetConfig()
-	config.Temperature = float64(temperature)
+func (s *SystrayManager) undoLastChange() {
+	_ = s.controller.Undo()
+}
 
-	_ = s.controller.ApplyNightLight()
+// stepTemperature sube (pasos positivos) o baja la temperatura trayScrollStep Kelvin por paso
+func (s *SystrayManager) stepTemperature(steps int) {
+	_ = s.controller.StepTemperature(float64(steps) * trayScrollStep)
+}
 
-	if s.mainView != nil {
-		s.mainView.updateTemperatureDisplay()
+func (s *SystrayManager) toggleNightLight() {
+	_ = s.controller.ToggleNightLight()
+}
+
+func (s *SystrayManager) toggleSkipTonight() {
+	if _, skipped := s.controller.GetSkipTonight(); skipped {
+		s.controller.CancelSkipTonight()
+		return
 	}
+	_, _ = s.controller.SkipTonight()
+}
+
+func (s *SystrayManager) toggleDisplay(display string) {
+	_ = s.controller.SetDisplayIncluded(display, !s.controller.IsDisplayIncluded(display))
+}
+
+func (s *SystrayManager) applyTemperaturePreset(index int) {
+	_ = s.controller.ApplyPreset(index)
 }
 
 func (s *SystrayManager) showMainWindow() {
@@ -0,0 +1,60 @@
+package views
+
+import (
+	"image/color"
+	"math"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/theme"
+
+	"luznocturna/luz-nocturna/internal/system"
+)
+
+// swatchSize es el lado de la muestra de color junto al slider de temperatura
+const swatchSize = 48
+
+/**
+ * createTemperatureSwatch - Crea la muestra de cómo se verá el blanco
+ *
+ * Un cuadrado blanco multiplicado por los factores RGB de
+ * system.TemperatureToRGB (y atenuado con el brillo) que se actualiza
+ * mientras se mueve el slider, antes de aplicar nada. Es aproximado: la
+ * pantalla real depende del panel y del backend.
+ *
+ * @returns {*canvas.Rectangle} Muestra de color con borde
+ * @private
+ */
+func (v *NightLightView) createTemperatureSwatch() *canvas.Rectangle {
+	v.tempSwatch = canvas.NewRectangle(color.White)
+	v.tempSwatch.SetMinSize(fyne.NewSize(swatchSize, swatchSize))
+	v.tempSwatch.StrokeColor = theme.Color(theme.ColorNameSeparator)
+	v.tempSwatch.StrokeWidth = 1
+	v.tempSwatch.CornerRadius = 4
+	v.updateTemperatureSwatch()
+	return v.tempSwatch
+}
+
+// updateTemperatureSwatch pinta la muestra con la temperatura y el brillo del slider
+func (v *NightLightView) updateTemperatureSwatch() {
+	if v.tempSwatch == nil {
+		return
+	}
+	temperature, _ := v.temperature.Get()
+	brightness, _ := v.brightness.Get()
+	v.tempSwatch.FillColor = swatchColor(temperature, brightness)
+	v.tempSwatch.Refresh()
+}
+
+// swatchColor devuelve el color aproximado del blanco a una temperatura y un brillo
+// (el brillo por encima de 1.0 no puede aclarar más que el blanco puro)
+func swatchColor(temperature, brightness float64) color.Color {
+	r, g, b := system.TemperatureToRGB(temperature)
+	if brightness <= 0 || brightness > 1.0 {
+		brightness = 1.0
+	}
+	channel := func(factor float64) uint8 {
+		return uint8(math.Round(math.Min(factor*brightness, 1.0) * 255))
+	}
+	return color.NRGBA{R: channel(r), G: channel(g), B: channel(b), A: 0xff}
+}
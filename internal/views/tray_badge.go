@@ -0,0 +1,100 @@
+package views
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+
+	"luznocturna/luz-nocturna/internal/logging"
+)
+
+// badgeDigits son las cifras 0-9 en una rejilla de 3x5 (una fila por cadena)
+var badgeDigits = [10][5]string{
+	{"111", "101", "101", "101", "111"},
+	{"010", "110", "010", "010", "111"},
+	{"111", "001", "111", "100", "111"},
+	{"111", "001", "111", "001", "111"},
+	{"101", "101", "111", "001", "001"},
+	{"111", "100", "111", "001", "111"},
+	{"111", "100", "111", "101", "111"},
+	{"111", "001", "010", "010", "010"},
+	{"111", "101", "111", "101", "111"},
+	{"111", "101", "111", "001", "111"},
+}
+
+// Colores de la insignia: fondo oscuro semitransparente y cifras blancas,
+// legibles sobre paneles claros y oscuros
+var (
+	badgeBackground = color.NRGBA{0x1e, 0x1e, 0x1e, 0xe0}
+	badgeForeground = color.NRGBA{0xff, 0xff, 0xff, 0xff}
+)
+
+// BadgeText devuelve las centenas de Kelvin que muestra la insignia ("34" para 3400K)
+func BadgeText(temp float64) string {
+	return fmt.Sprintf("%d", int(math.Round(temp/100)))
+}
+
+/**
+ * WithTemperatureBadge - Dibuja la temperatura sobre el icono de la bandeja
+ *
+ * La insignia ocupa la esquina inferior derecha con las centenas de
+ * Kelvin ("34" para 3400K) en una fuente de 3x5 píxeles, escalada al
+ * tamaño del icono para que siga siendo legible a 16 y a 24 píxeles.
+ *
+ * @param {[]byte} icon - Icono en PNG
+ * @param {string} text - Cifras a mostrar (BadgeText)
+ * @returns {[]byte} Icono con la insignia en PNG; el original si no se puede decodificar
+ * @example
+ *   icon = WithTemperatureBadge(icon, BadgeText(3400)) // "34"
+ */
+func WithTemperatureBadge(icon []byte, text string) []byte {
+	base, err := png.Decode(bytes.NewReader(icon))
+	if err != nil {
+		logging.Printf("⚠️  No se pudo dibujar la temperatura en el icono: %v\n", err)
+		return icon
+	}
+
+	bounds := base.Bounds()
+	canvas := image.NewNRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(canvas, canvas.Bounds(), base, bounds.Min, draw.Src)
+
+	// Escala de la fuente: 1 píxel por punto a 16px, 2 a 24px y más
+	scale := bounds.Dx() / 12
+	if scale < 1 {
+		scale = 1
+	}
+	textWidth := (len(text)*4 - 1) * scale // 3 columnas por cifra y 1 de separación
+	textHeight := 5 * scale
+	badge := image.Rect(
+		canvas.Bounds().Max.X-textWidth-2, canvas.Bounds().Max.Y-textHeight-2,
+		canvas.Bounds().Max.X, canvas.Bounds().Max.Y,
+	)
+	draw.Draw(canvas, badge, image.NewUniform(badgeBackground), image.Point{}, draw.Over)
+
+	x := badge.Min.X + 1
+	for _, r := range text {
+		if r < '0' || r > '9' {
+			continue
+		}
+		for row, bits := range badgeDigits[r-'0'] {
+			for col, bit := range bits {
+				if bit != '1' {
+					continue
+				}
+				dot := image.Rect(x+col*scale, badge.Min.Y+1+row*scale, x+(col+1)*scale, badge.Min.Y+1+(row+1)*scale)
+				draw.Draw(canvas, dot, image.NewUniform(badgeForeground), image.Point{}, draw.Src)
+			}
+		}
+		x += 4 * scale
+	}
+
+	var out bytes.Buffer
+	if err := png.Encode(&out, canvas); err != nil {
+		return icon
+	}
+	return out.Bytes()
+}
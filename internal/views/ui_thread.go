@@ -0,0 +1,78 @@
+package views
+
+import (
+	"time"
+
+	"fyne.io/fyne/v2"
+
+	"luznocturna/luz-nocturna/internal/controllers"
+)
+
+// =====================================================
+// ACTUALIZACIONES DESDE OTRAS GORUTINAS
+// =====================================================
+//
+// Fyne solo admite cambios en los widgets desde su hilo principal. Los
+// tickers, el bus de eventos del controlador (que publica desde el
+// programador, la cola de aplicación o D-Bus) y las llamadas del
+// StatusNotifierItem llegan desde otras gorutinas: todos pasan por estos
+// helpers en lugar de tocar los widgets directamente.
+
+// runOnUI encola fn en el hilo de la interfaz sin esperar a que termine
+func runOnUI(fn func()) {
+	fyne.Do(fn)
+}
+
+// runOnUIAndWait ejecuta fn en el hilo de la interfaz y espera a que termine
+func runOnUIAndWait(fn func()) {
+	fyne.DoAndWait(fn)
+}
+
+// onUI envuelve un callback para que siempre se ejecute en el hilo de la interfaz
+func onUI(fn func()) func() {
+	return func() { runOnUI(fn) }
+}
+
+// onUIEvent envuelve un suscriptor del controlador para que procese los eventos en el hilo de la interfaz
+func onUIEvent(handler func(controllers.Event)) func(controllers.Event) {
+	return func(event controllers.Event) {
+		runOnUI(func() { handler(event) })
+	}
+}
+
+/**
+ * everyOnUI - Ejecuta fn periódicamente en el hilo de la interfaz
+ *
+ * El ticker vive en su propia gorutina; cada tick se despacha con
+ * runOnUI para que fn pueda tocar los widgets sin riesgo.
+ *
+ * @param {time.Duration} interval - Intervalo entre ejecuciones
+ * @param {func()} fn - Actualización de la interfaz
+ * @returns {func()} Función que detiene el ticker
+ * @example
+ *   stop := everyOnUI(time.Minute, s.refreshNextChange)
+ *   defer stop()
+ */
+func everyOnUI(interval time.Duration, fn func()) func() {
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				runOnUI(fn)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() { close(done) }
+}
+
+// afterOnUI ejecuta fn en el hilo de la interfaz cuando pasa delay
+func afterOnUI(delay time.Duration, fn func()) {
+	time.AfterFunc(delay, onUI(fn))
+}
@@ -0,0 +1,121 @@
+package weather
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"luznocturna/luz-nocturna/internal/models"
+)
+
+// Servicios de meteorología consultados
+const (
+	OpenMeteoURL      = "https://api.open-meteo.com/v1/forecast"          // Sin clave
+	OpenWeatherMapURL = "https://api.openweathermap.org/data/2.5/weather" // Requiere clave
+)
+
+// requestTimeout es el tiempo máximo de una consulta
+const requestTimeout = 10 * time.Second
+
+/**
+ * CloudCover - Consulta la nubosidad actual de una ubicación
+ *
+ * Sin apiKey usa Open-Meteo; con ella, OpenWeatherMap. Solo se llama si
+ * el usuario activó el ajuste por nubosidad.
+ *
+ * @param {context.Context} ctx - Contexto para cancelar la petición
+ * @param {models.Location} location - Ubicación configurada
+ * @param {string} apiKey - Clave de OpenWeatherMap ("" para Open-Meteo)
+ * @returns {float64, error} Nubosidad en porcentaje (0-100) o error de red
+ * @example
+ *   cover, err := weather.CloudCover(ctx, models.Location{Latitude: 4.61, Longitude: -74.08}, "")
+ */
+func CloudCover(ctx context.Context, location models.Location, apiKey string) (float64, error) {
+	if !location.IsSet() || !location.IsValid() {
+		return 0, fmt.Errorf("no hay ubicación configurada")
+	}
+
+	query := url.Values{}
+	query.Set("latitude", fmt.Sprintf("%.4f", location.Latitude))
+	query.Set("longitude", fmt.Sprintf("%.4f", location.Longitude))
+	query.Set("current", "cloud_cover")
+	endpoint := OpenMeteoURL
+
+	if apiKey != "" {
+		query = url.Values{}
+		query.Set("lat", fmt.Sprintf("%.4f", location.Latitude))
+		query.Set("lon", fmt.Sprintf("%.4f", location.Longitude))
+		query.Set("appid", apiKey)
+		endpoint = OpenWeatherMapURL
+	}
+
+	var response struct {
+		Current struct {
+			CloudCover *float64 `json:"cloud_cover"`
+		} `json:"current"` // Open-Meteo
+		Clouds struct {
+			All *float64 `json:"all"`
+		} `json:"clouds"` // OpenWeatherMap
+	}
+	if err := getJSON(ctx, endpoint+"?"+query.Encode(), &response); err != nil {
+		return 0, err
+	}
+
+	switch {
+	case response.Current.CloudCover != nil:
+		return *response.Current.CloudCover, nil
+	case response.Clouds.All != nil:
+		return *response.Clouds.All, nil
+	default:
+		return 0, fmt.Errorf("la respuesta no incluye la nubosidad")
+	}
+}
+
+/**
+ * getJSON - Hace una petición GET y decodifica la respuesta JSON en target
+ *
+ * Los errores nunca incluyen la URL: su consulta lleva la clave de la API
+ * y las coordenadas, y acabaría en los registros.
+ *
+ * @param {context.Context} ctx - Contexto de la consulta
+ * @param {string} address - URL completa con la consulta
+ * @param {any} target - Destino de la respuesta decodificada
+ * @returns {error} Error de red, de estado HTTP o de formato
+ * @private
+ */
+func getJSON(ctx context.Context, address string, target any) error {
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, address, nil)
+	if err != nil {
+		return fmt.Errorf("no se pudo preparar la consulta del tiempo: %w", withoutURL(err))
+	}
+
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return fmt.Errorf("no se pudo consultar el tiempo: %w", withoutURL(err))
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("respuesta inesperada del servicio del tiempo: %s", response.Status)
+	}
+	if err := json.NewDecoder(response.Body).Decode(target); err != nil {
+		return fmt.Errorf("respuesta del servicio del tiempo inválida: %w", err)
+	}
+	return nil
+}
+
+// withoutURL quita la URL de un *url.Error y deja solo la causa
+func withoutURL(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return urlErr.Err
+	}
+	return err
+}
@@ -2,21 +2,132 @@ package main
 
 import (
 	"flag"
+	"fmt"
 	"fyne.io/fyne/v2/app"
+	"luznocturna/luz-nocturna/internal/cli"
 	"luznocturna/luz-nocturna/internal/controllers"
+	"luznocturna/luz-nocturna/internal/ipc"
+	"luznocturna/luz-nocturna/internal/logging"
+	"luznocturna/luz-nocturna/internal/models"
+	"luznocturna/luz-nocturna/internal/paths"
+	"luznocturna/luz-nocturna/internal/system"
+	"luznocturna/luz-nocturna/internal/version"
 	"luznocturna/luz-nocturna/internal/views"
+	"os"
+	"os/signal"
+	"syscall"
 )
 
 func main() {
+	// Subcomandos de línea de comandos (no abren la interfaz gráfica)
+	if cli.IsSubcommand(os.Args[1:]) {
+		os.Exit(cli.Run(os.Args[1:]))
+	}
+
 	// Flags de línea de comandos
 	trayMode := flag.Bool("tray", false, "Iniciar en modo bandeja del sistema")
+	dryRun := flag.Bool("dry-run", false, "Mostrar los comandos de gamma sin aplicarlos al display")
+	resetOnExit := flag.Bool("reset-on-exit", false, "Restaurar la gamma normal al salir")
+	fakeBackend := flag.Bool("fake-backend", false, "Usar un backend de gamma simulado (pruebas de integración)")
+	demo := flag.Bool("demo", false, "Modo demostración: backend simulado y configuración temporal (no toca los displays ni los archivos del usuario)")
+	portable := flag.Bool("portable", false, "Guardar la configuración y el estado junto al ejecutable")
+	showVersion := flag.Bool("version", false, "Mostrar la versión y salir")
+	logFormat := flag.String("log-format", logging.FormatText, "Formato de los registros: text o json")
 	flag.Parse()
 
+	if err := logging.SetFormat(*logFormat); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		os.Exit(2)
+	}
+
+	if *showVersion {
+		fmt.Println("luz-nocturna " + version.String())
+		return
+	}
+
+	// Al salir se borra el directorio temporal del modo demostración
+	cleanup := func() {}
+	if *demo {
+		dir, err := paths.EnableDemo()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		cleanup = func() { os.RemoveAll(dir) }
+		defer cleanup()
+		logging.Printf("🎬 Modo demostración: backend simulado, configuración en %s\n", dir)
+	} else if *portable {
+		if err := paths.EnablePortable(); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+	}
+	if paths.IsPortable() && !paths.IsDemo() {
+		logging.Printf("🎒 Modo portátil: configuración en %s\n", paths.ConfigDir())
+	}
+
+	// Subcomandos detrás de las opciones globales (luz-nocturna --portable config set ...)
+	if args := flag.Args(); len(args) > 0 {
+		if !cli.IsSubcommand(args) {
+			fmt.Fprintf(os.Stderr, "❌ Argumento desconocido: %s\n", args[0])
+			flag.Usage()
+			cleanup()
+			os.Exit(2)
+		}
+		code := cli.Run(args)
+		cleanup()
+		os.Exit(code)
+	}
+
 	// Crear la aplicación
 	myApp := app.NewWithID("com.luznocturna.app")
 
 	// Crear controlador
-	controller := controllers.NewNightLightController()
+	options := controllers.ControllerOptions{
+		DryRun:      *dryRun,
+		ResetOnExit: *resetOnExit,
+	}
+	// LUZ_NOCTURNA_BACKEND se suma a los flags (contenedores, kioscos, pruebas)
+	switch backend := models.BackendOverride(); backend {
+	case models.BackendFake:
+		*fakeBackend = true
+	case models.BackendDryRun:
+		options.DryRun = true
+	case models.BackendX11, models.BackendWayland:
+		options.Protocol = backend
+	}
+	switch {
+	case *demo:
+		// Nombres de conectores reales para que las capturas parezcan un equipo normal
+		options.Backend = system.NewFakeBackend("eDP-1", "HDMI-1")
+	case *fakeBackend:
+		options.Backend = system.NewFakeBackend()
+	}
+	controller := controllers.NewNightLightControllerWithOptions(options)
+
+	// Restaurar la gamma si el proceso muere por una señal o un pánico
+	handleExitSignals(controller, cleanup)
+	defer func() {
+		if r := recover(); r != nil {
+			controller.EmergencyRestore()
+			panic(r)
+		}
+	}()
+
+	// La demostración no publica servicios de sesión: podría quitarle el
+	// nombre D-Bus a la instancia real o enviar notificaciones
+	if !*demo {
+		defer startSessionServices(controller)()
+	}
+
+	// Detener el programador y restaurar la gamma si así se configuró
+	myApp.Lifecycle().SetOnStopped(controller.Shutdown)
+
+	// Sin bandeja disponible el modo bandeja dejaría la aplicación invisible
+	if *trayMode && !views.IsTrayAvailable() {
+		logging.Println("⚠️  No hay bandeja del sistema disponible; se abrirá la ventana principal")
+		*trayMode = false
+	}
 
 	if *trayMode {
 		// Modo bandeja del sistema (sin ventana visible)
@@ -35,13 +146,59 @@ func main() {
 		systrayManager := views.NewSystrayManager(myApp, controller, mainView)
 		systrayManager.CreateMenu()
 
-		// Configurar comportamiento al cerrar
-		window.SetCloseIntercept(func() {
-			// En lugar de cerrar completamente, minimizar a bandeja
-			window.Hide()
-		})
+		// Configurar comportamiento al cerrar (bandeja, salir o preguntar)
+		window.SetCloseIntercept(mainView.OnCloseRequested)
 
 		// Mostrar y ejecutar la aplicación
 		window.ShowAndRun()
 	}
-}
\ No newline at end of file
+}
+
+// startSessionServices publica el servicio D-Bus, el servidor gRPC (si se activó)
+// y las notificaciones de la programación; devuelve la función que los cierra
+func startSessionServices(controller *controllers.NightLightController) func() {
+	var closers []func()
+
+	// Servicio D-Bus para widgets y scripts externos (opcional)
+	if service, err := ipc.StartDBusService(controller); err != nil {
+		logging.Printf("⚠️  Servicio D-Bus no disponible: %v\n", err)
+	} else {
+		closers = append(closers, service.Close)
+	}
+
+	// Servidor gRPC para automatizaciones (solo si se activó en config.json)
+	if config := controller.GetGRPCConfig(); config.Enabled {
+		if server, err := ipc.StartGRPCServer(controller, config); err != nil {
+			logging.Printf("⚠️  Servidor gRPC no disponible: %v\n", err)
+		} else {
+			closers = append(closers, server.Close)
+		}
+	}
+
+	// Notificaciones de escritorio de la programación (no dependen de la ventana de Fyne)
+	if notifier, err := ipc.StartScheduleNotifications(controller); err != nil {
+		logging.Printf("⚠️  Notificaciones no disponibles: %v\n", err)
+	} else {
+		closers = append(closers, notifier.Close)
+	}
+
+	return func() {
+		for i := len(closers) - 1; i >= 0; i-- {
+			closers[i]()
+		}
+	}
+}
+
+// handleExitSignals restaura la gamma, ejecuta cleanup y sale al recibir SIGINT o SIGTERM
+func handleExitSignals(controller *controllers.NightLightController, cleanup func()) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+
+	go func() {
+		sig := <-signals
+		logging.Printf("\n🛑 Señal %v recibida\n", sig)
+		controller.EmergencyRestore()
+		cleanup()
+		os.Exit(128 + int(sig.(syscall.Signal)))
+	}()
+}
@@ -0,0 +1,8 @@
+// Package luznocturnav1 contiene los tipos y el cliente gRPC generados a
+// partir de nightlight.proto, la versión 1 de la API de control remoto.
+//
+// El servidor se activa con "grpc": {"enabled": true} en config.json y
+// escucha por defecto en un socket Unix de $XDG_RUNTIME_DIR/luz-nocturna.
+package luznocturnav1
+
+//go:generate protoc -I ../.. --go_out=../.. --go_opt=paths=source_relative --go-grpc_out=../.. --go-grpc_opt=paths=source_relative luznocturna/v1/nightlight.proto
@@ -0,0 +1,738 @@
+// API gRPC de Luz Nocturna, versión 1.
+//
+// Alternativa a D-Bus para automatizaciones que ya hablan gRPC. Los
+// cambios incompatibles irán a un paquete luznocturna.v2; en v1 solo se
+// añaden campos y métodos nuevos.
+//
+// Para regenerar el código Go (protoc-gen-go y protoc-gen-go-grpc):
+//
+//   go generate ./pkg/api/...
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.34.2
+// 	protoc        (unknown)
+// source: luznocturna/v1/nightlight.proto
+
+package luznocturnav1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// EventType es el tipo de un evento del controlador.
+type EventType int32
+
+const (
+	EventType_EVENT_TYPE_UNSPECIFIED EventType = 0
+	// Primer mensaje de Watch: estado en el momento de suscribirse.
+	EventType_EVENT_TYPE_SNAPSHOT             EventType = 1
+	EventType_EVENT_TYPE_TEMPERATURE_CHANGED  EventType = 2
+	EventType_EVENT_TYPE_APPLIED              EventType = 3
+	EventType_EVENT_TYPE_RESET                EventType = 4
+	EventType_EVENT_TYPE_SCHEDULE_TRANSITION  EventType = 5
+	EventType_EVENT_TYPE_PRESETS_CHANGED      EventType = 6
+	EventType_EVENT_TYPE_DISPLAYS_CHANGED     EventType = 7
+	EventType_EVENT_TYPE_APPLY_FAILED         EventType = 8
+	EventType_EVENT_TYPE_MOVIE_MODE_CHANGED   EventType = 9
+	EventType_EVENT_TYPE_SKIP_TONIGHT_CHANGED EventType = 10
+	EventType_EVENT_TYPE_VACATION_CHANGED     EventType = 11
+)
+
+// Enum value maps for EventType.
+var (
+	EventType_name = map[int32]string{
+		0:  "EVENT_TYPE_UNSPECIFIED",
+		1:  "EVENT_TYPE_SNAPSHOT",
+		2:  "EVENT_TYPE_TEMPERATURE_CHANGED",
+		3:  "EVENT_TYPE_APPLIED",
+		4:  "EVENT_TYPE_RESET",
+		5:  "EVENT_TYPE_SCHEDULE_TRANSITION",
+		6:  "EVENT_TYPE_PRESETS_CHANGED",
+		7:  "EVENT_TYPE_DISPLAYS_CHANGED",
+		8:  "EVENT_TYPE_APPLY_FAILED",
+		9:  "EVENT_TYPE_MOVIE_MODE_CHANGED",
+		10: "EVENT_TYPE_SKIP_TONIGHT_CHANGED",
+		11: "EVENT_TYPE_VACATION_CHANGED",
+	}
+	EventType_value = map[string]int32{
+		"EVENT_TYPE_UNSPECIFIED":          0,
+		"EVENT_TYPE_SNAPSHOT":             1,
+		"EVENT_TYPE_TEMPERATURE_CHANGED":  2,
+		"EVENT_TYPE_APPLIED":              3,
+		"EVENT_TYPE_RESET":                4,
+		"EVENT_TYPE_SCHEDULE_TRANSITION":  5,
+		"EVENT_TYPE_PRESETS_CHANGED":      6,
+		"EVENT_TYPE_DISPLAYS_CHANGED":     7,
+		"EVENT_TYPE_APPLY_FAILED":         8,
+		"EVENT_TYPE_MOVIE_MODE_CHANGED":   9,
+		"EVENT_TYPE_SKIP_TONIGHT_CHANGED": 10,
+		"EVENT_TYPE_VACATION_CHANGED":     11,
+	}
+)
+
+func (x EventType) Enum() *EventType {
+	p := new(EventType)
+	*p = x
+	return p
+}
+
+func (x EventType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (EventType) Descriptor() protoreflect.EnumDescriptor {
+	return file_luznocturna_v1_nightlight_proto_enumTypes[0].Descriptor()
+}
+
+func (EventType) Type() protoreflect.EnumType {
+	return &file_luznocturna_v1_nightlight_proto_enumTypes[0]
+}
+
+func (x EventType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use EventType.Descriptor instead.
+func (EventType) EnumDescriptor() ([]byte, []int) {
+	return file_luznocturna_v1_nightlight_proto_rawDescGZIP(), []int{0}
+}
+
+// State es el estado del filtro.
+type State struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Temperatura seleccionada en Kelvin.
+	Temperature float64 `protobuf:"fixed64,1,opt,name=temperature,proto3" json:"temperature,omitempty"`
+	// Brillo relativo (1.0 = sin atenuación).
+	Brightness float64 `protobuf:"fixed64,2,opt,name=brightness,proto3" json:"brightness,omitempty"`
+	// Si el filtro está aplicado.
+	Active bool `protobuf:"varint,3,opt,name=active,proto3" json:"active,omitempty"`
+	// Límites de temperatura aceptados por SetTemperature.
+	MinTemperature float64 `protobuf:"fixed64,4,opt,name=min_temperature,json=minTemperature,proto3" json:"min_temperature,omitempty"`
+	MaxTemperature float64 `protobuf:"fixed64,5,opt,name=max_temperature,json=maxTemperature,proto3" json:"max_temperature,omitempty"`
+}
+
+func (x *State) Reset() {
+	*x = State{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_luznocturna_v1_nightlight_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *State) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*State) ProtoMessage() {}
+
+func (x *State) ProtoReflect() protoreflect.Message {
+	mi := &file_luznocturna_v1_nightlight_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use State.ProtoReflect.Descriptor instead.
+func (*State) Descriptor() ([]byte, []int) {
+	return file_luznocturna_v1_nightlight_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *State) GetTemperature() float64 {
+	if x != nil {
+		return x.Temperature
+	}
+	return 0
+}
+
+func (x *State) GetBrightness() float64 {
+	if x != nil {
+		return x.Brightness
+	}
+	return 0
+}
+
+func (x *State) GetActive() bool {
+	if x != nil {
+		return x.Active
+	}
+	return false
+}
+
+func (x *State) GetMinTemperature() float64 {
+	if x != nil {
+		return x.MinTemperature
+	}
+	return 0
+}
+
+func (x *State) GetMaxTemperature() float64 {
+	if x != nil {
+		return x.MaxTemperature
+	}
+	return 0
+}
+
+type GetStateRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *GetStateRequest) Reset() {
+	*x = GetStateRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_luznocturna_v1_nightlight_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetStateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStateRequest) ProtoMessage() {}
+
+func (x *GetStateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_luznocturna_v1_nightlight_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStateRequest.ProtoReflect.Descriptor instead.
+func (*GetStateRequest) Descriptor() ([]byte, []int) {
+	return file_luznocturna_v1_nightlight_proto_rawDescGZIP(), []int{1}
+}
+
+type GetStateResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	State *State `protobuf:"bytes,1,opt,name=state,proto3" json:"state,omitempty"`
+}
+
+func (x *GetStateResponse) Reset() {
+	*x = GetStateResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_luznocturna_v1_nightlight_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetStateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStateResponse) ProtoMessage() {}
+
+func (x *GetStateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_luznocturna_v1_nightlight_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStateResponse.ProtoReflect.Descriptor instead.
+func (*GetStateResponse) Descriptor() ([]byte, []int) {
+	return file_luznocturna_v1_nightlight_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetStateResponse) GetState() *State {
+	if x != nil {
+		return x.State
+	}
+	return nil
+}
+
+type SetTemperatureRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Temperatura en Kelvin; fuera de rango devuelve INVALID_ARGUMENT.
+	Temperature float64 `protobuf:"fixed64,1,opt,name=temperature,proto3" json:"temperature,omitempty"`
+	// Aplicar la temperatura además de seleccionarla.
+	Apply bool `protobuf:"varint,2,opt,name=apply,proto3" json:"apply,omitempty"`
+}
+
+func (x *SetTemperatureRequest) Reset() {
+	*x = SetTemperatureRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_luznocturna_v1_nightlight_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SetTemperatureRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetTemperatureRequest) ProtoMessage() {}
+
+func (x *SetTemperatureRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_luznocturna_v1_nightlight_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetTemperatureRequest.ProtoReflect.Descriptor instead.
+func (*SetTemperatureRequest) Descriptor() ([]byte, []int) {
+	return file_luznocturna_v1_nightlight_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *SetTemperatureRequest) GetTemperature() float64 {
+	if x != nil {
+		return x.Temperature
+	}
+	return 0
+}
+
+func (x *SetTemperatureRequest) GetApply() bool {
+	if x != nil {
+		return x.Apply
+	}
+	return false
+}
+
+type SetTemperatureResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	State *State `protobuf:"bytes,1,opt,name=state,proto3" json:"state,omitempty"`
+}
+
+func (x *SetTemperatureResponse) Reset() {
+	*x = SetTemperatureResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_luznocturna_v1_nightlight_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SetTemperatureResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetTemperatureResponse) ProtoMessage() {}
+
+func (x *SetTemperatureResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_luznocturna_v1_nightlight_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetTemperatureResponse.ProtoReflect.Descriptor instead.
+func (*SetTemperatureResponse) Descriptor() ([]byte, []int) {
+	return file_luznocturna_v1_nightlight_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *SetTemperatureResponse) GetState() *State {
+	if x != nil {
+		return x.State
+	}
+	return nil
+}
+
+type WatchRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *WatchRequest) Reset() {
+	*x = WatchRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_luznocturna_v1_nightlight_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchRequest) ProtoMessage() {}
+
+func (x *WatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_luznocturna_v1_nightlight_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchRequest.ProtoReflect.Descriptor instead.
+func (*WatchRequest) Descriptor() ([]byte, []int) {
+	return file_luznocturna_v1_nightlight_proto_rawDescGZIP(), []int{5}
+}
+
+type WatchResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Type  EventType `protobuf:"varint,1,opt,name=type,proto3,enum=luznocturna.v1.EventType" json:"type,omitempty"`
+	State *State    `protobuf:"bytes,2,opt,name=state,proto3" json:"state,omitempty"`
+	// Origen del cambio: "manual", "preset", "scheduler"...
+	Source string `protobuf:"bytes,3,opt,name=source,proto3" json:"source,omitempty"`
+	// Motivo del fallo (solo en EVENT_TYPE_APPLY_FAILED).
+	Error string `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (x *WatchResponse) Reset() {
+	*x = WatchResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_luznocturna_v1_nightlight_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchResponse) ProtoMessage() {}
+
+func (x *WatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_luznocturna_v1_nightlight_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchResponse.ProtoReflect.Descriptor instead.
+func (*WatchResponse) Descriptor() ([]byte, []int) {
+	return file_luznocturna_v1_nightlight_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *WatchResponse) GetType() EventType {
+	if x != nil {
+		return x.Type
+	}
+	return EventType_EVENT_TYPE_UNSPECIFIED
+}
+
+func (x *WatchResponse) GetState() *State {
+	if x != nil {
+		return x.State
+	}
+	return nil
+}
+
+func (x *WatchResponse) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *WatchResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+var File_luznocturna_v1_nightlight_proto protoreflect.FileDescriptor
+
+var file_luznocturna_v1_nightlight_proto_rawDesc = []byte{
+	0x0a, 0x1f, 0x6c, 0x75, 0x7a, 0x6e, 0x6f, 0x63, 0x74, 0x75, 0x72, 0x6e, 0x61, 0x2f, 0x76, 0x31,
+	0x2f, 0x6e, 0x69, 0x67, 0x68, 0x74, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x12, 0x0e, 0x6c, 0x75, 0x7a, 0x6e, 0x6f, 0x63, 0x74, 0x75, 0x72, 0x6e, 0x61, 0x2e, 0x76,
+	0x31, 0x22, 0xb3, 0x01, 0x0a, 0x05, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x74,
+	0x65, 0x6d, 0x70, 0x65, 0x72, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01,
+	0x52, 0x0b, 0x74, 0x65, 0x6d, 0x70, 0x65, 0x72, 0x61, 0x74, 0x75, 0x72, 0x65, 0x12, 0x1e, 0x0a,
+	0x0a, 0x62, 0x72, 0x69, 0x67, 0x68, 0x74, 0x6e, 0x65, 0x73, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x01, 0x52, 0x0a, 0x62, 0x72, 0x69, 0x67, 0x68, 0x74, 0x6e, 0x65, 0x73, 0x73, 0x12, 0x16, 0x0a,
+	0x06, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x61,
+	0x63, 0x74, 0x69, 0x76, 0x65, 0x12, 0x27, 0x0a, 0x0f, 0x6d, 0x69, 0x6e, 0x5f, 0x74, 0x65, 0x6d,
+	0x70, 0x65, 0x72, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0e,
+	0x6d, 0x69, 0x6e, 0x54, 0x65, 0x6d, 0x70, 0x65, 0x72, 0x61, 0x74, 0x75, 0x72, 0x65, 0x12, 0x27,
+	0x0a, 0x0f, 0x6d, 0x61, 0x78, 0x5f, 0x74, 0x65, 0x6d, 0x70, 0x65, 0x72, 0x61, 0x74, 0x75, 0x72,
+	0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0e, 0x6d, 0x61, 0x78, 0x54, 0x65, 0x6d, 0x70,
+	0x65, 0x72, 0x61, 0x74, 0x75, 0x72, 0x65, 0x22, 0x11, 0x0a, 0x0f, 0x47, 0x65, 0x74, 0x53, 0x74,
+	0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x3f, 0x0a, 0x10, 0x47, 0x65,
+	0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2b,
+	0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e,
+	0x6c, 0x75, 0x7a, 0x6e, 0x6f, 0x63, 0x74, 0x75, 0x72, 0x6e, 0x61, 0x2e, 0x76, 0x31, 0x2e, 0x53,
+	0x74, 0x61, 0x74, 0x65, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x22, 0x4f, 0x0a, 0x15, 0x53,
+	0x65, 0x74, 0x54, 0x65, 0x6d, 0x70, 0x65, 0x72, 0x61, 0x74, 0x75, 0x72, 0x65, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x20, 0x0a, 0x0b, 0x74, 0x65, 0x6d, 0x70, 0x65, 0x72, 0x61, 0x74,
+	0x75, 0x72, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0b, 0x74, 0x65, 0x6d, 0x70, 0x65,
+	0x72, 0x61, 0x74, 0x75, 0x72, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x61, 0x70, 0x70, 0x6c, 0x79, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x61, 0x70, 0x70, 0x6c, 0x79, 0x22, 0x45, 0x0a, 0x16,
+	0x53, 0x65, 0x74, 0x54, 0x65, 0x6d, 0x70, 0x65, 0x72, 0x61, 0x74, 0x75, 0x72, 0x65, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2b, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x6c, 0x75, 0x7a, 0x6e, 0x6f, 0x63, 0x74, 0x75,
+	0x72, 0x6e, 0x61, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x05, 0x73, 0x74,
+	0x61, 0x74, 0x65, 0x22, 0x0e, 0x0a, 0x0c, 0x57, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x22, 0x99, 0x01, 0x0a, 0x0d, 0x57, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2d, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x19, 0x2e, 0x6c, 0x75, 0x7a, 0x6e, 0x6f, 0x63, 0x74, 0x75, 0x72, 0x6e,
+	0x61, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04,
+	0x74, 0x79, 0x70, 0x65, 0x12, 0x2b, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x6c, 0x75, 0x7a, 0x6e, 0x6f, 0x63, 0x74, 0x75, 0x72, 0x6e,
+	0x61, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74,
+	0x65, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72,
+	0x6f, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x2a,
+	0xfd, 0x02, 0x0a, 0x09, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1a, 0x0a,
+	0x16, 0x45, 0x56, 0x45, 0x4e, 0x54, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x55, 0x4e, 0x53, 0x50,
+	0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x17, 0x0a, 0x13, 0x45, 0x56, 0x45,
+	0x4e, 0x54, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x53, 0x4e, 0x41, 0x50, 0x53, 0x48, 0x4f, 0x54,
+	0x10, 0x01, 0x12, 0x22, 0x0a, 0x1e, 0x45, 0x56, 0x45, 0x4e, 0x54, 0x5f, 0x54, 0x59, 0x50, 0x45,
+	0x5f, 0x54, 0x45, 0x4d, 0x50, 0x45, 0x52, 0x41, 0x54, 0x55, 0x52, 0x45, 0x5f, 0x43, 0x48, 0x41,
+	0x4e, 0x47, 0x45, 0x44, 0x10, 0x02, 0x12, 0x16, 0x0a, 0x12, 0x45, 0x56, 0x45, 0x4e, 0x54, 0x5f,
+	0x54, 0x59, 0x50, 0x45, 0x5f, 0x41, 0x50, 0x50, 0x4c, 0x49, 0x45, 0x44, 0x10, 0x03, 0x12, 0x14,
+	0x0a, 0x10, 0x45, 0x56, 0x45, 0x4e, 0x54, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x52, 0x45, 0x53,
+	0x45, 0x54, 0x10, 0x04, 0x12, 0x22, 0x0a, 0x1e, 0x45, 0x56, 0x45, 0x4e, 0x54, 0x5f, 0x54, 0x59,
+	0x50, 0x45, 0x5f, 0x53, 0x43, 0x48, 0x45, 0x44, 0x55, 0x4c, 0x45, 0x5f, 0x54, 0x52, 0x41, 0x4e,
+	0x53, 0x49, 0x54, 0x49, 0x4f, 0x4e, 0x10, 0x05, 0x12, 0x1e, 0x0a, 0x1a, 0x45, 0x56, 0x45, 0x4e,
+	0x54, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x50, 0x52, 0x45, 0x53, 0x45, 0x54, 0x53, 0x5f, 0x43,
+	0x48, 0x41, 0x4e, 0x47, 0x45, 0x44, 0x10, 0x06, 0x12, 0x1f, 0x0a, 0x1b, 0x45, 0x56, 0x45, 0x4e,
+	0x54, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x44, 0x49, 0x53, 0x50, 0x4c, 0x41, 0x59, 0x53, 0x5f,
+	0x43, 0x48, 0x41, 0x4e, 0x47, 0x45, 0x44, 0x10, 0x07, 0x12, 0x1b, 0x0a, 0x17, 0x45, 0x56, 0x45,
+	0x4e, 0x54, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x41, 0x50, 0x50, 0x4c, 0x59, 0x5f, 0x46, 0x41,
+	0x49, 0x4c, 0x45, 0x44, 0x10, 0x08, 0x12, 0x21, 0x0a, 0x1d, 0x45, 0x56, 0x45, 0x4e, 0x54, 0x5f,
+	0x54, 0x59, 0x50, 0x45, 0x5f, 0x4d, 0x4f, 0x56, 0x49, 0x45, 0x5f, 0x4d, 0x4f, 0x44, 0x45, 0x5f,
+	0x43, 0x48, 0x41, 0x4e, 0x47, 0x45, 0x44, 0x10, 0x09, 0x12, 0x23, 0x0a, 0x1f, 0x45, 0x56, 0x45,
+	0x4e, 0x54, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x53, 0x4b, 0x49, 0x50, 0x5f, 0x54, 0x4f, 0x4e,
+	0x49, 0x47, 0x48, 0x54, 0x5f, 0x43, 0x48, 0x41, 0x4e, 0x47, 0x45, 0x44, 0x10, 0x0a, 0x12, 0x1f,
+	0x0a, 0x1b, 0x45, 0x56, 0x45, 0x4e, 0x54, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x56, 0x41, 0x43,
+	0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x43, 0x48, 0x41, 0x4e, 0x47, 0x45, 0x44, 0x10, 0x0b, 0x32,
+	0x8b, 0x02, 0x0a, 0x11, 0x4e, 0x69, 0x67, 0x68, 0x74, 0x4c, 0x69, 0x67, 0x68, 0x74, 0x53, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x4d, 0x0a, 0x08, 0x47, 0x65, 0x74, 0x53, 0x74, 0x61, 0x74,
+	0x65, 0x12, 0x1f, 0x2e, 0x6c, 0x75, 0x7a, 0x6e, 0x6f, 0x63, 0x74, 0x75, 0x72, 0x6e, 0x61, 0x2e,
+	0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x20, 0x2e, 0x6c, 0x75, 0x7a, 0x6e, 0x6f, 0x63, 0x74, 0x75, 0x72, 0x6e, 0x61,
+	0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x5f, 0x0a, 0x0e, 0x53, 0x65, 0x74, 0x54, 0x65, 0x6d, 0x70, 0x65,
+	0x72, 0x61, 0x74, 0x75, 0x72, 0x65, 0x12, 0x25, 0x2e, 0x6c, 0x75, 0x7a, 0x6e, 0x6f, 0x63, 0x74,
+	0x75, 0x72, 0x6e, 0x61, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x65, 0x74, 0x54, 0x65, 0x6d, 0x70, 0x65,
+	0x72, 0x61, 0x74, 0x75, 0x72, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x26, 0x2e,
+	0x6c, 0x75, 0x7a, 0x6e, 0x6f, 0x63, 0x74, 0x75, 0x72, 0x6e, 0x61, 0x2e, 0x76, 0x31, 0x2e, 0x53,
+	0x65, 0x74, 0x54, 0x65, 0x6d, 0x70, 0x65, 0x72, 0x61, 0x74, 0x75, 0x72, 0x65, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x46, 0x0a, 0x05, 0x57, 0x61, 0x74, 0x63, 0x68, 0x12, 0x1c,
+	0x2e, 0x6c, 0x75, 0x7a, 0x6e, 0x6f, 0x63, 0x74, 0x75, 0x72, 0x6e, 0x61, 0x2e, 0x76, 0x31, 0x2e,
+	0x57, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x6c,
+	0x75, 0x7a, 0x6e, 0x6f, 0x63, 0x74, 0x75, 0x72, 0x6e, 0x61, 0x2e, 0x76, 0x31, 0x2e, 0x57, 0x61,
+	0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x30, 0x01, 0x42, 0x3f, 0x5a,
+	0x3d, 0x6c, 0x75, 0x7a, 0x6e, 0x6f, 0x63, 0x74, 0x75, 0x72, 0x6e, 0x61, 0x2f, 0x6c, 0x75, 0x7a,
+	0x2d, 0x6e, 0x6f, 0x63, 0x74, 0x75, 0x72, 0x6e, 0x61, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x61, 0x70,
+	0x69, 0x2f, 0x6c, 0x75, 0x7a, 0x6e, 0x6f, 0x63, 0x74, 0x75, 0x72, 0x6e, 0x61, 0x2f, 0x76, 0x31,
+	0x3b, 0x6c, 0x75, 0x7a, 0x6e, 0x6f, 0x63, 0x74, 0x75, 0x72, 0x6e, 0x61, 0x76, 0x31, 0x62, 0x06,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_luznocturna_v1_nightlight_proto_rawDescOnce sync.Once
+	file_luznocturna_v1_nightlight_proto_rawDescData = file_luznocturna_v1_nightlight_proto_rawDesc
+)
+
+func file_luznocturna_v1_nightlight_proto_rawDescGZIP() []byte {
+	file_luznocturna_v1_nightlight_proto_rawDescOnce.Do(func() {
+		file_luznocturna_v1_nightlight_proto_rawDescData = protoimpl.X.CompressGZIP(file_luznocturna_v1_nightlight_proto_rawDescData)
+	})
+	return file_luznocturna_v1_nightlight_proto_rawDescData
+}
+
+var file_luznocturna_v1_nightlight_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_luznocturna_v1_nightlight_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_luznocturna_v1_nightlight_proto_goTypes = []any{
+	(EventType)(0),                 // 0: luznocturna.v1.EventType
+	(*State)(nil),                  // 1: luznocturna.v1.State
+	(*GetStateRequest)(nil),        // 2: luznocturna.v1.GetStateRequest
+	(*GetStateResponse)(nil),       // 3: luznocturna.v1.GetStateResponse
+	(*SetTemperatureRequest)(nil),  // 4: luznocturna.v1.SetTemperatureRequest
+	(*SetTemperatureResponse)(nil), // 5: luznocturna.v1.SetTemperatureResponse
+	(*WatchRequest)(nil),           // 6: luznocturna.v1.WatchRequest
+	(*WatchResponse)(nil),          // 7: luznocturna.v1.WatchResponse
+}
+var file_luznocturna_v1_nightlight_proto_depIdxs = []int32{
+	1, // 0: luznocturna.v1.GetStateResponse.state:type_name -> luznocturna.v1.State
+	1, // 1: luznocturna.v1.SetTemperatureResponse.state:type_name -> luznocturna.v1.State
+	0, // 2: luznocturna.v1.WatchResponse.type:type_name -> luznocturna.v1.EventType
+	1, // 3: luznocturna.v1.WatchResponse.state:type_name -> luznocturna.v1.State
+	2, // 4: luznocturna.v1.NightLightService.GetState:input_type -> luznocturna.v1.GetStateRequest
+	4, // 5: luznocturna.v1.NightLightService.SetTemperature:input_type -> luznocturna.v1.SetTemperatureRequest
+	6, // 6: luznocturna.v1.NightLightService.Watch:input_type -> luznocturna.v1.WatchRequest
+	3, // 7: luznocturna.v1.NightLightService.GetState:output_type -> luznocturna.v1.GetStateResponse
+	5, // 8: luznocturna.v1.NightLightService.SetTemperature:output_type -> luznocturna.v1.SetTemperatureResponse
+	7, // 9: luznocturna.v1.NightLightService.Watch:output_type -> luznocturna.v1.WatchResponse
+	7, // [7:10] is the sub-list for method output_type
+	4, // [4:7] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_luznocturna_v1_nightlight_proto_init() }
+func file_luznocturna_v1_nightlight_proto_init() {
+	if File_luznocturna_v1_nightlight_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_luznocturna_v1_nightlight_proto_msgTypes[0].Exporter = func(v any, i int) any {
+			switch v := v.(*State); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_luznocturna_v1_nightlight_proto_msgTypes[1].Exporter = func(v any, i int) any {
+			switch v := v.(*GetStateRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_luznocturna_v1_nightlight_proto_msgTypes[2].Exporter = func(v any, i int) any {
+			switch v := v.(*GetStateResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_luznocturna_v1_nightlight_proto_msgTypes[3].Exporter = func(v any, i int) any {
+			switch v := v.(*SetTemperatureRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_luznocturna_v1_nightlight_proto_msgTypes[4].Exporter = func(v any, i int) any {
+			switch v := v.(*SetTemperatureResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_luznocturna_v1_nightlight_proto_msgTypes[5].Exporter = func(v any, i int) any {
+			switch v := v.(*WatchRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_luznocturna_v1_nightlight_proto_msgTypes[6].Exporter = func(v any, i int) any {
+			switch v := v.(*WatchResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_luznocturna_v1_nightlight_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   7,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_luznocturna_v1_nightlight_proto_goTypes,
+		DependencyIndexes: file_luznocturna_v1_nightlight_proto_depIdxs,
+		EnumInfos:         file_luznocturna_v1_nightlight_proto_enumTypes,
+		MessageInfos:      file_luznocturna_v1_nightlight_proto_msgTypes,
+	}.Build()
+	File_luznocturna_v1_nightlight_proto = out.File
+	file_luznocturna_v1_nightlight_proto_rawDesc = nil
+	file_luznocturna_v1_nightlight_proto_goTypes = nil
+	file_luznocturna_v1_nightlight_proto_depIdxs = nil
+}
@@ -0,0 +1,223 @@
+// API gRPC de Luz Nocturna, versión 1.
+//
+// Alternativa a D-Bus para automatizaciones que ya hablan gRPC. Los
+// cambios incompatibles irán a un paquete luznocturna.v2; en v1 solo se
+// añaden campos y métodos nuevos.
+//
+// Para regenerar el código Go (protoc-gen-go y protoc-gen-go-grpc):
+//
+//   go generate ./pkg/api/...
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: luznocturna/v1/nightlight.proto
+
+package luznocturnav1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	NightLightService_GetState_FullMethodName       = "/luznocturna.v1.NightLightService/GetState"
+	NightLightService_SetTemperature_FullMethodName = "/luznocturna.v1.NightLightService/SetTemperature"
+	NightLightService_Watch_FullMethodName          = "/luznocturna.v1.NightLightService/Watch"
+)
+
+// NightLightServiceClient is the client API for NightLightService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// NightLightService controla la instancia de Luz Nocturna en ejecución.
+type NightLightServiceClient interface {
+	// GetState devuelve la temperatura seleccionada y si el filtro está activo.
+	GetState(ctx context.Context, in *GetStateRequest, opts ...grpc.CallOption) (*GetStateResponse, error)
+	// SetTemperature selecciona una temperatura y, si se pide, la aplica.
+	SetTemperature(ctx context.Context, in *SetTemperatureRequest, opts ...grpc.CallOption) (*SetTemperatureResponse, error)
+	// Watch envía el estado actual y después cada evento del controlador
+	// hasta que el cliente cancela la llamada.
+	Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchResponse], error)
+}
+
+type nightLightServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewNightLightServiceClient(cc grpc.ClientConnInterface) NightLightServiceClient {
+	return &nightLightServiceClient{cc}
+}
+
+func (c *nightLightServiceClient) GetState(ctx context.Context, in *GetStateRequest, opts ...grpc.CallOption) (*GetStateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetStateResponse)
+	err := c.cc.Invoke(ctx, NightLightService_GetState_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nightLightServiceClient) SetTemperature(ctx context.Context, in *SetTemperatureRequest, opts ...grpc.CallOption) (*SetTemperatureResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetTemperatureResponse)
+	err := c.cc.Invoke(ctx, NightLightService_SetTemperature_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nightLightServiceClient) Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &NightLightService_ServiceDesc.Streams[0], NightLightService_Watch_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchRequest, WatchResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type NightLightService_WatchClient = grpc.ServerStreamingClient[WatchResponse]
+
+// NightLightServiceServer is the server API for NightLightService service.
+// All implementations must embed UnimplementedNightLightServiceServer
+// for forward compatibility.
+//
+// NightLightService controla la instancia de Luz Nocturna en ejecución.
+type NightLightServiceServer interface {
+	// GetState devuelve la temperatura seleccionada y si el filtro está activo.
+	GetState(context.Context, *GetStateRequest) (*GetStateResponse, error)
+	// SetTemperature selecciona una temperatura y, si se pide, la aplica.
+	SetTemperature(context.Context, *SetTemperatureRequest) (*SetTemperatureResponse, error)
+	// Watch envía el estado actual y después cada evento del controlador
+	// hasta que el cliente cancela la llamada.
+	Watch(*WatchRequest, grpc.ServerStreamingServer[WatchResponse]) error
+	mustEmbedUnimplementedNightLightServiceServer()
+}
+
+// UnimplementedNightLightServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedNightLightServiceServer struct{}
+
+func (UnimplementedNightLightServiceServer) GetState(context.Context, *GetStateRequest) (*GetStateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetState not implemented")
+}
+func (UnimplementedNightLightServiceServer) SetTemperature(context.Context, *SetTemperatureRequest) (*SetTemperatureResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetTemperature not implemented")
+}
+func (UnimplementedNightLightServiceServer) Watch(*WatchRequest, grpc.ServerStreamingServer[WatchResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method Watch not implemented")
+}
+func (UnimplementedNightLightServiceServer) mustEmbedUnimplementedNightLightServiceServer() {}
+func (UnimplementedNightLightServiceServer) testEmbeddedByValue()                           {}
+
+// UnsafeNightLightServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to NightLightServiceServer will
+// result in compilation errors.
+type UnsafeNightLightServiceServer interface {
+	mustEmbedUnimplementedNightLightServiceServer()
+}
+
+func RegisterNightLightServiceServer(s grpc.ServiceRegistrar, srv NightLightServiceServer) {
+	// If the following call pancis, it indicates UnimplementedNightLightServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&NightLightService_ServiceDesc, srv)
+}
+
+func _NightLightService_GetState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NightLightServiceServer).GetState(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NightLightService_GetState_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NightLightServiceServer).GetState(ctx, req.(*GetStateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NightLightService_SetTemperature_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetTemperatureRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NightLightServiceServer).SetTemperature(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NightLightService_SetTemperature_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NightLightServiceServer).SetTemperature(ctx, req.(*SetTemperatureRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NightLightService_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(NightLightServiceServer).Watch(m, &grpc.GenericServerStream[WatchRequest, WatchResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type NightLightService_WatchServer = grpc.ServerStreamingServer[WatchResponse]
+
+// NightLightService_ServiceDesc is the grpc.ServiceDesc for NightLightService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var NightLightService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "luznocturna.v1.NightLightService",
+	HandlerType: (*NightLightServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetState",
+			Handler:    _NightLightService_GetState_Handler,
+		},
+		{
+			MethodName: "SetTemperature",
+			Handler:    _NightLightService_SetTemperature_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Watch",
+			Handler:       _NightLightService_Watch_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "luznocturna/v1/nightlight.proto",
+}
@@ -0,0 +1,107 @@
+package nightlight
+
+import (
+	"luznocturna/luz-nocturna/internal/system"
+)
+
+/**
+ * Backend - Operaciones de gamma sobre el display
+ *
+ * NewBackend devuelve la implementación real (xrandr en X11; GNOME, KDE,
+ * gammastep, wlsunset, DDC/CI... en Wayland) y NewFakeBackend una
+ * simulada para pruebas. También se puede pasar una implementación
+ * propia en Options.Backend.
+ *
+ * @interface {Backend}
+ */
+type Backend interface {
+	ApplyTemperatureWithBrightness(temperature, brightness float64) error
+	Reset() error
+	SetExcludedDisplays(displays []string)
+	VerifyGamma(temperature, brightness float64) (bool, error)
+	GetDisplays() []string
+	GetHDRDisplays() []string
+	RefreshDisplays() []string
+	RefreshCapabilities()
+	GetBackend() string
+	GetProtocol() string
+	Close()
+}
+
+// Protocolos de display admitidos en BackendOptions.Protocol
+const (
+	ProtocolX11     = "x11"
+	ProtocolWayland = "wayland"
+)
+
+// BackendOptions agrupa las opciones de creación del backend real
+type BackendOptions struct {
+	DryRun   bool   // Registrar los comandos de gamma sin tocar el display
+	Protocol string // ProtocolX11 o ProtocolWayland para no detectarlo ("" detecta)
+}
+
+/**
+ * NewBackend - Crea el backend de gamma del sistema
+ *
+ * Detecta el protocolo, los displays y las herramientas disponibles, y
+ * toma el control exclusivo de la gamma (desactiva GNOME Night Light o
+ * KDE Night Color mientras esté abierto). Hay que llamar a Close al
+ * terminar.
+ *
+ * @param {BackendOptions} opts - Opciones de creación
+ * @returns {Backend} Backend listo para usar
+ * @example
+ *   backend := nightlight.NewBackend(nightlight.BackendOptions{})
+ *   defer backend.Close()
+ *   backend.ApplyTemperatureWithBrightness(3400, 1.0)
+ */
+func NewBackend(opts BackendOptions) Backend {
+	return system.NewGammaManagerWithOptions(system.GammaOptions{DryRun: opts.DryRun, Protocol: opts.Protocol})
+}
+
+// NewFakeBackend crea un backend simulado que solo recuerda el último estado (para pruebas)
+func NewFakeBackend(displays ...string) Backend {
+	return system.NewFakeBackend(displays...)
+}
+
+// BackendInfo describe el entorno de display detectado
+type BackendInfo struct {
+	Protocol    string   // ProtocolX11 o ProtocolWayland
+	Displays    []string // Salidas detectadas
+	HDRDisplays []string // Salidas con HDR activo (solo Wayland)
+	Tools       []string // Herramientas externas disponibles (xrandr, gammastep, ddcutil...)
+}
+
+/**
+ * DetectBackend - Detecta el protocolo, los displays y las herramientas disponibles
+ *
+ * Usa un backend en modo dry-run, así que no modifica la gamma ni
+ * desactiva la luz nocturna del escritorio.
+ *
+ * @returns {BackendInfo} Entorno detectado
+ * @example
+ *   info := nightlight.DetectBackend()
+ *   fmt.Println(info.Protocol, info.Displays)
+ */
+func DetectBackend() BackendInfo {
+	gm := system.NewGammaManagerWithOptions(system.GammaOptions{DryRun: true})
+	defer gm.Close()
+
+	info := BackendInfo{
+		Protocol:    gm.GetProtocol(),
+		Displays:    gm.GetDisplays(),
+		HDRDisplays: gm.GetHDRDisplays(),
+	}
+	names, available := gm.GetCapabilities().Tools()
+	for _, name := range names {
+		if available[name] {
+			info.Tools = append(info.Tools, name)
+		}
+	}
+	return info
+}
+
+// TemperatureToRGB convierte una temperatura en Kelvin a los factores RGB de gamma (0-1)
+func TemperatureToRGB(temperature float64) (r, g, b float64) {
+	return system.TemperatureToRGB(temperature)
+}
@@ -0,0 +1,195 @@
+// Package nightlight permite integrar el control de temperatura de color de
+// Luz Nocturna en otros programas (barras, gestores de ventanas, daemons)
+// sin depender de Fyne.
+//
+// El Controller comparte la configuración (config.json), el historial y la
+// programación automática con la aplicación; Backend y DetectBackend dan
+// acceso directo a los backends de gamma y a su detección; Schedule calcula
+// la temperatura de una programación sin aplicarla.
+package nightlight
+
+import (
+	"io"
+	"time"
+
+	"luznocturna/luz-nocturna/internal/controllers"
+	"luznocturna/luz-nocturna/internal/logging"
+)
+
+// EventType identifica el tipo de cambio de estado publicado por el Controller
+type EventType string
+
+// Tipos de eventos que reciben los suscriptores
+const (
+	EventTemperatureChanged = EventType(controllers.EventTemperatureChanged) // Temperatura seleccionada (aún sin aplicar)
+	EventApplied            = EventType(controllers.EventApplied)            // Filtro aplicado al display
+	EventReset              = EventType(controllers.EventReset)              // Gamma restaurada a valores normales
+	EventScheduleTransition = EventType(controllers.EventScheduleTransition) // El programador aplicó una temperatura
+	EventPresetsChanged     = EventType(controllers.EventPresetsChanged)     // Lista de presets modificada
+	EventDisplaysChanged    = EventType(controllers.EventDisplaysChanged)    // Displays incluidos en el filtro modificados
+	EventApplyFailed        = EventType(controllers.EventApplyFailed)        // Un cambio automático no se pudo aplicar
+	EventMovieModeChanged   = EventType(controllers.EventMovieModeChanged)   // Modo película activado o terminado
+	EventSkipTonightChanged = EventType(controllers.EventSkipTonightChanged) // Noche de hoy omitida o reanudada
+	EventVacationChanged    = EventType(controllers.EventVacationChanged)    // Período de vacaciones cambiado
+)
+
+// Event describe un cambio de estado del Controller
+type Event struct {
+	Type        EventType
+	Temperature float64 // Temperatura en Kelvin tras el cambio
+	Active      bool    // Si el filtro queda activo
+	Source      string  // Origen del cambio: "manual", "preset", "scheduler", "dbus"...
+	Err         error   // Motivo del fallo (solo en EventApplyFailed)
+}
+
+// Options agrupa las opciones de creación del Controller
+type Options struct {
+	Backend     Backend // Backend de gamma a usar; nil para detectar el del sistema
+	DryRun      bool    // Registrar los comandos de gamma sin tocar el display (si Backend es nil)
+	Protocol    string  // ProtocolX11 o ProtocolWayland para no detectarlo (si Backend es nil)
+	ResetOnExit bool    // Restaurar la gamma en Close aunque la configuración no lo pida
+}
+
+/**
+ * Controller - Control de temperatura de color para programas externos
+ *
+ * Envuelve el controlador de la aplicación: cola de aplicación, historial,
+ * programación automática y vigilante de gamma funcionan igual que en la
+ * interfaz gráfica. Sus métodos deben llamarse desde una sola goroutine.
+ *
+ * @struct {Controller}
+ * @property {*controllers.NightLightController} controller - Controlador de la aplicación
+ */
+type Controller struct {
+	controller *controllers.NightLightController
+}
+
+/**
+ * New - Crea un Controller con la configuración guardada del usuario
+ *
+ * Si la programación automática está habilitada en la configuración,
+ * empieza a aplicarse de inmediato. Hay que llamar a Close al terminar.
+ *
+ * @param {Options} opts - Opciones de creación
+ * @returns {*Controller} Controller listo para usar
+ * @example
+ *   light := nightlight.New(nightlight.Options{})
+ *   defer light.Close()
+ *   light.SetTemperature(3400)
+ *   light.Apply()
+ */
+func New(opts Options) *Controller {
+	controller := controllers.NewNightLightControllerWithOptions(controllers.ControllerOptions{
+		DryRun:      opts.DryRun,
+		ResetOnExit: opts.ResetOnExit,
+		Backend:     opts.Backend,
+		Protocol:    opts.Protocol,
+	})
+	return &Controller{controller: controller}
+}
+
+// Temperature devuelve la temperatura seleccionada en Kelvin
+func (c *Controller) Temperature() float64 {
+	return c.controller.GetConfig().Temperature
+}
+
+// TemperatureRange devuelve el rango de temperatura admitido en Kelvin
+func (c *Controller) TemperatureRange() (min, max float64) {
+	return c.controller.GetTemperatureRange()
+}
+
+// SetTemperature selecciona y guarda una temperatura; si el filtro está activo se aplica al momento
+func (c *Controller) SetTemperature(temperature float64) {
+	c.controller.UpdateTemperature(temperature)
+}
+
+// IsActive indica si el filtro está aplicado
+func (c *Controller) IsActive() bool {
+	return c.controller.GetConfig().IsActive
+}
+
+// Apply aplica la temperatura seleccionada al display
+func (c *Controller) Apply() error {
+	return c.controller.ApplyNightLight()
+}
+
+// Reset restaura la gamma normal
+func (c *Controller) Reset() error {
+	return c.controller.ResetNightLight()
+}
+
+// Toggle activa el filtro si está desactivado y viceversa
+func (c *Controller) Toggle() error {
+	return c.controller.ToggleNightLight()
+}
+
+// Displays devuelve las salidas detectadas por el backend
+func (c *Controller) Displays() []string {
+	return c.controller.GetDisplays()
+}
+
+// Protocol devuelve el protocolo de display en uso (ProtocolX11 o ProtocolWayland)
+func (c *Controller) Protocol() string {
+	return c.controller.GetProtocol()
+}
+
+// BackendName devuelve el método que aplicó la última temperatura ("xrandr", "GNOME Mutter"...)
+func (c *Controller) BackendName() string {
+	return c.controller.GetBackend()
+}
+
+// ScheduleEnabled indica si la programación automática está habilitada
+func (c *Controller) ScheduleEnabled() bool {
+	return c.controller.IsScheduleEnabled()
+}
+
+// EnableSchedule habilita o deshabilita la programación automática y lo guarda en la configuración
+func (c *Controller) EnableSchedule(enabled bool) {
+	c.controller.EnableSchedule(enabled)
+}
+
+// Schedule devuelve la programación automática guardada
+func (c *Controller) Schedule() Schedule {
+	return scheduleFromConfig(c.controller.GetScheduleConfig())
+}
+
+// NextChange describe el próximo cambio programado: descripción, temperatura y tiempo restante
+func (c *Controller) NextChange() (string, float64, time.Duration) {
+	return c.controller.GetNextScheduleChange()
+}
+
+/**
+ * Subscribe - Registra un suscriptor para los cambios de estado
+ *
+ * El suscriptor se invoca en la goroutine que publica el cambio (la del
+ * programador, la de la cola de aplicación...), así que no debe bloquear.
+ *
+ * @param {func(Event)} handler - Función que recibe cada evento
+ * @returns {func()} Función que cancela la suscripción
+ * @example
+ *   unsubscribe := light.Subscribe(func(e nightlight.Event) {
+ *       fmt.Printf("%s: %.0fK\n", e.Type, e.Temperature)
+ *   })
+ *   defer unsubscribe()
+ */
+func (c *Controller) Subscribe(handler func(Event)) func() {
+	return c.controller.Subscribe(func(event controllers.Event) {
+		handler(Event{
+			Type:        EventType(event.Type),
+			Temperature: event.Temperature,
+			Active:      event.Active,
+			Source:      event.Source,
+			Err:         event.Err,
+		})
+	})
+}
+
+// Close detiene la programación y el vigilante, restaura la gamma si se configuró así y libera el backend
+func (c *Controller) Close() {
+	c.controller.Shutdown()
+}
+
+// SetLogOutput redirige los registros de la biblioteca (os.Stdout por defecto; io.Discard los silencia)
+func SetLogOutput(w io.Writer) {
+	logging.SetOutput(w)
+}
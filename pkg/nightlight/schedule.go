@@ -0,0 +1,101 @@
+package nightlight
+
+import (
+	"time"
+
+	"luznocturna/luz-nocturna/internal/models"
+)
+
+/**
+ * Schedule - Programación automática de la temperatura
+ *
+ * Con FollowSun la temperatura sigue la elevación del sol en Latitude y
+ * Longitude, hasta el crepúsculo Twilight y con los bordes desplazados
+ * SunsetOffset y SunriseOffset; si no, se usan las horas fijas Start y
+ * End ("HH:MM").
+ *
+ * @struct {Schedule}
+ */
+type Schedule struct {
+	Start      string        // Inicio del filtro nocturno ("HH:MM")
+	End        string        // Fin del filtro nocturno ("HH:MM")
+	NightTemp  float64       // Temperatura nocturna en Kelvin
+	DayTemp    float64       // Temperatura diurna en Kelvin
+	Transition time.Duration // Duración de las transiciones
+	FollowSun  bool          // Seguir la elevación del sol en lugar de las horas fijas
+	Latitude   float64       // Latitud para FollowSun (positiva al norte)
+	Longitude  float64       // Longitud para FollowSun (positiva al este)
+
+	Twilight      string        // Límite de la noche con FollowSun: TwilightCivil (por defecto), TwilightNautical o TwilightAstronomical
+	SunsetOffset  time.Duration // Desplazamiento del borde de la tarde (negativo = antes)
+	SunriseOffset time.Duration // Desplazamiento del borde de la mañana (negativo = antes)
+}
+
+// Crepúsculos que pueden limitar la noche con FollowSun
+const (
+	TwilightCivil        = models.TwilightCivil
+	TwilightNautical     = models.TwilightNautical
+	TwilightAstronomical = models.TwilightAstronomical
+)
+
+// DefaultSchedule devuelve la programación por defecto de la aplicación
+func DefaultSchedule() Schedule {
+	return scheduleFromConfig(models.NewAppConfig().Schedule)
+}
+
+/**
+ * TemperatureAt - Calcula la temperatura de la programación en un instante
+ *
+ * No aplica nada: sirve para barras o daemons que gestionan la gamma por
+ * su cuenta.
+ *
+ * @param {time.Time} t - Instante a evaluar (hora local)
+ * @returns {float64, error} Temperatura en Kelvin; error si la programación no es válida
+ * @example
+ *   temp, err := nightlight.DefaultSchedule().TemperatureAt(time.Now())
+ */
+func (s Schedule) TemperatureAt(t time.Time) (float64, error) {
+	config := models.NewAppConfig()
+	config.ScheduleEnabled = true
+	config.Schedule = s.toConfig(config.Schedule)
+	if err := config.Schedule.Validate(); err != nil {
+		return 0, err
+	}
+	return models.NewScheduler(config, nil).TemperatureAt(t), nil
+}
+
+// toConfig vuelca la programación sobre una ScheduleConfig conservando el resto de ajustes
+func (s Schedule) toConfig(schedule models.ScheduleConfig) models.ScheduleConfig {
+	schedule.StartTime = s.Start
+	schedule.EndTime = s.End
+	schedule.NightTemp = s.NightTemp
+	schedule.DayTemp = s.DayTemp
+	schedule.TransitionTime = int(s.Transition / time.Minute)
+	schedule.Mode = models.ScheduleModeFixed
+	if s.FollowSun {
+		schedule.Mode = models.ScheduleModeSolar
+	}
+	schedule.Location = models.Location{Latitude: s.Latitude, Longitude: s.Longitude}
+	schedule.Twilight = s.Twilight
+	schedule.SunsetOffset = int(s.SunsetOffset / time.Minute)
+	schedule.SunriseOffset = int(s.SunriseOffset / time.Minute)
+	return schedule
+}
+
+// scheduleFromConfig convierte la programación guardada al tipo público
+func scheduleFromConfig(schedule models.ScheduleConfig) Schedule {
+	return Schedule{
+		Start:      schedule.StartTime,
+		End:        schedule.EndTime,
+		NightTemp:  schedule.NightTemp,
+		DayTemp:    schedule.DayTemp,
+		Transition: time.Duration(schedule.TransitionTime) * time.Minute,
+		FollowSun:  schedule.Mode == models.ScheduleModeSolar,
+		Latitude:   schedule.Location.Latitude,
+		Longitude:  schedule.Location.Longitude,
+
+		Twilight:      schedule.GetTwilight(),
+		SunsetOffset:  time.Duration(schedule.SunsetOffset) * time.Minute,
+		SunriseOffset: time.Duration(schedule.SunriseOffset) * time.Minute,
+	}
+}
@@ -0,0 +1,52 @@
+#!/bin/sh
+# Pruebas de integración sobre displays virtuales.
+#
+# 1. Pruebas con FakeBackend (aplicar, resetear, programación) sin display.
+# 2. Backend real de X11 (xrandr) sobre Xvfb.
+# 3. Cadena de backends de Wayland sobre weston headless.
+#
+# Requiere: Xvfb, xrandr y weston. Los pasos cuyo servidor no esté
+# instalado se omiten con un aviso.
+set -eu
+
+cd "$(dirname "$0")/.."
+
+TEST="go test -count=1 -tags integration ./internal/integration/..."
+PIDS=""
+cleanup() {
+	for pid in $PIDS; do
+		kill "$pid" 2>/dev/null || true
+	done
+	[ -n "${WESTON_RUNTIME:-}" ] && rm -rf "$WESTON_RUNTIME"
+}
+trap cleanup EXIT INT TERM
+
+echo "🧪 Pruebas con FakeBackend"
+env -u DISPLAY -u WAYLAND_DISPLAY $TEST -run 'Apply|Schedule|Shutdown'
+
+if command -v Xvfb >/dev/null 2>&1; then
+	echo "🖥️  Pruebas sobre Xvfb"
+	Xvfb :99 -screen 0 1280x720x24 >/dev/null 2>&1 &
+	PIDS="$PIDS $!"
+	sleep 1
+	env -u WAYLAND_DISPLAY DISPLAY=:99 XDG_SESSION_TYPE=x11 LUZ_NOCTURNA_INTEGRATION_DISPLAY=1 \
+		$TEST -run Xvfb -v
+else
+	echo "⚠️  Xvfb no instalado, se omiten las pruebas de X11"
+fi
+
+if command -v weston >/dev/null 2>&1; then
+	echo "🖥️  Pruebas sobre weston headless"
+	WESTON_RUNTIME=$(mktemp -d)
+	chmod 700 "$WESTON_RUNTIME"
+	XDG_RUNTIME_DIR=$WESTON_RUNTIME weston --backend=headless-backend.so --socket=luz-nocturna-test >/dev/null 2>&1 &
+	PIDS="$PIDS $!"
+	sleep 2
+	# Ruta absoluta: las pruebas aíslan XDG_RUNTIME_DIR en un directorio temporal
+	env -u DISPLAY XDG_RUNTIME_DIR=$WESTON_RUNTIME WAYLAND_DISPLAY=$WESTON_RUNTIME/luz-nocturna-test XDG_SESSION_TYPE=wayland \
+		LUZ_NOCTURNA_INTEGRATION_DISPLAY=1 $TEST -run Weston -v
+else
+	echo "⚠️  weston no instalado, se omiten las pruebas de Wayland"
+fi
+
+echo "✅ Pruebas de integración completadas"